@@ -29,6 +29,10 @@ const (
 	runFlagData   = "data"
 	runFlagStream = "stream"
 
+	operationsFlagErrors = "errors"
+	operationsFlagMethod = "method"
+	operationsFlagCount  = "count"
+
 	loginFlagDisableBrowser = "disable-browser-open"
 	loginFlagKeyID          = "key-id"
 	loginFlagKey            = "key"
@@ -113,6 +117,8 @@ const (
 
 	cpFlagRecursive = "recursive"
 	cpFlagPreserve  = "preserve"
+
+	configValidateFlagProfile = "profile"
 )
 
 var commonFilterFlags = []cli.Flag{
@@ -513,6 +519,39 @@ var app = &cli.App{
 						},
 					},
 				},
+				{
+					Name:      "capture",
+					Usage:     "inspect and convert data capture files on local disk",
+					UsageText: createUsageText("data capture", nil, true),
+					Subcommands: []*cli.Command{
+						{
+							Name:      "inspect",
+							Usage:     "print the metadata, number of readings, and time range of a capture file",
+							UsageText: createUsageText("data capture inspect", nil, false, "<path to .capture or .prog file>"),
+							Action:    DataCaptureInspectAction,
+						},
+						{
+							Name:  "export",
+							Usage: "write the readings of a capture file as JSON lines, and any binary readings as files",
+							UsageText: createUsageText("data capture export", []string{dataFlagDestination}, false,
+								"<path to .capture or .prog file>"),
+							Flags: []cli.Flag{
+								&cli.PathFlag{
+									Name:     dataFlagDestination,
+									Required: true,
+									Usage:    "output directory for exported data",
+								},
+							},
+							Action: DataCaptureExportAction,
+						},
+						{
+							Name:      "recover",
+							Usage:     "write the complete readings of an in progress capture file, such as one left by a crash, to a .capture file",
+							UsageText: createUsageText("data capture recover", nil, false, "<path to .prog file>"),
+							Action:    DataCaptureRecoverAction,
+						},
+					},
+				},
 				{
 					Name:      "database",
 					Usage:     "interact with a MongoDB Atlas Data Federation instance",
@@ -1204,6 +1243,53 @@ var app = &cli.App{
 					},
 					Action: RobotsLogsAction,
 				},
+				{
+					Name:            "operations",
+					Usage:           "work with the operations of a machine part",
+					HideHelpCommand: true,
+					Subcommands: []*cli.Command{
+						{
+							Name:      "history",
+							Usage:     "display the history of finished operations of a machine part",
+							UsageText: createUsageText("machines operations history", []string{machineFlag, partFlag}, true),
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:        organizationFlag,
+									DefaultText: "first organization alphabetically",
+								},
+								&cli.StringFlag{
+									Name:        locationFlag,
+									DefaultText: "first location alphabetically",
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:     machineFlag,
+										Aliases:  []string{aliasRobotFlag},
+										Required: true,
+									},
+								},
+								&cli.StringFlag{
+									Name:     partFlag,
+									Required: true,
+								},
+								&cli.BoolFlag{
+									Name:  operationsFlagErrors,
+									Usage: "show only operations that failed or were canceled",
+								},
+								&cli.StringFlag{
+									Name:  operationsFlagMethod,
+									Usage: "show only operations whose method contains this text",
+								},
+								&cli.IntFlag{
+									Name:  operationsFlagCount,
+									Usage: "number of most recent operations to show, or all of them if 0",
+									Value: defaultNumOperations,
+								},
+							},
+							Action: MachinesOperationsHistoryAction,
+						},
+					},
+				},
 				{
 					Name:            "part",
 					Usage:           "work with a machine part",
@@ -1437,6 +1523,26 @@ Copy multiple files from the machine to a local destination with recursion and k
 				},
 			},
 		},
+		{
+			Name:            "config",
+			Usage:           "work with robot config files on local disk",
+			HideHelpCommand: true,
+			Subcommands: []*cli.Command{
+				{
+					Name: "validate",
+					Usage: "check a robot config file, its overlays, the attributes of its builtin resources, the dependencies " +
+						"between its resources, and its frame system, printing any problems found as JSON",
+					UsageText: createUsageText("config validate", nil, true, "<path to robot config file>"),
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  configValidateFlagProfile,
+							Usage: "config profile to apply over the config file",
+						},
+					},
+					Action: ConfigValidateAction,
+				},
+			},
+		},
 		{
 			Name:            "module",
 			Usage:           "manage your modules in Viam's registry",
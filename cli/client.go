@@ -42,6 +42,7 @@ import (
 	rconfig "go.viam.com/rdk/config"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/client"
 	"go.viam.com/rdk/services/shell"
@@ -55,6 +56,8 @@ const (
 	// maxNumLogs is an arbitrary limit used to stop CLI users from overwhelming
 	// our logs DB with heavy reads.
 	maxNumLogs = 10000
+	// defaultNumOperations is the number of finished operations shown by 'machines operations history'.
+	defaultNumOperations = 50
 )
 
 var errNoShellService = errors.New("shell service is not enabled on this machine part")
@@ -431,6 +434,112 @@ func RobotsPartRunAction(c *cli.Context) error {
 	)
 }
 
+// MachinesOperationsHistoryAction is the corresponding Action for 'machines operations history'.
+func MachinesOperationsHistoryAction(c *cli.Context) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+
+	// Create logger based on presence of debugFlag.
+	logger := logging.FromZapCompatible(zap.NewNop().Sugar())
+	if c.Bool(debugFlag) {
+		logger = logging.NewDebugLogger("cli")
+	}
+
+	records, err := client.robotPartOperationHistory(
+		c.String(organizationFlag),
+		c.String(locationFlag),
+		c.String(machineFlag),
+		c.String(partFlag),
+		c.Bool(debugFlag),
+		logger,
+	)
+	if err != nil {
+		return err
+	}
+	records = filterOperationRecords(records, c.Bool(operationsFlagErrors), c.String(operationsFlagMethod), c.Int(operationsFlagCount))
+	if len(records) == 0 {
+		infof(c.App.Writer, "No finished operations found")
+		return nil
+	}
+	for _, record := range records {
+		printf(c.App.Writer, "%s", operationRecordToString(record))
+	}
+	return nil
+}
+
+func (c *viamClient) robotPartOperationHistory(
+	orgStr, locStr, robotStr, partStr string,
+	debug bool,
+	logger logging.Logger,
+) ([]operation.Record, error) {
+	dialCtx, fqdn, rpcOpts, err := c.prepareDial(orgStr, locStr, robotStr, partStr, debug)
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		printf(c.c.App.Writer, "Establishing connection...")
+	}
+	robotClient, err := client.New(dialCtx, fqdn, logger, client.WithDialOptions(rpcOpts...))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to machine part")
+	}
+	defer func() {
+		utils.UncheckedError(robotClient.Close(c.c.Context))
+	}()
+
+	records, err := robotClient.OperationHistory(c.c.Context)
+	if status.Code(err) == codes.Unimplemented {
+		return nil, errors.New("machine part does not keep an operation history; it may need a newer version of viam-server")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get operation history from machine part")
+	}
+	return records, nil
+}
+
+// filterOperationRecords returns the last count records (or all of them if count is 0) that match the filters.
+func filterOperationRecords(records []operation.Record, errorsOnly bool, method string, count int) []operation.Record {
+	var filtered []operation.Record
+	for _, record := range records {
+		if errorsOnly && record.Outcome == operation.OutcomeSucceeded {
+			continue
+		}
+		if method != "" && !strings.Contains(record.Method, method) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	if count > 0 && len(filtered) > count {
+		filtered = filtered[len(filtered)-count:]
+	}
+	return filtered
+}
+
+// operationRecordToString formats a finished operation on a single line.
+func operationRecordToString(record operation.Record) string {
+	line := fmt.Sprintf("%s\t%s\t%s\t%s",
+		record.Started.UTC().Format(logging.DefaultTimeFormatStr),
+		record.Outcome,
+		record.Duration().Round(time.Millisecond),
+		record.Method,
+	)
+	if record.Caller != "" {
+		line += "\tcaller=" + record.Caller
+	}
+	if record.SessionID != uuid.Nil {
+		line += "\tsession=" + record.SessionID.String()
+	}
+	if record.Arguments != "" {
+		line += "\targuments=" + record.Arguments
+	}
+	if record.Error != "" {
+		line += "\terror=" + record.Error
+	}
+	return line
+}
+
 // RobotsPartShellAction is the corresponding Action for 'machines part shell'.
 func RobotsPartShellAction(c *cli.Context) error {
 	infof(c.App.Writer, "Ensure machine part has a valid shell type service")
@@ -28,6 +28,7 @@ import (
 
 	robotconfig "go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/shell"
@@ -227,6 +228,8 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	}
 
 	cCtx, ac, out, errOut := setup(&inject.AppServiceClient{}, dsc, nil, nil, nil, "token")
+	destination := t.TempDir()
+	test.That(t, cCtx.Set(dataFlagDestination, destination), test.ShouldBeNil)
 
 	test.That(t, ac.dataExportAction(cCtx), test.ShouldBeNil)
 	test.That(t, len(errOut.messages), test.ShouldEqual, 0)
@@ -241,7 +244,7 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	b := make([]byte, expectedDataSize)
 
 	// `data.ndjson` is the standardized name of the file data is written to in the `tabularData` call
-	filePath := utils.ResolveFile("data/data.ndjson")
+	filePath := filepath.Join(destination, "data", "data.ndjson")
 	file, err := os.Open(filePath)
 	test.That(t, err, test.ShouldBeNil)
 
@@ -257,7 +260,7 @@ func TestTabularDataByFilterAction(t *testing.T) {
 	b = make([]byte, expectedMetadataSize)
 
 	// metadata is named `0.json` based on its index in the metadata array
-	filePath = utils.ResolveFile("metadata/0.json")
+	filePath = filepath.Join(destination, "metadata", "0.json")
 	file, err = os.Open(filePath)
 	test.That(t, err, test.ShouldBeNil)
 
@@ -503,6 +506,33 @@ func TestGetRobotPartLogs(t *testing.T) {
 	})
 }
 
+func TestOperationHistory(t *testing.T) {
+	started := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	records := []operation.Record{
+		{Method: "/viam.component.arm.v1.ArmService/MoveToPosition", Started: started, Ended: started.Add(time.Second), Outcome: "succeeded"},
+		{
+			Method:    "/viam.component.base.v1.BaseService/MoveStraight",
+			Started:   started,
+			Ended:     started.Add(1500 * time.Millisecond),
+			Outcome:   "failed",
+			Caller:    "someone@example.com",
+			Arguments: `{"name":"base1"}`,
+			Error:     "base is stuck",
+		},
+		{Method: "/viam.component.base.v1.BaseService/Spin", Started: started, Ended: started, Outcome: "canceled"},
+	}
+
+	test.That(t, filterOperationRecords(records, false, "", 0), test.ShouldResemble, records)
+	test.That(t, filterOperationRecords(records, false, "", 2), test.ShouldResemble, records[1:])
+	test.That(t, filterOperationRecords(records, true, "", 0), test.ShouldResemble, records[1:])
+	test.That(t, filterOperationRecords(records, false, "BaseService", 1), test.ShouldResemble, records[2:])
+	test.That(t, filterOperationRecords(records, true, "ArmService", 0), test.ShouldBeEmpty)
+
+	test.That(t, operationRecordToString(records[1]), test.ShouldEqual,
+		"2024-05-06T07:08:09.000Z\tfailed\t1.5s\t/viam.component.base.v1.BaseService/MoveStraight"+
+			"\tcaller=someone@example.com\targuments={\"name\":\"base1\"}\terror=base is stuck")
+}
+
 func TestShellFileCopy(t *testing.T) {
 	listOrganizationsFunc := func(ctx context.Context, in *apppb.ListOrganizationsRequest,
 		opts ...grpc.CallOption,
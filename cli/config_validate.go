@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	configIssueError   = "error"
+	configIssueWarning = "warning"
+)
+
+// configIssue is a problem found in a robot config.
+type configIssue struct {
+	// Path is the JSONPath of the offending value in the resolved config.
+	Path     string `json:"path"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ConfigValidateAction is the corresponding action for 'config validate'.
+func ConfigValidateAction(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return errors.New("must provide exactly one config file to validate")
+	}
+	if profile := c.String(configValidateFlagProfile); profile != "" {
+		config.SetProfile(profile)
+	}
+	issues := validateRobotConfig(c.Context, c.Args().First())
+	out, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	printf(c.App.Writer, "%s", out)
+
+	var errCount int
+	for _, issue := range issues {
+		if issue.Severity == configIssueError {
+			errCount++
+		}
+	}
+	if errCount != 0 {
+		return errors.Errorf("%s is invalid: found %d error(s)", c.Args().First(), errCount)
+	}
+	return nil
+}
+
+// validationErrorRegexp matches errors made with resource.NewConfigValidationError, to find the path they refer to.
+var validationErrorRegexp = regexp.MustCompile(`(?s)Error validating\. Path: "([^"]*)" Error: (.*)`)
+
+// configValidator collects the problems found in a robot config.
+type configValidator struct {
+	issues []configIssue
+}
+
+func (v *configValidator) add(severity, path, format string, args ...interface{}) {
+	v.issues = append(v.issues, configIssue{Path: jsonPath(path), Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// addError records err, at the path it refers to if it was made by resource config validation, or at path
+// otherwise.
+func (v *configValidator) addError(path string, err error) {
+	path, msg := validationErrorPath(path, err)
+	v.add(configIssueError, path, "%s", msg)
+}
+
+// validationErrorPath returns the path err refers to if it was made by resource config validation, or path
+// otherwise, along with the message of the error.
+func validationErrorPath(path string, err error) (string, string) {
+	var fieldErr resource.FieldRequiredError
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Path + "." + fieldErr.Field, fmt.Sprintf("missing required field %q", fieldErr.Field)
+	}
+	if match := validationErrorRegexp.FindStringSubmatch(err.Error()); match != nil {
+		return match[1], match[2]
+	}
+	return path, err.Error()
+}
+
+func (v *configValidator) hasErrors() bool {
+	for _, issue := range v.issues {
+		if issue.Severity == configIssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPath converts a config path as used by config validation, such as "components.0.attributes", to a JSONPath.
+func jsonPath(path string) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	if path == "" {
+		return sb.String()
+	}
+	for _, part := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			sb.WriteString("[" + part + "]")
+			continue
+		}
+		sb.WriteString("." + part)
+	}
+	return sb.String()
+}
+
+// validateRobotConfig checks the robot config file at path, with its overlays, without fetching anything from the
+// cloud. It validates the config the way viam-server does when starting, the attributes of every resource with a
+// builtin model, the dependencies between resources, and the frame system.
+func validateRobotConfig(ctx context.Context, path string) []configIssue {
+	v := &configValidator{issues: []configIssue{}}
+
+	resolved, err := config.ResolveFile(path)
+	if err != nil {
+		v.add(configIssueError, "", "%s", err)
+		return v.issues
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(resolved, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			v.add(configIssueError, "", "invalid JSON at offset %d: %s", syntaxErr.Offset, syntaxErr)
+		} else {
+			v.add(configIssueError, "", "%s", err)
+		}
+		return v.issues
+	}
+	if _, ok := raw["cloud"]; ok {
+		// a config file with cloud credentials only bootstraps the config fetched from the cloud
+		v.add(configIssueWarning, "cloud", "the config of this machine is fetched from the cloud; only the local config file is validated")
+		delete(raw, "cloud")
+		if resolved, err = json.Marshal(raw); err != nil {
+			v.add(configIssueError, "", "%s", err)
+			return v.issues
+		}
+	}
+
+	var cfg config.Config
+	if err := json.Unmarshal(resolved, &cfg); err != nil {
+		v.add(configIssueError, "", "%s", err)
+		return v.issues
+	}
+	if err := cfg.ReplacePlaceholders(); err != nil {
+		v.add(configIssueWarning, "", "%s", err)
+	}
+
+	seen := map[string]string{}
+	for idx := range cfg.Modules {
+		path := fmt.Sprintf("modules.%d", idx)
+		if err := cfg.Modules[idx].Validate(path); err != nil {
+			v.addError(path, err)
+		}
+		v.validateUnique(seen, cfg.Modules[idx].Name, path+".name")
+	}
+	for idx := range cfg.Remotes {
+		path := fmt.Sprintf("remotes.%d", idx)
+		if _, err := cfg.Remotes[idx].Validate(path); err != nil {
+			v.addError(path, err)
+		}
+		remoteName := resource.NewName(resource.APINamespaceRDK.WithType("remote").WithSubtype(""), cfg.Remotes[idx].Name)
+		v.validateUnique(seen, remoteName.String(), path+".name")
+	}
+	deps := map[resource.Name][]resourceDependency{}
+	v.validateResources(&cfg, "components", cfg.Components, resource.APITypeComponentName, seen, deps)
+	for idx := range cfg.Processes {
+		path := fmt.Sprintf("processes.%d", idx)
+		if err := cfg.Processes[idx].Validate(path); err != nil {
+			v.addError(path, err)
+		}
+		v.validateUnique(seen, cfg.Processes[idx].ID, path+".id")
+	}
+	v.validateResources(&cfg, "services", cfg.Services, resource.APITypeServiceName, seen, deps)
+	v.validateDependencies(&cfg, deps)
+	v.validateFrames(&cfg)
+
+	// validation of the config as a whole is left to viam-server's own processing. It stops at the first resource
+	// whose attributes fail to convert, which are already reported above.
+	if !v.hasErrors() {
+		if _, err := config.FromReader(ctx, path, bytes.NewReader(resolved), logging.NewBlankLogger("config")); err != nil {
+			v.addError("", err)
+		}
+	}
+	return v.issues
+}
+
+// validateUnique checks that nothing else in the config, as recorded in seen, has the given name.
+func (v *configValidator) validateUnique(seen map[string]string, name, path string) {
+	if other, ok := seen[name]; ok {
+		v.add(configIssueError, path, "duplicate resource %s, also configured at %s", name, jsonPath(other))
+		return
+	}
+	seen[name] = path
+}
+
+// resourceDependency is a dependency of a resource on another, by the name the config refers to it by.
+type resourceDependency struct {
+	name string
+	path string
+}
+
+// validateResources validates the resource configs under key, and records the dependencies of each.
+func (v *configValidator) validateResources(
+	cfg *config.Config,
+	key string,
+	confs []resource.Config,
+	defaultAPIType string,
+	seen map[string]string,
+	deps map[resource.Name][]resourceDependency,
+) {
+	for idx := range confs {
+		conf := &confs[idx]
+		path := fmt.Sprintf("%s.%d", key, idx)
+		if _, err := conf.Validate(path, defaultAPIType); err != nil {
+			v.addError(path, err)
+			continue
+		}
+		name := conf.ResourceName()
+		v.validateUnique(seen, name.String(), path+".name")
+		for _, dep := range conf.DependsOn {
+			deps[name] = append(deps[name], resourceDependency{name: dep, path: path + ".depends_on"})
+		}
+
+		reg, ok := resource.LookupRegistration(name.API, conf.Model)
+		if !ok {
+			switch {
+			case conf.Model.Family == resource.DefaultModelFamily && hasBuiltinModels(name.API):
+				v.add(configIssueError, path+".model", "unknown builtin model %q for %s", conf.Model.Name, name.API)
+			case conf.Model.Family == resource.DefaultModelFamily:
+				v.add(configIssueWarning, path+".model",
+					"builtin models for %s are not included in this build of the CLI; its attributes are not validated", name.API)
+			case len(cfg.Modules) == 0:
+				v.add(configIssueWarning, path+".model",
+					"model %s is not builtin and no modules are configured to provide it", conf.Model)
+			}
+			continue
+		}
+		if reg.AttributeMapConverter == nil {
+			continue
+		}
+		attrsPath := path + ".attributes"
+		converted, err := reg.AttributeMapConverter(conf.Attributes)
+		if err != nil {
+			v.add(configIssueError, attrsPath, "%s", err)
+			continue
+		}
+		implicitDeps, err := converted.Validate(path)
+		if err != nil {
+			// models validate their attributes relative to the path of the resource
+			errPath, msg := validationErrorPath(path, err)
+			if rest, ok := strings.CutPrefix(errPath, path); ok && (rest == "" || strings.HasPrefix(rest, ".")) {
+				errPath = attrsPath + rest
+			}
+			v.add(configIssueError, errPath, "%s", msg)
+			continue
+		}
+		for _, dep := range implicitDeps {
+			deps[name] = append(deps[name], resourceDependency{name: dep, path: attrsPath})
+		}
+	}
+}
+
+// hasBuiltinModels returns whether any builtin model of api is registered.
+func hasBuiltinModels(api resource.API) bool {
+	for apiModel := range resource.RegisteredResources() {
+		if apiModel.API == api && apiModel.Model.Family == resource.DefaultModelFamily {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDependencies checks that the dependencies between resources can be resolved and do not form a cycle.
+func (v *configValidator) validateDependencies(cfg *config.Config, deps map[resource.Name][]resourceDependency) {
+	graph := resource.NewGraph()
+	byShortName := map[string][]resource.Name{}
+	for _, confs := range [][]resource.Config{cfg.Components, cfg.Services} {
+		for _, conf := range confs {
+			name := conf.ResourceName()
+			if err := graph.AddNode(name, resource.NewUninitializedNode()); err != nil {
+				// duplicate names are reported when validating the config as a whole
+				continue
+			}
+			byShortName[name.ShortName()] = append(byShortName[name.ShortName()], name)
+		}
+	}
+
+	for _, confs := range [][]resource.Config{cfg.Components, cfg.Services} {
+		for _, conf := range confs {
+			name := conf.ResourceName()
+			for _, dep := range deps[name] {
+				var targets []resource.Name
+				if depName, err := resource.NewFromString(dep.name); err == nil {
+					if _, ok := graph.Node(depName); ok {
+						targets = append(targets, depName)
+					}
+				} else {
+					targets = byShortName[dep.name]
+				}
+				if len(targets) == 0 {
+					// the dependency may be provided by a remote or be a default service
+					v.add(configIssueWarning, dep.path, "%s depends on %q, which is not configured", name.ShortName(), dep.name)
+					continue
+				}
+				for _, target := range targets {
+					if err := graph.AddChild(name, target); err != nil {
+						v.add(configIssueError, dep.path, "%s", err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// validateFrames checks that every frame in the frame system is valid and attached to a parent that exists, without
+// any frame being its own ancestor.
+func (v *configValidator) validateFrames(cfg *config.Config) {
+	var frames []string
+	parents := map[string]string{}
+	paths := map[string]string{}
+	for idx, conf := range cfg.Components {
+		if conf.Frame == nil {
+			continue
+		}
+		path := fmt.Sprintf("components.%d.frame", idx)
+		if conf.Name == referenceframe.World {
+			v.add(configIssueError, path, "a component with a frame cannot be named %q", referenceframe.World)
+			continue
+		}
+		if conf.Frame.Parent == "" {
+			v.add(configIssueError, path+".parent", "parent of the frame of %q is empty", conf.Name)
+			continue
+		}
+		frameCfg := *conf.Frame
+		if frameCfg.ID == "" {
+			frameCfg.ID = conf.Name
+		}
+		if _, err := frameCfg.ParseConfig(); err != nil {
+			v.add(configIssueError, path, "%s", err)
+			continue
+		}
+		frames = append(frames, frameCfg.ID)
+		parents[frameCfg.ID] = frameCfg.Parent
+		paths[frameCfg.ID] = path
+	}
+
+	for _, frame := range frames {
+		parent := parents[frame]
+		if parent == referenceframe.World {
+			continue
+		}
+		if _, ok := parents[parent]; !ok {
+			if len(cfg.Remotes) == 0 {
+				v.add(configIssueError, paths[frame]+".parent", "parent %q of frame %q is not a frame in the frame system", parent, frame)
+			} else {
+				v.add(configIssueWarning, paths[frame]+".parent",
+					"parent %q of frame %q is not a local frame and must be provided by a remote", parent, frame)
+			}
+			continue
+		}
+		// walk up from the frame; a chain longer than the number of frames loops
+		ancestor := parent
+		for steps := 0; steps < len(parents) && ancestor != referenceframe.World; steps++ {
+			if ancestor == frame {
+				v.add(configIssueError, paths[frame]+".parent", "frame %q is its own ancestor", frame)
+				break
+			}
+			next, ok := parents[ancestor]
+			if !ok {
+				break
+			}
+			ancestor = next
+		}
+	}
+}
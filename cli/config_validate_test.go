@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	// register the models validated below.
+	_ "go.viam.com/rdk/components/base/fake"
+	_ "go.viam.com/rdk/components/motor/gpio"
+	_ "go.viam.com/rdk/components/sensor/fake"
+)
+
+func validateTestConfig(t *testing.T, conf string) []configIssue {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "robot.json")
+	test.That(t, os.WriteFile(path, []byte(conf), 0o600), test.ShouldBeNil)
+	return validateRobotConfig(context.Background(), path)
+}
+
+func TestValidateRobotConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		issues := validateTestConfig(t, `{
+			"components": [
+				{"name": "base1", "api": "rdk:component:base", "model": "fake", "frame": {"parent": "world"}},
+				{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake", "depends_on": ["base1"],
+					"frame": {"parent": "base1", "translation": {"x": 1, "y": 0, "z": 0}}}
+			]
+		}`)
+		test.That(t, issues, test.ShouldBeEmpty)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		issues := validateTestConfig(t, `{"components": [}`)
+		test.That(t, issues, test.ShouldHaveLength, 1)
+		test.That(t, issues[0].Path, test.ShouldEqual, "$")
+		test.That(t, issues[0].Message, test.ShouldContainSubstring, "invalid JSON at offset 17")
+	})
+
+	t.Run("resources", func(t *testing.T) {
+		issues := validateTestConfig(t, `{
+			"components": [
+				{"name": "motor1", "api": "rdk:component:motor", "model": "gpio", "attributes": {"pins": {"a": "1"}}},
+				{"name": "sensor1", "api": "rdk:component:sensor", "model": "faek"},
+				{"name": "", "api": "rdk:component:sensor", "model": "fake"},
+				{"name": "thing1", "api": "rdk:component:sensor", "model": "acme:sensors:thing"}
+			]
+		}`)
+		test.That(t, issues, test.ShouldResemble, []configIssue{
+			{Path: "$.components[0].attributes.board", Severity: configIssueError, Message: `missing required field "board"`},
+			{Path: "$.components[1].model", Severity: configIssueError, Message: `unknown builtin model "faek" for rdk:component:sensor`},
+			{Path: "$.components[2].name", Severity: configIssueError, Message: `missing required field "name"`},
+			{
+				Path:     "$.components[3].model",
+				Severity: configIssueWarning,
+				Message:  "model acme:sensors:thing is not builtin and no modules are configured to provide it",
+			},
+		})
+	})
+
+	t.Run("dependency cycle", func(t *testing.T) {
+		issues := validateTestConfig(t, `{
+			"components": [
+				{"name": "base1", "api": "rdk:component:base", "model": "fake", "depends_on": ["sensor1"]},
+				{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake", "depends_on": ["base1", "remote-thing"]}
+			]
+		}`)
+		test.That(t, issues, test.ShouldHaveLength, 2)
+		test.That(t, issues[0].Path, test.ShouldEqual, "$.components[1].depends_on")
+		test.That(t, issues[0].Severity, test.ShouldEqual, configIssueError)
+		test.That(t, issues[0].Message, test.ShouldContainSubstring, "circular dependency")
+		test.That(t, issues[1].Path, test.ShouldEqual, "$.components[1].depends_on")
+		test.That(t, issues[1].Severity, test.ShouldEqual, configIssueWarning)
+		test.That(t, issues[1].Message, test.ShouldContainSubstring, `"remote-thing", which is not configured`)
+	})
+
+	t.Run("frames", func(t *testing.T) {
+		issues := validateTestConfig(t, `{
+			"components": [
+				{"name": "base1", "api": "rdk:component:base", "model": "fake", "frame": {"parent": "sensor1"}},
+				{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake", "frame": {"parent": "base1"}},
+				{"name": "sensor2", "api": "rdk:component:sensor", "model": "fake", "frame": {"parent": "nowhere"}},
+				{"name": "sensor3", "api": "rdk:component:sensor", "model": "fake", "frame": {"translation": {"x": 1}}}
+			]
+		}`)
+		test.That(t, issues, test.ShouldResemble, []configIssue{
+			{Path: "$.components[3].frame.parent", Severity: configIssueError, Message: `parent of the frame of "sensor3" is empty`},
+			{Path: "$.components[0].frame.parent", Severity: configIssueError, Message: `frame "base1" is its own ancestor`},
+			{Path: "$.components[1].frame.parent", Severity: configIssueError, Message: `frame "sensor1" is its own ancestor`},
+			{
+				Path:     "$.components[2].frame.parent",
+				Severity: configIssueError,
+				Message:  `parent "nowhere" of frame "sensor2" is not a frame in the frame system`,
+			},
+		})
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+		issues := validateTestConfig(t, `{
+			"components": [
+				{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake"},
+				{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake"}
+			]
+		}`)
+		test.That(t, issues, test.ShouldResemble, []configIssue{{
+			Path:     "$.components[1].name",
+			Severity: configIssueError,
+			Message:  "duplicate resource rdk:component:sensor/sensor1, also configured at $.components[0].name",
+		}})
+	})
+}
+
+func TestJSONPath(t *testing.T) {
+	test.That(t, jsonPath(""), test.ShouldEqual, "$")
+	test.That(t, jsonPath("components.0.attributes.board"), test.ShouldEqual, "$.components[0].attributes.board")
+	test.That(t, jsonPath("modules.12"), test.ShouldEqual, "$.modules[12]")
+}
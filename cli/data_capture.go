@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	datasyncpb "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/data"
+	rutils "go.viam.com/rdk/utils"
+)
+
+const (
+	captureReadingsFile = "readings.jsonl"
+	captureBinaryDir    = "binary"
+	defaultBinaryExt    = ".bin"
+)
+
+// captureFileSummary describes the contents of a capture file.
+type captureFileSummary struct {
+	metadata *datasyncpb.DataCaptureMetadata
+	count    int
+	first    time.Time
+	last     time.Time
+	// set if the file ends with a partially written reading
+	truncated bool
+}
+
+// DataCaptureInspectAction is the corresponding action for 'data capture inspect'.
+func DataCaptureInspectAction(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return errors.New("must provide exactly one capture file to inspect")
+	}
+	path := c.Args().First()
+	summary, err := summarizeCaptureFile(path)
+	if err != nil {
+		return err
+	}
+
+	md := summary.metadata
+	mdJSON, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(md)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal capture metadata")
+	}
+	printf(c.App.Writer, "File: %s", path)
+	printf(c.App.Writer, "Metadata:\n%s", string(mdJSON))
+	printf(c.App.Writer, "Readings: %d", summary.count)
+	if summary.count > 0 {
+		printf(c.App.Writer, "First reading: %s", summary.first.Format(time.RFC3339Nano))
+		printf(c.App.Writer, "Last reading: %s", summary.last.Format(time.RFC3339Nano))
+		printf(c.App.Writer, "Duration: %s", summary.last.Sub(summary.first))
+	}
+	if summary.truncated {
+		warningf(c.App.ErrWriter, "%s ends with a partially written reading; use 'viam data capture recover' to repair it", path)
+	}
+	return nil
+}
+
+// DataCaptureExportAction is the corresponding action for 'data capture export'.
+func DataCaptureExportAction(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return errors.New("must provide exactly one capture file to export")
+	}
+	path := c.Args().First()
+	dst := c.Path(dataFlagDestination)
+	count, err := exportCaptureFile(path, dst)
+	if err != nil {
+		return err
+	}
+	printf(c.App.Writer, "Exported %d readings from %s to %s", count, path, dst)
+	return nil
+}
+
+// DataCaptureRecoverAction is the corresponding action for 'data capture recover'.
+func DataCaptureRecoverAction(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return errors.New("must provide exactly one in progress capture file to recover")
+	}
+	path := c.Args().First()
+	recovered, count, err := recoverCaptureFile(path)
+	if err != nil {
+		return err
+	}
+	printf(c.App.Writer, "Recovered %d readings from %s into %s", count, path, recovered)
+	return nil
+}
+
+// readCaptureFile reads every complete reading from the capture file at path. Reading stops without error at the first partially
+// written reading, and truncated is set.
+func readCaptureFile(path string) (md *datasyncpb.DataCaptureMetadata, readings []*datasyncpb.SensorData, truncated bool, err error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	captureFile, err := data.ReadCaptureFile(f)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// Read the file reading by reading rather than with data.SensorDataFromCaptureFile so that we can tell whether it is truncated.
+	for {
+		reading, err := captureFile.ReadNext()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			// Anything other than a clean end of file means a reading was only partially written, or is corrupt.
+			return captureFile.ReadMetadata(), readings, true, nil
+		}
+		readings = append(readings, reading)
+	}
+	return captureFile.ReadMetadata(), readings, false, nil
+}
+
+func summarizeCaptureFile(path string) (*captureFileSummary, error) {
+	md, readings, truncated, err := readCaptureFile(path)
+	if err != nil {
+		return nil, err
+	}
+	summary := &captureFileSummary{metadata: md, count: len(readings), truncated: truncated}
+	for i, reading := range readings {
+		t := reading.GetMetadata().GetTimeRequested().AsTime()
+		if i == 0 || t.Before(summary.first) {
+			summary.first = t
+		}
+		if i == 0 || t.After(summary.last) {
+			summary.last = t
+		}
+	}
+	return summary, nil
+}
+
+// exportCaptureFile writes the metadata of a capture file as JSON, and each of its readings as a line of JSON to dst. Binary payloads
+// are written to their own files, which the JSON lines refer to.
+func exportCaptureFile(path, dst string) (int, error) {
+	md, readings, _, err := readCaptureFile(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Join(dst, captureBinaryDir), 0o700); err != nil {
+		return 0, errors.Wrap(err, "could not create destination directories")
+	}
+
+	mdJSON, err := protojson.Marshal(md)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not marshal capture metadata")
+	}
+	//nolint:gosec
+	if err := os.WriteFile(filepath.Join(dst, metadataDir+".json"), mdJSON, 0o600); err != nil {
+		return 0, errors.Wrap(err, "could not write metadata file")
+	}
+
+	//nolint:gosec
+	readingsFile, err := os.Create(filepath.Join(dst, captureReadingsFile))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not create readings file")
+	}
+	defer utils.UncheckedErrorFunc(readingsFile.Close)
+	w := bufio.NewWriter(readingsFile)
+
+	baseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	for i, reading := range readings {
+		line := map[string]interface{}{
+			"time_requested": reading.GetMetadata().GetTimeRequested().AsTime(),
+			"time_received":  reading.GetMetadata().GetTimeReceived().AsTime(),
+		}
+		switch d := reading.GetData().(type) {
+		case *datasyncpb.SensorData_Binary:
+			fileName := filepath.Join(captureBinaryDir, baseName+"_"+strconv.Itoa(i)+captureBinaryExt(md, d.Binary))
+			//nolint:gosec
+			if err := os.WriteFile(filepath.Join(dst, fileName), d.Binary, 0o600); err != nil {
+				return 0, errors.Wrapf(err, "could not write binary reading %d", i)
+			}
+			line["file"] = fileName
+		case *datasyncpb.SensorData_Struct:
+			line["data"] = d.Struct.AsMap()
+		}
+		lineJSON, err := json.Marshal(line)
+		if err != nil {
+			return 0, errors.Wrapf(err, "could not marshal reading %d", i)
+		}
+		if _, err := w.Write(append(lineJSON, '\n')); err != nil {
+			return 0, errors.Wrapf(err, "could not write to file %s", readingsFile.Name())
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, errors.Wrapf(err, "could not flush writer for %s", readingsFile.Name())
+	}
+	return len(readings), nil
+}
+
+// captureBinaryExt returns the file extension for a binary reading, preferring the one recorded in the capture metadata and otherwise
+// guessing from the payload.
+func captureBinaryExt(md *datasyncpb.DataCaptureMetadata, payload []byte) string {
+	if ext := md.GetFileExtension(); ext != "" {
+		return ext
+	}
+	if bytes.HasPrefix(payload, []byte("# .PCD")) || bytes.HasPrefix(payload, []byte("VERSION")) {
+		return ".pcd"
+	}
+	switch http.DetectContentType(payload) {
+	case rutils.MimeTypeJPEG:
+		return ".jpeg"
+	case rutils.MimeTypePNG:
+		return ".png"
+	default:
+		return defaultBinaryExt
+	}
+}
+
+// recoverCaptureFile writes every complete reading of an in progress capture file, such as one left behind by a crash, to a completed
+// capture file alongside it. The original file is left untouched.
+func recoverCaptureFile(path string) (string, int, error) {
+	if filepath.Ext(path) != data.InProgressCaptureFileExt {
+		return "", 0, errors.Errorf("%s is not an in progress capture file, expected a %s extension", path, data.InProgressCaptureFileExt)
+	}
+	md, readings, _, err := readCaptureFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+	// The recovered file is written uncompressed
+	md, err = data.WithCompression(md, data.CompressionNone)
+	if err != nil {
+		return "", 0, err
+	}
+
+	recoveredPath := strings.TrimSuffix(path, data.InProgressCaptureFileExt) + data.CompletedCaptureFileExt
+	//nolint:gosec
+	f, err := os.OpenFile(recoveredPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "could not create recovered capture file")
+	}
+	w := bufio.NewWriter(f)
+	if _, err := pbutil.WriteDelimited(w, md); err != nil {
+		utils.UncheckedError(f.Close())
+		return "", 0, err
+	}
+	for _, reading := range readings {
+		if _, err := pbutil.WriteDelimited(w, reading); err != nil {
+			utils.UncheckedError(f.Close())
+			return "", 0, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		utils.UncheckedError(f.Close())
+		return "", 0, err
+	}
+	if err := f.Close(); err != nil {
+		return "", 0, err
+	}
+	return recoveredPath, len(readings), nil
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	datasyncpb "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/data"
+)
+
+var captureTestStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// writeTestCaptureFile writes readings to a new capture file in dir, and returns the path to it. If inProgress is set the file is left
+// with the in progress extension.
+func writeTestCaptureFile(
+	t *testing.T, dir string, md *datasyncpb.DataCaptureMetadata, readings []*datasyncpb.SensorData, inProgress bool,
+) string {
+	t.Helper()
+	f, err := data.NewCaptureFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	for _, reading := range readings {
+		test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+	}
+	test.That(t, f.Flush(), test.ShouldBeNil)
+	if inProgress {
+		return f.GetPath()
+	}
+	test.That(t, f.Close(), test.ShouldBeNil)
+	return strings.TrimSuffix(f.GetPath(), data.InProgressCaptureFileExt) + data.CompletedCaptureFileExt
+}
+
+func testSensorMetadata(i int) *datasyncpb.SensorMetadata {
+	t := captureTestStart.Add(time.Duration(i) * time.Second)
+	return &datasyncpb.SensorMetadata{TimeRequested: timestamppb.New(t), TimeReceived: timestamppb.New(t)}
+}
+
+func tabularReadings(t *testing.T, n int) []*datasyncpb.SensorData {
+	t.Helper()
+	readings := []*datasyncpb.SensorData{}
+	for i := 0; i < n; i++ {
+		s, err := structpb.NewStruct(map[string]interface{}{"value": float64(i)})
+		test.That(t, err, test.ShouldBeNil)
+		readings = append(readings, &datasyncpb.SensorData{Metadata: testSensorMetadata(i), Data: &datasyncpb.SensorData_Struct{Struct: s}})
+	}
+	return readings
+}
+
+func TestDataCaptureInspect(t *testing.T) {
+	dir := t.TempDir()
+	md := &datasyncpb.DataCaptureMetadata{ComponentName: "sensor1", MethodName: "Readings", Type: datasyncpb.DataType_DATA_TYPE_TABULAR_SENSOR}
+	path := writeTestCaptureFile(t, dir, md, tabularReadings(t, 3), false)
+
+	summary, err := summarizeCaptureFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, summary.metadata.GetComponentName(), test.ShouldEqual, "sensor1")
+	test.That(t, summary.count, test.ShouldEqual, 3)
+	test.That(t, summary.first, test.ShouldEqual, captureTestStart)
+	test.That(t, summary.last, test.ShouldEqual, captureTestStart.Add(2*time.Second))
+	test.That(t, summary.truncated, test.ShouldBeFalse)
+
+	cCtx, _, out, errOut := setup(nil, nil, nil, nil, nil, "", path)
+	test.That(t, DataCaptureInspectAction(cCtx), test.ShouldBeNil)
+	test.That(t, strings.Join(out.messages, ""), test.ShouldContainSubstring, "Readings: 3")
+	test.That(t, errOut.messages, test.ShouldBeEmpty)
+
+	_, err = summarizeCaptureFile(filepath.Join(dir, "not_a_capture_file.txt"))
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestDataCaptureExport(t *testing.T) {
+	t.Run("tabular", func(t *testing.T) {
+		dir := t.TempDir()
+		md := &datasyncpb.DataCaptureMetadata{MethodName: "Readings", Type: datasyncpb.DataType_DATA_TYPE_TABULAR_SENSOR}
+		path := writeTestCaptureFile(t, dir, md, tabularReadings(t, 2), false)
+
+		dst := filepath.Join(dir, "export")
+		count, err := exportCaptureFile(path, dst)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, count, test.ShouldEqual, 2)
+
+		//nolint:gosec
+		f, err := os.Open(filepath.Join(dst, captureReadingsFile))
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		lines := 0
+		for scanner.Scan() {
+			var line map[string]interface{}
+			test.That(t, json.Unmarshal(scanner.Bytes(), &line), test.ShouldBeNil)
+			test.That(t, line["data"], test.ShouldResemble, map[string]interface{}{"value": float64(lines)})
+			lines++
+		}
+		test.That(t, lines, test.ShouldEqual, 2)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		dir := t.TempDir()
+		pcd := []byte("VERSION .7\nFIELDS x y z\n")
+		jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0, 0, 0}
+		readings := []*datasyncpb.SensorData{
+			{Metadata: testSensorMetadata(0), Data: &datasyncpb.SensorData_Binary{Binary: pcd}},
+			{Metadata: testSensorMetadata(1), Data: &datasyncpb.SensorData_Binary{Binary: jpeg}},
+		}
+		md := &datasyncpb.DataCaptureMetadata{MethodName: "ReadImage", Type: datasyncpb.DataType_DATA_TYPE_BINARY_SENSOR}
+		path := writeTestCaptureFile(t, dir, md, readings, false)
+
+		dst := filepath.Join(dir, "export")
+		count, err := exportCaptureFile(path, dst)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, count, test.ShouldEqual, 2)
+
+		base := strings.TrimSuffix(filepath.Base(path), data.CompletedCaptureFileExt)
+		//nolint:gosec
+		contents, err := os.ReadFile(filepath.Join(dst, captureBinaryDir, base+"_0.pcd"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, contents, test.ShouldResemble, pcd)
+		//nolint:gosec
+		contents, err = os.ReadFile(filepath.Join(dst, captureBinaryDir, base+"_1.jpeg"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, contents, test.ShouldResemble, jpeg)
+	})
+}
+
+func TestDataCaptureRecover(t *testing.T) {
+	dir := t.TempDir()
+	md := &datasyncpb.DataCaptureMetadata{MethodName: "Readings", Type: datasyncpb.DataType_DATA_TYPE_TABULAR_SENSOR}
+	path := writeTestCaptureFile(t, dir, md, tabularReadings(t, 3), true)
+
+	// Simulate a crash part way through writing the last reading
+	info, err := os.Stat(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.Truncate(path, info.Size()-3), test.ShouldBeNil)
+
+	summary, err := summarizeCaptureFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, summary.count, test.ShouldEqual, 2)
+	test.That(t, summary.truncated, test.ShouldBeTrue)
+
+	recovered, count, err := recoverCaptureFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, count, test.ShouldEqual, 2)
+	test.That(t, filepath.Ext(recovered), test.ShouldEqual, data.CompletedCaptureFileExt)
+
+	readings, err := data.SensorDataFromCaptureFilePath(recovered)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(readings), test.ShouldEqual, 2)
+	summary, err = summarizeCaptureFile(recovered)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, summary.truncated, test.ShouldBeFalse)
+
+	// Recovering again would overwrite the recovered file
+	_, _, err = recoverCaptureFile(path)
+	test.That(t, err, test.ShouldNotBeNil)
+	// Only in progress files can be recovered
+	_, _, err = recoverCaptureFile(recovered)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestDataCaptureRecoverCompressed(t *testing.T) {
+	dir := t.TempDir()
+	md, err := data.WithCompression(
+		&datasyncpb.DataCaptureMetadata{MethodName: "Readings", Type: datasyncpb.DataType_DATA_TYPE_TABULAR_SENSOR}, data.CompressionZstd)
+	test.That(t, err, test.ShouldBeNil)
+	path := writeTestCaptureFile(t, dir, md, tabularReadings(t, 3), true)
+
+	summary, err := summarizeCaptureFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, summary.count, test.ShouldEqual, 3)
+	test.That(t, summary.truncated, test.ShouldBeFalse)
+
+	// The recovered file is uncompressed
+	recovered, count, err := recoverCaptureFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, count, test.ShouldEqual, 3)
+	summary, err = summarizeCaptureFile(recovered)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, summary.count, test.ShouldEqual, 3)
+	codec, err := data.CompressionFromMetadata(summary.metadata)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, codec, test.ShouldEqual, data.CompressionNone)
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 
 	"go.viam.com/rdk/cli"
+	// register the builtin models for 'viam config validate'.
+	_ "go.viam.com/rdk/components/register"
+	_ "go.viam.com/rdk/services/register"
 )
 
 func main() {
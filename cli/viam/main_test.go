@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/cli"
+)
+
+// TestConfigValidateBuiltinModels checks that the CLI binary itself registers the builtin models, so that
+// 'viam config validate' checks their attributes.
+func TestConfigValidateBuiltinModels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "robot.json")
+	test.That(t, os.WriteFile(path, []byte(`{
+		"components": [
+			{"name": "motor1", "api": "rdk:component:motor", "model": "gpio", "attributes": {"pins": {"a": "1"}}},
+			{"name": "sensor1", "api": "rdk:component:sensor", "model": "faek"}
+		],
+		"services": [
+			{"name": "shell1", "api": "rdk:service:shell", "model": "builtin"}
+		]
+	}`), 0o600), test.ShouldBeNil)
+
+	var out, errOut bytes.Buffer
+	err := cli.NewApp(&out, &errOut).Run([]string{"viam", "config", "validate", path})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "found 2 error(s)")
+	test.That(t, out.String(), test.ShouldContainSubstring, `"path": "$.components[0].attributes.board"`)
+	test.That(t, out.String(), test.ShouldContainSubstring, `unknown builtin model \"faek\" for rdk:component:sensor`)
+	test.That(t, out.String(), test.ShouldNotContainSubstring, "not included in this build of the CLI")
+}
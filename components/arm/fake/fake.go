@@ -6,7 +6,9 @@ import (
 	_ "embed"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/arm/v1"
 
@@ -16,9 +18,11 @@ import (
 	"go.viam.com/rdk/components/arm/xarm"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -30,6 +34,9 @@ var Model = resource.DefaultModelFamily.WithModel("fake")
 
 var dofbotModel = "yahboom-dofbot"
 
+// simPollInterval is how often an arm with limited joint speeds checks whether a move is done.
+const simPollInterval = 10 * time.Millisecond
+
 //go:embed fake_model.json
 var fakejson []byte
 
@@ -40,6 +47,9 @@ var dofbotjson []byte
 type Config struct {
 	ArmModel      string `json:"arm-model,omitempty"`
 	ModelFilePath string `json:"model-path,omitempty"`
+	// MaxJointSpeeds, when set, limits how fast the joints move, in degrees per second or mm per
+	// second for prismatic joints, so that moves take time. A single speed applies to every joint.
+	MaxJointSpeeds []float64 `json:"max_joint_speeds,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -53,7 +63,15 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	case conf.ArmModel == "" && conf.ModelFilePath != "":
 		_, err = modelFromPath(conf.ModelFilePath, "")
 	}
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+	for _, speed := range conf.MaxJointSpeeds {
+		if speed <= 0 {
+			return nil, resource.NewConfigValidationError(path, errors.New("max_joint_speeds must be positive"))
+		}
+	}
+	return nil, nil
 }
 
 func init() {
@@ -67,6 +85,7 @@ func NewArm(ctx context.Context, deps resource.Dependencies, conf resource.Confi
 	a := &Arm{
 		Named:  conf.ResourceName().AsNamed(),
 		logger: logger,
+		opMgr:  operation.NewSingleOperationManager(),
 	}
 	if err := a.Reconfigure(ctx, deps, conf); err != nil {
 		return nil, err
@@ -106,6 +125,11 @@ type Arm struct {
 	mu     sync.RWMutex
 	joints []referenceframe.Input
 	model  referenceframe.Model
+	// motion moves the joints at limited speeds, in their protobuf units, when configured. The
+	// joints are then wherever it has them.
+	motion *sim.Joints
+	clock  clock.Clock
+	opMgr  *operation.SingleOperationManager
 }
 
 // Reconfigure atomically reconfigures this arm in place based on the new config.
@@ -127,10 +151,31 @@ func (a *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf
 			"the arm-model and model-path from attributes")
 	}
 
+	var speeds []float64
+	switch len(newConf.MaxJointSpeeds) {
+	case 0:
+	case 1:
+		for i := 0; i < dof; i++ {
+			speeds = append(speeds, newConf.MaxJointSpeeds[0])
+		}
+	case dof:
+		speeds = newConf.MaxJointSpeeds
+	default:
+		return errors.Errorf("fake arm has %d joints but %d max_joint_speeds", dof, len(newConf.MaxJointSpeeds))
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.joints = referenceframe.FloatsToInputs(make([]float64, dof))
 	a.model = model
+	a.motion = nil
+	if speeds != nil {
+		a.clock = sim.Clock()
+		a.motion = sim.NewJoints(model.ProtobufFromInput(a.joints).Values, speeds, a.clock)
+		if a.opMgr == nil {
+			a.opMgr = operation.NewSingleOperationManager()
+		}
+	}
 
 	return nil
 }
@@ -155,21 +200,31 @@ func (a *Arm) EndPosition(ctx context.Context, extra map[string]interface{}) (sp
 
 // MoveToPosition sets the position.
 func (a *Arm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+	joints, err := a.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
 	a.mu.RLock()
-	defer a.mu.RUnlock()
-
 	model := a.model
-	_, err := model.Transform(a.joints)
+	limited := a.motion != nil
+	a.mu.RUnlock()
+
+	_, err = model.Transform(joints)
 	if err != nil && strings.Contains(err.Error(), referenceframe.OOBErrString) {
 		return errors.New("cannot move arm: " + err.Error())
 	} else if err != nil {
 		return err
 	}
 
-	plan, err := motionplan.PlanFrameMotion(ctx, a.logger, pose, model, a.joints, nil, nil)
+	plan, err := motionplan.PlanFrameMotion(ctx, a.logger, pose, model, joints, nil, nil)
 	if err != nil {
 		return err
 	}
+	if limited {
+		return a.GoToInputs(ctx, plan...)
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	copy(a.joints, plan[len(plan)-1])
 	return nil
 }
@@ -181,12 +236,38 @@ func (a *Arm) MoveToJointPositions(ctx context.Context, joints *pb.JointPosition
 		return err
 	}
 	a.mu.RLock()
-	defer a.mu.RUnlock()
 	_, err := a.model.Transform(inputs)
+	motion, clk := a.motion, a.clock
+	if err == nil && motion == nil {
+		copy(a.joints, inputs)
+	}
+	a.mu.RUnlock()
 	if err != nil {
 		return err
 	}
-	copy(a.joints, inputs)
+	if motion != nil {
+		return a.moveJoints(ctx, motion, clk, joints.Values)
+	}
+	return nil
+}
+
+// moveJoints moves the joints at their limited speeds and waits for them to arrive. If the move is
+// cancelled, the joints stop where they are.
+func (a *Arm) moveJoints(ctx context.Context, motion *sim.Joints, clk clock.Clock, goal []float64) error {
+	ctx, done := a.opMgr.New(ctx)
+	defer done()
+
+	ticker := clk.Ticker(simPollInterval)
+	defer ticker.Stop()
+	motion.MoveTo(goal)
+	for motion.Moving() {
+		select {
+		case <-ctx.Done():
+			motion.Stop()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
 	return nil
 }
 
@@ -194,23 +275,40 @@ func (a *Arm) MoveToJointPositions(ctx context.Context, joints *pb.JointPosition
 func (a *Arm) JointPositions(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
+	if a.motion != nil {
+		return &pb.JointPositions{Values: a.motion.Positions()}, nil
+	}
 	return a.model.ProtobufFromInput(a.joints), nil
 }
 
-// Stop doesn't do anything for a fake arm.
+// Stop halts the joints of a fake arm with limited joint speeds, and otherwise does nothing.
 func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
+	a.mu.RLock()
+	motion := a.motion
+	a.mu.RUnlock()
+	if motion == nil {
+		return nil
+	}
+	a.opMgr.CancelRunning(ctx)
+	motion.Stop()
 	return nil
 }
 
-// IsMoving is always false for a fake arm.
+// IsMoving returns whether the joints of a fake arm with limited joint speeds are moving, and
+// otherwise false.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.motion != nil && a.motion.Moving(), nil
 }
 
 // CurrentInputs returns the current inputs of the fake arm.
 func (a *Arm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
+	if a.motion != nil {
+		return a.model.InputFromProtobuf(&pb.JointPositions{Values: a.motion.Positions()}), nil
+	}
 	return a.joints, nil
 }
 
@@ -3,13 +3,17 @@ package fake
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	pb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
+	"go.viam.com/rdk/utils"
 )
 
 func TestReconfigure(t *testing.T) {
@@ -131,3 +135,70 @@ func TestJointPositions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, sampleInputs, test.ShouldResemble, inputs)
 }
+
+func TestJointSpeedLimits(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	clk := clock.NewMock()
+	defer sim.SetClock(clk)()
+
+	_, err := (&Config{ArmModel: "ur5e", MaxJointSpeeds: []float64{0}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewArm(ctx, nil, resource.Config{
+		Name:                "testArm",
+		ConvertedAttributes: &Config{ArmModel: "ur5e", MaxJointSpeeds: []float64{10, 20}},
+	}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	a, err := NewArm(ctx, nil, resource.Config{
+		Name:                "testArm",
+		ConvertedAttributes: &Config{ArmModel: "ur5e", MaxJointSpeeds: []float64{30}},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the move takes as long as the joint with furthest to go needs
+	goal := &pb.JointPositions{Values: []float64{0, 30, 60, 90, 60, 30}}
+	errCh := make(chan error)
+	go func() {
+		errCh <- a.MoveToJointPositions(ctx, goal, nil)
+	}()
+	testMoving := func(expected bool) {
+		t.Helper()
+		moving, err := a.IsMoving(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, moving, test.ShouldEqual, expected)
+	}
+	// wait for the move to start
+	for moving, _ := a.IsMoving(ctx); !moving; moving, _ = a.IsMoving(ctx) {
+		time.Sleep(time.Millisecond)
+	}
+	clk.Add(time.Second)
+	positions, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	for i, value := range positions.Values {
+		test.That(t, value, test.ShouldAlmostEqual, goal.Values[i]/3)
+	}
+	testMoving(true)
+	clk.Add(2 * time.Second)
+	test.That(t, <-errCh, test.ShouldBeNil)
+	testMoving(false)
+	positions, err = a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions.Values, test.ShouldResemble, goal.Values)
+
+	// stopping interrupts the move where the joints are
+	go func() {
+		errCh <- a.MoveToJointPositions(ctx, &pb.JointPositions{Values: make([]float64, 6)}, nil)
+	}()
+	for moving, _ := a.IsMoving(ctx); !moving; moving, _ = a.IsMoving(ctx) {
+		time.Sleep(time.Millisecond)
+	}
+	clk.Add(time.Second)
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-errCh, test.ShouldNotBeNil)
+	testMoving(false)
+	clk.Add(time.Second)
+	inputs, err := a.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs[3].Value, test.ShouldAlmostEqual, utils.DegToRad(60))
+}
@@ -24,6 +24,7 @@ import (
 
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
@@ -38,6 +39,11 @@ var Model = resource.DefaultModelFamily.WithModel("ur5e")
 var (
 	errorPollDuration = 10 * time.Millisecond
 	defaultTimeout    = 10 * time.Second
+
+	// parameters used when streaming a trajectory to the arm with servoj.
+	servoSamplePeriod = 8 * time.Millisecond
+	servoLookahead    = 100 * time.Millisecond
+	servoGain         = 300
 )
 
 // Config is used for converting config attributes.
@@ -450,7 +456,74 @@ func (ua *urArm) moveToJointPositionRadians(ctx context.Context, radians []float
 	if _, err := ua.connControl.Write([]byte(cmd)); err != nil {
 		return err
 	}
+	return ua.waitForJointPositionRadians(ctx, radians, timeout)
+}
+
+// moveThroughJointPositionsRadians streams a time parameterized trajectory through each of the given waypoints to the arm as a single
+// URScript program of servoj commands, so that all joints move in sync and the arm does not stop at intermediate waypoints.
+func (ua *urArm) moveThroughJointPositionsRadians(ctx context.Context, waypoints [][]referenceframe.Input) error {
+	if !ua.inRemoteMode {
+		return errors.New("UR5 is in local mode; use the polyscope to switch it to remote control mode")
+	}
+	ctx, done := ua.opMgr.New(ctx)
+	defer done()
+
+	ua.muMove.Lock()
+	defer ua.muMove.Unlock()
+
+	state, err := ua.getState()
+	if err != nil {
+		return err
+	}
+	start := make([]float64, 0, len(state.Joints))
+	for _, joint := range state.Joints {
+		start = append(start, joint.Qactual)
+	}
+
+	// The configured speed caps any limits from the kinematic model
+	limits := referenceframe.KinematicLimits(ua.model)
+	for i := range limits {
+		if limits[i].Velocity <= 0 || limits[i].Velocity > ua.speedRadPerSec {
+			limits[i].Velocity = ua.speedRadPerSec
+		}
+		if limits[i].Acceleration <= 0 || limits[i].Acceleration > 0.8*ua.speedRadPerSec {
+			limits[i].Acceleration = 0.8 * ua.speedRadPerSec
+		}
+	}
+	opts := motionplan.NewTimingOptions()
+	opts.SamplePeriod = servoSamplePeriod.Seconds()
+	timed, err := motionplan.TimeParameterizeInputs(
+		append([][]referenceframe.Input{referenceframe.FloatsToInputs(start)}, waypoints...),
+		limits,
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+
+	var program strings.Builder
+	program.WriteString("def viam_servo():\n")
+	for _, waypoint := range timed[1:] {
+		q := referenceframe.InputsToFloats(waypoint.Inputs)
+		program.WriteString(fmt.Sprintf("  servoj([%f,%f,%f,%f,%f,%f], t=%1.3f, lookahead_time=%1.2f, gain=%d)\n",
+			q[0], q[1], q[2], q[3], q[4], q[5], servoSamplePeriod.Seconds(), servoLookahead.Seconds(), servoGain))
+	}
+	program.WriteString("  stopj(2.0)\nend\n")
 
+	if _, err := ua.connControl.Write([]byte(program.String())); err != nil {
+		return err
+	}
+
+	duration := timed[len(timed)-1].Time
+	timeout := defaultTimeout
+	if estTime := time.Duration(1.2 * float64(duration)); estTime > timeout {
+		timeout = estTime
+	}
+	return ua.waitForJointPositionRadians(ctx, referenceframe.InputsToFloats(waypoints[len(waypoints)-1]), timeout)
+}
+
+// waitForJointPositionRadians blocks until the arm reaches the given joint positions, the arm reports an error, or timeout elapses.
+func (ua *urArm) waitForJointPositionRadians(ctx context.Context, radians []float64, timeout time.Duration) error {
 	now := time.Now()
 	for {
 		state, err := ua.getState()
@@ -501,13 +574,19 @@ func (ua *urArm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, err
 	return ua.model.InputFromProtobuf(res), nil
 }
 
-// GoToInputs moves the UR arm to the Inputs specified.
+// GoToInputs moves the UR arm to the Inputs specified. When given multiple sets of Inputs, the arm moves through them in a single
+// continuous motion.
 func (ua *urArm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
 	for _, goal := range inputSteps {
 		// check that joint positions are not out of bounds
 		if err := arm.CheckDesiredJointPositions(ctx, ua, goal); err != nil {
 			return err
 		}
+	}
+	if len(inputSteps) > 1 {
+		return ua.moveThroughJointPositionsRadians(ctx, inputSteps)
+	}
+	for _, goal := range inputSteps {
 		err := ua.MoveToJointPositions(ctx, ua.model.ProtobufFromInput(goal), nil)
 		if err != nil {
 			return err
@@ -41,8 +41,6 @@ const (
 	defaultAccel  = 100. // degrees per second per second
 	defaultPort   = "502"
 	defaultMoveHz = 100. // Don't change this
-
-	interwaypointAccel = 600. // degrees per second per second. All xarms max out at 1145
 )
 
 type xArm struct {
@@ -11,6 +11,7 @@ import (
 	pb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/utils"
 
+	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/spatialmath"
@@ -359,134 +360,38 @@ func (x *xArm) MoveToJointPositions(ctx context.Context, newPositions *pb.JointP
 	return x.GoToInputs(ctx, x.model.InputFromProtobuf(newPositions))
 }
 
-// Using the configured moveHz, joint speed, and joint acceleration, create the series of joint positions for the arm to follow,
-// using a trapezoidal velocity profile to blend between waypoints to the extent possible.
+// Using the configured moveHz, joint speed, and joint acceleration, create the series of joint positions for the arm to follow.
+// The waypoints are blended together and time parameterized so that all joints move in sync and arrive at the same time.
 func (x *xArm) createRawJointSteps(startInputs []referenceframe.Input, inputSteps [][]referenceframe.Input) ([][]float64, error) {
 	x.mu.RLock()
 	speed := x.speed
 	acceleration := x.acceleration
 	moveHZ := x.moveHZ
 	x.mu.RUnlock()
-	// Generate list of joint positions to pass through
-	// This is almost-calculus but not quite because it's explicitly discretized
-	accelStep := acceleration / moveHZ
-	interwaypointAccelStep := interwaypointAccel / moveHZ
-
-	from := referenceframe.InputsToFloats(startInputs)
-
-	// We want smooth acceleration/motion but there's no guarantee the provided inputs have continuous velocity signs
-	floatMaxDiff := func(from, to []float64) float64 {
-		max := 0.
-		for i, toInput := range to {
-			diff := math.Abs(toInput - from[i])
-			if diff > max {
-				max = diff
-			}
+
+	// The configured speed and acceleration cap any limits from the kinematic model
+	limits := referenceframe.KinematicLimits(x.model)
+	for i := range limits {
+		if limits[i].Velocity <= 0 || limits[i].Velocity > speed {
+			limits[i].Velocity = speed
 		}
-		return max
-	}
-
-	// Preprocess steps into step counts
-	stepTotal := 0.
-	displacementTotal := 0.
-
-	for _, toInputs := range inputSteps {
-		to := referenceframe.InputsToFloats(toInputs)
-		max := floatMaxDiff(from, to)
-		displacementTotal += max
-		nSteps := (math.Abs(max) / speed) * moveHZ
-		stepTotal += nSteps
-		from = to
-	}
-
-	nominalAccelSteps := int((speed / acceleration) * moveHZ) // This many steps to accelerate, and the same to decelerate
-	if float64(nominalAccelSteps) > stepTotal*0.95 {
-		nominalAccelSteps = int(0.95 * math.Sqrt(displacementTotal/acceleration) * moveHZ)
-	}
-	maxVel := (float64(nominalAccelSteps) / moveHZ) * acceleration
-
-	inputStepsReversed := [][]referenceframe.Input{}
-	for i := len(inputSteps) - 1; i >= 0; i-- {
-		inputStepsReversed = append(inputStepsReversed, inputSteps[i])
-	}
-	inputStepsReversed = append(inputStepsReversed, startInputs)
-
-	accelCurve := func(
-		startInputs []referenceframe.Input,
-		allInputSteps [][]referenceframe.Input,
-		stopVel float64,
-	) (int, [][]float64, error) {
-		currSpeed := accelStep
-		steps := [][]float64{}
-		from = referenceframe.InputsToFloats(startInputs)
-		lastInputs := startInputs
-		for i, toInputs := range allInputSteps {
-			to := referenceframe.InputsToFloats(toInputs)
-			runningFrom := from
-
-			for currDiff := floatMaxDiff(runningFrom, to); currDiff > 1e-6; currDiff = floatMaxDiff(runningFrom, to) {
-				if currSpeed <= 0 {
-					break
-				}
-				nSteps := (currDiff / currSpeed) * moveHZ
-				stepSize := 1.
-				if nSteps <= 1 {
-					if currDiff == 0 {
-						break
-					}
-					stepSize = nSteps
-				}
-				nextInputs, err := x.model.Interpolate(lastInputs, toInputs, stepSize/nSteps)
-				if err != nil {
-					return 0, nil, err
-				}
-				runningFrom = referenceframe.InputsToFloats(nextInputs)
-				steps = append(steps, referenceframe.InputsToFloats(nextInputs))
-
-				if currSpeed < speed {
-					currSpeed += accelStep * stepSize
-					if currSpeed > speed {
-						currSpeed = speed
-					}
-				} else {
-					// If we reach max speed, accelerate at max for the remainder of the move
-					accelStep = interwaypointAccelStep
-				}
-
-				if currSpeed >= stopVel-1e-6 {
-					return i, steps, nil
-				}
-
-				lastInputs = nextInputs
-			}
-			lastInputs = toInputs
-			from = to
+		if limits[i].Acceleration <= 0 || limits[i].Acceleration > acceleration {
+			limits[i].Acceleration = acceleration
 		}
-		return len(allInputSteps), steps, nil
 	}
+	opts := motionplan.NewTimingOptions()
+	opts.SamplePeriod = 1. / moveHZ
 
-	decelStart, decelSteps, err := accelCurve(inputStepsReversed[0], inputStepsReversed, maxVel)
+	timed, err := motionplan.TimeParameterizeInputs(append([][]referenceframe.Input{startInputs}, inputSteps...), limits, opts)
 	if err != nil {
 		return nil, err
 	}
-	accelStop := len(inputSteps) - decelStart
-	accelInputSteps := [][]referenceframe.Input{}
-	for i, inputStep := range inputSteps {
-		if i == accelStop {
-			accelInputSteps = append(accelInputSteps, referenceframe.FloatsToInputs(decelSteps[len(decelSteps)-1]))
-			break
-		}
-		accelInputSteps = append(accelInputSteps, inputStep)
+	// The first sample is the current position, so it does not need to be sent
+	steps := make([][]float64, 0, len(timed)-1)
+	for _, waypoint := range timed[1:] {
+		steps = append(steps, referenceframe.InputsToFloats(waypoint.Inputs))
 	}
-	_, accelSteps, err := accelCurve(startInputs, accelInputSteps, math.Inf(1))
-	if err != nil {
-		return nil, err
-	}
-	for i := len(decelSteps) - 2; i >= 0; i-- {
-		accelSteps = append(accelSteps, decelSteps[i])
-	}
-
-	return accelSteps, nil
+	return steps, nil
 }
 
 func (x *xArm) executeInputs(ctx context.Context, rawSteps [][]float64) error {
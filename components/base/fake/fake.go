@@ -3,12 +3,19 @@ package fake
 
 import (
 	"context"
+	"math"
+	"reflect"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -16,7 +23,7 @@ func init() {
 	resource.RegisterComponent(
 		base.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[base.Base, resource.NoNativeConfig]{Constructor: NewBase},
+		resource.Registration[base.Base, *Config]{Constructor: NewBase},
 	)
 }
 
@@ -24,18 +31,86 @@ const (
 	defaultWidthMm               = 600
 	defaultMinimumTurningRadiusM = 0
 	defaultWheelCircumferenceM   = 3
+
+	defaultSimWheelCircumferenceMm = 300
+	defaultSimMaxRPM               = 120
+	// simPollInterval is how often a simulated base checks whether it has finished a motion.
+	simPollInterval = 10 * time.Millisecond
 )
 
-// Base is a fake base that returns what it was provided in each method.
+// Config describes the configuration of a fake base.
+type Config struct {
+	// Physics, when set, simulates a differential drive base whose wheels have inertia, torque and
+	// friction and slip on the ground, rather than one that does nothing.
+	Physics *PhysicsConfig `json:"physics,omitempty"`
+}
+
+// PhysicsConfig describes a simulated differential drive base.
+type PhysicsConfig struct {
+	WidthMm              int             `json:"width_mm,omitempty"`
+	WheelCircumferenceMm int             `json:"wheel_circumference_mm,omitempty"`
+	MaxRPM               float64         `json:"max_rpm,omitempty"`
+	Motor                sim.MotorConfig `json:"motor"`
+	// WheelSlipStdDevM is the standard deviation of each wheel's slip over a meter of travel.
+	WheelSlipStdDevM float64 `json:"wheel_slip_std_dev_m,omitempty"`
+	// Seed seeds the slip, so that a simulation can be repeated.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Physics == nil {
+		return nil, nil
+	}
+	physics := conf.Physics
+	if physics.WidthMm < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("physics.width_mm cannot be negative"))
+	}
+	if physics.WheelCircumferenceMm < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("physics.wheel_circumference_mm cannot be negative"))
+	}
+	if physics.MaxRPM < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("physics.max_rpm cannot be negative"))
+	}
+	if physics.WheelSlipStdDevM < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("physics.wheel_slip_std_dev_m cannot be negative"))
+	}
+	if err := physics.Motor.Validate(path + ".physics.motor"); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// physicsConfig returns the physics configured for the base, if any. Bases built directly may
+// have no attributes at all.
+func physicsConfig(conf resource.Config) (*PhysicsConfig, error) {
+	if conf.ConvertedAttributes == nil {
+		return nil, nil
+	}
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	return newConf.Physics, nil
+}
+
+// Base is a fake base that returns what it was provided in each method. When configured with
+// physics, it drives a simulated differential drive instead.
 type Base struct {
 	resource.Named
-	resource.TriviallyReconfigurable
 	CloseCount               int
 	WidthMeters              float64
 	TurningRadius            float64
 	WheelCircumferenceMeters float64
 	Geometry                 []spatialmath.Geometry
 	logger                   logging.Logger
+
+	physics *PhysicsConfig
+	drive   *sim.DifferentialDrive
+	clock   clock.Clock
+	// maxSpeed is the speed of a wheel at full power without friction, in mm/s.
+	maxSpeed float64
+	opMgr    *operation.SingleOperationManager
 }
 
 // NewBase instantiates a new base of the fake model type.
@@ -44,6 +119,7 @@ func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, l
 		Named:    conf.ResourceName().AsNamed(),
 		Geometry: []spatialmath.Geometry{},
 		logger:   logger,
+		opMgr:    operation.NewSingleOperationManager(),
 	}
 	if conf.Frame != nil && conf.Frame.Geometry != nil {
 		geometry, err := conf.Frame.Geometry.ParseConfig()
@@ -54,37 +130,161 @@ func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, l
 	}
 	b.WidthMeters = defaultWidthMm * 0.001
 	b.TurningRadius = defaultMinimumTurningRadiusM
+
+	physics, err := physicsConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	if physics != nil {
+		b.physics = physics
+		widthMm, circumferenceMm, maxRPM := physics.WidthMm, physics.WheelCircumferenceMm, physics.MaxRPM
+		if widthMm == 0 {
+			widthMm = defaultWidthMm
+		}
+		if circumferenceMm == 0 {
+			circumferenceMm = defaultSimWheelCircumferenceMm
+		}
+		if maxRPM == 0 {
+			maxRPM = defaultSimMaxRPM
+		}
+		b.WidthMeters = float64(widthMm) * 0.001
+		b.WheelCircumferenceMeters = float64(circumferenceMm) * 0.001
+		b.maxSpeed = maxRPM / 60 * float64(circumferenceMm)
+		b.clock = sim.Clock()
+		b.drive = sim.NewDifferentialDrive(
+			sim.NewMotor(physics.Motor, maxRPM, b.clock),
+			sim.NewMotor(physics.Motor, maxRPM, b.clock),
+			b.WidthMeters,
+			b.WheelCircumferenceMeters,
+			physics.WheelSlipStdDevM,
+			physics.Seed,
+		)
+	}
 	return b, nil
 }
 
-// MoveStraight does nothing.
-func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+// Reconfigure does nothing unless the base's physics change, which rebuilds it.
+func (b *Base) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	physics, err := physicsConfig(conf)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(physics, b.physics) {
+		return resource.NewMustRebuildError(conf.ResourceName())
+	}
 	return nil
 }
 
-// Spin does nothing.
+// Drive returns the simulated drive of a base configured with physics, or nil.
+func (b *Base) Drive() *sim.DifferentialDrive {
+	return b.drive
+}
+
+// MoveStraight drives a simulated base until its wheels have turned through the distance, and
+// otherwise does nothing.
+func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	if b.drive == nil {
+		return nil
+	}
+	if distanceMm == 0 || math.Abs(mmPerSec) < 0.0001 {
+		return b.Stop(ctx, nil)
+	}
+	dir := math.Copysign(1, float64(distanceMm)*mmPerSec)
+	power := dir * math.Abs(mmPerSec) / b.maxSpeed
+	return b.runUntil(ctx, power, power, func(left, right float64) bool {
+		return dir*(left+right)/2 >= math.Abs(float64(distanceMm))
+	})
+}
+
+// Spin turns a simulated base until its wheels have turned through the angle, and otherwise does
+// nothing.
 func (b *Base) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
-	return nil
+	if b.drive == nil {
+		return nil
+	}
+	if math.Abs(angleDeg) < 0.0001 || math.Abs(degsPerSec) < 0.0001 {
+		return b.Stop(ctx, nil)
+	}
+	// each wheel travels along a circle as wide as the base
+	widthMm := b.WidthMeters * 1000
+	dir := math.Copysign(1, angleDeg*degsPerSec)
+	power := dir * math.Abs(degsPerSec) / 360 * math.Pi * widthMm / b.maxSpeed
+	travel := math.Abs(angleDeg) / 360 * math.Pi * widthMm
+	return b.runUntil(ctx, -power, power, func(left, right float64) bool {
+		return dir*(right-left)/2 >= travel
+	})
 }
 
-// SetPower does nothing.
+// runUntil drives the wheels of a simulated base at the given powers until done, given how far
+// each wheel has travelled in mm, and then stops it. It returns early without stopping if the
+// operation is cancelled.
+func (b *Base) runUntil(ctx context.Context, leftPower, rightPower float64, done func(left, right float64) bool) error {
+	ctx, finish := b.opMgr.New(ctx)
+	defer finish()
+
+	leftStart, _ := b.drive.Left.State()
+	rightStart, _ := b.drive.Right.State()
+	b.drive.SetPowers(leftPower, rightPower)
+
+	ticker := b.clock.Ticker(simPollInterval)
+	defer ticker.Stop()
+	for {
+		left, _ := b.drive.Left.State()
+		right, _ := b.drive.Right.State()
+		circumferenceMm := b.WheelCircumferenceMeters * 1000
+		if done((left-leftStart)*circumferenceMm, (right-rightStart)*circumferenceMm) {
+			return b.Stop(ctx, nil)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetPower sets the wheel powers of a simulated base, and otherwise does nothing.
 func (b *Base) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if b.drive == nil {
+		return nil
+	}
+	b.opMgr.CancelRunning(ctx)
+	b.drive.SetPowers(linear.Y-angular.Z, linear.Y+angular.Z)
 	return nil
 }
 
-// SetVelocity does nothing.
+// SetVelocity sets the wheel powers of a simulated base for the velocities, without feedback, and
+// otherwise does nothing.
 func (b *Base) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if b.drive == nil {
+		return nil
+	}
+	b.opMgr.CancelRunning(ctx)
+	turn := angular.Z * math.Pi / 180 * b.WidthMeters * 1000 / 2
+	b.drive.SetPowers((linear.Y-turn)/b.maxSpeed, (linear.Y+turn)/b.maxSpeed)
 	return nil
 }
 
-// Stop does nothing.
+// Stop cuts the power to a simulated base, leaving it to coast to a stop, and otherwise does nothing.
 func (b *Base) Stop(ctx context.Context, extra map[string]interface{}) error {
+	if b.drive == nil {
+		return nil
+	}
+	b.opMgr.CancelRunning(ctx)
+	b.drive.SetPowers(0, 0)
 	return nil
 }
 
-// IsMoving always returns false.
+// IsMoving returns whether a simulated base is powered or still coasting, and otherwise false.
 func (b *Base) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	if b.drive == nil {
+		return false, nil
+	}
+	if b.drive.Left.Power() != 0 || b.drive.Right.Power() != 0 {
+		return true, nil
+	}
+	linear, angular := b.drive.Velocities()
+	return linear != 0 || angular != 0, nil
 }
 
 // Close does nothing.
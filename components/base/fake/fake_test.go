@@ -0,0 +1,137 @@
+package fake
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
+)
+
+// stepUntilDone steps the clock until f returns.
+func stepUntilDone(clk *clock.Mock, f func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f()
+	}()
+	for {
+		select {
+		case err := <-errCh:
+			return err
+		default:
+			clk.Add(simPollInterval)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	_, err := (&Config{}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = (&Config{Physics: &PhysicsConfig{}}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, physics := range []PhysicsConfig{
+		{WidthMm: -1},
+		{WheelCircumferenceMm: -1},
+		{MaxRPM: -1},
+		{WheelSlipStdDevM: -1},
+		{Motor: sim.MotorConfig{InertiaKgM2: -1}},
+	} {
+		physics := physics
+		_, err := (&Config{Physics: &physics}).Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestBase(t *testing.T) {
+	ctx := context.Background()
+	b, err := NewBase(ctx, nil, resource.Config{Name: "base", API: base.API}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	// without physics the base does nothing
+	test.That(t, b.(*Base).Drive(), test.ShouldBeNil)
+	test.That(t, b.MoveStraight(ctx, 100, 100, nil), test.ShouldBeNil)
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	test.That(t, b.Reconfigure(ctx, nil, resource.Config{Name: "base", API: base.API}), test.ShouldBeNil)
+}
+
+func TestPhysics(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	defer sim.SetClock(clk)()
+
+	conf := resource.Config{Name: "base", API: base.API, ConvertedAttributes: &Config{Physics: &PhysicsConfig{}}}
+	bRaw, err := NewBase(ctx, nil, conf, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	b := bRaw.(*Base)
+	drive := b.Drive()
+	test.That(t, drive, test.ShouldNotBeNil)
+	props, err := b.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.WidthMeters, test.ShouldEqual, 0.6)
+	test.That(t, props.WheelCircumferenceMeters, test.ShouldEqual, 0.3)
+
+	// the base drives forward and coasts a little past the goal
+	err = stepUntilDone(clk, func() error { return b.MoveStraight(ctx, 500, 200, nil) })
+	test.That(t, err, test.ShouldBeNil)
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+	clk.Add(time.Second)
+	moving, err = b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	x, y, theta := drive.Pose()
+	test.That(t, x, test.ShouldAlmostEqual, 0)
+	test.That(t, y, test.ShouldBeBetween, 0.5, 0.55)
+	test.That(t, theta, test.ShouldAlmostEqual, 0)
+
+	// spinning counterclockwise turns in place, coasting on by several degrees
+	err = stepUntilDone(clk, func() error { return b.Spin(ctx, 90, 90, nil) })
+	test.That(t, err, test.ShouldBeNil)
+	clk.Add(time.Second)
+	x, y, theta = drive.Pose()
+	test.That(t, x, test.ShouldAlmostEqual, 0)
+	test.That(t, y, test.ShouldBeBetween, 0.5, 0.55)
+	test.That(t, theta, test.ShouldBeBetween, math.Pi/2, math.Pi/2+0.25)
+
+	// velocities are open loop, so friction leaves the base short of them
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 300}, r3.Vector{}, nil), test.ShouldBeNil)
+	clk.Add(50 * time.Millisecond)
+	linear, _ := drive.Velocities()
+	test.That(t, linear, test.ShouldBeBetween, 0.05, 0.25)
+	clk.Add(time.Second)
+	linear, angular := drive.Velocities()
+	test.That(t, linear, test.ShouldBeBetween, 0.25, 0.3)
+	test.That(t, angular, test.ShouldEqual, 0)
+	test.That(t, b.SetPower(ctx, r3.Vector{}, r3.Vector{Z: 1}, nil), test.ShouldBeNil)
+	clk.Add(time.Second)
+	linear, angular = drive.Velocities()
+	test.That(t, linear, test.ShouldAlmostEqual, 0, 0.001)
+	test.That(t, angular, test.ShouldBeGreaterThan, 1)
+
+	// stopping interrupts a motion
+	errCh := make(chan error)
+	go func() {
+		errCh <- b.MoveStraight(ctx, 10000, 200, nil)
+	}()
+	clk.Add(simPollInterval)
+	test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-errCh, test.ShouldBeNil)
+
+	// changing the physics rebuilds the base
+	test.That(t, b.Reconfigure(ctx, nil, conf), test.ShouldBeNil)
+	conf.ConvertedAttributes = &Config{Physics: &PhysicsConfig{WheelSlipStdDevM: 0.01}}
+	test.That(t, resource.IsMustRebuildError(b.Reconfigure(ctx, nil, conf)), test.ShouldBeTrue)
+}
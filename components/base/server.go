@@ -162,7 +162,11 @@ func (s *serviceServer) GetGeometries(ctx context.Context, req *commonpb.GetGeom
 	if err != nil {
 		return nil, err
 	}
-	return &commonpb.GetGeometriesResponse{Geometries: spatialmath.NewGeometriesToProto(geometries)}, nil
+	geometriesProto, err := spatialmath.NewGeometriesToProto(geometries)
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.GetGeometriesResponse{Geometries: geometriesProto}, nil
 }
 
 // DoCommand receives arbitrary commands.
@@ -60,6 +60,7 @@ func NewBoard(
 		analogReaders: map[string]*wrappedAnalogReader{},
 		gpios:         map[string]*gpioPin{},
 		interrupts:    map[string]*digitalInterrupt{},
+		canBuses:      map[string]string{},
 	}
 
 	if err := b.Reconfigure(ctx, nil, conf); err != nil {
@@ -68,7 +69,7 @@ func NewBoard(
 	return b, nil
 }
 
-// Reconfigure reconfigures the board with interrupt pins, spi and i2c, and analogs.
+// Reconfigure reconfigures the board with interrupt pins, spi and i2c, analogs, and CAN buses.
 func (b *Board) Reconfigure(
 	ctx context.Context,
 	_ resource.Dependencies,
@@ -91,6 +92,10 @@ func (b *Board) Reconfigure(
 	if err := b.reconfigureInterrupts(newConf); err != nil {
 		return err
 	}
+	b.canBuses = make(map[string]string, len(newConf.CANBuses))
+	for _, c := range newConf.CANBuses {
+		b.canBuses[c.Name] = c.Interface
+	}
 	return nil
 }
 
@@ -362,6 +367,8 @@ type Board struct {
 
 	gpios      map[string]*gpioPin
 	interrupts map[string]*digitalInterrupt
+	// canBuses maps the name of each configured CAN bus to its network interface.
+	canBuses map[string]string
 
 	workers *utils.StoppableWorkers
 }
@@ -375,6 +382,18 @@ func (b *Board) AnalogByName(name string) (board.Analog, error) {
 	return a, nil
 }
 
+// CANBusByName opens the CAN bus by the given name if it exists. Each call opens a new connection
+// to the bus, which the caller must close.
+func (b *Board) CANBusByName(name string) (buses.CAN, error) {
+	b.mu.RLock()
+	iface, ok := b.canBuses[name]
+	b.mu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("can't find CAN bus (%s)", name)
+	}
+	return buses.NewCanBus(iface)
+}
+
 // DigitalInterruptByName returns the interrupt by the given name if it exists.
 func (b *Board) DigitalInterruptByName(name string) (board.DigitalInterrupt, error) {
 	b.mu.Lock()
@@ -10,6 +10,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/pkg/errors"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/board"
@@ -88,6 +89,16 @@ func TestConfigValidate(t *testing.T) {
 	validConfig.DigitalInterrupts = []board.DigitalInterruptConfig{{Name: "bar", Pin: "3"}}
 	_, err = validConfig.Validate("path")
 	test.That(t, err, test.ShouldBeNil)
+
+	validConfig.CANBuses = []CANBusConfig{{Name: "can"}}
+	_, err = validConfig.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `path.can_buses.0`)
+	test.That(t, resource.GetFieldFromFieldRequiredError(err), test.ShouldEqual, "interface")
+
+	validConfig.CANBuses = []CANBusConfig{{Name: "can", Interface: "can0"}}
+	_, err = validConfig.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
 }
 
 func TestNewBoard(t *testing.T) {
@@ -115,6 +126,7 @@ func TestNewBoard(t *testing.T) {
 
 	conf := &Config{}
 	conf.AnalogReaders = []mcp3008helper.MCP3008AnalogConfig{{Name: "an1", Pin: "1"}}
+	conf.CANBuses = []CANBusConfig{{Name: "can", Interface: "no-such-can"}}
 
 	config := resource.Config{
 		Name:                "board1",
@@ -131,6 +143,12 @@ func TestNewBoard(t *testing.T) {
 	dis := b.DigitalInterruptNames()
 	test.That(t, dis, test.ShouldResemble, []string{})
 
+	_, err = b.(*Board).CANBusByName("other")
+	test.That(t, err, test.ShouldBeError, errors.New("can't find CAN bus (other)"))
+	_, err = b.(*Board).CANBusByName("can")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no CAN interface "no-such-can"`)
+
 	gn1, err := b.GPIOPinByName("1")
 	test.That(t, err, test.ShouldBeNil)
 	// Our test framework uses reflection to walk the structs it asserts on. However, gn1 (and
@@ -0,0 +1,215 @@
+//go:build linux
+
+package buses
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// canFrameSize is the size of a struct can_frame as read from and written to a raw SocketCAN socket.
+const canFrameSize = 16
+
+// canReadTimeout bounds how long a read blocks so that Receive notices when its context is done.
+const canReadTimeout = 50 * time.Millisecond
+
+// The flags SocketCAN keeps in the upper bits of a frame's identifier.
+const (
+	canEFFFlag    = unix.CAN_EFF_FLAG
+	canRTRFlag    = unix.CAN_RTR_FLAG
+	canErrFlag    = unix.CAN_ERR_FLAG
+	canInvFilter  = unix.CAN_INV_FILTER
+	canMaxDataLen = 8
+)
+
+// NewCanBus opens a raw SocketCAN socket on the named network interface, such as "can0" or
+// "vcan0". The interface must already be up, with its bitrate set, e.g. by running
+// `ip link set can0 up type can bitrate 500000`.
+func NewCanBus(iface string) (CAN, error) {
+	netIface, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no CAN interface %q", iface)
+	}
+	fd, err := unix.Socket(unix.AF_CAN, unix.SOCK_RAW, unix.CAN_RAW)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening SocketCAN socket")
+	}
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{
+		Usec: canReadTimeout.Microseconds(),
+	}); err != nil {
+		//nolint:errcheck
+		unix.Close(fd)
+		return nil, err
+	}
+	if err := unix.Bind(fd, &unix.SockaddrCAN{Ifindex: netIface.Index}); err != nil {
+		//nolint:errcheck
+		unix.Close(fd)
+		return nil, errors.Wrapf(err, "error binding to CAN interface %q", iface)
+	}
+	return &canBus{fd: fd, iface: iface}, nil
+}
+
+type canBus struct {
+	iface string
+
+	// mu guards fd against being closed while in use.
+	mu     sync.RWMutex
+	fd     int
+	closed bool
+}
+
+func (cb *canBus) Send(ctx context.Context, frame CANFrame) error {
+	raw, err := encodeCANFrame(frame)
+	if err != nil {
+		return err
+	}
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if cb.closed {
+		return errors.Errorf("CAN bus %q is closed", cb.iface)
+	}
+	for {
+		_, err := unix.Write(cb.fd, raw)
+		// The transmit queue is full when the bus is busy; wait for it to drain.
+		if !errors.Is(err, unix.ENOBUFS) {
+			return errors.Wrapf(err, "error sending frame on CAN bus %q", cb.iface)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (cb *canBus) Receive(ctx context.Context) (CANFrame, error) {
+	raw := make([]byte, canFrameSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return CANFrame{}, err
+		}
+		n, err := cb.read(raw)
+		switch {
+		case errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR):
+			continue
+		case err != nil:
+			return CANFrame{}, errors.Wrapf(err, "error receiving frame on CAN bus %q", cb.iface)
+		case n != canFrameSize:
+			return CANFrame{}, errors.Errorf("received a frame of %d bytes on CAN bus %q", n, cb.iface)
+		}
+		return decodeCANFrame(raw), nil
+	}
+}
+
+func (cb *canBus) read(raw []byte) (int, error) {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if cb.closed {
+		return 0, errors.Errorf("CAN bus %q is closed", cb.iface)
+	}
+	return unix.Read(cb.fd, raw)
+}
+
+func (cb *canBus) SetFilters(filters []CANFilter) error {
+	raw := make([]unix.CanFilter, 0, len(filters))
+	for _, filter := range filters {
+		raw = append(raw, encodeCANFilter(filter))
+	}
+	if len(raw) == 0 {
+		// A single filter matching everything, since no filters at all matches nothing.
+		raw = append(raw, unix.CanFilter{})
+	}
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if cb.closed {
+		return errors.Errorf("CAN bus %q is closed", cb.iface)
+	}
+	return unix.SetsockoptCanRawFilter(cb.fd, unix.SOL_CAN_RAW, unix.CAN_RAW_FILTER, raw)
+}
+
+func (cb *canBus) SetErrorMask(mask uint32) error {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if cb.closed {
+		return errors.Errorf("CAN bus %q is closed", cb.iface)
+	}
+	return unix.SetsockoptInt(cb.fd, unix.SOL_CAN_RAW, unix.CAN_RAW_ERR_FILTER, int(mask&CANErrorAll))
+}
+
+func (cb *canBus) Close() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.closed {
+		return nil
+	}
+	cb.closed = true
+	return unix.Close(cb.fd)
+}
+
+// encodeCANFrame encodes a frame as a struct can_frame.
+func encodeCANFrame(frame CANFrame) ([]byte, error) {
+	if len(frame.Data) > canMaxDataLen {
+		return nil, errors.Errorf("CAN frame may carry at most %d data bytes, not %d", canMaxDataLen, len(frame.Data))
+	}
+	id := frame.ID
+	if frame.Extended {
+		if id > CANExtendedIDMask {
+			return nil, errors.Errorf("extended CAN ID %#x is out of range", id)
+		}
+		id |= canEFFFlag
+	} else if id > CANStandardIDMask {
+		return nil, errors.Errorf("standard CAN ID %#x is out of range", id)
+	}
+	if frame.RTR {
+		id |= canRTRFlag
+	}
+	raw := make([]byte, canFrameSize)
+	binary.NativeEndian.PutUint32(raw[0:4], id)
+	raw[4] = byte(len(frame.Data))
+	copy(raw[8:], frame.Data)
+	return raw, nil
+}
+
+// decodeCANFrame decodes a struct can_frame.
+func decodeCANFrame(raw []byte) CANFrame {
+	id := binary.NativeEndian.Uint32(raw[0:4])
+	length := int(raw[4])
+	if length > canMaxDataLen {
+		length = canMaxDataLen
+	}
+	frame := CANFrame{
+		Extended: id&canEFFFlag != 0,
+		RTR:      id&canRTRFlag != 0,
+		Error:    id&canErrFlag != 0,
+		Data:     make([]byte, length),
+	}
+	copy(frame.Data, raw[8:])
+	if frame.Extended || frame.Error {
+		frame.ID = id & CANExtendedIDMask
+	} else {
+		frame.ID = id & CANStandardIDMask
+	}
+	return frame
+}
+
+// encodeCANFilter encodes a filter as a struct can_filter, which also matches the frame format.
+func encodeCANFilter(filter CANFilter) unix.CanFilter {
+	var raw unix.CanFilter
+	if filter.Extended {
+		raw.Id = (filter.ID & CANExtendedIDMask) | canEFFFlag
+		raw.Mask = (filter.Mask & CANExtendedIDMask) | canEFFFlag
+	} else {
+		raw.Id = filter.ID & CANStandardIDMask
+		raw.Mask = (filter.Mask & CANStandardIDMask) | canEFFFlag
+	}
+	if filter.Invert {
+		raw.Id |= canInvFilter
+	}
+	return raw
+}
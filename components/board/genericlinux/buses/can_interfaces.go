@@ -0,0 +1,69 @@
+package buses
+
+import (
+	"context"
+)
+
+// The largest identifiers of standard (11 bit) and extended (29 bit) CAN frames.
+const (
+	CANStandardIDMask = 0x7FF
+	CANExtendedIDMask = 0x1FFFFFFF
+)
+
+// The classes of CAN error frames, as reported in the ID of an error frame. They may be combined
+// into the mask passed to CAN.SetErrorMask.
+const (
+	CANErrorTxTimeout       = 0x001
+	CANErrorLostArbitration = 0x002
+	CANErrorController      = 0x004
+	CANErrorProtocol        = 0x008
+	CANErrorTransceiver     = 0x010
+	CANErrorNoAck           = 0x020
+	CANErrorBusOff          = 0x040
+	CANErrorBus             = 0x080
+	CANErrorRestarted       = 0x100
+	CANErrorAll             = CANExtendedIDMask
+)
+
+// A CANFrame is a classic CAN frame of up to 8 data bytes.
+type CANFrame struct {
+	// ID is the 11 bit identifier of the frame, or the 29 bit identifier if Extended is set. For
+	// error frames, it holds the error classes instead.
+	ID       uint32
+	Extended bool
+	// RTR marks a remote transmission request, which carries no data.
+	RTR bool
+	// Error marks an error frame reported by the CAN controller rather than sent by another node.
+	Error bool
+	Data  []byte
+}
+
+// A CANFilter selects the frames received from a CAN bus: a frame matches when its ID, masked by
+// Mask, equals ID masked by Mask. Filters only match frames of their own format (standard or
+// extended), and Invert selects every frame that does not match instead.
+type CANFilter struct {
+	ID       uint32
+	Mask     uint32
+	Extended bool
+	Invert   bool
+}
+
+// CAN represents a connection to a CAN bus on the board. Each connection receives its own copy of
+// the frames on the bus, so that many devices on the same bus can be handled independently.
+type CAN interface {
+	// Send writes a frame to the bus.
+	Send(ctx context.Context, frame CANFrame) error
+
+	// Receive blocks until a frame passing the filters is received, or the context is done.
+	Receive(ctx context.Context) (CANFrame, error)
+
+	// SetFilters sets the filters frames must match at least one of to be received. No filters
+	// receives every frame.
+	SetFilters(filters []CANFilter) error
+
+	// SetErrorMask sets the classes of error frames received, none by default.
+	SetErrorMask(mask uint32) error
+
+	// Close closes the connection to the bus.
+	Close() error
+}
@@ -0,0 +1,77 @@
+//go:build linux
+
+package buses
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils"
+	"golang.org/x/sys/unix"
+)
+
+func TestCANFrameEncoding(t *testing.T) {
+	for _, frame := range []CANFrame{
+		{ID: 0x601, Data: []byte{0x40, 0x41, 0x60, 0x00, 0, 0, 0, 0}},
+		{ID: 0x18FF50E5, Extended: true, Data: []byte{1, 2, 3}},
+		{ID: 0x705, RTR: true, Data: []byte{}},
+	} {
+		raw, err := encodeCANFrame(frame)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, raw, test.ShouldHaveLength, canFrameSize)
+		test.That(t, decodeCANFrame(raw), test.ShouldResemble, frame)
+	}
+
+	_, err := encodeCANFrame(CANFrame{ID: 0x800})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = encodeCANFrame(CANFrame{ID: 0x20000000, Extended: true})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = encodeCANFrame(CANFrame{ID: 0x100, Data: make([]byte, 9)})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// error frames are reported by the controller with the error flag set
+	raw := make([]byte, canFrameSize)
+	binary.NativeEndian.PutUint32(raw, canErrFlag|CANErrorBusOff)
+	test.That(t, decodeCANFrame(raw), test.ShouldResemble, CANFrame{ID: CANErrorBusOff, Error: true, Data: []byte{}})
+}
+
+func TestCANFilterEncoding(t *testing.T) {
+	test.That(t, encodeCANFilter(CANFilter{ID: 0x585, Mask: 0x7F}), test.ShouldResemble,
+		unix.CanFilter{Id: 0x585, Mask: 0x7F | unix.CAN_EFF_FLAG})
+	test.That(t, encodeCANFilter(CANFilter{ID: 0x18FF50E5, Mask: 0xFF, Extended: true, Invert: true}), test.ShouldResemble,
+		unix.CanFilter{Id: 0x18FF50E5 | unix.CAN_EFF_FLAG | unix.CAN_INV_FILTER, Mask: 0xFF | unix.CAN_EFF_FLAG})
+}
+
+// TestVirtualCANBus sends frames between two connections to the vcan0 interface, which can be
+// created with:
+//
+//	ip link add dev vcan0 type vcan && ip link set up vcan0
+func TestVirtualCANBus(t *testing.T) {
+	sender, err := NewCanBus("vcan0")
+	if err != nil {
+		t.Skipf("vcan0 is not available: %v", err)
+	}
+	defer utils.UncheckedErrorFunc(sender.Close)
+	receiver, err := NewCanBus("vcan0")
+	test.That(t, err, test.ShouldBeNil)
+	defer utils.UncheckedErrorFunc(receiver.Close)
+	test.That(t, receiver.SetFilters([]CANFilter{{ID: 0x580, Mask: 0x780}}), test.ShouldBeNil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	test.That(t, sender.Send(ctx, CANFrame{ID: 0x185, Data: []byte{1}}), test.ShouldBeNil)
+	test.That(t, sender.Send(ctx, CANFrame{ID: 0x585, Data: []byte{2}}), test.ShouldBeNil)
+	frame, err := receiver.Receive(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frame, test.ShouldResemble, CANFrame{ID: 0x585, Data: []byte{2}})
+
+	_, err = receiver.Receive(ctx)
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+
+	test.That(t, receiver.Close(), test.ShouldBeNil)
+	_, err = receiver.Receive(context.Background())
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -13,6 +13,25 @@ import (
 type Config struct {
 	AnalogReaders     []mcp3008helper.MCP3008AnalogConfig `json:"analogs,omitempty"`
 	DigitalInterrupts []board.DigitalInterruptConfig      `json:"digital_interrupts,omitempty"`
+	CANBuses          []CANBusConfig                      `json:"can_buses,omitempty"`
+}
+
+// CANBusConfig names a SocketCAN network interface, such as "can0", so that other components can
+// open it through the board.
+type CANBusConfig struct {
+	Name      string `json:"name"`
+	Interface string `json:"interface"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *CANBusConfig) Validate(path string) error {
+	if conf.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if conf.Interface == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "interface")
+	}
+	return nil
 }
 
 // Validate ensures all parts of the config are valid.
@@ -27,6 +46,11 @@ func (conf *Config) Validate(path string) ([]string, error) {
 			return nil, err
 		}
 	}
+	for idx, c := range conf.CANBuses {
+		if err := c.Validate(fmt.Sprintf("%s.%s.%d", path, "can_buses", idx)); err != nil {
+			return nil, err
+		}
+	}
 	return nil, nil
 }
 
@@ -40,6 +64,7 @@ func (conf *Config) Validate(path string) ([]string, error) {
 type LinuxBoardConfig struct {
 	AnalogReaders     []mcp3008helper.MCP3008AnalogConfig
 	DigitalInterrupts []board.DigitalInterruptConfig
+	CANBuses          []CANBusConfig
 	GpioMappings      map[string]GPIOBoardMapping
 }
 
@@ -63,6 +88,7 @@ func ConstPinDefs(gpioMappings map[string]GPIOBoardMapping) ConfigConverter {
 		return &LinuxBoardConfig{
 			AnalogReaders:     newConf.AnalogReaders,
 			DigitalInterrupts: newConf.DigitalInterrupts,
+			CANBuses:          newConf.CANBuses,
 			GpioMappings:      gpioMappings,
 		}, nil
 	}
@@ -6,5 +6,6 @@ package register
 import (
 	// for cameras.
 	_ "go.viam.com/rdk/components/camera/fake"
+	_ "go.viam.com/rdk/components/camera/rtsp"
 	_ "go.viam.com/rdk/components/camera/transformpipeline"
 )
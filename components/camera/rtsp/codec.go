@@ -0,0 +1,186 @@
+package rtsp
+
+import (
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph265"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmjpeg"
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/pkg/codecs/h265"
+	"github.com/pion/rtp"
+	"github.com/pkg/errors"
+)
+
+// Codec names a video codec a camera can stream.
+type Codec string
+
+// The video codecs the rtsp camera can stream.
+const (
+	CodecH264  Codec = "H264"
+	CodecH265  Codec = "H265"
+	CodecMJPEG Codec = "MJPEG"
+)
+
+// videoCodec reassembles the RTP packets of one video format into access units: the NALUs of a
+// frame for H.264 and H.265, or the single JPEG of a frame for MJPEG.
+type videoCodec interface {
+	codec() Codec
+	// decode returns the access unit the packet completes, or nil if more packets are needed.
+	decode(pkt *rtp.Packet) ([][]byte, error)
+	// randomAccess returns whether decoding can start at the access unit, along with the unit
+	// completed by any parameter sets that were only sent out of band.
+	randomAccess(au [][]byte) ([][]byte, bool)
+}
+
+// findVideoCodec picks the first video format of the session the camera knows how to handle.
+func findVideoCodec(desc *description.Session) (*description.Media, format.Format, videoCodec, error) {
+	var h264Format *format.H264
+	if media := desc.FindFormat(&h264Format); media != nil {
+		c, err := newH264Codec(h264Format)
+		return media, h264Format, c, err
+	}
+	var h265Format *format.H265
+	if media := desc.FindFormat(&h265Format); media != nil {
+		c, err := newH265Codec(h265Format)
+		return media, h265Format, c, err
+	}
+	var mjpegFormat *format.MJPEG
+	if media := desc.FindFormat(&mjpegFormat); media != nil {
+		c, err := newMJPEGCodec(mjpegFormat)
+		return media, mjpegFormat, c, err
+	}
+	return nil, nil, nil, errors.New("stream has no H264, H265 or MJPEG video")
+}
+
+type h264Codec struct {
+	rtpDec   *rtph264.Decoder
+	sps, pps []byte
+}
+
+func newH264Codec(forma *format.H264) (*h264Codec, error) {
+	rtpDec, err := forma.CreateDecoder()
+	if err != nil {
+		return nil, err
+	}
+	sps, pps := forma.SafeParams()
+	return &h264Codec{rtpDec: rtpDec, sps: sps, pps: pps}, nil
+}
+
+func (c *h264Codec) codec() Codec {
+	return CodecH264
+}
+
+func (c *h264Codec) decode(pkt *rtp.Packet) ([][]byte, error) {
+	au, err := c.rtpDec.Decode(pkt)
+	if errors.Is(err, rtph264.ErrMorePacketsNeeded) || errors.Is(err, rtph264.ErrNonStartingPacketAndNoPrevious) {
+		return nil, nil
+	}
+	return au, err
+}
+
+func (c *h264Codec) randomAccess(au [][]byte) ([][]byte, bool) {
+	var hasSPS, hasPPS bool
+	for _, nalu := range au {
+		if len(nalu) == 0 {
+			continue
+		}
+		switch h264.NALUType(nalu[0] & 0x1f) {
+		case h264.NALUTypeSPS:
+			c.sps, hasSPS = nalu, true
+		case h264.NALUTypePPS:
+			c.pps, hasPPS = nalu, true
+		default:
+		}
+	}
+	if !h264.IDRPresent(au) {
+		return au, false
+	}
+	if (!hasSPS || !hasPPS) && c.sps != nil && c.pps != nil {
+		au = append([][]byte{c.sps, c.pps}, au...)
+	}
+	return au, true
+}
+
+type h265Codec struct {
+	rtpDec        *rtph265.Decoder
+	vps, sps, pps []byte
+}
+
+func newH265Codec(forma *format.H265) (*h265Codec, error) {
+	rtpDec, err := forma.CreateDecoder()
+	if err != nil {
+		return nil, err
+	}
+	vps, sps, pps := forma.SafeParams()
+	return &h265Codec{rtpDec: rtpDec, vps: vps, sps: sps, pps: pps}, nil
+}
+
+func (c *h265Codec) codec() Codec {
+	return CodecH265
+}
+
+func (c *h265Codec) decode(pkt *rtp.Packet) ([][]byte, error) {
+	au, err := c.rtpDec.Decode(pkt)
+	if errors.Is(err, rtph265.ErrMorePacketsNeeded) || errors.Is(err, rtph265.ErrNonStartingPacketAndNoPrevious) {
+		return nil, nil
+	}
+	return au, err
+}
+
+func (c *h265Codec) randomAccess(au [][]byte) ([][]byte, bool) {
+	var hasVPS, hasSPS, hasPPS bool
+	for _, nalu := range au {
+		if len(nalu) == 0 {
+			continue
+		}
+		switch h265.NALUType((nalu[0] >> 1) & 0x3f) {
+		case h265.NALUType_VPS_NUT:
+			c.vps, hasVPS = nalu, true
+		case h265.NALUType_SPS_NUT:
+			c.sps, hasSPS = nalu, true
+		case h265.NALUType_PPS_NUT:
+			c.pps, hasPPS = nalu, true
+		default:
+		}
+	}
+	if !h265.IsRandomAccess(au) {
+		return au, false
+	}
+	if (!hasVPS || !hasSPS || !hasPPS) && c.vps != nil && c.sps != nil && c.pps != nil {
+		au = append([][]byte{c.vps, c.sps, c.pps}, au...)
+	}
+	return au, true
+}
+
+type mjpegCodec struct {
+	rtpDec *rtpmjpeg.Decoder
+}
+
+func newMJPEGCodec(forma *format.MJPEG) (*mjpegCodec, error) {
+	rtpDec, err := forma.CreateDecoder()
+	if err != nil {
+		return nil, err
+	}
+	return &mjpegCodec{rtpDec: rtpDec}, nil
+}
+
+func (c *mjpegCodec) codec() Codec {
+	return CodecMJPEG
+}
+
+func (c *mjpegCodec) decode(pkt *rtp.Packet) ([][]byte, error) {
+	frame, err := c.rtpDec.Decode(pkt)
+	if errors.Is(err, rtpmjpeg.ErrMorePacketsNeeded) || errors.Is(err, rtpmjpeg.ErrNonStartingPacketAndNoPrevious) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{frame}, nil
+}
+
+// randomAccess is always true since every MJPEG frame stands alone.
+func (c *mjpegCodec) randomAccess(au [][]byte) ([][]byte, bool) {
+	return au, true
+}
@@ -0,0 +1,131 @@
+package rtsp
+
+import (
+	"image"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A FrameDecoder decodes the access units of an H.264 or H.265 stream into images. Access units
+// are fed in order starting from a random access point, and Decode returns a nil image until the
+// units fed so far complete a frame.
+type FrameDecoder interface {
+	Decode(au [][]byte) (image.Image, error)
+	Close() error
+}
+
+// A FrameDecoderFactory creates a decoder for a new stream.
+type FrameDecoderFactory func() (FrameDecoder, error)
+
+var (
+	frameDecodersMu sync.RWMutex
+	frameDecoders   = map[Codec]FrameDecoderFactory{}
+)
+
+// RegisterFrameDecoder makes a decoder for the codec available to rtsp cameras, so that they accept
+// streams in it and return images from them. MJPEG needs no decoder, and no H.264 or H.265 decoder
+// is built in; without one, H.264 streams are only accepted by cameras configured with
+// rtp_passthrough_only. Cameras only use the decoders registered by the time they are configured,
+// so this should be called from init.
+func RegisterFrameDecoder(codec Codec, factory FrameDecoderFactory) {
+	frameDecodersMu.Lock()
+	defer frameDecodersMu.Unlock()
+	frameDecoders[codec] = factory
+}
+
+// decodableCodecs returns the codecs images can currently be returned from.
+func decodableCodecs() map[Codec]bool {
+	frameDecodersMu.RLock()
+	defer frameDecodersMu.RUnlock()
+	decodable := map[Codec]bool{CodecMJPEG: true}
+	for codec := range frameDecoders {
+		decodable[codec] = true
+	}
+	return decodable
+}
+
+// noFrameDecoderError refuses a stream in a codec no decoder was registered for when the camera was
+// configured.
+func noFrameDecoderError(codec Codec) error {
+	if codec == CodecH264 {
+		return errors.Errorf("no frame decoder registered for %s, so images are unavailable; "+
+			"set rtp_passthrough_only to stream it to WebRTC without images", codec)
+	}
+	return errors.Errorf("no frame decoder registered for %s, so images are unavailable, "+
+		"and only H264 can be passed through to WebRTC", codec)
+}
+
+func newFrameDecoder(codec Codec) (FrameDecoder, error) {
+	frameDecodersMu.RLock()
+	factory, ok := frameDecoders[codec]
+	frameDecodersMu.RUnlock()
+	if !ok {
+		return nil, noFrameDecoderError(codec)
+	}
+	return factory()
+}
+
+// maxGroupSize bounds the access units kept to decode the latest frame on demand. A stream that
+// goes longer between random access points has its group dropped until the next one.
+const maxGroupSize = 600
+
+// groupDecoder decodes the latest frame of a group of pictures on demand. Each call feeds the
+// decoder only the access units received since the last, so frames are decoded when images are
+// requested rather than as they arrive, without decoding any frame twice.
+type groupDecoder struct {
+	mu        sync.Mutex
+	codec     Codec
+	decoder   FrameDecoder
+	group     int
+	fed       int
+	lastImage image.Image
+}
+
+// latest returns the latest frame of the numbered group, given all of its access units so far.
+func (d *groupDecoder) latest(codec Codec, group int, units [][][]byte) (image.Image, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.decoder == nil || d.codec != codec {
+		if err := d.closeDecoder(); err != nil {
+			return nil, err
+		}
+		decoder, err := newFrameDecoder(codec)
+		if err != nil {
+			return nil, err
+		}
+		d.decoder, d.codec = decoder, codec
+		d.group = group - 1
+	}
+	if d.group != group {
+		d.group, d.fed, d.lastImage = group, 0, nil
+	}
+	for ; d.fed < len(units); d.fed++ {
+		img, err := d.decoder.Decode(units[d.fed])
+		if err != nil {
+			return nil, err
+		}
+		if img != nil {
+			d.lastImage = img
+		}
+	}
+	if d.lastImage == nil {
+		return nil, errors.New("no frame decoded yet")
+	}
+	return d.lastImage, nil
+}
+
+func (d *groupDecoder) close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closeDecoder()
+}
+
+func (d *groupDecoder) closeDecoder() error {
+	if d.decoder == nil {
+		return nil
+	}
+	err := d.decoder.Close()
+	d.decoder, d.lastImage = nil, nil
+	return err
+}
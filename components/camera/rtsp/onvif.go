@@ -0,0 +1,126 @@
+package rtsp
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	wsDiscoveryAddress = "239.255.255.250:3702"
+	// discoveryTimeout is how long to wait for probe matches when the context has no deadline.
+	discoveryTimeout = 2 * time.Second
+)
+
+// wsDiscoveryProbe asks ONVIF video transmitters to identify themselves. It is formatted with a
+// message ID.
+const wsDiscoveryProbe = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" ` +
+	`xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" ` +
+	`xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" ` +
+	`xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+<s:Header>
+<a:Action s:mustUnderstand="1">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</a:Action>
+<a:MessageID>uuid:%s</a:MessageID>
+<a:ReplyTo><a:Address>http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo>
+<a:To s:mustUnderstand="1">urn:schemas-xmlsoap-org:ws:2005:04:discovery</a:To>
+</s:Header>
+<s:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></s:Body>
+</s:Envelope>`
+
+// probeMatches is the part of a WS-Discovery ProbeMatches response the camera uses.
+type probeMatches struct {
+	Matches []struct {
+		Address string `xml:"EndpointReference>Address"`
+		Scopes  string `xml:"Scopes"`
+		XAddrs  string `xml:"XAddrs"`
+	} `xml:"Body>ProbeMatches>ProbeMatch"`
+}
+
+// DiscoveredCameras are the ONVIF cameras found on the local network.
+type DiscoveredCameras struct {
+	Cameras []DiscoveredCamera `json:"cameras"`
+}
+
+// DiscoveredCamera is an ONVIF camera that answered a WS-Discovery probe. Its stream addresses are
+// listed by its device service, which usually requires credentials, so they are not resolved here.
+type DiscoveredCamera struct {
+	// Address identifies the device, usually as a URN.
+	Address string `json:"address"`
+	// XAddrs are the URLs of the device service.
+	XAddrs []string `json:"xaddrs"`
+	// Scopes describe the device, such as its name, hardware and location.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// Discover finds ONVIF cameras on the local network with a WS-Discovery probe.
+func Discover(ctx context.Context, logger logging.Logger) (*DiscoveredCameras, error) {
+	return discover(ctx, wsDiscoveryAddress, logger)
+}
+
+func discover(ctx context.Context, address string, logger logging.Logger) (*DiscoveredCameras, error) {
+	addr, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.CDebugw(ctx, "failed to close WS-Discovery connection", "error", err)
+		}
+	}()
+
+	if _, err := conn.WriteTo([]byte(fmt.Sprintf(wsDiscoveryProbe, uuid.New())), addr); err != nil {
+		return nil, errors.Wrap(err, "failed to send WS-Discovery probe")
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(discoveryTimeout)
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	discovered := &DiscoveredCameras{Cameras: []DiscoveredCamera{}}
+	seen := map[string]bool{}
+	buf := make([]byte, 64*1024)
+	for ctx.Err() == nil {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, err
+		}
+		var matches probeMatches
+		if err := xml.Unmarshal(buf[:n], &matches); err != nil {
+			logger.CDebugw(ctx, "ignoring malformed WS-Discovery response", "from", from, "error", err)
+			continue
+		}
+		for _, match := range matches.Matches {
+			address := strings.TrimSpace(match.Address)
+			if address == "" || seen[address] {
+				continue
+			}
+			seen[address] = true
+			discovered.Cameras = append(discovered.Cameras, DiscoveredCamera{
+				Address: address,
+				XAddrs:  strings.Fields(match.XAddrs),
+				Scopes:  strings.Fields(match.Scopes),
+			})
+		}
+	}
+	return discovered, nil
+}
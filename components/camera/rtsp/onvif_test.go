@@ -0,0 +1,69 @@
+package rtsp
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+const testProbeMatches = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope" ` +
+	`xmlns:wsa="http://schemas.xmlsoap.org/ws/2004/08/addressing" ` +
+	`xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+<SOAP-ENV:Body><d:ProbeMatches><d:ProbeMatch>
+<wsa:EndpointReference><wsa:Address>urn:uuid:%s</wsa:Address></wsa:EndpointReference>
+<d:Types>dn:NetworkVideoTransmitter</d:Types>
+<d:Scopes>onvif://www.onvif.org/name/Front onvif://www.onvif.org/hardware/IPC</d:Scopes>
+<d:XAddrs>http://192.168.1.20/onvif/device_service http://[fe80::1]/onvif/device_service</d:XAddrs>
+</d:ProbeMatch></d:ProbeMatches></SOAP-ENV:Body>
+</SOAP-ENV:Envelope>`
+
+func TestDiscover(t *testing.T) {
+	responder, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	test.That(t, err, test.ShouldBeNil)
+	defer responder.Close()
+
+	// answer the probe twice for one camera and once for another, with some noise between
+	probes := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 64*1024)
+		n, from, err := responder.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		probes <- string(buf[:n])
+		for _, reply := range []string{
+			strings.Replace(testProbeMatches, "%s", "front", 1),
+			"not xml",
+			strings.Replace(testProbeMatches, "%s", "front", 1),
+			strings.Replace(testProbeMatches, "%s", "back", 1),
+		} {
+			if _, err := responder.WriteTo([]byte(reply), from); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	discovered, err := discover(ctx, responder.LocalAddr().String(), logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	probe := <-probes
+	test.That(t, probe, test.ShouldContainSubstring, "<d:Probe>")
+	test.That(t, probe, test.ShouldContainSubstring, "dn:NetworkVideoTransmitter")
+
+	test.That(t, discovered.Cameras, test.ShouldHaveLength, 2)
+	test.That(t, discovered.Cameras[0], test.ShouldResemble, DiscoveredCamera{
+		Address: "urn:uuid:front",
+		XAddrs:  []string{"http://192.168.1.20/onvif/device_service", "http://[fe80::1]/onvif/device_service"},
+		Scopes:  []string{"onvif://www.onvif.org/name/Front", "onvif://www.onvif.org/hardware/IPC"},
+	})
+	test.That(t, discovered.Cameras[1].Address, test.ShouldEqual, "urn:uuid:back")
+}
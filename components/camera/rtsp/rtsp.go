@@ -0,0 +1,475 @@
+// Package rtsp implements a camera that streams from an RTSP server, such as an IP camera, without
+// shelling out to ffmpeg. H.264 streams are passed through to WebRTC without transcoding, and
+// frames are only decoded when an image is requested.
+//
+// Images are returned from MJPEG streams. No H.264 or H.265 decoder is built in, so a camera only
+// accepts streams in those codecs if a decoder was registered with RegisterFrameDecoder before it
+// was configured, or if it is configured with rtp_passthrough_only to stream H.264 to WebRTC
+// without returning images. H.265 cannot be passed through, since the robot's WebRTC video tracks
+// are H.264.
+package rtsp
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/rtptime"
+	"github.com/pion/rtp"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/rtppassthrough"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the model of the RTSP camera.
+var Model = resource.DefaultModelFamily.WithModel("rtsp")
+
+const (
+	defaultReconnectInterval = 2 * time.Second
+	// webrtcPayloadMaxSize keeps passed through packets within a WebRTC MTU of 1200 bytes, less the
+	// 12 byte RTP header.
+	webrtcPayloadMaxSize = 1188
+)
+
+func init() {
+	resource.RegisterComponent(
+		camera.API,
+		Model,
+		resource.Registration[camera.Camera, *Config]{
+			Constructor: NewCamera,
+			Discover: func(ctx context.Context, logger logging.Logger) (interface{}, error) {
+				return Discover(ctx, logger)
+			},
+		})
+}
+
+// Config are the attributes of an RTSP camera. The codec of the stream is only known once the
+// camera connects, and a stream the camera cannot serve is refused then, with the reason reported by
+// every call until it reconnects to one it can.
+type Config struct {
+	// Address is the rtsp:// or rtsps:// URL of the stream, including any credentials.
+	Address string `json:"rtsp_address"`
+	// Transport is "tcp" or "udp". When empty, UDP is tried first and TCP is used if no packets
+	// arrive over it.
+	Transport            string                             `json:"transport,omitempty"`
+	ReconnectIntervalSec float64                            `json:"reconnect_interval_sec,omitempty"`
+	CameraParameters     *transform.PinholeCameraIntrinsics `json:"intrinsic_parameters,omitempty"`
+	DistortionParameters *transform.BrownConrady            `json:"distortion_parameters,omitempty"`
+	// RTPPassthroughOnly streams H.264 to WebRTC without returning images, so that streams can be
+	// viewed when no frame decoder is registered for them. Streams in other codecs are refused.
+	RTPPassthroughOnly bool `json:"rtp_passthrough_only,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Address == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "rtsp_address")
+	}
+	if _, err := base.ParseURL(conf.Address); err != nil {
+		return nil, resource.NewConfigValidationError(path, errors.Wrap(err, "invalid rtsp_address"))
+	}
+	if _, err := conf.transport(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	if conf.ReconnectIntervalSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("reconnect_interval_sec cannot be negative"))
+	}
+	if conf.CameraParameters != nil && (conf.CameraParameters.Width < 0 || conf.CameraParameters.Height < 0) {
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("got illegal negative dimensions for width_px and height_px (%d, %d) fields set in intrinsic_parameters",
+				conf.CameraParameters.Width, conf.CameraParameters.Height))
+	}
+	return nil, nil
+}
+
+func (conf *Config) transport() (*gortsplib.Transport, error) {
+	var transport gortsplib.Transport
+	switch conf.Transport {
+	case "":
+		return nil, nil
+	case "tcp":
+		transport = gortsplib.TransportTCP
+	case "udp":
+		transport = gortsplib.TransportUDP
+	default:
+		return nil, errors.Errorf("transport must be tcp or udp, not %q", conf.Transport)
+	}
+	return &transport, nil
+}
+
+// NewCamera returns a camera that streams from the configured RTSP server, reconnecting whenever
+// the stream drops.
+func NewCamera(
+	ctx context.Context,
+	_ resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (camera.Camera, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	src, err := newRTSPCamera(newConf, logger)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := camera.NewVideoSourceFromReader(
+		ctx,
+		src,
+		&transform.PinholeCameraModel{
+			PinholeCameraIntrinsics: newConf.CameraParameters,
+			Distortion:              newConf.DistortionParameters,
+		},
+		camera.ColorStream,
+	)
+	if err != nil {
+		return nil, multierr.Combine(err, src.Close(ctx))
+	}
+	return camera.FromVideoSource(conf.ResourceName(), vs, logger), nil
+}
+
+// rtspCamera keeps the current group of pictures of the stream so that Read can decode its latest
+// frame, and passes H.264 access units through to RTP subscribers as they arrive.
+type rtspCamera struct {
+	address           *base.URL
+	transport         *gortsplib.Transport
+	reconnectInterval time.Duration
+	logger            logging.Logger
+
+	cancelCtx               context.Context
+	cancelFn                context.CancelFunc
+	activeBackgroundWorkers sync.WaitGroup
+	started                 time.Time
+	rtpEnc                  *rtph264.Encoder
+	rtpTime                 *rtptime.Encoder
+	decoder                 groupDecoder
+	// decodable are the codecs decoders were registered for when the camera was configured.
+	decodable       map[Codec]bool
+	passthroughOnly bool
+
+	mu           sync.Mutex
+	codec        Codec
+	streamErr    error
+	group        int
+	units        [][][]byte
+	bufAndCBByID map[rtppassthrough.SubscriptionID]*subscriber
+}
+
+// subscriber is an RTP passthrough subscription, which starts receiving access units at the next
+// random access point so that its stream can be decoded from the start.
+type subscriber struct {
+	cb      rtppassthrough.PacketCallback
+	buf     *rtppassthrough.Buffer
+	started bool
+}
+
+func newRTSPCamera(conf *Config, logger logging.Logger) (*rtspCamera, error) {
+	address, err := base.ParseURL(conf.Address)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := conf.transport()
+	if err != nil {
+		return nil, err
+	}
+	reconnectInterval := defaultReconnectInterval
+	if conf.ReconnectIntervalSec > 0 {
+		reconnectInterval = time.Duration(conf.ReconnectIntervalSec * float64(time.Second))
+	}
+
+	rtpEnc := &rtph264.Encoder{PayloadType: 96, PayloadMaxSize: webrtcPayloadMaxSize}
+	if err := rtpEnc.Init(); err != nil {
+		return nil, err
+	}
+	rtpTime := &rtptime.Encoder{ClockRate: 90000}
+	if err := rtpTime.Initialize(); err != nil {
+		return nil, err
+	}
+
+	cancelCtx, cancelFn := context.WithCancel(context.Background())
+	c := &rtspCamera{
+		address:           address,
+		transport:         transport,
+		reconnectInterval: reconnectInterval,
+		logger:            logger,
+		cancelCtx:         cancelCtx,
+		cancelFn:          cancelFn,
+		started:           time.Now(),
+		rtpEnc:            rtpEnc,
+		rtpTime:           rtpTime,
+		decodable:         decodableCodecs(),
+		passthroughOnly:   conf.RTPPassthroughOnly,
+		streamErr:         errors.New("not connected yet"),
+		bufAndCBByID:      map[rtppassthrough.SubscriptionID]*subscriber{},
+	}
+	c.activeBackgroundWorkers.Add(1)
+	goutils.ManagedGo(c.run, c.activeBackgroundWorkers.Done)
+	return c, nil
+}
+
+// run streams until the camera is closed, reconnecting after the reconnect interval whenever the
+// connection fails or drops.
+func (c *rtspCamera) run() {
+	// warn once per outage rather than at every attempt
+	warn := true
+	for {
+		err := c.stream()
+		if c.cancelCtx.Err() != nil {
+			return
+		}
+		c.mu.Lock()
+		wasStreaming := c.streamErr == nil
+		c.streamErr = err
+		c.units = nil
+		c.mu.Unlock()
+		if warn || wasStreaming {
+			c.logger.Warnw("rtsp stream disconnected, reconnecting", "address", c.address.String(), "error", err)
+			warn = false
+		} else {
+			c.logger.Debugw("rtsp reconnect failed", "address", c.address.String(), "error", err)
+		}
+		if !goutils.SelectContextOrWait(c.cancelCtx, c.reconnectInterval) {
+			return
+		}
+	}
+}
+
+// stream connects to the server and plays the stream until the connection fails or the camera is
+// closed.
+func (c *rtspCamera) stream() error {
+	client := &gortsplib.Client{
+		Transport: c.transport,
+		OnTransportSwitch: func(err error) {
+			c.logger.Debugw("rtsp transport switched", "address", c.address.String(), "reason", err)
+		},
+		OnPacketLost: func(err error) {
+			c.logger.Debugw("rtsp packets lost", "address", c.address.String(), "error", err)
+		},
+		OnDecodeError: func(err error) {
+			c.logger.Debugw("rtsp decode error", "address", c.address.String(), "error", err)
+		},
+	}
+	if err := client.Start(c.address.Scheme, c.address.Host); err != nil {
+		return err
+	}
+	defer client.Close()
+
+	desc, _, err := client.Describe(c.address)
+	if err != nil {
+		return err
+	}
+	media, forma, vc, err := findVideoCodec(desc)
+	if err != nil {
+		return err
+	}
+	if err := c.checkCodec(vc.codec()); err != nil {
+		return err
+	}
+	if _, err := client.Setup(desc.BaseURL, media, 0, 0); err != nil {
+		return err
+	}
+
+	// offset the stream's presentation times so that passed through timestamps keep increasing
+	// across reconnects
+	offset := time.Since(c.started)
+	c.startStream(vc.codec())
+	client.OnPacketRTP(media, forma, func(pkt *rtp.Packet) {
+		au, err := vc.decode(pkt)
+		if err != nil {
+			c.logger.Debugw("dropping undecodable rtp packet", "error", err)
+			return
+		}
+		if au == nil {
+			return
+		}
+		pts, ok := client.PacketPTS(media, pkt)
+		if !ok {
+			return
+		}
+		au, randomAccess := vc.randomAccess(au)
+		c.handleAccessUnit(vc.codec(), au, randomAccess, offset+pts)
+	})
+	if _, err := client.Play(nil); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	c.activeBackgroundWorkers.Add(1)
+	goutils.ManagedGo(func() {
+		select {
+		case <-c.cancelCtx.Done():
+			client.Close()
+		case <-done:
+		}
+	}, c.activeBackgroundWorkers.Done)
+	return client.Wait()
+}
+
+// checkCodec refuses a stream the camera cannot serve: one it can neither return images from nor,
+// when configured for passthrough only, pass through.
+func (c *rtspCamera) checkCodec(codec Codec) error {
+	if c.passthroughOnly {
+		if codec != CodecH264 {
+			return errors.Errorf("rtp_passthrough_only requires an H264 stream, not %s; "+
+				"only H264 can be passed through to WebRTC", codec)
+		}
+		return nil
+	}
+	if !c.decodable[codec] {
+		return noFrameDecoderError(codec)
+	}
+	return nil
+}
+
+// startStream resets the camera for a new connection. Subscribers wait for its first random
+// access point, and are dropped if the stream is no longer H.264.
+func (c *rtspCamera) startStream(codec Codec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.codec = codec
+	c.streamErr = nil
+	c.group++
+	c.units = nil
+	for id, sub := range c.bufAndCBByID {
+		if codec != CodecH264 {
+			delete(c.bufAndCBByID, id)
+			sub.buf.Close()
+			continue
+		}
+		sub.started = false
+	}
+}
+
+// handleAccessUnit adds the access unit to the current group of pictures, or starts a new group
+// at a random access point, and passes H.264 through to subscribers.
+func (c *rtspCamera) handleAccessUnit(codec Codec, au [][]byte, randomAccess bool, pts time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case c.passthroughOnly:
+		// no images are returned, so there is no need to keep the group
+	case randomAccess:
+		c.group++
+		c.units = [][][]byte{au}
+	case c.units != nil && len(c.units) < maxGroupSize:
+		c.units = append(c.units, au)
+	default:
+		// wait for the next random access point
+		c.units = nil
+	}
+
+	if codec != CodecH264 || len(c.bufAndCBByID) == 0 {
+		return
+	}
+	pkts, err := c.rtpEnc.Encode(au)
+	if err != nil {
+		c.logger.Debugw("failed to packetize access unit for rtp passthrough", "error", err)
+		return
+	}
+	ts := c.rtpTime.Encode(pts)
+	for _, pkt := range pkts {
+		pkt.Timestamp = ts
+	}
+	for _, sub := range c.bufAndCBByID {
+		if !sub.started {
+			if !randomAccess {
+				continue
+			}
+			sub.started = true
+		}
+		cb := sub.cb
+		if err := sub.buf.Publish(func() { cb(pkts) }); err != nil {
+			c.logger.Debugw("rtp passthrough publish failed", "error", err)
+		}
+	}
+}
+
+// Read returns the latest frame. MJPEG frames are returned still encoded, and H.264 and H.265
+// frames are decoded now rather than as they arrive, if a decoder was registered for their codec.
+func (c *rtspCamera) Read(ctx context.Context) (image.Image, func(), error) {
+	c.mu.Lock()
+	codec, group, units, streamErr := c.codec, c.group, c.units, c.streamErr
+	c.mu.Unlock()
+	if streamErr != nil {
+		return nil, nil, errors.Wrap(streamErr, "no frames from rtsp stream")
+	}
+	if c.passthroughOnly {
+		return nil, nil, errors.New("images are unavailable from an rtsp camera configured with rtp_passthrough_only")
+	}
+	if len(units) == 0 {
+		return nil, nil, errors.New("no frames from rtsp stream yet")
+	}
+
+	if codec == CodecMJPEG {
+		return rimage.NewLazyEncodedImage(units[len(units)-1][0], utils.MimeTypeJPEG), func() {}, nil
+	}
+	img, err := c.decoder.latest(codec, group, units)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, func() {}, nil
+}
+
+// SubscribeRTP begins a subscription to receive RTP packets. Only H.264 streams can be passed
+// through, since the robot's WebRTC video tracks are H.264; H.265 streams are refused.
+func (c *rtspCamera) SubscribeRTP(
+	ctx context.Context,
+	bufferSize int,
+	packetsCB rtppassthrough.PacketCallback,
+) (rtppassthrough.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.codec == "" {
+		return rtppassthrough.NilSubscription, errors.Errorf("rtsp stream has not connected: %v", c.streamErr)
+	}
+	if c.codec != CodecH264 {
+		return rtppassthrough.NilSubscription, errors.Errorf("rtp passthrough requires an H264 stream, not %s", c.codec)
+	}
+
+	sub, buf, err := rtppassthrough.NewSubscription(bufferSize)
+	if err != nil {
+		return rtppassthrough.NilSubscription, err
+	}
+	c.bufAndCBByID[sub.ID] = &subscriber{cb: packetsCB, buf: buf}
+	buf.Start()
+	return sub, nil
+}
+
+// Unsubscribe terminates the subscription.
+func (c *rtspCamera) Unsubscribe(ctx context.Context, id rtppassthrough.SubscriptionID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sub, ok := c.bufAndCBByID[id]
+	if !ok {
+		return errors.New("id not found")
+	}
+	delete(c.bufAndCBByID, id)
+	sub.buf.Close()
+	return nil
+}
+
+// Close stops streaming and terminates all subscriptions.
+func (c *rtspCamera) Close(ctx context.Context) error {
+	c.cancelFn()
+	c.activeBackgroundWorkers.Wait()
+	c.mu.Lock()
+	for id, sub := range c.bufAndCBByID {
+		delete(c.bufAndCBByID, id)
+		sub.buf.Close()
+	}
+	c.mu.Unlock()
+	return c.decoder.close()
+}
@@ -78,8 +78,10 @@ type fakeEncoder struct {
 
 	mu         sync.RWMutex
 	position   float64
-	speed      float64 // ticks per minute
-	updateRate int64   // update position in start every updateRate ms
+	speed      float64        // ticks per minute
+	updateRate int64          // update position in start every updateRate ms
+	source     func() float64 // ticks
+	offset     float64        // ticks subtracted from the source
 }
 
 // Position returns the current position in terms of ticks or
@@ -94,6 +96,9 @@ func (e *fakeEncoder) Position(
 	}
 	e.mu.RLock()
 	defer e.mu.RUnlock()
+	if e.source != nil {
+		return math.Floor(e.source()) - e.offset, e.positionType, nil
+	}
 	return e.position, e.positionType, nil
 }
 
@@ -119,6 +124,11 @@ func (e *fakeEncoder) start(cancelCtx context.Context) {
 			}
 
 			e.mu.Lock()
+			if e.source != nil {
+				lastTime = time.Now()
+				e.mu.Unlock()
+				continue
+			}
 			e.position += e.speed / (60. * 1000. / (float64(time.Since(lastTime)) / float64(int(time.Millisecond))))
 			lastTime = time.Now()
 			e.mu.Unlock()
@@ -131,6 +141,10 @@ func (e *fakeEncoder) start(cancelCtx context.Context) {
 func (e *fakeEncoder) ResetPosition(ctx context.Context, extra map[string]interface{}) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.source != nil {
+		e.offset = math.Floor(e.source())
+		return nil
+	}
 	e.position = 0
 	return nil
 }
@@ -148,6 +162,7 @@ type Encoder interface {
 	encoder.Encoder
 	SetSpeed(ctx context.Context, speed float64) error
 	SetPosition(ctx context.Context, position float64) error
+	SetPositionSource(source func() float64)
 }
 
 // SetSpeed sets the speed of the fake motor the encoder is measuring.
@@ -162,6 +177,25 @@ func (e *fakeEncoder) SetSpeed(ctx context.Context, speed float64) error {
 func (e *fakeEncoder) SetPosition(ctx context.Context, position float64) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.source != nil {
+		e.offset = math.Floor(e.source()) - position
+		return nil
+	}
 	e.position = position
 	return nil
 }
+
+// SetPositionSource makes the encoder count the whole ticks the source returns, such as those of a
+// simulated motor, instead of integrating its speed. Resetting or setting the position offsets the
+// count from the source. A nil source goes back to integrating the speed.
+func (e *fakeEncoder) SetPositionSource(source func() float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.source != nil {
+		e.position = math.Floor(e.source()) - e.offset
+	}
+	if source != nil {
+		e.offset = math.Floor(source()) - e.position
+	}
+	e.source = source
+}
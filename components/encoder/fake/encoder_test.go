@@ -97,3 +97,39 @@ func TestEncoder(t *testing.T) {
 		})
 	})
 }
+
+func TestPositionSource(t *testing.T) {
+	ctx := context.Background()
+	eRaw, err := NewEncoder(ctx, resource.Config{Name: "enc1", ConvertedAttributes: &Config{}}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	e := eRaw.(Encoder)
+	test.That(t, e.SetPosition(ctx, 5), test.ShouldBeNil)
+
+	// the count continues from where it was, in whole ticks
+	ticks := 100.0
+	e.SetPositionSource(func() float64 { return ticks })
+	pos, _, err := e.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 5)
+	ticks = 110.7
+	pos, _, err = e.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 15)
+
+	test.That(t, e.ResetPosition(ctx, nil), test.ShouldBeNil)
+	pos, _, err = e.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 0)
+	test.That(t, e.SetPosition(ctx, -3), test.ShouldBeNil)
+	ticks = 112
+	pos, _, err = e.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, -1)
+
+	// without a source it holds its count
+	e.SetPositionSource(nil)
+	ticks = 200
+	pos, _, err = e.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, -1)
+}
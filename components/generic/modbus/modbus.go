@@ -0,0 +1,264 @@
+// Package modbus implements a generic Modbus RTU or TCP actuator, which writes named setpoints to
+// the holding registers of the device through DoCommand.
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/modbusutils"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("modbus")
+
+// Command is the key for the command in DoCommand requests.
+const Command = "command"
+
+// The commands supported by DoCommand.
+const (
+	// Set writes the setpoints in "values", a map from setpoint names to values.
+	Set = "set"
+	// Get reads the setpoints in "names", or all of them if no names are given.
+	Get = "get"
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	Connection modbusutils.ConnectionConfig `json:"connection"`
+	Setpoints  []modbusutils.RegisterConfig `json:"setpoints"`
+	// StopValues are written to their setpoints when the actuator is stopped or closed, such as a
+	// zero speed for a drive.
+	StopValues map[string]float64 `json:"stop_values,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if err := conf.Connection.Validate(path + ".connection"); err != nil {
+		return nil, err
+	}
+	if len(conf.Setpoints) == 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "setpoints")
+	}
+	if err := modbusutils.ValidateRegisters(path, conf.Setpoints); err != nil {
+		return nil, err
+	}
+	setpoints := map[string]modbusutils.RegisterConfig{}
+	for i, setpoint := range conf.Setpoints {
+		if setpoint.Table != "" && setpoint.Table != modbusutils.TableHolding {
+			return nil, resource.NewConfigValidationError(fmt.Sprintf("%s.setpoints.%d", path, i),
+				errors.Errorf("setpoint %q must be a holding register", setpoint.Name))
+		}
+		setpoints[setpoint.Name] = setpoint
+	}
+	for name, value := range conf.StopValues {
+		setpoint, ok := setpoints[name]
+		if !ok {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("stop value for unknown setpoint %q", name))
+		}
+		if _, err := setpoint.Encode(value); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+	}
+	return nil, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		generic.API,
+		model,
+		resource.Registration[resource.Resource, *Config]{
+			Constructor: func(
+				ctx context.Context,
+				deps resource.Dependencies,
+				conf resource.Config,
+				logger logging.Logger,
+			) (resource.Resource, error) {
+				newConf, err := resource.NativeConfig[*Config](conf)
+				if err != nil {
+					return nil, err
+				}
+				client, err := modbusutils.NewClient(newConf.Connection)
+				if err != nil {
+					return nil, err
+				}
+				return newActuator(conf.ResourceName(), newConf, client, logger), nil
+			},
+		})
+}
+
+// Actuator is a Modbus device controlled by writing setpoints to its holding registers.
+type Actuator struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger logging.Logger
+
+	client     *modbusutils.Client
+	setpoints  []modbusutils.RegisterConfig
+	stopValues map[string]float64
+	opMgr      *operation.SingleOperationManager
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+func newActuator(name resource.Name, conf *Config, client *modbusutils.Client, logger logging.Logger) *Actuator {
+	return &Actuator{
+		Named:      name.AsNamed(),
+		logger:     logger,
+		client:     client,
+		setpoints:  conf.Setpoints,
+		stopValues: conf.StopValues,
+		opMgr:      operation.NewSingleOperationManager(),
+		stopped:    true,
+	}
+}
+
+// DoCommand writes or reads setpoints.
+func (a *Actuator) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	switch cmd[Command] {
+	case Set:
+		values, ok := cmd["values"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New(`"values" must be a map of setpoint names to values`)
+		}
+		if err := a.set(ctx, values); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{}, nil
+	case Get:
+		var names []string
+		if rawNames, ok := cmd["names"].([]interface{}); ok {
+			for _, rawName := range rawNames {
+				name, ok := rawName.(string)
+				if !ok {
+					return nil, errors.Errorf("setpoint name %v is not a string", rawName)
+				}
+				names = append(names, name)
+			}
+		}
+		return a.get(names)
+	default:
+		return nil, errors.Errorf("unknown command %v, expected %q or %q", cmd[Command], Set, Get)
+	}
+}
+
+// set writes setpoints in the order they are configured, after checking every value can be
+// written, so that an invalid request writes nothing.
+func (a *Actuator) set(ctx context.Context, values map[string]interface{}) error {
+	ctx, done := a.opMgr.New(ctx)
+	defer done()
+
+	type write struct {
+		setpoint modbusutils.RegisterConfig
+		value    float64
+	}
+	for name := range values {
+		if !a.hasSetpoint(name) {
+			return errors.Errorf("unknown setpoint %q", name)
+		}
+	}
+	var writes []write
+	for _, setpoint := range a.setpoints {
+		raw, ok := values[setpoint.Name]
+		if !ok {
+			continue
+		}
+		value, ok := raw.(float64)
+		if !ok {
+			return errors.Errorf("value %v of setpoint %q is not a number", raw, setpoint.Name)
+		}
+		if _, err := setpoint.Encode(value); err != nil {
+			return err
+		}
+		writes = append(writes, write{setpoint, value})
+	}
+
+	a.mu.Lock()
+	a.stopped = false
+	a.mu.Unlock()
+	for _, w := range writes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := a.client.Write(w.setpoint, w.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Actuator) hasSetpoint(name string) bool {
+	for _, setpoint := range a.setpoints {
+		if setpoint.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Actuator) get(names []string) (map[string]interface{}, error) {
+	setpoints := a.setpoints
+	if len(names) > 0 {
+		setpoints = nil
+		for _, name := range names {
+			found := false
+			for _, setpoint := range a.setpoints {
+				if setpoint.Name == name {
+					setpoints = append(setpoints, setpoint)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, errors.Errorf("unknown setpoint %q", name)
+			}
+		}
+	}
+	values, err := a.client.Read(modbusutils.PlanReads(setpoints))
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	for name, value := range values {
+		result[name] = value
+	}
+	return result, nil
+}
+
+// Stop cancels any write in progress and writes the configured stop values.
+func (a *Actuator) Stop(ctx context.Context, extra map[string]interface{}) error {
+	a.opMgr.CancelRunning(ctx)
+	var err error
+	for _, setpoint := range a.setpoints {
+		if value, ok := a.stopValues[setpoint.Name]; ok {
+			err = multierr.Combine(err, a.client.Write(setpoint, value))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.stopped = true
+	a.mu.Unlock()
+	return nil
+}
+
+// IsMoving returns whether setpoints were written since the actuator was last stopped.
+func (a *Actuator) IsMoving(ctx context.Context) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return !a.stopped, nil
+}
+
+// Close stops the actuator and closes the connection to the device.
+func (a *Actuator) Close(ctx context.Context) error {
+	return multierr.Combine(a.Stop(ctx, nil), a.client.Close())
+}
@@ -0,0 +1,124 @@
+package modbus
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/modbusutils"
+	mbtestutils "go.viam.com/rdk/components/modbusutils/testutils"
+	"go.viam.com/rdk/logging"
+)
+
+func testConfig(address string) *Config {
+	return &Config{
+		Connection: modbusutils.ConnectionConfig{TCPAddress: address},
+		Setpoints: []modbusutils.RegisterConfig{
+			{Name: "run", Address: 0, Type: modbusutils.TypeUint16},
+			{Name: "frequency", Address: 1, Scale: 0.01},
+			{Name: "ramp_time", Address: 10, Type: modbusutils.TypeFloat32, WordOrder: modbusutils.WordOrderLowFirst},
+		},
+		StopValues: map[string]float64{"run": 0, "frequency": 0},
+	}
+}
+
+func newTestActuator(t *testing.T) (*Actuator, *mbtestutils.Server) {
+	t.Helper()
+	server, err := mbtestutils.NewServer("127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	})
+	server.SetHoldingRegisters(0, 0, 0)
+	server.SetHoldingRegisters(10, 0, 0)
+
+	conf := testConfig(server.Address())
+	client, err := modbusutils.NewClient(conf.Connection)
+	test.That(t, err, test.ShouldBeNil)
+	return newActuator(generic.Named("vfd"), conf, client, logging.NewTestLogger(t)), server
+}
+
+func TestValidate(t *testing.T) {
+	conf := testConfig("127.0.0.1:502")
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, bad := range []func(conf *Config){
+		func(conf *Config) { conf.Connection = modbusutils.ConnectionConfig{} },
+		func(conf *Config) { conf.Setpoints = nil },
+		func(conf *Config) { conf.Setpoints[0].Table = modbusutils.TableInput },
+		func(conf *Config) { conf.StopValues["speed"] = 0 },
+		func(conf *Config) { conf.StopValues["run"] = -1 },
+	} {
+		conf := testConfig("127.0.0.1:502")
+		bad(conf)
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestSetpoints(t *testing.T) {
+	ctx := context.Background()
+	a, server := newTestActuator(t)
+	defer func() {
+		test.That(t, a.Close(ctx), test.ShouldBeNil)
+	}()
+
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	_, err = a.DoCommand(ctx, map[string]interface{}{
+		Command:  Set,
+		"values": map[string]interface{}{"run": 1.0, "frequency": 42.5, "ramp_time": 2.5},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, server.HoldingRegisters(0, 2), test.ShouldResemble, []uint16{1, 4250})
+	test.That(t, server.HoldingRegisters(10, 2), test.ShouldResemble, []uint16{0x0000, 0x4020})
+	moving, err = a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+
+	resp, err := a.DoCommand(ctx, map[string]interface{}{Command: Get})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{"run": 1.0, "frequency": 42.5, "ramp_time": 2.5})
+	resp, err = a.DoCommand(ctx, map[string]interface{}{Command: Get, "names": []interface{}{"frequency"}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{"frequency": 42.5})
+
+	// invalid requests write nothing
+	for _, values := range []map[string]interface{}{
+		{"frequency": 10.0, "speed": 1.0},
+		{"frequency": 10.0, "run": "yes"},
+		{"frequency": 10.0, "run": -1.0},
+	} {
+		_, err = a.DoCommand(ctx, map[string]interface{}{Command: Set, "values": values})
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+	test.That(t, server.HoldingRegisters(0, 2), test.ShouldResemble, []uint16{1, 4250})
+	_, err = a.DoCommand(ctx, map[string]interface{}{Command: Get, "names": []interface{}{"speed"}})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = a.DoCommand(ctx, map[string]interface{}{Command: "jog"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// stopping writes the stop values and leaves the others alone
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, server.HoldingRegisters(0, 2), test.ShouldResemble, []uint16{0, 0})
+	test.That(t, server.HoldingRegisters(10, 2), test.ShouldResemble, []uint16{0x0000, 0x4020})
+	moving, err = a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
+func TestCloseStops(t *testing.T) {
+	ctx := context.Background()
+	a, server := newTestActuator(t)
+
+	_, err := a.DoCommand(ctx, map[string]interface{}{Command: Set, "values": map[string]interface{}{"run": 1.0}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, server.HoldingRegisters(0, 1), test.ShouldResemble, []uint16{1})
+	test.That(t, a.Close(ctx), test.ShouldBeNil)
+	test.That(t, server.HoldingRegisters(0, 1), test.ShouldResemble, []uint16{0})
+}
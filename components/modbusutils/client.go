@@ -0,0 +1,185 @@
+package modbusutils
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/pkg/errors"
+)
+
+// maxReadWords is the most registers a single Modbus read request may return.
+const maxReadWords = 125
+
+// A ReadBlock is a run of consecutive registers in one table read with a single request, and the
+// values decoded from them.
+type ReadBlock struct {
+	Table     string
+	Address   int
+	Count     int
+	Registers []RegisterConfig
+}
+
+// PlanReads groups registers into as few reads as possible. Only registers that are adjacent or
+// overlap share a read, since devices often reject reads of the unmapped addresses between them.
+func PlanReads(registers []RegisterConfig) []ReadBlock {
+	sorted := append([]RegisterConfig(nil), registers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].table() != sorted[j].table() {
+			return sorted[i].table() < sorted[j].table()
+		}
+		return sorted[i].Address < sorted[j].Address
+	})
+
+	var blocks []ReadBlock
+	for _, reg := range sorted {
+		if n := len(blocks); n > 0 {
+			last := &blocks[n-1]
+			end := max(last.Address+last.Count, reg.Address+reg.Words())
+			if last.Table == reg.table() && reg.Address <= last.Address+last.Count && end-last.Address <= maxReadWords {
+				last.Count = end - last.Address
+				last.Registers = append(last.Registers, reg)
+				continue
+			}
+		}
+		blocks = append(blocks, ReadBlock{
+			Table:     reg.table(),
+			Address:   reg.Address,
+			Count:     reg.Words(),
+			Registers: []RegisterConfig{reg},
+		})
+	}
+	return blocks
+}
+
+// handler is the part of the goburrow handlers needed to manage the connection.
+type handler interface {
+	modbus.ClientHandler
+	Connect() error
+	Close() error
+}
+
+// Client is a connection to a Modbus device. It is safe for concurrent use, sending one request
+// at a time.
+type Client struct {
+	mu      sync.Mutex
+	handler handler
+	client  modbus.Client
+}
+
+// NewClient connects to the Modbus device described by the config.
+func NewClient(conf ConnectionConfig) (*Client, error) {
+	modbusID := byte(defaultModbusID)
+	if conf.ModbusID != 0 {
+		modbusID = byte(conf.ModbusID)
+	}
+	timeout := time.Duration(defaultTimeoutMs) * time.Millisecond
+	if conf.TimeoutMs != 0 {
+		timeout = time.Duration(conf.TimeoutMs) * time.Millisecond
+	}
+
+	var h handler
+	if conf.TCPAddress != "" {
+		tcpHandler := modbus.NewTCPClientHandler(conf.TCPAddress)
+		tcpHandler.SlaveId = modbusID
+		tcpHandler.Timeout = timeout
+		h = tcpHandler
+	} else {
+		rtuHandler := modbus.NewRTUClientHandler(conf.SerialPath)
+		rtuHandler.BaudRate = defaultBaudRate
+		if conf.SerialBaudRate != 0 {
+			rtuHandler.BaudRate = conf.SerialBaudRate
+		}
+		rtuHandler.DataBits = 8
+		rtuHandler.Parity = "N"
+		if conf.SerialParity != "" {
+			rtuHandler.Parity = conf.SerialParity
+		}
+		rtuHandler.StopBits = 1
+		if conf.SerialStopBits != 0 {
+			rtuHandler.StopBits = conf.SerialStopBits
+		}
+		rtuHandler.SlaveId = modbusID
+		rtuHandler.Timeout = timeout
+		h = rtuHandler
+	}
+	if err := h.Connect(); err != nil {
+		return nil, errors.Wrap(err, "failed to connect to modbus device")
+	}
+	return &Client{handler: h, client: modbus.NewClient(h)}, nil
+}
+
+// ReadRegisters reads count consecutive registers from the holding or input table.
+func (c *Client) ReadRegisters(table string, address, count int) ([]uint16, error) {
+	c.mu.Lock()
+	var results []byte
+	var err error
+	if table == TableInput {
+		results, err = c.client.ReadInputRegisters(uint16(address), uint16(count))
+	} else {
+		results, err = c.client.ReadHoldingRegisters(uint16(address), uint16(count))
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %d %s registers at %d", count, table, address)
+	}
+	if len(results) != 2*count {
+		return nil, errors.Errorf("read %d bytes of %s registers at %d, expected %d", len(results), table, address, 2*count)
+	}
+	words := make([]uint16, count)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint16(results[2*i:])
+	}
+	return words, nil
+}
+
+// WriteRegisters writes consecutive holding registers.
+func (c *Client) WriteRegisters(address int, words []uint16) error {
+	c.mu.Lock()
+	var err error
+	if len(words) == 1 {
+		_, err = c.client.WriteSingleRegister(uint16(address), words[0])
+	} else {
+		value := make([]byte, 2*len(words))
+		for i, word := range words {
+			binary.BigEndian.PutUint16(value[2*i:], word)
+		}
+		_, err = c.client.WriteMultipleRegisters(uint16(address), uint16(len(words)), value)
+	}
+	c.mu.Unlock()
+	return errors.Wrapf(err, "failed to write %d holding registers at %d", len(words), address)
+}
+
+// Read reads and decodes the registers of each block, returning the values by register name.
+func (c *Client) Read(blocks []ReadBlock) (map[string]float64, error) {
+	values := map[string]float64{}
+	for _, block := range blocks {
+		words, err := c.ReadRegisters(block.Table, block.Address, block.Count)
+		if err != nil {
+			return nil, err
+		}
+		for _, reg := range block.Registers {
+			start := reg.Address - block.Address
+			values[reg.Name] = reg.Decode(words[start : start+reg.Words()])
+		}
+	}
+	return values, nil
+}
+
+// Write encodes a value and writes it to its registers.
+func (c *Client) Write(reg RegisterConfig, value float64) error {
+	words, err := reg.Encode(value)
+	if err != nil {
+		return err
+	}
+	return c.WriteRegisters(reg.Address, words)
+}
+
+// Close closes the connection to the device.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.handler.Close()
+}
@@ -0,0 +1,58 @@
+package modbusutils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goburrow/modbus"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+
+	mbtestutils "go.viam.com/rdk/components/modbusutils/testutils"
+)
+
+func newTestClient(t *testing.T) (*Client, *mbtestutils.Server) {
+	t.Helper()
+	server, err := mbtestutils.NewServer("127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	})
+	client, err := NewClient(ConnectionConfig{TCPAddress: server.Address()})
+	test.That(t, err, test.ShouldBeNil)
+	return client, server
+}
+
+func TestClient(t *testing.T) {
+	client, server := newTestClient(t)
+	defer utils.UncheckedErrorFunc(client.Close)
+
+	server.SetHoldingRegisters(100, 235, 0x4049, 0x0FDB)
+	server.SetInputRegisters(7, 0xFFFF)
+
+	registers := []RegisterConfig{
+		{Name: "temperature", Address: 100, Scale: 0.1},
+		{Name: "pi", Address: 101, Type: TypeFloat32},
+		{Name: "counts", Table: TableInput, Address: 7, Type: TypeUint16},
+	}
+	values, err := client.Read(PlanReads(registers))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, values["temperature"], test.ShouldAlmostEqual, 23.5)
+	test.That(t, values["pi"], test.ShouldAlmostEqual, 3.14159, 1e-5)
+	test.That(t, values["counts"], test.ShouldEqual, 65535)
+	// one read per table
+	test.That(t, server.Requests(), test.ShouldEqual, 2)
+
+	test.That(t, client.Write(registers[0], -12.3), test.ShouldBeNil)
+	test.That(t, client.Write(registers[1], 1.5), test.ShouldBeNil)
+	test.That(t, server.HoldingRegisters(100, 3), test.ShouldResemble, []uint16{0xFF85, 0x3FC0, 0x0000})
+	test.That(t, client.Write(registers[0], 1e6), test.ShouldNotBeNil)
+
+	// unmapped registers are reported by the device
+	_, err = client.ReadRegisters(TableHolding, 103, 1)
+	var modbusErr *modbus.ModbusError
+	test.That(t, errors.As(err, &modbusErr), test.ShouldBeTrue)
+	test.That(t, modbusErr.ExceptionCode, test.ShouldEqual, modbus.ExceptionCodeIllegalDataAddress)
+	test.That(t, client.WriteRegisters(99, []uint16{1, 2}), test.ShouldNotBeNil)
+	test.That(t, server.HoldingRegisters(100, 1), test.ShouldResemble, []uint16{0xFF85})
+}
@@ -0,0 +1,243 @@
+// Package modbusutils contains the connection and register handling shared by the generic Modbus
+// models, which map the registers of a Modbus RTU or TCP device to named values.
+package modbusutils
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	defaultBaudRate  = 9600
+	defaultModbusID  = 1
+	defaultTimeoutMs = 1000
+)
+
+// ConnectionConfig describes how to reach a Modbus device: over TCP when TCPAddress is set, or
+// over a serial line (Modbus RTU) when SerialPath is set.
+type ConnectionConfig struct {
+	TCPAddress     string `json:"tcp_address,omitempty"`
+	SerialPath     string `json:"serial_path,omitempty"`
+	SerialBaudRate int    `json:"serial_baud_rate,omitempty"`
+	// SerialParity is "N" (the default), "E" or "O".
+	SerialParity   string `json:"serial_parity,omitempty"`
+	SerialStopBits int    `json:"serial_stop_bits,omitempty"`
+	ModbusID       int    `json:"modbus_id,omitempty"`
+	TimeoutMs      int    `json:"timeout_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *ConnectionConfig) Validate(path string) error {
+	switch {
+	case conf.TCPAddress == "" && conf.SerialPath == "":
+		return resource.NewConfigValidationError(path, errors.New("one of tcp_address or serial_path is required"))
+	case conf.TCPAddress != "" && conf.SerialPath != "":
+		return resource.NewConfigValidationError(path, errors.New("only one of tcp_address or serial_path may be set"))
+	}
+	switch conf.SerialParity {
+	case "", "N", "E", "O":
+	default:
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("serial_parity must be one of N, E or O, not %q", conf.SerialParity))
+	}
+	if conf.SerialStopBits != 0 && conf.SerialStopBits != 1 && conf.SerialStopBits != 2 {
+		return resource.NewConfigValidationError(path, errors.New("serial_stop_bits must be 1 or 2"))
+	}
+	if conf.SerialBaudRate < 0 {
+		return resource.NewConfigValidationError(path, errors.New("serial_baud_rate cannot be negative"))
+	}
+	if conf.ModbusID < 0 || conf.ModbusID > 255 {
+		return resource.NewConfigValidationError(path, errors.New("modbus_id must be between 0 and 255"))
+	}
+	if conf.TimeoutMs < 0 {
+		return resource.NewConfigValidationError(path, errors.New("timeout_ms cannot be negative"))
+	}
+	return nil
+}
+
+// The tables a register may be read from.
+const (
+	TableHolding = "holding"
+	TableInput   = "input"
+)
+
+// The types a register may hold. 32 bit types span two consecutive registers.
+const (
+	TypeInt16   = "int16"
+	TypeUint16  = "uint16"
+	TypeInt32   = "int32"
+	TypeUint32  = "uint32"
+	TypeFloat32 = "float32"
+)
+
+// The orders the two registers of a 32 bit value may be in. Within each register, bytes are always
+// big endian as the Modbus specification requires.
+const (
+	WordOrderHighFirst = "high_word_first"
+	WordOrderLowFirst  = "low_word_first"
+)
+
+// RegisterConfig maps one or two registers of a device to a named value. The value is the raw
+// register value multiplied by Scale, then added to Offset.
+type RegisterConfig struct {
+	Name string `json:"name"`
+	// Table is "holding" (the default) or "input".
+	Table   string `json:"table,omitempty"`
+	Address int    `json:"address"`
+	// Type is one of int16 (the default), uint16, int32, uint32 or float32.
+	Type string `json:"type,omitempty"`
+	// WordOrder is "high_word_first" (the default) or "low_word_first".
+	WordOrder string  `json:"word_order,omitempty"`
+	Scale     float64 `json:"scale,omitempty"`
+	Offset    float64 `json:"offset,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *RegisterConfig) Validate(path string) error {
+	if conf.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	switch conf.Table {
+	case "", TableHolding, TableInput:
+	default:
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("register %q table must be %q or %q, not %q", conf.Name, TableHolding, TableInput, conf.Table))
+	}
+	switch conf.Type {
+	case "", TypeInt16, TypeUint16, TypeInt32, TypeUint32, TypeFloat32:
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf("register %q has unknown type %q", conf.Name, conf.Type))
+	}
+	switch conf.WordOrder {
+	case "", WordOrderHighFirst, WordOrderLowFirst:
+	default:
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("register %q word_order must be %q or %q, not %q",
+				conf.Name, WordOrderHighFirst, WordOrderLowFirst, conf.WordOrder))
+	}
+	if conf.Address < 0 || conf.Address+conf.Words() > math.MaxUint16+1 {
+		return resource.NewConfigValidationError(path, errors.Errorf("register %q address %d is out of range", conf.Name, conf.Address))
+	}
+	return nil
+}
+
+// ValidateRegisters validates each register and ensures their names are unique.
+func ValidateRegisters(path string, registers []RegisterConfig) error {
+	names := map[string]bool{}
+	for i, reg := range registers {
+		if err := reg.Validate(fmt.Sprintf("%s.registers.%d", path, i)); err != nil {
+			return err
+		}
+		if names[reg.Name] {
+			return resource.NewConfigValidationError(path, errors.Errorf("register name %q is used more than once", reg.Name))
+		}
+		names[reg.Name] = true
+	}
+	return nil
+}
+
+func (conf *RegisterConfig) table() string {
+	if conf.Table == "" {
+		return TableHolding
+	}
+	return conf.Table
+}
+
+func (conf *RegisterConfig) scale() float64 {
+	if conf.Scale == 0 {
+		return 1
+	}
+	return conf.Scale
+}
+
+// Words returns the number of registers the value spans.
+func (conf *RegisterConfig) Words() int {
+	switch conf.Type {
+	case TypeInt32, TypeUint32, TypeFloat32:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Decode converts the registers holding the value to the scaled value.
+func (conf *RegisterConfig) Decode(words []uint16) float64 {
+	var raw float64
+	if conf.Words() == 1 {
+		if conf.Type == TypeUint16 {
+			raw = float64(words[0])
+		} else {
+			raw = float64(int16(words[0]))
+		}
+	} else {
+		bits := uint32(words[0])<<16 | uint32(words[1])
+		if conf.WordOrder == WordOrderLowFirst {
+			bits = uint32(words[1])<<16 | uint32(words[0])
+		}
+		switch conf.Type {
+		case TypeInt32:
+			raw = float64(int32(bits))
+		case TypeUint32:
+			raw = float64(bits)
+		default:
+			raw = float64(math.Float32frombits(bits))
+		}
+	}
+	return raw*conf.scale() + conf.Offset
+}
+
+// Encode converts a scaled value to the registers holding it, returning an error if the value does
+// not fit in the register's type.
+func (conf *RegisterConfig) Encode(value float64) ([]uint16, error) {
+	raw := (value - conf.Offset) / conf.scale()
+	var bits uint32
+	switch conf.Type {
+	case TypeFloat32:
+		if math.IsNaN(raw) || math.Abs(raw) > math.MaxFloat32 {
+			return nil, errors.Errorf("value %v does not fit in register %q of type %s", value, conf.Name, TypeFloat32)
+		}
+		bits = math.Float32bits(float32(raw))
+	default:
+		raw = math.Round(raw)
+		lower, upper := conf.integerRange()
+		if math.IsNaN(raw) || raw < lower || raw > upper {
+			return nil, errors.Errorf("value %v does not fit in register %q of type %s", value, conf.Name, conf.typeName())
+		}
+		if raw < 0 {
+			bits = uint32(int32(raw))
+		} else {
+			bits = uint32(raw)
+		}
+	}
+	if conf.Words() == 1 {
+		return []uint16{uint16(bits)}, nil
+	}
+	if conf.WordOrder == WordOrderLowFirst {
+		return []uint16{uint16(bits), uint16(bits >> 16)}, nil
+	}
+	return []uint16{uint16(bits >> 16), uint16(bits)}, nil
+}
+
+func (conf *RegisterConfig) integerRange() (float64, float64) {
+	switch conf.Type {
+	case TypeUint16:
+		return 0, math.MaxUint16
+	case TypeInt32:
+		return math.MinInt32, math.MaxInt32
+	case TypeUint32:
+		return 0, math.MaxUint32
+	default:
+		return math.MinInt16, math.MaxInt16
+	}
+}
+
+func (conf *RegisterConfig) typeName() string {
+	if conf.Type == "" {
+		return TypeInt16
+	}
+	return conf.Type
+}
@@ -0,0 +1,122 @@
+package modbusutils
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestValidateConnection(t *testing.T) {
+	for _, conf := range []ConnectionConfig{
+		{TCPAddress: "10.0.0.5:502"},
+		{SerialPath: "/dev/ttyUSB0", SerialBaudRate: 19200, SerialParity: "E", SerialStopBits: 1, ModbusID: 247},
+	} {
+		test.That(t, conf.Validate("path"), test.ShouldBeNil)
+	}
+
+	for _, conf := range []ConnectionConfig{
+		{},
+		{TCPAddress: "10.0.0.5:502", SerialPath: "/dev/ttyUSB0"},
+		{SerialPath: "/dev/ttyUSB0", SerialParity: "X"},
+		{SerialPath: "/dev/ttyUSB0", SerialStopBits: 3},
+		{TCPAddress: "10.0.0.5:502", ModbusID: 256},
+		{TCPAddress: "10.0.0.5:502", TimeoutMs: -1},
+	} {
+		test.That(t, conf.Validate("path"), test.ShouldNotBeNil)
+	}
+}
+
+func TestValidateRegisters(t *testing.T) {
+	good := []RegisterConfig{
+		{Name: "a", Address: 0},
+		{Name: "b", Table: TableInput, Address: 65534, Type: TypeFloat32, WordOrder: WordOrderLowFirst},
+	}
+	test.That(t, ValidateRegisters("path", good), test.ShouldBeNil)
+
+	for _, registers := range [][]RegisterConfig{
+		{{Address: 1}},
+		{{Name: "a", Table: "coil"}},
+		{{Name: "a", Type: "int64"}},
+		{{Name: "a", WordOrder: "big"}},
+		{{Name: "a", Address: -1}},
+		{{Name: "a", Address: 65535, Type: TypeUint32}},
+		{{Name: "a", Address: 1}, {Name: "a", Address: 2}},
+	} {
+		test.That(t, ValidateRegisters("path", registers), test.ShouldNotBeNil)
+	}
+}
+
+func TestDecodeEncode(t *testing.T) {
+	for _, tc := range []struct {
+		reg   RegisterConfig
+		words []uint16
+		value float64
+	}{
+		{RegisterConfig{}, []uint16{0xFFFE}, -2},
+		{RegisterConfig{Type: TypeUint16}, []uint16{0xFFFE}, 65534},
+		{RegisterConfig{Scale: 0.1}, []uint16{235}, 23.5},
+		{RegisterConfig{Scale: 0.5, Offset: -40}, []uint16{100}, 10},
+		{RegisterConfig{Type: TypeInt32}, []uint16{0xFFFF, 0xFFFF}, -1},
+		{RegisterConfig{Type: TypeUint32}, []uint16{0x0001, 0x0002}, 65538},
+		{RegisterConfig{Type: TypeUint32, WordOrder: WordOrderLowFirst}, []uint16{0x0002, 0x0001}, 65538},
+		{RegisterConfig{Type: TypeFloat32}, []uint16{0x4049, 0x0FDB}, float64(float32(math.Pi))},
+		{RegisterConfig{Type: TypeFloat32, WordOrder: WordOrderLowFirst}, []uint16{0x0000, 0xC120}, -10},
+	} {
+		test.That(t, tc.reg.Decode(tc.words), test.ShouldAlmostEqual, tc.value, 1e-9)
+		words, err := tc.reg.Encode(tc.value)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, words, test.ShouldResemble, tc.words)
+	}
+
+	for _, tc := range []struct {
+		reg   RegisterConfig
+		value float64
+	}{
+		{RegisterConfig{}, 40000},
+		{RegisterConfig{Type: TypeUint16}, -1},
+		{RegisterConfig{Type: TypeUint32, Scale: 0.001}, 5e6},
+		{RegisterConfig{Type: TypeFloat32}, math.NaN()},
+	} {
+		_, err := tc.reg.Encode(tc.value)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestPlanReads(t *testing.T) {
+	blocks := PlanReads([]RegisterConfig{
+		{Name: "d", Address: 10, Type: TypeFloat32},
+		{Name: "a", Address: 0},
+		{Name: "b", Address: 1, Type: TypeUint32},
+		{Name: "e", Table: TableInput, Address: 3},
+		{Name: "c", Address: 3},
+		{Name: "f", Address: 200},
+		{Name: "g", Address: 320},
+	})
+	var got [][3]interface{}
+	for _, block := range blocks {
+		var names []string
+		for _, reg := range block.Registers {
+			names = append(names, reg.Name)
+		}
+		got = append(got, [3]interface{}{block.Table, [2]int{block.Address, block.Count}, names})
+	}
+	test.That(t, got, test.ShouldResemble, [][3]interface{}{
+		{TableHolding, [2]int{0, 4}, []string{"a", "b", "c"}},
+		{TableHolding, [2]int{10, 2}, []string{"d"}},
+		{TableHolding, [2]int{200, 1}, []string{"f"}},
+		{TableHolding, [2]int{320, 1}, []string{"g"}},
+		{TableInput, [2]int{3, 1}, []string{"e"}},
+	})
+
+	// reads are limited to 125 registers
+	var registers []RegisterConfig
+	for i := 0; i < 100; i++ {
+		registers = append(registers, RegisterConfig{Name: string(rune('a' + i)), Address: 2 * i, Type: TypeInt32})
+	}
+	blocks = PlanReads(registers)
+	test.That(t, blocks, test.ShouldHaveLength, 2)
+	test.That(t, blocks[0].Count, test.ShouldEqual, 124)
+	test.That(t, blocks[1].Address, test.ShouldEqual, 124)
+	test.That(t, blocks[1].Count, test.ShouldEqual, 76)
+}
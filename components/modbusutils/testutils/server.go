@@ -0,0 +1,235 @@
+// Package testutils provides a Modbus TCP server to test Modbus models against.
+package testutils
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	goutils "go.viam.com/utils"
+)
+
+// The Modbus function and exception codes the Server handles.
+const (
+	funcReadHoldingRegisters   = 0x03
+	funcReadInputRegisters     = 0x04
+	funcWriteSingleRegister    = 0x06
+	funcWriteMultipleRegisters = 0x10
+
+	exceptionIllegalFunction    = 0x01
+	exceptionIllegalDataAddress = 0x02
+	exceptionIllegalDataValue   = 0x03
+
+	// maxReadWords is the most registers a single Modbus read request may return.
+	maxReadWords = 125
+)
+
+// Server is a minimal in-memory Modbus TCP server, used to test Modbus models without a device. It
+// serves reads of holding and input registers and writes of holding registers. Registers that were
+// never set do not exist, and accessing them returns an illegal data address exception, as most
+// devices do for unmapped addresses.
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	closed   bool
+	conns    map[net.Conn]struct{}
+	holding  map[uint16]uint16
+	input    map[uint16]uint16
+	requests int
+
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+// NewServer starts a Modbus TCP server listening on the given address, such as "127.0.0.1:0".
+func NewServer(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: listener,
+		conns:    map[net.Conn]struct{}{},
+		holding:  map[uint16]uint16{},
+		input:    map[uint16]uint16{},
+	}
+	s.activeBackgroundWorkers.Add(1)
+	goutils.ManagedGo(s.accept, s.activeBackgroundWorkers.Done)
+	return s, nil
+}
+
+// Address returns the address the server is listening on.
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// SetHoldingRegisters sets consecutive holding registers, creating them if needed.
+func (s *Server) SetHoldingRegisters(address int, words ...uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, word := range words {
+		s.holding[uint16(address+i)] = word
+	}
+}
+
+// SetInputRegisters sets consecutive input registers, creating them if needed.
+func (s *Server) SetInputRegisters(address int, words ...uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, word := range words {
+		s.input[uint16(address+i)] = word
+	}
+}
+
+// HoldingRegisters returns the values of consecutive holding registers.
+func (s *Server) HoldingRegisters(address, count int) []uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	words := make([]uint16, count)
+	for i := range words {
+		words[i] = s.holding[uint16(address+i)]
+	}
+	return words
+}
+
+// Requests returns the number of requests the server has handled.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		goutils.UncheckedError(conn.Close())
+	}
+	s.mu.Unlock()
+	s.activeBackgroundWorkers.Wait()
+	return err
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			goutils.UncheckedError(conn.Close())
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.activeBackgroundWorkers.Add(1)
+		goutils.ManagedGo(func() {
+			s.serve(conn)
+		}, s.activeBackgroundWorkers.Done)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		goutils.UncheckedError(conn.Close())
+	}()
+	// Each frame starts with a 7 byte MBAP header: a transaction ID, a protocol ID, the length of
+	// the rest of the frame, and a unit ID.
+	header := make([]byte, 7)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		length := int(binary.BigEndian.Uint16(header[4:]))
+		if length < 2 {
+			return
+		}
+		pdu := make([]byte, length-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+		response := s.handle(pdu)
+		frame := make([]byte, 7+len(response))
+		copy(frame, header)
+		binary.BigEndian.PutUint16(frame[4:], uint16(1+len(response)))
+		copy(frame[7:], response)
+		if _, err := conn.Write(frame); err != nil {
+			return
+		}
+	}
+}
+
+// handle applies a request PDU to the registers and returns the response PDU.
+func (s *Server) handle(pdu []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	function := pdu[0]
+	exception := func(code byte) []byte {
+		return []byte{function | 0x80, code}
+	}
+	data := pdu[1:]
+	switch function {
+	case funcReadHoldingRegisters, funcReadInputRegisters:
+		if len(data) != 4 {
+			return exception(exceptionIllegalDataValue)
+		}
+		table := s.holding
+		if function == funcReadInputRegisters {
+			table = s.input
+		}
+		address := binary.BigEndian.Uint16(data)
+		count := int(binary.BigEndian.Uint16(data[2:]))
+		if count < 1 || count > maxReadWords {
+			return exception(exceptionIllegalDataValue)
+		}
+		response := []byte{function, byte(2 * count)}
+		for i := 0; i < count; i++ {
+			word, ok := table[address+uint16(i)]
+			if !ok {
+				return exception(exceptionIllegalDataAddress)
+			}
+			response = binary.BigEndian.AppendUint16(response, word)
+		}
+		return response
+	case funcWriteSingleRegister:
+		if len(data) != 4 {
+			return exception(exceptionIllegalDataValue)
+		}
+		address := binary.BigEndian.Uint16(data)
+		if _, ok := s.holding[address]; !ok {
+			return exception(exceptionIllegalDataAddress)
+		}
+		s.holding[address] = binary.BigEndian.Uint16(data[2:])
+		return append([]byte{}, pdu...)
+	case funcWriteMultipleRegisters:
+		if len(data) < 5 {
+			return exception(exceptionIllegalDataValue)
+		}
+		address := binary.BigEndian.Uint16(data)
+		count := int(binary.BigEndian.Uint16(data[2:]))
+		if int(data[4]) != 2*count || len(data) != 5+2*count {
+			return exception(exceptionIllegalDataValue)
+		}
+		for i := 0; i < count; i++ {
+			if _, ok := s.holding[address+uint16(i)]; !ok {
+				return exception(exceptionIllegalDataAddress)
+			}
+		}
+		for i := 0; i < count; i++ {
+			s.holding[address+uint16(i)] = binary.BigEndian.Uint16(data[5+2*i:])
+		}
+		return append([]byte{function}, data[:4]...)
+	default:
+		return exception(exceptionIllegalFunction)
+	}
+}
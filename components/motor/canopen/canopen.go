@@ -0,0 +1,370 @@
+package canopen
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/logging"
+)
+
+// The function codes of CANopen communication objects, which are added to the node ID to form
+// the CAN ID of a frame.
+const (
+	cobNMT       = 0x000
+	cobEmergency = 0x080
+	cobTPDO1     = 0x180
+	cobRPDO1     = 0x200
+	cobTPDO2     = 0x280
+	cobRPDO2     = 0x300
+	cobSDOTx     = 0x580 // server (drive) to client
+	cobSDORx     = 0x600 // client to server (drive)
+	cobHeartbeat = 0x700
+)
+
+// NMT commands.
+const (
+	nmtStart               = 0x01
+	nmtEnterPreOperational = 0x80
+
+	// nmtStateBootUp is the state sent in the heartbeat of a node that just booted.
+	nmtStateBootUp = 0x00
+)
+
+// SDO command specifiers.
+const (
+	sdoDownloadRequest  = 0x20 // plus the expedited, size indicated and unused byte count bits
+	sdoDownloadResponse = 0x60
+	sdoUploadRequest    = 0x40
+	sdoUploadResponse   = 0x40 // plus the expedited, size indicated and unused byte count bits
+	sdoAbort            = 0x80
+	sdoCommandMask      = 0xE0
+	sdoExpedited        = 0x02
+	sdoSizeIndicated    = 0x01
+)
+
+const defaultSDOTimeout = 500 * time.Millisecond
+
+// sdoAbortDescriptions describes the most common SDO abort codes, from CiA 301.
+var sdoAbortDescriptions = map[uint32]string{
+	0x05030000: "toggle bit not alternated",
+	0x05040000: "SDO protocol timed out",
+	0x05040001: "command specifier not valid or unknown",
+	0x06010000: "unsupported access to an object",
+	0x06010001: "attempt to read a write only object",
+	0x06010002: "attempt to write a read only object",
+	0x06020000: "object does not exist in the object dictionary",
+	0x06040041: "object cannot be mapped to the PDO",
+	0x06040042: "the number and length of the objects to be mapped would exceed PDO length",
+	0x06070010: "data type does not match, length of service parameter does not match",
+	0x06090011: "sub-index does not exist",
+	0x06090030: "invalid value for parameter",
+	0x06090031: "value of parameter written too high",
+	0x06090032: "value of parameter written too low",
+	0x08000000: "general error",
+	0x08000020: "data cannot be transferred or stored to the application",
+	0x08000022: "data cannot be transferred or stored to the application because of the present device state",
+}
+
+// SDOAbortError is returned when a drive aborts an SDO transfer.
+type SDOAbortError struct {
+	Index    uint16
+	Subindex uint8
+	Code     uint32
+}
+
+func (e *SDOAbortError) Error() string {
+	desc, ok := sdoAbortDescriptions[e.Code]
+	if !ok {
+		desc = "unknown abort code"
+	}
+	return fmt.Sprintf("SDO transfer of object %#04x:%02x aborted with code %#08x: %s", e.Index, e.Subindex, e.Code, desc)
+}
+
+// A mappedObject is an object of the object dictionary mapped into a PDO.
+type mappedObject struct {
+	index    uint16
+	subindex uint8
+	bits     uint8
+}
+
+// mappingEntry returns the value of the entry of a PDO mapping parameter that maps the object.
+func (o mappedObject) mappingEntry() uint32 {
+	return uint32(o.index)<<16 | uint32(o.subindex)<<8 | uint32(o.bits)
+}
+
+// A pdoMapping describes a PDO and the objects mapped into it.
+type pdoMapping struct {
+	// commParameter and mappingParameter are the indices of the PDO's communication and mapping
+	// parameter objects, e.g. 0x1400 and 0x1600 for RPDO1.
+	commParameter    uint16
+	mappingParameter uint16
+	cob              uint32
+	transmit         bool
+	objects          []mappedObject
+}
+
+// A node is the client side of the CANopen connection to a single drive on a CAN bus. It runs a
+// background loop dispatching the frames the drive sends.
+type node struct {
+	bus    buses.CAN
+	id     uint8
+	logger logging.Logger
+
+	sdoTimeout   time.Duration
+	sdoMu        sync.Mutex // only one SDO transfer may be in progress at once
+	sdoResponses chan buses.CANFrame
+
+	// onTPDO is called with the function code and data of every TPDO received.
+	onTPDO func(cob uint32, data []byte)
+	// onEmergency is called with the error code and error register of every emergency received.
+	onEmergency func(code uint16, register uint8)
+
+	cancel  func()
+	workers sync.WaitGroup
+}
+
+func newNode(bus buses.CAN, id uint8, logger logging.Logger) *node {
+	return &node{
+		bus:          bus,
+		id:           id,
+		logger:       logger,
+		sdoTimeout:   defaultSDOTimeout,
+		sdoResponses: make(chan buses.CANFrame, 1),
+	}
+}
+
+// start begins receiving the frames sent by the drive, and the bus-off and controller error
+// frames of the bus.
+func (n *node) start() error {
+	// Every frame the drive sends carries its node ID in the lower 7 bits.
+	if err := n.bus.SetFilters([]buses.CANFilter{{ID: uint32(n.id), Mask: 0x7F}}); err != nil {
+		return err
+	}
+	if err := n.bus.SetErrorMask(buses.CANErrorBusOff | buses.CANErrorController | buses.CANErrorRestarted); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+	n.workers.Add(1)
+	go func() {
+		defer n.workers.Done()
+		n.receiveLoop(ctx)
+	}()
+	return nil
+}
+
+func (n *node) receiveLoop(ctx context.Context) {
+	for {
+		frame, err := n.bus.Receive(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				n.logger.Errorw("error receiving from CAN bus", "error", err)
+			}
+			return
+		}
+		if frame.Error {
+			n.logger.Warnw("CAN bus error", "error_classes", fmt.Sprintf("%#x", frame.ID))
+			continue
+		}
+		if frame.Extended || frame.RTR || frame.ID&0x7F != uint32(n.id) {
+			continue
+		}
+		switch cob := frame.ID &^ 0x7F; cob {
+		case cobSDOTx:
+			select {
+			case n.sdoResponses <- frame:
+			default:
+				// nobody is waiting for it
+			}
+		case cobTPDO1, cobTPDO2:
+			if n.onTPDO != nil {
+				n.onTPDO(cob, frame.Data)
+			}
+		case cobEmergency:
+			if len(frame.Data) >= 3 && n.onEmergency != nil {
+				n.onEmergency(binary.LittleEndian.Uint16(frame.Data[0:2]), frame.Data[2])
+			}
+		case cobHeartbeat:
+			if len(frame.Data) >= 1 && frame.Data[0] == nmtStateBootUp {
+				n.logger.Warnw("drive restarted and needs to be reconfigured", "node_id", n.id)
+			}
+		default:
+		}
+	}
+}
+
+// stop stops receiving frames from the drive.
+func (n *node) stop() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.workers.Wait()
+}
+
+// nmt sends a network management command to the drive.
+func (n *node) nmt(ctx context.Context, command byte) error {
+	return n.bus.Send(ctx, buses.CANFrame{ID: cobNMT, Data: []byte{command, n.id}})
+}
+
+// sdoTransfer sends an SDO request and waits for the drive's response.
+func (n *node) sdoTransfer(ctx context.Context, index uint16, subindex uint8, request []byte) ([]byte, error) {
+	n.sdoMu.Lock()
+	defer n.sdoMu.Unlock()
+
+	// discard a response to an earlier transfer that timed out
+	select {
+	case <-n.sdoResponses:
+	default:
+	}
+
+	if err := n.bus.Send(ctx, buses.CANFrame{ID: cobSDORx + uint32(n.id), Data: request}); err != nil {
+		return nil, err
+	}
+	timeout := time.NewTimer(n.sdoTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout.C:
+			return nil, errors.Errorf("timed out waiting for SDO response for object %#04x:%02x from node %d",
+				index, subindex, n.id)
+		case frame := <-n.sdoResponses:
+			if len(frame.Data) != 8 {
+				return nil, errors.Errorf("malformed SDO response of %d bytes from node %d", len(frame.Data), n.id)
+			}
+			if binary.LittleEndian.Uint16(frame.Data[1:3]) != index || frame.Data[3] != subindex {
+				// a late response to a different transfer
+				continue
+			}
+			if frame.Data[0] == sdoAbort {
+				return nil, &SDOAbortError{Index: index, Subindex: subindex, Code: binary.LittleEndian.Uint32(frame.Data[4:8])}
+			}
+			return frame.Data, nil
+		}
+	}
+}
+
+// download writes 1 to 4 bytes to an object of the drive with an expedited SDO transfer.
+func (n *node) download(ctx context.Context, index uint16, subindex uint8, data []byte) error {
+	if len(data) == 0 || len(data) > 4 {
+		return errors.Errorf("expedited SDO transfers carry 1 to 4 bytes, not %d", len(data))
+	}
+	request := make([]byte, 8)
+	request[0] = sdoDownloadRequest | byte(4-len(data))<<2 | sdoExpedited | sdoSizeIndicated
+	binary.LittleEndian.PutUint16(request[1:3], index)
+	request[3] = subindex
+	copy(request[4:], data)
+	response, err := n.sdoTransfer(ctx, index, subindex, request)
+	if err != nil {
+		return err
+	}
+	if response[0] != sdoDownloadResponse {
+		return errors.Errorf("unexpected SDO response %#02x writing object %#04x:%02x", response[0], index, subindex)
+	}
+	return nil
+}
+
+// upload reads an object of up to 4 bytes from the drive with an expedited SDO transfer.
+func (n *node) upload(ctx context.Context, index uint16, subindex uint8) ([]byte, error) {
+	request := make([]byte, 8)
+	request[0] = sdoUploadRequest
+	binary.LittleEndian.PutUint16(request[1:3], index)
+	request[3] = subindex
+	response, err := n.sdoTransfer(ctx, index, subindex, request)
+	if err != nil {
+		return nil, err
+	}
+	command := response[0]
+	if command&sdoCommandMask != sdoUploadResponse {
+		return nil, errors.Errorf("unexpected SDO response %#02x reading object %#04x:%02x", command, index, subindex)
+	}
+	if command&sdoExpedited == 0 {
+		return nil, errors.Errorf("object %#04x:%02x is too large for an expedited SDO transfer", index, subindex)
+	}
+	size := 4
+	if command&sdoSizeIndicated != 0 {
+		size = 4 - int(command>>2&0x03)
+	}
+	return response[4 : 4+size], nil
+}
+
+func (n *node) writeUint8(ctx context.Context, index uint16, subindex, value uint8) error {
+	return n.download(ctx, index, subindex, []byte{value})
+}
+
+func (n *node) writeUint16(ctx context.Context, index uint16, subindex uint8, value uint16) error {
+	return n.download(ctx, index, subindex, binary.LittleEndian.AppendUint16(nil, value))
+}
+
+func (n *node) writeUint32(ctx context.Context, index uint16, subindex uint8, value uint32) error {
+	return n.download(ctx, index, subindex, binary.LittleEndian.AppendUint32(nil, value))
+}
+
+func (n *node) readUint16(ctx context.Context, index uint16, subindex uint8) (uint16, error) {
+	data, err := n.upload(ctx, index, subindex)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 2 {
+		return 0, errors.Errorf("object %#04x:%02x is %d bytes, not 2", index, subindex, len(data))
+	}
+	return binary.LittleEndian.Uint16(data), nil
+}
+
+func (n *node) readInt32(ctx context.Context, index uint16, subindex uint8) (int32, error) {
+	data, err := n.upload(ctx, index, subindex)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 4 {
+		return 0, errors.Errorf("object %#04x:%02x is %d bytes, not 4", index, subindex, len(data))
+	}
+	return int32(binary.LittleEndian.Uint32(data)), nil
+}
+
+// configurePDO maps the objects of a PDO through SDO transfers, following the procedure of CiA
+// 301: the PDO is disabled while its mapping is changed. TPDOs are sent by the drive whenever
+// their data changes and at least every eventTimer.
+func (n *node) configurePDO(ctx context.Context, pdo pdoMapping, eventTimer time.Duration) error {
+	cobID := pdo.cob + uint32(n.id)
+	const pdoInvalid = 0x80000000
+	steps := []func() error{
+		func() error { return n.writeUint32(ctx, pdo.commParameter, 1, cobID|pdoInvalid) },
+		func() error { return n.writeUint8(ctx, pdo.mappingParameter, 0, 0) },
+	}
+	for i, object := range pdo.objects {
+		subindex := uint8(i + 1)
+		entry := object.mappingEntry()
+		steps = append(steps, func() error { return n.writeUint32(ctx, pdo.mappingParameter, subindex, entry) })
+	}
+	steps = append(steps,
+		func() error { return n.writeUint8(ctx, pdo.mappingParameter, 0, uint8(len(pdo.objects))) },
+		// asynchronous, event driven transmission
+		func() error { return n.writeUint8(ctx, pdo.commParameter, 2, 0xFF) },
+	)
+	if pdo.transmit {
+		steps = append(steps, func() error {
+			return n.writeUint16(ctx, pdo.commParameter, 5, uint16(eventTimer.Milliseconds()))
+		})
+	}
+	steps = append(steps, func() error { return n.writeUint32(ctx, pdo.commParameter, 1, cobID) })
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return errors.Wrapf(err, "error mapping PDO %#04x", pdo.commParameter)
+		}
+	}
+	return nil
+}
+
+// sendPDO sends an RPDO to the drive.
+func (n *node) sendPDO(ctx context.Context, pdo pdoMapping, data []byte) error {
+	return n.bus.Send(ctx, buses.CANFrame{ID: pdo.cob + uint32(n.id), Data: data})
+}
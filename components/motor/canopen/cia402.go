@@ -0,0 +1,807 @@
+// Package canopen implements motors driven by CANopen servo drives following the CiA 402 drive
+// profile, over a SocketCAN bus.
+package canopen
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("cia402")
+
+const (
+	defaultStatusPeriodMs = 20
+	// stateChangeTimeout bounds how long the drive may take to change state or mode of operation.
+	stateChangeTimeout = 2 * time.Second
+	pollPeriod         = 10 * time.Millisecond
+)
+
+// SDOWrite describes an object of the drive's object dictionary written when the motor is
+// configured, e.g. to set vendor specific current limits or gains.
+type SDOWrite struct {
+	// Index is the index of the object, e.g. "0x6085".
+	Index    string `json:"index"`
+	Subindex int    `json:"subindex,omitempty"`
+	// Size is the size of the object in bytes: 1, 2 or 4.
+	Size  int   `json:"size"`
+	Value int64 `json:"value"`
+}
+
+func (w SDOWrite) parse() (uint16, uint8, []byte, error) {
+	index, err := strconv.ParseUint(w.Index, 0, 16)
+	if err != nil {
+		return 0, 0, nil, errors.Errorf("invalid index %q", w.Index)
+	}
+	if w.Subindex < 0 || w.Subindex > math.MaxUint8 {
+		return 0, 0, nil, errors.Errorf("invalid subindex %d", w.Subindex)
+	}
+	var data []byte
+	switch w.Size {
+	case 1:
+		data = []byte{byte(w.Value)}
+	case 2:
+		data = binary.LittleEndian.AppendUint16(nil, uint16(w.Value))
+	case 4:
+		data = binary.LittleEndian.AppendUint32(nil, uint32(w.Value))
+	default:
+		return 0, 0, nil, errors.Errorf("size must be 1, 2 or 4 bytes, not %d", w.Size)
+	}
+	return uint16(index), uint8(w.Subindex), data, nil
+}
+
+// Config describes the configuration of a CiA 402 motor. Positions are in encoder ticks and
+// velocities in ticks per second on the drive, the units most drives default to.
+type Config struct {
+	// Board is the linux board whose can_buses include the bus the drive is on, and CANBus is the
+	// name of that bus.
+	Board            string  `json:"board"`
+	CANBus           string  `json:"can_bus"`
+	NodeID           int     `json:"node_id"`
+	TicksPerRotation int     `json:"ticks_per_rotation"`
+	MaxRPM           float64 `json:"max_rpm,omitempty"`
+	MaxAcceleration  float64 `json:"max_acceleration_rpm_per_sec,omitempty"`
+	// StatusPeriodMs is how often the drive reports its status if it has not changed. Defaults to 20.
+	StatusPeriodMs int        `json:"status_period_ms,omitempty"`
+	SDOWrites      []SDOWrite `json:"sdo_writes,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.Board == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "board")
+	}
+	if cfg.CANBus == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "can_bus")
+	}
+	if cfg.NodeID < 1 || cfg.NodeID > 127 {
+		return nil, resource.NewConfigValidationError(path, errors.New("node_id must be between 1 and 127"))
+	}
+	if cfg.TicksPerRotation <= 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "ticks_per_rotation")
+	}
+	if cfg.MaxRPM < 0 || cfg.MaxAcceleration < 0 || cfg.StatusPeriodMs < 0 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("max_rpm, max_acceleration_rpm_per_sec and status_period_ms may not be negative"))
+	}
+	for i, write := range cfg.SDOWrites {
+		if _, _, _, err := write.parse(); err != nil {
+			return nil, resource.NewConfigValidationError(fmt.Sprintf("%s.sdo_writes.%d", path, i), err)
+		}
+	}
+	return []string{cfg.Board}, nil
+}
+
+// Objects of the CiA 402 drive profile.
+const (
+	objControlword           = 0x6040
+	objStatusword            = 0x6041
+	objModesOfOperation      = 0x6060
+	objModesOfOperationShown = 0x6061
+	objPositionActual        = 0x6064
+	objVelocityActual        = 0x606C
+	objTargetPosition        = 0x607A
+	objMaxProfileVelocity    = 0x607F
+	objProfileVelocity       = 0x6081
+	objProfileAcceleration   = 0x6083
+	objProfileDeceleration   = 0x6084
+	objTargetVelocity        = 0x60FF
+)
+
+// Modes of operation.
+const (
+	modeProfilePosition = int8(1)
+	modeProfileVelocity = int8(3)
+)
+
+// Controlword bits and commands.
+const (
+	cwSwitchOn             = 0x0001
+	cwEnableVoltage        = 0x0002
+	cwQuickStop            = 0x0004 // active low
+	cwEnableOperation      = 0x0008
+	cwNewSetPoint          = 0x0010
+	cwChangeSetImmediately = 0x0020
+	cwFaultReset           = 0x0080
+	cwHalt                 = 0x0100
+
+	cmdDisableVoltage  = 0
+	cmdShutdown        = cwQuickStop | cwEnableVoltage
+	cmdSwitchOn        = cmdShutdown | cwSwitchOn
+	cmdEnableOperation = cmdSwitchOn | cwEnableOperation
+)
+
+// Statusword bits.
+const (
+	swTargetReached = 0x0400
+	swSetPointAck   = 0x1000
+	swFollowingErr  = 0x2000
+)
+
+// driveState is a state of the CiA 402 drive state machine.
+type driveState int
+
+const (
+	stateNotReadyToSwitchOn driveState = iota
+	stateSwitchOnDisabled
+	stateReadyToSwitchOn
+	stateSwitchedOn
+	stateOperationEnabled
+	stateQuickStopActive
+	stateFaultReactionActive
+	stateFault
+)
+
+func (s driveState) String() string {
+	switch s {
+	case stateNotReadyToSwitchOn:
+		return "not ready to switch on"
+	case stateSwitchOnDisabled:
+		return "switch on disabled"
+	case stateReadyToSwitchOn:
+		return "ready to switch on"
+	case stateSwitchedOn:
+		return "switched on"
+	case stateOperationEnabled:
+		return "operation enabled"
+	case stateQuickStopActive:
+		return "quick stop active"
+	case stateFaultReactionActive:
+		return "fault reaction active"
+	case stateFault:
+		return "fault"
+	default:
+		return "unknown"
+	}
+}
+
+// stateOf decodes the state of the drive from its statusword.
+func stateOf(statusword uint16) driveState {
+	switch {
+	case statusword&0x4F == 0x00:
+		return stateNotReadyToSwitchOn
+	case statusword&0x4F == 0x40:
+		return stateSwitchOnDisabled
+	case statusword&0x6F == 0x21:
+		return stateReadyToSwitchOn
+	case statusword&0x6F == 0x23:
+		return stateSwitchedOn
+	case statusword&0x6F == 0x27:
+		return stateOperationEnabled
+	case statusword&0x6F == 0x07:
+		return stateQuickStopActive
+	case statusword&0x4F == 0x0F:
+		return stateFaultReactionActive
+	default:
+		return stateFault
+	}
+}
+
+// The PDOs the motor maps: setpoints are sent in RPDOs, one per mode of operation, and the drive
+// reports its status in TPDOs.
+var (
+	rpdoPosition = pdoMapping{
+		commParameter:    0x1400,
+		mappingParameter: 0x1600,
+		cob:              cobRPDO1,
+		objects:          []mappedObject{{objControlword, 0, 16}, {objTargetPosition, 0, 32}},
+	}
+	rpdoVelocity = pdoMapping{
+		commParameter:    0x1401,
+		mappingParameter: 0x1601,
+		cob:              cobRPDO2,
+		objects:          []mappedObject{{objControlword, 0, 16}, {objTargetVelocity, 0, 32}},
+	}
+	tpdoStatus = pdoMapping{
+		commParameter:    0x1800,
+		mappingParameter: 0x1A00,
+		cob:              cobTPDO1,
+		transmit:         true,
+		objects:          []mappedObject{{objStatusword, 0, 16}, {objPositionActual, 0, 32}},
+	}
+	tpdoVelocity = pdoMapping{
+		commParameter:    0x1801,
+		mappingParameter: 0x1A01,
+		cob:              cobTPDO2,
+		transmit:         true,
+		objects:          []mappedObject{{objVelocityActual, 0, 32}, {objModesOfOperationShown, 0, 8}},
+	}
+)
+
+// driveStatus is the status of the drive as last reported.
+type driveStatus struct {
+	statusword uint16
+	position   int32
+	velocity   int32
+	mode       int8
+}
+
+// A Motor is a motor driven by a CiA 402 servo drive. Setpoints are sent to the drive in PDOs, in
+// the profile position mode for GoTo and GoFor and in the profile velocity mode otherwise.
+type Motor struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	bus              buses.CAN
+	node             *node
+	logger           logging.Logger
+	opMgr            *operation.SingleOperationManager
+	ticksPerRotation float64
+	maxRPM           float64
+	statusPeriod     time.Duration
+
+	// commandMu serializes the commands sent to the drive.
+	commandMu sync.Mutex
+	mode      int8 // the mode of operation last set
+	enabled   bool
+
+	mu            sync.Mutex
+	status        driveStatus
+	statusUpdated time.Time
+	lastEmergency uint16
+	zero          int32 // the position of the drive at the motor's zero position
+	powerPct      float64
+}
+
+// makeMotor returns a CiA 402 motor on the given bus, which it closes when the motor is closed. It
+// is separate from the constructor so that a simulated bus can be used in tests.
+func makeMotor(ctx context.Context, conf *Config, name resource.Name, logger logging.Logger, bus buses.CAN) (*Motor, error) {
+	statusPeriodMs := conf.StatusPeriodMs
+	if statusPeriodMs == 0 {
+		statusPeriodMs = defaultStatusPeriodMs
+	}
+	m := &Motor{
+		Named:            name.AsNamed(),
+		bus:              bus,
+		node:             newNode(bus, uint8(conf.NodeID), logger),
+		logger:           logger,
+		opMgr:            operation.NewSingleOperationManager(),
+		ticksPerRotation: float64(conf.TicksPerRotation),
+		maxRPM:           conf.MaxRPM,
+		statusPeriod:     time.Duration(statusPeriodMs) * time.Millisecond,
+	}
+	m.node.onTPDO = m.handleTPDO
+	m.node.onEmergency = m.handleEmergency
+	if err := m.node.start(); err != nil {
+		return nil, multierr.Combine(err, bus.Close())
+	}
+	if err := m.configure(ctx, conf); err != nil {
+		m.node.stop()
+		return nil, multierr.Combine(errors.Wrapf(err, "error configuring drive %d", conf.NodeID), bus.Close())
+	}
+	return m, nil
+}
+
+// configure writes the motion profile and PDO mapping to the drive, and starts it.
+func (m *Motor) configure(ctx context.Context, conf *Config) error {
+	// PDOs may only be mapped outside of the operational state.
+	if err := m.node.nmt(ctx, nmtEnterPreOperational); err != nil {
+		return err
+	}
+	if conf.MaxRPM > 0 {
+		if err := m.node.writeUint32(ctx, objMaxProfileVelocity, 0, uint32(m.rpmToVelocity(conf.MaxRPM))); err != nil {
+			return err
+		}
+	}
+	if conf.MaxAcceleration > 0 {
+		acceleration := uint32(conf.MaxAcceleration / 60 * m.ticksPerRotation)
+		if err := multierr.Combine(
+			m.node.writeUint32(ctx, objProfileAcceleration, 0, acceleration),
+			m.node.writeUint32(ctx, objProfileDeceleration, 0, acceleration),
+		); err != nil {
+			return err
+		}
+	}
+	for _, pdo := range []pdoMapping{rpdoPosition, rpdoVelocity} {
+		if err := m.node.configurePDO(ctx, pdo, 0); err != nil {
+			return err
+		}
+	}
+	for _, pdo := range []pdoMapping{tpdoStatus, tpdoVelocity} {
+		if err := m.node.configurePDO(ctx, pdo, m.statusPeriod); err != nil {
+			return err
+		}
+	}
+	// Writes from the config come last so that they may override any of the above.
+	for _, write := range conf.SDOWrites {
+		index, subindex, data, err := write.parse()
+		if err != nil {
+			return err
+		}
+		if err := m.node.download(ctx, index, subindex, data); err != nil {
+			return err
+		}
+	}
+	return m.node.nmt(ctx, nmtStart)
+}
+
+func (m *Motor) handleTPDO(cob uint32, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case cob == tpdoStatus.cob && len(data) >= 6:
+		m.status.statusword = binary.LittleEndian.Uint16(data[0:2])
+		m.status.position = int32(binary.LittleEndian.Uint32(data[2:6]))
+		m.statusUpdated = time.Now()
+	case cob == tpdoVelocity.cob && len(data) >= 5:
+		m.status.velocity = int32(binary.LittleEndian.Uint32(data[0:4]))
+		m.status.mode = int8(data[4])
+	}
+}
+
+func (m *Motor) handleEmergency(code uint16, register uint8) {
+	m.mu.Lock()
+	m.lastEmergency = code
+	m.mu.Unlock()
+	if code == 0 {
+		m.logger.Infow("drive errors cleared", "motor", m.Name().ShortName())
+		return
+	}
+	m.logger.Warnw("drive emergency",
+		"motor", m.Name().ShortName(),
+		"error_code", fmt.Sprintf("%#04x", code),
+		"error_register", fmt.Sprintf("%#02x", register),
+	)
+}
+
+// driveStatus returns the status last reported by the drive, reading it directly if the drive
+// has not reported its status recently.
+func (m *Motor) driveStatus(ctx context.Context) (driveStatus, error) {
+	m.mu.Lock()
+	status, updated := m.status, m.statusUpdated
+	m.mu.Unlock()
+	if time.Since(updated) < 3*m.statusPeriod {
+		return status, nil
+	}
+
+	statusword, err := m.node.readUint16(ctx, objStatusword, 0)
+	if err != nil {
+		return driveStatus{}, err
+	}
+	position, err := m.node.readInt32(ctx, objPositionActual, 0)
+	if err != nil {
+		return driveStatus{}, err
+	}
+	velocity, err := m.node.readInt32(ctx, objVelocityActual, 0)
+	if err != nil {
+		return driveStatus{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.statusword = statusword
+	m.status.position = position
+	m.status.velocity = velocity
+	return m.status, nil
+}
+
+// faultError returns an error describing the fault the drive is in.
+func (m *Motor) faultError() error {
+	m.mu.Lock()
+	code := m.lastEmergency
+	m.mu.Unlock()
+	return errors.Errorf("drive of motor (%s) is in fault state with error code %#04x; reset it with the %q command",
+		m.Name().ShortName(), code, FaultReset)
+}
+
+// enable brings the drive into the operation enabled state through the CiA 402 state machine. The
+// commandMu must be held.
+func (m *Motor) enable(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, stateChangeTimeout)
+	defer cancel()
+	for {
+		statusword, err := m.node.readUint16(ctx, objStatusword, 0)
+		if err != nil {
+			return err
+		}
+		state := stateOf(statusword)
+		var controlword uint16
+		switch state {
+		case stateOperationEnabled:
+			m.enabled = true
+			return nil
+		case stateFault, stateFaultReactionActive:
+			m.enabled = false
+			return m.faultError()
+		case stateSwitchOnDisabled:
+			controlword = cmdShutdown
+		case stateReadyToSwitchOn:
+			controlword = cmdSwitchOn
+		case stateSwitchedOn:
+			controlword = cmdEnableOperation
+		case stateQuickStopActive:
+			controlword = cmdDisableVoltage
+		case stateNotReadyToSwitchOn:
+		}
+		// the drive transitions out of the not ready to switch on state on its own
+		if state != stateNotReadyToSwitchOn {
+			if err := m.node.writeUint16(ctx, objControlword, 0, controlword); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "drive of motor (%s) stuck in state %q", m.Name().ShortName(), state)
+		case <-time.After(pollPeriod):
+		}
+	}
+}
+
+// prepare sets the mode of operation and enables the drive. The commandMu must be held.
+func (m *Motor) prepare(ctx context.Context, mode int8) error {
+	if m.mode != mode {
+		if err := m.node.writeUint8(ctx, objModesOfOperation, 0, uint8(mode)); err != nil {
+			return err
+		}
+		if err := m.waitForMode(ctx, mode); err != nil {
+			return err
+		}
+		m.mode = mode
+	}
+	return m.enable(ctx)
+}
+
+func (m *Motor) waitForMode(ctx context.Context, mode int8) error {
+	ctx, cancel := context.WithTimeout(ctx, stateChangeTimeout)
+	defer cancel()
+	for {
+		shown, err := m.node.upload(ctx, objModesOfOperationShown, 0)
+		if err != nil {
+			return err
+		}
+		if len(shown) > 0 && int8(shown[0]) == mode {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "drive of motor (%s) did not change to mode of operation %d", m.Name().ShortName(), mode)
+		case <-time.After(pollPeriod):
+		}
+	}
+}
+
+// sendSetPoint sends a controlword and target in the RPDO of the current mode of operation.
+func (m *Motor) sendSetPoint(ctx context.Context, controlword uint16, target int32) error {
+	pdo := rpdoVelocity
+	if m.mode == modeProfilePosition {
+		pdo = rpdoPosition
+	}
+	data := binary.LittleEndian.AppendUint16(nil, controlword)
+	data = binary.LittleEndian.AppendUint32(data, uint32(target))
+	return m.node.sendPDO(ctx, pdo, data)
+}
+
+// rpmToVelocity converts revolutions per minute to ticks per second.
+func (m *Motor) rpmToVelocity(rpm float64) int32 {
+	return int32(rpm / 60 * m.ticksPerRotation)
+}
+
+// setVelocity runs the drive at the given speed in the profile velocity mode.
+func (m *Motor) setVelocity(ctx context.Context, rpm float64) error {
+	m.commandMu.Lock()
+	defer m.commandMu.Unlock()
+	if err := m.prepare(ctx, modeProfileVelocity); err != nil {
+		return err
+	}
+	return m.sendSetPoint(ctx, cmdEnableOperation, m.rpmToVelocity(rpm))
+}
+
+// SetPower runs the motor at a speed of powerPct (between -1 and 1) of max_rpm.
+func (m *Motor) SetPower(ctx context.Context, powerPct float64, extra map[string]interface{}) error {
+	if m.maxRPM == 0 {
+		return errors.Errorf("motor (%s) needs max_rpm to be configured to set its power", m.Name().ShortName())
+	}
+	m.opMgr.CancelRunning(ctx)
+	powerPct = math.Max(-1, math.Min(1, powerPct))
+	if err := m.setVelocity(ctx, powerPct*m.maxRPM); err != nil {
+		return errors.Wrapf(err, "error in SetPower from motor (%s)", m.Name().ShortName())
+	}
+	m.setPowerPct(powerPct)
+	return nil
+}
+
+// SetRPM instructs the motor to move at the specified RPM indefinitely.
+func (m *Motor) SetRPM(ctx context.Context, rpm float64, extra map[string]interface{}) error {
+	m.opMgr.CancelRunning(ctx)
+	warning, err := motor.CheckSpeed(rpm, m.maxRPM)
+	if warning != "" {
+		m.logger.CWarn(ctx, warning)
+	}
+	if err != nil {
+		return err
+	}
+	if err := m.setVelocity(ctx, rpm); err != nil {
+		return errors.Wrapf(err, "error in SetRPM from motor (%s)", m.Name().ShortName())
+	}
+	m.setPowerPct(m.rpmToPowerPct(rpm))
+	return nil
+}
+
+// GoFor turns in the given direction the given number of times at the given speed.
+// Both the RPM and the revolutions can be assigned negative values to move in a backwards direction.
+// Note: if both are negative the motor will spin in the forward direction.
+func (m *Motor) GoFor(ctx context.Context, rpm, revolutions float64, extra map[string]interface{}) error {
+	if err := motor.CheckRevolutions(revolutions); err != nil {
+		return err
+	}
+	pos, err := m.Position(ctx, extra)
+	if err != nil {
+		return errors.Wrapf(err, "error in GoFor from motor (%s)", m.Name().ShortName())
+	}
+	target := pos + motor.GetRequestedDirection(rpm, revolutions)*math.Abs(revolutions)
+	return m.GoTo(ctx, math.Abs(rpm), target, extra)
+}
+
+// GoTo moves to the specified position in terms of (provided in revolutions from home/zero),
+// at a specific speed, in the profile position mode. Regardless of the directionality of the RPM
+// this function will move the motor towards the specified target.
+func (m *Motor) GoTo(ctx context.Context, rpm, positionRevolutions float64, extra map[string]interface{}) error {
+	ctx, done := m.opMgr.New(ctx)
+	defer done()
+
+	warning, err := motor.CheckSpeed(rpm, m.maxRPM)
+	if warning != "" {
+		m.logger.CWarn(ctx, warning)
+	}
+	if err != nil {
+		return err
+	}
+	if m.maxRPM > 0 {
+		rpm = math.Min(math.Abs(rpm), m.maxRPM)
+	}
+
+	m.mu.Lock()
+	target := m.zero + int32(math.Round(positionRevolutions*m.ticksPerRotation))
+	m.mu.Unlock()
+	if err := m.startMove(ctx, math.Abs(rpm), target); err != nil {
+		return errors.Wrapf(err, "error in GoTo from motor (%s)", m.Name().ShortName())
+	}
+	m.setPowerPct(m.rpmToPowerPct(rpm))
+	defer m.setPowerPct(0)
+
+	return m.opMgr.WaitForSuccess(ctx, pollPeriod, func(ctx context.Context) (bool, error) {
+		status, err := m.driveStatus(ctx)
+		if err != nil {
+			return false, err
+		}
+		switch stateOf(status.statusword) {
+		case stateFault, stateFaultReactionActive:
+			return false, m.faultError()
+		case stateOperationEnabled:
+		default:
+			return false, errors.Errorf("drive of motor (%s) was disabled while moving", m.Name().ShortName())
+		}
+		if status.statusword&swFollowingErr != 0 {
+			return false, errors.Errorf("drive of motor (%s) reported a following error", m.Name().ShortName())
+		}
+		return status.statusword&(swTargetReached|swSetPointAck) == swTargetReached, nil
+	})
+}
+
+// startMove hands a new position set-point to the drive: the new set-point bit is raised until
+// the drive acknowledges the set-point, and then lowered again.
+func (m *Motor) startMove(ctx context.Context, rpm float64, target int32) error {
+	m.commandMu.Lock()
+	defer m.commandMu.Unlock()
+	if err := m.prepare(ctx, modeProfilePosition); err != nil {
+		return err
+	}
+	if err := m.node.writeUint32(ctx, objProfileVelocity, 0, uint32(m.rpmToVelocity(rpm))); err != nil {
+		return err
+	}
+	const controlword = cmdEnableOperation | cwChangeSetImmediately
+	if err := m.sendSetPoint(ctx, controlword|cwNewSetPoint, target); err != nil {
+		return err
+	}
+	if err := m.waitForStatus(ctx, swSetPointAck, swSetPointAck); err != nil {
+		return errors.Wrap(err, "drive did not acknowledge the new set-point")
+	}
+	return m.sendSetPoint(ctx, controlword, target)
+}
+
+// waitForStatus waits until the bits of the statusword selected by mask equal want.
+func (m *Motor) waitForStatus(ctx context.Context, mask, want uint16) error {
+	ctx, cancel := context.WithTimeout(ctx, stateChangeTimeout)
+	defer cancel()
+	for {
+		status, err := m.driveStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if status.statusword&mask == want {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollPeriod):
+		}
+	}
+}
+
+// Stop halts the motor, which is held in place by the drive.
+func (m *Motor) Stop(ctx context.Context, extra map[string]interface{}) error {
+	m.opMgr.CancelRunning(ctx)
+	m.setPowerPct(0)
+	m.commandMu.Lock()
+	defer m.commandMu.Unlock()
+	if !m.enabled {
+		return nil
+	}
+	target := int32(0)
+	if m.mode == modeProfilePosition {
+		status, err := m.driveStatus(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "error in Stop from motor (%s)", m.Name().ShortName())
+		}
+		target = status.position
+	}
+	if err := m.sendSetPoint(ctx, cmdEnableOperation|cwChangeSetImmediately|cwHalt, target); err != nil {
+		return errors.Wrapf(err, "error in Stop from motor (%s)", m.Name().ShortName())
+	}
+	return nil
+}
+
+// ResetZeroPosition sets the current position of the motor (adjusted by a given offset) to be
+// its new zero position.
+func (m *Motor) ResetZeroPosition(ctx context.Context, offset float64, extra map[string]interface{}) error {
+	moving, err := m.IsMoving(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "error in ResetZeroPosition from motor (%s)", m.Name().ShortName())
+	}
+	if moving {
+		return errors.Errorf("can't zero motor (%s) while moving", m.Name().ShortName())
+	}
+	status, err := m.driveStatus(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "error in ResetZeroPosition from motor (%s)", m.Name().ShortName())
+	}
+	m.mu.Lock()
+	m.zero = status.position + int32(math.Round(offset*m.ticksPerRotation))
+	m.mu.Unlock()
+	return nil
+}
+
+// Position reports the position of the motor in revolutions.
+func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	status, err := m.driveStatus(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error in Position from motor (%s)", m.Name().ShortName())
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return float64(status.position-m.zero) / m.ticksPerRotation, nil
+}
+
+// Properties returns the status of optional properties on the motor.
+func (m *Motor) Properties(ctx context.Context, extra map[string]interface{}) (motor.Properties, error) {
+	return motor.Properties{PositionReporting: true}, nil
+}
+
+// IsPowered returns whether the motor is moving, and the percent of max_rpm it was last asked to
+// move at.
+func (m *Motor) IsPowered(ctx context.Context, extra map[string]interface{}) (bool, float64, error) {
+	moving, err := m.IsMoving(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		return false, m.powerPct, errors.Wrapf(err, "error in IsPowered from motor (%s)", m.Name().ShortName())
+	}
+	return moving, m.powerPct, nil
+}
+
+// IsMoving returns whether the drive reports a nonzero velocity.
+func (m *Motor) IsMoving(ctx context.Context) (bool, error) {
+	status, err := m.driveStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.velocity != 0, nil
+}
+
+func (m *Motor) setPowerPct(powerPct float64) {
+	m.mu.Lock()
+	m.powerPct = powerPct
+	m.mu.Unlock()
+}
+
+func (m *Motor) rpmToPowerPct(rpm float64) float64 {
+	if m.maxRPM == 0 {
+		return 0
+	}
+	return rpm / m.maxRPM
+}
+
+// DoCommand() related constants.
+const (
+	Command    = "command"
+	FaultReset = "fault_reset"
+	Status     = "status"
+)
+
+// DoCommand executes additional commands beyond the Motor{} interface:
+//
+//	{"command": "status"} returns the state of the drive, its statusword and last emergency error code.
+//	{"command": "fault_reset"} resets a drive in the fault state.
+func (m *Motor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd[Command]
+	if !ok {
+		return nil, errors.Errorf("missing %s value", Command)
+	}
+	switch name {
+	case Status:
+		statusword, err := m.node.readUint16(ctx, objStatusword, 0)
+		if err != nil {
+			return nil, err
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return map[string]interface{}{
+			"state":          stateOf(statusword).String(),
+			"statusword":     int(statusword),
+			"emergency_code": int(m.lastEmergency),
+		}, nil
+	case FaultReset:
+		return nil, m.resetFault(ctx)
+	default:
+		return nil, errors.Errorf("no such command: %s", name)
+	}
+}
+
+// resetFault sends the rising edge of the fault reset bit to the drive.
+func (m *Motor) resetFault(ctx context.Context) error {
+	m.commandMu.Lock()
+	defer m.commandMu.Unlock()
+	m.enabled = false
+	return multierr.Combine(
+		m.node.writeUint16(ctx, objControlword, 0, cmdDisableVoltage),
+		m.node.writeUint16(ctx, objControlword, 0, cwFaultReset),
+	)
+}
+
+// Close disables the drive's power stage and closes the bus.
+func (m *Motor) Close(ctx context.Context) error {
+	m.opMgr.CancelRunning(ctx)
+	m.commandMu.Lock()
+	var err error
+	if m.enabled {
+		err = m.node.writeUint16(ctx, objControlword, 0, cmdShutdown)
+		m.enabled = false
+	}
+	m.commandMu.Unlock()
+	err = multierr.Combine(err, m.node.nmt(ctx, nmtEnterPreOperational))
+	m.node.stop()
+	return multierr.Combine(err, m.bus.Close())
+}
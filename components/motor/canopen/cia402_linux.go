@@ -0,0 +1,48 @@
+//go:build linux
+
+package canopen
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	resource.RegisterComponent(motor.API, model, resource.Registration[motor.Motor, *Config]{
+		Constructor: newMotor,
+	})
+}
+
+// A canBoard is a board that can open its configured CAN buses by name, such as a genericlinux board.
+type canBoard interface {
+	CANBusByName(name string) (buses.CAN, error)
+}
+
+// newMotor returns a motor driven by a CiA 402 drive on a SocketCAN bus of a board.
+func newMotor(ctx context.Context, deps resource.Dependencies, c resource.Config, logger logging.Logger,
+) (motor.Motor, error) {
+	conf, err := resource.NativeConfig[*Config](c)
+	if err != nil {
+		return nil, err
+	}
+	b, err := board.FromDependencies(deps, conf.Board)
+	if err != nil {
+		return nil, err
+	}
+	cb, ok := b.(canBoard)
+	if !ok {
+		return nil, errors.Errorf("board %q has no CAN buses", conf.Board)
+	}
+	bus, err := cb.CANBusByName(conf.CANBus)
+	if err != nil {
+		return nil, err
+	}
+	return makeMotor(ctx, conf, c.ResourceName(), logger, bus)
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package canopen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestNewMotorNeedsCANBoard(t *testing.T) {
+	conf := testConfig()
+	deps := resource.Dependencies{board.Named(conf.Board): inject.NewBoard(conf.Board)}
+	_, err := newMotor(context.Background(), deps, resource.Config{Name: "m1", ConvertedAttributes: conf},
+		logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeError, errors.New(`board "board1" has no CAN buses`))
+}
+
+// TestVirtualCAN runs the motor against a simulated drive on the vcan0 interface, which can be
+// created with:
+//
+//	ip link add dev vcan0 type vcan && ip link set up vcan0
+func TestVirtualCAN(t *testing.T) {
+	motorBus, err := buses.NewCanBus("vcan0")
+	if err != nil {
+		t.Skipf("vcan0 is not available: %v", err)
+	}
+	driveBus, err := buses.NewCanBus("vcan0")
+	test.That(t, err, test.ShouldBeNil)
+	defer utils.UncheckedErrorFunc(driveBus.Close)
+
+	ctx := context.Background()
+	m, _ := newTestMotor(t, ctx, testConfig(), motorBus, driveBus)
+	defer func() {
+		test.That(t, m.Close(ctx), test.ShouldBeNil)
+	}()
+
+	test.That(t, m.GoTo(ctx, 600, 2, nil), test.ShouldBeNil)
+	pos, err := m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 2)
+}
@@ -0,0 +1,241 @@
+package canopen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+)
+
+const testNodeID = 5
+
+func newTestMotor(t *testing.T, ctx context.Context, conf *Config, motorBus, driveBus buses.CAN) (*Motor, *simulatedDrive) {
+	t.Helper()
+	drive := newSimulatedDrive(t, driveBus, testNodeID)
+	m, err := makeMotor(ctx, conf, motor.Named("m1"), logging.NewTestLogger(t), motorBus)
+	test.That(t, err, test.ShouldBeNil)
+	return m, drive
+}
+
+func testConfig() *Config {
+	return &Config{
+		Board:            "board1",
+		CANBus:           "can0",
+		NodeID:           testNodeID,
+		TicksPerRotation: 100,
+		MaxRPM:           1200,
+		MaxAcceleration:  60000,
+		StatusPeriodMs:   10,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	conf := testConfig()
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"board1"})
+
+	for _, bad := range []func(conf *Config){
+		func(conf *Config) { conf.Board = "" },
+		func(conf *Config) { conf.CANBus = "" },
+		func(conf *Config) { conf.NodeID = 0 },
+		func(conf *Config) { conf.NodeID = 128 },
+		func(conf *Config) { conf.TicksPerRotation = 0 },
+		func(conf *Config) { conf.MaxRPM = -1 },
+		func(conf *Config) { conf.SDOWrites = []SDOWrite{{Index: "0x6085", Size: 3}} },
+		func(conf *Config) { conf.SDOWrites = []SDOWrite{{Index: "quick stop", Size: 4}} },
+	} {
+		conf := testConfig()
+		bad(conf)
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestStateOf(t *testing.T) {
+	for statusword, state := range map[uint16]driveState{
+		0x0000: stateNotReadyToSwitchOn,
+		0x0250: stateSwitchOnDisabled,
+		0x0231: stateReadyToSwitchOn,
+		0x0233: stateSwitchedOn,
+		0x1637: stateOperationEnabled,
+		0x0217: stateQuickStopActive,
+		0x021F: stateFaultReactionActive,
+		0x0218: stateFault,
+	} {
+		test.That(t, stateOf(statusword), test.ShouldEqual, state)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	ctx := context.Background()
+	bus := &virtualBus{}
+	conf := testConfig()
+	conf.SDOWrites = []SDOWrite{{Index: "0x6085", Size: 4, Value: 5000}}
+	m, drive := newTestMotor(t, ctx, conf, bus.connect(), bus.connect())
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, drive.isOperational(), test.ShouldBeTrue)
+	})
+	for _, pdo := range []pdoMapping{rpdoPosition, rpdoVelocity, tpdoStatus, tpdoVelocity} {
+		test.That(t, drive.object(pdo.commParameter, 1), test.ShouldEqual, pdo.cob+testNodeID)
+		test.That(t, drive.object(pdo.mappingParameter, 0), test.ShouldEqual, len(pdo.objects))
+		for i, object := range pdo.objects {
+			test.That(t, drive.object(pdo.mappingParameter, uint8(i+1)), test.ShouldEqual, object.mappingEntry())
+		}
+	}
+	test.That(t, drive.object(tpdoStatus.commParameter, 5), test.ShouldEqual, 10)
+	test.That(t, drive.object(objMaxProfileVelocity, 0), test.ShouldEqual, 2000)
+	test.That(t, drive.object(objProfileAcceleration, 0), test.ShouldEqual, 100000)
+	test.That(t, drive.object(0x6085, 0), test.ShouldEqual, 5000)
+
+	// the drive reports its status in TPDOs
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		test.That(tb, m.statusUpdated.IsZero(), test.ShouldBeFalse)
+		test.That(tb, stateOf(m.status.statusword), test.ShouldEqual, stateSwitchOnDisabled)
+	})
+
+	var abortErr *SDOAbortError
+	_, err := m.node.upload(ctx, 0x2000, 0)
+	test.That(t, errors.As(err, &abortErr), test.ShouldBeTrue)
+	test.That(t, abortErr.Code, test.ShouldEqual, 0x06020000)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "object does not exist")
+
+	test.That(t, m.Close(ctx), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, drive.isOperational(), test.ShouldBeFalse)
+	})
+}
+
+func TestVelocity(t *testing.T) {
+	ctx := context.Background()
+	bus := &virtualBus{}
+	m, drive := newTestMotor(t, ctx, testConfig(), bus.connect(), bus.connect())
+	defer func() {
+		test.That(t, m.Close(ctx), test.ShouldBeNil)
+	}()
+
+	test.That(t, m.SetRPM(ctx, 600, nil), test.ShouldBeNil)
+	test.That(t, drive.driveState(), test.ShouldEqual, stateOperationEnabled)
+	test.That(t, int8(drive.object(objModesOfOperationShown, 0)), test.ShouldEqual, modeProfileVelocity)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, drive.object(objTargetVelocity, 0), test.ShouldEqual, 1000)
+		on, powerPct, err := m.IsPowered(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, on, test.ShouldBeTrue)
+		test.That(tb, powerPct, test.ShouldEqual, 0.5)
+	})
+
+	test.That(t, m.SetPower(ctx, -0.25, nil), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, int32(drive.object(objTargetVelocity, 0)), test.ShouldEqual, -500)
+	})
+
+	test.That(t, m.Stop(ctx, nil), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		moving, err := m.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeFalse)
+	})
+	// stopping holds the motor in place rather than disabling the drive
+	test.That(t, drive.driveState(), test.ShouldEqual, stateOperationEnabled)
+
+	test.That(t, m.SetRPM(ctx, 0, nil), test.ShouldBeError, motor.NewZeroRPMError())
+}
+
+func TestPosition(t *testing.T) {
+	ctx := context.Background()
+	bus := &virtualBus{}
+	m, drive := newTestMotor(t, ctx, testConfig(), bus.connect(), bus.connect())
+	defer func() {
+		test.That(t, m.Close(ctx), test.ShouldBeNil)
+	}()
+
+	test.That(t, m.GoTo(ctx, 600, 2, nil), test.ShouldBeNil)
+	test.That(t, int8(drive.object(objModesOfOperationShown, 0)), test.ShouldEqual, modeProfilePosition)
+	test.That(t, drive.object(objProfileVelocity, 0), test.ShouldEqual, 1000)
+	pos, err := m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 2)
+
+	test.That(t, m.GoFor(ctx, -600, 1.5, nil), test.ShouldBeNil)
+	pos, err = m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 0.5)
+
+	test.That(t, m.GoFor(ctx, 600, 0, nil), test.ShouldBeError, motor.NewZeroRevsError())
+
+	test.That(t, m.ResetZeroPosition(ctx, 0.25, nil), test.ShouldBeNil)
+	pos, err = m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, -0.25)
+	test.That(t, m.GoTo(ctx, 600, 1, nil), test.ShouldBeNil)
+	test.That(t, int32(drive.object(objPositionActual, 0)), test.ShouldEqual, 175)
+
+	// a move is interrupted by stopping the motor
+	errs := make(chan error, 1)
+	go func() {
+		errs <- m.GoFor(ctx, 60, 100, nil)
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		moving, err := m.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	test.That(t, m.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-errs, test.ShouldBeError, context.Canceled)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		moving, err := m.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeFalse)
+	})
+}
+
+func TestFault(t *testing.T) {
+	ctx := context.Background()
+	bus := &virtualBus{}
+	m, drive := newTestMotor(t, ctx, testConfig(), bus.connect(), bus.connect())
+	defer func() {
+		test.That(t, m.Close(ctx), test.ShouldBeNil)
+	}()
+
+	test.That(t, m.SetRPM(ctx, 60, nil), test.ShouldBeNil)
+	test.That(t, drive.fault(ctx, 0x2310), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		status, err := m.DoCommand(ctx, map[string]interface{}{Command: Status})
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, status["state"], test.ShouldEqual, "fault")
+		test.That(tb, status["emergency_code"], test.ShouldEqual, 0x2310)
+	})
+
+	err := m.SetRPM(ctx, 60, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "fault state with error code 0x2310")
+
+	_, err = m.DoCommand(ctx, map[string]interface{}{Command: FaultReset})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, drive.driveState(), test.ShouldEqual, stateSwitchOnDisabled)
+	test.That(t, m.SetRPM(ctx, 60, nil), test.ShouldBeNil)
+	test.That(t, drive.driveState(), test.ShouldEqual, stateOperationEnabled)
+
+	_, err = m.DoCommand(ctx, map[string]interface{}{Command: "dance"})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestUnresponsiveDrive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bus := &virtualBus{}
+	_, err := makeMotor(ctx, testConfig(), motor.Named("m1"), logging.NewTestLogger(t), bus.connect())
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "timed out waiting for SDO response")
+}
@@ -0,0 +1,506 @@
+package canopen
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+// virtualBus is an in-memory CAN bus, on which every frame sent on a connection is received by
+// all other connections whose filters it passes, like a SocketCAN interface.
+type virtualBus struct {
+	mu    sync.Mutex
+	conns []*virtualConn
+}
+
+func (b *virtualBus) connect() *virtualConn {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	conn := &virtualConn{bus: b, frames: make(chan buses.CANFrame, 256)}
+	b.conns = append(b.conns, conn)
+	return conn
+}
+
+type virtualConn struct {
+	bus    *virtualBus
+	frames chan buses.CANFrame
+
+	mu      sync.Mutex
+	filters []buses.CANFilter
+	closed  bool
+}
+
+func (c *virtualConn) Send(ctx context.Context, frame buses.CANFrame) error {
+	if len(frame.Data) > 8 {
+		return errors.New("frame too long")
+	}
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	for _, other := range c.bus.conns {
+		if other == c || !other.accepts(frame) {
+			continue
+		}
+		frame.Data = append([]byte(nil), frame.Data...)
+		select {
+		case other.frames <- frame:
+		default:
+			// dropped, like a full socket receive queue
+		}
+	}
+	return nil
+}
+
+func (c *virtualConn) accepts(frame buses.CANFrame) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	if len(c.filters) == 0 {
+		return true
+	}
+	for _, filter := range c.filters {
+		matches := frame.Extended == filter.Extended && frame.ID&filter.Mask == filter.ID&filter.Mask
+		if matches != filter.Invert {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *virtualConn) Receive(ctx context.Context) (buses.CANFrame, error) {
+	select {
+	case <-ctx.Done():
+		return buses.CANFrame{}, ctx.Err()
+	case frame := <-c.frames:
+		return frame, nil
+	}
+}
+
+func (c *virtualConn) SetFilters(filters []buses.CANFilter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filters = filters
+	return nil
+}
+
+func (c *virtualConn) SetErrorMask(mask uint32) error {
+	return nil
+}
+
+func (c *virtualConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// objectKey returns the key of an object in a simulated object dictionary.
+func objectKey(index uint16, subindex uint8) uint32 {
+	return uint32(index)<<8 | uint32(subindex)
+}
+
+// simulatedDrive simulates a CiA 402 servo drive: its object dictionary, SDO server, PDOs, state
+// machine and the kinematics of the profile position and profile velocity modes.
+type simulatedDrive struct {
+	conn buses.CAN
+	id   uint8
+
+	mu          sync.Mutex
+	sizes       map[uint32]int // the size in bytes of every object in the dictionary
+	objects     map[uint32]uint32
+	operational bool
+	state       driveState
+	controlword uint16
+	setPointAck bool
+	reached     bool
+	position    float64
+	velocity    float64
+	lastSent    map[uint16][]byte
+	lastSentAt  map[uint16]time.Time
+
+	cancel  func()
+	workers sync.WaitGroup
+}
+
+func newSimulatedDrive(t *testing.T, conn buses.CAN, id uint8) *simulatedDrive {
+	t.Helper()
+	d := &simulatedDrive{
+		conn:       conn,
+		id:         id,
+		sizes:      map[uint32]int{},
+		objects:    map[uint32]uint32{},
+		state:      stateSwitchOnDisabled,
+		lastSent:   map[uint16][]byte{},
+		lastSentAt: map[uint16]time.Time{},
+	}
+	define := func(index uint16, subindex uint8, size int, value uint32) {
+		d.sizes[objectKey(index, subindex)] = size
+		d.objects[objectKey(index, subindex)] = value
+	}
+	for i, cob := range []uint32{cobRPDO1, cobRPDO2} {
+		define(0x1400+uint16(i), 1, 4, cob+uint32(id))
+		define(0x1400+uint16(i), 2, 1, 0xFF)
+	}
+	for i, cob := range []uint32{cobTPDO1, cobTPDO2} {
+		define(0x1800+uint16(i), 1, 4, cob+uint32(id))
+		define(0x1800+uint16(i), 2, 1, 0xFF)
+		define(0x1800+uint16(i), 5, 2, 0)
+	}
+	for _, mapping := range []uint16{0x1600, 0x1601, 0x1A00, 0x1A01} {
+		for subindex := uint8(1); subindex <= 8; subindex++ {
+			define(mapping, subindex, 4, 0)
+		}
+	}
+	// Factory mappings the motor must replace.
+	define(0x1600, 0, 1, 1)
+	define(0x1600, 1, 4, mappedObject{objControlword, 0, 16}.mappingEntry())
+	define(0x1601, 0, 1, 0)
+	define(0x1A00, 0, 1, 1)
+	define(0x1A00, 1, 4, mappedObject{objStatusword, 0, 16}.mappingEntry())
+	define(0x1A01, 0, 1, 0)
+
+	for _, object := range []struct {
+		index uint16
+		size  int
+	}{
+		{objControlword, 2},
+		{objStatusword, 2},
+		{objModesOfOperation, 1},
+		{objModesOfOperationShown, 1},
+		{objPositionActual, 4},
+		{objVelocityActual, 4},
+		{objTargetPosition, 4},
+		{objMaxProfileVelocity, 4},
+		{objProfileVelocity, 4},
+		{objProfileAcceleration, 4},
+		{objProfileDeceleration, 4},
+		{0x6085, 4}, // quick stop deceleration
+		{objTargetVelocity, 4},
+	} {
+		define(object.index, 0, object.size, 0)
+	}
+	d.objects[objectKey(objStatusword, 0)] = uint32(d.statusword())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.workers.Add(2)
+	go func() {
+		defer d.workers.Done()
+		for {
+			frame, err := conn.Receive(ctx)
+			if err != nil {
+				return
+			}
+			d.handle(ctx, frame)
+		}
+	}()
+	go func() {
+		defer d.workers.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				d.step(ctx, now.Sub(last).Seconds())
+				last = now
+			}
+		}
+	}()
+	t.Cleanup(d.stop)
+	return d
+}
+
+func (d *simulatedDrive) stop() {
+	d.cancel()
+	d.workers.Wait()
+}
+
+func (d *simulatedDrive) object(index uint16, subindex uint8) uint32 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.objects[objectKey(index, subindex)]
+}
+
+func (d *simulatedDrive) isOperational() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.operational
+}
+
+func (d *simulatedDrive) driveState() driveState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+// fault puts the drive in the fault state and sends an emergency with the given error code.
+func (d *simulatedDrive) fault(ctx context.Context, code uint16) error {
+	d.mu.Lock()
+	d.state = stateFault
+	d.velocity = 0
+	d.objects[objectKey(objStatusword, 0)] = uint32(d.statusword())
+	d.mu.Unlock()
+	data := binary.LittleEndian.AppendUint16(nil, code)
+	data = append(data, 0x01, 0, 0, 0, 0, 0)
+	return d.conn.Send(ctx, buses.CANFrame{ID: cobEmergency + uint32(d.id), Data: data})
+}
+
+func (d *simulatedDrive) statusword() uint16 {
+	var statusword uint16
+	switch d.state {
+	case stateNotReadyToSwitchOn:
+		statusword = 0x00
+	case stateSwitchOnDisabled:
+		statusword = 0x40
+	case stateReadyToSwitchOn:
+		statusword = 0x21
+	case stateSwitchedOn:
+		statusword = 0x23
+	case stateOperationEnabled:
+		statusword = 0x27
+	case stateQuickStopActive:
+		statusword = 0x07
+	case stateFaultReactionActive:
+		statusword = 0x0F
+	case stateFault:
+		statusword = 0x08
+	}
+	if d.reached {
+		statusword |= swTargetReached
+	}
+	if d.setPointAck {
+		statusword |= swSetPointAck
+	}
+	return statusword
+}
+
+func (d *simulatedDrive) handle(ctx context.Context, frame buses.CANFrame) {
+	switch {
+	case frame.ID == cobNMT && len(frame.Data) == 2 && (frame.Data[1] == d.id || frame.Data[1] == 0):
+		d.mu.Lock()
+		switch frame.Data[0] {
+		case nmtStart:
+			d.operational = true
+		case nmtEnterPreOperational:
+			d.operational = false
+		}
+		d.mu.Unlock()
+	case frame.ID == cobSDORx+uint32(d.id) && len(frame.Data) == 8:
+		response := d.handleSDO(frame.Data)
+		//nolint:errcheck
+		d.conn.Send(ctx, buses.CANFrame{ID: cobSDOTx + uint32(d.id), Data: response})
+	case frame.ID == cobRPDO1+uint32(d.id) || frame.ID == cobRPDO2+uint32(d.id):
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		pdo := uint16(0)
+		if frame.ID == cobRPDO2+uint32(d.id) {
+			pdo = 1
+		}
+		if !d.operational || d.objects[objectKey(0x1400+pdo, 1)]&0x80000000 != 0 {
+			return
+		}
+		data := frame.Data
+		for subindex := uint8(1); subindex <= uint8(d.objects[objectKey(0x1600+pdo, 0)]); subindex++ {
+			entry := d.objects[objectKey(0x1600+pdo, subindex)]
+			size := int(entry&0xFF) / 8
+			if len(data) < size {
+				return
+			}
+			var value uint32
+			for i := size - 1; i >= 0; i-- {
+				value = value<<8 | uint32(data[i])
+			}
+			data = data[size:]
+			d.write(uint16(entry>>16), uint8(entry>>8), value)
+		}
+	}
+}
+
+func sdoAbortResponse(request []byte, code uint32) []byte {
+	response := append([]byte{sdoAbort}, request[1:4]...)
+	return binary.LittleEndian.AppendUint32(response, code)
+}
+
+func (d *simulatedDrive) handleSDO(request []byte) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	index := binary.LittleEndian.Uint16(request[1:3])
+	subindex := request[3]
+	key := objectKey(index, subindex)
+	size, ok := d.sizes[key]
+	if !ok {
+		return sdoAbortResponse(request, 0x06020000)
+	}
+	switch request[0] & sdoCommandMask {
+	case sdoUploadRequest:
+		response := []byte{sdoUploadResponse | byte(4-size)<<2 | sdoExpedited | sdoSizeIndicated}
+		response = append(response, request[1:4]...)
+		return binary.LittleEndian.AppendUint32(response, d.objects[key])
+	case sdoDownloadRequest:
+		if request[0]&(sdoExpedited|sdoSizeIndicated) != sdoExpedited|sdoSizeIndicated ||
+			4-int(request[0]>>2&0x03) != size {
+			return sdoAbortResponse(request, 0x06070010)
+		}
+		if index == objStatusword || index == objModesOfOperationShown ||
+			index == objPositionActual || index == objVelocityActual {
+			return sdoAbortResponse(request, 0x06010002)
+		}
+		// A PDO's mapping may only be changed while the PDO is disabled, and the PDO may only
+		// be enabled with a mapping.
+		if (index >= 0x1600 && index <= 0x1601) || (index >= 0x1A00 && index <= 0x1A01) {
+			comm := index - 0x200
+			if d.objects[objectKey(comm, 1)]&0x80000000 == 0 || (subindex != 0 && d.objects[objectKey(index, 0)] != 0) {
+				return sdoAbortResponse(request, 0x08000022)
+			}
+		}
+		d.write(index, subindex, binary.LittleEndian.Uint32(request[4:8]))
+		return append([]byte{sdoDownloadResponse}, append(request[1:4], 0, 0, 0, 0)...)
+	default:
+		return sdoAbortResponse(request, 0x05040001)
+	}
+}
+
+// write writes an object and applies its effects. The mu must be held.
+func (d *simulatedDrive) write(index uint16, subindex uint8, value uint32) {
+	d.objects[objectKey(index, subindex)] = value
+	switch index {
+	case objControlword:
+		d.applyControlword(uint16(value))
+		d.objects[objectKey(objStatusword, 0)] = uint32(d.statusword())
+	case objModesOfOperation:
+		d.objects[objectKey(objModesOfOperationShown, 0)] = value
+	}
+}
+
+// applyControlword runs the CiA 402 state machine. The mu must be held.
+func (d *simulatedDrive) applyControlword(controlword uint16) {
+	previous := d.controlword
+	d.controlword = controlword
+	if d.state == stateFault {
+		if controlword&cwFaultReset != 0 && previous&cwFaultReset == 0 {
+			d.state = stateSwitchOnDisabled
+		}
+		return
+	}
+	switch {
+	case controlword&cwEnableVoltage == 0:
+		d.state = stateSwitchOnDisabled
+	case controlword&cwQuickStop == 0:
+		if d.state == stateOperationEnabled {
+			d.state = stateQuickStopActive
+		} else {
+			d.state = stateSwitchOnDisabled
+		}
+	case controlword&0x0F == cmdShutdown:
+		if d.state == stateSwitchOnDisabled || d.state == stateSwitchedOn || d.state == stateOperationEnabled {
+			d.state = stateReadyToSwitchOn
+		}
+	case controlword&0x0F == cmdSwitchOn:
+		if d.state == stateReadyToSwitchOn || d.state == stateOperationEnabled {
+			d.state = stateSwitchedOn
+		}
+	case controlword&0x0F == cmdEnableOperation:
+		if d.state == stateSwitchedOn {
+			d.state = stateOperationEnabled
+		}
+	}
+	if d.state != stateOperationEnabled || int8(d.objects[objectKey(objModesOfOperation, 0)]) != modeProfilePosition {
+		return
+	}
+	switch {
+	case controlword&cwNewSetPoint != 0 && previous&cwNewSetPoint == 0:
+		d.setPointAck = true
+		d.reached = false
+	case controlword&cwNewSetPoint == 0:
+		d.setPointAck = false
+	}
+}
+
+// step advances the simulation by dt seconds and sends the TPDOs that are due.
+func (d *simulatedDrive) step(ctx context.Context, dt float64) {
+	d.mu.Lock()
+	acceleration := float64(d.objects[objectKey(objProfileAcceleration, 0)])
+	if acceleration == 0 {
+		acceleration = math.Inf(1)
+	}
+	halted := d.controlword&cwHalt != 0
+	desired := 0.0
+	if d.state == stateOperationEnabled && !halted {
+		switch int8(d.objects[objectKey(objModesOfOperation, 0)]) {
+		case modeProfileVelocity:
+			desired = float64(int32(d.objects[objectKey(objTargetVelocity, 0)]))
+		case modeProfilePosition:
+			remaining := float64(int32(d.objects[objectKey(objTargetPosition, 0)])) - d.position
+			speed := float64(d.objects[objectKey(objProfileVelocity, 0)])
+			if math.Abs(remaining) <= speed*dt {
+				// arrive at the target within this step
+				d.velocity = 0
+				d.position += remaining
+			} else {
+				desired = math.Copysign(speed, remaining)
+			}
+		}
+	}
+	change := desired - d.velocity
+	if math.Abs(change) > acceleration*dt {
+		change = math.Copysign(acceleration*dt, change)
+	}
+	if d.state != stateOperationEnabled {
+		change = -d.velocity
+	}
+	d.velocity += change
+	d.position += d.velocity * dt
+	switch int8(d.objects[objectKey(objModesOfOperation, 0)]) {
+	case modeProfilePosition:
+		if !d.setPointAck && d.velocity == 0 {
+			d.reached = true
+		}
+	case modeProfileVelocity:
+		d.reached = d.velocity == desired
+	}
+
+	d.objects[objectKey(objStatusword, 0)] = uint32(d.statusword())
+	d.objects[objectKey(objPositionActual, 0)] = uint32(int32(math.Round(d.position)))
+	d.objects[objectKey(objVelocityActual, 0)] = uint32(int32(math.Round(d.velocity)))
+
+	var frames []buses.CANFrame
+	for pdo := uint16(0); pdo < 2 && d.operational; pdo++ {
+		cobID := d.objects[objectKey(0x1800+pdo, 1)]
+		count := uint8(d.objects[objectKey(0x1A00+pdo, 0)])
+		if cobID&0x80000000 != 0 || count == 0 {
+			continue
+		}
+		var data []byte
+		for subindex := uint8(1); subindex <= count; subindex++ {
+			entry := d.objects[objectKey(0x1A00+pdo, subindex)]
+			value := d.objects[objectKey(uint16(entry>>16), uint8(entry>>8))]
+			for i := 0; i < int(entry&0xFF)/8; i++ {
+				data = append(data, byte(value>>(8*i)))
+			}
+		}
+		eventTimer := time.Duration(d.objects[objectKey(0x1800+pdo, 5)]) * time.Millisecond
+		if string(data) == string(d.lastSent[pdo]) && time.Since(d.lastSentAt[pdo]) < eventTimer {
+			continue
+		}
+		d.lastSent[pdo] = data
+		d.lastSentAt[pdo] = time.Now()
+		frames = append(frames, buses.CANFrame{ID: cobID, Data: data})
+	}
+	d.mu.Unlock()
+
+	for _, frame := range frames {
+		//nolint:errcheck
+		d.conn.Send(ctx, frame)
+	}
+}
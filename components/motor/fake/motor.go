@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/board"
@@ -18,6 +19,7 @@ import (
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
 )
 
 var (
@@ -33,6 +35,8 @@ var (
 
 const (
 	defaultMaxRpm = 100
+	// simPollInterval is how often a simulated motor checks whether it has reached its goal.
+	simPollInterval = 10 * time.Millisecond
 )
 
 // PinConfig defines the mapping of where motor are wired.
@@ -52,6 +56,9 @@ type Config struct {
 	MaxRPM           float64   `json:"max_rpm,omitempty"`
 	TicksPerRotation int       `json:"ticks_per_rotation,omitempty"`
 	DirectionFlip    bool      `json:"direction_flip,omitempty"`
+	// Physics, when set, simulates the motor's inertia, torque and friction rather than having it
+	// move at the commanded speed instantly.
+	Physics *sim.MotorConfig `json:"physics,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -66,6 +73,11 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 		}
 		deps = append(deps, cfg.Encoder)
 	}
+	if cfg.Physics != nil {
+		if err := cfg.Physics.Validate(path + ".physics"); err != nil {
+			return nil, err
+		}
+	}
 	return deps, nil
 }
 
@@ -91,6 +103,10 @@ type Motor struct {
 	DirFlip           bool
 	TicksPerRotation  int
 
+	// physics simulates the motor when configured, on the shared sim clock.
+	physics *sim.Motor
+	clock   clock.Clock
+
 	OpMgr  *operation.SingleOperationManager
 	Logger logging.Logger
 }
@@ -152,9 +168,18 @@ func (m *Motor) Reconfigure(ctx context.Context, deps resource.Dependencies, con
 		m.MaxRPM = defaultMaxRpm
 	}
 
-	if newConf.Encoder != "" {
-		m.TicksPerRotation = newConf.TicksPerRotation
+	m.TicksPerRotation = newConf.TicksPerRotation
+	m.physics = nil
+	if newConf.Physics != nil {
+		m.clock = sim.Clock()
+		m.physics = sim.NewMotor(*newConf.Physics, m.MaxRPM, m.clock)
+	}
 
+	if m.Encoder != nil {
+		// detach the encoder from the simulated motor of the previous config, if any
+		m.Encoder.SetPositionSource(nil)
+	}
+	if newConf.Encoder != "" {
 		e, err := encoder.FromDependencies(deps, newConf.Encoder)
 		if err != nil {
 			return err
@@ -165,8 +190,18 @@ func (m *Motor) Reconfigure(ctx context.Context, deps resource.Dependencies, con
 		}
 		m.Encoder = fakeEncoder
 		m.PositionReporting = true
+		if m.physics != nil {
+			physics, ticksPerRotation := m.physics, float64(m.TicksPerRotation)
+			m.Encoder.SetPositionSource(func() float64 {
+				position, _ := physics.State()
+				return position * ticksPerRotation
+			})
+		} else {
+			m.Encoder.SetPositionSource(nil)
+		}
 	} else {
-		m.PositionReporting = false
+		m.Encoder = nil
+		m.PositionReporting = m.physics != nil
 	}
 	m.DirFlip = false
 	if newConf.DirectionFlip {
@@ -180,6 +215,15 @@ func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (flo
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.Encoder == nil && m.physics != nil {
+		position, _ := m.physics.State()
+		if m.TicksPerRotation > 0 {
+			// report what an encoder on the motor would
+			ticks := float64(m.TicksPerRotation)
+			position = math.Floor(position*ticks) / ticks
+		}
+		return position, nil
+	}
 	if m.Encoder == nil {
 		return 0, errors.New("encoder is not defined")
 	}
@@ -212,6 +256,10 @@ func (m *Motor) SetPower(ctx context.Context, powerPct float64, extra map[string
 	m.Logger.CDebugf(ctx, "Motor SetPower %f", powerPct)
 	m.setPowerPct(powerPct)
 
+	if m.physics != nil {
+		m.physics.SetPower(powerPct)
+		return nil
+	}
 	if m.Encoder != nil {
 		if m.TicksPerRotation <= 0 {
 			return errors.New("need positive nonzero TicksPerRotation")
@@ -299,6 +347,17 @@ func (m *Motor) GoFor(ctx context.Context, rpm, revolutions float64, extra map[s
 
 	powerPct, waitDur, dir := goForMath(m.MaxRPM, rpm, revolutions)
 
+	if m.physics != nil {
+		curPos, err := m.Position(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := m.SetPower(ctx, powerPct, nil); err != nil {
+			return err
+		}
+		return m.runToPosition(ctx, curPos+dir*math.Abs(revolutions), dir)
+	}
+
 	var finalPos float64
 	if m.Encoder != nil {
 		curPos, err := m.Position(ctx, nil)
@@ -328,7 +387,7 @@ func (m *Motor) GoFor(ctx context.Context, rpm, revolutions float64, extra map[s
 
 // GoTo sets the given direction and an arbitrary power percentage for now.
 func (m *Motor) GoTo(ctx context.Context, rpm, pos float64, extra map[string]interface{}) error {
-	if m.Encoder == nil {
+	if m.Encoder == nil && m.physics == nil {
 		return errors.New("encoder is not defined")
 	}
 
@@ -351,13 +410,17 @@ func (m *Motor) GoTo(ctx context.Context, rpm, pos float64, extra map[string]int
 
 	revolutions := pos - curPos
 
-	powerPct, waitDur, _ := goForMath(m.MaxRPM, math.Abs(rpm), revolutions)
+	powerPct, waitDur, dir := goForMath(m.MaxRPM, math.Abs(rpm), revolutions)
 
 	err = m.SetPower(ctx, powerPct, nil)
 	if err != nil {
 		return err
 	}
 
+	if m.physics != nil {
+		return m.runToPosition(ctx, pos, dir)
+	}
+
 	if m.OpMgr.NewTimedWaitOp(ctx, waitDur) {
 		err = m.Stop(ctx, nil)
 		if err != nil {
@@ -370,6 +433,27 @@ func (m *Motor) GoTo(ctx context.Context, rpm, pos float64, extra map[string]int
 	return nil
 }
 
+// runToPosition waits for the simulated motor to pass the target position in the given direction
+// and then stops it, leaving it to coast. It returns early without stopping if the operation is
+// cancelled.
+func (m *Motor) runToPosition(ctx context.Context, target, dir float64) error {
+	ctx, done := m.OpMgr.New(ctx)
+	defer done()
+
+	ticker := m.clock.Ticker(simPollInterval)
+	defer ticker.Stop()
+	for {
+		if position, _ := m.physics.State(); (target-position)*dir <= 0 {
+			return m.Stop(ctx, nil)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 // SetRPM instructs the motor to move at the specified RPM indefinitely.
 func (m *Motor) SetRPM(ctx context.Context, rpm float64, extra map[string]interface{}) error {
 	warning, err := checkSpeed(rpm, m.MaxRPM)
@@ -391,6 +475,10 @@ func (m *Motor) Stop(ctx context.Context, extra map[string]interface{}) error {
 
 	m.Logger.CDebug(ctx, "Motor Stopped")
 	m.setPowerPct(0.0)
+	if m.physics != nil {
+		m.physics.SetPower(0)
+		return nil
+	}
 	if m.Encoder != nil {
 		err := m.Encoder.SetSpeed(ctx, 0.0)
 		if err != nil {
@@ -402,6 +490,10 @@ func (m *Motor) Stop(ctx context.Context, extra map[string]interface{}) error {
 
 // ResetZeroPosition resets the zero position.
 func (m *Motor) ResetZeroPosition(ctx context.Context, offset float64, extra map[string]interface{}) error {
+	if m.Encoder == nil && m.physics != nil {
+		m.physics.SetPosition(-1 * offset)
+		return nil
+	}
 	if m.Encoder == nil {
 		return errors.New("encoder is not defined")
 	}
@@ -425,9 +517,14 @@ func (m *Motor) IsPowered(ctx context.Context, extra map[string]interface{}) (bo
 	return math.Abs(m.powerPct) >= 0.005, m.powerPct, nil
 }
 
-// IsMoving returns if the motor is pretending to be moving or not.
+// IsMoving returns if the motor is pretending to be moving or not. A simulated motor is moving
+// until it has coasted to a stop.
 func (m *Motor) IsMoving(ctx context.Context) (bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.physics != nil {
+		_, rpm := m.physics.State()
+		return rpm != 0 || math.Abs(m.powerPct) >= 0.005, nil
+	}
 	return math.Abs(m.powerPct) >= 0.005, nil
 }
@@ -6,14 +6,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
 
+	"go.viam.com/rdk/components/encoder"
 	"go.viam.com/rdk/components/encoder/fake"
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
 )
 
 func TestMotorInit(t *testing.T) {
@@ -256,3 +259,122 @@ func TestGoForMath(t *testing.T) {
 	test.That(t, waitDur, test.ShouldEqual, 0)
 	test.That(t, dir, test.ShouldEqual, 0)
 }
+
+// stepUntilDone steps the clock until f returns.
+func stepUntilDone(clk *clock.Mock, f func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f()
+	}()
+	for {
+		select {
+		case err := <-errCh:
+			return err
+		default:
+			clk.Add(simPollInterval)
+		}
+	}
+}
+
+func TestPhysics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	clk := clock.NewMock()
+	defer sim.SetClock(clk)()
+
+	conf := &Config{MaxRPM: 60, TicksPerRotation: 100, Physics: &sim.MotorConfig{}}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldBeEmpty)
+	_, err = (&Config{Physics: &sim.MotorConfig{FrictionTorqueNm: 1}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	mRaw, err := NewMotor(ctx, nil, resource.Config{Name: "m", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	m := mRaw.(*Motor)
+	properties, err := m.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, properties.PositionReporting, test.ShouldBeTrue)
+
+	// the motor takes time to come up to speed, and friction keeps it below the commanded speed
+	test.That(t, m.SetRPM(ctx, 30, nil), test.ShouldBeNil)
+	clk.Add(50 * time.Millisecond)
+	_, rpm := m.physics.State()
+	test.That(t, rpm, test.ShouldBeBetween, 5, 25)
+	clk.Add(time.Second)
+	_, rpm = m.physics.State()
+	test.That(t, rpm, test.ShouldBeBetween, 25, 30)
+
+	// positions are in whole encoder ticks
+	pos, err := m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldBeGreaterThan, 0)
+	test.That(t, pos*100, test.ShouldEqual, float64(int(pos*100)))
+
+	// stopping leaves the motor coasting until friction stops it
+	test.That(t, m.Stop(ctx, nil), test.ShouldBeNil)
+	moving, err := m.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+	clk.Add(time.Second)
+	moving, err = m.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	// GoFor stops once it passes the goal and overshoots a little
+	test.That(t, m.ResetZeroPosition(ctx, 0, nil), test.ShouldBeNil)
+	err = stepUntilDone(clk, func() error { return m.GoFor(ctx, 60, 2, nil) })
+	test.That(t, err, test.ShouldBeNil)
+	clk.Add(time.Second)
+	pos, err = m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldBeBetween, 2, 2.5)
+
+	err = stepUntilDone(clk, func() error { return m.GoTo(ctx, 30, -1, nil) })
+	test.That(t, err, test.ShouldBeNil)
+	clk.Add(time.Second)
+	pos, err = m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldBeBetween, -1.5, -1)
+
+	// cancelling a move leaves the motor running
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	test.That(t, m.GoFor(cancelCtx, 60, 10, nil), test.ShouldBeNil)
+	powered, _, err := m.IsPowered(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, powered, test.ShouldBeTrue)
+	test.That(t, m.Stop(ctx, nil), test.ShouldBeNil)
+}
+
+func TestPhysicsEncoder(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	clk := clock.NewMock()
+	defer sim.SetClock(clk)()
+
+	enc, err := fake.NewEncoder(ctx, resource.Config{Name: "e", API: encoder.API, ConvertedAttributes: &fake.Config{}}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	deps := resource.Dependencies{enc.Name(): enc}
+	conf := &Config{MaxRPM: 60, TicksPerRotation: 100, Encoder: "e", Physics: &sim.MotorConfig{}}
+	m, err := NewMotor(ctx, deps, resource.Config{Name: "m", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the encoder counts the simulated motor's ticks
+	test.That(t, m.SetPower(ctx, 1, nil), test.ShouldBeNil)
+	clk.Add(2 * time.Second)
+	ticks, _, err := enc.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	pos, err := m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, ticks/100)
+	test.That(t, pos, test.ShouldBeBetween, 1.5, 2)
+
+	// without physics the encoder no longer follows the simulated motor
+	conf = &Config{MaxRPM: 60, TicksPerRotation: 100, Encoder: "e"}
+	test.That(t, m.Reconfigure(ctx, deps, resource.Config{Name: "m", ConvertedAttributes: conf}), test.ShouldBeNil)
+	clk.Add(2 * time.Second)
+	after, _, err := enc.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, after, test.ShouldEqual, ticks)
+}
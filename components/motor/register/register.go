@@ -3,6 +3,7 @@ package register
 
 import (
 	// for motors.
+	_ "go.viam.com/rdk/components/motor/canopen"
 	_ "go.viam.com/rdk/components/motor/dimensionengineering"
 	_ "go.viam.com/rdk/components/motor/dmc4000"
 	_ "go.viam.com/rdk/components/motor/fake"
@@ -0,0 +1,605 @@
+// Package ekf implements a movementsensor that fuses the readings of other movement sensors, such
+// as GPS receivers, IMUs and wheeled odometry, with an extended Kalman filter. It estimates the
+// position, heading, speed and turn rate of a vehicle moving on the ground, weighting each reading
+// by the accuracy its sensor reports.
+package ekf
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
+	"go.viam.com/rdk/utils/contextutils"
+)
+
+var model = resource.DefaultModelFamily.WithModel("ekf")
+
+const (
+	defaultUpdateRateHz           = 10.
+	defaultPositionStdDevM        = 3.
+	defaultCompassStdDevDegs      = 5.
+	defaultLinearVelocityStdDev   = 0.1 // m/s
+	defaultAngularVelocityStdDev  = 2.  // degs/s
+	defaultAccelerationStdDev     = 1.  // m/s^2
+	defaultYawAccelerationStdDev  = 30. // degs/s^2
+	altitudeDriftStdDev           = 0.5 // m/s
+	verticalToHorizontalStdDevs   = 1.5
+	unknownPositionStdDevM        = 1e3
+	unknownHeadingStdDevRads      = math.Pi
+	unknownSpeedStdDevMPerSec     = 1.
+	unknownYawRateStdDevRadPerSec = 0.5
+)
+
+// errNoPositionFix is returned for the position until a position source reports a fix.
+var errNoPositionFix = errors.New("no position fix has been received yet")
+
+// Config is the config of the ekf movement_sensor model. Each list names the movement sensors whose
+// readings of that kind are fused.
+//
+// Each sample of a source is fused once. Sources report when a reading was sampled with the
+// contextutils.TimeReceivedMetadataKey (or TimeRequestedMetadataKey) response metadata, and a
+// reading sampled no later than the last one fused of its kind from the same source is skipped.
+// Readings from sources that report no sample time, such as those on the same machine, are fused at
+// every update, so update_rate_hz should not be faster than such sources sample.
+type Config struct {
+	// Position sources, such as GPS receivers.
+	Position []string `json:"position,omitempty"`
+	// CompassHeading sources, such as IMUs with magnetometers.
+	CompassHeading []string `json:"compass_heading,omitempty"`
+	// LinearVelocity sources report the velocity of the vehicle in its own frame, with Y forward,
+	// such as wheeled odometry.
+	LinearVelocity []string `json:"linear_velocity,omitempty"`
+	// GroundVelocity sources report the velocity over the ground with X east and Y north, such as
+	// GPS receivers.
+	GroundVelocity []string `json:"ground_velocity,omitempty"`
+	// AngularVelocity sources, such as IMUs or wheeled odometry.
+	AngularVelocity []string `json:"angular_velocity,omitempty"`
+
+	UpdateRateHz float64 `json:"update_rate_hz,omitempty"`
+
+	// The standard deviations of readings from sources that do not report their accuracy.
+	PositionStdDevM                 float64 `json:"position_std_dev_m,omitempty"`
+	CompassStdDevDegs               float64 `json:"compass_std_dev_degs,omitempty"`
+	LinearVelocityStdDevMPerSec     float64 `json:"linear_velocity_std_dev_m_per_sec,omitempty"`
+	AngularVelocityStdDevDegsPerSec float64 `json:"angular_velocity_std_dev_degs_per_sec,omitempty"`
+
+	// The standard deviations of the accelerations of the vehicle, which is assumed to move at a
+	// constant speed and turn rate between readings.
+	AccelerationStdDevMPerSecPerSec       float64 `json:"acceleration_std_dev_m_per_sec_per_sec,omitempty"`
+	YawAccelerationStdDevDegsPerSecPerSec float64 `json:"yaw_acceleration_std_dev_degs_per_sec_per_sec,omitempty"`
+}
+
+// Validate validates the ekf model's configuration.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	var deps []string
+	deps = append(deps, cfg.Position...)
+	deps = append(deps, cfg.CompassHeading...)
+	deps = append(deps, cfg.LinearVelocity...)
+	deps = append(deps, cfg.GroundVelocity...)
+	deps = append(deps, cfg.AngularVelocity...)
+	if len(deps) == 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("at least one source movement sensor is required"))
+	}
+	for name, value := range map[string]float64{
+		"update_rate_hz":                                cfg.UpdateRateHz,
+		"position_std_dev_m":                            cfg.PositionStdDevM,
+		"compass_std_dev_degs":                          cfg.CompassStdDevDegs,
+		"linear_velocity_std_dev_m_per_sec":             cfg.LinearVelocityStdDevMPerSec,
+		"angular_velocity_std_dev_degs_per_sec":         cfg.AngularVelocityStdDevDegsPerSec,
+		"acceleration_std_dev_m_per_sec_per_sec":        cfg.AccelerationStdDevMPerSecPerSec,
+		"yaw_acceleration_std_dev_degs_per_sec_per_sec": cfg.YawAccelerationStdDevDegsPerSecPerSec,
+	} {
+		if value < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("%s cannot be negative", name))
+		}
+	}
+	return deps, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API, model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{
+			Constructor: newEKF,
+		})
+}
+
+func newEKF(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (
+	movementsensor.MovementSensor, error,
+) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	e, err := makeEKF(deps, conf.ResourceName(), newConf, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	updateRate := newConf.UpdateRateHz
+	if updateRate == 0 {
+		updateRate = defaultUpdateRateHz
+	}
+	e.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / updateRate))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				e.step(ctx, now)
+			}
+		}
+	})
+	return e, nil
+}
+
+// stdDevs are the standard deviations assumed for readings from sources that do not report their
+// accuracy.
+type stdDevs struct {
+	position        float64 // m
+	compass         float64 // rad
+	linearVelocity  float64 // m/s
+	angularVelocity float64 // rad/s
+}
+
+type ekf struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger logging.Logger
+
+	position        []movementsensor.MovementSensor
+	compass         []movementsensor.MovementSensor
+	linearVelocity  []movementsensor.MovementSensor
+	groundVelocity  []movementsensor.MovementSensor
+	angularVelocity []movementsensor.MovementSensor
+	defaults        stdDevs
+	noise           processNoise
+	workers         *goutils.StoppableWorkers
+
+	// lastSamples and sourceErrors are only used by step, which is never called concurrently.
+	lastSamples  map[string]time.Time
+	sourceErrors map[string]string
+
+	mu           sync.Mutex
+	filter       *filter
+	lastStep     time.Time
+	origin       *geo.Point
+	headingKnown bool
+	nmeaFix      int32
+}
+
+func makeEKF(deps resource.Dependencies, name resource.Name, conf *Config, logger logging.Logger) (*ekf, error) {
+	orDefault := func(value, defaultValue float64) float64 {
+		if value == 0 {
+			return defaultValue
+		}
+		return value
+	}
+	e := &ekf{
+		Named:  name.AsNamed(),
+		logger: logger,
+		defaults: stdDevs{
+			position:        orDefault(conf.PositionStdDevM, defaultPositionStdDevM),
+			compass:         rutils.DegToRad(orDefault(conf.CompassStdDevDegs, defaultCompassStdDevDegs)),
+			linearVelocity:  orDefault(conf.LinearVelocityStdDevMPerSec, defaultLinearVelocityStdDev),
+			angularVelocity: rutils.DegToRad(orDefault(conf.AngularVelocityStdDevDegsPerSec, defaultAngularVelocityStdDev)),
+		},
+		noise: processNoise{
+			altitude:     altitudeDriftStdDev,
+			acceleration: orDefault(conf.AccelerationStdDevMPerSecPerSec, defaultAccelerationStdDev),
+			yawAcceleration: rutils.DegToRad(
+				orDefault(conf.YawAccelerationStdDevDegsPerSecPerSec, defaultYawAccelerationStdDev)),
+		},
+		lastSamples:  map[string]time.Time{},
+		sourceErrors: map[string]string{},
+		filter: newFilter([stateSize]float64{
+			unknownPositionStdDevM, unknownPositionStdDevM, unknownPositionStdDevM,
+			unknownHeadingStdDevRads, unknownSpeedStdDevMPerSec, unknownYawRateStdDevRadPerSec,
+		}),
+		nmeaFix: -1,
+	}
+
+	var err error
+	for _, sources := range []struct {
+		names []string
+		to    *[]movementsensor.MovementSensor
+	}{
+		{conf.Position, &e.position},
+		{conf.CompassHeading, &e.compass},
+		{conf.LinearVelocity, &e.linearVelocity},
+		{conf.GroundVelocity, &e.groundVelocity},
+		{conf.AngularVelocity, &e.angularVelocity},
+	} {
+		for _, sourceName := range sources.names {
+			var ms movementsensor.MovementSensor
+			ms, err = movementsensor.FromDependencies(deps, sourceName)
+			if err != nil {
+				return nil, err
+			}
+			*sources.to = append(*sources.to, ms)
+		}
+	}
+	return e, nil
+}
+
+func sourceKey(source movementsensor.MovementSensor, kind string) string {
+	return source.Name().ShortName() + "/" + kind
+}
+
+// sourceFailed logs errors reading a source, only when the error changes rather than on every step.
+func (e *ekf) sourceFailed(ctx context.Context, source movementsensor.MovementSensor, kind string, err error) {
+	key := sourceKey(source, kind)
+	if err == nil {
+		delete(e.sourceErrors, key)
+		return
+	}
+	if e.sourceErrors[key] != err.Error() {
+		e.logger.CWarnw(ctx, "failed to read source movement sensor",
+			"source", source.Name().ShortName(), "reading", kind, "error", err)
+		e.sourceErrors[key] = err.Error()
+	}
+}
+
+// A reading from a source fuses it into the state.
+type reading func(f *filter) error
+
+// readContext returns a context to read a source with, which collects the response metadata the
+// source reports its sample time with.
+func readContext(ctx context.Context) (context.Context, map[string][]string) {
+	return contextutils.ContextWithMetadata(context.WithValue(ctx, contextutils.MetadataContextKey, nil))
+}
+
+// fresh returns whether a reading of the kind from the source, whose response metadata is md, is a
+// new sample rather than one already fused. Readings without a sample time are always new.
+func (e *ekf) fresh(source movementsensor.MovementSensor, kind string, md map[string][]string) bool {
+	var sampled time.Time
+	for _, key := range []string{contextutils.TimeReceivedMetadataKey, contextutils.TimeRequestedMetadataKey} {
+		if values := md[key]; len(values) != 0 {
+			var err error
+			if sampled, err = time.Parse(time.RFC3339Nano, values[0]); err == nil {
+				break
+			}
+		}
+	}
+	if sampled.IsZero() {
+		return true
+	}
+	key := sourceKey(source, kind)
+	if last, ok := e.lastSamples[key]; ok && !sampled.After(last) {
+		return false
+	}
+	e.lastSamples[key] = sampled
+	return true
+}
+
+// readPositions reads the position sources.
+func (e *ekf) readPositions(ctx context.Context) ([]*geo.Point, []float64, [][2]float64, int32) {
+	var points []*geo.Point
+	var altitudes []float64
+	var sourceStdDevs [][2]float64
+	nmeaFix := int32(-1)
+	for _, source := range e.position {
+		readCtx, md := readContext(ctx)
+		point, altitude, err := source.Position(readCtx, nil)
+		e.sourceFailed(ctx, source, "position", err)
+		if err != nil || point == nil || movementsensor.IsPositionNaN(point) || movementsensor.IsZeroPosition(point) {
+			continue
+		}
+		if !e.fresh(source, "position", md) {
+			continue
+		}
+
+		acc, err := source.Accuracy(ctx, nil)
+		if err != nil {
+			acc = nil
+		}
+		if acc != nil && acc.NmeaFix == 0 {
+			// the source has no fix
+			continue
+		}
+		horizontal, vertical := positionStdDevs(acc, e.defaults.position)
+		if acc != nil && acc.NmeaFix > nmeaFix {
+			nmeaFix = acc.NmeaFix
+		}
+		if math.IsNaN(altitude) {
+			altitude, vertical = math.NaN(), 0
+		}
+		points = append(points, point)
+		altitudes = append(altitudes, altitude)
+		sourceStdDevs = append(sourceStdDevs, [2]float64{horizontal, vertical})
+	}
+	return points, altitudes, sourceStdDevs, nmeaFix
+}
+
+// The typical range errors of GPS fixes of each NMEA fix quality, which multiplied by the dilution
+// of precision estimate the error of a position.
+var rangeErrorsByFix = map[int32]float64{
+	1: 4,    // GPS
+	2: 1.5,  // DGPS
+	4: 0.03, // RTK fixed
+	5: 0.5,  // RTK float
+}
+
+// positionStdDevs estimates the horizontal and vertical standard deviations of a position from the
+// accuracy its source reports.
+func positionStdDevs(acc *movementsensor.Accuracy, defaultStdDev float64) (float64, float64) {
+	if acc == nil {
+		return defaultStdDev, verticalToHorizontalStdDevs * defaultStdDev
+	}
+	rangeError, ok := rangeErrorsByFix[acc.NmeaFix]
+	if !ok {
+		return defaultStdDev, verticalToHorizontalStdDevs * defaultStdDev
+	}
+	horizontal := rangeError
+	if validAccuracy(acc.Hdop) {
+		horizontal = rangeError * float64(acc.Hdop)
+	}
+	vertical := verticalToHorizontalStdDevs * horizontal
+	if validAccuracy(acc.Vdop) {
+		vertical = rangeError * float64(acc.Vdop)
+	}
+	return horizontal, vertical
+}
+
+func validAccuracy(value float32) bool {
+	return value > 0 && !math.IsNaN(float64(value)) && !math.IsInf(float64(value), 0)
+}
+
+// read reads every source, returning the readings to fuse.
+func (e *ekf) read(ctx context.Context) []reading {
+	var readings []reading
+
+	points, altitudes, positionStdDevs, nmeaFix := e.readPositions(ctx)
+	for i := range points {
+		point, altitude, stdDevs := points[i], altitudes[i], positionStdDevs[i]
+		readings = append(readings, func(f *filter) error {
+			if e.origin == nil {
+				// the first fix is the origin of the filter's frame
+				e.origin = point
+				f.set(stateEast, 0, stdDevs[0])
+				f.set(stateNorth, 0, stdDevs[0])
+				if !math.IsNaN(altitude) {
+					f.set(stateAltitude, altitude, stdDevs[1])
+				}
+				return nil
+			}
+			east, north := e.toLocal(point)
+			if math.IsNaN(altitude) {
+				return f.update(observe(f, []float64{east, north}, []int{stateEast, stateNorth},
+					[]float64{stdDevs[0], stdDevs[0]}))
+			}
+			return f.update(observe(f, []float64{east, north, altitude}, []int{stateEast, stateNorth, stateAltitude},
+				[]float64{stdDevs[0], stdDevs[0], stdDevs[1]}))
+		})
+	}
+	if nmeaFix >= 0 {
+		readings = append(readings, func(f *filter) error {
+			e.nmeaFix = nmeaFix
+			return nil
+		})
+	}
+
+	for _, source := range e.compass {
+		readCtx, md := readContext(ctx)
+		heading, err := source.CompassHeading(readCtx, nil)
+		e.sourceFailed(ctx, source, "compass_heading", err)
+		if err != nil || math.IsNaN(heading) || !e.fresh(source, "compass_heading", md) {
+			continue
+		}
+		stdDev := e.defaults.compass
+		if acc, err := source.Accuracy(ctx, nil); err == nil && acc != nil && validAccuracy(acc.CompassDegreeError) {
+			stdDev = rutils.DegToRad(float64(acc.CompassDegreeError))
+		}
+		heading = normalizeAngle(rutils.DegToRad(heading))
+		readings = append(readings, func(f *filter) error {
+			if !e.headingKnown {
+				e.headingKnown = true
+				f.set(stateHeading, heading, stdDev)
+				return nil
+			}
+			return f.update(observe(f, []float64{heading}, []int{stateHeading}, []float64{stdDev}))
+		})
+	}
+
+	for _, source := range e.linearVelocity {
+		readCtx, md := readContext(ctx)
+		velocity, err := source.LinearVelocity(readCtx, nil)
+		e.sourceFailed(ctx, source, "linear_velocity", err)
+		if err != nil || math.IsNaN(velocity.Y) || !e.fresh(source, "linear_velocity", md) {
+			continue
+		}
+		readings = append(readings, func(f *filter) error {
+			return f.update(observe(f, []float64{velocity.Y}, []int{stateSpeed}, []float64{e.defaults.linearVelocity}))
+		})
+	}
+
+	for _, source := range e.groundVelocity {
+		readCtx, md := readContext(ctx)
+		velocity, err := source.LinearVelocity(readCtx, nil)
+		e.sourceFailed(ctx, source, "ground_velocity", err)
+		if err != nil || math.IsNaN(velocity.X) || math.IsNaN(velocity.Y) || !e.fresh(source, "ground_velocity", md) {
+			continue
+		}
+		readings = append(readings, func(f *filter) error {
+			return f.update(observeGroundVelocity(f, velocity.X, velocity.Y, e.defaults.linearVelocity))
+		})
+	}
+
+	for _, source := range e.angularVelocity {
+		readCtx, md := readContext(ctx)
+		velocity, err := source.AngularVelocity(readCtx, nil)
+		e.sourceFailed(ctx, source, "angular_velocity", err)
+		if err != nil || math.IsNaN(velocity.Z) || !e.fresh(source, "angular_velocity", md) {
+			continue
+		}
+		// angular velocities are counterclockwise about Z, and the yaw rate is clockwise like the heading
+		yawRate := -rutils.DegToRad(velocity.Z)
+		readings = append(readings, func(f *filter) error {
+			return f.update(observe(f, []float64{yawRate}, []int{stateYawRate}, []float64{e.defaults.angularVelocity}))
+		})
+	}
+	return readings
+}
+
+// step predicts the state at the given time, and fuses the latest readings of every source.
+func (e *ekf) step(ctx context.Context, now time.Time) {
+	// sources are read without holding the lock so that slow sources don't block callers
+	readings := e.read(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.lastStep.IsZero() {
+		e.filter.predict(now.Sub(e.lastStep).Seconds(), e.noise)
+	}
+	e.lastStep = now
+	for _, r := range readings {
+		if err := r(e.filter); err != nil {
+			e.logger.CDebugw(ctx, "failed to fuse reading", "error", err)
+		}
+	}
+}
+
+// toLocal returns the meters east and north of the origin of a point.
+func (e *ekf) toLocal(point *geo.Point) (float64, float64) {
+	distance := e.origin.GreatCircleDistance(point) * 1000
+	bearing := rutils.DegToRad(e.origin.BearingTo(point))
+	return distance * math.Sin(bearing), distance * math.Cos(bearing)
+}
+
+// fromLocal returns the point at the given meters east and north of the origin.
+func (e *ekf) fromLocal(east, north float64) *geo.Point {
+	return e.origin.PointAtDistanceAndBearing(math.Hypot(east, north)/1000, rutils.RadToDeg(math.Atan2(east, north)))
+}
+
+func (e *ekf) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	if len(e.position) == 0 {
+		return geo.NewPoint(math.NaN(), math.NaN()), math.NaN(), movementsensor.ErrMethodUnimplementedPosition
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.origin == nil {
+		return geo.NewPoint(math.NaN(), math.NaN()), math.NaN(), errNoPositionFix
+	}
+	return e.fromLocal(e.filter.state(stateEast), e.filter.state(stateNorth)), e.filter.state(stateAltitude), nil
+}
+
+func (e *ekf) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if !e.headingSupported() {
+		return math.NaN(), movementsensor.ErrMethodUnimplementedCompassHeading
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	heading := rutils.RadToDeg(e.filter.state(stateHeading))
+	if heading < 0 {
+		heading += 360
+	}
+	return heading, nil
+}
+
+// LinearVelocity returns the velocity of the vehicle in its own frame, with Y forward.
+func (e *ekf) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	if !e.linearVelocitySupported() {
+		return r3.Vector{X: math.NaN(), Y: math.NaN(), Z: math.NaN()}, movementsensor.ErrMethodUnimplementedLinearVelocity
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return r3.Vector{Y: e.filter.state(stateSpeed)}, nil
+}
+
+func (e *ekf) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	if !e.angularVelocitySupported() {
+		return spatialmath.AngularVelocity{X: math.NaN(), Y: math.NaN(), Z: math.NaN()},
+			movementsensor.ErrMethodUnimplementedAngularVelocity
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return spatialmath.AngularVelocity{Z: -rutils.RadToDeg(e.filter.state(stateYawRate))}, nil
+}
+
+func (e *ekf) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return r3.Vector{X: math.NaN(), Y: math.NaN(), Z: math.NaN()}, movementsensor.ErrMethodUnimplementedLinearAcceleration
+}
+
+func (e *ekf) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	nanOri := spatialmath.NewOrientationVector()
+	nanOri.OX = math.NaN()
+	nanOri.OY = math.NaN()
+	nanOri.OZ = math.NaN()
+	nanOri.Theta = math.NaN()
+	return nanOri, movementsensor.ErrMethodUnimplementedOrientation
+}
+
+// The heading is observed by compasses, and by the direction of travel of positions and ground
+// velocities.
+func (e *ekf) headingSupported() bool {
+	return len(e.compass) > 0 || len(e.groundVelocity) > 0 || len(e.position) > 0
+}
+
+func (e *ekf) linearVelocitySupported() bool {
+	return len(e.linearVelocity) > 0 || len(e.groundVelocity) > 0 || len(e.position) > 0
+}
+
+func (e *ekf) angularVelocitySupported() bool {
+	return len(e.angularVelocity) > 0 || len(e.compass) > 0
+}
+
+// Accuracy returns the standard deviations of the estimates, and the best NMEA fix quality of the
+// position sources.
+func (e *ekf) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	acc := movementsensor.UnimplementedOptionalAccuracies()
+	acc.AccuracyMap = map[string]float32{}
+	if len(e.position) > 0 && e.origin != nil {
+		acc.AccuracyMap["position_std_dev_m"] = float32(math.Hypot(e.filter.stdDev(stateEast), e.filter.stdDev(stateNorth)))
+		acc.AccuracyMap["altitude_std_dev_m"] = float32(e.filter.stdDev(stateAltitude))
+		acc.NmeaFix = e.nmeaFix
+	}
+	if e.headingSupported() {
+		headingStdDev := float32(rutils.RadToDeg(e.filter.stdDev(stateHeading)))
+		acc.AccuracyMap["compass_std_dev_degs"] = headingStdDev
+		acc.CompassDegreeError = headingStdDev
+	}
+	if e.linearVelocitySupported() {
+		acc.AccuracyMap["linear_velocity_std_dev_m_per_sec"] = float32(e.filter.stdDev(stateSpeed))
+	}
+	if e.angularVelocitySupported() {
+		acc.AccuracyMap["angular_velocity_std_dev_degs_per_sec"] = float32(rutils.RadToDeg(e.filter.stdDev(stateYawRate)))
+	}
+	return acc, nil
+}
+
+func (e *ekf) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	return &movementsensor.Properties{
+		PositionSupported:        len(e.position) > 0,
+		CompassHeadingSupported:  e.headingSupported(),
+		LinearVelocitySupported:  e.linearVelocitySupported(),
+		AngularVelocitySupported: e.angularVelocitySupported(),
+	}, nil
+}
+
+func (e *ekf) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return movementsensor.DefaultAPIReadings(ctx, e, extra)
+}
+
+func (e *ekf) Close(context.Context) error {
+	// the source movement sensors are closed by their own drivers
+	if e.workers != nil {
+		e.workers.Stop()
+	}
+	return nil
+}
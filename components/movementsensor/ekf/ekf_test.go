@@ -0,0 +1,408 @@
+package ekf
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	datapb "go.viam.com/api/app/data/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/components/movementsensor/replay"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/internal/cloud"
+	cloudinject "go.viam.com/rdk/internal/testutils/inject"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	rutils "go.viam.com/rdk/utils"
+	"go.viam.com/rdk/utils/contextutils"
+)
+
+const (
+	testRobotID        = "robot_id"
+	testLocationID     = "location_id"
+	testOrganizationID = "organization_id"
+	numSteps           = 120
+)
+
+var testStart = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// groundTruth is the state of a rover at each second of a drive: east, then turning south, then
+// straight again.
+type groundTruth struct {
+	point    *geo.Point
+	altitude float64
+	heading  float64 // degs
+	speed    float64 // m/s
+	yawRate  float64 // degs/s, clockwise
+}
+
+func drive() []groundTruth {
+	origin := geo.NewPoint(40.7, -74.0)
+	var truth []groundTruth
+	east, north, heading := 0., 0., 90.
+	for i := 0; i < numSteps; i++ {
+		yawRate := 0.
+		if i >= 40 && i < 70 {
+			yawRate = 3
+		}
+		speed := 1.
+		truth = append(truth, groundTruth{
+			point: origin.PointAtDistanceAndBearing(
+				math.Hypot(east, north)/1000, rutils.RadToDeg(math.Atan2(east, north))),
+			altitude: 10,
+			heading:  heading,
+			speed:    speed,
+			yawRate:  yawRate,
+		})
+		// integrate the next second finely
+		for j := 0; j < 100; j++ {
+			sin, cos := math.Sincos(rutils.DegToRad(heading))
+			east += speed * sin * 0.01
+			north += speed * cos * 0.01
+			heading += yawRate * 0.01
+		}
+	}
+	return truth
+}
+
+// recording holds the data captured from each source movement sensor, by method.
+type recording map[string]map[string][]*structpb.Struct
+
+func record(truth []groundTruth) recording {
+	noise := rand.New(rand.NewSource(1))
+	rec := recording{
+		"gps":      map[string][]*structpb.Struct{},
+		"imu":      map[string][]*structpb.Struct{},
+		"odometry": map[string][]*structpb.Struct{},
+	}
+	for _, state := range truth {
+		point := state.point.PointAtDistanceAndBearing(math.Abs(noise.NormFloat64()*2)/1000, noise.Float64()*360)
+		rec["gps"]["Position"] = append(rec["gps"]["Position"], mustStruct(map[string]interface{}{
+			"coordinate": map[string]interface{}{"latitude": point.Lat(), "longitude": point.Lng()},
+			"altitude_m": state.altitude + noise.NormFloat64()*3,
+		}))
+		rec["imu"]["CompassHeading"] = append(rec["imu"]["CompassHeading"], mustStruct(map[string]interface{}{
+			"value": math.Mod(state.heading+noise.NormFloat64()*5+360, 360),
+		}))
+		rec["imu"]["AngularVelocity"] = append(rec["imu"]["AngularVelocity"], mustStruct(map[string]interface{}{
+			"angular_velocity": map[string]interface{}{"x": 0, "y": 0, "z": -state.yawRate + noise.NormFloat64()},
+		}))
+		rec["odometry"]["LinearVelocity"] = append(rec["odometry"]["LinearVelocity"], mustStruct(map[string]interface{}{
+			"linear_velocity": map[string]interface{}{"x": 0, "y": state.speed + noise.NormFloat64()*0.05, "z": 0},
+		}))
+	}
+	return rec
+}
+
+func mustStruct(m map[string]interface{}) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// mockDataServiceServer serves a recording to replay movement sensors, one captured reading per
+// second.
+type mockDataServiceServer struct {
+	datapb.UnimplementedDataServiceServer
+	recording recording
+}
+
+func (s *mockDataServiceServer) TabularDataByFilter(ctx context.Context, req *datapb.TabularDataByFilterRequest,
+) (*datapb.TabularDataByFilterResponse, error) {
+	filter := req.DataRequest.GetFilter()
+	data := s.recording[filter.ComponentName][filter.Method]
+	next := 0
+	if last := req.DataRequest.GetLast(); last != "" {
+		index, err := strconv.Atoi(last)
+		if err != nil {
+			return nil, err
+		}
+		next = index + 1
+	}
+	if next >= len(data) {
+		return nil, replay.ErrEndOfDataset
+	}
+
+	resp := &datapb.TabularDataByFilterResponse{}
+	for i := next; i < len(data) && i < next+int(req.DataRequest.GetLimit()); i++ {
+		captured := testStart.Add(time.Duration(i) * time.Second)
+		resp.Data = append(resp.Data, &datapb.TabularData{
+			Data:          data[i],
+			TimeRequested: timestamppb.New(captured),
+			TimeReceived:  timestamppb.New(captured),
+		})
+		resp.Last = strconv.Itoa(i)
+	}
+	return resp, nil
+}
+
+// newReplaySources creates replay movement sensors playing back the recording of each source.
+func newReplaySources(ctx context.Context, t *testing.T, rec recording, logger logging.Logger) resource.Dependencies {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		ctx,
+		&datapb.DataService_ServiceDesc,
+		&mockDataServiceServer{recording: rec},
+		datapb.RegisterDataServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	t.Cleanup(func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+	})
+
+	registration, ok := resource.LookupRegistration(movementsensor.API, resource.DefaultModelFamily.WithModel("replay"))
+	test.That(t, ok, test.ShouldBeTrue)
+	batchSize := uint64(numSteps)
+	deps := resource.Dependencies{}
+	for source := range rec {
+		// each replay movement sensor closes its own connection
+		conn, err := viamgrpc.Dial(ctx, listener.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		cloudDeps := resource.Dependencies{
+			cloud.InternalServiceName: &cloudinject.CloudConnectionService{Named: cloud.InternalServiceName.AsNamed(), Conn: conn},
+		}
+		res, err := registration.Constructor(ctx, cloudDeps, resource.Config{
+			Name:  source,
+			API:   movementsensor.API,
+			Model: resource.DefaultModelFamily.WithModel("replay"),
+			ConvertedAttributes: &replay.Config{
+				Source:         source,
+				RobotID:        testRobotID,
+				LocationID:     testLocationID,
+				OrganizationID: testOrganizationID,
+				BatchSize:      &batchSize,
+			},
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() {
+			test.That(t, res.Close(ctx), test.ShouldBeNil)
+		})
+		deps[movementsensor.Named(source)] = res
+	}
+	return deps
+}
+
+func testConfig() *Config {
+	return &Config{
+		Position:                        []string{"gps"},
+		CompassHeading:                  []string{"imu"},
+		AngularVelocity:                 []string{"imu"},
+		LinearVelocity:                  []string{"odometry"},
+		PositionStdDevM:                 2,
+		CompassStdDevDegs:               5,
+		AngularVelocityStdDevDegsPerSec: 1,
+		LinearVelocityStdDevMPerSec:     0.05,
+		// the rover drives gently
+		AccelerationStdDevMPerSecPerSec:       0.2,
+		YawAccelerationStdDevDegsPerSecPerSec: 2,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	deps, err := testConfig().Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"gps", "imu", "odometry", "imu"})
+
+	_, err = (&Config{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	conf := testConfig()
+	conf.CompassStdDevDegs = -1
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestReplayedDrive(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	truth := drive()
+	rec := record(truth)
+	deps := newReplaySources(ctx, t, rec, logger)
+
+	e, err := makeEKF(deps, movementsensor.Named("fused"), testConfig(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, e.Close(ctx), test.ShouldBeNil)
+	}()
+
+	props, err := e.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props, test.ShouldResemble, &movementsensor.Properties{
+		PositionSupported:        true,
+		CompassHeadingSupported:  true,
+		LinearVelocitySupported:  true,
+		AngularVelocitySupported: true,
+	})
+	_, _, err = e.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeError, errNoPositionFix)
+
+	var rawPositionErrs, fusedPositionErrs, rawHeadingErrs, fusedHeadingErrs []float64
+	consistent := 0
+	for i, state := range truth {
+		e.step(ctx, testStart.Add(time.Duration(i)*time.Second))
+		if i < 20 {
+			// let the filter settle
+			continue
+		}
+
+		point, altitude, err := e.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		heading, err := e.CompassHeading(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		acc, err := e.Accuracy(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		rawPoint := geo.NewPoint(
+			rec["gps"]["Position"][i].Fields["coordinate"].GetStructValue().Fields["latitude"].GetNumberValue(),
+			rec["gps"]["Position"][i].Fields["coordinate"].GetStructValue().Fields["longitude"].GetNumberValue())
+		rawPositionErrs = append(rawPositionErrs, rawPoint.GreatCircleDistance(state.point)*1000)
+		positionErr := point.GreatCircleDistance(state.point) * 1000
+		fusedPositionErrs = append(fusedPositionErrs, positionErr)
+		rawHeadingErrs = append(rawHeadingErrs,
+			headingDiff(rec["imu"]["CompassHeading"][i].Fields["value"].GetNumberValue(), state.heading))
+		fusedHeadingErrs = append(fusedHeadingErrs, headingDiff(heading, state.heading))
+		test.That(t, altitude, test.ShouldAlmostEqual, 10, 3)
+
+		// the reported accuracy is consistent with the actual error
+		if positionErr < 3*float64(acc.AccuracyMap["position_std_dev_m"]) &&
+			headingDiff(heading, state.heading) < 3*float64(acc.CompassDegreeError) {
+			consistent++
+		}
+		test.That(t, acc.AccuracyMap["position_std_dev_m"], test.ShouldBeLessThan, 2)
+		test.That(t, acc.CompassDegreeError, test.ShouldBeLessThan, 5)
+	}
+
+	// fusing the sources is far more accurate than any one of them
+	test.That(t, rms(fusedPositionErrs), test.ShouldBeLessThan, rms(rawPositionErrs)/2)
+	test.That(t, rms(fusedHeadingErrs), test.ShouldBeLessThan, rms(rawHeadingErrs)/2)
+	test.That(t, consistent, test.ShouldBeGreaterThanOrEqualTo, (numSteps-20)*9/10)
+
+	velocity, err := e.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, velocity.Y, test.ShouldAlmostEqual, 1, 0.1)
+	angularVelocity, err := e.AngularVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, angularVelocity.Z, test.ShouldAlmostEqual, 0, 1)
+
+	// the end of the recording leaves the estimate in place
+	e.step(ctx, testStart.Add(numSteps*time.Second))
+	readings, err := e.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["linear_velocity"], test.ShouldHaveSameTypeAs, r3.Vector{})
+	test.That(t, readings["compass"], test.ShouldAlmostEqual, truth[numSteps-1].heading, 3)
+}
+
+func headingDiff(a, b float64) float64 {
+	return math.Abs(rutils.RadToDeg(normalizeAngle(rutils.DegToRad(a - b))))
+}
+
+func rms(values []float64) float64 {
+	sum := 0.
+	for _, value := range values {
+		sum += value * value
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func TestPositionStdDevs(t *testing.T) {
+	horizontal, vertical := positionStdDevs(nil, 3)
+	test.That(t, horizontal, test.ShouldEqual, 3)
+	test.That(t, vertical, test.ShouldEqual, 4.5)
+
+	horizontal, vertical = positionStdDevs(movementsensor.UnimplementedOptionalAccuracies(), 3)
+	test.That(t, horizontal, test.ShouldEqual, 3)
+	test.That(t, vertical, test.ShouldEqual, 4.5)
+
+	// an RTK fix is far more accurate than the default
+	horizontal, vertical = positionStdDevs(&movementsensor.Accuracy{NmeaFix: 4, Hdop: 2, Vdop: 3}, 3)
+	test.That(t, horizontal, test.ShouldAlmostEqual, 0.06)
+	test.That(t, vertical, test.ShouldAlmostEqual, 0.09)
+
+	horizontal, vertical = positionStdDevs(&movementsensor.Accuracy{NmeaFix: 1, Hdop: float32(math.NaN())}, 3)
+	test.That(t, horizontal, test.ShouldEqual, 4)
+	test.That(t, vertical, test.ShouldEqual, 6)
+}
+
+func TestFreshReadings(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	// sampled is the sample time the remote source reports, and the local source reports none
+	var sampled time.Time
+	report := func(ctx context.Context) {
+		if md, ok := ctx.Value(contextutils.MetadataContextKey).(map[string][]string); ok {
+			md[contextutils.TimeReceivedMetadataKey] = []string{sampled.Format(time.RFC3339Nano)}
+		}
+	}
+	newSource := func(name string, reportsTime bool) *inject.MovementSensor {
+		source := inject.NewMovementSensor(name)
+		source.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			if reportsTime {
+				report(ctx)
+			}
+			return geo.NewPoint(40, -74), 0, nil
+		}
+		source.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			if reportsTime {
+				report(ctx)
+			}
+			return 90, nil
+		}
+		source.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+			if reportsTime {
+				report(ctx)
+			}
+			return r3.Vector{Y: 1}, nil
+		}
+		source.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+			if reportsTime {
+				report(ctx)
+			}
+			return spatialmath.AngularVelocity{Z: 10}, nil
+		}
+		source.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+			return nil, nil
+		}
+		return source
+	}
+	remote, local := newSource("remote", true), newSource("local", false)
+	deps := resource.Dependencies{remote.Name(): remote, local.Name(): local}
+	sources := []string{remote.Name().ShortName(), local.Name().ShortName()}
+	e, err := makeEKF(deps, movementsensor.Named("ekf"), &Config{
+		Position:        sources,
+		CompassHeading:  sources,
+		LinearVelocity:  sources,
+		GroundVelocity:  sources,
+		AngularVelocity: sources,
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// five kinds of readings from both sources
+	sampled = testStart
+	test.That(t, e.read(ctx), test.ShouldHaveLength, 10)
+
+	// the same sample of the remote source is not fused again, but local readings always are
+	test.That(t, e.read(ctx), test.ShouldHaveLength, 5)
+
+	// a new sample is fused even though its readings are identical to the last
+	sampled = testStart.Add(time.Second)
+	test.That(t, e.read(ctx), test.ShouldHaveLength, 10)
+}
@@ -0,0 +1,200 @@
+package ekf
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// The elements of the filter's state. Positions are in meters east and north of the origin and
+// altitude is in meters. The heading is in radians clockwise from north, like a compass heading,
+// and the yaw rate is in radians per second in the same direction. The speed is in meters per
+// second along the heading.
+const (
+	stateEast = iota
+	stateNorth
+	stateAltitude
+	stateHeading
+	stateSpeed
+	stateYawRate
+	stateSize
+)
+
+// processNoise holds the standard deviations of the unmodeled changes to the state, per second.
+type processNoise struct {
+	altitude        float64 // m/s
+	acceleration    float64 // m/s^2
+	yawAcceleration float64 // rad/s^2
+}
+
+// filter is an extended Kalman filter tracking a vehicle moving on the ground with a constant
+// speed and turn rate between updates.
+type filter struct {
+	x *mat.VecDense
+	p *mat.Dense
+}
+
+// newFilter returns a filter at rest at the origin, with the given initial standard deviations.
+func newFilter(stdDevs [stateSize]float64) *filter {
+	p := mat.NewDense(stateSize, stateSize, nil)
+	for i, stdDev := range stdDevs {
+		p.Set(i, i, stdDev*stdDev)
+	}
+	return &filter{x: mat.NewVecDense(stateSize, nil), p: p}
+}
+
+func (f *filter) state(i int) float64 {
+	return f.x.AtVec(i)
+}
+
+func (f *filter) stdDev(i int) float64 {
+	return math.Sqrt(f.p.At(i, i))
+}
+
+// set sets an element of the state and its standard deviation, clearing its correlation with the
+// others.
+func (f *filter) set(i int, value, stdDev float64) {
+	f.x.SetVec(i, value)
+	for j := 0; j < stateSize; j++ {
+		f.p.Set(i, j, 0)
+		f.p.Set(j, i, 0)
+	}
+	f.p.Set(i, i, stdDev*stdDev)
+}
+
+// predict advances the state by dt seconds.
+func (f *filter) predict(dt float64, noise processNoise) {
+	if dt <= 0 {
+		return
+	}
+	heading, speed := f.state(stateHeading), f.state(stateSpeed)
+	sin, cos := math.Sincos(heading)
+
+	f.x.SetVec(stateEast, f.state(stateEast)+speed*sin*dt)
+	f.x.SetVec(stateNorth, f.state(stateNorth)+speed*cos*dt)
+	f.x.SetVec(stateHeading, normalizeAngle(heading+f.state(stateYawRate)*dt))
+
+	jacobian := identity()
+	jacobian.Set(stateEast, stateHeading, speed*cos*dt)
+	jacobian.Set(stateEast, stateSpeed, sin*dt)
+	jacobian.Set(stateNorth, stateHeading, -speed*sin*dt)
+	jacobian.Set(stateNorth, stateSpeed, cos*dt)
+	jacobian.Set(stateHeading, stateYawRate, dt)
+
+	var p mat.Dense
+	p.Product(jacobian, f.p, jacobian.T())
+
+	// Accelerations are modeled as white noise, which integrate into the speed and yaw rate, and
+	// twice into the position and heading.
+	accel := noise.acceleration * noise.acceleration
+	yawAccel := noise.yawAcceleration * noise.yawAcceleration
+	dt3 := dt * dt * dt / 3
+	p.Set(stateEast, stateEast, p.At(stateEast, stateEast)+accel*dt3)
+	p.Set(stateNorth, stateNorth, p.At(stateNorth, stateNorth)+accel*dt3)
+	p.Set(stateAltitude, stateAltitude, p.At(stateAltitude, stateAltitude)+noise.altitude*noise.altitude*dt)
+	p.Set(stateHeading, stateHeading, p.At(stateHeading, stateHeading)+yawAccel*dt3)
+	p.Set(stateSpeed, stateSpeed, p.At(stateSpeed, stateSpeed)+accel*dt)
+	p.Set(stateYawRate, stateYawRate, p.At(stateYawRate, stateYawRate)+yawAccel*dt)
+	f.p = &p
+}
+
+// measurement is an observation of the state: z is the measured value, predicted is the value
+// expected from the current state, and jacobian is the derivative of the prediction with respect
+// to the state. Angles are rows whose innovation is wrapped to [-pi, pi).
+type measurement struct {
+	z         []float64
+	predicted []float64
+	jacobian  *mat.Dense
+	stdDevs   []float64
+	angles    []bool
+}
+
+// update corrects the state with a measurement.
+func (f *filter) update(m measurement) error {
+	n := len(m.z)
+	innovation := mat.NewVecDense(n, nil)
+	r := mat.NewDense(n, n, nil)
+	for i := range m.z {
+		diff := m.z[i] - m.predicted[i]
+		if m.angles != nil && m.angles[i] {
+			diff = normalizeAngle(diff)
+		}
+		innovation.SetVec(i, diff)
+		r.Set(i, i, m.stdDevs[i]*m.stdDevs[i])
+	}
+
+	var s mat.Dense
+	s.Product(m.jacobian, f.p, m.jacobian.T())
+	s.Add(&s, r)
+	var sInv mat.Dense
+	if err := sInv.Inverse(&s); err != nil {
+		return errors.Wrap(err, "measurement covariance is singular")
+	}
+	var gain mat.Dense
+	gain.Product(f.p, m.jacobian.T(), &sInv)
+
+	var correction mat.VecDense
+	correction.MulVec(&gain, innovation)
+	f.x.AddVec(f.x, &correction)
+	f.x.SetVec(stateHeading, normalizeAngle(f.state(stateHeading)))
+
+	// The Joseph form keeps the covariance symmetric and positive definite despite rounding.
+	var kh mat.Dense
+	kh.Mul(&gain, m.jacobian)
+	ikh := identity()
+	ikh.Sub(ikh, &kh)
+	var p, krk mat.Dense
+	p.Product(ikh, f.p, ikh.T())
+	krk.Product(&gain, r, gain.T())
+	p.Add(&p, &krk)
+	f.p = &p
+	return nil
+}
+
+// observe returns the measurement of the given elements of the state, directly observed.
+func observe(f *filter, z []float64, elements []int, stdDevs []float64) measurement {
+	jacobian := mat.NewDense(len(elements), stateSize, nil)
+	predicted := make([]float64, len(elements))
+	angles := make([]bool, len(elements))
+	for i, element := range elements {
+		jacobian.Set(i, element, 1)
+		predicted[i] = f.state(element)
+		angles[i] = element == stateHeading
+	}
+	return measurement{z: z, predicted: predicted, jacobian: jacobian, stdDevs: stdDevs, angles: angles}
+}
+
+// observeGroundVelocity returns the measurement of the velocity east and north.
+func observeGroundVelocity(f *filter, east, north, stdDev float64) measurement {
+	heading, speed := f.state(stateHeading), f.state(stateSpeed)
+	sin, cos := math.Sincos(heading)
+	jacobian := mat.NewDense(2, stateSize, nil)
+	jacobian.Set(0, stateHeading, speed*cos)
+	jacobian.Set(0, stateSpeed, sin)
+	jacobian.Set(1, stateHeading, -speed*sin)
+	jacobian.Set(1, stateSpeed, cos)
+	return measurement{
+		z:         []float64{east, north},
+		predicted: []float64{speed * sin, speed * cos},
+		jacobian:  jacobian,
+		stdDevs:   []float64{stdDev, stdDev},
+	}
+}
+
+func identity() *mat.Dense {
+	m := mat.NewDense(stateSize, stateSize, nil)
+	for i := 0; i < stateSize; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// normalizeAngle wraps an angle in radians to [-pi, pi).
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle+math.Pi, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle - math.Pi
+}
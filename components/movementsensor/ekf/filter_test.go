@@ -0,0 +1,84 @@
+package ekf
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestNormalizeAngle(t *testing.T) {
+	for angle, normalized := range map[float64]float64{
+		0:               0,
+		math.Pi / 2:     math.Pi / 2,
+		math.Pi:         -math.Pi,
+		3 * math.Pi / 2: -math.Pi / 2,
+		-5 * math.Pi:    -math.Pi,
+		7:               7 - 2*math.Pi,
+	} {
+		test.That(t, normalizeAngle(angle), test.ShouldAlmostEqual, normalized)
+	}
+}
+
+func TestPredict(t *testing.T) {
+	f := newFilter([stateSize]float64{1, 1, 1, 0.1, 0.1, 0.01})
+	f.set(stateHeading, math.Pi/2, 0.1)
+	f.set(stateSpeed, 2, 0.1)
+
+	// heading east at 2 m/s
+	f.predict(1.5, processNoise{})
+	test.That(t, f.state(stateEast), test.ShouldAlmostEqual, 3)
+	test.That(t, f.state(stateNorth), test.ShouldAlmostEqual, 0)
+	// the heading and speed uncertainty spreads into the position
+	test.That(t, f.stdDev(stateEast), test.ShouldBeGreaterThan, 1)
+	test.That(t, f.stdDev(stateNorth), test.ShouldBeGreaterThan, 1)
+
+	// turning clockwise through south
+	f.set(stateYawRate, math.Pi/2, 0.01)
+	f.predict(2, processNoise{})
+	test.That(t, f.state(stateHeading), test.ShouldAlmostEqual, -math.Pi/2)
+
+	// process noise grows the uncertainty of every element
+	before := [stateSize]float64{}
+	for i := range before {
+		before[i] = f.stdDev(i)
+	}
+	f.predict(1, processNoise{altitude: 1, acceleration: 1, yawAcceleration: 1})
+	for i := range before {
+		test.That(t, f.stdDev(i), test.ShouldBeGreaterThan, before[i])
+	}
+
+	// nothing changes when no time passes
+	east := f.state(stateEast)
+	f.predict(0, processNoise{altitude: 1})
+	test.That(t, f.state(stateEast), test.ShouldEqual, east)
+}
+
+func TestUpdate(t *testing.T) {
+	f := newFilter([stateSize]float64{10, 10, 10, 1, 1, 1})
+
+	// equally uncertain measurements and estimates average
+	err := f.update(observe(f, []float64{4, -2}, []int{stateEast, stateNorth}, []float64{10, 10}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, f.state(stateEast), test.ShouldAlmostEqual, 2)
+	test.That(t, f.state(stateNorth), test.ShouldAlmostEqual, -1)
+	test.That(t, f.stdDev(stateEast), test.ShouldAlmostEqual, 10/math.Sqrt2)
+
+	// angles are corrected the short way around
+	f.set(stateHeading, math.Pi-0.1, 0.1)
+	err = f.update(observe(f, []float64{-math.Pi + 0.1}, []int{stateHeading}, []float64{0.1}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, math.Abs(f.state(stateHeading)), test.ShouldAlmostEqual, math.Pi)
+
+	// a ground velocity observes both the heading and speed, though it can't tell driving forward
+	// from reversing in the opposite direction
+	f.set(stateHeading, 0.5, 1)
+	f.set(stateSpeed, 1, 1)
+	for i := 0; i < 50; i++ {
+		f.predict(0.1, processNoise{acceleration: 1, yawAcceleration: 1})
+		err = f.update(observeGroundVelocity(f, 3, 0, 0.1))
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, f.state(stateHeading), test.ShouldAlmostEqual, math.Pi/2, 0.01)
+	test.That(t, f.state(stateSpeed), test.ShouldAlmostEqual, 3, 0.01)
+}
@@ -3,21 +3,41 @@ package fake
 
 import (
 	"context"
+	"math"
 
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
 
+	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
 	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
 )
 
 var model = resource.DefaultModelFamily.WithModel("fake")
 
 // Config is used for converting fake movementsensor attributes.
 type Config struct {
-	resource.TriviallyValidateConfig
+	// Base, when set, names a fake base configured with physics whose simulated motion the sensor
+	// reports, rather than fixed readings.
+	Base string `json:"base,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.Base != "" {
+		return []string{cfg.Base}, nil
+	}
+	return nil, nil
+}
+
+// simulatedBase is implemented by the fake base.
+type simulatedBase interface {
+	Drive() *sim.DifferentialDrive
 }
 
 func init() {
@@ -30,10 +50,29 @@ func init() {
 // NewMovementSensor makes a new fake movement sensor.
 func NewMovementSensor(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
 ) (movementsensor.MovementSensor, error) {
-	return &MovementSensor{
+	f := &MovementSensor{
 		Named:  conf.ResourceName().AsNamed(),
 		logger: logger,
-	}, nil
+	}
+	if conf.ConvertedAttributes == nil {
+		return f, nil
+	}
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	if newConf.Base != "" {
+		b, err := base.FromDependencies(deps, newConf.Base)
+		if err != nil {
+			return nil, err
+		}
+		simulated, ok := b.(simulatedBase)
+		if !ok || simulated.Drive() == nil {
+			return nil, errors.Errorf("base %q is not a fake base configured with physics", newConf.Base)
+		}
+		f.drive = simulated.Drive()
+	}
+	return f, nil
 }
 
 // MovementSensor implements is a fake movement sensor interface.
@@ -41,16 +80,30 @@ type MovementSensor struct {
 	resource.Named
 	resource.AlwaysRebuild
 	logger logging.Logger
+
+	// drive is the simulated base the sensor tracks, if any. It starts at origin facing north.
+	drive *sim.DifferentialDrive
 }
 
+var origin = geo.NewPoint(40.7, -73.98)
+
 // Position gets the position of a fake movementsensor.
 func (f *MovementSensor) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
-	p := geo.NewPoint(40.7, -73.98)
+	if f.drive != nil {
+		x, y, _ := f.drive.Pose()
+		bearing := rdkutils.RadToDeg(math.Atan2(x, y))
+		return origin.PointAtDistanceAndBearing(math.Hypot(x, y)/1000, bearing), 50.5, nil
+	}
+	p := geo.NewPoint(origin.Lat(), origin.Lng())
 	return p, 50.5, nil
 }
 
 // LinearVelocity gets the linear velocity of a fake movementsensor.
 func (f *MovementSensor) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	if f.drive != nil {
+		linear, _ := f.drive.Velocities()
+		return r3.Vector{Y: linear}, nil
+	}
 	return r3.Vector{Y: 5.4}, nil
 }
 
@@ -61,16 +114,28 @@ func (f *MovementSensor) LinearAcceleration(ctx context.Context, extra map[strin
 
 // AngularVelocity gets the angular velocity of a fake movementsensor.
 func (f *MovementSensor) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	if f.drive != nil {
+		_, angular := f.drive.Velocities()
+		return spatialmath.AngularVelocity{Z: rdkutils.RadToDeg(angular)}, nil
+	}
 	return spatialmath.AngularVelocity{Z: 1}, nil
 }
 
 // CompassHeading gets the compass headings of a fake movementsensor.
 func (f *MovementSensor) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if f.drive != nil {
+		_, _, theta := f.drive.Pose()
+		return math.Mod(360-rdkutils.RadToDeg(theta), 360), nil
+	}
 	return 25, nil
 }
 
 // Orientation gets the orientation of a fake movementsensor.
 func (f *MovementSensor) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	if f.drive != nil {
+		_, _, theta := f.drive.Pose()
+		return &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: rdkutils.RadToDeg(theta)}, nil
+	}
 	return spatialmath.NewZeroOrientation(), nil
 }
 
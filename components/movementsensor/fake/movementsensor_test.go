@@ -0,0 +1,78 @@
+package fake
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	fakebase "go.viam.com/rdk/components/base/fake"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/sim"
+)
+
+func TestSimulatedBase(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	clk := clock.NewMock()
+	defer sim.SetClock(clk)()
+
+	conf := &Config{Base: "base"}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"base"})
+
+	// the base must be simulated
+	b, err := fakebase.NewBase(ctx, nil, resource.Config{Name: "base", API: base.API}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	msConf := resource.Config{Name: "ms", API: movementsensor.API, ConvertedAttributes: conf}
+	_, err = NewMovementSensor(ctx, resource.Dependencies{b.Name(): b}, msConf, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	b, err = fakebase.NewBase(ctx, nil, resource.Config{
+		Name:                "base",
+		API:                 base.API,
+		ConvertedAttributes: &fakebase.Config{Physics: &fakebase.PhysicsConfig{}},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	ms, err := NewMovementSensor(ctx, resource.Dependencies{b.Name(): b}, msConf, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	start, _, err := ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// driving forward heads north
+	test.That(t, b.SetPower(ctx, r3.Vector{Y: 0.5}, r3.Vector{}, nil), test.ShouldBeNil)
+	clk.Add(2 * time.Second)
+	linear, err := ms.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, linear.Y, test.ShouldBeBetween, 0.25, 0.3)
+	x, y, _ := b.(*fakebase.Base).Drive().Pose()
+	pos, _, err := ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, start.GreatCircleDistance(pos)*1000, test.ShouldAlmostEqual, math.Hypot(x, y), 0.001)
+	test.That(t, start.BearingTo(pos), test.ShouldAlmostEqual, 0, 0.1)
+	heading, err := ms.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldAlmostEqual, 0)
+
+	// turning left turns counterclockwise, toward west
+	test.That(t, b.SetPower(ctx, r3.Vector{}, r3.Vector{Z: 0.5}, nil), test.ShouldBeNil)
+	clk.Add(500 * time.Millisecond)
+	angular, err := ms.AngularVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, angular.Z, test.ShouldBeGreaterThan, 0)
+	heading, err = ms.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldBeBetween, 180, 360)
+	orientation, err := ms.Orientation(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, orientation.OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 360-heading)
+}
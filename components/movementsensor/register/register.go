@@ -5,6 +5,7 @@ import (
 	// Load all movementsensors.
 	_ "go.viam.com/rdk/components/movementsensor/adxl345"
 	_ "go.viam.com/rdk/components/movementsensor/dualgps"
+	_ "go.viam.com/rdk/components/movementsensor/ekf"
 	_ "go.viam.com/rdk/components/movementsensor/fake"
 	_ "go.viam.com/rdk/components/movementsensor/gpsnmea"
 	_ "go.viam.com/rdk/components/movementsensor/gpsrtk"
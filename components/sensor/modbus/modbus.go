@@ -0,0 +1,229 @@
+// Package modbus implements a generic Modbus RTU or TCP sensor, whose readings are mapped from the
+// holding and input registers of the device.
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/modbusutils"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("modbus")
+
+// Config is used for converting config attributes.
+type Config struct {
+	Connection    modbusutils.ConnectionConfig `json:"connection"`
+	Registers     []modbusutils.RegisterConfig `json:"registers"`
+	PollingGroups []PollingGroupConfig         `json:"polling_groups,omitempty"`
+}
+
+// PollingGroupConfig names registers that are read in the background every IntervalMs, rather than
+// when readings are requested. Readings of these registers are the latest values polled.
+type PollingGroupConfig struct {
+	Name       string   `json:"name"`
+	IntervalMs int      `json:"interval_ms"`
+	Registers  []string `json:"registers"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if err := conf.Connection.Validate(path + ".connection"); err != nil {
+		return nil, err
+	}
+	if len(conf.Registers) == 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "registers")
+	}
+	if err := modbusutils.ValidateRegisters(path, conf.Registers); err != nil {
+		return nil, err
+	}
+
+	registers := map[string]bool{}
+	for _, reg := range conf.Registers {
+		registers[reg.Name] = true
+	}
+	grouped := map[string]string{}
+	for i, group := range conf.PollingGroups {
+		groupPath := fmt.Sprintf("%s.polling_groups.%d", path, i)
+		if group.Name == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(groupPath, "name")
+		}
+		if group.IntervalMs <= 0 {
+			return nil, resource.NewConfigValidationError(groupPath, errors.New("interval_ms must be positive"))
+		}
+		for _, name := range group.Registers {
+			if !registers[name] {
+				return nil, resource.NewConfigValidationError(groupPath, errors.Errorf("unknown register %q", name))
+			}
+			if other, ok := grouped[name]; ok {
+				return nil, resource.NewConfigValidationError(groupPath,
+					errors.Errorf("register %q is already polled by group %q", name, other))
+			}
+			grouped[name] = group.Name
+		}
+	}
+	return nil, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		sensor.API,
+		model,
+		resource.Registration[sensor.Sensor, *Config]{
+			Constructor: func(
+				ctx context.Context,
+				deps resource.Dependencies,
+				conf resource.Config,
+				logger logging.Logger,
+			) (sensor.Sensor, error) {
+				newConf, err := resource.NativeConfig[*Config](conf)
+				if err != nil {
+					return nil, err
+				}
+				client, err := modbusutils.NewClient(newConf.Connection)
+				if err != nil {
+					return nil, err
+				}
+				return newSensor(conf.ResourceName(), newConf, client, logger), nil
+			},
+		})
+}
+
+// pollingGroup holds the latest values read by a polling group.
+type pollingGroup struct {
+	name     string
+	interval time.Duration
+	blocks   []modbusutils.ReadBlock
+
+	mu     sync.Mutex
+	polled bool
+	values map[string]float64
+	err    error
+}
+
+// latest returns the values of the last poll, or polls the group if it has not been yet.
+func (g *pollingGroup) latest(client *modbusutils.Client) (map[string]float64, error) {
+	g.mu.Lock()
+	polled, values, err := g.polled, g.values, g.err
+	g.mu.Unlock()
+	if !polled {
+		return g.poll(client)
+	}
+	return values, err
+}
+
+func (g *pollingGroup) poll(client *modbusutils.Client) (map[string]float64, error) {
+	values, err := client.Read(g.blocks)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.polled = true
+	g.values, g.err = values, err
+	return values, err
+}
+
+// Sensor is a Modbus device whose registers are reported as readings.
+type Sensor struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger logging.Logger
+
+	client   *modbusutils.Client
+	onDemand []modbusutils.ReadBlock
+	groups   []*pollingGroup
+	workers  *goutils.StoppableWorkers
+}
+
+func newSensor(name resource.Name, conf *Config, client *modbusutils.Client, logger logging.Logger) *Sensor {
+	registers := map[string]modbusutils.RegisterConfig{}
+	for _, reg := range conf.Registers {
+		registers[reg.Name] = reg
+	}
+
+	s := &Sensor{
+		Named:   name.AsNamed(),
+		logger:  logger,
+		client:  client,
+		workers: goutils.NewBackgroundStoppableWorkers(),
+	}
+	for _, groupConf := range conf.PollingGroups {
+		var groupRegisters []modbusutils.RegisterConfig
+		for _, regName := range groupConf.Registers {
+			groupRegisters = append(groupRegisters, registers[regName])
+			delete(registers, regName)
+		}
+		group := &pollingGroup{
+			name:     groupConf.Name,
+			interval: time.Duration(groupConf.IntervalMs) * time.Millisecond,
+			blocks:   modbusutils.PlanReads(groupRegisters),
+		}
+		s.groups = append(s.groups, group)
+		s.workers.Add(func(ctx context.Context) {
+			s.pollLoop(ctx, group)
+		})
+	}
+
+	var onDemand []modbusutils.RegisterConfig
+	for _, reg := range conf.Registers {
+		if _, ok := registers[reg.Name]; ok {
+			onDemand = append(onDemand, reg)
+		}
+	}
+	s.onDemand = modbusutils.PlanReads(onDemand)
+	return s
+}
+
+func (s *Sensor) pollLoop(ctx context.Context, group *pollingGroup) {
+	ticker := time.NewTicker(group.interval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		_, err := group.poll(s.client)
+		// only log when the error changes, rather than on every poll
+		if err != nil && (lastErr == nil || err.Error() != lastErr.Error()) {
+			s.logger.CWarnw(ctx, "failed to poll modbus registers", "group", group.name, "error", err)
+		}
+		lastErr = err
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Readings returns the latest values of polled registers, and reads the others from the device.
+func (s *Sensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	readings := map[string]interface{}{}
+	for _, group := range s.groups {
+		values, err := group.latest(s.client)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to poll group %q", group.name)
+		}
+		for name, value := range values {
+			readings[name] = value
+		}
+	}
+
+	values, err := s.client.Read(s.onDemand)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range values {
+		readings[name] = value
+	}
+	return readings, nil
+}
+
+// Close stops polling and closes the connection to the device.
+func (s *Sensor) Close(ctx context.Context) error {
+	s.workers.Stop()
+	return s.client.Close()
+}
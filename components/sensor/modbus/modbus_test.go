@@ -0,0 +1,120 @@
+package modbus
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/modbusutils"
+	mbtestutils "go.viam.com/rdk/components/modbusutils/testutils"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+)
+
+func testConfig(address string) *Config {
+	return &Config{
+		Connection: modbusutils.ConnectionConfig{TCPAddress: address},
+		Registers: []modbusutils.RegisterConfig{
+			{Name: "flow_rate", Table: modbusutils.TableInput, Address: 0, Type: modbusutils.TypeFloat32},
+			{Name: "total", Table: modbusutils.TableInput, Address: 2, Type: modbusutils.TypeUint32},
+			{Name: "temperature", Address: 40, Scale: 0.1},
+		},
+		PollingGroups: []PollingGroupConfig{{Name: "fast", IntervalMs: 10, Registers: []string{"flow_rate", "total"}}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	conf := testConfig("127.0.0.1:502")
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, bad := range []func(conf *Config){
+		func(conf *Config) { conf.Connection = modbusutils.ConnectionConfig{} },
+		func(conf *Config) { conf.Registers = nil },
+		func(conf *Config) { conf.Registers[0].Type = "int8" },
+		func(conf *Config) { conf.PollingGroups[0].Name = "" },
+		func(conf *Config) { conf.PollingGroups[0].IntervalMs = 0 },
+		func(conf *Config) { conf.PollingGroups[0].Registers = []string{"pressure"} },
+		func(conf *Config) {
+			conf.PollingGroups = append(conf.PollingGroups, PollingGroupConfig{Name: "slow", IntervalMs: 1000, Registers: []string{"total"}})
+		},
+	} {
+		conf := testConfig("127.0.0.1:502")
+		bad(conf)
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestReadings(t *testing.T) {
+	ctx := context.Background()
+	server, err := mbtestutils.NewServer("127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	}()
+	server.SetInputRegisters(0, 0x4120, 0x0000, 0x0001, 0x0000)
+	server.SetHoldingRegisters(40, 215)
+
+	conf := testConfig(server.Address())
+	client, err := modbusutils.NewClient(conf.Connection)
+	test.That(t, err, test.ShouldBeNil)
+	s := newSensor(sensor.Named("flow"), conf, client, logging.NewTestLogger(t))
+	defer func() {
+		test.That(t, s.Close(ctx), test.ShouldBeNil)
+	}()
+
+	readings, err := s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{
+		"flow_rate":   10.0,
+		"total":       65536.0,
+		"temperature": 21.5,
+	})
+
+	// polled registers are updated in the background, others when readings are requested
+	server.SetInputRegisters(0, 0x4130)
+	server.SetHoldingRegisters(40, 220)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		readings, err := s.Readings(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, readings["flow_rate"], test.ShouldEqual, 11.0)
+		test.That(tb, readings["temperature"], test.ShouldEqual, 22.0)
+	})
+	requests := server.Requests()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, server.Requests(), test.ShouldBeGreaterThan, requests+2)
+	})
+}
+
+func TestReadingsError(t *testing.T) {
+	ctx := context.Background()
+	server, err := mbtestutils.NewServer("127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	}()
+	// only the on demand register exists
+	server.SetHoldingRegisters(40, 215)
+
+	conf := testConfig(server.Address())
+	client, err := modbusutils.NewClient(conf.Connection)
+	test.That(t, err, test.ShouldBeNil)
+	s := newSensor(sensor.Named("flow"), conf, client, logging.NewTestLogger(t))
+	defer func() {
+		test.That(t, s.Close(ctx), test.ShouldBeNil)
+	}()
+
+	_, err = s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `failed to poll group "fast"`)
+
+	server.SetInputRegisters(0, 0, 0, 0, 0)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		readings, err := s.Readings(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, readings["total"], test.ShouldEqual, 0.0)
+	})
+}
@@ -6,5 +6,6 @@ import (
 	_ "go.viam.com/rdk/components/sensor/bme280"
 	_ "go.viam.com/rdk/components/sensor/ds18b20"
 	_ "go.viam.com/rdk/components/sensor/fake"
+	_ "go.viam.com/rdk/components/sensor/modbus"
 	_ "go.viam.com/rdk/components/sensor/sht3xd"
 )
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// AuthRoleConfig describes a role which limits the resources and methods authenticated callers may use.
+// When no roles are configured every authenticated caller may call every method. Once any role is configured,
+// a caller may only make the calls allowed by the roles bound to it, and callers bound to no role, including
+// unauthenticated ones, may make none.
+// A sample role in JSON form, which lets the holder of an API key view cameras and read sensors, is shown below.
+//
+//	"auth": {
+//		"roles": [
+//			{
+//				"name": "floor-operator",
+//				"entities": ["API_KEY_ID"],
+//				"allow": [
+//					{"apis": ["rdk:component:camera", "rdk:component:sensor"]},
+//					{"methods": ["/viam.robot.v1.RobotService/*"]}
+//				],
+//				"deny": [
+//					{"methods": ["DoCommand"]}
+//				]
+//			}
+//		]
+//	}
+type AuthRoleConfig struct {
+	Name string `json:"name"`
+	// Entities binds callers to the role by the entity they authenticated as: an API key ID, a TLS auth entity
+	// or the subject of an external JWT.
+	Entities []string `json:"entities,omitempty"`
+	// Claims binds callers authenticated with an external JWT whose rpc_auth_md claims contain every one of these
+	// key value pairs to the role.
+	Claims map[string]string `json:"claims,omitempty"`
+	// WebRTC binds callers connected over WebRTC to the role. The machine only knows that such callers were
+	// allowed to connect by the signaling server, not who they are, so Entities and Claims never bind them.
+	WebRTC bool `json:"webrtc,omitempty"`
+	// MaxLeasePriority is the highest priority callers bound to the role may lease resources with, and so the
+	// leases they may preempt (see the session package). Callers bound to several roles get the highest of them.
+	MaxLeasePriority int `json:"max_lease_priority,omitempty"`
+	// Allow lists the calls the role permits, and Deny the calls it forbids even when they are allowed.
+	Allow []AuthRuleConfig `json:"allow,omitempty"`
+	Deny  []AuthRuleConfig `json:"deny,omitempty"`
+}
+
+// AuthRuleConfig matches the calls it applies to. Each field is a list of glob patterns (see path.Match), of
+// which a call must match at least one; empty fields match every call.
+type AuthRuleConfig struct {
+	// APIs match the API of the resource being called, for example "rdk:component:arm" or "rdk:component:*".
+	APIs []string `json:"apis,omitempty"`
+	// Resources match the short name of the resource being called.
+	Resources []string `json:"resources,omitempty"`
+	// Methods match either the name of the method being called, for example "GetReadings", or its full gRPC
+	// name, for example "/viam.robot.v1.RobotService/ResourceNames".
+	Methods []string `json:"methods,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (config *AuthRoleConfig) Validate(rolePath string) error {
+	if config.Name == "" {
+		return resource.NewConfigValidationError(rolePath, errors.New("role must have a name"))
+	}
+	if len(config.Entities) == 0 && len(config.Claims) == 0 && !config.WebRTC {
+		return resource.NewConfigValidationError(rolePath, errors.New("role must bind at least one entity or claim, or webrtc"))
+	}
+	for idx, rule := range config.Allow {
+		if err := rule.Validate(fmt.Sprintf("%s.allow.%d", rolePath, idx)); err != nil {
+			return err
+		}
+	}
+	for idx, rule := range config.Deny {
+		if err := rule.Validate(fmt.Sprintf("%s.deny.%d", rolePath, idx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate ensures all parts of the config are valid.
+func (config *AuthRuleConfig) Validate(rulePath string) error {
+	for _, field := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"apis", config.APIs},
+		{"resources", config.Resources},
+		{"methods", config.Methods},
+	} {
+		for _, pattern := range field.patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return resource.NewConfigValidationError(fmt.Sprintf("%s.%s", rulePath, field.name),
+					errors.Wrapf(err, "invalid pattern %q", pattern))
+			}
+		}
+	}
+	return nil
+}
@@ -22,8 +22,10 @@ import (
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -40,6 +42,11 @@ type Config struct {
 	Auth       AuthConfig
 	Debug      bool
 	LogConfig  []logging.LoggerPatternConfig
+	Tracing    *tracing.Config
+
+	// OperationHistory configures the history of finished operations the robot persists. If unset, the history
+	// is kept in memory only.
+	OperationHistory *operation.HistoryConfig
 
 	ConfigFilePath string
 
@@ -68,6 +75,10 @@ type Config struct {
 	// EnableWebProfile turns pprof http server in localhost. Defaults to false.
 	EnableWebProfile bool
 
+	// EnableWebMetrics serves Prometheus metrics at /metrics on the web server, authenticated as gRPC calls are.
+	// Defaults to false.
+	EnableWebMetrics bool
+
 	// Revision contains the current revision of the config.
 	Revision string
 
@@ -91,7 +102,10 @@ type configData struct {
 	Debug               bool                          `json:"debug,omitempty"`
 	DisablePartialStart bool                          `json:"disable_partial_start"`
 	EnableWebProfile    bool                          `json:"enable_web_profile"`
+	EnableWebMetrics    bool                          `json:"enable_web_metrics,omitempty"`
 	LogConfig           []logging.LoggerPatternConfig `json:"log,omitempty"`
+	Tracing             *tracing.Config               `json:"tracing,omitempty"`
+	OperationHistory    *operation.HistoryConfig      `json:"operation_history,omitempty"`
 	Revision            string                        `json:"revision,omitempty"`
 }
 
@@ -134,6 +148,18 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		return err
 	}
 
+	if c.Tracing != nil {
+		if err := c.Tracing.Validate("tracing"); err != nil {
+			return err
+		}
+	}
+
+	if c.OperationHistory != nil {
+		if err := c.OperationHistory.Validate("operation_history"); err != nil {
+			return err
+		}
+	}
+
 	for idx := 0; idx < len(c.Modules); idx++ {
 		if err := c.Modules[idx].Validate(fmt.Sprintf("%s.%d", "modules", idx)); err != nil {
 			if c.DisablePartialStart {
@@ -297,7 +323,10 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.Debug = conf.Debug
 	c.DisablePartialStart = conf.DisablePartialStart
 	c.EnableWebProfile = conf.EnableWebProfile
+	c.EnableWebMetrics = conf.EnableWebMetrics
 	c.LogConfig = conf.LogConfig
+	c.Tracing = conf.Tracing
+	c.OperationHistory = conf.OperationHistory
 	c.Revision = conf.Revision
 
 	return nil
@@ -328,7 +357,10 @@ func (c Config) MarshalJSON() ([]byte, error) {
 		Debug:               c.Debug,
 		DisablePartialStart: c.DisablePartialStart,
 		EnableWebProfile:    c.EnableWebProfile,
+		EnableWebMetrics:    c.EnableWebMetrics,
 		LogConfig:           c.LogConfig,
+		Tracing:             c.Tracing,
+		OperationHistory:    c.OperationHistory,
 		Revision:            c.Revision,
 	})
 }
@@ -798,6 +830,7 @@ type AuthConfig struct {
 	Handlers           []AuthHandlerConfig `json:"handlers,omitempty"`
 	TLSAuthEntities    []string            `json:"tls_auth_entities,omitempty"`
 	ExternalAuthConfig *ExternalAuthConfig `json:"external_auth_config,omitempty"`
+	Roles              []AuthRoleConfig    `json:"roles,omitempty"`
 }
 
 // ExternalAuthConfig contains information needed to verify externally authenticated tokens.
@@ -918,6 +951,17 @@ func (config *AuthConfig) Validate(path string) error {
 			return err
 		}
 	}
+	seenRoles := make(map[string]struct{}, len(config.Roles))
+	for idx, role := range config.Roles {
+		rolePath := fmt.Sprintf("%s.%s.%d", path, "roles", idx)
+		if _, ok := seenRoles[role.Name]; ok {
+			return resource.NewConfigValidationError(rolePath, errors.Errorf("duplicate role %q", role.Name))
+		}
+		seenRoles[role.Name] = struct{}{}
+		if err := role.Validate(rolePath); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
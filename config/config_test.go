@@ -1232,3 +1232,64 @@ func TestConfigRobotRevision(t *testing.T) {
 
 	test.That(t, cfg.Revision, test.ShouldEqual, "rev1")
 }
+
+func TestAuthConfigRoles(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	validRole := config.AuthRoleConfig{
+		Name:     "operator",
+		Entities: []string{"key-id"},
+		Allow:    []config.AuthRuleConfig{{APIs: []string{"rdk:component:*"}, Methods: []string{"Get*"}}},
+		Deny:     []config.AuthRuleConfig{{Resources: []string{"arm1"}}},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		roles []config.AuthRoleConfig
+		err   string
+	}{
+		{name: "valid", roles: []config.AuthRoleConfig{validRole}},
+		{
+			name:  "claims only",
+			roles: []config.AuthRoleConfig{{Name: "maintenance", Claims: map[string]string{"team": "maintenance"}}},
+		},
+		{
+			name:  "webrtc only",
+			roles: []config.AuthRoleConfig{{Name: "remote", WebRTC: true}},
+		},
+		{
+			name:  "missing name",
+			roles: []config.AuthRoleConfig{{Entities: []string{"key-id"}}},
+			err:   `Error validating. Path: "auth.roles.0" Error: role must have a name`,
+		},
+		{
+			name:  "unbound",
+			roles: []config.AuthRoleConfig{{Name: "operator"}},
+			err:   `Error validating. Path: "auth.roles.0" Error: role must bind at least one entity or claim, or webrtc`,
+		},
+		{
+			name:  "duplicate",
+			roles: []config.AuthRoleConfig{validRole, validRole},
+			err:   `Error validating. Path: "auth.roles.1" Error: duplicate role "operator"`,
+		},
+		{
+			name: "bad pattern",
+			roles: []config.AuthRoleConfig{{
+				Name:     "operator",
+				Entities: []string{"key-id"},
+				Deny:     []config.AuthRuleConfig{{Methods: []string{"Get["}}},
+			}},
+			err: `Error validating. Path: "auth.roles.0.deny.0.methods" Error: invalid pattern "Get["`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := config.Config{Auth: config.AuthConfig{Roles: tc.roles}}
+			err := conf.Ensure(false, logger)
+			if tc.err == "" {
+				test.That(t, err, test.ShouldBeNil)
+				return
+			}
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		})
+	}
+}
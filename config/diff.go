@@ -23,6 +23,7 @@ type Diff struct {
 	ResourcesEqual      bool
 	NetworkEqual        bool
 	LogEqual            bool
+	TracingEqual        bool
 	PrettyDiff          string
 	UnmodifiedResources []resource.Config
 }
@@ -93,9 +94,14 @@ func DiffConfigs(left, right Config, revealSensitiveConfigDiffs bool) (_ *Diff,
 	logDifferent := diffLogCfg(&left, &right, servicesDifferent, componentsDifferent)
 	diff.LogEqual = !logDifferent
 
+	diff.TracingEqual = left.Tracing.Equals(right.Tracing)
+
 	return &diff, nil
 }
 
+// secretMask replaces secrets in configs that are printed.
+const secretMask = "******"
+
 func prettyDiff(left, right Config) (string, error) {
 	leftMd, err := json.Marshal(left)
 	if err != nil {
@@ -115,7 +121,7 @@ func prettyDiff(left, right Config) (string, error) {
 	left = leftClone
 	right = rightClone
 
-	mask := "******"
+	mask := secretMask
 	sanitizeConfig := func(conf *Config) {
 		// Note(erd): keep in mind this will destroy the actual pretty diffing of these which
 		// is fine because we aren't considering pretty diff changes to these fields at this level
@@ -421,6 +427,10 @@ func diffNetworkingCfg(left, right *Config) bool {
 		return true
 	}
 
+	if left.EnableWebMetrics != right.EnableWebMetrics {
+		return true
+	}
+
 	return false
 }
 
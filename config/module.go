@@ -38,6 +38,10 @@ type Module struct {
 	// Environment contains additional variables that are passed to the module process when it is started.
 	// They overwrite existing environment variables.
 	Environment map[string]string `json:"env,omitempty"`
+	// Limits restricts the resources the module process may use.
+	Limits *ModuleLimits `json:"limits,omitempty"`
+	// RestartPolicy configures how the module is restarted after it crashes.
+	RestartPolicy *ModuleRestartPolicy `json:"restart_policy,omitempty"`
 
 	// Status refers to the validations done in the APP to make sure a module is configured correctly
 	Status           *AppValidationStatus `json:"status"`
@@ -96,6 +100,17 @@ func (m *Module) validate(path string) error {
 		return errors.Errorf("module %s cannot use the reserved name of %s", path, reservedModuleName)
 	}
 
+	if m.Limits != nil {
+		if err := m.Limits.Validate(path + ".limits"); err != nil {
+			return err
+		}
+	}
+	if m.RestartPolicy != nil {
+		if err := m.RestartPolicy.Validate(path + ".restart_policy"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
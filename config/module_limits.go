@@ -0,0 +1,50 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// ModuleLimits restricts the resources a module process may use so that a misbehaving module cannot starve the rest
+// of the machine. Memory and CPU limits are enforced with a cgroup v2 group per module where one can be created,
+// which requires viam-server to run in a delegated cgroup subtree, and otherwise with rlimits, which can only
+// approximately limit memory. Limits are only supported on Unix-like systems.
+//
+//	"limits": {
+//		"memory_mb": 512,
+//		"cpus": 0.5,
+//		"nice": 10,
+//		"sandbox": {"user": "viam-module"}
+//	}
+type ModuleLimits struct {
+	// MemoryMB is the most memory, in megabytes, the module may use before it is killed, or, when enforced with
+	// rlimits, before its allocations fail.
+	MemoryMB uint64 `json:"memory_mb,omitempty"`
+	// CPUs is the number of CPU cores' worth of time the module may use, for example 0.5 for half of one core.
+	CPUs float64 `json:"cpus,omitempty"`
+	// Nice is the niceness the module runs with, from -20 (highest priority) to 19 (lowest priority).
+	Nice int `json:"nice,omitempty"`
+	// Sandbox, when set, runs the module in restricted mode, see ModuleSandbox.
+	Sandbox *ModuleSandbox `json:"sandbox,omitempty"`
+}
+
+// ModuleSandbox runs a module with a read-only view of the filesystem, except for its data directory and the
+// directory of its socket, without network access besides the socket it is served on and with only the environment
+// variables given to modules. It requires Linux with bubblewrap (bwrap) installed.
+type ModuleSandbox struct {
+	// User, when set, is the user the module runs as, which requires setpriv to be installed. The module's data
+	// directory is given to this user, who must also be able to create the module's socket next to the server's.
+	User string `json:"user,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (limits *ModuleLimits) Validate(path string) error {
+	if limits.CPUs < 0 {
+		return resource.NewConfigValidationError(path, errors.New("cpus cannot be negative"))
+	}
+	if limits.Nice < -20 || limits.Nice > 19 {
+		return resource.NewConfigValidationError(path, errors.New("nice must be between -20 and 19"))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// ModuleRestartPolicy configures how a module is restarted after it crashes. Restart attempts are backed off
+// exponentially, and once MaxAttempts consecutive attempts have failed the module is given up on and the resources
+// it provides are removed, unless Forever is set. A module that crashes again within ResetWindow of being restarted
+// counts as another failed attempt, so a module stuck in a crash loop is eventually given up on as well.
+//
+//	"restart_policy": {
+//		"max_attempts": 5,
+//		"initial_backoff": "1s",
+//		"max_backoff": "1m",
+//		"reset_window": "10m"
+//	}
+type ModuleRestartPolicy struct {
+	// MaxAttempts is the number of consecutive failed restart attempts after which the module is given up on.
+	// Defaults to 3.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// Forever keeps attempting to restart the module no matter how many attempts have failed.
+	Forever bool `json:"forever,omitempty"`
+	// InitialBackoff is how long to wait before the second consecutive restart attempt, doubling for every
+	// attempt after that. Defaults to 5s.
+	InitialBackoff string `json:"initial_backoff,omitempty"`
+	// MaxBackoff caps how long to wait between restart attempts. Defaults to 5m.
+	MaxBackoff string `json:"max_backoff,omitempty"`
+	// ResetWindow is how long a restarted module must run before its failed restart attempts are forgotten.
+	// Defaults to 1m.
+	ResetWindow string `json:"reset_window,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (policy *ModuleRestartPolicy) Validate(path string) error {
+	if policy.MaxAttempts < 0 {
+		return resource.NewConfigValidationError(path, errors.New("max_attempts cannot be negative"))
+	}
+	for _, field := range []struct {
+		name, value string
+	}{
+		{"initial_backoff", policy.InitialBackoff},
+		{"max_backoff", policy.MaxBackoff},
+		{"reset_window", policy.ResetWindow},
+	} {
+		if field.value == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(field.value)
+		if err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrapf(err, "invalid %s", field.name))
+		}
+		if dur < 0 {
+			return resource.NewConfigValidationError(path, errors.Errorf("%s cannot be negative", field.name))
+		}
+	}
+	return nil
+}
+
+// ModuleState is the state of a module's process.
+type ModuleState string
+
+// The states a module's process can be in.
+const (
+	ModuleStateRunning    ModuleState = "running"
+	ModuleStateRestarting ModuleState = "restarting"
+	ModuleStateFailed     ModuleState = "failed"
+)
+
+// ModuleHealth describes how a module's process has been faring since the module was added.
+type ModuleHealth struct {
+	Name  string      `json:"name"`
+	State ModuleState `json:"state"`
+	// Crashes is the number of times the module's process has exited unexpectedly.
+	Crashes int `json:"crashes"`
+	// RestartAttempts is the number of consecutive restart attempts made since the module last ran for its
+	// restart policy's reset window.
+	RestartAttempts int `json:"restart_attempts"`
+	// LastExitCode is the exit code of the module's process when it last crashed.
+	LastExitCode int `json:"last_exit_code"`
+	// LastCrash is when the module's process last crashed, or the zero time if it never has.
+	LastCrash time.Time `json:"last_crash"`
+	// LastOutput holds the last lines the module's process wrote to stdout or stderr.
+	LastOutput []string `json:"last_output,omitempty"`
+}
@@ -112,3 +112,43 @@ func testWriteJSON(t *testing.T, path string, value any) {
 	err = encoder.Encode(value)
 	test.That(t, err, test.ShouldBeNil)
 }
+
+func TestModuleLimitsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		limits ModuleLimits
+		err    string
+	}{
+		{limits: ModuleLimits{MemoryMB: 512, CPUs: 0.5, Nice: 10, Sandbox: &ModuleSandbox{}}},
+		{limits: ModuleLimits{CPUs: -1}, err: "cpus cannot be negative"},
+		{limits: ModuleLimits{Nice: 20}, err: "nice must be between -20 and 19"},
+	} {
+		err := tc.limits.Validate("modules.0.limits")
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+			continue
+		}
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+	}
+}
+
+func TestModuleRestartPolicyValidate(t *testing.T) {
+	for _, tc := range []struct {
+		policy ModuleRestartPolicy
+		err    string
+	}{
+		{policy: ModuleRestartPolicy{MaxAttempts: 5, InitialBackoff: "1s", MaxBackoff: "1m", ResetWindow: "10m"}},
+		{policy: ModuleRestartPolicy{Forever: true}},
+		{policy: ModuleRestartPolicy{MaxAttempts: -1}, err: "max_attempts cannot be negative"},
+		{policy: ModuleRestartPolicy{InitialBackoff: "soon"}, err: "invalid initial_backoff"},
+		{policy: ModuleRestartPolicy{ResetWindow: "-1s"}, err: "reset_window cannot be negative"},
+	} {
+		err := tc.policy.Validate("modules.0.restart_policy")
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+			continue
+		}
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+	}
+}
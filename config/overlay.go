@@ -0,0 +1,315 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/a8m/envsubst"
+	"github.com/pkg/errors"
+)
+
+// ProfileEnvVar names the environment variable selecting the config profile to apply, unless one is set with
+// SetProfile.
+const ProfileEnvVar = "VIAM_CONFIG_PROFILE"
+
+// A config file may layer overlay files over itself, which lets a checked-in base config be combined with
+// site-specific overrides. Overlays listed under "overlays" are always applied, and those listed under the profile
+// selected with SetProfile or VIAM_CONFIG_PROFILE are applied after them, in order. Relative paths are relative to
+// the directory of the base config file.
+//
+//	{
+//		"components": [{"name": "arm1", "model": "fake", "api": "rdk:component:arm"}],
+//		"overlays": ["site.json"],
+//		"profiles": {
+//			"lab": ["lab.json"],
+//			"field": ["field.json", "field-secrets.json"]
+//		}
+//	}
+//
+// Each overlay is applied as a JSON merge patch (RFC 7386): objects are merged, null removes a field and any other
+// value replaces it. The components, services, modules and remotes lists are merged by name instead of being
+// replaced: an entry with the name of an existing one is merged into it, an entry with "_delete": true removes it,
+// and any other entry is appended.
+const (
+	overlaysKey = "overlays"
+	profilesKey = "profiles"
+	deleteKey   = "_delete"
+)
+
+// namedListKeys are the config lists overlays merge by name.
+var namedListKeys = map[string]bool{
+	"components": true,
+	"services":   true,
+	"modules":    true,
+	"remotes":    true,
+}
+
+var (
+	profileMu sync.Mutex
+	profile   string
+)
+
+// SetProfile selects the config profile to apply, overriding VIAM_CONFIG_PROFILE.
+func SetProfile(name string) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profile = name
+}
+
+func activeProfile() string {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv(ProfileEnvVar)
+}
+
+// ResolveFile returns the JSON config resolved from the config file at filePath, its overlays and the active
+// profile.
+func ResolveFile(filePath string) ([]byte, error) {
+	resolved, _, err := resolveFile(filePath)
+	return resolved, err
+}
+
+// resolveFile returns the JSON config resolved from the config file at filePath, and the paths of every file it
+// was resolved from, starting with filePath. Files without overlays or profiles are returned as they are.
+func resolveFile(filePath string) ([]byte, []string, error) {
+	files := []string{filePath}
+	buf, err := envsubst.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var base map[string]interface{}
+	if err := json.Unmarshal(buf, &base); err != nil {
+		// leave reporting malformed configs to the config decoder
+		//nolint:nilerr
+		return buf, files, nil
+	}
+	if _, ok := base[overlaysKey]; !ok {
+		if _, ok := base[profilesKey]; !ok {
+			return buf, files, nil
+		}
+	}
+
+	overlays, err := overlayFiles(base)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid overlays in config file %s", filePath)
+	}
+	for _, overlay := range overlays {
+		if !filepath.IsAbs(overlay) {
+			overlay = filepath.Join(filepath.Dir(filePath), overlay)
+		}
+		files = append(files, overlay)
+		buf, err := envsubst.ReadFile(overlay)
+		if err != nil {
+			return nil, nil, err
+		}
+		var layer map[string]interface{}
+		if err := json.Unmarshal(buf, &layer); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to decode config overlay %s from json", overlay)
+		}
+		if err := mergeLayer(base, layer); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to apply config overlay %s", overlay)
+		}
+	}
+	delete(base, overlaysKey)
+	delete(base, profilesKey)
+
+	resolved, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolved, files, nil
+}
+
+// overlayFiles returns the overlays to apply to the base config, including those of the active profile.
+func overlayFiles(base map[string]interface{}) ([]string, error) {
+	var files []string
+	if overlays, ok := base[overlaysKey]; ok {
+		list, err := stringList(overlays)
+		if err != nil {
+			return nil, errors.Wrap(err, overlaysKey)
+		}
+		files = append(files, list...)
+	}
+
+	name := activeProfile()
+	if name == "" {
+		return files, nil
+	}
+	profiles, _ := base[profilesKey].(map[string]interface{})
+	overlays, ok := profiles[name]
+	if !ok {
+		return nil, errors.Errorf("unknown profile %q", name)
+	}
+	list, err := stringList(overlays)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s.%s", profilesKey, name)
+	}
+	return append(files, list...), nil
+}
+
+func stringList(value interface{}) ([]string, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("must be a list of file paths")
+	}
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a list of file paths")
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// mergeLayer applies the overlay layer to the config base.
+func mergeLayer(base, layer map[string]interface{}) error {
+	for key, value := range layer {
+		if key == overlaysKey || key == profilesKey {
+			return errors.Errorf("overlays cannot set %q", key)
+		}
+		patch, isList := value.([]interface{})
+		if !namedListKeys[key] || !isList {
+			if merged := mergePatch(base[key], value); merged == nil {
+				delete(base, key)
+			} else {
+				base[key] = merged
+			}
+			continue
+		}
+		target, _ := base[key].([]interface{})
+		merged, err := mergeNamedList(target, patch)
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+		base[key] = merged
+	}
+	return nil
+}
+
+// mergePatch applies patch to target as a JSON merge patch (RFC 7386), returning nil if the result is null.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// mergeNamedList merges the entries of patch into the entries of target with the same name.
+func mergeNamedList(target, patch []interface{}) ([]interface{}, error) {
+	byName := make(map[string]int, len(target))
+	for idx, entry := range target {
+		if obj, ok := entry.(map[string]interface{}); ok {
+			if name, ok := obj["name"].(string); ok {
+				byName[name] = idx
+			}
+		}
+	}
+
+	removed := map[int]bool{}
+	for idx, entry := range patch {
+		obj, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("entry %d must be an object", idx)
+		}
+		name, ok := obj["name"].(string)
+		if !ok || name == "" {
+			return nil, errors.Errorf("entry %d must have a name", idx)
+		}
+		existing, exists := byName[name]
+		if del, _ := obj[deleteKey].(bool); del {
+			if exists {
+				removed[existing] = true
+				delete(byName, name)
+			}
+			continue
+		}
+		delete(obj, deleteKey)
+		if exists {
+			target[existing] = mergePatch(target[existing], obj)
+			continue
+		}
+		byName[name] = len(target)
+		target = append(target, mergePatch(nil, obj))
+	}
+
+	merged := make([]interface{}, 0, len(target)-len(removed))
+	for idx, entry := range target {
+		if !removed[idx] {
+			merged = append(merged, entry)
+		}
+	}
+	return merged, nil
+}
+
+// RedactSecrets returns the JSON config resolved by ResolveFile with the cloud, auth handler and remote secrets it
+// may have merged from overlays masked, so that it can be printed.
+func RedactSecrets(resolved []byte) ([]byte, error) {
+	var conf map[string]interface{}
+	if err := json.Unmarshal(resolved, &conf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode config from json")
+	}
+	if cloud, ok := conf["cloud"].(map[string]interface{}); ok {
+		redactFields(cloud, "secret", "location_secret", "tls_private_key")
+		for _, secret := range objectList(cloud["location_secrets"]) {
+			redactFields(secret, "secret")
+		}
+	}
+	if auth, ok := conf["auth"].(map[string]interface{}); ok {
+		for _, handler := range objectList(auth["handlers"]) {
+			if handlerConf, ok := handler["config"].(map[string]interface{}); ok {
+				for key := range handlerConf {
+					redactFields(handlerConf, key)
+				}
+			}
+		}
+	}
+	for _, remote := range objectList(conf["remotes"]) {
+		redactFields(remote, "secret")
+		if auth, ok := remote["auth"].(map[string]interface{}); ok {
+			if creds, ok := auth["credentials"].(map[string]interface{}); ok {
+				redactFields(creds, "payload")
+			}
+		}
+	}
+	return json.MarshalIndent(conf, "", "  ")
+}
+
+// redactFields masks the fields of obj with the given keys that are set.
+func redactFields(obj map[string]interface{}, keys ...string) {
+	for _, key := range keys {
+		if value, ok := obj[key]; ok && value != nil && value != "" {
+			obj[key] = secretMask
+		}
+	}
+}
+
+// objectList returns the objects in the JSON list value.
+func objectList(value interface{}) []map[string]interface{} {
+	values, _ := value.([]interface{})
+	objects := make([]map[string]interface{}, 0, len(values))
+	for _, v := range values {
+		if obj, ok := v.(map[string]interface{}); ok {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
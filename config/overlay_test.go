@@ -0,0 +1,176 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func writeJSON(t *testing.T, path, data string) {
+	t.Helper()
+	test.That(t, os.WriteFile(path, []byte(data), 0o600), test.ShouldBeNil)
+}
+
+func TestResolveFileOverlays(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	writeJSON(t, base, `{
+		"components": [
+			{"name": "arm1", "api": "rdk:component:arm", "model": "fake", "attributes": {"speed": 1, "port": "/dev/a"}},
+			{"name": "cam1", "api": "rdk:component:camera", "model": "fake"}
+		],
+		"network": {"bind_address": "localhost:8080"},
+		"overlays": ["site.json"],
+		"profiles": {"lab": ["lab.json"]}
+	}`)
+	writeJSON(t, filepath.Join(dir, "site.json"), `{
+		"components": [
+			{"name": "arm1", "attributes": {"speed": 2, "port": null}},
+			{"name": "cam1", "_delete": true},
+			{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake"}
+		],
+		"network": {"bind_address": "localhost:9090"}
+	}`)
+	writeJSON(t, filepath.Join(dir, "lab.json"), `{"components": [{"name": "arm1", "model": "lab"}]}`)
+
+	resolved, err := config.ResolveFile(base)
+	test.That(t, err, test.ShouldBeNil)
+	var conf map[string]interface{}
+	test.That(t, json.Unmarshal(resolved, &conf), test.ShouldBeNil)
+	test.That(t, conf, test.ShouldResemble, map[string]interface{}{
+		"components": []interface{}{
+			map[string]interface{}{
+				"name": "arm1", "api": "rdk:component:arm", "model": "fake",
+				"attributes": map[string]interface{}{"speed": 2.0},
+			},
+			map[string]interface{}{"name": "sensor1", "api": "rdk:component:sensor", "model": "fake"},
+		},
+		"network": map[string]interface{}{"bind_address": "localhost:9090"},
+	})
+
+	t.Setenv(config.ProfileEnvVar, "lab")
+	resolved, err = config.ResolveFile(base)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, json.Unmarshal(resolved, &conf), test.ShouldBeNil)
+	//nolint:forcetypeassert
+	arm1 := conf["components"].([]interface{})[0].(map[string]interface{})
+	test.That(t, arm1["model"], test.ShouldEqual, "lab")
+
+	config.SetProfile("field")
+	defer config.SetProfile("")
+	_, err = config.ResolveFile(base)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `unknown profile "field"`)
+}
+
+func TestResolveFileWithoutOverlays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "robot.json")
+	data := `{"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}]}`
+	writeJSON(t, path, data)
+
+	resolved, err := config.ResolveFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(resolved), test.ShouldEqual, data)
+}
+
+func TestRedactSecrets(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	writeJSON(t, base, `{
+		"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}],
+		"overlays": ["secrets.json"]
+	}`)
+	writeJSON(t, filepath.Join(dir, "secrets.json"), `{
+		"cloud": {"id": "robot", "secret": "s1", "location_secrets": [{"id": "l", "secret": "s2"}], "tls_private_key": "s3"},
+		"auth": {"handlers": [{"type": "api-key", "config": {"key": "s4", "keys": ["s5"]}}]},
+		"remotes": [{"name": "rem1", "address": "addr", "secret": "s6", "auth": {"credentials": {"type": "api-key", "payload": "s7"}}}]
+	}`)
+
+	resolved, err := config.ResolveFile(base)
+	test.That(t, err, test.ShouldBeNil)
+	redacted, err := config.RedactSecrets(resolved)
+	test.That(t, err, test.ShouldBeNil)
+	for _, secret := range []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7"} {
+		test.That(t, string(redacted), test.ShouldNotContainSubstring, `"`+secret+`"`)
+	}
+
+	var conf map[string]interface{}
+	test.That(t, json.Unmarshal(redacted, &conf), test.ShouldBeNil)
+	cloud := conf["cloud"].(map[string]interface{})
+	test.That(t, cloud["id"], test.ShouldEqual, "robot")
+	test.That(t, cloud["secret"], test.ShouldEqual, "******")
+	remote := conf["remotes"].([]interface{})[0].(map[string]interface{})
+	test.That(t, remote["address"], test.ShouldEqual, "addr")
+	test.That(t, remote["auth"].(map[string]interface{})["credentials"].(map[string]interface{})["type"], test.ShouldEqual, "api-key")
+	test.That(t, conf["components"], test.ShouldHaveLength, 1)
+
+	_, err = config.RedactSecrets([]byte("{"))
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestOverlayDiff(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	site := filepath.Join(dir, "site.json")
+	writeJSON(t, base, `{
+		"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}],
+		"overlays": ["site.json"]
+	}`)
+	writeJSON(t, site, `{"components": [{"name": "arm1", "attributes": {"speed": 1}}]}`)
+
+	left, err := config.Read(context.Background(), base, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	writeJSON(t, site, `{"components": [{"name": "arm1", "attributes": {"speed": 2}}]}`)
+	right, err := config.Read(context.Background(), base, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	diff, err := config.DiffConfigs(*left, *right, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.Added.Components, test.ShouldBeEmpty)
+	test.That(t, diff.Removed.Components, test.ShouldBeEmpty)
+	test.That(t, diff.Modified.Components, test.ShouldHaveLength, 1)
+	test.That(t, diff.Modified.Components[0].Name, test.ShouldEqual, "arm1")
+	test.That(t, diff.Modified.Components[0].Attributes["speed"], test.ShouldEqual, 2.0)
+}
+
+func TestNewWatcherOverlay(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	site := filepath.Join(dir, "site.json")
+	writeJSON(t, base, `{
+		"components": [{"name": "arm1", "api": "rdk:component:arm", "model": "fake"}],
+		"overlays": ["site.json"]
+	}`)
+	writeJSON(t, site, `{}`)
+
+	watcher, err := config.NewWatcher(context.Background(), &config.Config{ConfigFilePath: base}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, watcher.Close(), test.ShouldBeNil)
+	}()
+
+	writeJSON(t, site, `{"components": [{"name": "arm1", "attributes": {"speed": 2}}]}`)
+	timer := time.NewTimer(10 * time.Second)
+	defer timer.Stop()
+	select {
+	case newConf := <-watcher.Config():
+		test.That(t, newConf.Components, test.ShouldHaveLength, 1)
+		test.That(t, newConf.Components[0].ResourceName(), test.ShouldResemble,
+			resource.NewName(resource.APINamespaceRDK.WithComponentType("arm"), "arm1"))
+		test.That(t, newConf.Components[0].Attributes["speed"], test.ShouldEqual, 2.0)
+	case <-timer.C:
+		t.Fatal("timed out waiting for config after overlay was written")
+	}
+}
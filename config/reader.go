@@ -13,7 +13,6 @@ import (
 	"runtime"
 	"time"
 
-	"github.com/a8m/envsubst"
 	"github.com/pkg/errors"
 	apppb "go.viam.com/api/app/v1"
 	"go.viam.com/utils"
@@ -338,13 +337,13 @@ func (tls *tlsConfig) readFromCache(id string, logger logging.Logger) error {
 	return nil
 }
 
-// Read reads a config from the given file.
+// Read reads a config from the given file, applying its overlays and the active profile.
 func Read(
 	ctx context.Context,
 	filePath string,
 	logger logging.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := ResolveFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -352,13 +351,14 @@ func Read(
 	return FromReader(ctx, filePath, bytes.NewReader(buf), logger)
 }
 
-// ReadLocalConfig reads a config from the given file but does not fetch any config from the remote servers.
+// ReadLocalConfig reads a config from the given file, applying its overlays and the active profile, but does not
+// fetch any config from the remote servers.
 func ReadLocalConfig(
 	ctx context.Context,
 	filePath string,
 	logger logging.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := ResolveFile(filePath)
 	if err != nil {
 		return nil, err
 	}
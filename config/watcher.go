@@ -3,7 +3,7 @@ package config
 import (
 	"bytes"
 	"context"
-	"os"
+	"sync"
 	"time"
 
 	"github.com/bep/debounce"
@@ -109,7 +109,8 @@ func (w *cloudWatcher) Close() error {
 	return nil
 }
 
-// A fsConfigWatcher fetches new configs from an underlying file when written to.
+// A fsConfigWatcher fetches new configs from an underlying file, or any of the overlay files layered over it, when
+// written to.
 type fsConfigWatcher struct {
 	fsWatcher     *fsnotify.Watcher
 	configCh      chan *Config
@@ -118,19 +119,48 @@ type fsConfigWatcher struct {
 }
 
 // newFSWatcher returns a new v that will fetch new configs
-// as soon as the underlying file is written to.
+// as soon as the underlying file or any of its overlays is written to.
 func newFSWatcher(ctx context.Context, configPath string, logger logging.Logger) (*fsConfigWatcher, error) {
 	fsWatcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
-	if err := fsWatcher.Add(configPath); err != nil {
+	_, files, err := resolveFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	watched := map[string]bool{}
+	// watch updates the watched files to those the config is currently resolved from.
+	watch := func(files []string) error {
+		current := make(map[string]bool, len(files))
+		for _, file := range files {
+			current[file] = true
+			if watched[file] {
+				continue
+			}
+			if err := fsWatcher.Add(file); err != nil {
+				return err
+			}
+			watched[file] = true
+		}
+		for file := range watched {
+			if !current[file] {
+				utils.UncheckedError(fsWatcher.Remove(file))
+				delete(watched, file)
+			}
+		}
+		return nil
+	}
+	if err := watch(files); err != nil {
 		return nil, err
 	}
 	configCh := make(chan *Config)
 	watcherDoneCh := make(chan struct{})
 	cancelCtx, cancel := context.WithCancel(ctx)
-	var lastRd []byte
+	var (
+		reloadMu sync.Mutex
+		lastRd   []byte
+	)
 	utils.ManagedGo(func() {
 		debounced := debounce.New(time.Millisecond * 500)
 		for {
@@ -143,13 +173,17 @@ func newFSWatcher(ctx context.Context, configPath string, logger logging.Logger)
 			case event := <-fsWatcher.Events:
 				if event.Op&fsnotify.Write == fsnotify.Write {
 					debounced(func() {
-						logger.Info("On-disk config file changed. Reloading the config file.")
-						//nolint:gosec
-						rd, err := os.ReadFile(configPath)
+						reloadMu.Lock()
+						defer reloadMu.Unlock()
+						logger.Infow("On-disk config file changed. Reloading the config file.", "file", event.Name)
+						rd, files, err := resolveFile(configPath)
 						if err != nil {
 							logger.Errorw("error reading config file after write", "error", err)
 							return
 						}
+						if err := watch(files); err != nil {
+							logger.Errorw("error watching config overlays", "error", err)
+						}
 						if bytes.Equal(rd, lastRd) {
 							return
 						}
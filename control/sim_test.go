@@ -0,0 +1,67 @@
+package control
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/sim"
+)
+
+// simMotor is a simulated motor with an encoder, for closing control loops around.
+type simMotor struct {
+	motor            *sim.Motor
+	ticksPerRotation float64
+}
+
+func (m *simMotor) SetState(ctx context.Context, state []*Signal) error {
+	m.motor.SetPower(state[0].GetSignalValueAt(0))
+	return nil
+}
+
+func (m *simMotor) State(ctx context.Context) ([]float64, error) {
+	position, _ := m.motor.State()
+	return []float64{math.Floor(position * m.ticksPerRotation)}, nil
+}
+
+func TestPositionControlSimulatedMotor(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	m := &simMotor{motor: sim.NewMotor(sim.MotorConfig{}, 100, clock.New()), ticksPerRotation: 100}
+
+	pl, err := SetupPIDControlConfig(
+		[]PIDConfig{{P: 2, I: 10}},
+		"motor",
+		Options{PositionControlUsingTrapz: true, LoopFrequency: 100},
+		m,
+		logger,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pl.StartControlLoop(), test.ShouldBeNil)
+	defer pl.ControlLoop.Stop()
+
+	// move three revolutions at 60 rpm despite the motor's lag and friction
+	constant := pl.BlockNames[BlockNameConstant][0]
+	dependsOn := []string{constant, pl.BlockNames[BlockNameEndpoint][0]}
+	test.That(t, UpdateTrapzBlock(ctx, pl.BlockNames[BlockNameTrapezoidal][0], 100, dependsOn, pl.ControlLoop), test.ShouldBeNil)
+	test.That(t, UpdateConstantBlock(ctx, constant, 300, pl.ControlLoop), test.ShouldBeNil)
+
+	start := time.Now()
+	var maxRPM float64
+	testutils.WaitForAssertionWithSleep(t, 10*time.Millisecond, 1000, func(tb testing.TB) {
+		tb.Helper()
+		position, rpm := m.motor.State()
+		maxRPM = math.Max(maxRPM, rpm)
+		test.That(tb, position, test.ShouldAlmostEqual, 3, 0.05)
+		test.That(tb, rpm, test.ShouldAlmostEqual, 0, 1)
+	})
+	// at 60 rpm, three revolutions take three seconds
+	test.That(t, time.Since(start), test.ShouldBeGreaterThan, 2*time.Second)
+	test.That(t, maxRPM, test.ShouldBeBetween, 50, 75)
+}
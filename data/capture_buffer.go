@@ -2,7 +2,9 @@ package data
 
 import (
 	"sync"
+	"time"
 
+	"go.uber.org/multierr"
 	v1 "go.viam.com/api/app/datasync/v1"
 )
 
@@ -18,21 +20,41 @@ type CaptureBuffer struct {
 	Directory          string
 	MetaData           *v1.DataCaptureMetadata
 	nextFile           *CaptureFile
+	nextFileCreated    time.Time
+	rotateTimer        *time.Timer
+	rotateErr          error
 	lock               sync.Mutex
 	maxCaptureFileSize int64
+	maxCaptureFileAge  time.Duration
 }
 
 // NewCaptureBuffer returns a new Buffer.
 func NewCaptureBuffer(dir string, md *v1.DataCaptureMetadata, maxCaptureFileSize int64) *CaptureBuffer {
+	return NewCaptureBufferWithMaxAge(dir, md, maxCaptureFileSize, 0)
+}
+
+// NewCaptureBufferWithMaxAge returns a new Buffer which, in addition to rotating files once they reach
+// maxCaptureFileSize, rotates files which were created more than maxCaptureFileAge ago, so that data
+// from low rate collectors becomes available to sync in a bounded amount of time. Files are rotated on
+// a timer, so that this also holds for collectors which stop writing.
+// A maxCaptureFileAge of zero disables time based rotation.
+func NewCaptureBufferWithMaxAge(
+	dir string,
+	md *v1.DataCaptureMetadata,
+	maxCaptureFileSize int64,
+	maxCaptureFileAge time.Duration,
+) *CaptureBuffer {
 	return &CaptureBuffer{
 		Directory:          dir,
 		MetaData:           md,
 		maxCaptureFileSize: maxCaptureFileSize,
+		maxCaptureFileAge:  maxCaptureFileAge,
 	}
 }
 
 // Write writes item onto b. Binary sensor data is written to its own file.
-// Tabular data is written to disk in maxCaptureFileSize sized files. Files that
+// Tabular data is written to disk in maxCaptureFileSize sized files, which are
+// also rotated once they are older than maxCaptureFileAge if it is set. Files that
 // are still being written to are indicated with the extension
 // InProgressFileExt. Files that have finished being written to are indicated by
 // FileExt.
@@ -54,43 +76,76 @@ func (b *CaptureBuffer) Write(item *v1.SensorData) error {
 		return nil
 	}
 
-	if b.nextFile == nil {
-		nextFile, err := NewCaptureFile(b.Directory, b.MetaData)
-		if err != nil {
+	// We want to special case on "CaptureAllFromCamera" because it is sensor data that contains images
+	// and their corresponding annotations. We want each image and its annotations to be stored in a
+	// separate file.
+	if b.nextFile != nil &&
+		(b.nextFile.Size() > b.maxCaptureFileSize || b.MetaData.MethodName == "CaptureAllFromCamera" || b.nextFileExpired()) {
+		if err := b.closeNextFile(); err != nil {
 			return err
 		}
-		b.nextFile = nextFile
-		// We want to special case on "CaptureAllFromCamera" because it is sensor data that contains images
-		// and their corresponding annotations. We want each image and its annotations to be stored in a
-		// separate file.
-	} else if b.nextFile.Size() > b.maxCaptureFileSize || b.MetaData.MethodName == "CaptureAllFromCamera" {
-		if err := b.nextFile.Close(); err != nil {
-			return err
-		}
-		nextFile, err := NewCaptureFile(b.Directory, b.MetaData)
-		if err != nil {
+	}
+	if b.nextFile == nil {
+		if err := b.openNextFile(); err != nil {
 			return err
 		}
-		b.nextFile = nextFile
 	}
 
 	return b.nextFile.WriteNext(item)
 }
 
-// Flush flushes all buffered data to disk and marks any in progress file as complete.
+// Flush flushes all buffered data to disk and marks any in progress file as complete. It also returns
+// any error from closing a file that was rotated on a timer since the last Flush.
 func (b *CaptureBuffer) Flush() error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
+	rotateErr := b.rotateErr
+	b.rotateErr = nil
 	if b.nextFile == nil {
-		return nil
+		return rotateErr
 	}
-	if err := b.nextFile.Close(); err != nil {
+	return multierr.Combine(rotateErr, b.closeNextFile())
+}
+
+func (b *CaptureBuffer) openNextFile() error {
+	nextFile, err := NewCaptureFile(b.Directory, b.MetaData)
+	if err != nil {
 		return err
 	}
-	b.nextFile = nil
+	b.nextFile = nextFile
+	b.nextFileCreated = time.Now()
+	if b.maxCaptureFileAge > 0 {
+		b.rotateTimer = time.AfterFunc(b.maxCaptureFileAge, func() { b.rotateExpired(nextFile) })
+	}
 	return nil
 }
 
+func (b *CaptureBuffer) closeNextFile() error {
+	if b.rotateTimer != nil {
+		b.rotateTimer.Stop()
+		b.rotateTimer = nil
+	}
+	err := b.nextFile.Close()
+	b.nextFile = nil
+	return err
+}
+
+// rotateExpired completes file if it is still the in progress file once it has reached maxCaptureFileAge.
+func (b *CaptureBuffer) rotateExpired(file *CaptureFile) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.nextFile != file {
+		return
+	}
+	if err := b.closeNextFile(); err != nil {
+		b.rotateErr = multierr.Combine(b.rotateErr, err)
+	}
+}
+
+func (b *CaptureBuffer) nextFileExpired() bool {
+	return b.maxCaptureFileAge > 0 && time.Since(b.nextFileCreated) >= b.maxCaptureFileAge
+}
+
 // Path returns the path to the directory containing the backing data capture files.
 func (b *CaptureBuffer) Path() string {
 	return b.Directory
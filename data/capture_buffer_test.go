@@ -13,6 +13,7 @@ import (
 	"go.viam.com/test"
 	"go.viam.com/utils"
 	"go.viam.com/utils/protoutils"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -676,3 +677,50 @@ func getCaptureFiles(dir string) (dcFiles, progFiles []string) {
 	})
 	return dcFiles, progFiles
 }
+
+func TestCaptureBufferMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	maxAge := 50 * time.Millisecond
+	b := NewCaptureBufferWithMaxAge(dir, md, 1024*1024, maxAge)
+
+	test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+	completed, inProgress := getCaptureFiles(dir)
+	test.That(t, len(completed), test.ShouldEqual, 0)
+	test.That(t, len(inProgress), test.ShouldEqual, 1)
+
+	// once the in progress file is older than maxAge, the next write rotates it even though it is far from full
+	time.Sleep(maxAge)
+	test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+	completed, inProgress = getCaptureFiles(dir)
+	test.That(t, len(completed), test.ShouldEqual, 1)
+	test.That(t, len(inProgress), test.ShouldEqual, 1)
+	readings, err := SensorDataFromCaptureFilePath(completed[0])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(readings), test.ShouldEqual, 2)
+	test.That(t, b.Flush(), test.ShouldBeNil)
+}
+
+func TestCaptureBufferMaxAgeIdle(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	maxAge := 50 * time.Millisecond
+	b := NewCaptureBufferWithMaxAge(dir, md, 1024*1024, maxAge)
+
+	test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+
+	// the in progress file is completed once it is older than maxAge even if nothing else is written
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		completed, inProgress := getCaptureFiles(dir)
+		test.That(tb, len(completed), test.ShouldEqual, 1)
+		test.That(tb, len(inProgress), test.ShouldEqual, 0)
+	})
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	completed, inProgress := getCaptureFiles(dir)
+	test.That(t, len(completed), test.ShouldEqual, 2)
+	test.That(t, len(inProgress), test.ShouldEqual, 0)
+}
@@ -0,0 +1,158 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Capture file compression codecs.
+const (
+	// CompressionNone writes readings as uncompressed length delimited protobuf messages. This is the default.
+	CompressionNone = ""
+	// CompressionGzip writes readings in gzip compressed blocks.
+	CompressionGzip = "gzip"
+	// CompressionZstd writes readings in zstd compressed blocks.
+	CompressionZstd = "zstd"
+	// CompressionMetadataKey is the key of the DataCaptureMetadata method parameter which records the codec a
+	// capture file was written with. Capture files without it are uncompressed.
+	CompressionMetadataKey = "viam_capture_compression"
+	// captureBlockSize is the number of uncompressed bytes of readings buffered before they are compressed and
+	// written to a compressed capture file as a block.
+	captureBlockSize = 64 * 1024
+	// maxCaptureBlockSize bounds the size of a single decompressed block, so that a corrupt capture file can't
+	// exhaust memory.
+	maxCaptureBlockSize = 1 << 30
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	errZstd     error
+)
+
+// ValidateCompression returns an error if codec is not a supported capture file compression codec.
+func ValidateCompression(codec string) error {
+	switch codec {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return nil
+	default:
+		return errors.Errorf("unsupported capture file compression %q, expected one of %q, %q or %q",
+			codec, CompressionNone, CompressionGzip, CompressionZstd)
+	}
+}
+
+// WithCompression returns a copy of md which records that capture files are compressed with codec.
+// md is not modified, as its method parameters are frequently shared with the collector it describes.
+func WithCompression(md *v1.DataCaptureMetadata, codec string) (*v1.DataCaptureMetadata, error) {
+	if err := ValidateCompression(codec); err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	ret := proto.Clone(md).(*v1.DataCaptureMetadata)
+	if codec == CompressionNone {
+		delete(ret.MethodParameters, CompressionMetadataKey)
+		return ret, nil
+	}
+	param, err := anypb.New(wrapperspb.String(codec))
+	if err != nil {
+		return nil, err
+	}
+	if ret.MethodParameters == nil {
+		ret.MethodParameters = map[string]*anypb.Any{}
+	}
+	ret.MethodParameters[CompressionMetadataKey] = param
+	return ret, nil
+}
+
+// CompressionFromMetadata returns the codec recorded in md, or CompressionNone if md does not record one.
+func CompressionFromMetadata(md *v1.DataCaptureMetadata) (string, error) {
+	param, ok := md.GetMethodParameters()[CompressionMetadataKey]
+	if !ok {
+		return CompressionNone, nil
+	}
+	codec := &wrapperspb.StringValue{}
+	if err := param.UnmarshalTo(codec); err != nil {
+		return "", errors.Wrap(err, "invalid capture file compression in metadata")
+	}
+	if err := ValidateCompression(codec.GetValue()); err != nil {
+		return "", err
+	}
+	return codec.GetValue(), nil
+}
+
+// MethodParametersWithoutCompression returns the method parameters of md without the compression codec, which
+// only describes how the capture file is stored.
+func MethodParametersWithoutCompression(md *v1.DataCaptureMetadata) map[string]*anypb.Any {
+	params := md.GetMethodParameters()
+	if _, ok := params[CompressionMetadataKey]; !ok {
+		return params
+	}
+	ret := make(map[string]*anypb.Any, len(params)-1)
+	for k, v := range params {
+		if k != CompressionMetadataKey {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func initZstd() error {
+	zstdOnce.Do(func() {
+		zstdEncoder, errZstd = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if errZstd != nil {
+			return
+		}
+		zstdDecoder, errZstd = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxCaptureBlockSize))
+	})
+	return errZstd
+}
+
+func compressBlock(codec string, block []byte) ([]byte, error) {
+	switch codec {
+	case CompressionZstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdEncoder.EncodeAll(block, nil), nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(block); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Errorf("unsupported capture file compression %q", codec)
+	}
+}
+
+func decompressBlock(codec string, block []byte) ([]byte, error) {
+	switch codec {
+	case CompressionZstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdDecoder.DecodeAll(block, nil)
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(block))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(io.LimitReader(r, maxCaptureBlockSize))
+	default:
+		return nil, errors.Errorf("unsupported capture file compression %q", codec)
+	}
+}
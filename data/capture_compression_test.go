@@ -0,0 +1,128 @@
+package data
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func compressionTestReadings(t *testing.T, n int) []*v1.SensorData {
+	t.Helper()
+	readings := []*v1.SensorData{}
+	for i := 0; i < n; i++ {
+		s, err := structpb.NewStruct(map[string]interface{}{"index": float64(i), "name": "a fairly repetitive reading"})
+		test.That(t, err, test.ShouldBeNil)
+		readings = append(readings, &v1.SensorData{Metadata: &v1.SensorMetadata{}, Data: &v1.SensorData_Struct{Struct: s}})
+	}
+	return readings
+}
+
+func TestWithCompression(t *testing.T) {
+	md := &v1.DataCaptureMetadata{MethodName: "Readings"}
+	codec, err := CompressionFromMetadata(md)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, codec, test.ShouldEqual, CompressionNone)
+
+	compressed, err := WithCompression(md, CompressionZstd)
+	test.That(t, err, test.ShouldBeNil)
+	codec, err = CompressionFromMetadata(compressed)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, codec, test.ShouldEqual, CompressionZstd)
+	// the original metadata is not modified
+	test.That(t, md.GetMethodParameters(), test.ShouldBeEmpty)
+	test.That(t, MethodParametersWithoutCompression(compressed), test.ShouldBeEmpty)
+
+	uncompressed, err := WithCompression(compressed, CompressionNone)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, uncompressed.GetMethodParameters(), test.ShouldBeEmpty)
+
+	_, err = WithCompression(md, "lz4")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCaptureFileCompression(t *testing.T) {
+	// enough readings to span several blocks
+	readings := compressionTestReadings(t, 5000)
+	for _, codec := range []string{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run("codec "+codec, func(t *testing.T) {
+			dir := t.TempDir()
+			md, err := WithCompression(&v1.DataCaptureMetadata{MethodName: "Readings"}, codec)
+			test.That(t, err, test.ShouldBeNil)
+			f, err := NewCaptureFile(dir, md)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, f.Compression(), test.ShouldEqual, codec)
+			for _, reading := range readings {
+				test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+			}
+			test.That(t, f.Close(), test.ShouldBeNil)
+
+			path := strings.TrimSuffix(f.GetPath(), InProgressCaptureFileExt) + CompletedCaptureFileExt
+			read, err := SensorDataFromCaptureFilePath(path)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(read), test.ShouldEqual, len(readings))
+			test.That(t, read[len(read)-1], test.ShouldResemble, readings[len(readings)-1])
+
+			info, err := os.Stat(path)
+			test.That(t, err, test.ShouldBeNil)
+			if codec != CompressionNone {
+				test.That(t, info.Size(), test.ShouldBeLessThan, int64(len(readings))*10)
+			}
+
+			// a file which ends part way through a reading or block reads every complete reading before it
+			test.That(t, os.Truncate(path, info.Size()-3), test.ShouldBeNil)
+			//nolint:gosec
+			truncated, err := os.Open(path)
+			test.That(t, err, test.ShouldBeNil)
+			defer truncated.Close()
+			cf, err := ReadCaptureFile(truncated)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, cf.ReadMetadata(), test.ShouldResembleProto, md)
+			count := 0
+			for {
+				_, err := cf.ReadNext()
+				if err != nil {
+					test.That(t, err, test.ShouldBeError, io.ErrUnexpectedEOF)
+					break
+				}
+				count++
+			}
+			test.That(t, count, test.ShouldBeGreaterThan, 0)
+			test.That(t, count, test.ShouldBeLessThan, len(readings))
+		})
+	}
+}
+
+func TestCaptureFileCompressionFlush(t *testing.T) {
+	dir := t.TempDir()
+	md, err := WithCompression(&v1.DataCaptureMetadata{MethodName: "Readings"}, CompressionGzip)
+	test.That(t, err, test.ShouldBeNil)
+	f, err := NewCaptureFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	readings := compressionTestReadings(t, 3)
+
+	// readings written before a flush are readable in the in progress file
+	test.That(t, f.WriteNext(readings[0]), test.ShouldBeNil)
+	test.That(t, f.Flush(), test.ShouldBeNil)
+	test.That(t, f.WriteNext(readings[1]), test.ShouldBeNil)
+	test.That(t, f.WriteNext(readings[2]), test.ShouldBeNil)
+	test.That(t, f.Flush(), test.ShouldBeNil)
+
+	//nolint:gosec
+	inProgress, err := os.Open(filepath.Join(dir, filepath.Base(f.GetPath())))
+	test.That(t, err, test.ShouldBeNil)
+	defer inProgress.Close()
+	cf, err := ReadCaptureFile(inProgress)
+	test.That(t, err, test.ShouldBeNil)
+	read, err := SensorDataFromCaptureFile(cf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(read), test.ShouldEqual, 3)
+	_, err = cf.ReadNext()
+	test.That(t, err, test.ShouldBeError, io.EOF)
+	test.That(t, f.Close(), test.ShouldBeNil)
+}
@@ -2,6 +2,8 @@ package data
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
@@ -41,6 +43,8 @@ const (
 // CaptureFile is the data structure containing data captured by collectors. It is backed by a file on disk containing
 // length delimited protobuf messages, where the first message is the CaptureMetadata for the file, and ensuing
 // messages contain the captured data.
+// If the metadata records a compression codec (see WithCompression), the captured data is instead stored as a series
+// of blocks, each a varint length followed by that many bytes of compressed, length delimited SensorData messages.
 type CaptureFile struct {
 	path     string
 	lock     sync.Mutex
@@ -49,6 +53,12 @@ type CaptureFile struct {
 	size     int64
 	metadata *v1.DataCaptureMetadata
 
+	compression string
+	// readings written to a compressed file which have not yet been compressed into a block
+	pendingBlock bytes.Buffer
+	// the remainder of the block being read from a compressed file
+	readBlock *bytes.Reader
+
 	initialReadOffset int64
 	readOffset        int64
 	writeOffset       int64
@@ -69,6 +79,10 @@ func ReadCaptureFile(f *os.File) (*CaptureFile, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, fmt.Sprintf("failed to read DataCaptureMetadata from %s", f.Name()))
 	}
+	compression, err := CompressionFromMetadata(md)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read DataCaptureMetadata from %s", f.Name())
+	}
 
 	ret := CaptureFile{
 		path:              f.Name(),
@@ -76,6 +90,7 @@ func ReadCaptureFile(f *os.File) (*CaptureFile, error) {
 		writer:            bufio.NewWriter(f),
 		size:              finfo.Size(),
 		metadata:          md,
+		compression:       compression,
 		initialReadOffset: int64(initOffset),
 		readOffset:        int64(initOffset),
 		writeOffset:       int64(initOffset),
@@ -85,7 +100,12 @@ func ReadCaptureFile(f *os.File) (*CaptureFile, error) {
 }
 
 // NewCaptureFile creates a new *CaptureFile with the specified md in the specified directory.
+// Readings are compressed with the codec recorded in md, if any.
 func NewCaptureFile(dir string, md *v1.DataCaptureMetadata) (*CaptureFile, error) {
+	compression, err := CompressionFromMetadata(md)
+	if err != nil {
+		return nil, err
+	}
 	fileName := CaptureFilePathWithReplacedReservedChars(
 		filepath.Join(dir, getFileTimestampName()) + InProgressCaptureFileExt)
 	//nolint:gosec
@@ -104,6 +124,7 @@ func NewCaptureFile(dir string, md *v1.DataCaptureMetadata) (*CaptureFile, error
 		writer:            bufio.NewWriter(f),
 		file:              f,
 		size:              int64(n),
+		compression:       compression,
 		initialReadOffset: int64(n),
 		readOffset:        int64(n),
 		writeOffset:       int64(n),
@@ -115,21 +136,37 @@ func (f *CaptureFile) ReadMetadata() *v1.DataCaptureMetadata {
 	return f.metadata
 }
 
-// ReadNext returns the next SensorData reading.
+// ReadNext returns the next SensorData reading. It returns io.EOF once every reading has been read, and
+// io.ErrUnexpectedEOF if the file ends part way through a reading, such as when the process writing it crashed.
 func (f *CaptureFile) ReadNext() (*v1.SensorData, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
-	if err := f.writer.Flush(); err != nil {
+	if err := f.flush(); err != nil {
 		return nil, err
 	}
 
+	r := v1.SensorData{}
+	if f.compression != CompressionNone {
+		for f.readBlock == nil || f.readBlock.Len() == 0 {
+			if err := f.readNextBlock(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := pbutil.ReadDelimited(f.readBlock, &r); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return &r, nil
+	}
+
 	if _, err := f.file.Seek(f.readOffset, io.SeekStart); err != nil {
 		return nil, err
 	}
-	r := v1.SensorData{}
 	read, err := pbutil.ReadDelimited(f.file, &r)
 	if err != nil {
+		if errors.Is(err, io.EOF) && f.readOffset+int64(read) != f.size {
+			return nil, io.ErrUnexpectedEOF
+		}
 		return nil, err
 	}
 	f.readOffset += int64(read)
@@ -137,11 +174,51 @@ func (f *CaptureFile) ReadNext() (*v1.SensorData, error) {
 	return &r, nil
 }
 
+// readNextBlock decompresses the block at the read offset of a compressed file.
+func (f *CaptureFile) readNextBlock() error {
+	if f.readOffset >= f.size {
+		return io.EOF
+	}
+	header := make([]byte, binary.MaxVarintLen64)
+	n, err := f.file.ReadAt(header, f.readOffset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	blockLen, headerLen := binary.Uvarint(header[:n])
+	if headerLen <= 0 || blockLen > uint64(f.size-f.readOffset-int64(headerLen)) {
+		return io.ErrUnexpectedEOF
+	}
+	block := make([]byte, blockLen)
+	if _, err := f.file.ReadAt(block, f.readOffset+int64(headerLen)); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	decompressed, err := decompressBlock(f.compression, block)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decompress block at offset %d of %s", f.readOffset, f.path)
+	}
+	f.readBlock = bytes.NewReader(decompressed)
+	f.readOffset += int64(headerLen) + int64(blockLen)
+	return nil
+}
+
 // WriteNext writes the next SensorData reading.
 func (f *CaptureFile) WriteNext(data *v1.SensorData) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	if f.compression != CompressionNone {
+		if _, err := pbutil.WriteDelimited(&f.pendingBlock, data); err != nil {
+			return err
+		}
+		if f.pendingBlock.Len() >= captureBlockSize {
+			return f.writeBlock()
+		}
+		return nil
+	}
+
 	if _, err := f.file.Seek(f.writeOffset, 0); err != nil {
 		return err
 	}
@@ -154,11 +231,41 @@ func (f *CaptureFile) WriteNext(data *v1.SensorData) error {
 	return nil
 }
 
+// writeBlock compresses the pending readings of a compressed file and writes them as a block.
+func (f *CaptureFile) writeBlock() error {
+	if f.pendingBlock.Len() == 0 {
+		return nil
+	}
+	block, err := compressBlock(f.compression, f.pendingBlock.Bytes())
+	if err != nil {
+		return err
+	}
+	header := binary.AppendUvarint(nil, uint64(len(block)))
+	if _, err := f.writer.Write(header); err != nil {
+		return err
+	}
+	if _, err := f.writer.Write(block); err != nil {
+		return err
+	}
+	f.pendingBlock.Reset()
+	n := int64(len(header) + len(block))
+	f.size += n
+	f.writeOffset += n
+	return nil
+}
+
+func (f *CaptureFile) flush() error {
+	if err := f.writeBlock(); err != nil {
+		return err
+	}
+	return f.writer.Flush()
+}
+
 // Flush flushes any buffered writes to disk.
 func (f *CaptureFile) Flush() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	return f.writer.Flush()
+	return f.flush()
 }
 
 // Reset resets the read pointer of f.
@@ -166,13 +273,20 @@ func (f *CaptureFile) Reset() {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 	f.readOffset = f.initialReadOffset
+	f.readBlock = nil
 }
 
-// Size returns the size of the file.
+// Size returns the size of the file. For compressed files this includes the uncompressed size of readings which
+// have not yet been compressed.
 func (f *CaptureFile) Size() int64 {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	return f.size
+	return f.size + int64(f.pendingBlock.Len())
+}
+
+// Compression returns the codec the readings of f are compressed with.
+func (f *CaptureFile) Compression() string {
+	return f.compression
 }
 
 // GetPath returns the path of the underlying os.File.
@@ -184,7 +298,7 @@ func (f *CaptureFile) GetPath() string {
 func (f *CaptureFile) Close() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	if err := f.writer.Flush(); err != nil {
+	if err := f.flush(); err != nil {
 		return err
 	}
 
@@ -23,6 +23,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 	"go.viam.com/rdk/resource"
 )
 
@@ -56,6 +57,8 @@ type Collector interface {
 }
 
 type collector struct {
+	componentName  string
+	methodName     string
 	clock          clock.Clock
 	captureResults chan *v1.SensorData
 	captureErrors  chan error
@@ -71,6 +74,9 @@ type collector struct {
 	captureFunc      CaptureFunc
 	target           CaptureBufferedWriter
 	lastLoggedErrors map[string]int64
+	// trigger and triggerBuffer are only set on triggered collectors
+	trigger       *TriggerParams
+	triggerBuffer *triggerBuffer
 }
 
 // Close closes the channels backing the Collector. It should always be called before disposing of a Collector to avoid
@@ -90,6 +96,7 @@ func (c *collector) Close() {
 
 	close(c.captureErrors)
 	c.logRoutine.Wait()
+	metrics.CaptureQueueDepth.DeleteLabelValues(c.componentName, c.methodName)
 }
 
 func (c *collector) Flush() {
@@ -111,6 +118,10 @@ func (c *collector) Collect() {
 	utils.ManagedGo(func() { c.capture(started) }, c.captureWorkers.Done)
 	c.captureWorkers.Add(1)
 	utils.ManagedGo(c.writeCaptureResults, c.captureWorkers.Done)
+	if c.trigger != nil {
+		c.captureWorkers.Add(1)
+		utils.ManagedGo(c.pollTrigger, c.captureWorkers.Done)
+	}
 	c.logRoutine.Add(1)
 	utils.ManagedGo(c.logCaptureErrs, c.logRoutine.Done)
 
@@ -185,6 +196,7 @@ func (c *collector) getAndPushNextReading() {
 			c.logger.Debug("capture filtered out by modular resource")
 			return
 		}
+		c.dropReading("capture_error")
 		c.captureErrors <- errors.Wrap(err, "error while capturing data")
 		return
 	}
@@ -218,6 +230,7 @@ func (c *collector) getAndPushNextReading() {
 		} else {
 			pbReading, err = protoutils.StructToStructPbIgnoreOmitEmpty(reading)
 			if err != nil {
+				c.dropReading("capture_error")
 				c.captureErrors <- errors.Wrap(err, "error while converting reading to structpb.Struct")
 				return
 			}
@@ -239,11 +252,54 @@ func (c *collector) getAndPushNextReading() {
 	// still work when this happens.
 	case <-c.cancelCtx.Done():
 	case c.captureResults <- &msg:
+		c.updateQueueDepth()
+	}
+}
+
+// dropReading records a reading that failed to be captured or written.
+func (c *collector) dropReading(reason string) {
+	metrics.CaptureDroppedReadings.WithLabelValues(c.componentName, c.methodName, reason).Inc()
+}
+
+func (c *collector) updateQueueDepth() {
+	metrics.CaptureQueueDepth.WithLabelValues(c.componentName, c.methodName).Set(float64(len(c.captureResults)))
+}
+
+// pollTrigger evaluates the trigger condition every trigger interval, writing the buffered readings to the target
+// when it is met.
+func (c *collector) pollTrigger() {
+	ticker := c.clock.Ticker(c.trigger.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.cancelCtx.Err(); err != nil {
+			return
+		}
+
+		select {
+		case <-c.cancelCtx.Done():
+			return
+		case <-ticker.C:
+			triggered, err := c.trigger.Func(c.cancelCtx)
+			if c.cancelCtx.Err() != nil {
+				return
+			}
+			if err != nil {
+				c.captureErrors <- errors.Wrap(err, "error while evaluating capture trigger")
+				continue
+			}
+			if !triggered {
+				continue
+			}
+			if err := c.triggerBuffer.fire(c.clock.Now()); err != nil {
+				c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write to collector %s", c.target.Path())).Error())
+			}
+		}
 	}
 }
 
 // NewCollector returns a new Collector with the passed capturer and configuration options. It calls capturer at the
-// specified Interval, and appends the resulting reading to target.
+// specified Interval, and appends the resulting reading to target. If params.Trigger is set, readings are only
+// appended to target around the times the trigger condition is met.
 func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, error) {
 	if err := params.Validate(); err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to construct collector for %s", params.ComponentName))
@@ -256,7 +312,9 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 	} else {
 		c = params.Clock
 	}
-	return &collector{
+	coll := &collector{
+		componentName:    params.ComponentName,
+		methodName:       params.MethodName,
 		captureResults:   make(chan *v1.SensorData, params.QueueSize),
 		captureErrors:    make(chan error, params.QueueSize),
 		interval:         params.Interval,
@@ -268,7 +326,13 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		target:           params.Target,
 		clock:            c,
 		lastLoggedErrors: make(map[string]int64, 0),
-	}, nil
+	}
+	if params.Trigger != nil {
+		trigger := *params.Trigger
+		coll.trigger = &trigger
+		coll.triggerBuffer = newTriggerBuffer(params.Target, trigger)
+	}
+	return coll, nil
 }
 
 func (c *collector) writeCaptureResults() {
@@ -281,7 +345,9 @@ func (c *collector) writeCaptureResults() {
 		case <-c.cancelCtx.Done():
 			return
 		case msg := <-c.captureResults:
-			if err := c.target.Write(msg); err != nil {
+			c.updateQueueDepth()
+			if err := c.write(msg); err != nil {
+				c.dropReading("write_error")
 				c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write to collector %s", c.target.Path())).Error())
 				return
 			}
@@ -289,6 +355,13 @@ func (c *collector) writeCaptureResults() {
 	}
 }
 
+func (c *collector) write(msg *v1.SensorData) error {
+	if c.triggerBuffer != nil {
+		return c.triggerBuffer.write(msg, c.clock.Now())
+	}
+	return c.target.Write(msg)
+}
+
 func (c *collector) logCaptureErrs() {
 	for err := range c.captureErrors {
 		now := c.clock.Now().Unix()
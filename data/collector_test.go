@@ -11,13 +11,16 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap/zapcore"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 )
 
 var (
@@ -312,6 +315,36 @@ func TestLogErrorsOnlyOnce(t *testing.T) {
 	c.Close()
 }
 
+func TestDroppedReadingsMetric(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	errorCapturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		return nil, errors.New("I am an error")
+	})
+	mockClock := clock.NewMock()
+	interval := time.Millisecond * 5
+
+	c, err := NewCollector(errorCapturer, CollectorParams{
+		ComponentName: "droppingComponent",
+		MethodName:    "Readings",
+		Interval:      interval,
+		Target:        NewCaptureBuffer(t.TempDir(), &v1.DataCaptureMetadata{}, 50),
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        logger,
+		Clock:         mockClock,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	mockClock.Add(interval * 3)
+
+	dropped := metrics.CaptureDroppedReadings.WithLabelValues("droppingComponent", "Readings", "capture_error")
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, testutil.ToFloat64(dropped), test.ShouldBeGreaterThanOrEqualTo, 1)
+	})
+	c.Close()
+}
+
 func validateReadings(t *testing.T, act []*v1.SensorData, n int) {
 	t.Helper()
 	for i := 0; i < n; i++ {
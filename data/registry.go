@@ -18,6 +18,7 @@ type CollectorConstructor func(resource interface{}, params CollectorParams) (Co
 // CollectorParams contain the parameters needed to construct a Collector.
 type CollectorParams struct {
 	ComponentName string
+	MethodName    string
 	Interval      time.Duration
 	MethodParams  map[string]*anypb.Any
 	Target        CaptureBufferedWriter
@@ -25,6 +26,8 @@ type CollectorParams struct {
 	BufferSize    int
 	Logger        logging.Logger
 	Clock         clock.Clock
+	// Trigger, if set, makes the collector only keep the readings captured around when its condition is met.
+	Trigger *TriggerParams
 }
 
 // Validate validates that p contains all required parameters.
@@ -38,6 +41,11 @@ func (p CollectorParams) Validate() error {
 	if p.ComponentName == "" {
 		return errors.New("missing required parameter component name")
 	}
+	if p.Trigger != nil {
+		if err := p.Trigger.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
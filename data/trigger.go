@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+)
+
+// TriggerFunc returns true while the condition a triggered collector captures around is met.
+type TriggerFunc func(ctx context.Context) (bool, error)
+
+// TriggerParams configure a collector to only write the readings it captures around the times Func returns true,
+// rather than every reading.
+type TriggerParams struct {
+	// Func is called every Interval to evaluate the trigger condition.
+	Func     TriggerFunc
+	Interval time.Duration
+	// PreTrigger is how long readings are kept in memory in case the trigger fires.
+	PreTrigger time.Duration
+	// PostTrigger is how long readings continue to be written after the trigger condition was last met.
+	PostTrigger time.Duration
+}
+
+// Validate validates that p contains all required parameters.
+func (p TriggerParams) Validate() error {
+	if p.Func == nil {
+		return errors.New("missing required trigger parameter func")
+	}
+	if p.Interval <= 0 {
+		return errors.New("trigger interval must be positive")
+	}
+	if p.PreTrigger < 0 || p.PostTrigger < 0 {
+		return errors.New("trigger windows can't be negative")
+	}
+	return nil
+}
+
+// triggerBuffer holds the readings of a triggered collector in memory until its trigger fires. Readings older than the
+// pre trigger window are dropped as new readings arrive, so the buffer acts as a ring of the last PreTrigger of data.
+// Once the trigger fires the buffered readings are written to the target, as is every reading until PostTrigger has
+// elapsed since the trigger condition was last met.
+type triggerBuffer struct {
+	mu           sync.Mutex
+	target       CaptureBufferedWriter
+	preTrigger   time.Duration
+	postTrigger  time.Duration
+	pending      []*v1.SensorData
+	captureUntil time.Time
+}
+
+func newTriggerBuffer(target CaptureBufferedWriter, params TriggerParams) *triggerBuffer {
+	return &triggerBuffer{target: target, preTrigger: params.PreTrigger, postTrigger: params.PostTrigger}
+}
+
+// write writes msg to the target if the trigger fired within the post trigger window of now, otherwise it buffers it.
+func (b *triggerBuffer) write(msg *v1.SensorData, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !now.After(b.captureUntil) {
+		return b.target.Write(msg)
+	}
+
+	b.pending = append(b.pending, msg)
+	cutoff := now.Add(-b.preTrigger)
+	expired := 0
+	for _, pending := range b.pending {
+		if !pending.GetMetadata().GetTimeReceived().AsTime().Before(cutoff) {
+			break
+		}
+		expired++
+	}
+	if expired > 0 {
+		// copy rather than reslice so that the backing array doesn't grow without bound
+		b.pending = append(b.pending[:0], b.pending[expired:]...)
+	}
+	return nil
+}
+
+// fire writes the buffered readings to the target and starts a post trigger window at now.
+func (b *triggerBuffer) fire(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.captureUntil = now.Add(b.postTrigger)
+	pending := b.pending
+	b.pending = nil
+	for _, msg := range pending {
+		if err := b.target.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// len returns the number of buffered readings.
+func (b *triggerBuffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/logging"
+)
+
+type recordingBuffer struct {
+	written []*v1.SensorData
+}
+
+func (b *recordingBuffer) Write(item *v1.SensorData) error {
+	b.written = append(b.written, item)
+	return nil
+}
+
+func (b *recordingBuffer) Flush() error {
+	return nil
+}
+
+func (b *recordingBuffer) Path() string {
+	return "/recording"
+}
+
+func readingAt(t time.Time) *v1.SensorData {
+	return &v1.SensorData{Metadata: &v1.SensorMetadata{TimeReceived: timestamppb.New(t)}}
+}
+
+func TestTriggerBuffer(t *testing.T) {
+	target := &recordingBuffer{}
+	b := newTriggerBuffer(target, TriggerParams{PreTrigger: 2 * time.Second, PostTrigger: 3 * time.Second})
+	start := time.Now()
+	at := func(secs int) time.Time {
+		return start.Add(time.Duration(secs) * time.Second)
+	}
+
+	// readings are buffered until the trigger fires, keeping only the pre trigger window
+	for i := 0; i <= 5; i++ {
+		test.That(t, b.write(readingAt(at(i)), at(i)), test.ShouldBeNil)
+	}
+	test.That(t, target.written, test.ShouldBeEmpty)
+	test.That(t, b.len(), test.ShouldEqual, 3)
+
+	test.That(t, b.fire(at(5)), test.ShouldBeNil)
+	test.That(t, b.len(), test.ShouldEqual, 0)
+	test.That(t, len(target.written), test.ShouldEqual, 3)
+	test.That(t, target.written[0].GetMetadata().GetTimeReceived().AsTime(), test.ShouldEqual, at(3))
+
+	// readings within the post trigger window are written directly
+	for i := 6; i <= 8; i++ {
+		test.That(t, b.write(readingAt(at(i)), at(i)), test.ShouldBeNil)
+	}
+	test.That(t, len(target.written), test.ShouldEqual, 6)
+
+	// after which they are buffered again
+	test.That(t, b.write(readingAt(at(9)), at(9)), test.ShouldBeNil)
+	test.That(t, len(target.written), test.ShouldEqual, 6)
+	test.That(t, b.len(), test.ShouldEqual, 1)
+}
+
+func TestTriggerParams(t *testing.T) {
+	trigger := func(ctx context.Context) (bool, error) { return false, nil }
+	params := CollectorParams{
+		ComponentName: "name",
+		Logger:        logging.NewTestLogger(t),
+		Target:        &recordingBuffer{},
+	}
+
+	params.Trigger = &TriggerParams{Func: trigger}
+	_, err := NewCollector(structCapturer, params)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	params.Trigger = &TriggerParams{Func: trigger, Interval: time.Second, PreTrigger: -time.Second}
+	_, err = NewCollector(structCapturer, params)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	params.Trigger = &TriggerParams{Func: trigger, Interval: time.Second, PreTrigger: time.Second, PostTrigger: time.Second}
+	c, err := NewCollector(structCapturer, params)
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	c.Close()
+}
@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// rotatedFileTimeFormat is the format of the timestamp added to the names of rotated log files. It sorts in the same
+// order as the time it formats.
+const rotatedFileTimeFormat = "2006-01-02T15-04-05.000"
+
+// FileAppenderConfig configures a FileAppender.
+type FileAppenderConfig struct {
+	// Path is the path of the file logs are written to.
+	Path string
+	// MaxSizeMB is the size in megabytes the file is rotated at. Zero disables size based rotation.
+	MaxSizeMB int
+	// RotateInterval is how long logs are written to a file before it is rotated. Zero disables time based rotation.
+	RotateInterval time.Duration
+	// MaxBackups is the number of rotated files kept. Zero keeps all of them.
+	MaxBackups int
+	// MaxAge is how long rotated files are kept for. Zero keeps them regardless of age.
+	MaxAge time.Duration
+}
+
+// FileAppender writes log entries as JSON lines to a file, which it rotates by size and age. Rotated files are
+// renamed with the time they were rotated at, e.g: "viam-2024-01-02T15-04-05.000.log", and removed according to
+// the configured retention.
+type FileAppender struct {
+	cfg     FileAppenderConfig
+	encoder zapcore.Encoder
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewFileAppender returns a FileAppender writing to the file configured, creating it if needed.
+func NewFileAppender(cfg FileAppenderConfig) (*FileAppender, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("file appender requires a path")
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, err
+	}
+	appender := &FileAppender{cfg: cfg, encoder: newJSONEncoder()}
+	if err := appender.open(); err != nil {
+		return nil, err
+	}
+	return appender, nil
+}
+
+func newJSONEncoder() zapcore.Encoder {
+	return zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:        "ts",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	})
+}
+
+func (appender *FileAppender) open() error {
+	//nolint:gosec
+	file, err := os.OpenFile(appender.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return multierr.Combine(err, file.Close())
+	}
+	appender.file = file
+	appender.size = info.Size()
+	appender.openedAt = time.Now()
+	return nil
+}
+
+// Write writes the log entry to the file as a JSON line, rotating the file first if it is due.
+func (appender *FileAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Time = entry.Time.UTC()
+	buf, err := appender.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	if appender.file == nil {
+		return fmt.Errorf("file appender for %s is closed", appender.cfg.Path)
+	}
+	if appender.dueForRotation(int64(buf.Len())) {
+		if err := appender.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := appender.file.Write(buf.Bytes())
+	appender.size += int64(n)
+	return err
+}
+
+func (appender *FileAppender) dueForRotation(writeSize int64) bool {
+	if appender.size == 0 {
+		return false
+	}
+	if appender.cfg.MaxSizeMB > 0 && appender.size+writeSize > int64(appender.cfg.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return appender.cfg.RotateInterval > 0 && time.Since(appender.openedAt) >= appender.cfg.RotateInterval
+}
+
+// rotate moves the current file aside, opens a new one, and removes the rotated files past retention.
+func (appender *FileAppender) rotate() error {
+	if err := appender.file.Close(); err != nil {
+		return err
+	}
+	appender.file = nil
+	ext := filepath.Ext(appender.cfg.Path)
+	base := strings.TrimSuffix(appender.cfg.Path, ext)
+	rotated := fmt.Sprintf("%s-%s%s", base, time.Now().UTC().Format(rotatedFileTimeFormat), ext)
+	if err := os.Rename(appender.cfg.Path, rotated); err != nil {
+		return err
+	}
+	if err := appender.open(); err != nil {
+		return err
+	}
+	return appender.removeExpired()
+}
+
+// removeExpired removes the rotated files past the configured number of backups or age.
+func (appender *FileAppender) removeExpired() error {
+	if appender.cfg.MaxBackups <= 0 && appender.cfg.MaxAge <= 0 {
+		return nil
+	}
+	ext := filepath.Ext(appender.cfg.Path)
+	base := strings.TrimSuffix(appender.cfg.Path, ext)
+	rotated, err := filepath.Glob(escapeGlob(base) + "-*" + escapeGlob(ext))
+	if err != nil {
+		return err
+	}
+	// newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+
+	var errs error
+	for idx, path := range rotated {
+		expired := appender.cfg.MaxBackups > 0 && idx >= appender.cfg.MaxBackups
+		if !expired && appender.cfg.MaxAge > 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				errs = multierr.Combine(errs, err)
+				continue
+			}
+			expired = time.Since(info.ModTime()) > appender.cfg.MaxAge
+		}
+		if expired {
+			errs = multierr.Combine(errs, os.Remove(path))
+		}
+	}
+	return errs
+}
+
+func escapeGlob(path string) string {
+	replacer := strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return replacer.Replace(path)
+}
+
+// Sync flushes the file to disk.
+func (appender *FileAppender) Sync() error {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	if appender.file == nil {
+		return nil
+	}
+	return appender.file.Sync()
+}
+
+// Close closes the file.
+func (appender *FileAppender) Close() error {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	if appender.file == nil {
+		return nil
+	}
+	err := appender.file.Close()
+	appender.file = nil
+	return err
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+)
+
+func readJSONLines(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	//nolint:gosec
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		test.That(t, json.Unmarshal(scanner.Bytes(), &line), test.ShouldBeNil)
+		lines = append(lines, line)
+	}
+	test.That(t, scanner.Err(), test.ShouldBeNil)
+	return lines
+}
+
+func TestFileAppender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "viam.log")
+	appender, err := NewFileAppender(FileAppenderConfig{Path: path})
+	test.That(t, err, test.ShouldBeNil)
+
+	entry := zapcore.Entry{Level: zapcore.WarnLevel, LoggerName: "rdk.test", Message: "hello", Time: time.Now()}
+	test.That(t, appender.Write(entry, []zapcore.Field{zap.String("resource", "arm1"), zap.Int("attempt", 2)}), test.ShouldBeNil)
+	test.That(t, appender.Sync(), test.ShouldBeNil)
+	test.That(t, appender.Close(), test.ShouldBeNil)
+
+	lines := readJSONLines(t, path)
+	test.That(t, lines, test.ShouldHaveLength, 1)
+	test.That(t, lines[0]["level"], test.ShouldEqual, "warn")
+	test.That(t, lines[0]["logger"], test.ShouldEqual, "rdk.test")
+	test.That(t, lines[0]["msg"], test.ShouldEqual, "hello")
+	test.That(t, lines[0]["resource"], test.ShouldEqual, "arm1")
+	test.That(t, lines[0]["attempt"], test.ShouldEqual, 2.0)
+
+	test.That(t, appender.Write(entry, nil), test.ShouldNotBeNil)
+}
+
+func TestFileAppenderRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "viam.log")
+	appender, err := NewFileAppender(FileAppenderConfig{Path: path, MaxSizeMB: 1, MaxBackups: 2})
+	test.That(t, err, test.ShouldBeNil)
+	defer appender.Close()
+
+	// each entry is a little over 100KB, so every 10th entry rotates the file
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: strings.Repeat("x", 100*1024)}
+	for i := 0; i < 35; i++ {
+		test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+		// rotated files are named by the millisecond they were rotated at
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "viam-*.log"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rotated, test.ShouldHaveLength, 2)
+	for _, file := range append(rotated, path) {
+		info, err := os.Stat(file)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, info.Size(), test.ShouldBeLessThanOrEqualTo, 1024*1024)
+	}
+	test.That(t, readJSONLines(t, path), test.ShouldHaveLength, 5)
+}
+
+func TestFileAppenderRotateInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "viam.log")
+	appender, err := NewFileAppender(FileAppenderConfig{Path: path, RotateInterval: 50 * time.Millisecond, MaxAge: time.Hour})
+	test.That(t, err, test.ShouldBeNil)
+	defer appender.Close()
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "hello"}
+	test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+	test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+	time.Sleep(60 * time.Millisecond)
+	test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "viam-*.log"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rotated, test.ShouldHaveLength, 1)
+	test.That(t, readJSONLines(t, rotated[0]), test.ShouldHaveLength, 2)
+	test.That(t, readJSONLines(t, path), test.ShouldHaveLength, 1)
+}
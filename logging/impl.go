@@ -104,6 +104,9 @@ func (imp *impl) Sync() error {
 			errs = append(errs, err)
 		}
 	}
+	if err := imp.registry.syncAppenders(imp.name); err != nil {
+		errs = append(errs, err)
+	}
 
 	return multierr.Combine(errs...)
 }
@@ -204,6 +207,7 @@ func (imp *impl) Write(entry *LogEntry) {
 			fmt.Fprint(os.Stderr, err)
 		}
 	}
+	imp.registry.writeToAppenders(imp.name, entry)
 }
 
 // Constructs the log message by forwarding to `fmt.Sprint`. `traceKey` may be the empty string.
@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// journaldSocket is the socket systemd-journald receives entries on, in its native protocol.
+const journaldSocket = "/run/systemd/journal/socket"
+
+// JournaldAppender sends log entries to systemd-journald with their fields as structured journal fields. The name
+// of each field is upper cased with any characters journald does not allow replaced by underscores, e.g: a
+// "resource" field is stored as RESOURCE.
+type JournaldAppender struct {
+	identifier string
+	socket     string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewJournaldAppender returns a JournaldAppender sending entries tagged with the given syslog identifier.
+func NewJournaldAppender(identifier string) (*JournaldAppender, error) {
+	return newJournaldAppender(identifier, journaldSocket)
+}
+
+func newJournaldAppender(identifier, socket string) (*JournaldAppender, error) {
+	conn, err := net.Dial("unixgram", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to journald: %w", err)
+	}
+	return &JournaldAppender{identifier: identifier, socket: socket, conn: conn}, nil
+}
+
+// Write sends the log entry to journald.
+func (appender *JournaldAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var msg bytes.Buffer
+	writeJournaldField(&msg, "MESSAGE", entry.Message)
+	writeJournaldField(&msg, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	writeJournaldField(&msg, "SYSLOG_IDENTIFIER", appender.identifier)
+	writeJournaldField(&msg, "LOGGER", entry.LoggerName)
+	if entry.Caller.Defined {
+		writeJournaldField(&msg, "CODE_FILE", entry.Caller.File)
+		writeJournaldField(&msg, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+	}
+	for _, field := range fieldValues(fields) {
+		writeJournaldField(&msg, journaldFieldName(field.key), field.value)
+	}
+
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	if appender.conn == nil {
+		return fmt.Errorf("journald appender is closed")
+	}
+	_, err := appender.conn.Write(msg.Bytes())
+	return err
+}
+
+// writeJournaldField writes a field in journald's native protocol, which requires values spanning lines to be
+// prefixed with their length.
+func writeJournaldField(buf *bytes.Buffer, name, value string) {
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteString(name + "=" + value + "\n")
+		return
+	}
+	buf.WriteString(name + "\n")
+	//nolint:errcheck
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value + "\n")
+}
+
+// journaldFieldName returns a field name journald accepts: upper case letters, digits and underscores, not
+// starting with an underscore or a digit.
+func journaldFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		r = unicode.ToUpper(r)
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "FIELD_" + name
+	}
+	return name
+}
+
+// Sync is a no-op.
+func (appender *JournaldAppender) Sync() error {
+	return nil
+}
+
+// Close closes the connection to journald.
+func (appender *JournaldAppender) Close() error {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	if appender.conn == nil {
+		return nil
+	}
+	err := appender.conn.Close()
+	appender.conn = nil
+	return err
+}
+
+type fieldValue struct {
+	key   string
+	value string
+}
+
+// fieldValues returns the fields of a log entry as strings, in order. Values that are not strings are JSON
+// encoded.
+func fieldValues(fields []zapcore.Field) []fieldValue {
+	enc := zapcore.NewMapObjectEncoder()
+	values := make([]fieldValue, 0, len(fields))
+	for _, field := range fields {
+		field.AddTo(enc)
+		value := enc.Fields[field.Key]
+		delete(enc.Fields, field.Key)
+
+		var str string
+		switch v := value.(type) {
+		case string:
+			str = v
+		case nil:
+			continue
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				str = fmt.Sprint(v)
+			} else {
+				str = string(encoded)
+			}
+		}
+		values = append(values, fieldValue{key: field.Key, value: str})
+	}
+	return values
+}
+
+// syslogSeverity returns the syslog severity of a log level, as used by journald and syslog.
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel, zapcore.InvalidLevel:
+		return 2
+	default:
+		return 2
+	}
+}
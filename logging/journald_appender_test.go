@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+)
+
+func TestJournaldAppender(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+
+	appender, err := newJournaldAppender("viam-server", socket)
+	test.That(t, err, test.ShouldBeNil)
+	defer appender.Close()
+
+	entry := zapcore.Entry{Level: zapcore.WarnLevel, LoggerName: "rdk.modmanager", Message: "module crashed"}
+	fields := []zapcore.Field{zap.String("module", "my-module"), zap.String("last output", "line 1\nline 2")}
+	test.That(t, appender.Write(entry, fields), test.ShouldBeNil)
+
+	buf := make([]byte, 2048)
+	test.That(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+	n, err := conn.Read(buf)
+	test.That(t, err, test.ShouldBeNil)
+
+	multiline := "line 1\nline 2"
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, uint64(len(multiline)))
+	expected := "MESSAGE=module crashed\n" +
+		"PRIORITY=4\n" +
+		"SYSLOG_IDENTIFIER=viam-server\n" +
+		"LOGGER=rdk.modmanager\n" +
+		"MODULE=my-module\n" +
+		"LAST_OUTPUT\n" + string(length) + multiline + "\n"
+	test.That(t, string(buf[:n]), test.ShouldEqual, expected)
+}
+
+func TestJournaldFieldName(t *testing.T) {
+	test.That(t, journaldFieldName("resource"), test.ShouldEqual, "RESOURCE")
+	test.That(t, journaldFieldName("_private.key"), test.ShouldEqual, "PRIVATE_KEY")
+	test.That(t, journaldFieldName("2fa"), test.ShouldEqual, "FIELD_2FA")
+}
@@ -1,14 +1,132 @@
 package logging
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
-// LoggerPatternConfig is an instance of a level specification for a given logger.
+// LoggerPatternConfig is an instance of a level specification for a given logger, along with any appenders the
+// logger's entries are also written to. An empty level leaves the level of the matching loggers as it is.
 type LoggerPatternConfig struct {
-	Pattern string `json:"pattern"`
-	Level   string `json:"level"`
+	Pattern   string           `json:"pattern"`
+	Level     string           `json:"level"`
+	Appenders []AppenderConfig `json:"appenders,omitempty"`
+}
+
+// The types of appenders that may be configured.
+const (
+	AppenderTypeFile     = "file"
+	AppenderTypeJournald = "journald"
+	AppenderTypeSyslog   = "syslog"
+)
+
+// AppenderConfig configures an appender that receives the log entries of the loggers matching the pattern it is
+// configured under.
+//
+//	"log": [
+//		{
+//			"pattern": "rdk.resource_manager.*",
+//			"level": "debug",
+//			"appenders": [
+//				{"type": "file", "path": "/var/log/viam/resources.log", "max_size_mb": 10, "max_backups": 5},
+//				{"type": "syslog", "network": "udp", "address": "logs.local:514", "level": "warn"}
+//			]
+//		}
+//	]
+type AppenderConfig struct {
+	// Type is one of "file", "journald" or "syslog".
+	Type string `json:"type"`
+	// Level is the minimum level of the entries written to the appender. Entries are only written when they are at
+	// least the level of the logger too, so a level below that of the loggers the appender is configured for has
+	// no effect, and is warned about when it's below the level configured with the appender.
+	Level string `json:"level,omitempty"`
+
+	// Path is the file a "file" appender writes to.
+	Path string `json:"path,omitempty"`
+	// MaxSizeMB is the size a "file" appender rotates its file at.
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+	// RotateInterval is how often a "file" appender rotates its file, e.g: "24h".
+	RotateInterval string `json:"rotate_interval,omitempty"`
+	// MaxBackups is the number of rotated files a "file" appender keeps.
+	MaxBackups int `json:"max_backups,omitempty"`
+	// MaxAge is how long a "file" appender keeps rotated files for, e.g: "168h".
+	MaxAge string `json:"max_age,omitempty"`
+
+	// Network is the network a "syslog" appender sends messages over: "udp", "tcp" or "unix". Defaults to the
+	// local syslog daemon.
+	Network string `json:"network,omitempty"`
+	// Address is the address of the server a "syslog" appender sends messages to.
+	Address string `json:"address,omitempty"`
+	// Facility is the facility a "syslog" appender logs as. Defaults to "user".
+	Facility string `json:"facility,omitempty"`
+
+	// Identifier is the syslog identifier or APP-NAME of a "journald" or "syslog" appender. Defaults to
+	// "viam-server".
+	Identifier string `json:"identifier,omitempty"`
+}
+
+const defaultAppenderIdentifier = "viam-server"
+
+// NewAppenderFromConfig returns the appender configured by cfg, and the minimum level of the entries to write to
+// it.
+func NewAppenderFromConfig(cfg AppenderConfig) (Appender, Level, error) {
+	level := DEBUG
+	if cfg.Level != "" {
+		var err error
+		if level, err = LevelFromString(cfg.Level); err != nil {
+			return nil, level, err
+		}
+	}
+	identifier := cfg.Identifier
+	if identifier == "" {
+		identifier = defaultAppenderIdentifier
+	}
+
+	switch cfg.Type {
+	case AppenderTypeFile:
+		rotateInterval, err := parseOptionalDuration("rotate_interval", cfg.RotateInterval)
+		if err != nil {
+			return nil, level, err
+		}
+		maxAge, err := parseOptionalDuration("max_age", cfg.MaxAge)
+		if err != nil {
+			return nil, level, err
+		}
+		appender, err := NewFileAppender(FileAppenderConfig{
+			Path:           cfg.Path,
+			MaxSizeMB:      cfg.MaxSizeMB,
+			RotateInterval: rotateInterval,
+			MaxBackups:     cfg.MaxBackups,
+			MaxAge:         maxAge,
+		})
+		return appender, level, err
+	case AppenderTypeJournald:
+		appender, err := NewJournaldAppender(identifier)
+		return appender, level, err
+	case AppenderTypeSyslog:
+		appender, err := NewSyslogAppender(SyslogAppenderConfig{
+			Network:  cfg.Network,
+			Address:  cfg.Address,
+			Facility: cfg.Facility,
+			AppName:  identifier,
+		})
+		return appender, level, err
+	default:
+		return nil, level, fmt.Errorf("unknown appender type %q", cfg.Type)
+	}
+}
+
+func parseOptionalDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	dur, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return dur, nil
 }
 
 const (
@@ -2,22 +2,49 @@ package logging
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"regexp"
 	"sync"
+
+	"go.uber.org/multierr"
 )
 
 // Registry is a registry of loggers. It is stored on a logger, and holds a map
 // of known subloggers (`loggers`) and a slice of configuration objects
-// (`logConfig`).
+// (`logConfig`). It also holds the appenders configured for loggers matching
+// a pattern, which every logger in the registry writes to.
 type Registry struct {
 	mu        sync.RWMutex
 	loggers   map[string]Logger
 	logConfig []LoggerPatternConfig
+
+	appendersMu sync.RWMutex
+	appenders   []*patternAppender
+	// appendersFor caches the appenders each logger name matches.
+	appendersFor map[string][]*patternAppender
+	// writesMu is held for reading across writes to the appenders, so that appenders no longer
+	// configured are only closed once the writes in flight to them are done.
+	writesMu sync.RWMutex
+}
+
+// patternAppender is an appender configured for the loggers matching a pattern.
+type patternAppender struct {
+	key      patternAppenderKey
+	matcher  *regexp.Regexp
+	level    Level
+	appender Appender
+}
+
+type patternAppenderKey struct {
+	pattern string
+	cfg     AppenderConfig
 }
 
 func newRegistry() *Registry {
 	return &Registry{
-		loggers: make(map[string]Logger),
+		loggers:      make(map[string]Logger),
+		appendersFor: make(map[string][]*patternAppender),
 	}
 }
 
@@ -58,6 +85,10 @@ func (lr *Registry) Update(logConfig []LoggerPatternConfig, warnLogger Logger) e
 			warnLogger.Warnw("failed to validate a pattern", "pattern", lpc.Pattern)
 			continue
 		}
+		if lpc.Level == "" {
+			// only configures appenders
+			continue
+		}
 
 		r, err := regexp.Compile(buildRegexFromPattern(lpc.Pattern))
 		if err != nil {
@@ -90,9 +121,120 @@ func (lr *Registry) Update(logConfig []LoggerPatternConfig, warnLogger Logger) e
 		}
 	}
 
+	lr.updateAppenders(logConfig, warnLogger)
 	return nil
 }
 
+// updateAppenders replaces the configured appenders with those in `logConfig`. Appenders configured the same way
+// as before are kept as they are, and those no longer configured are closed.
+func (lr *Registry) updateAppenders(logConfig []LoggerPatternConfig, warnLogger Logger) {
+	lr.appendersMu.RLock()
+	existing := make(map[patternAppenderKey]*patternAppender, len(lr.appenders))
+	for _, pa := range lr.appenders {
+		existing[pa.key] = pa
+	}
+	lr.appendersMu.RUnlock()
+
+	var appenders []*patternAppender
+	for _, lpc := range logConfig {
+		if len(lpc.Appenders) == 0 || !validatePattern(lpc.Pattern) {
+			continue
+		}
+		matcher, err := regexp.Compile(buildRegexFromPattern(lpc.Pattern))
+		if err != nil {
+			warnLogger.Warnw("failed to compile a pattern", "pattern", lpc.Pattern, "error", err)
+			continue
+		}
+		for _, cfg := range lpc.Appenders {
+			key := patternAppenderKey{lpc.Pattern, cfg}
+			if pa, ok := existing[key]; ok {
+				appenders = append(appenders, pa)
+				delete(existing, key)
+				continue
+			}
+			appender, level, err := NewAppenderFromConfig(cfg)
+			if err != nil {
+				warnLogger.Warnw("failed to create log appender", "pattern", lpc.Pattern, "type", cfg.Type, "error", err)
+				continue
+			}
+			if loggerLevel, err := LevelFromString(lpc.Level); err == nil && cfg.Level != "" && level < loggerLevel {
+				warnLogger.Warnw("log appender level is below the level of the loggers it is configured for, "+
+					"whose entries below their level are not written to it",
+					"pattern", lpc.Pattern, "type", cfg.Type, "appender_level", level, "level", loggerLevel)
+			}
+			appenders = append(appenders, &patternAppender{key: key, matcher: matcher, level: level, appender: appender})
+		}
+	}
+
+	lr.appendersMu.Lock()
+	lr.appenders = appenders
+	lr.appendersFor = make(map[string][]*patternAppender)
+	lr.appendersMu.Unlock()
+
+	// the old appenders are closed once the writes that may still be using them are done. Failures are warned
+	// about after, since warnLogger may write to the appenders.
+	closeErrs := make(map[*patternAppender]error)
+	lr.writesMu.Lock()
+	for _, pa := range existing {
+		if closer, ok := pa.appender.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				closeErrs[pa] = err
+			}
+		}
+	}
+	lr.writesMu.Unlock()
+	for pa, err := range closeErrs {
+		warnLogger.Warnw("failed to close log appender", "pattern", pa.key.pattern, "type", pa.key.cfg.Type, "error", err)
+	}
+}
+
+// matchingAppenders returns the configured appenders for the logger with the given name.
+func (lr *Registry) matchingAppenders(name string) []*patternAppender {
+	lr.appendersMu.RLock()
+	if len(lr.appenders) == 0 {
+		lr.appendersMu.RUnlock()
+		return nil
+	}
+	matching, ok := lr.appendersFor[name]
+	lr.appendersMu.RUnlock()
+	if ok {
+		return matching
+	}
+
+	lr.appendersMu.Lock()
+	defer lr.appendersMu.Unlock()
+	for _, pa := range lr.appenders {
+		if pa.matcher.MatchString(name) {
+			matching = append(matching, pa)
+		}
+	}
+	lr.appendersFor[name] = matching
+	return matching
+}
+
+func (lr *Registry) writeToAppenders(name string, entry *LogEntry) {
+	lr.writesMu.RLock()
+	defer lr.writesMu.RUnlock()
+	for _, pa := range lr.matchingAppenders(name) {
+		if entry.Level < pa.level.AsZap() {
+			continue
+		}
+		if err := pa.appender.Write(entry.Entry, entry.fields); err != nil {
+			fmt.Fprint(os.Stderr, err)
+		}
+	}
+}
+
+func (lr *Registry) syncAppenders(name string) error {
+	lr.writesMu.RLock()
+	defer lr.writesMu.RUnlock()
+	var errs error
+	for _, pa := range lr.matchingAppenders(name) {
+		errs = multierr.Combine(errs, pa.appender.Sync())
+	}
+	return errs
+}
+
 func (lr *Registry) getRegisteredLoggerNames() []string {
 	lr.mu.RLock()
 	defer lr.mu.RUnlock()
@@ -1,8 +1,15 @@
 package logging
 
 import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"go.uber.org/zap/zapcore"
 	"go.viam.com/test"
 )
 
@@ -162,3 +169,158 @@ func TestLoggerLevelReset(t *testing.T) {
 	test.That(t, ok, test.ShouldBeTrue)
 	test.That(t, logger.GetLevel().String(), test.ShouldEqual, "Info")
 }
+
+func TestRegistryAppenders(t *testing.T) {
+	logger, registry := NewLoggerWithRegistry("rdk")
+	resourceLogger := logger.Sublogger("resource_manager")
+	networkLogger := logger.Sublogger("networking")
+
+	dir := t.TempDir()
+	resourceLogs := filepath.Join(dir, "resources.log")
+	warnLogs := filepath.Join(dir, "warnings.log")
+	logConfig := []LoggerPatternConfig{
+		{Pattern: "rdk.resource_manager", Appenders: []AppenderConfig{{Type: AppenderTypeFile, Path: resourceLogs}}},
+		{Pattern: "rdk.*", Level: "info", Appenders: []AppenderConfig{{Type: AppenderTypeFile, Path: warnLogs, Level: "warn"}}},
+		{Pattern: "rdk.*", Appenders: []AppenderConfig{{Type: "carrier-pigeon"}}},
+	}
+	warnLogger, warnings := NewObservedTestLogger(t)
+	test.That(t, registry.Update(logConfig, warnLogger), test.ShouldBeNil)
+	test.That(t, warnings.FilterMessage("failed to create log appender").Len(), test.ShouldEqual, 1)
+
+	resourceLogger.Infow("built resource", "name", "arm1")
+	resourceLogger.Warn("resource is slow")
+	networkLogger.Info("connected")
+	networkLogger.Error("disconnected")
+
+	lines := readJSONLines(t, resourceLogs)
+	test.That(t, lines, test.ShouldHaveLength, 2)
+	test.That(t, lines[0]["msg"], test.ShouldEqual, "built resource")
+	test.That(t, lines[0]["name"], test.ShouldEqual, "arm1")
+	test.That(t, lines[1]["msg"], test.ShouldEqual, "resource is slow")
+
+	lines = readJSONLines(t, warnLogs)
+	test.That(t, lines, test.ShouldHaveLength, 2)
+	test.That(t, lines[0]["msg"], test.ShouldEqual, "resource is slow")
+	test.That(t, lines[1]["msg"], test.ShouldEqual, "disconnected")
+	test.That(t, lines[1]["logger"], test.ShouldEqual, "rdk.networking")
+
+	// appenders configured the same way are kept, and those no longer configured are closed
+	registry.appendersMu.RLock()
+	kept := registry.appenders[1]
+	registry.appendersMu.RUnlock()
+	test.That(t, registry.Update(logConfig[1:2], warnLogger), test.ShouldBeNil)
+	registry.appendersMu.RLock()
+	test.That(t, registry.appenders, test.ShouldHaveLength, 1)
+	test.That(t, registry.appenders[0], test.ShouldEqual, kept)
+	registry.appendersMu.RUnlock()
+
+	resourceLogger.Error("resource failed")
+	test.That(t, readJSONLines(t, resourceLogs), test.ShouldHaveLength, 2)
+	test.That(t, readJSONLines(t, warnLogs), test.ShouldHaveLength, 3)
+
+	test.That(t, registry.Update(nil, warnLogger), test.ShouldBeNil)
+	networkLogger.Error("disconnected again")
+	test.That(t, readJSONLines(t, warnLogs), test.ShouldHaveLength, 3)
+}
+
+func TestRegistryAppenderLevelWarning(t *testing.T) {
+	_, registry := NewLoggerWithRegistry("rdk")
+	warnLogger, warnings := NewObservedTestLogger(t)
+	dir := t.TempDir()
+	test.That(t, registry.Update([]LoggerPatternConfig{{
+		Pattern: "rdk.*",
+		Level:   "warn",
+		Appenders: []AppenderConfig{
+			{Type: AppenderTypeFile, Path: filepath.Join(dir, "debug.log"), Level: "debug"},
+			{Type: AppenderTypeFile, Path: filepath.Join(dir, "error.log"), Level: "error"},
+			{Type: AppenderTypeFile, Path: filepath.Join(dir, "all.log")},
+		},
+	}}, warnLogger), test.ShouldBeNil)
+	test.That(t, warnings.FilterMessageSnippet("level is below the level of the loggers").Len(), test.ShouldEqual, 1)
+	test.That(t, registry.Update(nil, warnLogger), test.ShouldBeNil)
+}
+
+// closeTrackingAppender records writes made to it while or after it's closed.
+type closeTrackingAppender struct {
+	writing       atomic.Int64
+	written       atomic.Int64
+	closed        atomic.Bool
+	writesOnClose atomic.Int64
+}
+
+func (a *closeTrackingAppender) Write(zapcore.Entry, []zapcore.Field) error {
+	a.writing.Add(1)
+	defer a.writing.Add(-1)
+	defer a.written.Add(1)
+	if a.closed.Load() {
+		a.writesOnClose.Add(1)
+	}
+	time.Sleep(10 * time.Microsecond)
+	return nil
+}
+
+func (a *closeTrackingAppender) Sync() error {
+	return nil
+}
+
+func (a *closeTrackingAppender) Close() error {
+	a.closed.Store(true)
+	a.writesOnClose.Add(a.writing.Load())
+	return nil
+}
+
+func TestRegistryAppenderCloseWaitsForWrites(t *testing.T) {
+	registry := newRegistry()
+	logger := &impl{
+		name:       "rdk",
+		level:      NewAtomicLevelAt(INFO),
+		registry:   registry,
+		testHelper: func() {},
+	}
+	registry.registerLogger("rdk", logger)
+	warnLogger := NewTestLogger(t)
+	matcher, err := regexp.Compile(buildRegexFromPattern("rdk"))
+	test.That(t, err, test.ShouldBeNil)
+
+	var appenders []*closeTrackingAppender
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				logger.Info("hello")
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		appender := &closeTrackingAppender{}
+		appenders = append(appenders, appender)
+		registry.appendersMu.Lock()
+		registry.appenders = []*patternAppender{{
+			key:      patternAppenderKey{pattern: "rdk", cfg: AppenderConfig{Path: strconv.Itoa(i)}},
+			matcher:  matcher,
+			level:    DEBUG,
+			appender: appender,
+		}}
+		registry.appendersFor = make(map[string][]*patternAppender)
+		registry.appendersMu.Unlock()
+		for appender.written.Load() == 0 {
+			time.Sleep(time.Microsecond)
+		}
+		test.That(t, registry.Update(nil, warnLogger), test.ShouldBeNil)
+	}
+	close(done)
+	wg.Wait()
+
+	for _, appender := range appenders {
+		test.That(t, appender.closed.Load(), test.ShouldBeTrue)
+		test.That(t, appender.writesOnClose.Load(), test.ShouldEqual, 0)
+	}
+}
@@ -0,0 +1,227 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// syslogStructuredDataID identifies the structured data element log entry fields are sent in. 32473 is the
+	// private enterprise number reserved for documentation (RFC 5612).
+	syslogStructuredDataID = "viam@32473"
+	syslogTimeFormat       = "2006-01-02T15:04:05.000000Z07:00"
+	defaultSyslogFacility  = 1 // user-level messages
+)
+
+// syslogFacilities are the names of the syslog facilities a SyslogAppender may log as.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// localSyslogSockets are where a local syslog daemon listens, in order of preference.
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogAppenderConfig configures a SyslogAppender.
+type SyslogAppenderConfig struct {
+	// Network is "udp", "tcp" or "unix". An empty network with an empty address logs to the local syslog daemon.
+	Network string
+	// Address is the address of the syslog server, or the path of its socket for "unix".
+	Address string
+	// Facility is the name of the syslog facility to log as, e.g: "local0". Defaults to "user".
+	Facility string
+	// AppName is the APP-NAME of the messages sent.
+	AppName string
+}
+
+// SyslogAppender sends log entries as RFC 5424 syslog messages over UDP, TCP or a unix socket. The fields of a log
+// entry are sent as the parameters of a structured data element, along with the name of its logger and its caller.
+type SyslogAppender struct {
+	cfg      SyslogAppenderConfig
+	facility int
+	hostname string
+	pid      int
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+	// framed is whether messages are sent over a stream and so must be framed, by octet counting (RFC 6587).
+	framed bool
+}
+
+// NewSyslogAppender returns a SyslogAppender connected to the configured syslog server.
+func NewSyslogAppender(cfg SyslogAppenderConfig) (*SyslogAppender, error) {
+	facility := defaultSyslogFacility
+	if cfg.Facility != "" {
+		var ok bool
+		if facility, ok = syslogFacilities[strings.ToLower(cfg.Facility)]; !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", cfg.Facility)
+		}
+	}
+	switch cfg.Network {
+	case "", "udp", "tcp", "unix":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", cfg.Network)
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	appender := &SyslogAppender{cfg: cfg, facility: facility, hostname: hostname, pid: os.Getpid()}
+	if err := appender.connect(); err != nil {
+		return nil, err
+	}
+	return appender, nil
+}
+
+func (appender *SyslogAppender) connect() error {
+	var err error
+	switch appender.cfg.Network {
+	case "udp":
+		appender.conn, err = net.Dial("udp", appender.cfg.Address)
+		appender.framed = false
+	case "tcp":
+		appender.conn, err = net.DialTimeout("tcp", appender.cfg.Address, 5*time.Second)
+		appender.framed = true
+	default:
+		sockets := localSyslogSockets
+		if appender.cfg.Address != "" {
+			sockets = []string{appender.cfg.Address}
+		}
+		for _, socket := range sockets {
+			if appender.conn, err = net.Dial("unixgram", socket); err == nil {
+				appender.framed = false
+				return nil
+			}
+			if appender.conn, err = net.Dial("unix", socket); err == nil {
+				appender.framed = true
+				return nil
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return nil
+}
+
+// Write sends the log entry to the syslog server, reconnecting once if sending fails.
+func (appender *SyslogAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	msg := appender.format(entry, fields)
+
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	if appender.closed {
+		return fmt.Errorf("syslog appender is closed")
+	}
+	if appender.conn != nil {
+		if err := appender.send(msg); err == nil {
+			return nil
+		}
+		//nolint:errcheck,gosec
+		appender.conn.Close()
+		appender.conn = nil
+	}
+	if err := appender.connect(); err != nil {
+		return err
+	}
+	return appender.send(msg)
+}
+
+func (appender *SyslogAppender) send(msg string) error {
+	if appender.framed {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	_, err := appender.conn.Write([]byte(msg))
+	return err
+}
+
+// format returns the log entry as an RFC 5424 syslog message.
+func (appender *SyslogAppender) format(entry zapcore.Entry, fields []zapcore.Field) string {
+	var sd strings.Builder
+	sd.WriteString("[" + syslogStructuredDataID)
+	writeParam := func(name, value string) {
+		sd.WriteString(" " + syslogParamName(name) + `="` + syslogParamValueEscaper.Replace(value) + `"`)
+	}
+	writeParam("logger", entry.LoggerName)
+	if entry.Caller.Defined {
+		writeParam("caller", callerToString(&entry.Caller))
+	}
+	for _, field := range fieldValues(fields) {
+		writeParam(field.key, field.value)
+	}
+	sd.WriteString("]")
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d - %s %s",
+		appender.facility*8+syslogSeverity(entry.Level),
+		entry.Time.UTC().Format(syslogTimeFormat),
+		appender.hostname,
+		syslogHeaderValue(appender.cfg.AppName, 48),
+		appender.pid,
+		sd.String(),
+		entry.Message,
+	)
+}
+
+var syslogParamValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogParamName returns a valid structured data parameter name: at most 32 printable ASCII characters other
+// than '=', ' ', ']' and '"'.
+func syslogParamName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// syslogHeaderValue returns a valid syslog header field of at most maxLen printable ASCII characters, or the nil
+// value "-" if empty.
+func syslogHeaderValue(value string, maxLen int) string {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// Sync is a no-op.
+func (appender *SyslogAppender) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the syslog server.
+func (appender *SyslogAppender) Close() error {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	appender.closed = true
+	if appender.conn == nil {
+		return nil
+	}
+	err := appender.conn.Close()
+	appender.conn = nil
+	return err
+}
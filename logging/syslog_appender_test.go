@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+)
+
+func TestSyslogAppenderUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+
+	appender, err := NewSyslogAppender(SyslogAppenderConfig{
+		Network: "udp", Address: conn.LocalAddr().String(), Facility: "local3", AppName: "viam-server",
+	})
+	test.That(t, err, test.ShouldBeNil)
+	defer appender.Close()
+
+	entry := zapcore.Entry{
+		Level:      zapcore.ErrorLevel,
+		LoggerName: "rdk.resource_manager",
+		Message:    "failed to build",
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+	}
+	test.That(t, appender.Write(entry, []zapcore.Field{zap.String("name", `arm "1"]`), zap.Int("attempt", 3)}), test.ShouldBeNil)
+
+	buf := make([]byte, 2048)
+	test.That(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+	n, _, err := conn.ReadFrom(buf)
+	test.That(t, err, test.ShouldBeNil)
+	// local3 (19) * 8 + error (3)
+	expected := "<155>1 2024-01-02T03:04:05.000006Z " + appender.hostname + " viam-server " + strconv.Itoa(os.Getpid()) +
+		` - [viam@32473 logger="rdk.resource_manager" name="arm \"1\"\]" attempt="3"] failed to build`
+	test.That(t, string(buf[:n]), test.ShouldEqual, expected)
+}
+
+func TestSyslogAppenderTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer listener.Close()
+
+	appender, err := NewSyslogAppender(SyslogAppenderConfig{Network: "tcp", Address: listener.Addr().String()})
+	test.That(t, err, test.ShouldBeNil)
+	defer appender.Close()
+	conn, err := listener.Accept()
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+
+	for _, msg := range []string{"first", "second"} {
+		test.That(t, appender.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: msg}, nil), test.ShouldBeNil)
+	}
+
+	// messages are framed by octet counting
+	reader := bufio.NewReader(conn)
+	for _, msg := range []string{"first", "second"} {
+		length, err := reader.ReadString(' ')
+		test.That(t, err, test.ShouldBeNil)
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		test.That(t, err, test.ShouldBeNil)
+		frame := make([]byte, n)
+		_, err = io.ReadFull(reader, frame)
+		test.That(t, err, test.ShouldBeNil)
+		// user (1) * 8 + info (6)
+		test.That(t, string(frame), test.ShouldStartWith, "<14>1 ")
+		test.That(t, string(frame), test.ShouldEndWith, `[viam@32473 logger=""] `+msg)
+	}
+
+	test.That(t, appender.Close(), test.ShouldBeNil)
+	test.That(t, appender.Write(zapcore.Entry{Message: "closed"}, nil), test.ShouldNotBeNil)
+}
+
+func TestSyslogAppenderConfig(t *testing.T) {
+	_, err := NewSyslogAppender(SyslogAppenderConfig{Network: "udp", Address: "127.0.0.1:514", Facility: "nope"})
+	test.That(t, err, test.ShouldBeError, `unknown syslog facility "nope"`)
+	_, err = NewSyslogAppender(SyslogAppenderConfig{Network: "sctp", Address: "127.0.0.1:514"})
+	test.That(t, err, test.ShouldBeError, `unsupported syslog network "sctp"`)
+}
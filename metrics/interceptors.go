@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// unknownResource labels calls naming a resource that is not configured, so that clients cannot create series at
+// will.
+const unknownResource = "unknown"
+
+// namedRequest is implemented by the requests of calls made to a resource.
+type namedRequest interface {
+	GetName() string
+}
+
+// ServerInterceptors record the count, latency and status of the gRPC calls a server handles. Calls are labeled with
+// the resource they name only if it is one of the configured resources.
+type ServerInterceptors struct {
+	mu        sync.RWMutex
+	resources map[string]bool
+}
+
+// NewServerInterceptors returns interceptors that label every named resource as unknown until SetResourceNames is
+// called.
+func NewServerInterceptors() *ServerInterceptors {
+	return &ServerInterceptors{resources: map[string]bool{}}
+}
+
+// SetResourceNames replaces the names of the resources calls may be labeled with.
+func (si *ServerInterceptors) SetResourceNames(names []string) {
+	resources := make(map[string]bool, len(names))
+	for _, name := range names {
+		resources[name] = true
+	}
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	si.resources = resources
+}
+
+func (si *ServerInterceptors) resourceOf(req interface{}) string {
+	named, ok := req.(namedRequest)
+	if !ok || named.GetName() == "" {
+		return ""
+	}
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	if !si.resources[named.GetName()] {
+		return unknownResource
+	}
+	return named.GetName()
+}
+
+func observe(method, resource string, start time.Time, err error) {
+	GRPCCalls.WithLabelValues(method, resource, status.Code(err).String()).Inc()
+	GRPCCallDuration.WithLabelValues(method, resource).Observe(time.Since(start).Seconds())
+}
+
+// UnaryServerInterceptor records the count, latency and status of unary calls.
+func (si *ServerInterceptors) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(info.FullMethod, si.resourceOf(req), start, err)
+	return resp, err
+}
+
+// StreamServerInterceptor records the count, duration and status of streaming calls. The resource called is that
+// named by the first message received.
+func (si *ServerInterceptors) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	stream := &namedServerStream{ServerStream: ss, interceptors: si}
+	err := handler(srv, stream)
+	observe(info.FullMethod, stream.resource, start, err)
+	return err
+}
+
+type namedServerStream struct {
+	grpc.ServerStream
+	interceptors *ServerInterceptors
+	received     bool
+	resource     string
+}
+
+func (s *namedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && !s.received {
+		s.received = true
+		s.resource = s.interceptors.resourceOf(m)
+	}
+	return err
+}
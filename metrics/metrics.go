@@ -0,0 +1,129 @@
+// Package metrics defines the Prometheus metrics a robot reports about its runtime, and serves them in the
+// Prometheus text and OpenMetrics formats.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "viam"
+
+// Registry holds all of the metrics reported by this process, along with those of the Go runtime and the process.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// GRPCCalls counts the gRPC calls handled by full method name, the resource called and the status code returned.
+	GRPCCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_server",
+		Name:      "handled_total",
+		Help:      "gRPC calls handled, by method, resource and status code.",
+	}, []string{"method", "resource", "code"})
+
+	// GRPCCallDuration observes how long gRPC calls take to handle by full method name and the resource called.
+	GRPCCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_server",
+		Name:      "handling_seconds",
+		Help:      "Time taken to handle gRPC calls, by method and resource.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "resource"})
+
+	// ModuleCrashes counts the unexpected exits of module processes by module.
+	ModuleCrashes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "module",
+		Name:      "unexpected_exits_total",
+		Help:      "Unexpected exits of module processes, by module.",
+	}, []string{"module"})
+
+	// ModuleRestarts counts the restarts of crashed module processes by module and whether they succeeded.
+	ModuleRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "module",
+		Name:      "restarts_total",
+		Help:      "Restarts of crashed module processes, by module and result.",
+	}, []string{"module", "result"})
+
+	// CaptureQueueDepth is the number of readings captured by a data capture collector waiting to be written.
+	CaptureQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "data_capture",
+		Name:      "queue_depth",
+		Help:      "Captured readings waiting to be written, by resource and method.",
+	}, []string{"resource", "method"})
+
+	// CaptureDroppedReadings counts the readings a data capture collector failed to capture or write.
+	CaptureDroppedReadings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "data_capture",
+		Name:      "dropped_readings_total",
+		Help:      "Readings that failed to be captured or written, by resource, method and reason.",
+	}, []string{"resource", "method", "reason"})
+
+	// SyncUploadedFiles counts the files uploaded by data sync by kind: "binary", "tabular" or "arbitrary".
+	SyncUploadedFiles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "data_sync",
+		Name:      "uploaded_files_total",
+		Help:      "Files uploaded by data sync, by kind.",
+	}, []string{"kind"})
+
+	// SyncUploadedBytes counts the bytes uploaded by data sync by kind.
+	SyncUploadedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "data_sync",
+		Name:      "uploaded_bytes_total",
+		Help:      "Bytes uploaded by data sync, by kind.",
+	}, []string{"kind"})
+
+	// SyncUploadFailures counts the files data sync gave up uploading by kind.
+	SyncUploadFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "data_sync",
+		Name:      "upload_failures_total",
+		Help:      "Files data sync failed to upload, by kind.",
+	}, []string{"kind"})
+
+	// Sessions is the number of sessions clients of the robot hold.
+	Sessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sessions",
+		Help:      "Sessions held by clients of the robot.",
+	})
+
+	// WebRTCPeers is the number of peers connected to the robot over WebRTC.
+	WebRTCPeers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "webrtc",
+		Name:      "peers",
+		Help:      "Peers connected over WebRTC.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		GRPCCalls,
+		GRPCCallDuration,
+		ModuleCrashes,
+		ModuleRestarts,
+		CaptureQueueDepth,
+		CaptureDroppedReadings,
+		SyncUploadedFiles,
+		SyncUploadedBytes,
+		SyncUploadFailures,
+		Sessions,
+		WebRTCPeers,
+	)
+}
+
+// Handler serves the metrics in the registry, as OpenMetrics if the scraper accepts it.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	method := "/viam.component.arm.v1.ArmService/GetEndPosition"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.GetEndPositionResponse{}, nil
+	}
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "arm is gone")
+	}
+
+	interceptors := NewServerInterceptors()
+	interceptors.SetResourceNames([]string{"arm1"})
+	for i := 0; i < 2; i++ {
+		_, err := interceptors.UnaryServerInterceptor(context.Background(), &pb.GetEndPositionRequest{Name: "arm1"}, info, ok)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	_, err := interceptors.UnaryServerInterceptor(context.Background(), &pb.GetEndPositionRequest{Name: "arm1"}, info, failing)
+	test.That(t, err, test.ShouldNotBeNil)
+	for _, name := range []string{"arm2", "arm3"} {
+		_, err := interceptors.UnaryServerInterceptor(context.Background(), &pb.GetEndPositionRequest{Name: name}, info, ok)
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	test.That(t, testutil.ToFloat64(GRPCCalls.WithLabelValues(method, "arm1", "OK")), test.ShouldEqual, 2)
+	test.That(t, testutil.ToFloat64(GRPCCalls.WithLabelValues(method, "arm1", "Unavailable")), test.ShouldEqual, 1)
+	test.That(t, testutil.ToFloat64(GRPCCalls.WithLabelValues(method, "unknown", "OK")), test.ShouldEqual, 2)
+	test.That(t, testutil.CollectAndCount(GRPCCallDuration), test.ShouldEqual, 2)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	msgs []interface{}
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(s.msgs) == 0 {
+		return io.EOF
+	}
+	//nolint:forcetypeassert
+	proto.Reset(m.(proto.Message))
+	//nolint:forcetypeassert
+	proto.Merge(m.(proto.Message), s.msgs[0].(proto.Message))
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	method := "/viam.component.arm.v1.ArmService/StreamSomething"
+	stream := &fakeServerStream{msgs: []interface{}{
+		&pb.MoveToPositionRequest{Name: "arm2"},
+		&pb.MoveToPositionRequest{Name: "other"},
+	}}
+	interceptors := NewServerInterceptors()
+	interceptors.SetResourceNames([]string{"arm2", "other"})
+	err := interceptors.StreamServerInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: method},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			for {
+				var req pb.MoveToPositionRequest
+				if err := stream.RecvMsg(&req); err != nil {
+					return status.Error(codes.Canceled, err.Error())
+				}
+			}
+		})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, testutil.ToFloat64(GRPCCalls.WithLabelValues(method, "arm2", "Canceled")), test.ShouldEqual, 1)
+}
+
+func TestHandler(t *testing.T) {
+	SyncUploadedBytes.WithLabelValues("tabular").Add(1024)
+	WebRTCPeers.Set(2)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	test.That(t, body, test.ShouldContainSubstring, `viam_data_sync_uploaded_bytes_total{kind="tabular"} 1024`)
+	test.That(t, body, test.ShouldContainSubstring, "viam_webrtc_peers 2")
+	test.That(t, body, test.ShouldContainSubstring, "go_goroutines")
+
+	request := httptest.NewRequest("GET", "/metrics", nil)
+	request.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, request)
+	test.That(t, recorder.Header().Get("Content-Type"), test.ShouldStartWith, "application/openmetrics-text")
+	test.That(t, recorder.Body.String(), test.ShouldEndWith, "# EOF\n")
+}
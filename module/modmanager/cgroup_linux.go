@@ -0,0 +1,127 @@
+package modmanager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	// cgroupServerLeaf is the cgroup the server is expected to run in within a delegated subtree, such as one set
+	// up with systemd's Delegate=yes and DelegateSubgroup=viam-server. Module cgroups are created beside it, as
+	// cgroup v2 does not let a cgroup both contain processes and delegate controllers.
+	cgroupServerLeaf = "viam-server"
+	cgroupCPUPeriod  = 100000
+)
+
+// checkLimitsSupported returns nil, as all module limits can be applied on linux.
+func checkLimitsSupported() error {
+	return nil
+}
+
+// checkSandboxSupported returns nil, as modules can be sandboxed with bubblewrap on linux.
+func checkSandboxSupported() error {
+	return nil
+}
+
+// createModuleCgroup creates a cgroup for the module beneath the cgroup of the server and applies limits to it.
+func createModuleCgroup(moduleName string, limits *config.ModuleLimits) (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", errors.Wrap(err, "cgroup v2 is not available")
+	}
+	parent, err := serverCgroup()
+	if err != nil {
+		return "", err
+	}
+	if err := enableCgroupControllers(parent); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(parent, "module-"+moduleName)
+	if err := os.Mkdir(dir, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", err
+	}
+	memoryMax, cpuMax := "max", "max"
+	if limits.MemoryMB != 0 {
+		memoryMax = strconv.FormatUint(limits.MemoryMB*1024*1024, 10)
+	}
+	if limits.CPUs != 0 {
+		cpuMax = fmt.Sprintf("%d %d", int64(limits.CPUs*cgroupCPUPeriod), cgroupCPUPeriod)
+	}
+	if err := writeCgroupFile(dir, "memory.max", memoryMax); err != nil {
+		return "", err
+	}
+	if err := writeCgroupFile(dir, "cpu.max", cpuMax); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// serverCgroup returns the directory of the cgroup the server process belongs to, or the parent of it if the server
+// runs in its leaf of a delegated subtree.
+func serverCgroup() (string, error) {
+	//nolint:gosec
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		// the cgroup v2 hierarchy is listed as "0::/path"
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			dir := filepath.Join(cgroupRoot, path)
+			if filepath.Base(dir) == cgroupServerLeaf {
+				dir = filepath.Dir(dir)
+			}
+			return dir, nil
+		}
+	}
+	return "", errors.New("server is not in a cgroup v2 hierarchy")
+}
+
+// enableCgroupControllers enables the memory and cpu controllers for the children of dir. The server never moves
+// itself between cgroups, so this fails if dir contains the server process rather than being a delegated subtree
+// with the server in its leaf.
+func enableCgroupControllers(dir string) error {
+	err := writeCgroupFile(dir, "cgroup.subtree_control", "+memory +cpu")
+	if errors.Is(err, syscall.EBUSY) {
+		return errors.Errorf("cgroup %s contains processes; module cgroups require viam-server to run in a %q cgroup "+
+			"of a delegated subtree, such as with systemd's Delegate=yes and DelegateSubgroup=%s",
+			dir, cgroupServerLeaf, cgroupServerLeaf)
+	}
+	return err
+}
+
+// cgroupOOMKills returns the number of processes in the cgroup killed for exceeding its memory limit.
+func cgroupOOMKills(dir string) (uint64, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(filepath.Join(dir, "memory.events"))
+	if err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if count, ok := strings.CutPrefix(scanner.Text(), "oom_kill "); ok {
+			return strconv.ParseUint(count, 10, 64)
+		}
+	}
+	return 0, nil
+}
+
+func removeCgroup(dir string) error {
+	return os.Remove(dir)
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	//nolint:gosec
+	return os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644)
+}
@@ -0,0 +1,38 @@
+//go:build !linux
+
+package modmanager
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+)
+
+var errCgroupUnsupported = errors.New("cgroups are only supported on linux")
+
+// checkLimitsSupported returns an error on windows, where modules cannot be started through a shell to apply their
+// limits.
+func checkLimitsSupported() error {
+	if runtime.GOOS == "windows" {
+		return errors.New("module limits are not supported on windows")
+	}
+	return nil
+}
+
+func checkSandboxSupported() error {
+	return errors.New("module sandbox is only supported on linux")
+}
+
+func createModuleCgroup(moduleName string, limits *config.ModuleLimits) (string, error) {
+	return "", errCgroupUnsupported
+}
+
+func cgroupOOMKills(dir string) (uint64, error) {
+	return 0, errCgroupUnsupported
+}
+
+func removeCgroup(dir string) error {
+	return errCgroupUnsupported
+}
@@ -0,0 +1,195 @@
+package modmanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils/pexec"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+// moduleLimiter applies the limits of a module config to its process. As pexec gives no access to the process it
+// starts, the module is started through a shell that joins the module's cgroup or sets rlimits on itself and then
+// executes the module, through nice, bwrap and setpriv as needed.
+type moduleLimiter struct {
+	limits     *config.ModuleLimits
+	moduleName string
+	logger     logging.Logger
+
+	// cgroup is the directory of the module's cgroup, or empty if limits are enforced with rlimits.
+	cgroup string
+	// rlimitMemory is whether the memory limit is enforced with RLIMIT_DATA rather than a cgroup.
+	rlimitMemory bool
+	// oomKills is the number of processes in the module's cgroup killed for exceeding its memory limit when the
+	// module was last started.
+	oomKills uint64
+}
+
+func newModuleLimiter(limits *config.ModuleLimits, moduleName string, logger logging.Logger) *moduleLimiter {
+	return &moduleLimiter{limits: limits, moduleName: moduleName, logger: logger}
+}
+
+// wrap rewrites pconf to start the module with its limits applied.
+func (l *moduleLimiter) wrap(pconf *pexec.ProcessConfig, dataDir, socketDir string) error {
+	if err := checkLimitsSupported(); err != nil {
+		return err
+	}
+
+	var script []string
+	if l.limits.MemoryMB != 0 || l.limits.CPUs != 0 {
+		cgroup, err := createModuleCgroup(l.moduleName, l.limits)
+		if err == nil {
+			l.cgroup = cgroup
+			if l.oomKills, err = cgroupOOMKills(cgroup); err != nil {
+				l.logger.Debugw("Failed to read module cgroup memory events", "module", l.moduleName, "error", err)
+			}
+			script = append(script, "echo $$ > "+shellQuote(filepath.Join(cgroup, "cgroup.procs")))
+		} else {
+			l.logger.Warnw("Could not create a cgroup for module, falling back to rlimits", "module", l.moduleName, "error", err)
+			if l.limits.MemoryMB != 0 {
+				// RLIMIT_DATA bounds the memory the module allocates rather than the address space it reserves,
+				// which runtimes such as Go's and the JVM reserve far more of than they use
+				l.logger.Warnw("Module memory limit is only approximately enforced without a cgroup: allocations "+
+					"beyond it fail rather than the module being killed, and memory such as shared mappings is not counted",
+					"module", l.moduleName)
+				script = append(script, fmt.Sprintf("ulimit -d %d", l.limits.MemoryMB*1024))
+				l.rlimitMemory = true
+			}
+			if l.limits.CPUs != 0 {
+				l.logger.Warnw("Module CPU limit requires cgroup v2 and will not be enforced", "module", l.moduleName)
+			}
+		}
+	}
+
+	var command []string
+	if l.limits.Nice != 0 {
+		command = append(command, "nice", "-n", strconv.Itoa(l.limits.Nice))
+	}
+	if l.limits.Sandbox != nil {
+		sandbox, err := l.sandboxCommand(pconf, dataDir, socketDir)
+		if err != nil {
+			return err
+		}
+		command = append(command, sandbox...)
+	}
+	command = append(command, pconf.Name)
+	for i, arg := range command {
+		command[i] = shellQuote(arg)
+	}
+	script = append(script, "exec "+strings.Join(command, " ")+` "$@"`)
+
+	// the module's path is passed as $0 so that it still shows in process listings
+	pconf.Args = append([]string{"-c", strings.Join(script, " && "), pconf.Name}, pconf.Args...)
+	pconf.Name = "/bin/sh"
+	return nil
+}
+
+// sandboxCommand returns the command the module is executed through to run it in restricted mode.
+func (l *moduleLimiter) sandboxCommand(pconf *pexec.ProcessConfig, dataDir, socketDir string) ([]string, error) {
+	if err := checkSandboxSupported(); err != nil {
+		return nil, err
+	}
+	bwrap, err := exec.LookPath("bwrap")
+	if err != nil {
+		return nil, errors.Wrap(err, "module sandbox requires bubblewrap (bwrap) to be installed")
+	}
+	command := []string{
+		bwrap,
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--tmpfs", "/tmp",
+		// unix sockets are reachable from any network namespace, so the module can still serve on and dial the
+		// sockets in this directory
+		"--bind", socketDir, socketDir,
+		"--unshare-net",
+		"--unshare-ipc",
+		"--die-with-parent",
+		"--clearenv",
+	}
+	if dataDir != "" {
+		command = append(command, "--bind", dataDir, dataDir)
+	}
+	if pconf.CWD != "" {
+		command = append(command, "--chdir", pconf.CWD)
+	}
+
+	env := map[string]string{"PATH": os.Getenv("PATH")}
+	for k, v := range pconf.Environment {
+		env[k] = v
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		command = append(command, "--setenv", k, env[k])
+	}
+
+	if l.limits.Sandbox.User == "" {
+		return append(command, "--"), nil
+	}
+	setpriv, err := exec.LookPath("setpriv")
+	if err != nil {
+		return nil, errors.Wrap(err, "running a sandboxed module as another user requires setpriv to be installed")
+	}
+	u, err := user.Lookup(l.limits.Sandbox.User)
+	if err != nil {
+		return nil, err
+	}
+	if dataDir != "" {
+		uid, uidErr := strconv.Atoi(u.Uid)
+		gid, gidErr := strconv.Atoi(u.Gid)
+		if err := multierr.Combine(uidErr, gidErr); err != nil {
+			return nil, err
+		}
+		if err := os.Chown(dataDir, uid, gid); err != nil {
+			l.logger.Warnw("Failed to give module data directory to module user",
+				"module", l.moduleName, "user", u.Username, "error", err)
+		}
+	}
+	return append(command, "--", setpriv, "--reuid="+u.Uid, "--regid="+u.Gid, "--init-groups", "--"), nil
+}
+
+// exitReason returns why the module's last process exited if it was killed for exceeding a limit, or an empty
+// string otherwise. A module limited with RLIMIT_DATA is not killed but fails to allocate memory, which can't be
+// told apart from other failures, so its limit is reported as a possible reason for any exit.
+func (l *moduleLimiter) exitReason() string {
+	if l.rlimitMemory {
+		return fmt.Sprintf("module may have exceeded its memory limit of %d MB", l.limits.MemoryMB)
+	}
+	if l.cgroup == "" {
+		return ""
+	}
+	oomKills, err := cgroupOOMKills(l.cgroup)
+	if err != nil || oomKills <= l.oomKills {
+		return ""
+	}
+	return fmt.Sprintf("module exceeded its memory limit of %d MB", l.limits.MemoryMB)
+}
+
+// close removes the module's cgroup, if any, once its process has stopped.
+func (l *moduleLimiter) close() {
+	if l.cgroup == "" {
+		return
+	}
+	if err := removeCgroup(l.cgroup); err != nil {
+		l.logger.Debugw("Failed to remove module cgroup", "module", l.moduleName, "error", err)
+	}
+	l.cgroup = ""
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
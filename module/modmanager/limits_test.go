@@ -0,0 +1,73 @@
+package modmanager
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/pexec"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+func TestModuleLimiterWrap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("module limits are not supported on windows")
+	}
+	logger := logging.NewTestLogger(t)
+
+	echo, err := exec.LookPath("echo")
+	test.That(t, err, test.ShouldBeNil)
+	pconf := pexec.ProcessConfig{Name: echo, Args: []string{"a b", "it's"}}
+	limiter := newModuleLimiter(&config.ModuleLimits{Nice: 5}, "test-module", logger)
+	test.That(t, limiter.wrap(&pconf, "", t.TempDir()), test.ShouldBeNil)
+	test.That(t, pconf.Name, test.ShouldEqual, "/bin/sh")
+	//nolint:gosec
+	out, err := exec.Command(pconf.Name, pconf.Args...).Output()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(out), test.ShouldEqual, "a b it's\n")
+
+	nice, err := exec.LookPath("nice")
+	test.That(t, err, test.ShouldBeNil)
+	//nolint:gosec
+	base, err := exec.Command(nice).Output()
+	test.That(t, err, test.ShouldBeNil)
+	pconf = pexec.ProcessConfig{Name: nice}
+	test.That(t, limiter.wrap(&pconf, "", t.TempDir()), test.ShouldBeNil)
+	//nolint:gosec
+	out, err = exec.Command(pconf.Name, pconf.Args...).Output()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, strings.TrimSpace(string(out)), test.ShouldNotEqual, strings.TrimSpace(string(base)))
+	test.That(t, limiter.exitReason(), test.ShouldBeEmpty)
+}
+
+func TestShellQuote(t *testing.T) {
+	test.That(t, shellQuote("plain"), test.ShouldEqual, "'plain'")
+	test.That(t, shellQuote("it's"), test.ShouldEqual, `'it'\''s'`)
+}
+
+func TestModuleLimiterRlimitFallback(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("RLIMIT_DATA is only checked on linux")
+	}
+	logger := logging.NewTestLogger(t)
+	limiter := newModuleLimiter(&config.ModuleLimits{MemoryMB: 64}, "test-module", logger)
+	sh, err := exec.LookPath("sh")
+	test.That(t, err, test.ShouldBeNil)
+	pconf := pexec.ProcessConfig{Name: sh, Args: []string{"-c", "ulimit -d; ulimit -v"}}
+	test.That(t, limiter.wrap(&pconf, "", t.TempDir()), test.ShouldBeNil)
+	if limiter.cgroup != "" {
+		// the server runs in a delegated cgroup subtree, so the limit is enforced with a cgroup instead
+		limiter.close()
+		t.Skip("module cgroups are available")
+	}
+	//nolint:gosec
+	out, err := exec.Command(pconf.Name, pconf.Args...).Output()
+	test.That(t, err, test.ShouldBeNil)
+	// the data segment is limited rather than the address space, which runtimes reserve much more of than they use
+	test.That(t, strings.Fields(string(out)), test.ShouldResemble, []string{"65536", "unlimited"})
+	test.That(t, limiter.exitReason(), test.ShouldContainSubstring, "memory limit of 64 MB")
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,12 +30,14 @@ import (
 	"go.viam.com/rdk/config"
 	rdkgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 	modlib "go.viam.com/rdk/module"
 	modmanageroptions "go.viam.com/rdk/module/modmanager/options"
 	"go.viam.com/rdk/module/modmaninterface"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/packages"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -69,14 +72,23 @@ func NewManager(
 }
 
 type module struct {
-	cfg        config.Module
-	dataDir    string
-	process    pexec.ManagedProcess
-	handles    modlib.HandlerMap
-	sharedConn rdkgrpc.SharedConn
-	client     pb.ModuleServiceClient
-	addr       string
-	resources  map[resource.Name]*addedResource
+	cfg     config.Module
+	dataDir string
+	process pexec.ManagedProcess
+	// limiter applies the limits of cfg to process, if any are configured.
+	limiter *moduleLimiter
+	health  *moduleHealth
+	// processGen counts the processes started for the module, so that exits of processes that have since been
+	// replaced can be ignored.
+	processGen atomic.Uint64
+	// cancelRecovery, when set, stops attempts to restart the crashed module. It must only be accessed with the
+	// module manager write-locked.
+	cancelRecovery context.CancelFunc
+	handles        modlib.HandlerMap
+	sharedConn     rdkgrpc.SharedConn
+	client         pb.ModuleServiceClient
+	addr           string
+	resources      map[resource.Name]*addedResource
 	// resourcesMu must be held if the `resources` field is accessed without
 	// write-locking the module manager.
 	resourcesMu sync.Mutex
@@ -166,6 +178,10 @@ type Manager struct {
 	removeOrphanedResources func(ctx context.Context, rNames []resource.Name)
 	restartCtx              context.Context
 	restartCtxCancel        context.CancelFunc
+
+	// failedModules holds the health of modules given up on after crashing, until they are added or removed again.
+	failedModulesMu sync.Mutex
+	failedModules   map[string]config.ModuleHealth
 }
 
 // Close terminates module connections and processes.
@@ -278,8 +294,10 @@ func (mgr *Manager) add(ctx context.Context, conf config.Module) error {
 		cfg:       conf,
 		dataDir:   moduleDataDir,
 		resources: map[resource.Name]*addedResource{},
+		health:    newModuleHealth(),
 		logger:    mgr.logger.Sublogger(conf.Name),
 	}
+	mgr.clearFailedModule(conf.Name)
 
 	if err := mgr.startModule(ctx, mod); err != nil {
 		return err
@@ -288,6 +306,7 @@ func (mgr *Manager) add(ctx context.Context, conf config.Module) error {
 }
 
 func (mgr *Manager) startModuleProcess(mod *module) error {
+	// the handler must be created right before the process is started, see newOnUnexpectedExitHandler
 	return mod.startProcess(
 		mgr.restartCtx,
 		mgr.parentAddr,
@@ -339,6 +358,7 @@ func (mgr *Manager) startModule(ctx context.Context, mod *module) error {
 
 	mod.registerResources(mgr, mgr.logger)
 	mgr.modules.Store(mod.cfg.Name, mod)
+	mod.health.started()
 	mgr.logger.Infow("Module successfully added", "module", mod.cfg.Name)
 	success = true
 	return nil
@@ -392,6 +412,7 @@ func (mgr *Manager) Reconfigure(ctx context.Context, conf config.Module) ([]reso
 func (mgr *Manager) Remove(modName string) ([]resource.Name, error) {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
+	mgr.clearFailedModule(modName)
 	mod, exists := mgr.modules.Load(modName)
 	if !exists {
 		return nil, errors.Errorf("cannot remove module %s as it does not exist", modName)
@@ -416,6 +437,13 @@ func (mgr *Manager) Remove(modName string) ([]resource.Name, error) {
 	}
 	mgr.logger.Infow("Resources handled by removed module will be removed",
 		"module", mod.cfg.Name, "resources", orphanedResourceNameStrings)
+
+	// A crashed module waiting to be restarted cannot close its resources, so
+	// remove it now as well.
+	if mod.cancelRecovery != nil {
+		mod.resources = map[resource.Name]*addedResource{}
+		return orphanedResourceNames, mgr.closeModule(mod, false)
+	}
 	mod.pendingRemoval = true
 	return orphanedResourceNames, nil
 }
@@ -428,6 +456,10 @@ func (mgr *Manager) closeModule(mod *module, reconfigure bool) error {
 		mgr.logger.Warnw("Forcing removal of module with active resources", "module", mod.cfg.Name)
 	}
 
+	if mod.cancelRecovery != nil {
+		mod.cancelRecovery()
+	}
+
 	// need to actually close the resources within the module itself before stopping
 	for res := range mod.resources {
 		_, err := mod.client.RemoveResource(context.Background(), &pb.RemoveResourceRequest{Name: res.String()})
@@ -544,6 +576,28 @@ func (mgr *Manager) Configs() []config.Module {
 	return configs
 }
 
+// Health returns the health of every module, including those given up on after crashing.
+func (mgr *Manager) Health() []config.ModuleHealth {
+	var health []config.ModuleHealth
+	mgr.modules.Range(func(name string, mod *module) bool {
+		health = append(health, mod.health.status(name))
+		return true
+	})
+	mgr.failedModulesMu.Lock()
+	defer mgr.failedModulesMu.Unlock()
+	for _, h := range mgr.failedModules {
+		health = append(health, h)
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Name < health[j].Name })
+	return health
+}
+
+func (mgr *Manager) clearFailedModule(name string) {
+	mgr.failedModulesMu.Lock()
+	defer mgr.failedModulesMu.Unlock()
+	delete(mgr.failedModules, name)
+}
+
 // Provides returns true if a component/service config WOULD be handled by a module.
 func (mgr *Manager) Provides(conf resource.Config) bool {
 	mgr.mu.RLock()
@@ -796,39 +850,53 @@ func (mgr *Manager) CleanModuleDataDirectory() error {
 }
 
 // oueRestartInterval is the interval of time at which an OnUnexpectedExit
-// function can attempt to restart the module process. Multiple restart
-// attempts will use basic backoff.
+// function can attempt to restart the module process, unless the module
+// configures a restart policy. Multiple restart attempts will use exponential
+// backoff.
 var oueRestartInterval = 5 * time.Second
 
 // newOnUnexpectedExitHandler returns the appropriate OnUnexpectedExit function
-// for the passed-in module to include in the pexec.ProcessConfig.
+// for the passed-in module to include in the pexec.ProcessConfig of the next
+// process started for it.
 func (mgr *Manager) newOnUnexpectedExitHandler(mod *module) func(exitCode int) bool {
+	gen := mod.processGen.Add(1)
 	return func(exitCode int) bool {
 		mod.inRecoveryLock.Lock()
 		defer mod.inRecoveryLock.Unlock()
 		if mod.inStartup.Load() {
 			return false
 		}
+		// A failed restart attempt may leave a process behind that exits only
+		// once another process has replaced it.
+		if mod.processGen.Load() != gen {
+			return false
+		}
 
 		mod.inStartup.Store(true)
 		defer mod.inStartup.Store(false)
 
 		// Log error immediately, as this is unexpected behavior.
-		mgr.logger.Errorw(
-			"Module has unexpectedly exited.", "module", mod.cfg.Name, "exit_code", exitCode,
-		)
+		fields := []interface{}{"module", mod.cfg.Name, "exit_code", exitCode}
+		if mod.limiter != nil {
+			if reason := mod.limiter.exitReason(); reason != "" {
+				fields = append(fields, "reason", reason)
+			}
+		}
+		mgr.logger.Errorw("Module has unexpectedly exited.", fields...)
+		mod.health.crashed(exitCode, newRestartPolicy(mod.cfg.RestartPolicy))
+		metrics.ModuleCrashes.WithLabelValues(mod.cfg.Name).Inc()
 
 		if err := mod.sharedConn.Close(); err != nil {
 			mod.logger.Warnw("Error closing connection to crashed module. Continuing restart attempt",
 				"error", err)
 		}
 
-		// If attemptRestart returns any orphaned resource names, restart failed,
-		// and we should remove orphaned resources. Since we handle process
-		// restarting ourselves, return false here so goutils knows not to attempt
-		// a process restart.
-		if orphanedResourceNames := mgr.attemptRestart(mgr.restartCtx, mod); orphanedResourceNames != nil {
-			if mgr.removeOrphanedResources != nil {
+		// If attemptRestart fails, we should remove orphaned resources. Since we
+		// handle process restarting ourselves, return false here so goutils knows
+		// not to attempt a process restart.
+		if orphanedResourceNames, restarted := mgr.attemptRestart(mgr.restartCtx, mod); !restarted {
+			metrics.ModuleRestarts.WithLabelValues(mod.cfg.Name, "failure").Inc()
+			if len(orphanedResourceNames) != 0 && mgr.removeOrphanedResources != nil {
 				mgr.removeOrphanedResources(mgr.restartCtx, orphanedResourceNames)
 				rNames := make([]string, 0, len(orphanedResourceNames))
 				for _, rName := range orphanedResourceNames {
@@ -838,6 +906,7 @@ func (mgr *Manager) newOnUnexpectedExitHandler(mod *module) func(exitCode int) b
 			}
 			return false
 		}
+		metrics.ModuleRestarts.WithLabelValues(mod.cfg.Name, "success").Inc()
 		mgr.logger.Infow("Module successfully restarted, re-adding resources", "module", mod.cfg.Name)
 
 		// Otherwise, add old module process' resources to new module; warn if new
@@ -869,11 +938,27 @@ func (mgr *Manager) newOnUnexpectedExitHandler(mod *module) func(exitCode int) b
 	}
 }
 
-// attemptRestart will attempt to restart the module up to three times and
-// return the names of now orphaned resources.
-func (mgr *Manager) attemptRestart(ctx context.Context, mod *module) []resource.Name {
+// attemptRestart will attempt to restart the module according to its restart
+// policy and returns whether it was restarted. If not, the names of now orphaned
+// resources are returned as well, unless the module was removed or reconfigured
+// while waiting to restart it, which handles its resources instead.
+func (mgr *Manager) attemptRestart(ctx context.Context, mod *module) ([]resource.Name, bool) {
 	mgr.mu.Lock()
-	defer mgr.mu.Unlock()
+	locked := true
+	defer func() {
+		if locked {
+			mgr.mu.Unlock()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mod.cancelRecovery = cancel
+	defer func() {
+		if locked {
+			mod.cancelRecovery = nil
+		}
+	}()
 
 	// deregister crashed module's resources, and let later checkReady reset m.handles
 	// before reregistering.
@@ -888,15 +973,20 @@ func (mgr *Manager) attemptRestart(ctx context.Context, mod *module) []resource.
 	// already.
 	rutils.RemoveFileNoError(mod.addr)
 
-	var success, processRestarted bool
+	// The crashed process needs no stopping, and must not be stopped while
+	// its exit is being handled.
+	mod.process = nil
+
+	var success, superseded bool
 	defer func() {
-		if !success {
-			if processRestarted {
-				if err := mod.stopProcess(); err != nil {
-					msg := "Error while stopping process of crashed module"
-					mgr.logger.Errorw(msg, "module", mod.cfg.Name, "error", err)
-				}
+		if !success && !superseded {
+			mod.health.failed()
+			mgr.failedModulesMu.Lock()
+			if mgr.failedModules == nil {
+				mgr.failedModules = map[string]config.ModuleHealth{}
 			}
+			mgr.failedModules[mod.cfg.Name] = mod.health.status(mod.cfg.Name)
+			mgr.failedModulesMu.Unlock()
 			mod.cleanupAfterCrash(mgr)
 		}
 	}()
@@ -905,7 +995,7 @@ func (mgr *Manager) attemptRestart(ctx context.Context, mod *module) []resource.
 		mgr.logger.CInfow(
 			ctx, "Will not attempt to restart crashed module", "module", mod.cfg.Name, "reason", ctx.Err().Error(),
 		)
-		return orphanedResourceNames
+		return orphanedResourceNames, false
 	}
 	mgr.logger.CInfow(ctx, "Attempting to restart crashed module", "module", mod.cfg.Name)
 
@@ -916,43 +1006,76 @@ func (mgr *Manager) attemptRestart(ctx context.Context, mod *module) []resource.
 		ctx, "Waiting for module to complete restart and re-registration", "module", mod.cfg.Name, mgr.logger)
 	defer cleanup()
 
-	// Attempt to restart module process 3 times.
-	for attempt := 1; attempt < 4; attempt++ {
-		if err := mgr.startModuleProcess(mod); err != nil {
-			mgr.logger.Errorw("Error while restarting crashed module", "restart attempt",
-				attempt, "module", mod.cfg.Name, "error", err)
-			if attempt == 3 {
-				// return early upon last attempt failure.
-				return orphanedResourceNames
+	policy := newRestartPolicy(mod.cfg.RestartPolicy)
+	for {
+		attempts := mod.health.attempts()
+		if policy.exhausted(attempts) {
+			mgr.logger.Errorw("Giving up on restarting crashed module", "module", mod.cfg.Name, "restart attempts", attempts)
+			return orphanedResourceNames, false
+		}
+
+		// Wait with backoff, without blocking the module manager meanwhile. Exit
+		// early if context has errorred, such as when the module is removed or
+		// reconfigured.
+		if backoff := policy.backoff(attempts); backoff > 0 {
+			mgr.mu.Unlock()
+			locked = false
+			waited := utils.SelectContextOrWait(ctx, backoff)
+			mgr.mu.Lock()
+			locked = true
+			if !waited || ctx.Err() != nil {
+				mgr.logger.CInfow(
+					ctx, "Will not continue to attempt restarting crashed module", "module", mod.cfg.Name, "reason", ctx.Err().Error(),
+				)
+				if errors.Is(mgr.restartCtx.Err(), context.Canceled) {
+					return orphanedResourceNames, false
+				}
+				superseded = true
+				return nil, false
 			}
-		} else {
-			break
 		}
 
-		// Wait with a bit of backoff. Exit early if context has errorred.
-		if !utils.SelectContextOrWait(ctx, time.Duration(attempt)*oueRestartInterval) {
-			mgr.logger.CInfow(
-				ctx, "Will not continue to attempt restarting crashed module", "module", mod.cfg.Name, "reason", ctx.Err().Error(),
-			)
-			return orphanedResourceNames
+		mod.health.attempted()
+		if err := mgr.restartModuleProcess(ctx, mod); err != nil {
+			mgr.logger.Errorw("Error while restarting crashed module", "restart attempt",
+				attempts+1, "module", mod.cfg.Name, "error", err)
+			continue
 		}
+
+		mod.registerResources(mgr, mgr.logger)
+		mod.health.started()
+		success = true
+		return nil, true
 	}
-	processRestarted = true
+}
+
+// restartModuleProcess starts a new process for a crashed module and waits for
+// it to be ready, stopping the process again if it does not become ready.
+func (mgr *Manager) restartModuleProcess(ctx context.Context, mod *module) error {
+	if err := mgr.startModuleProcess(mod); err != nil {
+		// The process either never started or has exited already, and stopping
+		// it would wait for its exit to be handled.
+		mod.process = nil
+		return err
+	}
+
+	var success bool
+	defer func() {
+		if !success {
+			if err := mod.stopProcess(); err != nil {
+				msg := "Error while stopping process of crashed module"
+				mgr.logger.Errorw(msg, "module", mod.cfg.Name, "error", err)
+			}
+		}
+	}()
 
 	if err := mod.dial(); err != nil {
-		mgr.logger.CErrorw(ctx, "Error while dialing restarted module",
-			"module", mod.cfg.Name, "error", err)
-		return orphanedResourceNames
+		return errors.WithMessage(err, "error while dialing restarted module")
 	}
 
 	if err := mod.checkReady(ctx, mgr.parentAddr, mgr.logger); err != nil {
-		mgr.logger.CErrorw(ctx, "Error while waiting for restarted module to be ready",
-			"module", mod.cfg.Name, "error", err)
-		return orphanedResourceNames
+		return errors.WithMessage(err, "error while waiting for restarted module to be ready")
 	}
-
-	mod.registerResources(mgr, mgr.logger)
-
 	success = true
 	return nil
 }
@@ -968,10 +1091,12 @@ func (m *module) dial() error {
 			rdkgrpc.EnsureTimeoutUnaryClientInterceptor,
 			grpc_retry.UnaryClientInterceptor(),
 			operation.UnaryClientInterceptor,
+			rpc.UnaryClientTracingInterceptor(),
 		),
 		grpc.WithChainStreamInterceptor(
 			grpc_retry.StreamClientInterceptor(),
 			operation.StreamClientInterceptor,
+			rpc.StreamClientTracingInterceptor(),
 		),
 	)
 	if err != nil {
@@ -1083,6 +1208,15 @@ func (m *module) startProcess(
 		Log:              true,
 		OnUnexpectedExit: oue,
 	}
+	if m.health != nil {
+		pconf.LogWriter = m.health.output
+	}
+	if m.cfg.Limits != nil {
+		m.limiter = newModuleLimiter(m.cfg.Limits, m.cfg.Name, logger)
+		if err := m.limiter.wrap(&pconf, m.dataDir, filepath.Dir(parentAddr)); err != nil {
+			return errors.WithMessage(err, "failed to apply module limits")
+		}
+	}
 	// Start module process with supplied log level or "debug" if none is
 	// supplied and module manager has a DebugLevel logger.
 	if m.cfg.LogLevel != "" {
@@ -1137,6 +1271,9 @@ func (m *module) stopProcess() error {
 	// Attempt to remove module's .sock file if module did not remove it
 	// already.
 	defer rutils.RemoveFileNoError(m.addr)
+	if m.limiter != nil {
+		defer m.limiter.close()
+	}
 
 	// TODO(RSDK-2551): stop ignoring exit status 143 once Python modules handle
 	// SIGTERM correctly.
@@ -1230,6 +1367,10 @@ func (m *module) getFullEnvironment(viamHomeDir string) map[string]string {
 	if m.cfg.Type == config.ModuleTypeRegistry {
 		environment["VIAM_MODULE_ID"] = m.cfg.ModuleID
 	}
+	// Modules export their traces as the robot does, continuing the traces of the calls made to them.
+	for key, value := range tracing.Environment(m.cfg.Name) {
+		environment[key] = value
+	}
 	// Overwrite the base environment variables with the module's environment variables (if specified)
 	for key, value := range m.cfg.Environment {
 		environment[key] = value
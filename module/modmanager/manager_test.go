@@ -29,6 +29,7 @@ import (
 	"go.viam.com/rdk/module/modmaninterface"
 	"go.viam.com/rdk/resource"
 	rtestutils "go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -145,6 +146,16 @@ func TestModManagerFunctions(t *testing.T) {
 	_, ok = modEnv["VIAM_MODULE_ID"]
 	test.That(t, ok, test.ShouldBeFalse)
 
+	// Test that modules are passed the tracing config of the robot
+	_, ok = modEnv[tracing.ConfigEnvVar]
+	test.That(t, ok, test.ShouldBeFalse)
+	tracingCfg := &tracing.Config{Enabled: true, Protocol: tracing.ProtocolFile, Path: filepath.Join(viamHomeTemp, "spans.jsonl")}
+	test.That(t, tracing.Configure(tracingCfg, "viam-server", logger), test.ShouldBeNil)
+	modEnv = mod.getFullEnvironment(viamHomeTemp)
+	test.That(t, modEnv[tracing.ServiceNameEnvVar], test.ShouldEqual, mod.cfg.Name)
+	test.That(t, modEnv[tracing.ConfigEnvVar], test.ShouldNotBeEmpty)
+	test.That(t, tracing.Close(), test.ShouldBeNil)
+
 	// Make a copy of addr and client to test that connections are properly remade
 	oldAddr := mod.addr
 	oldClient := mod.client
@@ -480,6 +491,13 @@ func TestModuleReloading(t *testing.T) {
 		test.That(t, logs.FilterMessageSnippet("Error while restarting crashed module").Len(),
 			test.ShouldEqual, 0)
 
+		health := mgr.Health()
+		test.That(t, health, test.ShouldHaveLength, 1)
+		test.That(t, health[0].Name, test.ShouldEqual, modCfg.Name)
+		test.That(t, health[0].State, test.ShouldEqual, config.ModuleStateRunning)
+		test.That(t, health[0].Crashes, test.ShouldEqual, 1)
+		test.That(t, health[0].LastCrash.IsZero(), test.ShouldBeFalse)
+
 		// Assert that RemoveOrphanedResources was not called (successful restart and re-addition of
 		// modular resources should not require removal of any orphans).
 		test.That(t, dummyRemoveOrphanedResourcesCallCount.Load(), test.ShouldEqual, 0)
@@ -549,9 +567,73 @@ func TestModuleReloading(t *testing.T) {
 		test.That(t, logs.FilterMessageSnippet("Error while restarting crashed module").Len(),
 			test.ShouldEqual, 3)
 
+		// Assert that the module is reported as failed until it is removed.
+		health := mgr.Health()
+		test.That(t, health, test.ShouldHaveLength, 1)
+		test.That(t, health[0].State, test.ShouldEqual, config.ModuleStateFailed)
+		test.That(t, health[0].Crashes, test.ShouldEqual, 1)
+		test.That(t, health[0].RestartAttempts, test.ShouldEqual, 3)
+		_, err = mgr.Remove(modCfg.Name)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, mgr.Health(), test.ShouldBeEmpty)
+
 		// Assert that RemoveOrphanedResources was called once.
 		test.That(t, dummyRemoveOrphanedResourcesCallCount.Load(), test.ShouldEqual, 1)
 	})
+	t.Run("restart forever", func(t *testing.T) {
+		logger, logs := logging.NewObservedTestLogger(t)
+
+		// Precompile module to avoid timeout issues when building takes too long.
+		modCfg := modCfg
+		modCfg.ExePath = rtestutils.BuildTempModule(t, "module/testmodule")
+		modCfg.RestartPolicy = &config.ModuleRestartPolicy{Forever: true, InitialBackoff: "10ms", MaxBackoff: "20ms"}
+
+		var dummyRemoveOrphanedResourcesCallCount atomic.Uint64
+		dummyRemoveOrphanedResources := func(context.Context, []resource.Name) {
+			dummyRemoveOrphanedResourcesCallCount.Add(1)
+		}
+		mgr := setupModManager(t, ctx, parentAddr, logger, modmanageroptions.Options{
+			UntrustedEnv:            false,
+			RemoveOrphanedResources: dummyRemoveOrphanedResources,
+		})
+		err = mgr.Add(ctx, modCfg)
+		test.That(t, err, test.ShouldBeNil)
+
+		h, err := mgr.AddResource(ctx, cfgMyHelper, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		// Remove testmodule binary, so process cannot be successfully restarted
+		// after crash, and assert that restarts are attempted beyond the default
+		// three attempts without blocking the module manager.
+		err = os.Remove(modCfg.ExePath)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = h.DoCommand(ctx, map[string]interface{}{"command": "kill_module"})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, logs.FilterMessageSnippet("Error while restarting crashed module").Len(),
+				test.ShouldBeGreaterThan, 5)
+		})
+		health := mgr.Health()
+		test.That(t, health, test.ShouldHaveLength, 1)
+		test.That(t, health[0].State, test.ShouldEqual, config.ModuleStateRestarting)
+
+		// Removing the module stops the restart attempts and closes it right
+		// away, as the crashed module cannot close its resources.
+		orphaned, err := mgr.Remove(modCfg.Name)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, orphaned, test.ShouldResemble, []resource.Name{rNameMyHelper})
+		test.That(t, mgr.IsModularResource(rNameMyHelper), test.ShouldBeFalse)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, logs.FilterMessageSnippet("Will not continue to attempt restarting crashed module").Len(),
+				test.ShouldEqual, 1)
+		})
+		test.That(t, mgr.Health(), test.ShouldBeEmpty)
+		test.That(t, dummyRemoveOrphanedResourcesCallCount.Load(), test.ShouldEqual, 0)
+		test.That(t, logs.FilterMessageSnippet("Giving up on restarting crashed module").Len(), test.ShouldEqual, 0)
+	})
 	t.Run("do not restart if context canceled", func(t *testing.T) {
 		logger, logs := logging.NewObservedTestLogger(t)
 
@@ -0,0 +1,196 @@
+package modmanager
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/config"
+)
+
+const (
+	defaultRestartMaxAttempts = 3
+	defaultRestartMaxBackoff  = 5 * time.Minute
+	defaultRestartResetWindow = time.Minute
+	// moduleOutputLines is the number of lines of a module's output kept to report in its health.
+	moduleOutputLines = 20
+)
+
+// restartPolicy is the parsed form of a config.ModuleRestartPolicy.
+type restartPolicy struct {
+	maxAttempts    int
+	forever        bool
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	resetWindow    time.Duration
+	// resetEveryCrash forgets past restart attempts whenever the module crashes, however soon after it was
+	// restarted, rather than only once it has run for resetWindow.
+	resetEveryCrash bool
+}
+
+// legacyRestartPolicy returns the restart policy of modules without one configured, which restarts them the way
+// modules were restarted before restart policies could be configured: up to three attempts each time a module
+// crashes, however often it crashes.
+func legacyRestartPolicy() restartPolicy {
+	return restartPolicy{
+		maxAttempts:     defaultRestartMaxAttempts,
+		initialBackoff:  oueRestartInterval,
+		maxBackoff:      defaultRestartMaxBackoff,
+		resetEveryCrash: true,
+	}
+}
+
+// newRestartPolicy returns the restart policy of a module, which is the legacyRestartPolicy when not configured.
+func newRestartPolicy(conf *config.ModuleRestartPolicy) restartPolicy {
+	if conf == nil {
+		return legacyRestartPolicy()
+	}
+	policy := restartPolicy{
+		maxAttempts:    defaultRestartMaxAttempts,
+		forever:        conf.Forever,
+		initialBackoff: oueRestartInterval,
+		maxBackoff:     defaultRestartMaxBackoff,
+		resetWindow:    defaultRestartResetWindow,
+	}
+	if conf.MaxAttempts != 0 {
+		policy.maxAttempts = conf.MaxAttempts
+	}
+	// durations are validated with the config
+	if dur, err := time.ParseDuration(conf.InitialBackoff); err == nil {
+		policy.initialBackoff = dur
+	}
+	if dur, err := time.ParseDuration(conf.MaxBackoff); err == nil {
+		policy.maxBackoff = dur
+	}
+	if dur, err := time.ParseDuration(conf.ResetWindow); err == nil {
+		policy.resetWindow = dur
+	}
+	return policy
+}
+
+// resets returns whether past restart attempts are forgotten when a module crashes after running for ranFor.
+func (p restartPolicy) resets(ranFor time.Duration) bool {
+	return p.resetEveryCrash || ranFor >= p.resetWindow
+}
+
+// exhausted returns whether the module should be given up on after attempts consecutive failed restart attempts.
+func (p restartPolicy) exhausted(attempts int) bool {
+	return !p.forever && attempts >= p.maxAttempts
+}
+
+// backoff returns how long to wait before the next restart attempt after attempts consecutive attempts.
+func (p restartPolicy) backoff(attempts int) time.Duration {
+	if attempts == 0 {
+		return 0
+	}
+	backoff := p.initialBackoff
+	for i := 1; i < attempts && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.maxBackoff)
+}
+
+// moduleHealth tracks the crashes and restarts of a module's process.
+type moduleHealth struct {
+	mu              sync.Mutex
+	state           config.ModuleState
+	crashes         int
+	restartAttempts int
+	lastExitCode    int
+	lastCrash       time.Time
+	lastStart       time.Time
+	output          *outputTail
+}
+
+func newModuleHealth() *moduleHealth {
+	return &moduleHealth{output: &outputTail{}}
+}
+
+// started records that the module's process started successfully.
+func (h *moduleHealth) started() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state = config.ModuleStateRunning
+	h.lastStart = time.Now()
+}
+
+// crashed records that the module's process exited unexpectedly, and forgets past restart attempts if the policy
+// resets them after how long it had been running.
+func (h *moduleHealth) crashed(exitCode int, policy restartPolicy) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state = config.ModuleStateRestarting
+	h.crashes++
+	h.lastExitCode = exitCode
+	h.lastCrash = time.Now()
+	if policy.resets(h.lastCrash.Sub(h.lastStart)) {
+		h.restartAttempts = 0
+	}
+}
+
+// attempts returns the number of consecutive restart attempts made.
+func (h *moduleHealth) attempts() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.restartAttempts
+}
+
+// attempted records a restart attempt.
+func (h *moduleHealth) attempted() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.restartAttempts++
+}
+
+// failed records that the module was given up on.
+func (h *moduleHealth) failed() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state = config.ModuleStateFailed
+}
+
+func (h *moduleHealth) status(name string) config.ModuleHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return config.ModuleHealth{
+		Name:            name,
+		State:           h.state,
+		Crashes:         h.crashes,
+		RestartAttempts: h.restartAttempts,
+		LastExitCode:    h.lastExitCode,
+		LastCrash:       h.lastCrash,
+		LastOutput:      h.output.lines(),
+	}
+}
+
+// outputTail is an io.Writer keeping the last lines written to it.
+type outputTail struct {
+	mu      sync.Mutex
+	partial []byte
+	tail    []string
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data := append(t.partial, p...)
+	for {
+		idx := bytes.IndexByte(data, '\n')
+		if idx == -1 {
+			break
+		}
+		t.tail = append(t.tail, string(data[:idx]))
+		if len(t.tail) > moduleOutputLines {
+			t.tail = t.tail[len(t.tail)-moduleOutputLines:]
+		}
+		data = data[idx+1:]
+	}
+	t.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+func (t *outputTail) lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.tail...)
+}
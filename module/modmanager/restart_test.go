@@ -0,0 +1,90 @@
+package modmanager
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+)
+
+func TestRestartPolicy(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		policy := newRestartPolicy(nil)
+		test.That(t, policy.exhausted(2), test.ShouldBeFalse)
+		test.That(t, policy.exhausted(3), test.ShouldBeTrue)
+		test.That(t, policy.backoff(0), test.ShouldEqual, 0)
+		test.That(t, policy.backoff(1), test.ShouldEqual, oueRestartInterval)
+		test.That(t, policy.backoff(2), test.ShouldEqual, 2*oueRestartInterval)
+		// attempts are forgotten on every crash, however soon after the module was restarted
+		test.That(t, policy.resetEveryCrash, test.ShouldBeTrue)
+		test.That(t, policy.resets(time.Nanosecond), test.ShouldBeTrue)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		policy := newRestartPolicy(&config.ModuleRestartPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: "1s",
+			MaxBackoff:     "5s",
+		})
+		test.That(t, policy.exhausted(4), test.ShouldBeFalse)
+		test.That(t, policy.exhausted(5), test.ShouldBeTrue)
+		test.That(t, policy.backoff(1), test.ShouldEqual, time.Second)
+		test.That(t, policy.backoff(3), test.ShouldEqual, 4*time.Second)
+		test.That(t, policy.backoff(4), test.ShouldEqual, 5*time.Second)
+		test.That(t, policy.backoff(100), test.ShouldEqual, 5*time.Second)
+		test.That(t, policy.resets(defaultRestartResetWindow-time.Second), test.ShouldBeFalse)
+		test.That(t, policy.resets(defaultRestartResetWindow), test.ShouldBeTrue)
+
+		policy = newRestartPolicy(&config.ModuleRestartPolicy{Forever: true})
+		test.That(t, policy.exhausted(1000), test.ShouldBeFalse)
+	})
+}
+
+func TestModuleHealth(t *testing.T) {
+	health := newModuleHealth()
+	health.started()
+	health.attempted()
+	health.attempted()
+
+	// a crash soon after starting keeps counting restart attempts
+	health.crashed(1, restartPolicy{resetWindow: time.Hour})
+	status := health.status("mod")
+	test.That(t, status.State, test.ShouldEqual, config.ModuleStateRestarting)
+	test.That(t, status.Crashes, test.ShouldEqual, 1)
+	test.That(t, status.LastExitCode, test.ShouldEqual, 1)
+	test.That(t, status.RestartAttempts, test.ShouldEqual, 2)
+
+	// a crash after running for the reset window forgets them
+	health.crashed(2, restartPolicy{})
+	status = health.status("mod")
+	test.That(t, status.Crashes, test.ShouldEqual, 2)
+	test.That(t, status.LastExitCode, test.ShouldEqual, 2)
+	test.That(t, status.RestartAttempts, test.ShouldEqual, 0)
+
+	// as does any crash under the legacy policy
+	health.started()
+	health.attempted()
+	health.crashed(3, restartPolicy{resetWindow: time.Hour, resetEveryCrash: true})
+	test.That(t, health.status("mod").RestartAttempts, test.ShouldEqual, 0)
+
+	health.failed()
+	test.That(t, health.status("mod").State, test.ShouldEqual, config.ModuleStateFailed)
+}
+
+func TestOutputTail(t *testing.T) {
+	tail := &outputTail{}
+	for i := 0; i < moduleOutputLines; i++ {
+		_, err := tail.Write([]byte("old\n"))
+		test.That(t, err, test.ShouldBeNil)
+	}
+	_, err := tail.Write([]byte("panic: oops"))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = tail.Write([]byte("\n\ngoroutine 1\nunterminated"))
+	test.That(t, err, test.ShouldBeNil)
+
+	lines := tail.lines()
+	test.That(t, lines, test.ShouldHaveLength, moduleOutputLines)
+	test.That(t, lines[moduleOutputLines-3:], test.ShouldResemble, []string{"panic: oops", "", "goroutine 1"})
+}
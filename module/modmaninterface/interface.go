@@ -26,6 +26,7 @@ type ModuleManager interface {
 	Configs() []config.Module
 	Provides(cfg resource.Config) bool
 	Handles() map[string]module.HandlerMap
+	Health() []config.ModuleHealth
 
 	Close(ctx context.Context) error
 }
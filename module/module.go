@@ -37,6 +37,7 @@ import (
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -199,9 +200,11 @@ func NewModule(ctx context.Context, address string, logger logging.Logger) (*Mod
 	opMgr := operation.NewManager(logger)
 	unaries := []grpc.UnaryServerInterceptor{
 		rgrpc.EnsureTimeoutUnaryServerInterceptor,
+		rpc.UnaryServerTracingInterceptor(logger.Desugar()),
 		opMgr.UnaryServerInterceptor,
 	}
 	streams := []grpc.StreamServerInterceptor{
+		rpc.StreamServerTracingInterceptor(logger.Desugar()),
 		opMgr.StreamServerInterceptor,
 	}
 	opts := []grpc.ServerOption{
@@ -259,6 +262,9 @@ func NewModuleFromArgs(ctx context.Context, logger logging.Logger) (*Module, err
 	if len(os.Args) < 2 {
 		return nil, errors.New("need socket path as command line argument")
 	}
+	if err := tracing.ConfigureFromEnvironment(logger.Sublogger("tracing")); err != nil {
+		logger.Warnw("failed to configure tracing", "error", err)
+	}
 	return NewModule(ctx, os.Args[1], logger)
 }
 
@@ -314,6 +320,9 @@ func (m *Module) Close(ctx context.Context) {
 			m.logger.Error(err)
 		}
 		m.activeBackgroundWorkers.Wait()
+		if err := tracing.Close(); err != nil {
+			m.logger.Error(err)
+		}
 	})
 }
 
@@ -163,6 +163,17 @@ func PlanFrameMotion(ctx context.Context,
 // Replan plans a motion from a provided plan request, and then will return that plan only if its cost is better than the cost of the
 // passed-in plan multiplied by `replanCostFactor`.
 func Replan(ctx context.Context, request *PlanRequest, currentPlan Plan, replanCostFactor float64) (Plan, error) {
+	return replan(ctx, request, currentPlan, replanCostFactor, nil)
+}
+
+// replan is Replan, optionally seeding the planner's start tree with the configurations of a previously solved path.
+func replan(
+	ctx context.Context,
+	request *PlanRequest,
+	currentPlan Plan,
+	replanCostFactor float64,
+	warmStart [][]frame.Input,
+) (Plan, error) {
 	// Make sure request is well formed and not missing vital information
 	if err := request.validatePlanRequest(); err != nil {
 		return nil, err
@@ -188,6 +199,9 @@ func Replan(ctx context.Context, request *PlanRequest, currentPlan Plan, replanC
 	if err != nil {
 		return nil, err
 	}
+	if !sfPlanner.useTPspace {
+		sfPlanner.warmStart = warmStart
+	}
 	// Check if the PlanRequest's options specify "complex"
 	// This is a list of poses in the same frame as the goal through which the robot must pass
 	if complexOption, ok := request.Options["complex"]; ok {
@@ -212,7 +226,7 @@ func Replan(ctx context.Context, request *PlanRequest, currentPlan Plan, replanC
 			if err != nil {
 				return nil, err
 			}
-			return multiGoalPlan, nil
+			return timeParameterizeIfRequested(request, multiGoalPlan)
 		}
 		return nil, errors.New("Invalid 'complex' option type. Expected a list of protobuf poses")
 	}
@@ -235,7 +249,25 @@ func Replan(ctx context.Context, request *PlanRequest, currentPlan Plan, replanC
 		}
 	}
 
-	return newPlan, nil
+	if sfPlanner.useTPspace {
+		if _, ok := request.Options["time_parameterize"]; ok {
+			return nil, errors.New("time parameterization is not supported when planning for a TP-space frame")
+		}
+		return newPlan, nil
+	}
+	return timeParameterizeIfRequested(request, newPlan)
+}
+
+// timeParameterizeIfRequested attaches a TimedTrajectory to the plan if the "time_parameterize" option is set.
+func timeParameterizeIfRequested(request *PlanRequest, plan Plan) (Plan, error) {
+	if enabled, ok := request.Options["time_parameterize"].(bool); !ok || !enabled {
+		return plan, nil
+	}
+	opts, err := newTimingOptionsFromExtra(request.Options)
+	if err != nil {
+		return nil, err
+	}
+	return TimeParameterizePlan(plan, request.FrameSystem, opts)
 }
 
 type planner struct {
@@ -0,0 +1,222 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// Number of configurations to sample along each span of a smoothing spline.
+	defaultSplineSamplesPerSpan = 4
+
+	// Number of times a spline may be pulled closer to its control polygon to avoid a constraint violation before giving up.
+	maxSplineRefinements = 20
+)
+
+// pathSmoother shortens and smooths a solved path through configuration space. It is independent of the planner used to produce the
+// path, and only accepts changes which satisfy every constraint in the given ConstraintHandler.
+type pathSmoother struct {
+	frame    referenceframe.Frame
+	opt      *plannerOptions
+	randseed *rand.Rand
+	logger   logging.Logger
+}
+
+// smooth runs randomized shortcutting on the path and then, if enabled, fits it with a cubic B-spline. The first and last configurations
+// of the path are never changed. If smoothing is not enabled, no budget remains, or smoothing fails, the path is returned unchanged.
+func (ps *pathSmoother) smooth(ctx context.Context, path []node) []node {
+	if !ps.opt.postSmoothingEnabled() || len(path) <= 2 {
+		return path
+	}
+	if ps.opt.PostSmoothTimeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(ps.opt.PostSmoothTimeout*float64(time.Second)))
+		defer cancel()
+	}
+	startCost := ps.cost(path)
+	path = ps.shortcut(ctx, path)
+	if ps.opt.SplineSmoothing {
+		path = ps.fitSpline(ctx, path)
+	}
+	ps.logger.CDebugf(ctx, "post-planning smoothing changed path cost from %f to %f, now with %d nodes", startCost, ps.cost(path), len(path))
+	return path
+}
+
+// shortcut repeatedly picks two random configurations along the path, and if they can be connected directly, replaces the portion of
+// the path between them. Only shortcuts which reduce the cost of the path are kept.
+func (ps *pathSmoother) shortcut(ctx context.Context, path []node) []node {
+	for i := 0; i < ps.opt.PostSmoothIter; i++ {
+		select {
+		case <-ctx.Done():
+			return path
+		default:
+		}
+		if len(path) <= 2 {
+			return path
+		}
+
+		// Pick two distinct edges, and a random point along each
+		firstEdge := ps.randseed.Intn(len(path) - 1)
+		secondEdge := ps.randseed.Intn(len(path) - 1)
+		if firstEdge == secondEdge {
+			continue
+		}
+		if firstEdge > secondEdge {
+			firstEdge, secondEdge = secondEdge, firstEdge
+		}
+		from, err := ps.frame.Interpolate(path[firstEdge].Q(), path[firstEdge+1].Q(), ps.randseed.Float64())
+		if err != nil {
+			return path
+		}
+		to, err := ps.frame.Interpolate(path[secondEdge].Q(), path[secondEdge+1].Q(), ps.randseed.Float64())
+		if err != nil {
+			return path
+		}
+
+		// Cost of the portion of the path which would be replaced
+		replacedCost := ps.distance(from, path[firstEdge+1].Q()) + ps.distance(path[secondEdge].Q(), to)
+		for j := firstEdge + 1; j < secondEdge; j++ {
+			replacedCost += ps.distance(path[j].Q(), path[j+1].Q())
+		}
+		if ps.distance(from, to) >= replacedCost-defaultEpsilon {
+			continue
+		}
+		if !ps.checkPath(from, to) {
+			continue
+		}
+
+		newPath := make([]node, 0, len(path))
+		newPath = append(newPath, path[:firstEdge+1]...)
+		newPath = appendDistinctNode(newPath, from, ps.distance)
+		newPath = appendDistinctNode(newPath, to, ps.distance)
+		for _, n := range path[secondEdge+1:] {
+			newPath = appendDistinctNode(newPath, n.Q(), ps.distance)
+		}
+		// The final configuration is the goal, and must be kept exactly
+		newPath[len(newPath)-1] = path[len(path)-1]
+		path = newPath
+	}
+	return path
+}
+
+// fitSpline replaces the path with samples from a uniform cubic B-spline using the path's configurations as control points. Wherever
+// the spline violates a constraint, the nearby control points are repeated, which pulls the spline onto the original path, and the fit
+// is retried. The endpoints are repeated so that the spline starts and ends at the same configurations as the path.
+func (ps *pathSmoother) fitSpline(ctx context.Context, path []node) []node {
+	multiplicity := make([]int, len(path))
+	for i := range multiplicity {
+		multiplicity[i] = 1
+	}
+	multiplicity[0] = 3
+	multiplicity[len(path)-1] = 3
+
+	for attempt := 0; attempt < maxSplineRefinements; attempt++ {
+		control := []int{}
+		for i, m := range multiplicity {
+			for j := 0; j < m; j++ {
+				control = append(control, i)
+			}
+		}
+
+		smoothed := []node{path[0]}
+		failedSpan := -1
+		for span := 0; span+3 < len(control) && failedSpan < 0; span++ {
+			for k := 1; k <= defaultSplineSamplesPerSpan; k++ {
+				select {
+				case <-ctx.Done():
+					return path
+				default:
+				}
+				q := evaluateSplineSpan(path, control[span:span+4], float64(k)/defaultSplineSamplesPerSpan)
+				last := smoothed[len(smoothed)-1].Q()
+				if ps.distance(last, q) < defaultEpsilon {
+					continue
+				}
+				if !ps.checkPath(last, q) {
+					failedSpan = span
+					break
+				}
+				smoothed = append(smoothed, newConfigurationNode(q))
+			}
+		}
+		if failedSpan < 0 {
+			// Land exactly on the goal
+			if ps.distance(smoothed[len(smoothed)-1].Q(), path[len(path)-1].Q()) < defaultEpsilon {
+				smoothed[len(smoothed)-1] = path[len(path)-1]
+			} else {
+				smoothed = append(smoothed, path[len(path)-1])
+			}
+			return smoothed
+		}
+
+		// Pull the spline towards the control points governing the failed span
+		refined := false
+		for _, idx := range control[failedSpan+1 : failedSpan+3] {
+			if multiplicity[idx] < 3 {
+				multiplicity[idx] = 3
+				refined = true
+			}
+		}
+		if !refined {
+			break
+		}
+	}
+	ps.logger.CDebug(ctx, "unable to fit a valid spline to the path, keeping unsmoothed path")
+	return path
+}
+
+// evaluateSplineSpan evaluates a uniform cubic B-spline span with the four given control points, at u in [0, 1].
+func evaluateSplineSpan(path []node, control []int, u float64) []referenceframe.Input {
+	basis := []float64{
+		(1 - u) * (1 - u) * (1 - u) / 6,
+		(3*u*u*u - 6*u*u + 4) / 6,
+		(-3*u*u*u + 3*u*u + 3*u + 1) / 6,
+		u * u * u / 6,
+	}
+	q := make([]referenceframe.Input, len(path[0].Q()))
+	for i, idx := range control {
+		for j, input := range path[idx].Q() {
+			q[j].Value += basis[i] * input.Value
+		}
+	}
+	return q
+}
+
+func (ps *pathSmoother) checkPath(from, to []referenceframe.Input) bool {
+	ok, _ := ps.opt.CheckSegmentAndStateValidity(
+		&ik.Segment{
+			StartConfiguration: from,
+			EndConfiguration:   to,
+			Frame:              ps.frame,
+		},
+		ps.opt.Resolution,
+	)
+	return ok
+}
+
+func (ps *pathSmoother) distance(from, to []referenceframe.Input) float64 {
+	return ps.opt.DistanceFunc(&ik.Segment{StartConfiguration: from, EndConfiguration: to})
+}
+
+func (ps *pathSmoother) cost(path []node) float64 {
+	cost := 0.
+	for i := 1; i < len(path); i++ {
+		cost += ps.distance(path[i-1].Q(), path[i].Q())
+	}
+	return cost
+}
+
+// appendDistinctNode appends a node with the given configuration to the path, unless it is indistinguishable from the last node.
+func appendDistinctNode(path []node, q []referenceframe.Input, distFunc func(from, to []referenceframe.Input) float64) []node {
+	if len(path) > 0 && distFunc(path[len(path)-1].Q(), q) < defaultEpsilon {
+		return path
+	}
+	return append(path, newConfigurationNode(q))
+}
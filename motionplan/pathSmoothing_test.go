@@ -0,0 +1,108 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// inWall reports whether a configuration is within the region |x| < 1, y < 6, shrunk by the given margin.
+func inWall(q []referenceframe.Input, margin float64) bool {
+	return math.Abs(q[0].Value) < 1-margin && q[1].Value < 6-margin
+}
+
+// newTestPathSmoother creates a smoother for a 2D mobile frame which must avoid a wall in configuration space.
+func newTestPathSmoother(t *testing.T) *pathSmoother {
+	t.Helper()
+	frame, err := referenceframe.New2DMobileModelFrame("base", []referenceframe.Limit{{-10, 10}, {-10, 10}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	opt := newBasicPlannerOptions(frame)
+	opt.Resolution = 0.1
+	opt.PostSmoothIter = 100
+	opt.AddStateConstraint("wall", func(state *ik.State) bool {
+		return !inWall(state.Configuration, 0)
+	})
+	return &pathSmoother{
+		frame:    frame,
+		opt:      opt,
+		randseed: rand.New(rand.NewSource(1)),
+		logger:   logging.NewTestLogger(t),
+	}
+}
+
+func detourPath() []node {
+	return []node{
+		newConfigurationNode(referenceframe.FloatsToInputs([]float64{-5, 0})),
+		newConfigurationNode(referenceframe.FloatsToInputs([]float64{-5, 9})),
+		newConfigurationNode(referenceframe.FloatsToInputs([]float64{0, 9})),
+		newConfigurationNode(referenceframe.FloatsToInputs([]float64{5, 9})),
+		newConfigurationNode(referenceframe.FloatsToInputs([]float64{5, 0})),
+	}
+}
+
+func checkSmoothedPath(t *testing.T, ps *pathSmoother, original, smoothed []node) {
+	t.Helper()
+	test.That(t, smoothed[0].Q(), test.ShouldResemble, original[0].Q())
+	test.That(t, smoothed[len(smoothed)-1].Q(), test.ShouldResemble, original[len(original)-1].Q())
+	// Segments are only checked at the planner resolution, so may clip the wall by up to half of that
+	for i := 1; i < len(smoothed); i++ {
+		for step := 0.; step <= 1; step += 0.01 {
+			q, err := ps.frame.Interpolate(smoothed[i-1].Q(), smoothed[i].Q(), step)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, inWall(q, ps.opt.Resolution/2), test.ShouldBeFalse)
+		}
+	}
+	test.That(t, ps.cost(smoothed), test.ShouldBeLessThanOrEqualTo, ps.cost(original))
+}
+
+func TestShortcutSmoothing(t *testing.T) {
+	ps := newTestPathSmoother(t)
+	path := detourPath()
+	smoothed := ps.smooth(context.Background(), path)
+	checkSmoothedPath(t, ps, path, smoothed)
+	test.That(t, ps.cost(smoothed), test.ShouldBeLessThan, ps.cost(path))
+
+	t.Run("disabled by default", func(t *testing.T) {
+		ps := newTestPathSmoother(t)
+		ps.opt.PostSmoothIter = newBasicPlannerOptions(ps.frame).PostSmoothIter
+		test.That(t, ps.opt.postSmoothingEnabled(), test.ShouldBeFalse)
+		test.That(t, ps.smooth(context.Background(), path), test.ShouldResemble, path)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		test.That(t, ps.smooth(ctx, path), test.ShouldResemble, path)
+	})
+}
+
+func TestSplineSmoothing(t *testing.T) {
+	ps := newTestPathSmoother(t)
+	ps.opt.SplineSmoothing = true
+	path := detourPath()
+
+	splined := ps.fitSpline(context.Background(), path)
+	checkSmoothedPath(t, ps, path, splined)
+	test.That(t, len(splined), test.ShouldBeGreaterThan, len(path))
+
+	t.Run("refines around obstacle", func(t *testing.T) {
+		// The unrefined spline through this path would cut the corner into the obstacle
+		tight := []node{
+			newConfigurationNode(referenceframe.FloatsToInputs([]float64{-1.1, 0})),
+			newConfigurationNode(referenceframe.FloatsToInputs([]float64{-1.1, 6.1})),
+			newConfigurationNode(referenceframe.FloatsToInputs([]float64{1.1, 6.1})),
+			newConfigurationNode(referenceframe.FloatsToInputs([]float64{1.1, 0})),
+		}
+		splined := ps.fitSpline(context.Background(), tight)
+		checkSmoothedPath(t, ps, tight, splined)
+	})
+}
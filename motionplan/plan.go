@@ -58,6 +58,9 @@ func OffsetPlan(plan Plan, offset spatialmath.Pose) Plan {
 		newPath = append(newPath, newStep)
 	}
 	simplePlan := NewSimplePlan(newPath, plan.Trajectory())
+	if timed, ok := plan.(TimedPlan); ok {
+		simplePlan.timed = timed.TimedTrajectory()
+	}
 	if rrt, ok := plan.(*rrtPlan); ok {
 		return &rrtPlan{SimplePlan: *simplePlan, nodes: rrt.nodes}
 	}
@@ -226,9 +229,11 @@ func NewGeoPlan(plan Plan, pt *geo.Point) Plan {
 }
 
 // SimplePlan is a struct containing a Path and a Trajectory, together these comprise a Plan.
+// A SimplePlan may additionally carry a TimedTrajectory if its Trajectory has been time parameterized.
 type SimplePlan struct {
-	path Path
-	traj Trajectory
+	path  Path
+	traj  Trajectory
+	timed TimedTrajectory
 }
 
 // NewSimplePlan instantiates a new Plan from a Path and Trajectory.
@@ -252,6 +257,11 @@ func (plan *SimplePlan) Trajectory() Trajectory {
 	return plan.traj
 }
 
+// TimedTrajectory returns the time parameterization of the Plan's Trajectory, or nil if it has not been time parameterized.
+func (plan *SimplePlan) TimedTrajectory() TimedTrajectory {
+	return plan.timed
+}
+
 // ExecutionState describes a plan and a particular state along it.
 type ExecutionState struct {
 	plan  Plan
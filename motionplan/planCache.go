@@ -0,0 +1,448 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// default values for a PlanCache.
+const (
+	defaultPlanCacheCapacity        = 100
+	defaultPlanCacheInputResolution = 0.001
+	defaultPlanCacheGoalResolution  = 0.1
+	defaultNearMissInputDistance    = 0.5
+	defaultNearMissGoalDistance     = 50.
+	defaultNearMissGoalAngle        = 0.2
+
+	// quaternion components of a goal orientation are rounded to this resolution when building cache keys.
+	planCacheOrientationResolution = 1e-4
+)
+
+var errCachedPlanInvalid = errors.New("cached plan is not valid for the request")
+
+// PlanCacheConfig describes how a PlanCache decides whether two requests are the same. Zero values will be replaced with defaults.
+type PlanCacheConfig struct {
+	// Maximum number of plans to keep. The least recently used plan is evicted first.
+	Capacity int `json:"capacity,omitempty"`
+
+	// Start inputs are rounded to this resolution, in radians or mm, before being compared.
+	InputResolution float64 `json:"input_resolution,omitempty"`
+
+	// Goal positions are rounded to this resolution, in mm, before being compared.
+	GoalResolutionMM float64 `json:"goal_resolution_mm,omitempty"`
+
+	// A cached plan whose start inputs and goal are within these distances of a request is used to seed planning for that request.
+	NearMissInputDistance  float64 `json:"near_miss_input_distance,omitempty"`
+	NearMissGoalDistanceMM float64 `json:"near_miss_goal_distance_mm,omitempty"`
+	NearMissGoalAngleRads  float64 `json:"near_miss_goal_angle_rads,omitempty"`
+}
+
+// PlanCacheStats counts how requests to a PlanCache were served.
+type PlanCacheStats struct {
+	// Requests answered with a cached plan without planning.
+	Hits int `json:"hits"`
+	// Requests planned from scratch.
+	Misses int `json:"misses"`
+	// Requests planned with a similar cached plan used to seed the planner.
+	NearMisses int `json:"near_misses"`
+	// Cached plans which matched a request, but no longer satisfied its constraints.
+	Invalidated int `json:"invalidated"`
+	// Number of plans currently cached.
+	Size int `json:"size"`
+}
+
+type planCacheKey struct {
+	// hash of everything about a request other than where it starts and ends
+	context string
+	// hash of the context along with the quantized start and goal
+	exact  string
+	inputs []float64
+	goal   spatialmath.Pose
+}
+
+type planCacheEntry struct {
+	key      planCacheKey
+	steps    [][]referenceframe.Input
+	lastUsed uint64
+}
+
+// PlanCache remembers solved plans so that a repeated PlanRequest can be answered without planning. A cached plan is re-validated against
+// the constraints of each request it is returned for. A request similar to a cached one is planned with the cached path seeding the
+// planner. Plans for frames which plan in TP-space are not cached.
+type PlanCache struct {
+	mu      sync.Mutex
+	cfg     PlanCacheConfig
+	entries []*planCacheEntry
+	clock   uint64
+	stats   PlanCacheStats
+}
+
+// NewPlanCache creates an empty PlanCache.
+func NewPlanCache(cfg PlanCacheConfig) *PlanCache {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = defaultPlanCacheCapacity
+	}
+	if cfg.InputResolution <= 0 {
+		cfg.InputResolution = defaultPlanCacheInputResolution
+	}
+	if cfg.GoalResolutionMM <= 0 {
+		cfg.GoalResolutionMM = defaultPlanCacheGoalResolution
+	}
+	if cfg.NearMissInputDistance <= 0 {
+		cfg.NearMissInputDistance = defaultNearMissInputDistance
+	}
+	if cfg.NearMissGoalDistanceMM <= 0 {
+		cfg.NearMissGoalDistanceMM = defaultNearMissGoalDistance
+	}
+	if cfg.NearMissGoalAngleRads <= 0 {
+		cfg.NearMissGoalAngleRads = defaultNearMissGoalAngle
+	}
+	return &PlanCache{cfg: cfg}
+}
+
+// PlanMotion plans a motion from a provided plan request, returning a cached plan if one is valid for the request.
+func (pc *PlanCache) PlanMotion(ctx context.Context, request *PlanRequest) (Plan, error) {
+	if err := request.validatePlanRequest(); err != nil {
+		return nil, err
+	}
+	sf, err := newSolverFrame(request.FrameSystem, request.Frame.Name(), request.Goal.Parent(), request.StartConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	if len(sf.PTGSolvers()) > 0 {
+		return PlanMotion(ctx, request)
+	}
+	key, err := newPlanCacheKey(request, pc.cfg)
+	if err != nil {
+		request.Logger.CDebugf(ctx, "unable to build plan cache key, planning without cache: %v", err)
+		return PlanMotion(ctx, request)
+	}
+
+	entry, exact := pc.lookup(key)
+	if entry != nil && exact {
+		plan, err := validateCachedPlan(request, sf, entry.steps)
+		if err == nil {
+			pc.mu.Lock()
+			pc.stats.Hits++
+			pc.mu.Unlock()
+			return plan, nil
+		}
+		request.Logger.CDebugf(ctx, "discarding cached plan: %v", err)
+		pc.mu.Lock()
+		pc.stats.Invalidated++
+		pc.mu.Unlock()
+	}
+
+	var warmStart [][]referenceframe.Input
+	pc.mu.Lock()
+	if entry != nil {
+		pc.stats.NearMisses++
+		warmStart = entry.steps
+	} else {
+		pc.stats.Misses++
+	}
+	pc.mu.Unlock()
+
+	plan, err := replan(ctx, request, nil, 0, warmStart)
+	if err != nil {
+		return nil, err
+	}
+	if rrt, ok := plan.(*rrtPlan); ok {
+		steps := make([][]referenceframe.Input, 0, len(rrt.nodes))
+		for _, n := range rrt.nodes {
+			steps = append(steps, n.Q())
+		}
+		pc.store(key, steps)
+	}
+	return plan, nil
+}
+
+// Stats returns counts of how requests to the cache have been served.
+func (pc *PlanCache) Stats() PlanCacheStats {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	stats := pc.stats
+	stats.Size = len(pc.entries)
+	return stats
+}
+
+// Clear removes all cached plans.
+func (pc *PlanCache) Clear() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.entries = nil
+}
+
+// lookup returns the cached entry matching the key exactly if there is one, otherwise the closest entry within near-miss distance.
+func (pc *PlanCache) lookup(key planCacheKey) (*planCacheEntry, bool) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.clock++
+
+	var nearest *planCacheEntry
+	nearestDist := math.Inf(1)
+	for _, entry := range pc.entries {
+		if entry.key.context != key.context {
+			continue
+		}
+		if entry.key.exact == key.exact {
+			entry.lastUsed = pc.clock
+			return entry, true
+		}
+		if len(entry.key.inputs) != len(key.inputs) {
+			continue
+		}
+		inputDist := floatsL2Distance(entry.key.inputs, key.inputs)
+		goalDist := entry.key.goal.Point().Distance(key.goal.Point())
+		goalAngle := spatialmath.QuatToR3AA(
+			spatialmath.OrientationBetween(entry.key.goal.Orientation(), key.goal.Orientation()).Quaternion(),
+		).Norm()
+		if inputDist > pc.cfg.NearMissInputDistance || goalDist > pc.cfg.NearMissGoalDistanceMM || goalAngle > pc.cfg.NearMissGoalAngleRads {
+			continue
+		}
+		// normalize so that start and goal distances contribute equally
+		dist := inputDist/pc.cfg.NearMissInputDistance + goalDist/pc.cfg.NearMissGoalDistanceMM
+		if dist < nearestDist {
+			nearest, nearestDist = entry, dist
+		}
+	}
+	if nearest != nil {
+		nearest.lastUsed = pc.clock
+	}
+	return nearest, false
+}
+
+// store adds a plan to the cache, replacing any existing plan with the same key and evicting the least recently used plan if full.
+func (pc *PlanCache) store(key planCacheKey, steps [][]referenceframe.Input) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.clock++
+
+	entry := &planCacheEntry{key: key, steps: steps, lastUsed: pc.clock}
+	for i, existing := range pc.entries {
+		if existing.key.exact == key.exact {
+			pc.entries[i] = entry
+			return
+		}
+	}
+	if len(pc.entries) >= pc.cfg.Capacity {
+		oldest := 0
+		for i, existing := range pc.entries {
+			if existing.lastUsed < pc.entries[oldest].lastUsed {
+				oldest = i
+			}
+		}
+		pc.entries = append(pc.entries[:oldest], pc.entries[oldest+1:]...)
+	}
+	pc.entries = append(pc.entries, entry)
+}
+
+// newPlanCacheKey builds the key under which the result of a request is cached.
+func newPlanCacheKey(request *PlanRequest, cfg PlanCacheConfig) (planCacheKey, error) {
+	hasher := sha256.New()
+	fsHash, err := frameSystemHash(request.FrameSystem)
+	if err != nil {
+		return planCacheKey{}, err
+	}
+	hasher.Write([]byte(fsHash))
+	hasher.Write([]byte(request.Frame.Name()))
+	hasher.Write([]byte(request.Goal.Parent()))
+
+	if request.WorldState != nil {
+		wsProto, err := request.WorldState.ToProtobuf()
+		if err != nil {
+			return planCacheKey{}, err
+		}
+		wsBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(wsProto)
+		if err != nil {
+			return planCacheKey{}, err
+		}
+		hasher.Write(wsBytes)
+	}
+	if request.Constraints != nil {
+		constraintBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(request.Constraints.ToProtobuf())
+		if err != nil {
+			return planCacheKey{}, err
+		}
+		hasher.Write(constraintBytes)
+	}
+	for _, region := range request.BoundingRegions {
+		regionProto, err := region.ToProtobuf()
+		if err != nil {
+			return planCacheKey{}, err
+		}
+		regionBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(regionProto)
+		if err != nil {
+			return planCacheKey{}, err
+		}
+		hasher.Write(regionBytes)
+	}
+	// json.Marshal sorts map keys, so this is deterministic
+	optionBytes, err := json.Marshal(request.Options)
+	if err != nil {
+		return planCacheKey{}, err
+	}
+	hasher.Write(optionBytes)
+	contextHash := hasher.Sum(nil)
+
+	frameNames := make([]string, 0, len(request.StartConfiguration))
+	for name := range request.StartConfiguration {
+		frameNames = append(frameNames, name)
+	}
+	sort.Strings(frameNames)
+	inputs := []float64{}
+	for _, name := range frameNames {
+		hasher.Write([]byte(name))
+		for _, input := range request.StartConfiguration[name] {
+			inputs = append(inputs, input.Value)
+			writeQuantized(hasher, input.Value, cfg.InputResolution)
+		}
+	}
+
+	goal := request.Goal.Pose()
+	pt := goal.Point()
+	for _, v := range []float64{pt.X, pt.Y, pt.Z} {
+		writeQuantized(hasher, v, cfg.GoalResolutionMM)
+	}
+	q := goal.Orientation().Quaternion()
+	// q and -q are the same orientation
+	if q.Real < 0 {
+		q.Real, q.Imag, q.Jmag, q.Kmag = -q.Real, -q.Imag, -q.Jmag, -q.Kmag
+	}
+	for _, v := range []float64{q.Real, q.Imag, q.Jmag, q.Kmag} {
+		writeQuantized(hasher, v, planCacheOrientationResolution)
+	}
+
+	return planCacheKey{
+		context: hex.EncodeToString(contextHash),
+		exact:   hex.EncodeToString(hasher.Sum(nil)),
+		inputs:  inputs,
+		goal:    goal,
+	}, nil
+}
+
+// frameSystemHash returns a hash of the names, parents, and serialized contents of every frame in a frame system.
+func frameSystemHash(fs referenceframe.FrameSystem) (string, error) {
+	hasher := sha256.New()
+	names := fs.FrameNames()
+	sort.Strings(names)
+	for _, name := range names {
+		f := fs.Frame(name)
+		parent, err := fs.Parent(f)
+		if err != nil {
+			return "", err
+		}
+		frameBytes, err := f.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		hasher.Write([]byte(name))
+		hasher.Write([]byte(parent.Name()))
+		hasher.Write(frameBytes)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func writeQuantized(hasher interface{ Write([]byte) (int, error) }, value, resolution float64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(int64(math.Round(value/resolution))))
+	//nolint:errcheck
+	hasher.Write(buf[:])
+}
+
+func floatsL2Distance(a, b []float64) float64 {
+	dist := 0.
+	for i := range a {
+		dist += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(dist)
+}
+
+// validateCachedPlan checks that the steps of a cached plan, started from the request's actual start configuration, satisfy every
+// constraint of the request and reach its goal. If so, a plan is returned following those steps.
+func validateCachedPlan(request *PlanRequest, sf *solverFrame, steps [][]referenceframe.Input) (Plan, error) {
+	if len(steps) == 0 {
+		return nil, errCachedPlanInvalid
+	}
+	pm, err := newPlanManager(sf, request.Logger, defaultRandomSeed)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := sf.mapToSlice(request.StartConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	if len(seed) != len(steps[0]) {
+		return nil, errCachedPlanInvalid
+	}
+	startPose, err := sf.Transform(seed)
+	if err != nil {
+		return nil, err
+	}
+	goalPos := request.Goal.Pose()
+	if sf.worldRooted {
+		tf, err := sf.fss.Transform(request.StartConfiguration, request.Goal, referenceframe.World)
+		if err != nil {
+			return nil, err
+		}
+		goalPos = tf.(*referenceframe.PoseInFrame).Pose()
+	}
+	opt, err := pm.plannerSetupFromMoveRequest(
+		startPose,
+		goalPos,
+		request.StartConfiguration,
+		request.WorldState,
+		request.BoundingRegions,
+		request.Constraints,
+		request.Options,
+	)
+	if err != nil {
+		return nil, err
+	}
+	opt.SetGoal(goalPos)
+
+	// Follow the cached steps from exactly where we are now
+	path := append([][]referenceframe.Input{seed}, steps[1:]...)
+	for i := 1; i < len(path); i++ {
+		ok, _ := opt.CheckSegmentAndStateValidity(
+			&ik.Segment{StartConfiguration: path[i-1], EndConfiguration: path[i], Frame: sf},
+			opt.Resolution,
+		)
+		if !ok {
+			return nil, errors.Wrapf(errCachedPlanInvalid, "step %d violates constraints", i)
+		}
+	}
+	final := path[len(path)-1]
+	finalPose, err := sf.Transform(final)
+	if err != nil {
+		return nil, err
+	}
+	if opt.goalMetric(&ik.State{Position: finalPose, Configuration: final, Frame: sf}) > opt.GoalThreshold {
+		return nil, errors.Wrap(errCachedPlanInvalid, "plan does not reach the goal")
+	}
+
+	nodes := make([]node, 0, len(path))
+	for _, q := range path {
+		nodes = append(nodes, newConfigurationNode(q))
+	}
+	plan, err := newRRTPlan(nodes, sf, false)
+	if err != nil {
+		return nil, err
+	}
+	return timeParameterizeIfRequested(request, plan)
+}
@@ -0,0 +1,160 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func planCacheTestRequest(t *testing.T, goal r3.Vector) *PlanRequest {
+	t.Helper()
+	robotGeom, err := spatial.NewSphere(spatial.NewZeroPose(), 10, "base")
+	test.That(t, err, test.ShouldBeNil)
+	base, err := frame.New2DMobileModelFrame("base", []frame.Limit{{-1000, 1000}, {-1000, 1000}}, robotGeom)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(base, fs.World()), test.ShouldBeNil)
+
+	// A wall between the start and the goal
+	wall, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{250, 0, 0}), r3.Vector{20, 400, 20}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := frame.NewWorldState([]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatial.Geometry{wall})}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	return &PlanRequest{
+		Logger:             logging.NewTestLogger(t),
+		Goal:               frame.NewPoseInFrame(frame.World, spatial.NewPoseFromPoint(goal)),
+		Frame:              base,
+		FrameSystem:        fs,
+		StartConfiguration: map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{0, 0})},
+		WorldState:         worldState,
+		Options:            map[string]interface{}{"rseed": 1},
+	}
+}
+
+func TestPlanCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewPlanCache(PlanCacheConfig{})
+	goal := r3.Vector{500, 0, 0}
+
+	plan, err := cache.PlanMotion(ctx, planCacheTestRequest(t, goal))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cache.Stats(), test.ShouldResemble, PlanCacheStats{Misses: 1, Size: 1})
+
+	// An identical request is served from the cache
+	cachedPlan, err := cache.PlanMotion(ctx, planCacheTestRequest(t, goal))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cache.Stats(), test.ShouldResemble, PlanCacheStats{Hits: 1, Misses: 1, Size: 1})
+	test.That(t, cachedPlan.Trajectory(), test.ShouldResemble, plan.Trajectory())
+
+	// A request for a slightly different goal is planned with the cached path as a seed
+	_, err = cache.PlanMotion(ctx, planCacheTestRequest(t, r3.Vector{510, 10, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cache.Stats(), test.ShouldResemble, PlanCacheStats{Hits: 1, Misses: 1, NearMisses: 1, Size: 2})
+
+	// A request for a faraway goal is planned from scratch
+	_, err = cache.PlanMotion(ctx, planCacheTestRequest(t, r3.Vector{-500, 0, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cache.Stats(), test.ShouldResemble, PlanCacheStats{Hits: 1, Misses: 2, NearMisses: 1, Size: 3})
+
+	cache.Clear()
+	test.That(t, cache.Stats().Size, test.ShouldEqual, 0)
+}
+
+func TestPlanCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	cache := NewPlanCache(PlanCacheConfig{})
+	goal := r3.Vector{500, 0, 0}
+
+	_, err := cache.PlanMotion(ctx, planCacheTestRequest(t, goal))
+	test.That(t, err, test.ShouldBeNil)
+
+	// Replace the cached path with one that drives straight through the wall
+	cache.entries[0].steps = [][]frame.Input{
+		frame.FloatsToInputs([]float64{0, 0}),
+		frame.FloatsToInputs([]float64{500, 0}),
+	}
+	request := planCacheTestRequest(t, goal)
+	sf, err := newSolverFrame(request.FrameSystem, request.Frame.Name(), request.Goal.Parent(), request.StartConfiguration)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = validateCachedPlan(request, sf, cache.entries[0].steps)
+	test.That(t, err, test.ShouldBeError)
+
+	// The invalid plan is replanned rather than returned
+	plan, err := cache.PlanMotion(ctx, request)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan.Trajectory()), test.ShouldBeGreaterThan, 2)
+	test.That(t, cache.Stats(), test.ShouldResemble, PlanCacheStats{Misses: 1, NearMisses: 1, Invalidated: 1, Size: 1})
+}
+
+func TestPlanCacheEviction(t *testing.T) {
+	cache := NewPlanCache(PlanCacheConfig{Capacity: 2})
+	keys := []planCacheKey{}
+	for i := 0; i < 3; i++ {
+		key, err := newPlanCacheKey(planCacheTestRequest(t, r3.Vector{500, float64(i) * 100, 0}), cache.cfg)
+		test.That(t, err, test.ShouldBeNil)
+		keys = append(keys, key)
+	}
+	test.That(t, keys[0].context, test.ShouldEqual, keys[1].context)
+	test.That(t, keys[0].exact, test.ShouldNotEqual, keys[1].exact)
+
+	cache.store(keys[0], nil)
+	cache.store(keys[1], nil)
+	// Use the first entry so that the second is the least recently used
+	entry, exact := cache.lookup(keys[0])
+	test.That(t, entry, test.ShouldNotBeNil)
+	test.That(t, exact, test.ShouldBeTrue)
+	cache.store(keys[2], nil)
+
+	test.That(t, cache.Stats().Size, test.ShouldEqual, 2)
+	_, exact = cache.lookup(keys[0])
+	test.That(t, exact, test.ShouldBeTrue)
+	_, exact = cache.lookup(keys[1])
+	test.That(t, exact, test.ShouldBeFalse)
+}
+
+func TestGraftWarmStart(t *testing.T) {
+	request := planCacheTestRequest(t, r3.Vector{500, 0, 0})
+	sf, err := newSolverFrame(request.FrameSystem, request.Frame.Name(), request.Goal.Parent(), request.StartConfiguration)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, request.Logger, 1)
+	test.That(t, err, test.ShouldBeNil)
+	pm.warmStart = [][]frame.Input{
+		frame.FloatsToInputs([]float64{0, 100}),
+		frame.FloatsToInputs([]float64{100, 100}),
+	}
+	pathPlanner, err := newCBiRRTMotionPlanner(sf, rand.New(rand.NewSource(1)), request.Logger, newBasicPlannerOptions(sf))
+	test.That(t, err, test.ShouldBeNil)
+
+	// Whatever else the start tree holds, the warm start path is grafted onto its root at the seed
+	seed := frame.FloatsToInputs([]float64{0, 0})
+	for i := 0; i < 10; i++ {
+		root := &basicNode{q: seed}
+		maps := &rrtMaps{startMap: map[node]node{root: nil}, goalMap: map[node]node{}}
+		for j := 1; j <= 5; j++ {
+			maps.startMap[&basicNode{q: frame.FloatsToInputs([]float64{float64(-10 * j), 0})}] = root
+		}
+		pm.graftWarmStart(maps, seed, pathPlanner)
+
+		test.That(t, len(maps.startMap), test.ShouldEqual, 8)
+		for n, parent := range maps.startMap {
+			switch n.Q()[0].Value {
+			case 0:
+				if n.Q()[1].Value == 100 {
+					test.That(t, parent, test.ShouldEqual, root)
+				}
+			case 100:
+				test.That(t, parent.Q(), test.ShouldResemble, pm.warmStart[0])
+			}
+		}
+	}
+}
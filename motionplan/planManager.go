@@ -39,6 +39,9 @@ type planManager struct {
 	activeBackgroundWorkers sync.WaitGroup
 
 	useTPspace bool
+
+	// configurations of a previously solved path which will be grafted onto the start tree of each RRT planner
+	warmStart [][]referenceframe.Input
 }
 
 func newPlanManager(
@@ -339,13 +342,30 @@ func (pm *planManager) planAtomicWaypoints(
 	}
 
 	// All goals have been submitted for solving. Reconstruct in order
-	resultSlices := []node{}
+	solvedSteps := make([][]node, 0, len(resultPromises))
 	for i, future := range resultPromises {
 		steps, err := future.result()
 		if err != nil {
 			return nil, err
 		}
 		pm.logger.Debug("completed planning for ", spatialmath.PoseToProtobuf(goals[i]))
+		solvedSteps = append(solvedSteps, steps)
+	}
+
+	// Now that all planning is done, smooth each solved path. This is done after all results are resolved as background planners may
+	// still be drawing from pm.randseed until then.
+	resultSlices := []node{}
+	for i, steps := range solvedSteps {
+		// Only draw from pm.randseed when smoothing is enabled, so that plans without it are unchanged
+		if !pm.useTPspace && planners[i].opt().postSmoothingEnabled() {
+			smoother := &pathSmoother{
+				frame:    pm.frame,
+				opt:      planners[i].opt(),
+				randseed: rand.New(rand.NewSource(int64(pm.randseed.Int()))),
+				logger:   pm.logger,
+			}
+			steps = smoother.smooth(ctx, steps)
+		}
 		resultSlices = append(resultSlices, steps...)
 	}
 
@@ -424,6 +444,7 @@ func (pm *planManager) planParallelRRTMotion(
 			return
 		}
 		maps = planSeed.maps
+		pm.graftWarmStart(maps, seed, pathPlanner)
 	}
 
 	// publish endpoint of plan if it is known
@@ -838,6 +859,43 @@ func (pm *planManager) planToRRTGoalMap(plan Plan, goal spatialmath.Pose) (*rrtM
 	return maps, nil
 }
 
+// graftWarmStart adds the configurations of the warm start path to the start tree, for as long as the path can be followed from the seed
+// without violating constraints. The planner may then extend its start tree from anywhere along the previous solution.
+func (pm *planManager) graftWarmStart(maps *rrtMaps, seed []referenceframe.Input, pathPlanner motionPlanner) {
+	if len(pm.warmStart) == 0 {
+		return
+	}
+	// Graft onto the seed node, which is the root of the start tree
+	var parent node
+	for n, p := range maps.startMap {
+		if p == nil && referenceframe.InputsL2Distance(n.Q(), seed) < defaultEpsilon {
+			parent = n
+			break
+		}
+	}
+	if parent == nil {
+		return
+	}
+	grafted := 0
+	for _, q := range pm.warmStart {
+		if len(q) != len(seed) {
+			return
+		}
+		cost := pathPlanner.opt().DistanceFunc(&ik.Segment{StartConfiguration: parent.Q(), EndConfiguration: q})
+		if cost < defaultEpsilon {
+			continue
+		}
+		if !pathPlanner.checkPath(parent.Q(), q) {
+			break
+		}
+		child := &basicNode{q: q, cost: parent.Cost() + cost}
+		maps.startMap[child] = parent
+		parent = child
+		grafted++
+	}
+	pm.logger.Debugf("grafted %d nodes from a previous solution onto the start tree", grafted)
+}
+
 // planRelativeWaypoint will solve the solver frame to one individual pose. This is used for solverframes whose inputs are relative, that
 // is, the pose returned by `Transform` is a transformation rather than an absolute position.
 func (pm *planManager) planRelativeWaypoint(ctx context.Context, request *PlanRequest, seedPlan Plan) (Plan, error) {
@@ -48,6 +48,9 @@ const (
 	// default number of times to try to smooth the path.
 	defaultSmoothIter = 100
 
+	// default number of seconds to spend smoothing each solved path after planning, if enabled.
+	defaultPostSmoothTimeout = 1.0
+
 	// default number of position only seeds to use for tp-space planning.
 	defaultTPspacePositionOnlySeeds = 16
 
@@ -116,6 +119,7 @@ func newBasicPlannerOptions(frame referenceframe.Frame) *plannerOptions {
 	opt.PlannerConstructor = newCBiRRTMotionPlanner
 
 	opt.SmoothIter = defaultSmoothIter
+	opt.PostSmoothTimeout = defaultPostSmoothTimeout
 
 	opt.NumThreads = defaultNumThreads
 
@@ -151,6 +155,16 @@ type plannerOptions struct {
 	// Number of times to try to smooth the path
 	SmoothIter int `json:"smooth_iter"`
 
+	// Number of shortcuts to attempt on the solved path after planning, regardless of planner. Post-planning smoothing is off unless
+	// this is > 0 or SplineSmoothing is set.
+	PostSmoothIter int `json:"post_smooth_iter"`
+
+	// Number of seconds to spend smoothing the solved path after planning. Set <= 0 for no time limit.
+	PostSmoothTimeout float64 `json:"post_smooth_timeout"`
+
+	// Whether to fit the solved path with a B-spline after shortcutting
+	SplineSmoothing bool `json:"spline_smoothing"`
+
 	// Number of cpu cores to use
 	NumThreads int `json:"num_threads"`
 
@@ -205,6 +219,11 @@ func (p *plannerOptions) SetPathMetric(m ik.StateMetric) {
 	p.pathMetric = m
 }
 
+// postSmoothingEnabled returns whether solved paths should be smoothed after planning.
+func (p *plannerOptions) postSmoothingEnabled() bool {
+	return p.PostSmoothIter > 0 || p.SplineSmoothing
+}
+
 // SetMaxSolutions sets the maximum number of IK solutions to generate for the planner.
 func (p *plannerOptions) SetMaxSolutions(maxSolutions int) {
 	p.MaxSolutions = maxSolutions
@@ -0,0 +1,733 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// default values for time parameterization.
+const (
+	// Maximum distance, in units of Input, that the blended path is allowed to deviate from an intermediate waypoint.
+	defaultTimingMaxDeviation = 0.01
+
+	// Spacing, in units of Input, of the points along the path at which the kinematic limits are enforced.
+	defaultTimingPathResolution = 0.001
+
+	// Number of seconds between consecutive samples of a timed trajectory.
+	defaultTimingSamplePeriod = 0.01
+
+	// Upper bound on the number of points along the path at which limits are enforced, to bound computation on long paths.
+	maxTimingPathSamples = 100000
+
+	// Distance below which two configurations or directions are considered identical.
+	timingEpsilon = 1e-9
+)
+
+var (
+	errNoTimingLimits      = errors.New("time parameterization requires a velocity or acceleration limit for every moving degree of freedom")
+	errTimingStalled       = errors.New("time parameterization could not make progress along the path")
+	errTimingBadSampleRate = errors.New("time parameterization sample period must be positive")
+)
+
+// TimingOptions configures how a path is converted into a time-parameterized trajectory.
+type TimingOptions struct {
+	// How far, in units of Input, the trajectory may deviate from an intermediate waypoint in order to pass it without stopping.
+	// Set <= 0 to stop at every waypoint.
+	MaxDeviation float64 `json:"timing_max_deviation"`
+
+	// Spacing, in units of Input, of the points along the path at which kinematic limits are enforced. Defaults if left unset.
+	PathResolution float64 `json:"timing_path_resolution"`
+
+	// Number of seconds between consecutive samples of the resulting trajectory.
+	SamplePeriod float64 `json:"timing_sample_period"`
+
+	// Limits used for any degree of freedom whose velocity, acceleration or jerk is not limited by its kinematic model.
+	DefaultVelocity     float64 `json:"timing_default_max_vel"`
+	DefaultAcceleration float64 `json:"timing_default_max_acc"`
+	DefaultJerk         float64 `json:"timing_default_max_jerk"`
+}
+
+// NewTimingOptions returns a set of TimingOptions with default values.
+func NewTimingOptions() *TimingOptions {
+	return &TimingOptions{
+		MaxDeviation:   defaultTimingMaxDeviation,
+		PathResolution: defaultTimingPathResolution,
+		SamplePeriod:   defaultTimingSamplePeriod,
+	}
+}
+
+// newTimingOptionsFromExtra reads any timing options present in a set of planning options, overwriting defaults.
+func newTimingOptionsFromExtra(extra map[string]interface{}) (*TimingOptions, error) {
+	opts := NewTimingOptions()
+	jsonString, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jsonString, opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// TimedWaypoint is a single sample of a time-parameterized trajectory for a single frame.
+type TimedWaypoint struct {
+	Time       time.Duration
+	Inputs     []referenceframe.Input
+	Velocities []float64 // in units of Input per second
+}
+
+// TimedStep is a single sample of a time-parameterized trajectory, mapping each Frame's name to its Inputs and their velocities.
+type TimedStep struct {
+	Time       time.Duration
+	Positions  map[string][]referenceframe.Input
+	Velocities map[string][]float64
+}
+
+// TimedTrajectory is a series of TimedSteps sampled at a regular interval, describing where a robot should be at each point in time
+// while following a Plan.
+type TimedTrajectory []TimedStep
+
+// Duration returns the time needed to execute the trajectory.
+func (tt TimedTrajectory) Duration() time.Duration {
+	if len(tt) == 0 {
+		return 0
+	}
+	return tt[len(tt)-1].Time
+}
+
+// GetFrameWaypoints is a helper function which will extract the timed waypoints of a single frame from the trajectory.
+func (tt TimedTrajectory) GetFrameWaypoints(frameName string) ([]TimedWaypoint, error) {
+	waypoints := make([]TimedWaypoint, 0, len(tt))
+	for _, step := range tt {
+		inputs, ok := step.Positions[frameName]
+		if !ok {
+			return nil, fmt.Errorf("frame named %s not found in timed trajectory", frameName)
+		}
+		waypoints = append(waypoints, TimedWaypoint{Time: step.Time, Inputs: inputs, Velocities: step.Velocities[frameName]})
+	}
+	return waypoints, nil
+}
+
+// TimedPlan is a Plan which additionally describes the timing of its Trajectory.
+type TimedPlan interface {
+	Plan
+	TimedTrajectory() TimedTrajectory
+}
+
+// TimeParameterizePlan returns a copy of the given plan whose Trajectory has been time-parameterized subject to the kinematic limits of
+// each frame in the frame system. The geometric path is blended around intermediate waypoints by at most opts.MaxDeviation, and then
+// traversed as quickly as the velocity and acceleration limits allow, starting and ending at rest. If any jerk limits are present the
+// resulting motion is additionally smoothed so that they are respected.
+func TimeParameterizePlan(plan Plan, fs referenceframe.FrameSystem, opts *TimingOptions) (TimedPlan, error) {
+	traj := plan.Trajectory()
+	if len(traj) == 0 {
+		return nil, errors.New("cannot time parameterize an empty trajectory")
+	}
+
+	// Concatenate the inputs of every moving frame into a single configuration, in a deterministic order
+	frameNames := make([]string, 0, len(traj[0]))
+	for name, inputs := range traj[0] {
+		if len(inputs) > 0 {
+			frameNames = append(frameNames, name)
+		}
+	}
+	sort.Strings(frameNames)
+	limits := []referenceframe.KinematicLimit{}
+	for _, name := range frameNames {
+		f := fs.Frame(name)
+		if f == nil {
+			return nil, referenceframe.NewFrameMissingError(name)
+		}
+		limits = append(limits, referenceframe.KinematicLimits(f)...)
+	}
+	waypoints := make([][]referenceframe.Input, 0, len(traj))
+	for _, step := range traj {
+		inputs := make([]referenceframe.Input, 0, len(limits))
+		for _, name := range frameNames {
+			frameInputs, ok := step[name]
+			if !ok {
+				return nil, fmt.Errorf("frame named %s not found in trajectory", name)
+			}
+			inputs = append(inputs, frameInputs...)
+		}
+		waypoints = append(waypoints, inputs)
+	}
+
+	timed, err := TimeParameterizeInputs(waypoints, limits, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	timedTraj := make(TimedTrajectory, 0, len(timed))
+	for _, waypoint := range timed {
+		step := TimedStep{
+			Time:       waypoint.Time,
+			Positions:  make(map[string][]referenceframe.Input, len(traj[0])),
+			Velocities: make(map[string][]float64, len(traj[0])),
+		}
+		// Frames without inputs are carried along so that every frame in the Trajectory appears in each step
+		for name, inputs := range traj[0] {
+			if len(inputs) == 0 {
+				step.Positions[name] = inputs
+				step.Velocities[name] = []float64{}
+			}
+		}
+		idx := 0
+		for _, name := range frameNames {
+			dof := len(traj[0][name])
+			step.Positions[name] = waypoint.Inputs[idx : idx+dof]
+			step.Velocities[name] = waypoint.Velocities[idx : idx+dof]
+			idx += dof
+		}
+		timedTraj = append(timedTraj, step)
+	}
+
+	simplePlan := NewSimplePlan(plan.Path(), traj)
+	simplePlan.timed = timedTraj
+	if rrt, ok := plan.(*rrtPlan); ok {
+		return &rrtPlan{SimplePlan: *simplePlan, nodes: rrt.nodes}, nil
+	}
+	return simplePlan, nil
+}
+
+// TimeParameterizeInputs computes a time-optimal trajectory which passes near each of the given waypoints in order, subject to the
+// per-input kinematic limits. The trajectory starts and ends at rest and is sampled every opts.SamplePeriod seconds.
+func TimeParameterizeInputs(
+	waypoints [][]referenceframe.Input,
+	limits []referenceframe.KinematicLimit,
+	opts *TimingOptions,
+) ([]TimedWaypoint, error) {
+	if opts == nil {
+		opts = NewTimingOptions()
+	}
+	if opts.SamplePeriod <= 0 {
+		return nil, errTimingBadSampleRate
+	}
+	if len(waypoints) == 0 {
+		return nil, errors.New("cannot time parameterize an empty set of waypoints")
+	}
+	points := make([][]float64, 0, len(waypoints))
+	for _, waypoint := range waypoints {
+		if len(waypoint) != len(limits) {
+			return nil, referenceframe.NewIncorrectInputLengthError(len(waypoint), len(limits))
+		}
+		points = append(points, referenceframe.InputsToFloats(waypoint))
+	}
+	limits = applyDefaultLimits(limits, opts)
+
+	path := newTimingPath(points, opts.MaxDeviation)
+	if len(path) == 0 {
+		// Every waypoint is the same, so the trajectory is already complete
+		return []TimedWaypoint{{Inputs: waypoints[0], Velocities: make([]float64, len(limits))}}, nil
+	}
+	profile, err := newTimingProfile(path, limits, opts.PathResolution)
+	if err != nil {
+		return nil, err
+	}
+	// Each stretch of the path between the points where the profile comes to rest is sampled and smoothed separately, as the
+	// path may change direction abruptly where it comes to rest.
+	var arcs, speeds []float64
+	rests := profile.rests()
+	for i := 0; i+1 < len(rests); i++ {
+		sectionArcs, sectionSpeeds := profile.sample(opts.SamplePeriod, rests[i], rests[i+1])
+		sectionArcs, sectionSpeeds = profile.limitJerk(sectionArcs, sectionSpeeds, limits, opts.SamplePeriod)
+		if i > 0 {
+			// the first sample of a section is the last of the one before
+			sectionArcs, sectionSpeeds = sectionArcs[1:], sectionSpeeds[1:]
+		}
+		arcs = append(arcs, sectionArcs...)
+		speeds = append(speeds, sectionSpeeds...)
+	}
+
+	timed := make([]TimedWaypoint, 0, len(arcs))
+	for i := range arcs {
+		position, velocity := profile.state(arcs[i], speeds[i])
+		timed = append(timed, TimedWaypoint{
+			Time:       time.Duration(math.Round(float64(i) * opts.SamplePeriod * float64(time.Second))),
+			Inputs:     referenceframe.FloatsToInputs(position),
+			Velocities: velocity,
+		})
+	}
+	return timed, nil
+}
+
+func applyDefaultLimits(limits []referenceframe.KinematicLimit, opts *TimingOptions) []referenceframe.KinematicLimit {
+	withDefaults := make([]referenceframe.KinematicLimit, 0, len(limits))
+	for _, limit := range limits {
+		if limit.Velocity <= 0 {
+			limit.Velocity = opts.DefaultVelocity
+		}
+		if limit.Acceleration <= 0 {
+			limit.Acceleration = opts.DefaultAcceleration
+		}
+		if limit.Jerk <= 0 {
+			limit.Jerk = opts.DefaultJerk
+		}
+		withDefaults = append(withDefaults, limit)
+	}
+	return withDefaults
+}
+
+// timingPathSegment is a piece of a path through configuration space, parameterized by arc length.
+type timingPathSegment interface {
+	length() float64
+	position(s float64) []float64
+	// tangent returns the first derivative of position with respect to arc length.
+	tangent(s float64) []float64
+	// curvature returns the second derivative of position with respect to arc length.
+	curvature(s float64) []float64
+}
+
+type linearTimingSegment struct {
+	start     []float64
+	direction []float64
+	len       float64
+}
+
+func newLinearTimingSegment(start, end []float64) *linearTimingSegment {
+	diff := make([]float64, len(start))
+	for i := range start {
+		diff[i] = end[i] - start[i]
+	}
+	l := vecNorm(diff)
+	for i := range diff {
+		diff[i] /= l
+	}
+	return &linearTimingSegment{start: start, direction: diff, len: l}
+}
+
+func (ls *linearTimingSegment) length() float64 {
+	return ls.len
+}
+
+func (ls *linearTimingSegment) position(s float64) []float64 {
+	pos := make([]float64, len(ls.start))
+	for i := range pos {
+		pos[i] = ls.start[i] + s*ls.direction[i]
+	}
+	return pos
+}
+
+func (ls *linearTimingSegment) tangent(s float64) []float64 {
+	return ls.direction
+}
+
+func (ls *linearTimingSegment) curvature(s float64) []float64 {
+	return make([]float64, len(ls.start))
+}
+
+// circularTimingSegment is an arc in the plane spanned by x and y, used to blend between two linear segments.
+type circularTimingSegment struct {
+	center []float64
+	x, y   []float64
+	radius float64
+	angle  float64
+}
+
+// newCircularBlend creates an arc which blends the corner at b of the path a->b->c, deviating from b by at most maxDeviation. It returns
+// nil if the corner is too shallow to need blending or too sharp to be blended.
+// Reference: Kunz & Stilman, "Time-Optimal Trajectory Generation for Path Following with Bounded Acceleration and Velocity", RSS 2012.
+func newCircularBlend(a, b, c []float64, maxDeviation float64) *circularTimingSegment {
+	if maxDeviation <= 0 {
+		return nil
+	}
+	in := newLinearTimingSegment(a, b)
+	out := newLinearTimingSegment(b, c)
+	cosAngle := math.Max(-1, math.Min(1, vecDot(in.direction, out.direction)))
+	angle := math.Acos(cosAngle)
+	if angle < 1e-6 || math.Pi-angle < 1e-6 {
+		return nil
+	}
+	halfAngle := angle / 2
+	blendDistance := math.Min(in.len/2, math.Min(out.len/2, maxDeviation*math.Sin(halfAngle)/(1-math.Cos(halfAngle))))
+	radius := blendDistance / math.Tan(halfAngle)
+
+	dirDiff := make([]float64, len(b))
+	for i := range b {
+		dirDiff[i] = out.direction[i] - in.direction[i]
+	}
+	diffNorm := vecNorm(dirDiff)
+	center := make([]float64, len(b))
+	x := make([]float64, len(b))
+	for i := range b {
+		center[i] = b[i] + dirDiff[i]/diffNorm*radius/math.Cos(halfAngle)
+		x[i] = b[i] - blendDistance*in.direction[i] - center[i]
+	}
+	xNorm := vecNorm(x)
+	for i := range x {
+		x[i] /= xNorm
+	}
+	return &circularTimingSegment{center: center, x: x, y: in.direction, radius: radius, angle: angle}
+}
+
+func (cs *circularTimingSegment) length() float64 {
+	return cs.radius * cs.angle
+}
+
+func (cs *circularTimingSegment) position(s float64) []float64 {
+	cos, sin := math.Cos(s/cs.radius), math.Sin(s/cs.radius)
+	pos := make([]float64, len(cs.center))
+	for i := range pos {
+		pos[i] = cs.center[i] + cs.radius*(cs.x[i]*cos+cs.y[i]*sin)
+	}
+	return pos
+}
+
+func (cs *circularTimingSegment) tangent(s float64) []float64 {
+	cos, sin := math.Cos(s/cs.radius), math.Sin(s/cs.radius)
+	tan := make([]float64, len(cs.center))
+	for i := range tan {
+		tan[i] = -cs.x[i]*sin + cs.y[i]*cos
+	}
+	return tan
+}
+
+func (cs *circularTimingSegment) curvature(s float64) []float64 {
+	cos, sin := math.Cos(s/cs.radius), math.Sin(s/cs.radius)
+	curv := make([]float64, len(cs.center))
+	for i := range curv {
+		curv[i] = -(cs.x[i]*cos + cs.y[i]*sin) / cs.radius
+	}
+	return curv
+}
+
+// newTimingPath builds a path through the given waypoints which is made up of straight lines joined by circular blends.
+func newTimingPath(waypoints [][]float64, maxDeviation float64) []timingPathSegment {
+	// Drop repeated waypoints, which would otherwise produce degenerate segments
+	deduped := [][]float64{waypoints[0]}
+	for _, waypoint := range waypoints[1:] {
+		if vecDist(waypoint, deduped[len(deduped)-1]) > timingEpsilon {
+			deduped = append(deduped, waypoint)
+		}
+	}
+
+	path := []timingPathSegment{}
+	start := deduped[0]
+	for i := 1; i < len(deduped)-1; i++ {
+		blend := newCircularBlend(deduped[i-1], deduped[i], deduped[i+1], maxDeviation)
+		if blend == nil {
+			path = appendLinearTimingSegment(path, start, deduped[i])
+			start = deduped[i]
+			continue
+		}
+		path = appendLinearTimingSegment(path, start, blend.position(0))
+		path = append(path, blend)
+		start = blend.position(blend.length())
+	}
+	if len(deduped) > 1 {
+		path = appendLinearTimingSegment(path, start, deduped[len(deduped)-1])
+	}
+	return path
+}
+
+func appendLinearTimingSegment(path []timingPathSegment, start, end []float64) []timingPathSegment {
+	if vecDist(start, end) <= timingEpsilon {
+		return path
+	}
+	return append(path, newLinearTimingSegment(start, end))
+}
+
+// timingProfile is the result of time-optimal parameterization of a path, describing the squared path velocity at a series of points
+// along it. The path acceleration is constant between consecutive points.
+type timingProfile struct {
+	path     []timingPathSegment
+	offsets  []float64   // arc length at which each path segment starts
+	segment  []int       // index of the path segment containing each interval
+	local    []float64   // arc length at which each interval starts, relative to the start of its segment
+	ds       []float64   // arc length of each interval
+	xs       []float64   // squared path velocity at each point
+	times    []float64   // time, in seconds, at which each point is reached
+	tangents [][]float64 // tangent at the start of each interval
+}
+
+// newTimingProfile computes the fastest traversal of the path such that velocity and acceleration limits are not exceeded, by
+// integrating the path velocity backward from the end and then forward from the start along the limit curve.
+func newTimingProfile(path []timingPathSegment, limits []referenceframe.KinematicLimit, resolution float64) (*timingProfile, error) {
+	profile := &timingProfile{path: path}
+	totalLength := 0.
+	for _, seg := range path {
+		profile.offsets = append(profile.offsets, totalLength)
+		totalLength += seg.length()
+	}
+	if resolution <= 0 {
+		resolution = defaultTimingPathResolution
+	}
+	resolution = math.Max(resolution, totalLength/maxTimingPathSamples)
+
+	// Discretize each segment separately so that segment boundaries fall on sample points
+	type pathPoint struct{ tangent, curvature []float64 }
+	maxX := []float64{0}
+	var curvatures [][]float64
+	for segIdx, seg := range path {
+		n := int(math.Ceil(seg.length() / resolution))
+		ds := seg.length() / float64(n)
+		for i := 0; i < n; i++ {
+			s := float64(i) * ds
+			tan, curv := seg.tangent(s), seg.curvature(s)
+			profile.segment = append(profile.segment, segIdx)
+			profile.local = append(profile.local, s)
+			profile.ds = append(profile.ds, ds)
+			profile.tangents = append(profile.tangents, tan)
+			curvatures = append(curvatures, curv)
+			// The limit at each point must hold for the interval on either side of it
+			maxX[len(maxX)-1] = math.Min(maxX[len(maxX)-1], maxPathVelocitySq(tan, curv, limits))
+			endTan, endCurv := seg.tangent(s+ds), seg.curvature(s+ds)
+			maxX = append(maxX, maxPathVelocitySq(endTan, endCurv, limits))
+		}
+		// A discontinuity in direction between segments can only be traversed while stopped
+		if segIdx+1 < len(path) && vecDist(seg.tangent(seg.length()), path[segIdx+1].tangent(0)) > 1e-6 {
+			maxX[len(maxX)-1] = 0
+		}
+	}
+	if len(maxX) > 0 {
+		maxX[0] = 0
+		maxX[len(maxX)-1] = 0
+	}
+	for _, x := range maxX {
+		if math.IsInf(x, 1) {
+			return nil, errNoTimingLimits
+		}
+	}
+
+	// Backward pass: the fastest speed at each point from which it is still possible to decelerate in time for the rest of the path
+	xs := make([]float64, len(maxX))
+	for i := len(maxX) - 2; i >= 0; i-- {
+		xs[i] = math.Min(maxX[i], maxDecelerableVelocitySq(profile.tangents[i], curvatures[i], limits, profile.ds[i], xs[i+1]))
+	}
+	// Forward pass: accelerate as hard as possible without exceeding the backward pass
+	for i := 0; i < len(xs)-1; i++ {
+		_, hi := pathAccelerationBounds(profile.tangents[i], curvatures[i], limits, xs[i])
+		xs[i+1] = math.Max(0, math.Min(xs[i+1], xs[i]+2*profile.ds[i]*hi))
+	}
+	profile.xs = xs
+
+	profile.times = make([]float64, len(xs))
+	for i := 0; i < len(xs)-1; i++ {
+		denom := math.Sqrt(xs[i]) + math.Sqrt(xs[i+1])
+		if denom <= 0 {
+			return nil, errTimingStalled
+		}
+		profile.times[i+1] = profile.times[i] + 2*profile.ds[i]/denom
+	}
+	return profile, nil
+}
+
+// duration returns the number of seconds needed to traverse the profile.
+func (tp *timingProfile) duration() float64 {
+	if len(tp.times) == 0 {
+		return 0
+	}
+	return tp.times[len(tp.times)-1]
+}
+
+// rests returns the indices of the points at which the profile is at rest, which include its first and last points.
+func (tp *timingProfile) rests() []int {
+	var rests []int
+	for i, x := range tp.xs {
+		if x == 0 {
+			rests = append(rests, i)
+		}
+	}
+	return rests
+}
+
+// sample returns the arc length and path velocity along the profile every period seconds, from its point first until its point
+// last, ending with the latter.
+func (tp *timingProfile) sample(period float64, first, last int) ([]float64, []float64) {
+	start, duration := tp.times[first], tp.times[last]-tp.times[first]
+	n := int(math.Ceil(duration/period - timingEpsilon))
+	arcs := make([]float64, 0, n+1)
+	speeds := make([]float64, 0, n+1)
+	interval := first
+	for k := 0; k <= n; k++ {
+		t := start + math.Min(float64(k)*period, duration)
+		for interval < last-1 && tp.times[interval+1] <= t {
+			interval++
+		}
+		sdot := math.Sqrt(tp.xs[interval])
+		sddot := (tp.xs[interval+1] - tp.xs[interval]) / (2 * tp.ds[interval])
+		tau := t - tp.times[interval]
+		s := math.Min(tp.local[interval]+sdot*tau+0.5*sddot*tau*tau, tp.local[interval]+tp.ds[interval])
+		arcs = append(arcs, tp.offsets[tp.segment[interval]]+s)
+		speeds = append(speeds, math.Max(0, sdot+sddot*tau))
+	}
+	return arcs, speeds
+}
+
+// state returns the position and velocity of the inputs at arc length s along the path, moving at path velocity sdot.
+func (tp *timingProfile) state(s, sdot float64) ([]float64, []float64) {
+	segIdx := sort.SearchFloat64s(tp.offsets, s)
+	if segIdx == len(tp.offsets) || (segIdx > 0 && tp.offsets[segIdx] > s) {
+		segIdx--
+	}
+	seg := tp.path[segIdx]
+	local := math.Max(0, math.Min(s-tp.offsets[segIdx], seg.length()))
+	tan := seg.tangent(local)
+	vel := make([]float64, len(tan))
+	for i := range tan {
+		vel[i] = tan[i] * sdot
+	}
+	return seg.position(local), vel
+}
+
+// maxPathVelocitySq returns the largest squared path velocity at a point with the given tangent and curvature for which no input
+// exceeds its velocity limit and some path acceleration exists which keeps every input within its acceleration limit.
+func maxPathVelocitySq(tangent, curvature []float64, limits []referenceframe.KinematicLimit) float64 {
+	maxX := math.Inf(1)
+	for j, lim := range limits {
+		if lim.Velocity > 0 && math.Abs(tangent[j]) > timingEpsilon {
+			maxX = math.Min(maxX, math.Pow(lim.Velocity/tangent[j], 2))
+		}
+		if lim.Acceleration <= 0 {
+			continue
+		}
+		if math.Abs(tangent[j]) <= timingEpsilon {
+			// Input j is not moving along the path; only the centripetal term contributes to its acceleration
+			if math.Abs(curvature[j]) > timingEpsilon {
+				maxX = math.Min(maxX, lim.Acceleration/math.Abs(curvature[j]))
+			}
+			continue
+		}
+		// Input j bounds the path acceleration to within a/|d| of -(c/d)x. The bounds of inputs j and k stop overlapping at the x where
+		// a_j/|d_j| + a_k/|d_k| = (c_j/d_j - c_k/d_k) x.
+		for k, other := range limits {
+			if other.Acceleration <= 0 || math.Abs(tangent[k]) <= timingEpsilon {
+				continue
+			}
+			slope := curvature[j]/tangent[j] - curvature[k]/tangent[k]
+			if slope > timingEpsilon {
+				width := lim.Acceleration/math.Abs(tangent[j]) + other.Acceleration/math.Abs(tangent[k])
+				maxX = math.Min(maxX, width/slope)
+			}
+		}
+	}
+	return maxX
+}
+
+// pathAccelerationBounds returns the range of path accelerations which keep every input within its acceleration limit when moving along
+// a point of the path with the given tangent and curvature at squared path velocity x.
+func pathAccelerationBounds(tangent, curvature []float64, limits []referenceframe.KinematicLimit, x float64) (float64, float64) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	for j, lim := range limits {
+		if lim.Acceleration <= 0 || math.Abs(tangent[j]) <= timingEpsilon {
+			continue
+		}
+		center := -curvature[j] / tangent[j] * x
+		halfWidth := lim.Acceleration / math.Abs(tangent[j])
+		lo = math.Max(lo, center-halfWidth)
+		hi = math.Min(hi, center+halfWidth)
+	}
+	return lo, hi
+}
+
+// maxDecelerableVelocitySq returns the largest squared path velocity at a point from which the squared path velocity can be brought
+// to at most nextX over the following ds of path while respecting acceleration limits.
+func maxDecelerableVelocitySq(
+	tangent, curvature []float64,
+	limits []referenceframe.KinematicLimit,
+	ds, nextX float64,
+) float64 {
+	// The slowest permitted path acceleration at x for input j is -a_j/|d_j| - (c_j/d_j)x, which must satisfy x + 2 ds u <= nextX
+	maxX := math.Inf(1)
+	for j, lim := range limits {
+		if lim.Acceleration <= 0 || math.Abs(tangent[j]) <= timingEpsilon {
+			continue
+		}
+		scale := 1 - 2*ds*curvature[j]/tangent[j]
+		if scale <= 0 {
+			continue
+		}
+		maxX = math.Min(maxX, (nextX+2*ds*lim.Acceleration/math.Abs(tangent[j]))/scale)
+	}
+	if math.IsInf(maxX, 1) {
+		// Without any acceleration limits, velocity may change instantaneously
+		return maxX
+	}
+	return math.Max(0, maxX)
+}
+
+// limitJerk smooths a uniformly sampled stretch of the profile, which starts and ends at rest, so that no input exceeds its jerk limit.
+// Only the timing of the motion along the path is smoothed, by averaging the arc length over a sliding window long enough that the
+// acceleration of each input can swing through its full observed range without exceeding its jerk limit, so the trajectory stays on
+// the path that was checked for collisions. Averaging cannot increase the peak path velocity, and along straight portions of the path,
+// where the inputs move in proportion to the arc length, it cannot increase the peak velocity or acceleration of any input either.
+func (tp *timingProfile) limitJerk(
+	arcs, speeds []float64,
+	limits []referenceframe.KinematicLimit,
+	period float64,
+) ([]float64, []float64) {
+	window := 0.
+	for j, lim := range limits {
+		if lim.Jerk <= 0 {
+			continue
+		}
+		peakAccel := 0.
+		_, last := tp.state(arcs[0], speeds[0])
+		for i := 1; i < len(arcs); i++ {
+			_, vel := tp.state(arcs[i], speeds[i])
+			peakAccel = math.Max(peakAccel, math.Abs(vel[j]-last[j])/period)
+			last = vel
+		}
+		window = math.Max(window, 2*peakAccel/lim.Jerk)
+	}
+	n := int(math.Ceil(window/period - timingEpsilon))
+	if n <= 1 {
+		return arcs, speeds
+	}
+
+	// The stretch is padded at rest at its start before the beginning, and at its end after the end
+	padded := make([]float64, 0, len(arcs)+2*(n-1))
+	paddedSpeeds := make([]float64, 0, len(arcs)+2*(n-1))
+	for i := 0; i < n-1; i++ {
+		padded = append(padded, arcs[0])
+		paddedSpeeds = append(paddedSpeeds, 0)
+	}
+	padded = append(padded, arcs...)
+	paddedSpeeds = append(paddedSpeeds, speeds...)
+	for i := 0; i < n-1; i++ {
+		padded = append(padded, arcs[len(arcs)-1])
+		paddedSpeeds = append(paddedSpeeds, 0)
+	}
+
+	smoothedArcs := make([]float64, 0, len(padded)-n+1)
+	smoothedSpeeds := make([]float64, 0, len(padded)-n+1)
+	for k := 0; k+n <= len(padded); k++ {
+		s, sdot := 0., 0.
+		for i := k; i < k+n; i++ {
+			s += padded[i] / float64(n)
+			sdot += paddedSpeeds[i] / float64(n)
+		}
+		smoothedArcs = append(smoothedArcs, s)
+		smoothedSpeeds = append(smoothedSpeeds, sdot)
+	}
+	// Snap the endpoints to remove floating point error introduced by averaging
+	smoothedArcs[0] = arcs[0]
+	smoothedArcs[len(smoothedArcs)-1] = arcs[len(arcs)-1]
+	return smoothedArcs, smoothedSpeeds
+}
+
+func vecDot(a, b []float64) float64 {
+	dot := 0.
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return dot
+}
+
+func vecNorm(a []float64) float64 {
+	return math.Sqrt(vecDot(a, a))
+}
+
+func vecDist(a, b []float64) float64 {
+	dist := 0.
+	for i := range a {
+		dist += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(dist)
+}
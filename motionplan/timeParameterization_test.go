@@ -0,0 +1,252 @@
+package motionplan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func floatWaypoints(points ...[]float64) [][]frame.Input {
+	waypoints := make([][]frame.Input, 0, len(points))
+	for _, p := range points {
+		waypoints = append(waypoints, frame.FloatsToInputs(p))
+	}
+	return waypoints
+}
+
+// checkTimedLimits verifies, using finite differences of the sampled velocities, that no input exceeds its limits.
+func checkTimedLimits(t *testing.T, timed []TimedWaypoint, limits []frame.KinematicLimit, period float64) {
+	t.Helper()
+	const slack = 1.05
+	accels := make([][]float64, 0, len(timed))
+	for i := range timed {
+		for j, lim := range limits {
+			if lim.Velocity > 0 {
+				test.That(t, math.Abs(timed[i].Velocities[j]), test.ShouldBeLessThanOrEqualTo, lim.Velocity*slack)
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		accel := make([]float64, len(limits))
+		for j, lim := range limits {
+			accel[j] = (timed[i].Velocities[j] - timed[i-1].Velocities[j]) / period
+			if lim.Acceleration > 0 {
+				test.That(t, math.Abs(accel[j]), test.ShouldBeLessThanOrEqualTo, lim.Acceleration*slack)
+			}
+		}
+		accels = append(accels, accel)
+	}
+	for i := 1; i < len(accels); i++ {
+		for j, lim := range limits {
+			if lim.Jerk > 0 {
+				test.That(t, math.Abs(accels[i][j]-accels[i-1][j])/period, test.ShouldBeLessThanOrEqualTo, lim.Jerk*slack)
+			}
+		}
+	}
+}
+
+func TestTimeParameterizeStraightLine(t *testing.T) {
+	limits := []frame.KinematicLimit{{Velocity: 1, Acceleration: 1}}
+	opts := NewTimingOptions()
+	timed, err := TimeParameterizeInputs(floatWaypoints([]float64{0}, []float64{2}), limits, opts)
+	test.That(t, err, test.ShouldBeNil)
+
+	// Accelerate for 1s over 0.5, cruise for 1s over 1, and decelerate for 1s over 0.5
+	duration := timed[len(timed)-1].Time.Seconds()
+	test.That(t, duration, test.ShouldAlmostEqual, 3, 0.02)
+	test.That(t, timed[0].Inputs[0].Value, test.ShouldEqual, 0)
+	test.That(t, timed[len(timed)-1].Inputs[0].Value, test.ShouldAlmostEqual, 2)
+	test.That(t, timed[0].Velocities[0], test.ShouldEqual, 0)
+	test.That(t, timed[len(timed)/2].Velocities[0], test.ShouldAlmostEqual, 1, 1e-3)
+	checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+
+	for i := 1; i < len(timed); i++ {
+		test.That(t, (timed[i].Time - timed[i-1].Time).Seconds(), test.ShouldAlmostEqual, opts.SamplePeriod)
+		test.That(t, timed[i].Inputs[0].Value, test.ShouldBeGreaterThanOrEqualTo, timed[i-1].Inputs[0].Value)
+	}
+}
+
+func TestTimeParameterizeSynchronized(t *testing.T) {
+	limits := []frame.KinematicLimit{{Velocity: 1, Acceleration: 2}, {Velocity: 1, Acceleration: 2}}
+	opts := NewTimingOptions()
+	timed, err := TimeParameterizeInputs(floatWaypoints([]float64{0, 0}, []float64{1, -3}), limits, opts)
+	test.That(t, err, test.ShouldBeNil)
+	checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+
+	// The slower joint sets the pace, and the other joint moves in proportion so that both arrive together
+	for _, waypoint := range timed {
+		test.That(t, waypoint.Inputs[1].Value, test.ShouldAlmostEqual, -3*waypoint.Inputs[0].Value, 1e-9)
+		test.That(t, waypoint.Velocities[1], test.ShouldAlmostEqual, -3*waypoint.Velocities[0], 1e-9)
+	}
+	test.That(t, timed[len(timed)-1].Inputs[0].Value, test.ShouldAlmostEqual, 1)
+	test.That(t, timed[len(timed)-1].Inputs[1].Value, test.ShouldAlmostEqual, -3)
+}
+
+func TestTimeParameterizeBlending(t *testing.T) {
+	limits := []frame.KinematicLimit{{Velocity: 1, Acceleration: 1}, {Velocity: 1, Acceleration: 1}}
+	waypoints := floatWaypoints([]float64{0, 0}, []float64{1, 0}, []float64{1, 1})
+	corner := r3.Vector{X: 1, Y: 0}
+	closestToCorner := func(timed []TimedWaypoint) (float64, float64) {
+		minDist, speed := math.Inf(1), 0.
+		for _, waypoint := range timed {
+			pt := r3.Vector{X: waypoint.Inputs[0].Value, Y: waypoint.Inputs[1].Value}
+			if dist := pt.Sub(corner).Norm(); dist < minDist {
+				minDist = dist
+				speed = math.Hypot(waypoint.Velocities[0], waypoint.Velocities[1])
+			}
+		}
+		return minDist, speed
+	}
+
+	t.Run("blended corner", func(t *testing.T) {
+		opts := NewTimingOptions()
+		opts.MaxDeviation = 0.1
+		timed, err := TimeParameterizeInputs(waypoints, limits, opts)
+		test.That(t, err, test.ShouldBeNil)
+		checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+		dist, speed := closestToCorner(timed)
+		test.That(t, dist, test.ShouldBeLessThanOrEqualTo, 0.1+1e-6)
+		test.That(t, speed, test.ShouldBeGreaterThan, 0.1)
+	})
+
+	t.Run("stop at corner", func(t *testing.T) {
+		opts := NewTimingOptions()
+		opts.MaxDeviation = 0
+		timed, err := TimeParameterizeInputs(waypoints, limits, opts)
+		test.That(t, err, test.ShouldBeNil)
+		checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+		dist, speed := closestToCorner(timed)
+		test.That(t, dist, test.ShouldBeLessThan, 1e-3)
+		test.That(t, speed, test.ShouldBeLessThan, 0.05)
+		// Two rest-to-rest moves of length 1 which never reach full speed
+		test.That(t, timed[len(timed)-1].Time.Seconds(), test.ShouldAlmostEqual, 4, 0.05)
+	})
+
+	t.Run("reversal", func(t *testing.T) {
+		opts := NewTimingOptions()
+		timed, err := TimeParameterizeInputs(
+			floatWaypoints([]float64{0, 0}, []float64{1, 0}, []float64{0, 0}), limits, opts,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+		test.That(t, timed[len(timed)-1].Time.Seconds(), test.ShouldAlmostEqual, 4, 0.05)
+	})
+}
+
+func TestTimeParameterizeJerk(t *testing.T) {
+	limits := []frame.KinematicLimit{{Velocity: 1, Acceleration: 1, Jerk: 2}}
+	opts := NewTimingOptions()
+	timed, err := TimeParameterizeInputs(floatWaypoints([]float64{0}, []float64{2}), limits, opts)
+	test.That(t, err, test.ShouldBeNil)
+	checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+	test.That(t, timed[len(timed)-1].Time.Seconds(), test.ShouldBeGreaterThan, 3)
+	test.That(t, timed[len(timed)-1].Inputs[0].Value, test.ShouldAlmostEqual, 2)
+	test.That(t, timed[len(timed)-1].Velocities[0], test.ShouldEqual, 0)
+
+	// smoothing only changes the timing, so the trajectory stays on the path through the corner
+	limits = []frame.KinematicLimit{{Velocity: 1, Acceleration: 1, Jerk: 2}, {Velocity: 1, Acceleration: 1, Jerk: 2}}
+	opts.MaxDeviation = 0
+	timed, err = TimeParameterizeInputs(floatWaypoints([]float64{0, 0}, []float64{1, 0}, []float64{1, 1}), limits, opts)
+	test.That(t, err, test.ShouldBeNil)
+	checkTimedLimits(t, timed, limits, opts.SamplePeriod)
+	for _, waypoint := range timed {
+		x, y := waypoint.Inputs[0].Value, waypoint.Inputs[1].Value
+		test.That(t, math.Min(math.Abs(y), math.Abs(x-1)), test.ShouldBeLessThan, 1e-9)
+	}
+	test.That(t, timed[len(timed)-1].Inputs[0].Value, test.ShouldAlmostEqual, 1)
+	test.That(t, timed[len(timed)-1].Inputs[1].Value, test.ShouldAlmostEqual, 1)
+}
+
+func TestTimeParameterizeErrors(t *testing.T) {
+	_, err := TimeParameterizeInputs(floatWaypoints([]float64{0}, []float64{1}), []frame.KinematicLimit{{}}, nil)
+	test.That(t, err, test.ShouldBeError, errNoTimingLimits)
+
+	// Defaults fill in missing limits
+	opts := NewTimingOptions()
+	opts.DefaultVelocity = 1
+	_, err = TimeParameterizeInputs(floatWaypoints([]float64{0}, []float64{1}), []frame.KinematicLimit{{}}, opts)
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = TimeParameterizeInputs(floatWaypoints([]float64{0, 0}), []frame.KinematicLimit{{}}, opts)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	opts.SamplePeriod = 0
+	_, err = TimeParameterizeInputs(floatWaypoints([]float64{0}, []float64{1}), []frame.KinematicLimit{{}}, opts)
+	test.That(t, err, test.ShouldBeError, errTimingBadSampleRate)
+
+	// A single waypoint is a trajectory which is already complete
+	timed, err := TimeParameterizeInputs(floatWaypoints([]float64{3}), []frame.KinematicLimit{{}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(timed), test.ShouldEqual, 1)
+	test.That(t, timed[0].Inputs[0].Value, test.ShouldEqual, 3)
+}
+
+func TestTimeParameterizePlan(t *testing.T) {
+	m, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	modelConfig := m.(*frame.SimpleModel).ModelConfig()
+	for i := range modelConfig.Joints {
+		modelConfig.Joints[i].MaxVel = 90
+		modelConfig.Joints[i].MaxAcc = 180
+	}
+	model, err := modelConfig.ParseConfig("xarm")
+	test.That(t, err, test.ShouldBeNil)
+	for _, limit := range frame.KinematicLimits(model) {
+		test.That(t, limit.Velocity, test.ShouldAlmostEqual, math.Pi/2)
+		test.That(t, limit.Acceleration, test.ShouldAlmostEqual, math.Pi)
+	}
+
+	fs := frame.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	gripper, err := frame.NewStaticFrame("gripper", spatialmath.NewZeroPose())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripper, model), test.ShouldBeNil)
+
+	traj := Trajectory{
+		{model.Name(): frame.FloatsToInputs([]float64{0, 0, 0, 0, 0, 0}), gripper.Name(): {}},
+		{model.Name(): frame.FloatsToInputs([]float64{0.5, 0.2, 0, 0, 0, 0}), gripper.Name(): {}},
+		{model.Name(): frame.FloatsToInputs([]float64{0.5, 0.2, -0.3, 0, 0.1, 0}), gripper.Name(): {}},
+	}
+	request := &PlanRequest{
+		FrameSystem: fs,
+		Options:     map[string]interface{}{"time_parameterize": true, "timing_sample_period": 0.02},
+	}
+	plan, err := timeParameterizeIfRequested(request, NewSimplePlan(nil, traj))
+	test.That(t, err, test.ShouldBeNil)
+	timedPlan, ok := plan.(TimedPlan)
+	test.That(t, ok, test.ShouldBeTrue)
+	timedTraj := timedPlan.TimedTrajectory()
+	test.That(t, len(timedTraj), test.ShouldBeGreaterThan, 2)
+	test.That(t, timedTraj[1].Time.Seconds(), test.ShouldAlmostEqual, 0.02)
+
+	// The timed trajectory starts and ends where the untimed trajectory does, and includes frames without inputs
+	test.That(t, len(timedTraj[0].Positions[gripper.Name()]), test.ShouldEqual, 0)
+	first, last := timedTraj[0].Positions[model.Name()], timedTraj[len(timedTraj)-1].Positions[model.Name()]
+	for i := range first {
+		test.That(t, first[i].Value, test.ShouldAlmostEqual, traj[0][model.Name()][i].Value)
+		test.That(t, last[i].Value, test.ShouldAlmostEqual, traj[len(traj)-1][model.Name()][i].Value)
+	}
+	waypoints, err := timedTraj.GetFrameWaypoints(model.Name())
+	test.That(t, err, test.ShouldBeNil)
+	checkTimedLimits(t, waypoints, frame.KinematicLimits(model), 0.02)
+	_, err = timedTraj.GetFrameWaypoints("missing")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// Offsetting the plan preserves its timing
+	offset, ok := OffsetPlan(plan, spatialmath.NewPoseFromPoint(r3.Vector{X: 1})).(TimedPlan)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, offset.TimedTrajectory().Duration(), test.ShouldEqual, timedTraj.Duration())
+
+	// Without the option, the plan is returned unchanged
+	request.Options = map[string]interface{}{}
+	plan, err = timeParameterizeIfRequested(request, NewSimplePlan(nil, traj))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, plan.(TimedPlan).TimedTrajectory(), test.ShouldBeNil)
+}
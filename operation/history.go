@@ -0,0 +1,308 @@
+package operation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+)
+
+// The outcomes of a finished operation.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeCanceled  = "canceled"
+)
+
+// A Record describes a finished operation.
+type Record struct {
+	ID        uuid.UUID `json:"id"`
+	SessionID uuid.UUID `json:"session_id"`
+	Method    string    `json:"method"`
+	// Arguments summarizes the arguments the operation was called with.
+	Arguments string `json:"arguments,omitempty"`
+	// Caller is the authenticated entity that called the operation, if any.
+	Caller  string    `json:"caller,omitempty"`
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Duration returns how long the operation ran for.
+func (r Record) Duration() time.Duration {
+	return r.Ended.Sub(r.Started)
+}
+
+const (
+	defaultHistoryMaxRecords = 1000
+	// defaultHistoryMinDuration keeps the calls made many times a second, such as those reading sensors, from
+	// filling the history.
+	defaultHistoryMinDuration = time.Second
+	// maxArgumentsSummaryLength bounds the length of the summary of an operation's arguments.
+	maxArgumentsSummaryLength = 256
+)
+
+// methodPrefixesWithSecretArguments are the methods whose arguments hold credentials and are never summarized.
+var methodPrefixesWithSecretArguments = [...]string{
+	"/proto.rpc.v1.AuthService",
+	"/proto.rpc.v1.ExternalAuthService",
+}
+
+// HistoryConfig configures the history of finished operations a robot keeps.
+//
+//	"operation_history": {
+//		"path": "/var/log/viam/operations.jsonl",
+//		"max_records": 5000,
+//		"min_duration": "100ms",
+//		"max_size_mb": 10,
+//		"max_backups": 5
+//	}
+type HistoryConfig struct {
+	// Path is the file the history is persisted to as JSON lines. The robot defaults this to a file under
+	// ~/.viam.
+	Path string `json:"path,omitempty"`
+	// MaxRecords is the number of records kept in memory and loaded from the file at startup. Defaults to 1000.
+	MaxRecords int `json:"max_records,omitempty"`
+	// MinDuration is how long an operation must run for to be recorded unless it fails or is canceled, e.g:
+	// "100ms". Defaults to 1s; "0s" records all operations.
+	MinDuration string `json:"min_duration,omitempty"`
+	// MaxSizeMB is the size the file is rotated at. Zero disables rotation.
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+	// MaxBackups is the number of rotated files kept. Zero keeps all of them.
+	MaxBackups int `json:"max_backups,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *HistoryConfig) Validate(path string) error {
+	if cfg.MaxRecords < 0 {
+		return fmt.Errorf("%s: max_records may not be negative", path)
+	}
+	if cfg.MaxSizeMB < 0 || cfg.MaxBackups < 0 {
+		return fmt.Errorf("%s: max_size_mb and max_backups may not be negative", path)
+	}
+	if _, err := cfg.minDuration(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func (cfg *HistoryConfig) maxRecords() int {
+	if cfg == nil || cfg.MaxRecords == 0 {
+		return defaultHistoryMaxRecords
+	}
+	return cfg.MaxRecords
+}
+
+func (cfg *HistoryConfig) minDuration() (time.Duration, error) {
+	if cfg == nil || cfg.MinDuration == "" {
+		return defaultHistoryMinDuration, nil
+	}
+	dur, err := time.ParseDuration(cfg.MinDuration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid min_duration %q: %w", cfg.MinDuration, err)
+	}
+	return dur, nil
+}
+
+// A History keeps a bounded number of records of finished operations in memory, and persists them to a rotated
+// file when configured with a path.
+type History struct {
+	minDuration time.Duration
+	file        *logging.FileAppender
+
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+// NewHistory returns a history configured by cfg, which may be nil to keep records in memory only with the default
+// settings. Records already in the configured file are loaded.
+func NewHistory(cfg *HistoryConfig) (*History, error) {
+	minDuration, err := cfg.minDuration()
+	if err != nil {
+		return nil, err
+	}
+	h := &History{
+		minDuration: minDuration,
+		records:     make([]Record, cfg.maxRecords()),
+	}
+	if cfg == nil || cfg.Path == "" {
+		return h, nil
+	}
+	if err := h.load(cfg.Path); err != nil {
+		return nil, err
+	}
+	h.file, err = logging.NewFileAppender(logging.FileAppenderConfig{
+		Path:       cfg.Path,
+		MaxSizeMB:  cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// load reads the records persisted to the file at path, if it exists.
+func (h *History) load(path string) error {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	//nolint:errcheck
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// a partially written line is expected if the process exited while writing
+			continue
+		}
+		h.add(record)
+	}
+	return scanner.Err()
+}
+
+// add keeps the record in memory, replacing the oldest one if full.
+func (h *History) add(record Record) {
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// keeps returns whether the record would be kept, which it is unless it succeeded in less than the configured
+// minimum duration.
+func (h *History) keeps(record Record) bool {
+	return record.Outcome != OutcomeSucceeded || record.Duration() >= h.minDuration
+}
+
+// Add records a finished operation unless it succeeded in less than the configured minimum duration.
+func (h *History) Add(record Record) error {
+	if !h.keeps(record) {
+		return nil
+	}
+	h.mu.Lock()
+	h.add(record)
+	h.mu.Unlock()
+	if h.file == nil {
+		return nil
+	}
+	return h.file.Write(zapcore.Entry{
+		Level:      zapcore.InfoLevel,
+		Time:       record.Ended,
+		LoggerName: "operation_history",
+		Message:    "operation finished",
+	}, record.fields())
+}
+
+// fields returns the record as log fields with the same keys as its JSON encoding, so that it can be read back.
+func (r Record) fields() []zapcore.Field {
+	fields := []zapcore.Field{
+		zap.Stringer("id", r.ID),
+		zap.Stringer("session_id", r.SessionID),
+		zap.String("method", r.Method),
+		zap.Time("started", r.Started.UTC()),
+		zap.Time("ended", r.Ended.UTC()),
+		zap.String("outcome", r.Outcome),
+	}
+	if r.Arguments != "" {
+		fields = append(fields, zap.String("arguments", r.Arguments))
+	}
+	if r.Caller != "" {
+		fields = append(fields, zap.String("caller", r.Caller))
+	}
+	if r.Error != "" {
+		fields = append(fields, zap.String("error", r.Error))
+	}
+	return fields
+}
+
+// Records returns the records kept, oldest first.
+func (h *History) Records() []Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full {
+		return append([]Record(nil), h.records[:h.next]...)
+	}
+	records := make([]Record, 0, len(h.records))
+	records = append(records, h.records[h.next:]...)
+	return append(records, h.records[:h.next]...)
+}
+
+// Close closes the file the history is persisted to.
+func (h *History) Close() error {
+	if h.file == nil {
+		return nil
+	}
+	return h.file.Close()
+}
+
+// outcomeOf returns the outcome of an operation that ended with err.
+func outcomeOf(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSucceeded
+	case errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled:
+		return OutcomeCanceled
+	default:
+		return OutcomeFailed
+	}
+}
+
+// summarizeArguments returns the arguments of a call to method as JSON, truncated to a bounded length.
+func summarizeArguments(method string, args interface{}) string {
+	if args == nil || (reflect.ValueOf(args).Kind() == reflect.Ptr && reflect.ValueOf(args).IsNil()) {
+		return ""
+	}
+	for _, prefix := range methodPrefixesWithSecretArguments {
+		if strings.HasPrefix(method, prefix) {
+			return ""
+		}
+	}
+	var encoded []byte
+	var err error
+	if msg, ok := args.(proto.Message); ok {
+		encoded, err = protojson.Marshal(msg)
+	} else {
+		encoded, err = json.Marshal(args)
+	}
+	if err != nil {
+		return fmt.Sprintf("%T", args)
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, encoded); err == nil {
+		encoded = compacted.Bytes()
+	}
+	summary := string(encoded)
+	if len(summary) > maxArgumentsSummaryLength {
+		summary = strings.ToValidUTF8(summary[:maxArgumentsSummaryLength], "") + "..."
+	}
+	return summary
+}
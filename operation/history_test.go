@@ -0,0 +1,134 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+)
+
+func testRecord(method, outcome string, started time.Time, dur time.Duration) Record {
+	return Record{
+		ID:      uuid.New(),
+		Method:  method,
+		Started: started,
+		Ended:   started.Add(dur),
+		Outcome: outcome,
+	}
+}
+
+func TestHistoryBounded(t *testing.T) {
+	h, err := NewHistory(&HistoryConfig{MaxRecords: 3})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, h.Records(), test.ShouldBeEmpty)
+
+	start := time.Now()
+	for _, method := range []string{"a", "b", "c", "d", "e"} {
+		test.That(t, h.Add(testRecord(method, OutcomeSucceeded, start, time.Second)), test.ShouldBeNil)
+	}
+	var methods []string
+	for _, record := range h.Records() {
+		methods = append(methods, record.Method)
+	}
+	test.That(t, methods, test.ShouldResemble, []string{"c", "d", "e"})
+	test.That(t, h.Close(), test.ShouldBeNil)
+}
+
+func TestHistoryPersisted(t *testing.T) {
+	cfg := &HistoryConfig{Path: filepath.Join(t.TempDir(), "operations", "history.jsonl"), MinDuration: "1s"}
+	h, err := NewHistory(cfg)
+	test.That(t, err, test.ShouldBeNil)
+
+	start := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+	failed := testRecord("/viam.component.arm.v1.ArmService/MoveToPosition", OutcomeFailed, start, time.Millisecond)
+	failed.SessionID = uuid.New()
+	failed.Arguments = `{"name":"arm1"}`
+	failed.Caller = "someone@example.com"
+	failed.Error = "arm is stuck"
+	slow := testRecord("/viam.service.motion.v1.MotionService/Move", OutcomeSucceeded, start, time.Minute)
+	test.That(t, h.Add(failed), test.ShouldBeNil)
+	// succeeded faster than the minimum duration
+	test.That(t, h.Add(testRecord("/viam.component.arm.v1.ArmService/GetEndPosition", OutcomeSucceeded, start, 0)),
+		test.ShouldBeNil)
+	test.That(t, h.Add(slow), test.ShouldBeNil)
+	test.That(t, h.Records(), test.ShouldResemble, []Record{failed, slow})
+	test.That(t, h.Close(), test.ShouldBeNil)
+
+	h, err = NewHistory(cfg)
+	test.That(t, err, test.ShouldBeNil)
+	defer h.Close()
+	test.That(t, h.Records(), test.ShouldResemble, []Record{failed, slow})
+
+	_, err = NewHistory(&HistoryConfig{MinDuration: "soon"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, (&HistoryConfig{MaxRecords: -1}).Validate("operation_history"), test.ShouldNotBeNil)
+	test.That(t, cfg.Validate("operation_history"), test.ShouldBeNil)
+}
+
+func TestManagerHistory(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m := NewManager(logger)
+	defer m.Close()
+
+	call := func(ctx context.Context, method string, req interface{}, err error) {
+		_, _ = m.UnaryServerInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, err
+			})
+	}
+	// by default, only operations that fail or run for long are recorded
+	call(context.Background(), "/viam.component.sensor.v1.SensorService/GetReadings", &commonpb.DoCommandRequest{}, nil)
+	call(context.Background(), "/viam.component.base.v1.BaseService/Stop", nil, errors.New("stuck"))
+	test.That(t, m.History(), test.ShouldHaveLength, 1)
+	test.That(t, m.History()[0].Method, test.ShouldEqual, "/viam.component.base.v1.BaseService/Stop")
+
+	m = NewManager(logger)
+	defer m.Close()
+	test.That(t, m.ConfigureHistory(&HistoryConfig{MinDuration: "0s"}), test.ShouldBeNil)
+	callerCtx := rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: "someone@example.com"})
+	call(callerCtx, "/viam.component.base.v1.BaseService/DoCommand", &commonpb.DoCommandRequest{Name: "base1"}, nil)
+	call(context.Background(), "/viam.component.base.v1.BaseService/MoveStraight", nil, status.Error(codes.Unavailable, "no"))
+	call(context.Background(), "/viam.component.base.v1.BaseService/Spin", nil, context.Canceled)
+	call(context.Background(), "/proto.rpc.v1.AuthService/Authenticate", &commonpb.DoCommandRequest{Name: "secret"}, nil)
+
+	_, done := m.Create(context.Background(), "plan", map[string]interface{}{"steps": strings.Repeat("x", 300)})
+	done()
+
+	records := m.History()
+	test.That(t, records, test.ShouldHaveLength, 5)
+	test.That(t, records[0].Method, test.ShouldEqual, "/viam.component.base.v1.BaseService/DoCommand")
+	test.That(t, records[0].Arguments, test.ShouldEqual, `{"name":"base1"}`)
+	test.That(t, records[0].Caller, test.ShouldEqual, "someone@example.com")
+	test.That(t, records[0].Outcome, test.ShouldEqual, OutcomeSucceeded)
+	test.That(t, records[0].Ended.Before(records[0].Started), test.ShouldBeFalse)
+	test.That(t, records[1].Outcome, test.ShouldEqual, OutcomeFailed)
+	test.That(t, records[1].Error, test.ShouldContainSubstring, "no")
+	test.That(t, records[2].Outcome, test.ShouldEqual, OutcomeCanceled)
+	test.That(t, records[3].Arguments, test.ShouldBeEmpty)
+	test.That(t, records[4].Arguments, test.ShouldStartWith, `{"steps":"xxx`)
+	test.That(t, records[4].Arguments, test.ShouldHaveLength, maxArgumentsSummaryLength+len("..."))
+
+	// records are carried over into a newly configured history
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	test.That(t, m.ConfigureHistory(&HistoryConfig{Path: path, MaxRecords: 2}), test.ShouldBeNil)
+	test.That(t, m.History(), test.ShouldResemble, records[3:])
+	call(context.Background(), "/viam.component.base.v1.BaseService/Stop", nil, errors.New("stuck"))
+
+	reloaded, err := NewHistory(&HistoryConfig{Path: path})
+	test.That(t, err, test.ShouldBeNil)
+	defer reloaded.Close()
+	test.That(t, reloaded.Records(), test.ShouldHaveLength, 1)
+	test.That(t, reloaded.Records()[0].Error, test.ShouldEqual, "stuck")
+}
@@ -3,11 +3,13 @@ package operation
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/session"
@@ -34,6 +36,7 @@ type Operation struct {
 	myManager *Manager
 	cancel    context.CancelFunc
 	labels    []string
+	caller    string
 }
 
 // Cancel cancel the context associated with an operation.
@@ -66,14 +69,30 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 	o.labels = append(o.labels, label)
 }
 
-func (o *Operation) cleanup() {
+// cleanup removes the operation from those running and records it as having ended with err.
+func (o *Operation) cleanup(err error) {
 	o.myManager.remove(o.ID)
+	record := Record{
+		ID:        o.ID,
+		SessionID: o.SessionID,
+		Method:    o.Method,
+		Caller:    o.caller,
+		Started:   o.Started,
+		Ended:     time.Now(),
+		Outcome:   outcomeOf(err),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	o.myManager.record(record, o.Arguments)
 }
 
 // NewManager creates a new manager for holding Operations.
 func NewManager(logger logging.Logger) *Manager {
 	opLogger := logger.Sublogger("operation_manager")
-	return &Manager{ops: map[string]*Operation{}, logger: opLogger}
+	//nolint:errcheck
+	history, _ := NewHistory(nil)
+	return &Manager{ops: map[string]*Operation{}, logger: opLogger, history: history}
 }
 
 // Manager holds Operations.
@@ -81,6 +100,63 @@ type Manager struct {
 	ops    map[string]*Operation
 	lock   sync.Mutex
 	logger logging.Logger
+
+	historyMu     sync.Mutex
+	history       *History
+	historyConfig *HistoryConfig
+}
+
+// record adds the record of an operation called with args to the history. The arguments are only summarized if
+// the record is kept, as encoding them is costly.
+func (m *Manager) record(record Record, args interface{}) {
+	m.historyMu.Lock()
+	history := m.history
+	m.historyMu.Unlock()
+	if !history.keeps(record) {
+		return
+	}
+	record.Arguments = summarizeArguments(record.Method, args)
+	if err := history.Add(record); err != nil {
+		m.logger.Warnw("failed to persist operation history", "error", err)
+	}
+}
+
+// ConfigureHistory replaces the history of finished operations with one configured by cfg, which may be nil to
+// keep records in memory only. Records kept in memory are carried over unless records are loaded from the newly
+// configured file instead.
+func (m *Manager) ConfigureHistory(cfg *HistoryConfig) error {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	if reflect.DeepEqual(cfg, m.historyConfig) {
+		return nil
+	}
+	history, err := NewHistory(cfg)
+	if err != nil {
+		return err
+	}
+	if len(history.Records()) == 0 {
+		for _, record := range m.history.Records() {
+			history.add(record)
+		}
+	}
+	old := m.history
+	m.history = history
+	m.historyConfig = cfg
+	return old.Close()
+}
+
+// History returns the records of finished operations kept, oldest first.
+func (m *Manager) History() []Record {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	return m.history.Records()
+}
+
+// Close closes the file the history of finished operations is persisted to, if any.
+func (m *Manager) Close() error {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	return m.history.Close()
 }
 
 func (m *Manager) remove(id uuid.UUID) {
@@ -122,17 +198,25 @@ func (m *Manager) FindString(id string) *Operation {
 
 // Create puts an operation on this context.
 func (m *Manager) Create(ctx context.Context, method string, args interface{}) (context.Context, func()) {
-	return m.createWithID(ctx, uuid.New(), method, args)
+	ctx, done := m.createWithID(ctx, uuid.New(), method, args)
+	return ctx, func() { done(nil) }
 }
 
-func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string, args interface{}) (context.Context, func()) {
+// createWithID puts an operation on this context, returning a func to call with the error the operation ended
+// with, if any, when it is done.
+func (m *Manager) createWithID(
+	ctx context.Context,
+	id uuid.UUID,
+	method string,
+	args interface{},
+) (context.Context, func(error)) {
 	if ctx.Value(opidKey) != nil {
 		panic("operations cannot be nested")
 	}
 
 	for _, val := range methodPrefixesToFilter {
 		if strings.HasPrefix(method, val) {
-			return ctx, func() {}
+			return ctx, func(error) {}
 		}
 	}
 
@@ -153,7 +237,7 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 			method,
 		)
 		ctx = context.WithValue(ctx, opidKey, o)
-		return ctx, func() {}
+		return ctx, func(error) {}
 	}
 
 	op := &Operation{
@@ -166,11 +250,14 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 	if sess, ok := session.FromContext(ctx); ok {
 		op.SessionID = sess.ID()
 	}
+	if entity, ok := rpc.ContextAuthEntity(ctx); ok {
+		op.caller = entity.Entity
+	}
 	ctx = context.WithValue(ctx, opidKey, op)
 	ctx, op.cancel = context.WithCancel(ctx)
 	m.add(op)
 
-	return ctx, func() { op.cleanup() }
+	return ctx, op.cleanup
 }
 
 // Get returns the current Operation. This can be nil.
@@ -112,6 +112,6 @@ func TestCreateDuplicate(t *testing.T) {
 	test.That(t, op1, test.ShouldEqual, op3)
 
 	op1.cancel()
-	cleanup()
+	cleanup(nil)
 	test.That(t, op3Ctx.Err(), test.ShouldBeError, context.Canceled)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"go.viam.com/test"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 
 	"go.viam.com/rdk/logging"
@@ -37,3 +38,38 @@ func TestCreateFromIncomingContextWithOpid(t *testing.T) {
 	test.That(t, ops, test.ShouldHaveLength, 1)
 	test.That(t, ops[0].ID.String(), test.ShouldEqual, opid.String())
 }
+
+func TestServerInterceptorsPanic(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m := NewManager(logger)
+
+	test.That(t, func() {
+		//nolint:errcheck
+		m.UnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "fake"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				panic("oops")
+			})
+	}, test.ShouldPanicWith, "oops")
+	test.That(t, m.All(), test.ShouldBeEmpty)
+
+	test.That(t, func() {
+		//nolint:errcheck
+		m.StreamServerInterceptor(nil, &fakeServerStream{}, &grpc.StreamServerInfo{FullMethod: "fake"},
+			func(srv interface{}, stream grpc.ServerStream) error {
+				panic("oops")
+			})
+	}, test.ShouldPanicWith, "oops")
+	test.That(t, m.All(), test.ShouldBeEmpty)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeServerStream) SetHeader(metadata.MD) error {
+	return nil
+}
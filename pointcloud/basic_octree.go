@@ -194,8 +194,8 @@ func (octree *BasicOctree) Transform(pose spatialmath.Pose) spatialmath.Geometry
 
 // ToProtobuf converts the octree to a Geometry proto message.
 // TODO (RSDK-3743): Implement BasicOctree Geometry functions.
-func (octree *BasicOctree) ToProtobuf() *commonpb.Geometry {
-	return nil
+func (octree *BasicOctree) ToProtobuf() (*commonpb.Geometry, error) {
+	return nil, nil
 }
 
 // CollidesWithGeometry will return whether a given geometry is in collision with a given point.
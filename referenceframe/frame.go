@@ -29,6 +29,23 @@ type Limit struct {
 	Max float64
 }
 
+// KinematicLimit represents the velocity, acceleration and jerk limits of a single degree of freedom, in units of its Input per
+// second, per second squared, and per second cubed respectively. A value of zero means that quantity is not limited.
+type KinematicLimit struct {
+	Velocity     float64
+	Acceleration float64
+	Jerk         float64
+}
+
+// KinematicLimits returns the kinematic limits of each degree of freedom of a frame, in the same order as DoF(). Frames which do not
+// carry kinematic limit information return zero-valued (unlimited) limits.
+func KinematicLimits(f Frame) []KinematicLimit {
+	if model, ok := f.(*SimpleModel); ok {
+		return model.KinematicLimits()
+	}
+	return make([]KinematicLimit, len(f.DoF()))
+}
+
 // RestrictedRandomFrameInputs will produce a list of valid, in-bounds inputs for the frame.
 // The range of selection is restricted to `restrictionPercent` percent of the limits, and the
 // selection frame is centered at reference.
@@ -32,6 +32,9 @@ type JointConfig struct {
 	Axis     spatial.AxisConfig      `json:"axis"`
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
+	MaxVel   float64                 `json:"max_vel,omitempty"`  // in mm/s or degs/s
+	MaxAcc   float64                 `json:"max_acc,omitempty"`  // in mm/s^2 or degs/s^2
+	MaxJerk  float64                 `json:"max_jerk,omitempty"` // in mm/s^3 or degs/s^3
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
 }
 
@@ -42,8 +45,11 @@ type DHParamConfig struct {
 	A        float64                 `json:"a"`
 	D        float64                 `json:"d"`
 	Alpha    float64                 `json:"alpha"`
-	Max      float64                 `json:"max"` // in mm or degs
-	Min      float64                 `json:"min"` // in mm or degs
+	Max      float64                 `json:"max"`                // in mm or degs
+	Min      float64                 `json:"min"`                // in mm or degs
+	MaxVel   float64                 `json:"max_vel,omitempty"`  // in degs/s
+	MaxAcc   float64                 `json:"max_acc,omitempty"`  // in degs/s^2
+	MaxJerk  float64                 `json:"max_jerk,omitempty"` // in degs/s^3
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"`
 }
 
@@ -114,6 +120,27 @@ func (cfg *JointConfig) ToFrame() (Frame, error) {
 	}
 }
 
+// KinematicLimit returns the velocity, acceleration and jerk limits of the joint in the units of its Input.
+func (cfg *JointConfig) KinematicLimit() KinematicLimit {
+	if cfg.Type == PrismaticJoint {
+		return KinematicLimit{Velocity: cfg.MaxVel, Acceleration: cfg.MaxAcc, Jerk: cfg.MaxJerk}
+	}
+	return KinematicLimit{
+		Velocity:     utils.DegToRad(cfg.MaxVel),
+		Acceleration: utils.DegToRad(cfg.MaxAcc),
+		Jerk:         utils.DegToRad(cfg.MaxJerk),
+	}
+}
+
+// KinematicLimit returns the velocity, acceleration and jerk limits of the revolute joint described by the DH parameters, in radians.
+func (cfg *DHParamConfig) KinematicLimit() KinematicLimit {
+	return KinematicLimit{
+		Velocity:     utils.DegToRad(cfg.MaxVel),
+		Acceleration: utils.DegToRad(cfg.MaxAcc),
+		Jerk:         utils.DegToRad(cfg.MaxJerk),
+	}
+}
+
 // ToDHFrames converts a DHParamConfig into a joint frame and a link frame.
 func (cfg *DHParamConfig) ToDHFrames() (Frame, Frame, error) {
 	jointID := cfg.ID + "_j"
@@ -185,6 +185,27 @@ func (m *SimpleModel) DoF() []Limit {
 	return limits
 }
 
+// KinematicLimits returns the velocity, acceleration and jerk limits of each degree of freedom of the model, in the same order as DoF().
+// Limits are read from the model config; joints without configured limits are reported as unlimited.
+func (m *SimpleModel) KinematicLimits() []KinematicLimit {
+	byFrame := map[string]KinematicLimit{}
+	if m.modelConfig != nil {
+		for i := range m.modelConfig.Joints {
+			byFrame[m.modelConfig.Joints[i].ID] = m.modelConfig.Joints[i].KinematicLimit()
+		}
+		for i := range m.modelConfig.DHParams {
+			byFrame[m.modelConfig.DHParams[i].ID+"_j"] = m.modelConfig.DHParams[i].KinematicLimit()
+		}
+	}
+	limits := make([]KinematicLimit, 0, len(m.OrdTransforms))
+	for _, transform := range m.OrdTransforms {
+		for range transform.DoF() {
+			limits = append(limits, byFrame[transform.Name()])
+		}
+	}
+	return limits
+}
+
 // MarshalJSON serializes a Model.
 func (m *SimpleModel) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m.modelConfig)
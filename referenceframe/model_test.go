@@ -140,3 +140,36 @@ func Test2DMobileModelFrame(t *testing.T) {
 	limit := frame.DoF()
 	test.That(t, limit[0], test.ShouldResemble, expLimit[0])
 }
+
+func TestKinematicLimits(t *testing.T) {
+	jsonData := []byte(`{
+		"name": "limited",
+		"links": [{"id": "base_link", "parent": "world", "translation": {"x": 0, "y": 0, "z": 10}}],
+		"joints": [
+			{"id": "spin", "type": "revolute", "parent": "base_link", "axis": {"z": 1}, "min": -180, "max": 180,
+			 "max_vel": 90, "max_acc": 180, "max_jerk": 360},
+			{"id": "slide", "type": "prismatic", "parent": "spin", "axis": {"x": 1}, "min": 0, "max": 500, "max_vel": 100},
+			{"id": "free", "type": "revolute", "parent": "slide", "axis": {"x": 1}, "min": -90, "max": 90}
+		]
+	}`)
+	m, err := UnmarshalModelJSON(jsonData, "")
+	test.That(t, err, test.ShouldBeNil)
+	limits := KinematicLimits(m)
+	test.That(t, len(limits), test.ShouldEqual, len(m.DoF()))
+
+	// Limits are reported in the same order as DoF, and in units of Input
+	byLimit := map[float64]KinematicLimit{}
+	for i, dof := range m.DoF() {
+		byLimit[dof.Max] = limits[i]
+	}
+	test.That(t, byLimit[math.Pi].Velocity, test.ShouldAlmostEqual, math.Pi/2)
+	test.That(t, byLimit[math.Pi].Acceleration, test.ShouldAlmostEqual, math.Pi)
+	test.That(t, byLimit[math.Pi].Jerk, test.ShouldAlmostEqual, 2*math.Pi)
+	test.That(t, byLimit[500], test.ShouldResemble, KinematicLimit{Velocity: 100})
+	test.That(t, byLimit[math.Pi/2], test.ShouldResemble, KinematicLimit{})
+
+	// Frames without kinematic information are unlimited
+	frame, err := NewRotationalFrame("foo", spatial.R4AA{RZ: 1}, Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, KinematicLimits(frame), test.ShouldResemble, []KinematicLimit{{}})
+}
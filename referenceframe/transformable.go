@@ -146,7 +146,10 @@ func LinkInFrameToTransformProtobuf(framedLink *LinkInFrame) (*commonpb.Transfor
 		PoseInObserverFrame: PoseInFrameToProtobuf(framedLink.PoseInFrame),
 	}
 	if framedLink.geometry != nil {
-		tform.PhysicalObject = framedLink.geometry.ToProtobuf()
+		var err error
+		if tform.PhysicalObject, err = framedLink.geometry.ToProtobuf(); err != nil {
+			return nil, err
+		}
 	}
 	return tform, nil
 }
@@ -262,11 +265,15 @@ func (gF *GeometriesInFrame) Transform(tf *PoseInFrame) Transformable {
 }
 
 // GeometriesInFrameToProtobuf converts a GeometriesInFrame struct to a GeometriesInFrame message as specified in common.proto.
-func GeometriesInFrameToProtobuf(framedGeometries *GeometriesInFrame) *commonpb.GeometriesInFrame {
+func GeometriesInFrameToProtobuf(framedGeometries *GeometriesInFrame) (*commonpb.GeometriesInFrame, error) {
+	geometries, err := spatialmath.NewGeometriesToProto(framedGeometries.Geometries())
+	if err != nil {
+		return nil, err
+	}
 	return &commonpb.GeometriesInFrame{
 		ReferenceFrame: framedGeometries.frame,
-		Geometries:     spatialmath.NewGeometriesToProto(framedGeometries.Geometries()),
-	}
+		Geometries:     geometries,
+	}, nil
 }
 
 // ProtobufToGeometriesInFrame converts a GeometriesInFrame message as specified in common.proto to a GeometriesInFrame struct.
@@ -36,7 +36,9 @@ func TestGeometriesInFrame(t *testing.T) {
 	gF := NewGeometriesInFrame("frame", geometryList)
 	test.That(t, gF.Parent(), test.ShouldEqual, "frame")
 	test.That(t, spatial.GeometriesAlmostEqual(one, gF.GeometryByName("one")), test.ShouldBeTrue)
-	convertedGF, err := ProtobufToGeometriesInFrame(GeometriesInFrameToProtobuf(gF))
+	gFProto, err := GeometriesInFrameToProtobuf(gF)
+	test.That(t, err, test.ShouldBeNil)
+	convertedGF, err := ProtobufToGeometriesInFrame(gFProto)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, gF.Parent(), test.ShouldEqual, convertedGF.Parent())
 	test.That(t, spatial.GeometriesAlmostEqual(one, convertedGF.GeometryByName("one")), test.ShouldBeTrue)
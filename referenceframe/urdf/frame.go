@@ -11,9 +11,10 @@ type frame struct {
 }
 
 type limit struct {
-	XMLName xml.Name `xml:"limit"`
-	Lower   float64  `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
-	Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
+	XMLName  xml.Name `xml:"limit"`
+	Lower    float64  `xml:"lower,attr"`              // translation limits are in meters, revolute limits are in radians
+	Upper    float64  `xml:"upper,attr"`              // translation limits are in meters, revolute limits are in radians
+	Velocity float64  `xml:"velocity,attr,omitempty"` // in meters/sec or radians/sec
 }
 
 type axis struct {
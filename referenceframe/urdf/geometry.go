@@ -3,6 +3,9 @@ package urdf
 import (
 	"encoding/xml"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
@@ -13,14 +16,20 @@ import (
 
 var errGeometryTypeUnsupported = errors.New("unsupported Geometry type")
 
+// rosPackagePathEnv is the environment variable listing directories in which ROS packages referenced by package:// URIs may be found.
+const rosPackagePathEnv = "ROS_PACKAGE_PATH"
+
 // collision is a struct which details the XML used in a URDF collision geometry.
 type collision struct {
 	XMLName  xml.Name `xml:"collision"`
 	Origin   *pose    `xml:"origin"`
 	Geometry struct {
-		XMLName xml.Name `xml:"geometry"`
-		Box     *box     `xml:"box,omitempty"`
-		Sphere  *sphere  `xml:"sphere,omitempty"`
+		XMLName  xml.Name  `xml:"geometry"`
+		Box      *box      `xml:"box,omitempty"`
+		Sphere   *sphere   `xml:"sphere,omitempty"`
+		Cylinder *cylinder `xml:"cylinder,omitempty"`
+		Capsule  *cylinder `xml:"capsule,omitempty"`
+		Mesh     *mesh     `xml:"mesh,omitempty"`
 	} `xml:"geometry"`
 }
 
@@ -34,7 +43,21 @@ type sphere struct {
 	Radius  float64  `xml:"radius,attr"` // in meters
 }
 
-func newCollision(g spatialmath.Geometry) (*collision, error) {
+// cylinder is used for both cylinder and capsule elements. In both cases the length excludes any hemispherical end caps.
+type cylinder struct {
+	Radius float64 `xml:"radius,attr"` // in meters
+	Length float64 `xml:"length,attr"` // in meters
+}
+
+type mesh struct {
+	XMLName  xml.Name `xml:"mesh"`
+	Filename string   `xml:"filename,attr"`        // path, file:// or package:// URI of an STL, OBJ or PLY file
+	Scale    string   `xml:"scale,attr,omitempty"` // "x y z" format, unitless
+}
+
+// newCollision converts the geometry to a collision element of a URDF to be written to dir. Mesh files are referenced relative to
+// dir, so that the URDF and its meshes may be moved together.
+func newCollision(g spatialmath.Geometry, dir string) (*collision, error) {
 	cfg, err := spatialmath.NewGeometryConfig(g)
 	if err != nil {
 		return nil, err
@@ -48,16 +71,41 @@ func newCollision(g spatialmath.Geometry) (*collision, error) {
 		urdf.Geometry.Box = &box{Size: fmt.Sprintf("%f %f %f", utils.MMToMeters(cfg.X), utils.MMToMeters(cfg.Y), utils.MMToMeters(cfg.Z))}
 	case spatialmath.SphereType:
 		urdf.Geometry.Sphere = &sphere{Radius: utils.MMToMeters(cfg.R)}
+	case spatialmath.CapsuleType:
+		// URDF has no capsule type, so capsules are written as the cylinder which becomes the same capsule when read back in
+		urdf.Geometry.Cylinder = &cylinder{Radius: utils.MMToMeters(cfg.R), Length: utils.MMToMeters(cfg.L - 2*cfg.R)}
+	case spatialmath.MeshType:
+		if cfg.MeshFilePath == "" {
+			return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, "mesh not loaded from a file")
+		}
+		scale := r3.Vector{X: 1, Y: 1, Z: 1}
+		if cfg.MeshScale != nil {
+			scale = *cfg.MeshScale
+		}
+		// vertices are scaled to millimeters when read, which is not part of the URDF scale
+		filename, err := relativeMeshPath(cfg.MeshFilePath, dir)
+		if err != nil {
+			return nil, err
+		}
+		urdf.Geometry.Mesh = &mesh{
+			Filename: filename,
+			Scale:    fmt.Sprintf("%g %g %g", utils.MMToMeters(scale.X), utils.MMToMeters(scale.Y), utils.MMToMeters(scale.Z)),
+		}
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", cfg.Type))
 	}
 	return urdf, nil
 }
 
-func (c *collision) toGeometry() (spatialmath.Geometry, error) {
+// toGeometry converts the collision element to a Geometry. Relative mesh paths are resolved against dir, which should be the
+// directory containing the URDF file.
+func (c *collision) toGeometry(dir string) (spatialmath.Geometry, error) {
 	switch {
 	case c.Geometry.Box != nil:
 		dims := spaceDelimitedStringToFloatSlice(c.Geometry.Box.Size)
+		if len(dims) != 3 {
+			return nil, errors.Errorf("couldn't parse xml: box size %q must have 3 dimensions", c.Geometry.Box.Size)
+		}
 		return spatialmath.NewBox(
 			c.Origin.Parse(),
 			r3.Vector{X: utils.MetersToMM(dims[0]), Y: utils.MetersToMM(dims[1]), Z: utils.MetersToMM(dims[2])},
@@ -65,7 +113,98 @@ func (c *collision) toGeometry() (spatialmath.Geometry, error) {
 		)
 	case c.Geometry.Sphere != nil:
 		return spatialmath.NewSphere(c.Origin.Parse(), utils.MetersToMM(c.Geometry.Sphere.Radius), "")
+	case c.Geometry.Cylinder != nil:
+		// Cylinders are approximated by the smallest capsule that encloses them, whose caps extend past the cylinder's flat ends
+		return c.Geometry.Cylinder.toCapsule(c.Origin.Parse())
+	case c.Geometry.Capsule != nil:
+		return c.Geometry.Capsule.toCapsule(c.Origin.Parse())
+	case c.Geometry.Mesh != nil:
+		return c.Geometry.Mesh.toGeometry(c.Origin.Parse(), dir)
 	default:
 		return nil, errors.New("couldn't parse xml: no geometry defined")
 	}
 }
+
+func (c *cylinder) toCapsule(offset spatialmath.Pose) (spatialmath.Geometry, error) {
+	radius := utils.MetersToMM(c.Radius)
+	return spatialmath.NewCapsule(offset, radius, utils.MetersToMM(c.Length)+2*radius, "")
+}
+
+func (m *mesh) toGeometry(offset spatialmath.Pose, dir string) (spatialmath.Geometry, error) {
+	path, err := resolveMeshPath(m.Filename, dir)
+	if err != nil {
+		return nil, err
+	}
+	g, err := spatialmath.NewMeshFromFile(offset, path, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't load mesh %q", m.Filename)
+	}
+	scale := r3.Vector{X: 1, Y: 1, Z: 1}
+	if m.Scale != "" {
+		s := spaceDelimitedStringToFloatSlice(m.Scale)
+		if len(s) != 3 {
+			return nil, errors.Errorf("couldn't parse xml: mesh scale %q must have 3 dimensions", m.Scale)
+		}
+		scale = r3.Vector{X: s[0], Y: s[1], Z: s[2]}
+	}
+	// mesh files referenced by URDFs are in meters
+	return spatialmath.ScaleMesh(g, r3.Vector{X: utils.MetersToMM(scale.X), Y: utils.MetersToMM(scale.Y), Z: utils.MetersToMM(scale.Z)})
+}
+
+// relativeMeshPath returns the filename of a mesh at path for a URDF in dir, which is the path relative to dir if there is one.
+func relativeMeshPath(path, dir string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		// e.g. the mesh is on another volume than the URDF
+		return path, nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// resolveMeshPath converts the filename of a URDF mesh element into a path on disk.
+// package://<package>/<path> URIs are searched for in the directories listed in ROS_PACKAGE_PATH, and in the directory containing
+// the URDF and each of its parents, as packages typically keep their URDFs and meshes in sibling directories.
+func resolveMeshPath(filename, dir string) (string, error) {
+	switch {
+	case strings.HasPrefix(filename, "file://"):
+		return strings.TrimPrefix(filename, "file://"), nil
+	case strings.HasPrefix(filename, "package://"):
+		pkg, rel, found := strings.Cut(strings.TrimPrefix(filename, "package://"), "/")
+		if !found {
+			return "", errors.Errorf("malformed package URI %q", filename)
+		}
+		var candidates []string
+		for _, root := range filepath.SplitList(os.Getenv(rosPackagePathEnv)) {
+			candidates = append(candidates, filepath.Join(root, pkg, rel))
+		}
+		if dir != "" {
+			for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+				if filepath.Base(d) == pkg {
+					candidates = append(candidates, filepath.Join(d, rel))
+				}
+				candidates = append(candidates, filepath.Join(d, pkg, rel))
+				if d == filepath.Dir(d) {
+					break
+				}
+			}
+		}
+		for _, candidate := range candidates {
+			if _, err := os.Stat(candidate); err == nil {
+				return candidate, nil
+			}
+		}
+		return "", errors.Errorf("couldn't find %q, check that the package is in %s", filename, rosPackagePathEnv)
+	case filepath.IsAbs(filename) || dir == "":
+		return filename, nil
+	default:
+		return filepath.Join(dir, filename), nil
+	}
+}
@@ -2,12 +2,14 @@ package urdf
 
 import (
 	"encoding/xml"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
 )
 
 func TestGeometrySerialization(t *testing.T) {
@@ -21,6 +23,16 @@ func TestGeometrySerialization(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	capsule, err := spatialmath.NewCapsule(spatialmath.NewZeroPose(), 1, 10, "")
 	test.That(t, err, test.ShouldBeNil)
+	mesh, err := spatialmath.NewMeshFromFile(
+		spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}),
+		utils.ResolveFile("referenceframe/urdf/testfiles/meshes/cube.stl"),
+		"",
+	)
+	test.That(t, err, test.ShouldBeNil)
+	mesh, err = spatialmath.ScaleMesh(mesh, r3.Vector{X: 1000, Y: 1000, Z: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	inlineMesh, err := spatialmath.NewMeshFromOBJ(spatialmath.NewZeroPose(), []byte("v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\n"), "")
+	test.That(t, err, test.ShouldBeNil)
 
 	testCases := []struct {
 		name    string
@@ -29,12 +41,17 @@ func TestGeometrySerialization(t *testing.T) {
 	}{
 		{"box", box, true},
 		{"sphere", sphere, true},
-		{"capsule", capsule, false},
+		{"capsule", capsule, true},
+		{"mesh", mesh, true},
+		{"mesh without file", inlineMesh, false},
+		{"point", spatialmath.NewPoint(r3.Vector{}, ""), false},
 	}
 
+	// the URDF is written to the directory containing the test files
+	dir := utils.ResolveFile("referenceframe/urdf/testfiles")
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			urdf, err := newCollision(tc.g)
+			urdf, err := newCollision(tc.g, dir)
 			if !tc.success {
 				test.That(t, err.Error(), test.ShouldContainSubstring, errGeometryTypeUnsupported.Error())
 				return
@@ -44,9 +61,66 @@ func TestGeometrySerialization(t *testing.T) {
 			test.That(t, err, test.ShouldBeNil)
 			var urdf2 collision
 			xml.Unmarshal(bytes, &urdf2)
-			g2, err := urdf2.toGeometry()
+			if urdf2.Geometry.Mesh != nil {
+				test.That(t, urdf2.Geometry.Mesh.Filename, test.ShouldEqual, "meshes/cube.stl")
+			}
+			g2, err := urdf2.toGeometry(dir)
 			test.That(t, err, test.ShouldBeNil)
 			test.That(t, spatialmath.GeometriesAlmostEqual(tc.g, g2), test.ShouldBeTrue)
 		})
 	}
 }
+
+func TestCylinderToCapsule(t *testing.T) {
+	var c collision
+	err := xml.Unmarshal([]byte(`<collision><geometry><cylinder radius="0.1" length="0.5"/></geometry></collision>`), &c)
+	test.That(t, err, test.ShouldBeNil)
+	g, err := c.toGeometry("")
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := spatialmath.NewCapsule(spatialmath.NewZeroPose(), 100, 700, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.GeometriesAlmostEqual(g, expected), test.ShouldBeTrue)
+}
+
+func TestResolveMeshPath(t *testing.T) {
+	dir := utils.ResolveFile("referenceframe/urdf/testfiles")
+	expected := filepath.Join(dir, "meshes", "cube.stl")
+
+	path, err := resolveMeshPath("meshes/cube.stl", dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path, test.ShouldEqual, expected)
+
+	path, err = resolveMeshPath("file://"+expected, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path, test.ShouldEqual, expected)
+
+	// the package is found as an ancestor of the URDF directory
+	path, err = resolveMeshPath("package://testfiles/meshes/cube.stl", filepath.Join(dir, "meshes"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path, test.ShouldEqual, expected)
+
+	// the package is found through ROS_PACKAGE_PATH
+	t.Setenv(rosPackagePathEnv, filepath.Dir(dir))
+	path, err = resolveMeshPath("package://testfiles/meshes/cube.stl", "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path, test.ShouldEqual, expected)
+
+	_, err = resolveMeshPath("package://missing/meshes/cube.stl", dir)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestRelativeMeshPath(t *testing.T) {
+	dir := utils.ResolveFile("referenceframe/urdf/testfiles")
+	mesh := filepath.Join(dir, "meshes", "cube.stl")
+
+	path, err := relativeMeshPath(mesh, dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path, test.ShouldEqual, "meshes/cube.stl")
+
+	path, err = relativeMeshPath(mesh, filepath.Join(dir, "urdfs"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path, test.ShouldEqual, "../meshes/cube.stl")
+	resolved, err := resolveMeshPath(path, filepath.Join(dir, "urdfs"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resolved, test.ShouldEqual, mesh)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"math"
 	"os"
+	"path/filepath"
 
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
@@ -45,8 +46,9 @@ type joint struct {
 }
 
 // NewModelFromWorldState creates a urdf.Config struct which can be marshalled into xml and will be a
-// valid .urdf file representing the geometries in the given worldstate.
-func NewModelFromWorldState(ws *referenceframe.WorldState, name string) (*ModelConfig, error) {
+// valid .urdf file representing the geometries in the given worldstate. Mesh files are referenced relative to dir, the
+// directory the .urdf file is to be written to.
+func NewModelFromWorldState(ws *referenceframe.WorldState, name, dir string) (*ModelConfig, error) {
 	// the link we initialize this list with represents the world frame
 	links := []link{{Name: referenceframe.World}}
 	joints := make([]joint, 0)
@@ -56,7 +58,7 @@ func NewModelFromWorldState(ws *referenceframe.WorldState, name string) (*ModelC
 		return nil, err
 	}
 	for _, g := range gf.Geometries() {
-		coll, err := newCollision(g)
+		coll, err := newCollision(g, dir)
 		if err != nil {
 			return nil, err
 		}
@@ -81,7 +83,13 @@ func NewModelFromWorldState(ws *referenceframe.WorldState, name string) (*ModelC
 // UnmarshalModelXML will transfer the given URDF XML data into an equivalent ModelConfig. Direct unmarshaling in the
 // same fashion as ModelJSON is not possible, as URDF data will need to be evaluated to accommodate differences
 // between the two kinematics encoding schemes.
+// Relative mesh paths are resolved against the current working directory; use ParseModelXMLFile to resolve them relative to the
+// URDF file instead.
 func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelConfig, error) {
+	return unmarshalModelXML(xmlData, modelName, "")
+}
+
+func unmarshalModelXML(xmlData []byte, modelName, dir string) (*referenceframe.ModelConfig, error) {
 	// Unmarshal into a URDF ModelConfig
 	urdf := &ModelConfig{}
 	err := xml.Unmarshal(xmlData, urdf)
@@ -104,7 +112,7 @@ func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelC
 
 		link := &referenceframe.LinkConfig{ID: linkElem.Name}
 		if len(linkElem.Collision) > 0 {
-			geometry, err := linkElem.Collision[0].toGeometry()
+			geometry, err := linkElem.Collision[0].toGeometry(dir)
 			if err != nil {
 				return nil, err
 			}
@@ -137,10 +145,15 @@ func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelC
 			case referenceframe.ContinuousJoint:
 				thisJoint.Type = referenceframe.RevoluteJoint // Currently, we treate a continuous joint as a special case of a revolute joint
 				thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
+				if jointElem.Limit != nil {
+					thisJoint.MaxVel = utils.RadToDeg(jointElem.Limit.Velocity)
+				}
 			case referenceframe.PrismaticJoint:
 				thisJoint.Min, thisJoint.Max = utils.MetersToMM(jointElem.Limit.Lower), utils.MetersToMM(jointElem.Limit.Upper)
+				thisJoint.MaxVel = utils.MetersToMM(jointElem.Limit.Velocity)
 			case referenceframe.RevoluteJoint:
 				thisJoint.Min, thisJoint.Max = utils.RadToDeg(jointElem.Limit.Lower), utils.RadToDeg(jointElem.Limit.Upper)
+				thisJoint.MaxVel = utils.RadToDeg(jointElem.Limit.Velocity)
 			default:
 				return nil, err
 			}
@@ -227,7 +240,7 @@ func ParseModelXMLFile(filename, modelName string) (referenceframe.Model, error)
 		return nil, errors.Wrap(err, "failed to read URDF file")
 	}
 
-	mc, err := UnmarshalModelXML(xmlData, modelName)
+	mc, err := unmarshalModelXML(xmlData, modelName, filepath.Dir(filename))
 	if err != nil {
 		return nil, err
 	}
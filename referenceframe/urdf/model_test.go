@@ -2,6 +2,7 @@ package urdf
 
 import (
 	"encoding/xml"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -28,6 +29,18 @@ func TestParseURDFFile(t *testing.T) {
 	modelGeo, err := model.Geometries(make([]referenceframe.Input, len(model.DoF())))
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(modelGeo.Geometries()), test.ShouldEqual, 5) // notably we only have 5 geometries for this model
+	for _, limit := range model.KinematicLimits() {
+		test.That(t, limit.Velocity, test.ShouldAlmostEqual, 3.141592)
+	}
+
+	// Test a URDF with cylinder and mesh collision geometries
+	u, err = ParseModelXMLFile(utils.ResolveFile("referenceframe/urdf/testfiles/mesh_arm.urdf"), "")
+	test.That(t, err, test.ShouldBeNil)
+	model, ok = u.(*referenceframe.SimpleModel)
+	test.That(t, ok, test.ShouldBeTrue)
+	modelGeo, err = model.Geometries(make([]referenceframe.Input, len(model.DoF())))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(modelGeo.Geometries()), test.ShouldEqual, 3)
 
 	// Test naming of a URDF to something other than the robot's name element
 	u, err = ParseModelXMLFile(utils.ResolveFile("referenceframe/urdf/testfiles/ur5e.urdf"), "foo")
@@ -69,15 +82,21 @@ func TestWorldStateConversion(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	bar, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 1, Y: 2, Z: 3}, "bar")
 	test.That(t, err, test.ShouldBeNil)
+	dir := utils.ResolveFile("referenceframe/urdf/testfiles")
+	baz, err := spatialmath.NewMeshFromFile(spatialmath.NewZeroPose(), filepath.Join(dir, "meshes", "cube.stl"), "baz")
+	test.That(t, err, test.ShouldBeNil)
 	ws, err := referenceframe.NewWorldState(
-		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{foo, bar})},
+		[]*referenceframe.GeometriesInFrame{
+			referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{foo, bar, baz}),
+		},
 		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 
-	cfg, err := NewModelFromWorldState(ws, "test")
+	// meshes are referenced relative to the directory the URDF is written to
+	cfg, err := NewModelFromWorldState(ws, "test", filepath.Join(dir, "urdfs"))
 	test.That(t, err, test.ShouldBeNil)
 	bytes, err := xml.MarshalIndent(cfg, "", "  ")
 	test.That(t, err, test.ShouldBeNil)
-	_ = bytes
+	test.That(t, string(bytes), test.ShouldContainSubstring, `filename="../meshes/cube.stl"`)
 }
@@ -26,6 +26,10 @@ func newPose(p spatialmath.Pose) *pose {
 }
 
 func (p *pose) Parse() spatialmath.Pose {
+	// the origin element is optional and defaults to the identity
+	if p == nil {
+		return spatialmath.NewZeroPose()
+	}
 	// Offset for the geometry origin from the reference link origin
 	xyz := spaceDelimitedStringToFloatSlice(p.XYZ)
 	rpy := spaceDelimitedStringToFloatSlice(p.RPY)
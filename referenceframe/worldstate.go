@@ -85,12 +85,13 @@ func (ws *WorldState) ToProtobuf() (*commonpb.WorldState, error) {
 		return &commonpb.WorldState{}, nil
 	}
 
-	convertGeometriesToProto := func(allGeometries []*GeometriesInFrame) []*commonpb.GeometriesInFrame {
-		list := make([]*commonpb.GeometriesInFrame, 0, len(allGeometries))
-		for _, geometries := range allGeometries {
-			list = append(list, GeometriesInFrameToProtobuf(geometries))
+	obstacles := make([]*commonpb.GeometriesInFrame, 0, len(ws.obstacles))
+	for _, geometries := range ws.obstacles {
+		geometriesProto, err := GeometriesInFrameToProtobuf(geometries)
+		if err != nil {
+			return nil, err
 		}
-		return list
+		obstacles = append(obstacles, geometriesProto)
 	}
 
 	transforms, err := LinkInFramesToTransformsProtobuf(ws.transforms)
@@ -99,7 +100,7 @@ func (ws *WorldState) ToProtobuf() (*commonpb.WorldState, error) {
 	}
 
 	return &commonpb.WorldState{
-		Obstacles:  convertGeometriesToProto(ws.obstacles),
+		Obstacles:  obstacles,
 		Transforms: transforms,
 	}, nil
 }
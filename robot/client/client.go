@@ -3,6 +3,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -1108,6 +1109,70 @@ func (rc *RobotClient) MachineStatus(ctx context.Context) (robot.MachineStatus,
 	return mStatus, nil
 }
 
+// DoCommand runs a command of the machine's command service, see robot.CommandServiceName.
+func (rc *RobotClient) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	command, err := structpb.NewStruct(cmd)
+	if err != nil {
+		return nil, err
+	}
+	var resp commonpb.DoCommandResponse
+	if err := rc.conn.Invoke(
+		ctx,
+		"/"+robot.CommandServiceName+"/DoCommand",
+		&commonpb.DoCommandRequest{Command: command},
+		&resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.GetResult().AsMap(), nil
+}
+
+// doCommand runs the named command of the machine's command service and decodes its result into result.
+func (rc *RobotClient) doCommand(ctx context.Context, command string, result interface{}) error {
+	resp, err := rc.DoCommand(ctx, map[string]interface{}{robot.CommandKey: command})
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, result)
+}
+
+// ModuleHealth returns the health of the machine's modules.
+func (rc *RobotClient) ModuleHealth(ctx context.Context) ([]config.ModuleHealth, error) {
+	var result struct {
+		Modules []config.ModuleHealth `json:"modules"`
+	}
+	if err := rc.doCommand(ctx, robot.CommandModuleHealth, &result); err != nil {
+		return nil, err
+	}
+	return result.Modules, nil
+}
+
+// Leases returns the leases held by sessions on the machine's resources.
+func (rc *RobotClient) Leases(ctx context.Context) ([]session.Lease, error) {
+	var result struct {
+		Leases []session.Lease `json:"leases"`
+	}
+	if err := rc.doCommand(ctx, robot.CommandLeases, &result); err != nil {
+		return nil, err
+	}
+	return result.Leases, nil
+}
+
+// OperationHistory returns the finished operations the machine keeps in its history, oldest first.
+func (rc *RobotClient) OperationHistory(ctx context.Context) ([]operation.Record, error) {
+	var result struct {
+		History []operation.Record `json:"history"`
+	}
+	if err := rc.doCommand(ctx, robot.CommandOperationHistory, &result); err != nil {
+		return nil, err
+	}
+	return result.History, nil
+}
+
 // Version returns version information about the machine.
 func (rc *RobotClient) Version(ctx context.Context) (robot.VersionResponse, error) {
 	mVersion := robot.VersionResponse{}
@@ -65,6 +65,7 @@ import (
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/robot/server"
+	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils"
 	"go.viam.com/rdk/testutils/inject"
@@ -2245,3 +2246,135 @@ func TestVersion(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, md, test.ShouldResemble, version)
 }
+
+func TestModuleHealth(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	modules := []config.ModuleHealth{
+		{Name: "healthy", State: config.ModuleStateRunning},
+		{
+			Name:            "crashing",
+			State:           config.ModuleStateFailed,
+			Crashes:         3,
+			RestartAttempts: 3,
+			LastExitCode:    2,
+			LastCrash:       time.Unix(1700000100, 0).UTC(),
+			LastOutput:      []string{"panic: oops"},
+		},
+	}
+	injectRobot := &inject.Robot{
+		ResourceNamesFunc:   func() []resource.Name { return nil },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		MachineStatusFunc: func(ctx context.Context) (robot.MachineStatus, error) {
+			return robot.MachineStatus{Modules: modules}, nil
+		},
+	}
+	robotServer := server.New(injectRobot)
+	pb.RegisterRobotServiceServer(gServer, robotServer)
+	gServer.RegisterService(&server.CommandServiceDesc, robotServer)
+
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	health, err := client.ModuleHealth(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health, test.ShouldResemble, modules)
+
+	_, err = client.DoCommand(context.Background(), map[string]interface{}{robot.CommandKey: "bad"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
+
+func TestLeases(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	injectRobot := &inject.Robot{
+		ResourceNamesFunc:   func() []resource.Name { return nil },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		LoggerFunc:          func() logging.Logger { return logger },
+	}
+	sm := robot.NewSessionManager(injectRobot, config.DefaultSessionHeartbeatWindow)
+	defer sm.Close()
+	injectRobot.SessMgr = sm
+	robotServer := server.New(injectRobot)
+	pb.RegisterRobotServiceServer(gServer, robotServer)
+	gServer.RegisterService(&server.CommandServiceDesc, robotServer)
+
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	leases, err := client.Leases(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldBeEmpty)
+
+	sess, err := sm.Start(context.Background(), "owner")
+	test.That(t, err, test.ShouldBeNil)
+	lease, err := sm.AcquireLease(context.Background(), sess.ID(), arm.Named("arm1"),
+		session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 3})
+	test.That(t, err, test.ShouldBeNil)
+
+	leases, err = client.Leases(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldHaveLength, 1)
+	test.That(t, leases[0].Resource, test.ShouldResemble, lease.Resource)
+	test.That(t, leases[0].SessionID, test.ShouldEqual, sess.ID())
+	test.That(t, leases[0].Mode, test.ShouldEqual, session.LeaseExclusive)
+	test.That(t, leases[0].Priority, test.ShouldEqual, 3)
+	test.That(t, leases[0].Acquired.Equal(lease.Acquired), test.ShouldBeTrue)
+}
+
+func TestOperationHistory(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	injectRobot := &inject.Robot{
+		ResourceNamesFunc:   func() []resource.Name { return nil },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		LoggerFunc:          func() logging.Logger { return logger },
+	}
+	robotServer := server.New(injectRobot)
+	pb.RegisterRobotServiceServer(gServer, robotServer)
+	gServer.RegisterService(&server.CommandServiceDesc, robotServer)
+
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	ops := injectRobot.OperationManager()
+	test.That(t, ops.ConfigureHistory(&operation.HistoryConfig{MinDuration: "0s"}), test.ShouldBeNil)
+	opCtx, done := ops.Create(context.Background(), "/viam.component.arm.v1.ArmService/MoveToPosition", nil)
+	op := operation.Get(opCtx)
+	done()
+
+	history, err := client.OperationHistory(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, history, test.ShouldHaveLength, 1)
+	test.That(t, history[0].ID, test.ShouldEqual, op.ID)
+	test.That(t, history[0].Method, test.ShouldEqual, "/viam.component.arm.v1.ArmService/MoveToPosition")
+	test.That(t, history[0].Outcome, test.ShouldEqual, operation.OutcomeSucceeded)
+}
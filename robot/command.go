@@ -0,0 +1,21 @@
+package robot
+
+// CommandServiceName is the name of the gRPC service through which a machine answers commands about the parts of it
+// that are not resources, such as its modules. Its only method, DoCommand, takes and returns the same messages as the
+// DoCommand method of resources, with the command to run named by the CommandKey of the request.
+const CommandServiceName = "rdk.robot.v1.CommandService"
+
+// CommandKey is the key of a command service request which names the command to run.
+const CommandKey = "command"
+
+// CommandModuleHealth is the command which returns the config.ModuleHealth of each of the machine's modules,
+// under "modules".
+const CommandModuleHealth = "module_health"
+
+// CommandLeases is the command which returns the session.Lease held on each of the machine's resources, under
+// "leases".
+const CommandLeases = "leases"
+
+// CommandOperationHistory is the command which returns the operation.Record of each finished operation the machine
+// keeps in its history, oldest first, under "history".
+const CommandOperationHistory = "operation_history"
@@ -6,6 +6,7 @@ package robotimpl
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -174,6 +175,7 @@ func (r *localRobot) Close(ctx context.Context) error {
 	if r.webSvc != nil {
 		err = multierr.Combine(err, r.webSvc.Close(ctx))
 	}
+	err = multierr.Combine(err, r.operations.Close())
 	return err
 }
 
@@ -1125,6 +1127,19 @@ func (r *localRobot) discoverRobotInternals(query resource.DiscoveryQuery) (inte
 	}
 }
 
+// configureOperationHistory persists the history of finished operations as configured, to a file under
+// ~/.viam unless a path is given.
+func (r *localRobot) configureOperationHistory(cfg *operation.HistoryConfig) {
+	if cfg != nil && cfg.Path == "" {
+		withPath := *cfg
+		withPath.Path = filepath.Join(config.ViamDotDir, "operations", "history.jsonl")
+		cfg = &withPath
+	}
+	if err := r.operations.ConfigureHistory(cfg); err != nil {
+		r.logger.Errorw("failed to configure operation history", "error", err)
+	}
+}
+
 func dialRobotClient(
 	ctx context.Context,
 	config config.Remote,
@@ -1265,6 +1280,8 @@ func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config,
 		return
 	}
 
+	r.configureOperationHistory(newConfig.OperationHistory)
+
 	revision := diff.NewRevision()
 	for _, res := range diff.UnmodifiedResources {
 		r.manager.updateRevision(res.ResourceName(), revision)
@@ -1423,6 +1440,9 @@ func (r *localRobot) MachineStatus(ctx context.Context) (robot.MachineStatus, er
 	var result robot.MachineStatus
 
 	result.Resources = append(result.Resources, r.manager.resources.Status()...)
+	if r.manager.moduleManager != nil {
+		result.Modules = r.manager.moduleManager.Health()
+	}
 
 	r.configRevisionMu.RLock()
 	result.Config = r.configRevision
@@ -1383,6 +1383,53 @@ func TestValidationErrorOnReconfigure(t *testing.T) {
 	test.That(t, ok, test.ShouldBeFalse)
 }
 
+func TestDataManagerTrigger(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	captureDir := t.TempDir()
+
+	cfg, err := config.FromReader(ctx, "", strings.NewReader(fmt.Sprintf(`{
+		"components": [
+			{
+				"name": "sensor1", "api": "rdk:component:sensor", "model": "fake",
+				"service_configs": [{
+					"type": "data_manager",
+					"attributes": {"capture_methods": [{
+						"method": "Readings",
+						"capture_frequency_hz": 20,
+						"trigger": {"type": "sensor_threshold", "resource": "sensor2", "key": "a", "above": 0,
+							"check_frequency_hz": 20, "post_trigger_secs": 1}
+					}]}
+				}]
+			},
+			{"name": "sensor2", "api": "rdk:component:sensor", "model": "fake"}
+		],
+		"services": [
+			{"name": "dm", "api": "rdk:service:data_manager", "attributes": {"capture_dir": %q, "sync_disabled": true}}
+		]
+	}`, captureDir)), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Services, test.ShouldHaveLength, 1)
+	test.That(t, cfg.Services[0].ImplicitDependsOn, test.ShouldContain, "sensor2")
+
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	_, err = datamanager.FromRobot(r, "dm")
+	test.That(t, err, test.ShouldBeNil)
+
+	// sensor2 always reads a=1, so the trigger is met and readings of sensor1 are captured.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		var captured int
+		test.That(tb, filepath.WalkDir(captureDir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.Contains(path, "sensor1") {
+				captured++
+			}
+			return err
+		}), test.ShouldBeNil)
+		test.That(tb, captured, test.ShouldBeGreaterThan, 0)
+	})
+}
+
 func TestConfigStartsInvalidReconfiguresValid(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	ctx := context.Background()
@@ -333,6 +333,9 @@ func (rmr *RestartModuleRequest) MatchesModule(mod config.Module) bool {
 type MachineStatus struct {
 	Resources []resource.Status
 	Config    config.Revision
+	// Modules holds the health of the robot's modules. It is not part of the robot API's machine status, clients
+	// get it with the CommandModuleHealth command instead.
+	Modules []config.ModuleHealth
 }
 
 // VersionResponse encapsulates the version info of the robot.
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/robot"
+)
+
+// CommandServiceServer is the server API of the command service, see robot.CommandServiceName.
+type CommandServiceServer interface {
+	DoCommand(ctx context.Context, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, error)
+}
+
+// CommandServiceDesc describes the command service, see robot.CommandServiceName. It is served by a Server.
+var CommandServiceDesc = grpc.ServiceDesc{
+	ServiceName: robot.CommandServiceName,
+	HandlerType: (*CommandServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DoCommand",
+			Handler:    commandServiceDoCommandHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func commandServiceDoCommandHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	in := new(commonpb.DoCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandServiceServer).DoCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + robot.CommandServiceName + "/DoCommand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandServiceServer).DoCommand(ctx, req.(*commonpb.DoCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DoCommand runs a command of the command service, see robot.CommandServiceName.
+func (s *Server) DoCommand(ctx context.Context, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, error) {
+	command, _ := req.GetCommand().AsMap()[robot.CommandKey].(string)
+	var result interface{}
+	switch command {
+	case robot.CommandModuleHealth:
+		mStatus, err := s.robot.MachineStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = map[string]interface{}{"modules": mStatus.Modules}
+	case robot.CommandLeases:
+		result = map[string]interface{}{"leases": s.robot.SessionManager().Leases()}
+	case robot.CommandOperationHistory:
+		result = map[string]interface{}{"history": s.robot.OperationManager().History()}
+	default:
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "unknown command %q", command)
+	}
+
+	// results are converted through JSON so that they may hold any JSON encodable value
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var pbResult structpb.Struct
+	if err := pbResult.UnmarshalJSON(encoded); err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: &pbResult}, nil
+}
@@ -895,6 +895,23 @@ func (mgr *sessionManager) AssociateResource(id uuid.UUID, resourceName resource
 	panic("unimplemented")
 }
 
+func (mgr *sessionManager) AcquireLease(
+	ctx context.Context,
+	id uuid.UUID,
+	resourceName resource.Name,
+	req session.LeaseRequest,
+) (session.Lease, error) {
+	panic("unimplemented")
+}
+
+func (mgr *sessionManager) ReleaseLease(id uuid.UUID, resourceName resource.Name) {
+	panic("unimplemented")
+}
+
+func (mgr *sessionManager) Leases() []session.Lease {
+	panic("unimplemented")
+}
+
 func (mgr *sessionManager) Close() {
 }
 
@@ -0,0 +1,189 @@
+package robot
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/session"
+)
+
+// leaseHeldError is returned when a lease held by another session prevents a lease from being
+// acquired or a resource from being controlled.
+func leaseHeldError(resourceName resource.Name, held session.Lease) error {
+	if held.Mode == session.LeaseExclusive {
+		return status.Errorf(codes.FailedPrecondition,
+			"resource %q is leased exclusively by session %s with priority %d",
+			resourceName, held.SessionID, held.Priority)
+	}
+	return status.Errorf(codes.FailedPrecondition,
+		"resource %q is leased for shared reading by session %s with priority %d and may not be controlled",
+		resourceName, held.SessionID, held.Priority)
+}
+
+// AcquireLease acquires a lease on a resource for the session with the given ID, replacing any
+// lease the session already holds on it. Conflicting leases held by other sessions cause this to fail
+// unless the request preempts them and is of a higher priority than all of them. The priority may not
+// exceed the maximum allowed for the caller by ctx, see session.WithMaxLeasePriority. When an exclusive
+// lease is preempted, the resource is stopped so that the preempting session takes over a resource
+// at rest.
+func (m *SessionManager) AcquireLease(
+	ctx context.Context,
+	id uuid.UUID,
+	resourceName resource.Name,
+	req session.LeaseRequest,
+) (session.Lease, error) {
+	if req.Mode != session.LeaseExclusive && req.Mode != session.LeaseSharedRead {
+		return session.Lease{}, status.Errorf(codes.InvalidArgument, "invalid lease mode %s", req.Mode)
+	}
+	if maxPriority := session.MaxLeasePriorityFromContext(ctx); req.Priority > maxPriority {
+		return session.Lease{}, status.Errorf(codes.PermissionDenied,
+			"lease priority %d exceeds the maximum of %d allowed for the caller", req.Priority, maxPriority)
+	}
+
+	lease := session.Lease{
+		Resource:  resourceName,
+		SessionID: id,
+		Mode:      req.Mode,
+		Priority:  req.Priority,
+		Acquired:  time.Now(),
+	}
+	var preempted []session.Lease
+	if err := func() error {
+		m.sessionResourceMu.Lock()
+		defer m.sessionResourceMu.Unlock()
+		if _, ok := m.sessions[id]; !ok {
+			return session.ErrNoSession
+		}
+
+		kept := make([]session.Lease, 0, len(m.leases[resourceName])+1)
+		for _, held := range m.leases[resourceName] {
+			switch {
+			case held.SessionID == id:
+			case !held.ConflictsWith(req.Mode):
+				kept = append(kept, held)
+			case req.Preempt && req.Priority > held.Priority:
+				preempted = append(preempted, held)
+			default:
+				return leaseHeldError(resourceName, held)
+			}
+		}
+		m.leases[resourceName] = append(kept, lease)
+		return nil
+	}(); err != nil {
+		return session.Lease{}, err
+	}
+
+	var stop bool
+	for _, held := range preempted {
+		m.logger.CInfow(ctx, "lease preempted",
+			"resource", resourceName,
+			"session_id", held.SessionID,
+			"mode", held.Mode,
+			"preempted_by", id,
+		)
+		stop = stop || held.Mode == session.LeaseExclusive
+	}
+	if stop {
+		if err := m.stopResource(ctx, resourceName); err != nil {
+			m.logger.CErrorw(ctx, "failed to stop resource after preempting its lease", "resource", resourceName, "error", err)
+		}
+	}
+	return lease, nil
+}
+
+// stopResource stops the named resource if it actuates.
+func (m *SessionManager) stopResource(ctx context.Context, resourceName resource.Name) (err error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = errors.Errorf("panic stopping %q: %v", resourceName, panicErr)
+		}
+	}()
+	res, err := m.robot.ResourceByName(resourceName)
+	if err != nil {
+		return err
+	}
+	if actuator, ok := res.(resource.Actuator); ok {
+		return actuator.Stop(ctx, nil)
+	}
+	return nil
+}
+
+// ReleaseLease releases the lease, if any, the session with the given ID holds on a resource.
+func (m *SessionManager) ReleaseLease(id uuid.UUID, resourceName resource.Name) {
+	m.sessionResourceMu.Lock()
+	defer m.sessionResourceMu.Unlock()
+	m.releaseLeases(resourceName, func(held session.Lease) bool {
+		return held.SessionID == id
+	})
+}
+
+// releaseLeases removes the leases on a resource matching release. The sessionResourceMu must be held.
+func (m *SessionManager) releaseLeases(resourceName resource.Name, release func(held session.Lease) bool) {
+	var kept []session.Lease
+	for _, held := range m.leases[resourceName] {
+		if !release(held) {
+			kept = append(kept, held)
+		}
+	}
+	if len(kept) == 0 {
+		delete(m.leases, resourceName)
+		return
+	}
+	m.leases[resourceName] = kept
+}
+
+// Leases returns all leases held, ordered by resource and then by when they were acquired.
+func (m *SessionManager) Leases() []session.Lease {
+	m.sessionResourceMu.RLock()
+	var leases []session.Lease
+	for _, held := range m.leases {
+		leases = append(leases, held...)
+	}
+	m.sessionResourceMu.RUnlock()
+	sort.Slice(leases, func(i, j int) bool {
+		if leases[i].Resource != leases[j].Resource {
+			return leases[i].Resource.String() < leases[j].Resource.String()
+		}
+		return leases[i].Acquired.Before(leases[j].Acquired)
+	})
+	return leases
+}
+
+// leasesHeld returns whether any session holds a lease, so that calls need not be checked against
+// leases while none are.
+func (m *SessionManager) leasesHeld() bool {
+	m.sessionResourceMu.RLock()
+	defer m.sessionResourceMu.RUnlock()
+	return len(m.leases) != 0
+}
+
+// checkLease returns an error if a lease on the resource prevents the session with the given ID
+// (which may be uuid.Nil) from controlling it. Only the holder of an exclusive lease may control a
+// leased resource.
+func (m *SessionManager) checkLease(id uuid.UUID, resourceName resource.Name) error {
+	m.sessionResourceMu.RLock()
+	defer m.sessionResourceMu.RUnlock()
+	held := m.leases[resourceName]
+	if len(held) == 0 {
+		return nil
+	}
+	for _, lease := range held {
+		if lease.SessionID == id && lease.Mode == session.LeaseExclusive {
+			return nil
+		}
+	}
+	// prefer naming a lease held by another session
+	for _, lease := range held {
+		if lease.SessionID != id {
+			return leaseHeldError(resourceName, lease)
+		}
+	}
+	return leaseHeldError(resourceName, held[0])
+}
@@ -10,6 +10,7 @@ import (
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/session"
 )
@@ -22,6 +23,7 @@ func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManag
 		logger:            robot.Logger().Sublogger("networking.session_manager"),
 		sessions:          map[uuid.UUID]*session.Session{},
 		resourceToSession: map[resource.Name]uuid.UUID{},
+		leases:            map[resource.Name][]session.Lease{},
 	}
 	m.workers = utils.NewBackgroundStoppableWorkers(m.expireLoop)
 	return m
@@ -38,6 +40,7 @@ type SessionManager struct {
 	sessions          map[uuid.UUID]*session.Session
 
 	resourceToSession map[resource.Name]uuid.UUID
+	leases            map[resource.Name][]session.Lease
 
 	workers *utils.StoppableWorkers
 }
@@ -88,6 +91,15 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 			for id := range toDelete {
 				delete(m.sessions, id)
 			}
+			metrics.Sessions.Set(float64(len(m.sessions)))
+			if len(toDelete) != 0 {
+				for resName := range m.leases {
+					m.releaseLeases(resName, func(held session.Lease) bool {
+						_, expired := toDelete[held.SessionID]
+						return expired
+					})
+				}
+			}
 
 			if len(toStop) == 0 {
 				return
@@ -158,6 +170,7 @@ func (m *SessionManager) Start(ctx context.Context, ownerID string) (*session.Se
 		return nil, errors.New("too many concurrent sessions")
 	}
 	m.sessions[sess.ID()] = sess
+	metrics.Sessions.Set(float64(len(m.sessions)))
 	m.sessionResourceMu.Unlock()
 	return sess, nil
 }
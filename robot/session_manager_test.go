@@ -5,11 +5,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	commonpb "go.viam.com/api/common/v1"
+	basepb "go.viam.com/api/component/base/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/testutils/inject"
@@ -87,3 +96,202 @@ func TestSessionManagerExpiredSessions(t *testing.T) {
 			test.ShouldEqual, 1)
 	})
 }
+
+func TestSessionManagerLeases(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+	injectBase := inject.NewBase("base1")
+	var stops int
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stops++
+		return nil
+	}
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		return injectBase, nil
+	}
+
+	sm := robot.NewSessionManager(r, config.DefaultSessionHeartbeatWindow)
+	defer sm.Close()
+
+	driver, err := sm.Start(ctx, "driver")
+	test.That(t, err, test.ShouldBeNil)
+	other, err := sm.Start(ctx, "other")
+	test.That(t, err, test.ShouldBeNil)
+	supervisor, err := sm.Start(ctx, "supervisor")
+	test.That(t, err, test.ShouldBeNil)
+
+	baseName := base.Named("base1")
+	ctx = session.WithMaxLeasePriority(ctx, 1)
+	_, err = sm.AcquireLease(ctx, driver.ID(), baseName, session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 1})
+	test.That(t, err, test.ShouldBeNil)
+
+	// conflicting leases fail, even when preempting a lease of the same priority
+	for _, req := range []session.LeaseRequest{
+		{Mode: session.LeaseExclusive},
+		{Mode: session.LeaseSharedRead},
+		{Mode: session.LeaseExclusive, Priority: 1, Preempt: true},
+	} {
+		_, err = sm.AcquireLease(ctx, other.ID(), baseName, req)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "leased exclusively by session "+driver.ID().String())
+	}
+
+	_, err = sm.AcquireLease(ctx, uuid.New(), baseName, session.LeaseRequest{Mode: session.LeaseExclusive})
+	test.That(t, err, test.ShouldBeError, session.ErrNoSession)
+
+	// priorities are limited to the maximum allowed for the caller
+	_, err = sm.AcquireLease(ctx, supervisor.ID(), baseName,
+		session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 10, Preempt: true})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "exceeds the maximum of 1")
+	test.That(t, stops, test.ShouldEqual, 0)
+	supervisorCtx := session.WithMaxLeasePriority(ctx, 10)
+
+	// a supervisor of higher priority takes over and the base is stopped
+	lease, err := sm.AcquireLease(supervisorCtx, supervisor.ID(), baseName,
+		session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 10, Preempt: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stops, test.ShouldEqual, 1)
+	test.That(t, sm.Leases(), test.ShouldResemble, []session.Lease{lease})
+	test.That(t, lease.SessionID, test.ShouldEqual, supervisor.ID())
+	test.That(t, lease.Mode, test.ShouldEqual, session.LeaseExclusive)
+
+	sm.ReleaseLease(supervisor.ID(), baseName)
+	test.That(t, sm.Leases(), test.ShouldBeEmpty)
+
+	// shared reads do not conflict with each other
+	_, err = sm.AcquireLease(ctx, driver.ID(), baseName, session.LeaseRequest{Mode: session.LeaseSharedRead})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = sm.AcquireLease(ctx, other.ID(), baseName, session.LeaseRequest{Mode: session.LeaseSharedRead})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sm.Leases(), test.ShouldHaveLength, 2)
+	_, err = sm.AcquireLease(ctx, driver.ID(), baseName, session.LeaseRequest{Mode: session.LeaseExclusive})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "leased for shared reading by session "+other.ID().String())
+
+	// preempting shared reads does not stop the base
+	_, err = sm.AcquireLease(ctx, supervisor.ID(), baseName,
+		session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 1, Preempt: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stops, test.ShouldEqual, 1)
+	test.That(t, sm.Leases(), test.ShouldHaveLength, 1)
+}
+
+func TestSessionManagerLeasesExpire(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+
+	sm := robot.NewSessionManager(r, 100*time.Millisecond)
+	defer sm.Close()
+
+	sess, err := sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = sm.AcquireLease(ctx, sess.ID(), base.Named("base1"), session.LeaseRequest{Mode: session.LeaseSharedRead})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sm.Leases(), test.ShouldHaveLength, 1)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, sm.Leases(), test.ShouldBeEmpty)
+	})
+}
+
+func TestSessionManagerLeaseInterceptor(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+	baseReg, ok := resource.LookupGenericAPIRegistration(base.API)
+	test.That(t, ok, test.ShouldBeTrue)
+	r.ResourceRPCAPIsFunc = func() []resource.RPCAPI {
+		return []resource.RPCAPI{{API: base.API, Desc: baseReg.ReflectRPCServiceDesc}}
+	}
+	injectBase := inject.NewBase("base1")
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		return injectBase, nil
+	}
+
+	sm := robot.NewSessionManager(r, config.DefaultSessionHeartbeatWindow)
+	defer sm.Close()
+
+	driver, err := sm.Start(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+	other, err := sm.Start(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+
+	call := func(method string, sessID uuid.UUID, lease *session.LeaseRequest) error {
+		md := metadata.MD{}
+		if sessID != uuid.Nil {
+			md.Set(session.IDMetadataKey, sessID.String())
+		}
+		if lease != nil {
+			md.Set(session.LeaseMetadataKey, lease.String())
+		}
+		var req interface{}
+		switch method {
+		case "IsMoving":
+			req = &basepb.IsMovingRequest{Name: "base1"}
+		case "Stop":
+			req = &basepb.StopRequest{Name: "base1"}
+		case "DoCommand":
+			req = &commonpb.DoCommandRequest{Name: "base1"}
+		default:
+			req = &basepb.SetPowerRequest{Name: "base1"}
+		}
+		_, err := sm.UnaryServerInterceptor(
+			metadata.NewIncomingContext(ctx, md),
+			req,
+			&grpc.UnaryServerInfo{FullMethod: "/viam.component.base.v1.BaseService/" + method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+		)
+		return err
+	}
+
+	// a lease can be acquired with any method of the resource
+	err = call("IsMoving", driver.ID(), &session.LeaseRequest{Mode: session.LeaseExclusive})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sm.Leases(), test.ShouldHaveLength, 1)
+
+	test.That(t, call("SetPower", driver.ID(), nil), test.ShouldBeNil)
+	test.That(t, call("DoCommand", driver.ID(), nil), test.ShouldBeNil)
+	// reading and stopping a leased resource are allowed to anyone
+	test.That(t, call("IsMoving", other.ID(), nil), test.ShouldBeNil)
+	test.That(t, call("Stop", other.ID(), nil), test.ShouldBeNil)
+	for _, sessID := range []uuid.UUID{other.ID(), uuid.Nil} {
+		// every other method controls the resource, whether safety monitored or not
+		for _, method := range []string{"SetPower", "DoCommand"} {
+			err = call(method, sessID, nil)
+			test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+			test.That(t, err.Error(), test.ShouldContainSubstring, "leased exclusively")
+		}
+	}
+
+	err = call("IsMoving", uuid.Nil, &session.LeaseRequest{Mode: session.LeaseSharedRead})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+
+	test.That(t, call("SetPower", driver.ID(), &session.LeaseRequest{Release: true}), test.ShouldBeNil)
+	test.That(t, sm.Leases(), test.ShouldBeEmpty)
+	test.That(t, call("SetPower", other.ID(), nil), test.ShouldBeNil)
+
+	// without auth roles to limit them, callers may preempt with any priority
+	err = call("IsMoving", driver.ID(), &session.LeaseRequest{Mode: session.LeaseExclusive})
+	test.That(t, err, test.ShouldBeNil)
+	err = call("IsMoving", other.ID(), &session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 10, Preempt: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sm.Leases(), test.ShouldHaveLength, 1)
+	test.That(t, sm.Leases()[0].SessionID, test.ShouldEqual, other.ID())
+	test.That(t, sm.Leases()[0].Priority, test.ShouldEqual, 10)
+	err = call("DoCommand", driver.ID(), nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+}
@@ -2,6 +2,7 @@ package robot
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -11,7 +12,9 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/rdk/protoutils"
@@ -19,45 +22,101 @@ import (
 	"go.viam.com/rdk/session"
 )
 
-func (m *SessionManager) safetyMonitoredTypeAndMethod(method string) (*resource.RPCAPI, *desc.MethodDescriptor, bool) {
+// calledResource is the resource a call is made to, if it is safety monitored, controls a leased
+// resource or is leased by the call.
+type calledResource struct {
+	name            resource.Name
+	safetyMonitored bool
+	controls        bool
+}
+
+// readOnlyMethodPrefixes and readOnlyMethods name the resource methods that only read a resource's
+// state, and so may be called regardless of the leases held on it. Any other method, including
+// DoCommand and the methods of APIs registered by modules, is assumed to control the resource.
+var (
+	readOnlyMethodPrefixes = []string{"Get", "Is", "Read", "List", "Stream"}
+	readOnlyMethods        = map[string]bool{
+		"CaptureAllFromCamera": true,
+		"Chunks":               true,
+		"Echo":                 true,
+		"Infer":                true,
+		"Metadata":             true,
+		"Properties":           true,
+		"PWM":                  true,
+		"PWMFrequency":         true,
+		"RenderFrame":          true,
+		// any session may stop a resource, since stopping is always safe
+		"Stop": true,
+	}
+)
+
+// controlsResource returns whether calling the named resource method violates the leases held by
+// other sessions on the resource.
+func controlsResource(methodName string) bool {
+	if readOnlyMethods[methodName] {
+		return false
+	}
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(methodName, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// leaseRequested returns whether an incoming call asks to acquire or release a lease.
+func leaseRequested(ctx context.Context) bool {
+	meta, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(meta.Get(session.LeaseMetadataKey)) != 0
+}
+
+// calledTypeAndMethod returns the resource API and method description of a method whose resource
+// the call must be checked against: one that is safety monitored, one that controls a resource
+// while any lease is held, or, if leasing, any resource method.
+func (m *SessionManager) calledTypeAndMethod(
+	method string,
+	leasing bool,
+) (*resource.RPCAPI, *desc.MethodDescriptor, calledResource, bool) {
 	subType, methodDesc, err := TypeAndMethodDescFromMethod(m.robot, method)
 	if err != nil {
-		return nil, nil, false
+		return nil, nil, calledResource{}, false
 	}
 	opts := methodDesc.AsMethodDescriptorProto().Options
-	if !proto.HasExtension(opts, commonpb.E_SafetyHeartbeatMonitored) {
-		return nil, nil, false
+	var called calledResource
+	if proto.HasExtension(opts, commonpb.E_SafetyHeartbeatMonitored) {
+		called.safetyMonitored = proto.GetExtension(opts, commonpb.E_SafetyHeartbeatMonitored).(bool)
 	}
-	safetyMonitored := proto.GetExtension(opts, commonpb.E_SafetyHeartbeatMonitored).(bool)
-	if !safetyMonitored {
-		return nil, nil, false
+	called.controls = called.safetyMonitored || controlsResource(methodDesc.GetName())
+	if !called.safetyMonitored && !leasing && !(called.controls && m.leasesHeld()) {
+		return nil, nil, calledResource{}, false
 	}
-	return subType, methodDesc, true
+	return subType, methodDesc, called, true
 }
 
-func (m *SessionManager) safetyMonitoredResourceFromUnary(req interface{}, method string) resource.Name {
-	subType, _, ok := m.safetyMonitoredTypeAndMethod(method)
+func (m *SessionManager) calledResourceFromUnary(req interface{}, method string, leasing bool) calledResource {
+	subType, _, called, ok := m.calledTypeAndMethod(method, leasing)
 	if !ok {
-		return resource.Name{}
+		return calledResource{}
 	}
 
 	reqMsg := protoutils.MessageToProtoV1(req)
 	if reqMsg == nil {
-		return resource.Name{}
+		return calledResource{}
 	}
 
 	msg, err := dynamic.AsDynamicMessage(reqMsg)
 	if err != nil {
 		m.logger.Errorw("error converting message to dynamic", "error", err, "method", method)
-		return resource.Name{}
+		return calledResource{}
 	}
 
 	_, resName, err := ResourceFromProtoMessage(m.robot, msg, subType.API)
 	if err != nil {
 		m.logger.Errorw("unable to find resource", "error", err)
-		return resource.Name{}
+		return calledResource{}
 	}
-	return resName
+	called.name = resName
+	return called
 }
 
 type firstMessageServerStreamWrapper struct {
@@ -81,22 +140,23 @@ func (w *firstMessageServerStreamWrapper) RecvMsg(m interface{}) error {
 	return w.ServerStream.RecvMsg(m)
 }
 
-func (m *SessionManager) safetyMonitoredResourceFromStream(
+func (m *SessionManager) calledResourceFromStream(
 	stream grpc.ServerStream,
 	method string,
-) (resource.Name, grpc.ServerStream, error) {
-	subType, methodDesc, ok := m.safetyMonitoredTypeAndMethod(method)
+	leasing bool,
+) (calledResource, grpc.ServerStream, error) {
+	subType, methodDesc, called, ok := m.calledTypeAndMethod(method, leasing)
 	if !ok {
 		// Note(erd): could maybe cache this in the future but may be subject to a DOS attack
 		// since method space is unbounded.
-		return resource.Name{}, nil, nil
+		return calledResource{}, nil, nil
 	}
 
 	firstMsg := dynamic.NewMessage(methodDesc.GetInputType())
 
 	if err := stream.RecvMsg(firstMsg); err != nil {
 		// this error counts
-		return resource.Name{}, nil, err
+		return calledResource{}, nil, err
 	}
 
 	newStream := &firstMessageServerStreamWrapper{ServerStream: stream, firstMsg: firstMsg}
@@ -104,10 +164,11 @@ func (m *SessionManager) safetyMonitoredResourceFromStream(
 	_, resName, err := ResourceFromProtoMessage(m.robot, firstMsg, subType.API)
 	if err != nil {
 		m.logger.Errorw("unable to find resource", "error", err)
-		return resource.Name{}, newStream, nil
+		return calledResource{}, newStream, nil
 	}
 
-	return resName, newStream, nil
+	called.name = resName
+	return called, newStream, nil
 }
 
 var exemptFromSession = map[string]bool{
@@ -142,8 +203,8 @@ func (m *SessionManager) UnaryServerInterceptor(
 	if exemptFromSession[info.FullMethod] {
 		return handler(ctx, req)
 	}
-	safetyMonitoredResourceName := m.safetyMonitoredResourceFromUnary(req, info.FullMethod)
-	ctx, err := associateSession(ctx, m, safetyMonitoredResourceName, info.FullMethod)
+	called := m.calledResourceFromUnary(req, info.FullMethod, leaseRequested(ctx))
+	ctx, err := associateSession(ctx, m, called, info.FullMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -161,14 +222,14 @@ func (m *SessionManager) StreamServerInterceptor(
 	if exemptFromSession[info.FullMethod] {
 		return handler(srv, ss)
 	}
-	safetyMonitoredResource, wrappedStream, err := m.safetyMonitoredResourceFromStream(ss, info.FullMethod)
+	called, wrappedStream, err := m.calledResourceFromStream(ss, info.FullMethod, leaseRequested(ss.Context()))
 	if err != nil {
 		return err
 	}
 	if wrappedStream != nil {
 		ss = wrappedStream
 	}
-	ctx, err := associateSession(ss.Context(), m, safetyMonitoredResource, info.FullMethod)
+	ctx, err := associateSession(ss.Context(), m, called, info.FullMethod)
 	if err != nil {
 		return err
 	}
@@ -176,41 +237,77 @@ func (m *SessionManager) StreamServerInterceptor(
 }
 
 // associateSession creates a new context associated with the session, if found, from an incoming context.
+// Any lease requested by the call is acquired or released, and calls controlling a resource in violation
+// of a lease on it are rejected, see controlsResource.
 func associateSession(
 	ctx context.Context,
 	m *SessionManager,
-	safetyMonitoredResourceName resource.Name,
+	called calledResource,
 	method string,
 ) (nextCtx context.Context, err error) {
 	var sessID uuid.UUID
-	if safetyMonitoredResourceName != (resource.Name{}) {
+	if called.safetyMonitored && called.name != (resource.Name{}) {
 		// defer this because no matter what we want to know that someone was using
 		// a resource with a monitored method as long as no error happened.
 		defer func() {
 			if err == nil {
-				m.AssociateResource(sessID, safetyMonitoredResourceName)
+				m.AssociateResource(sessID, called.name)
 			}
 		}()
 	}
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		m.logger.CWarnw(ctx, "failed to pull metadata from context", "method", method)
-		return ctx, nil
+		return ctx, m.leaseCall(ctx, nil, sessID, called)
 	}
 	sessID, err = sessionFromMetadata(meta)
 	if err != nil {
 		m.logger.CWarnw(ctx, "failed to get session id from metadata", "error", err)
 		return ctx, err
 	}
-	if sessID == uuid.Nil {
-		return ctx, nil
+	if sessID != uuid.Nil {
+		authEntity, _ := rpc.ContextAuthEntity(ctx)
+		sess, err := m.FindByID(ctx, sessID, authEntity.Entity)
+		if err != nil {
+			return nil, err
+		}
+		ctx = session.ToContext(ctx, sess)
 	}
-	authEntity, _ := rpc.ContextAuthEntity(ctx)
-	sess, err := m.FindByID(ctx, sessID, authEntity.Entity)
-	if err != nil {
+	if err := m.leaseCall(ctx, meta, sessID, called); err != nil {
 		return nil, err
 	}
-	return session.ToContext(ctx, sess), nil
+	return ctx, nil
+}
+
+// leaseCall acquires or releases the lease a call asks for, if any, on the resource called and then
+// checks that a call controlling the resource does not violate a lease held on it.
+func (m *SessionManager) leaseCall(
+	ctx context.Context,
+	meta metadata.MD,
+	sessID uuid.UUID,
+	called calledResource,
+) error {
+	if called.name == (resource.Name{}) {
+		return nil
+	}
+	req, err := session.LeaseRequestFromMetadata(meta)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req != nil {
+		if sessID == uuid.Nil {
+			return status.Error(codes.InvalidArgument, "a session is required to lease a resource")
+		}
+		if req.Release {
+			m.ReleaseLease(sessID, called.name)
+		} else if _, err := m.AcquireLease(ctx, sessID, called.name, *req); err != nil {
+			return err
+		}
+	}
+	if !called.controls {
+		return nil
+	}
+	return m.checkLease(sessID, called.name)
 }
 
 // sessionFromMetadata returns a session id from metadata.
@@ -0,0 +1,331 @@
+package web
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/session"
+)
+
+// rpcFrameworkServicePrefix prefixes the services of the rpc framework itself, such as authentication and WebRTC
+// signaling, which must remain callable for callers to be able to authenticate and connect at all.
+const rpcFrameworkServicePrefix = "/proto.rpc."
+
+// authClaims are the rpc_auth_md claims of an externally authenticated caller, stored as its entity data.
+type authClaims map[string]string
+
+// authorizer enforces the auth roles of the robot config on the calls made to the robot's gRPC server, see
+// config.AuthRoleConfig.
+//
+// Calls made over WebRTC are authenticated as the entity of the machine's host names rather than the caller, so
+// they are only bound to roles that bind WebRTC callers explicitly.
+type authorizer struct {
+	roles  []config.AuthRoleConfig
+	logger logging.Logger
+	// overWebRTC returns whether the call of ctx was made over WebRTC.
+	overWebRTC func(ctx context.Context) bool
+
+	apisMu sync.Mutex
+	// apisByService maps the gRPC service names of registered resource APIs to the API, and otherServices holds
+	// the services called that are not resource APIs. Both are refreshed when the resource graph changes, as
+	// modules register APIs after the web service starts.
+	apisByService map[string]resource.API
+	otherServices map[string]struct{}
+}
+
+// authorizedCall describes a call to check against roles.
+type authorizedCall struct {
+	fullMethod string
+	method     string
+	api        string
+	resource   string
+	// requested is whether the call's request is known.
+	requested bool
+	// anyResource is set to check whether the call may be allowed on some resource of its API, before the
+	// resource it's made on is known.
+	anyResource bool
+}
+
+func newAuthorizer(roles []config.AuthRoleConfig, logger logging.Logger) *authorizer {
+	return &authorizer{roles: roles, logger: logger, overWebRTC: func(ctx context.Context) bool {
+		_, ok := rpc.ContextPeerConnection(ctx)
+		return ok
+	}}
+}
+
+// entityDataLoader stores the claims of externally authenticated callers, which roles may be bound by, as their
+// entity data.
+func (a *authorizer) entityDataLoader(ctx context.Context, claims rpc.Claims) (interface{}, error) {
+	return authClaims(claims.Metadata()), nil
+}
+
+// UnaryServerInterceptor rejects unary calls the caller's roles do not allow.
+func (a *authorizer) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *googlegrpc.UnaryServerInfo,
+	handler googlegrpc.UnaryHandler,
+) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(a.withMaxLeasePriority(ctx), req)
+}
+
+// StreamServerInterceptor rejects streaming calls the caller's roles do not allow. Streams are checked when opened
+// against the method called, and streams of resource APIs are checked again against every request received, as
+// the resource being called is only known from them. Calls to resource APIs which would send a response before
+// receiving any request are rejected, as which resource they call can't be checked.
+func (a *authorizer) StreamServerInterceptor(
+	srv interface{},
+	ss googlegrpc.ServerStream,
+	info *googlegrpc.StreamServerInfo,
+	handler googlegrpc.StreamHandler,
+) error {
+	if strings.HasPrefix(info.FullMethod, rpcFrameworkServicePrefix) {
+		return handler(srv, ss)
+	}
+	call := a.describe(info.FullMethod, nil)
+	call.anyResource = call.api != ""
+	if err := a.authorizeCall(ss.Context(), call); err != nil {
+		return err
+	}
+	return handler(srv, &authorizedServerStream{
+		ServerStream: ss,
+		ctx:          a.withMaxLeasePriority(ss.Context()),
+		authorizer:   a,
+		fullMethod:   info.FullMethod,
+		resourceAPI:  call.anyResource,
+	})
+}
+
+type authorizedServerStream struct {
+	googlegrpc.ServerStream
+	ctx        context.Context
+	authorizer *authorizer
+	fullMethod string
+	// resourceAPI is whether the stream calls a resource API, and so is checked against every request.
+	resourceAPI bool
+
+	mu sync.Mutex
+	// received is whether a request has been received and authorized.
+	received bool
+}
+
+func (s *authorizedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.resourceAPI {
+		return nil
+	}
+	if err := s.authorizer.authorize(s.Context(), s.fullMethod, m); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.received = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *authorizedServerStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
+	received := s.received
+	s.mu.Unlock()
+	if s.resourceAPI && !received {
+		if err := s.authorizer.authorize(s.Context(), s.fullMethod, nil); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// authorize returns a PermissionDenied error, and audit logs it, if none of the roles bound to the caller allow
+// the call.
+func (a *authorizer) authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	if strings.HasPrefix(fullMethod, rpcFrameworkServicePrefix) {
+		return nil
+	}
+	return a.authorizeCall(ctx, a.describe(fullMethod, req))
+}
+
+// authorizeCall returns a PermissionDenied error, and audit logs it, if none of the roles bound to the caller
+// allow call.
+func (a *authorizer) authorizeCall(ctx context.Context, call authorizedCall) error {
+	entity, ok := rpc.ContextAuthEntity(ctx)
+	if !ok {
+		// no role can bind a caller that did not authenticate
+		a.logger.Warnw("permission denied", "entity", "", "method", call.fullMethod, "api", call.api, "resource", call.resource)
+		return status.Errorf(codes.PermissionDenied, "unauthenticated callers are not permitted to call %s", call.fullMethod)
+	}
+	claims, _ := entity.Data.(authClaims)
+	webRTC := a.overWebRTC(ctx)
+	if call.api != "" && !call.requested && !call.anyResource {
+		a.logger.Warnw("permission denied", "entity", entity.Entity, "method", call.fullMethod, "api", call.api)
+		return status.Errorf(codes.PermissionDenied, "%s must be sent a request before responding", call.fullMethod)
+	}
+
+	var boundRoles []string
+	for _, role := range a.roles {
+		if !roleBinds(role, entity.Entity, claims, webRTC) {
+			continue
+		}
+		boundRoles = append(boundRoles, role.Name)
+		if roleAllows(role, call) {
+			return nil
+		}
+	}
+
+	a.logger.Warnw("permission denied",
+		"entity", entity.Entity,
+		"roles", boundRoles,
+		"method", call.fullMethod,
+		"api", call.api,
+		"resource", call.resource)
+	if call.resource != "" {
+		return status.Errorf(codes.PermissionDenied, "%s is not permitted to call %s on %s", entity.Entity, call.method, call.resource)
+	}
+	return status.Errorf(codes.PermissionDenied, "%s is not permitted to call %s", entity.Entity, call.fullMethod)
+}
+
+// withMaxLeasePriority returns a context limiting the priority of the leases the caller may acquire to the highest
+// MaxLeasePriority of the roles bound to it, or to 0 if none are.
+func (a *authorizer) withMaxLeasePriority(ctx context.Context) context.Context {
+	var maxPriority int
+	if entity, ok := rpc.ContextAuthEntity(ctx); ok {
+		claims, _ := entity.Data.(authClaims)
+		webRTC := a.overWebRTC(ctx)
+		var bound bool
+		for _, role := range a.roles {
+			if !roleBinds(role, entity.Entity, claims, webRTC) {
+				continue
+			}
+			if !bound || role.MaxLeasePriority > maxPriority {
+				maxPriority = role.MaxLeasePriority
+			}
+			bound = true
+		}
+	}
+	return session.WithMaxLeasePriority(ctx, maxPriority)
+}
+
+func (a *authorizer) describe(fullMethod string, req interface{}) authorizedCall {
+	call := authorizedCall{fullMethod: fullMethod, method: fullMethod, requested: req != nil}
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if ok {
+		call.method = method
+		if api, ok := a.apiForService(service); ok {
+			call.api = api.String()
+		}
+	}
+	if named, ok := req.(interface{ GetName() string }); ok && call.api != "" {
+		call.resource = named.GetName()
+	}
+	return call
+}
+
+func (a *authorizer) apiForService(service string) (resource.API, bool) {
+	a.apisMu.Lock()
+	defer a.apisMu.Unlock()
+	if api, ok := a.apisByService[service]; ok {
+		return api, true
+	}
+	if _, ok := a.otherServices[service]; ok {
+		return resource.API{}, false
+	}
+	if a.apisByService == nil {
+		a.apisByService = map[string]resource.API{}
+		for api, reg := range resource.RegisteredAPIs() {
+			if reg.RPCServiceDesc != nil {
+				a.apisByService[reg.RPCServiceDesc.ServiceName] = api
+			}
+		}
+		a.otherServices = map[string]struct{}{}
+	}
+	api, ok := a.apisByService[service]
+	if !ok {
+		a.otherServices[service] = struct{}{}
+	}
+	return api, ok
+}
+
+// resourcesChanged refreshes the resource APIs services are known to be, as modules may have registered more.
+func (a *authorizer) resourcesChanged() {
+	a.apisMu.Lock()
+	defer a.apisMu.Unlock()
+	a.apisByService = nil
+	a.otherServices = nil
+}
+
+// roleBinds returns whether the caller authenticated as entity with claims, or connected over WebRTC, is bound to
+// role.
+func roleBinds(role config.AuthRoleConfig, entity string, claims authClaims, webRTC bool) bool {
+	if webRTC {
+		return role.WebRTC
+	}
+	if len(role.Entities) != 0 && matchesAny(role.Entities, entity) {
+		return true
+	}
+	if len(role.Claims) == 0 || claims == nil {
+		return false
+	}
+	for k, v := range role.Claims {
+		if claims[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// roleAllows returns whether any of the role's allow rules and none of its deny rules match call. When any resource
+// may be called, only deny rules matching every resource deny it.
+func roleAllows(role config.AuthRoleConfig, call authorizedCall) bool {
+	for _, rule := range role.Deny {
+		if call.anyResource && len(rule.Resources) != 0 {
+			continue
+		}
+		if ruleMatches(rule, call) {
+			return false
+		}
+	}
+	for _, rule := range role.Allow {
+		if ruleMatches(rule, call) {
+			return true
+		}
+	}
+	return false
+}
+
+func ruleMatches(rule config.AuthRuleConfig, call authorizedCall) bool {
+	return matchesAny(rule.APIs, call.api) &&
+		(call.anyResource || matchesAny(rule.Resources, call.resource)) &&
+		(matchesAny(rule.Methods, call.method) || matchesAny(rule.Methods, call.fullMethod))
+}
+
+// matchesAny returns whether value matches any of patterns, or true if there are none. Patterns are validated
+// with the config, so match errors are treated as mismatches.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package web
+
+import (
+	"context"
+	"testing"
+
+	commonpb "go.viam.com/api/common/v1"
+	armpb "go.viam.com/api/component/arm/v1"
+	camerapb "go.viam.com/api/component/camera/v1"
+	robotpb "go.viam.com/api/robot/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// register the APIs called below.
+	_ "go.viam.com/rdk/components/arm"
+	_ "go.viam.com/rdk/components/camera"
+	_ "go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/session"
+)
+
+func TestAuthorizer(t *testing.T) {
+	logger, logs := logging.NewObservedTestLogger(t)
+	authz := newAuthorizer([]config.AuthRoleConfig{
+		{
+			Name:     "operator",
+			Entities: []string{"operator-key"},
+			Allow: []config.AuthRuleConfig{
+				{APIs: []string{"rdk:component:camera", "rdk:component:sensor"}},
+				{Methods: []string{"/viam.robot.v1.RobotService/*"}},
+			},
+			Deny: []config.AuthRuleConfig{{Methods: []string{"DoCommand"}}},
+		},
+		{
+			Name:   "technician",
+			Claims: map[string]string{"team": "maintenance"},
+			Allow:  []config.AuthRuleConfig{{APIs: []string{"rdk:component:*"}, Resources: []string{"arm1"}}},
+		},
+	}, logger)
+
+	ctxFor := func(entity string, claims authClaims) context.Context {
+		return rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: entity, Data: claims})
+	}
+	call := func(ctx context.Context, method string, req interface{}) error {
+		_, err := authz.UnaryServerInterceptor(ctx, req, &googlegrpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil })
+		return err
+	}
+	const (
+		getReadings   = "/viam.component.sensor.v1.SensorService/GetReadings"
+		doCommand     = "/viam.component.sensor.v1.SensorService/DoCommand"
+		moveToPos     = "/viam.component.arm.v1.ArmService/MoveToPosition"
+		resourceNames = "/viam.robot.v1.RobotService/ResourceNames"
+	)
+
+	operator := ctxFor("operator-key", nil)
+	test.That(t, call(operator, getReadings, &commonpb.GetReadingsRequest{Name: "sensor1"}), test.ShouldBeNil)
+	test.That(t, call(operator, resourceNames, &robotpb.ResourceNamesRequest{}), test.ShouldBeNil)
+	test.That(t, call(operator, "/proto.rpc.v1.AuthService/Authenticate", nil), test.ShouldBeNil)
+
+	err := call(operator, doCommand, &commonpb.DoCommandRequest{Name: "sensor1"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	err = call(operator, moveToPos, &armpb.MoveToPositionRequest{Name: "arm1"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "operator-key is not permitted to call MoveToPosition on arm1")
+
+	denials := logs.FilterMessage("permission denied")
+	test.That(t, denials.Len(), test.ShouldEqual, 2)
+	fields := denials.All()[1].ContextMap()
+	test.That(t, fields["entity"], test.ShouldEqual, "operator-key")
+	test.That(t, fields["api"], test.ShouldEqual, "rdk:component:arm")
+	test.That(t, fields["resource"], test.ShouldEqual, "arm1")
+	test.That(t, fields["method"], test.ShouldEqual, moveToPos)
+
+	technician := ctxFor("someone@example.com", authClaims{"team": "maintenance"})
+	test.That(t, call(technician, moveToPos, &armpb.MoveToPositionRequest{Name: "arm1"}), test.ShouldBeNil)
+	err = call(technician, moveToPos, &armpb.MoveToPositionRequest{Name: "arm2"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+
+	// callers bound to no role may call nothing
+	err = call(ctxFor("someone@example.com", authClaims{"team": "sales"}), getReadings, &commonpb.GetReadingsRequest{Name: "sensor1"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	// unauthenticated callers are bound to no role
+	err = call(context.Background(), moveToPos, &armpb.MoveToPositionRequest{Name: "arm1"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+}
+
+func TestAuthorizerWebRTC(t *testing.T) {
+	authz := newAuthorizer([]config.AuthRoleConfig{
+		{
+			Name:     "operator",
+			Entities: []string{"*"},
+			Allow:    []config.AuthRuleConfig{{}},
+		},
+		{
+			Name:   "remote-viewer",
+			WebRTC: true,
+			Allow:  []config.AuthRuleConfig{{APIs: []string{"rdk:component:camera"}}},
+		},
+	}, logging.NewTestLogger(t))
+	authz.overWebRTC = func(ctx context.Context) bool { return true }
+	call := func(method string, req interface{}) error {
+		// calls over WebRTC are authenticated as the machine's host names
+		ctx := rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: "machine.local"})
+		_, err := authz.UnaryServerInterceptor(ctx, req, &googlegrpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil })
+		return err
+	}
+
+	// only roles binding WebRTC callers apply, even if others would bind the host entity
+	test.That(t, call("/viam.component.camera.v1.CameraService/GetImage", &camerapb.GetImageRequest{Name: "cam1"}), test.ShouldBeNil)
+	err := call("/viam.component.arm.v1.ArmService/MoveToPosition", &armpb.MoveToPositionRequest{Name: "arm1"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+}
+
+func TestAuthorizerMaxLeasePriority(t *testing.T) {
+	authz := newAuthorizer([]config.AuthRoleConfig{
+		{
+			Name:             "operator",
+			Entities:         []string{"operator-key"},
+			MaxLeasePriority: 5,
+			Allow:            []config.AuthRuleConfig{{}},
+		},
+		{
+			Name:             "supervisor",
+			Claims:           map[string]string{"team": "safety"},
+			MaxLeasePriority: 20,
+			Allow:            []config.AuthRuleConfig{{}},
+		},
+		{
+			Name:     "viewer",
+			Entities: []string{"viewer-key"},
+			Allow:    []config.AuthRuleConfig{{}},
+		},
+	}, logging.NewTestLogger(t))
+	maxPriority := func(entity string, claims authClaims) int {
+		ctx := rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: entity, Data: claims})
+		var priority int
+		_, err := authz.UnaryServerInterceptor(ctx, &armpb.StopRequest{Name: "arm1"},
+			&googlegrpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/Stop"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				priority = session.MaxLeasePriorityFromContext(ctx)
+				return req, nil
+			})
+		test.That(t, err, test.ShouldBeNil)
+		return priority
+	}
+
+	test.That(t, maxPriority("operator-key", nil), test.ShouldEqual, 5)
+	// callers bound to several roles get the highest priority of them
+	test.That(t, maxPriority("operator-key", authClaims{"team": "safety"}), test.ShouldEqual, 20)
+	test.That(t, maxPriority("viewer-key", nil), test.ShouldEqual, 0)
+
+	// streams are limited the same way
+	ctx := rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: "operator-key"})
+	info := &googlegrpc.StreamServerInfo{FullMethod: "/viam.component.camera.v1.CameraService/GetImage"}
+	err := authz.StreamServerInterceptor(nil, &fakeServerStream{ctx: ctx, req: &camerapb.GetImageRequest{Name: "cam1"}}, info,
+		func(srv interface{}, stream googlegrpc.ServerStream) error {
+			test.That(t, session.MaxLeasePriorityFromContext(stream.Context()), test.ShouldEqual, 5)
+			return nil
+		})
+	test.That(t, err, test.ShouldBeNil)
+}
+
+type fakeServerStream struct {
+	googlegrpc.ServerStream
+	ctx  context.Context
+	req  *camerapb.GetImageRequest
+	sent int
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	//nolint:forcetypeassert
+	m.(*camerapb.GetImageRequest).Name = s.req.GetName()
+	return nil
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent++
+	return nil
+}
+
+func TestAuthorizerStream(t *testing.T) {
+	authz := newAuthorizer([]config.AuthRoleConfig{
+		{
+			Name:     "viewer",
+			Entities: []string{"viewer-key"},
+			Allow:    []config.AuthRuleConfig{{APIs: []string{"rdk:component:camera"}, Resources: []string{"cam1"}}},
+		},
+	}, logging.NewTestLogger(t))
+	ctx := rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: "viewer-key"})
+	info := &googlegrpc.StreamServerInfo{FullMethod: "/viam.component.camera.v1.CameraService/GetImage"}
+	handler := func(srv interface{}, stream googlegrpc.ServerStream) error {
+		var req camerapb.GetImageRequest
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		return stream.SendMsg(&camerapb.GetImageResponse{})
+	}
+
+	allowed := &fakeServerStream{ctx: ctx, req: &camerapb.GetImageRequest{Name: "cam1"}}
+	test.That(t, authz.StreamServerInterceptor(nil, allowed, info, handler), test.ShouldBeNil)
+	test.That(t, allowed.sent, test.ShouldEqual, 1)
+
+	denied := &fakeServerStream{ctx: ctx, req: &camerapb.GetImageRequest{Name: "cam2"}}
+	err := authz.StreamServerInterceptor(nil, denied, info, handler)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, denied.sent, test.ShouldEqual, 0)
+
+	// the resource is unknown until a request is received, so responding first is denied
+	sendFirst := &fakeServerStream{ctx: ctx, req: &camerapb.GetImageRequest{Name: "cam1"}}
+	err = authz.StreamServerInterceptor(nil, sendFirst, info, func(srv interface{}, stream googlegrpc.ServerStream) error {
+		return stream.SendMsg(&camerapb.GetImageResponse{})
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, sendFirst.sent, test.ShouldEqual, 0)
+
+	// every request is checked, not just the first
+	switched := &fakeServerStream{ctx: ctx, req: &camerapb.GetImageRequest{Name: "cam1"}}
+	err = authz.StreamServerInterceptor(nil, switched, info, func(srv interface{}, stream googlegrpc.ServerStream) error {
+		for _, name := range []string{"cam1", "cam2"} {
+			switched.req.Name = name
+			var req camerapb.GetImageRequest
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+			if err := stream.SendMsg(&camerapb.GetImageResponse{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, switched.sent, test.ShouldEqual, 1)
+
+	// streams of methods no role allows are denied when opened
+	var handled bool
+	otherInfo := &googlegrpc.StreamServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/GetJointPositions"}
+	err = authz.StreamServerInterceptor(nil, allowed, otherInfo, func(srv interface{}, stream googlegrpc.ServerStream) error {
+		handled = true
+		return nil
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, handled, test.ShouldBeFalse)
+
+	otherInfo = &googlegrpc.StreamServerInfo{FullMethod: "/viam.robot.v1.RobotService/StreamStatus"}
+	err = authz.StreamServerInterceptor(nil, allowed, otherInfo, func(srv interface{}, stream googlegrpc.ServerStream) error {
+		handled = true
+		return nil
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, handled, test.ShouldBeFalse)
+}
+
+func TestAuthorizerAPIForService(t *testing.T) {
+	authz := newAuthorizer(nil, logging.NewTestLogger(t))
+	api, ok := authz.apiForService("viam.component.camera.v1.CameraService")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, api.String(), test.ShouldEqual, "rdk:component:camera")
+
+	// services which aren't resource APIs are remembered until the resources change
+	_, ok = authz.apiForService("viam.robot.v1.RobotService")
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, authz.otherServices, test.ShouldContainKey, "viam.robot.v1.RobotService")
+	authz.resourcesChanged()
+	test.That(t, authz.otherServices, test.ShouldBeEmpty)
+	_, ok = authz.apiForService("viam.robot.v1.RobotService")
+	test.That(t, ok, test.ShouldBeFalse)
+}
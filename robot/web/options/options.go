@@ -20,6 +20,10 @@ type Options struct {
 	// Pprof turns on the pprof profiler accessible at /debug
 	Pprof bool
 
+	// Metrics turns on the Prometheus metrics endpoint accessible at /metrics, and the recording of gRPC call metrics
+	// it serves. When auth handlers are configured, the endpoint requires a bearer access token, as gRPC calls do.
+	Metrics bool
+
 	// SharedDir is the location of static web assets.
 	SharedDir string
 
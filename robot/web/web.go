@@ -24,6 +24,7 @@ import (
 	"github.com/jhump/protoreflect/dynamic"
 	"github.com/pkg/errors"
 	"github.com/rs/cors"
+	"github.com/viamrobotics/webrtc/v3"
 	"go.opencensus.io/trace"
 	pb "go.viam.com/api/robot/v1"
 	"go.viam.com/utils"
@@ -33,10 +34,12 @@ import (
 	"goji.io"
 	"goji.io/pat"
 	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 	"go.viam.com/rdk/module"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -357,7 +360,9 @@ func (svc *webService) refreshResources() error {
 // with the correct resources, include deleting ones which have been removed from the resource graph.
 func (svc *webService) updateResources(resources map[resource.Name]resource.Resource) error {
 	groupedResources := make(map[resource.API]map[resource.Name]resource.Resource)
+	names := make([]string, 0, len(resources))
 	for n, v := range resources {
+		names = append(names, n.ShortName())
 		r, ok := groupedResources[n.API]
 		if !ok {
 			r = make(map[resource.Name]resource.Resource)
@@ -365,6 +370,10 @@ func (svc *webService) updateResources(resources map[resource.Name]resource.Reso
 		r[n] = v
 		groupedResources[n.API] = r
 	}
+	svc.grpcMetrics.SetResourceNames(names)
+	if svc.authorizer != nil {
+		svc.authorizer.resourcesChanged()
+	}
 
 	// For a given API that the web service has resources for, we get the new set of resources we should be updated with.
 	// If we find a set of resources, `coll.ReplaceAll` will do the work of adding any new resources and deleting old ones.
@@ -517,14 +526,18 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		options.SignalingAddress = svc.addr
 	}
 
+	robotServer := grpcserver.New(svc.r)
 	if err := svc.rpcServer.RegisterServiceServer(
 		ctx,
 		&pb.RobotService_ServiceDesc,
-		grpcserver.New(svc.r),
+		robotServer,
 		pb.RegisterRobotServiceHandlerFromEndpoint,
 	); err != nil {
 		return err
 	}
+	if err := svc.rpcServer.RegisterServiceServer(ctx, &grpcserver.CommandServiceDesc, robotServer); err != nil {
+		return err
+	}
 
 	if err := svc.initAPIResourceCollections(ctx, false); err != nil {
 		return err
@@ -627,8 +640,18 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 		ExternalSignalingHosts:    hosts.External,
 		InternalSignalingHosts:    hosts.Internal,
 		Config:                    &grpc.DefaultWebRTCConfiguration,
-		OnPeerAdded:               options.WebRTCOnPeerAdded,
-		OnPeerRemoved:             options.WebRTCOnPeerRemoved,
+		OnPeerAdded: func(pc *webrtc.PeerConnection) {
+			metrics.WebRTCPeers.Inc()
+			if options.WebRTCOnPeerAdded != nil {
+				options.WebRTCOnPeerAdded(pc)
+			}
+		},
+		OnPeerRemoved: func(pc *webrtc.PeerConnection) {
+			metrics.WebRTCPeers.Dec()
+			if options.WebRTCOnPeerRemoved != nil {
+				options.WebRTCOnPeerRemoved(pc)
+			}
+		},
 	}
 	if options.DisallowWebRTC {
 		webrtcOptions = rpc.WebRTCServerOptions{
@@ -677,6 +700,22 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 
 	var streamInterceptors []googlegrpc.StreamServerInterceptor
 
+	if options.Metrics {
+		unaryInterceptors = append(unaryInterceptors, svc.grpcMetrics.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, svc.grpcMetrics.StreamServerInterceptor)
+	}
+
+	if len(options.Auth.Roles) != 0 {
+		authz := newAuthorizer(options.Auth.Roles, svc.logger.Sublogger("audit"))
+		svc.authorizer = authz
+		unaryInterceptors = append(unaryInterceptors, authz.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, authz.StreamServerInterceptor)
+		if options.Auth.ExternalAuthConfig != nil {
+			rpcOpts = append(rpcOpts,
+				rpc.WithEntityDataLoader(rpc.CredentialsTypeExternal, rpc.EntityDataLoaderFunc(authz.entityDataLoader)))
+		}
+	}
+
 	opManager := svc.r.OperationManager()
 	sessManagerInts := svc.r.SessionManager().ServerInterceptors()
 	if sessManagerInts.UnaryServerInterceptor != nil {
@@ -838,6 +877,21 @@ func (svc *webService) initHTTPServer(listenerTCPAddr *net.TCPAddr, options webo
 	return httpServer, nil
 }
 
+// authenticatedHandler serves h only to requests authorized with an access token the rpc server accepts, such as one
+// returned by the Authenticate RPC, as gRPC calls must be.
+func (svc *webService) authenticatedHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := metadata.NewIncomingContext(r.Context(),
+			metadata.Pairs(rpc.MetadataFieldAuthorization, r.Header.Get(rpc.MetadataFieldAuthorization)))
+		if _, err := svc.rpcServer.EnsureAuthed(ctx); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 // Initialize multiplexer between http handlers.
 func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 	mux := goji.NewMux()
@@ -853,6 +907,14 @@ func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 		mux.HandleFunc(pat.New("/debug/pprof/trace"), pprof.Trace)
 	}
 
+	if options.Metrics {
+		if len(options.Auth.Handlers) == 0 {
+			mux.Handle(pat.New("/metrics"), metrics.Handler())
+		} else {
+			mux.Handle(pat.New("/metrics"), svc.authenticatedHandler(metrics.Handler()))
+		}
+	}
+
 	// serve resource graph visualization
 	// TODO: hide behind option
 	// TODO: accept params to display different formats
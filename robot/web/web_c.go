@@ -20,6 +20,7 @@ import (
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	weboptions "go.viam.com/rdk/robot/web/options"
@@ -51,6 +52,7 @@ func New(r robot.Robot, logger logging.Logger, opts ...Option) Service {
 		opts:         wOpts,
 		videoSources: map[string]gostream.HotSwappableVideoSource{},
 		audioSources: map[string]gostream.HotSwappableAudioSource{},
+		grpcMetrics:  metrics.NewServerInterceptors(),
 	}
 	return webSvc
 }
@@ -76,6 +78,10 @@ type webService struct {
 
 	videoSources map[string]gostream.HotSwappableVideoSource
 	audioSources map[string]gostream.HotSwappableAudioSource
+
+	grpcMetrics *metrics.ServerInterceptors
+	// authorizer enforces the auth roles, if any are configured.
+	authorizer *authorizer
 }
 
 func (svc *webService) streamInitialized() bool {
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	weboptions "go.viam.com/rdk/robot/web/options"
@@ -20,12 +21,13 @@ func New(r robot.Robot, logger logging.Logger, opts ...Option) Service {
 		opt.apply(&wOpts)
 	}
 	webSvc := &webService{
-		Named:     InternalServiceName.AsNamed(),
-		r:         r,
-		logger:    logger,
-		rpcServer: nil,
-		services:  map[resource.API]resource.APIResourceCollection[resource.Resource]{},
-		opts:      wOpts,
+		Named:       InternalServiceName.AsNamed(),
+		r:           r,
+		logger:      logger,
+		rpcServer:   nil,
+		services:    map[resource.API]resource.APIResourceCollection[resource.Resource]{},
+		opts:        wOpts,
+		grpcMetrics: metrics.NewServerInterceptors(),
 	}
 	return webSvc
 }
@@ -47,6 +49,10 @@ type webService struct {
 	isRunning  bool
 	webWorkers sync.WaitGroup
 	modWorkers sync.WaitGroup
+
+	grpcMetrics *metrics.ServerInterceptors
+	// authorizer enforces the auth roles, if any are configured.
+	authorizer *authorizer
 }
 
 // Update updates the web service when the robot has changed.
@@ -8,6 +8,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -23,6 +24,7 @@ import (
 	streampb "go.viam.com/api/stream/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
 	"go.viam.com/utils/rpc"
 	"go.viam.com/utils/testutils"
 	"google.golang.org/grpc"
@@ -42,6 +44,7 @@ import (
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
@@ -86,6 +89,31 @@ func TestWebStart(t *testing.T) {
 	test.That(t, conn.Close(), test.ShouldBeNil)
 }
 
+func TestWebCommandService(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	modules := []config.ModuleHealth{{Name: "mod1", State: config.ModuleStateRestarting, Crashes: 1, LastExitCode: 1}}
+	injectRobot.(*inject.Robot).MachineStatusFunc = func(ctx context.Context) (robot.MachineStatus, error) {
+		return robot.MachineStatus{Modules: modules}, nil
+	}
+
+	svc := web.New(injectRobot, logger)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+	defer func() {
+		test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	}()
+
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	health, err := robotClient.ModuleHealth(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health, test.ShouldResemble, modules)
+}
+
 func TestModule(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	ctx, injectRobot := setupRobotCtx(t)
@@ -733,6 +761,57 @@ func TestWebWithOnlyNewAPIKeyAuthHandlers(t *testing.T) {
 	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestWebMetricsWithAuth(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.Metrics = true
+	apiKeyID := uuid.New().String()
+	apiKey := utils.RandomAlphaString(32)
+	options.Auth.Handlers = []config.AuthHandlerConfig{
+		{
+			Type: rpc.CredentialsTypeAPIKey,
+			Config: rutils.AttributeMap{
+				apiKeyID: apiKey,
+				"keys":   []string{apiKeyID},
+			},
+		},
+	}
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+	defer func() {
+		test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	getMetrics := func(token string) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/metrics", nil)
+		test.That(t, err, test.ShouldBeNil)
+		if token != "" {
+			req.Header.Set("Authorization", rpc.AuthorizationValuePrefixBearer+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.Body.Close(), test.ShouldBeNil)
+		return resp.StatusCode
+	}
+	test.That(t, getMetrics(""), test.ShouldEqual, http.StatusUnauthorized)
+	test.That(t, getMetrics("not-a-token"), test.ShouldEqual, http.StatusUnauthorized)
+
+	conn, err := rpc.DialDirectGRPC(ctx, addr, logger, rpc.WithInsecure())
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	resp, err := rpcpb.NewAuthServiceClient(conn).Authenticate(ctx, &rpcpb.AuthenticateRequest{
+		Entity:      apiKeyID,
+		Credentials: &rpcpb.Credentials{Type: string(rpc.CredentialsTypeAPIKey), Payload: apiKey},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, getMetrics(resp.AccessToken), test.ShouldEqual, http.StatusOK)
+}
+
 func TestWebReconfigure(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	// robot is configured with an arm
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
@@ -57,6 +58,10 @@ type Capture struct {
 	captureDir string
 	// maxCaptureFileSize is only stored on Capture so that we can detect when it changs
 	maxCaptureFileSize int64
+	// maxCaptureFileAge is only stored on Capture so that we can detect when it changs
+	maxCaptureFileAge time.Duration
+	// compression is only stored on Capture so that we can detect when it changs
+	compression string
 }
 
 type (
@@ -71,6 +76,8 @@ type collectorAndConfig struct {
 	Resource  resource.Resource
 	Collector data.Collector
 	Config    datamanager.DataCaptureConfig
+	// TriggerResource is the resource the collector's trigger is evaluated with, if it has one
+	TriggerResource resource.Resource
 }
 
 // Identifier for a particular collector: component name, component model, component type,
@@ -148,6 +155,14 @@ func (c *Capture) Reconfigure(
 		c.logger.Infof("maximum_capture_file_size_bytes old: %d, new: %d", c.maxCaptureFileSize, config.MaximumCaptureFileSizeBytes)
 	}
 
+	if c.maxCaptureFileAge != config.MaximumCaptureFileAge {
+		c.logger.Infof("maximum_capture_file_age old: %s, new: %s", c.maxCaptureFileAge, config.MaximumCaptureFileAge)
+	}
+
+	if c.compression != config.Compression {
+		c.logger.Infof("capture_compression old: %q, new: %q", c.compression, config.Compression)
+	}
+
 	newCollectors := c.newCollectors(collectorConfigsByResource, config)
 	// If a component/method has been removed from the config, close the collector.
 	c.collectorsMu.Lock()
@@ -161,6 +176,8 @@ func (c *Capture) Reconfigure(
 	c.collectorsMu.Unlock()
 	c.captureDir = config.CaptureDir
 	c.maxCaptureFileSize = config.MaximumCaptureFileSizeBytes
+	c.maxCaptureFileAge = config.MaximumCaptureFileAge
+	c.compression = config.Compression
 }
 
 // Close closes the capture manager.
@@ -183,11 +200,25 @@ func (c *Capture) initializeOrUpdateCollector(
 		return nil, err
 	}
 
+	var (
+		trigger         *data.TriggerParams
+		triggerResource resource.Resource
+	)
+	if collectorConfig.Trigger != nil {
+		trigger, triggerResource, err = newTrigger(collectorConfig.Trigger, config.TriggerDependencies)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid trigger")
+		}
+	}
+
 	maxFileSizeChanged := c.maxCaptureFileSize != config.MaximumCaptureFileSizeBytes
+	maxFileAgeChanged := c.maxCaptureFileAge != config.MaximumCaptureFileAge
+	compressionChanged := c.compression != config.Compression
 	if storedCollectorAndConfig, ok := c.collectors[md]; ok {
 		if storedCollectorAndConfig.Config.Equals(&collectorConfig) &&
 			res == storedCollectorAndConfig.Resource &&
-			!maxFileSizeChanged {
+			triggerResource == storedCollectorAndConfig.TriggerResource &&
+			!maxFileSizeChanged && !maxFileAgeChanged && !compressionChanged {
 			// If the attributes have not changed, do nothing and leave the existing collector.
 			return c.collectors[md], nil
 		}
@@ -233,19 +264,26 @@ func (c *Capture) initializeOrUpdateCollector(
 		methodParams,
 		collectorConfig.Tags,
 	)
+	captureMetadata, err = data.WithCompression(captureMetadata, config.Compression)
+	if err != nil {
+		return nil, err
+	}
 	// Parameters to initialize collector.
 	queueSize := defaultIfZeroVal(collectorConfig.CaptureQueueSize, defaultCaptureQueueSize)
 	bufferSize := defaultIfZeroVal(collectorConfig.CaptureBufferSize, defaultCaptureBufferSize)
 	collector, err := collectorConstructor(res, data.CollectorParams{
 		ComponentName: collectorConfig.Name.ShortName(),
+		MethodName:    collectorConfig.Method,
 		Interval:      data.GetDurationFromHz(collectorConfig.CaptureFrequencyHz),
 		MethodParams:  methodParams,
-		Target:        data.NewCaptureBuffer(targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes),
+		Target: data.NewCaptureBufferWithMaxAge(
+			targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes, config.MaximumCaptureFileAge),
 		// Set queue size to defaultCaptureQueueSize if it was not set in the config.
 		QueueSize:  queueSize,
 		BufferSize: bufferSize,
 		Logger:     c.logger,
 		Clock:      c.clk,
+		Trigger:    trigger,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "constructor for collector %s failed with config: %s",
@@ -256,7 +294,7 @@ func (c *Capture) initializeOrUpdateCollector(
 		md, collectorConfigDescription(collectorConfig, targetDir, config.MaximumCaptureFileSizeBytes, queueSize, bufferSize))
 	collector.Collect()
 
-	return &collectorAndConfig{res, collector, collectorConfig}, nil
+	return &collectorAndConfig{res, collector, collectorConfig, triggerResource}, nil
 }
 
 func collectorConfigDescription(
@@ -266,10 +304,14 @@ func collectorConfigDescription(
 	queueSize,
 	bufferSize int,
 ) string {
+	trigger := "none"
+	if t := collectorConfig.Trigger; t != nil {
+		trigger = fmt.Sprintf("%s on %s (pre: %gs, post: %gs)", t.Type, t.Resource, t.PreTriggerSecs, t.PostTriggerSecs)
+	}
 	return fmt.Sprintf("[CaptureFrequencyHz: %f, Tags: %v, MaximumCaptureFileSize: %s, "+
-		"CaptureBufferQueueSize: %d, CaptureBufferSize: %d, TargetDir: %s]",
+		"CaptureBufferQueueSize: %d, CaptureBufferSize: %d, TargetDir: %s, Trigger: %s]",
 		collectorConfig.CaptureFrequencyHz, collectorConfig.Tags, data.FormatBytesI64(maximumCaptureFileSizeBytes),
-		queueSize, bufferSize, targetDir,
+		queueSize, bufferSize, targetDir, trigger,
 	)
 }
 
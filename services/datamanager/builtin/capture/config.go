@@ -1,5 +1,11 @@
 package capture
 
+import (
+	"time"
+
+	"go.viam.com/rdk/resource"
+)
+
 // Config is the capture config.
 type Config struct {
 	// CaptureDisabled if set to true disables all data capture collectors
@@ -12,4 +18,13 @@ type Config struct {
 	// (.prog) files should be allowed to grow to before they are convered into .capture
 	// files
 	MaximumCaptureFileSizeBytes int64
+	// MaximumCaptureFileAge defines the maximum age in progress data capture files should be
+	// allowed to reach before they are converted into .capture files. Zero disables time based
+	// rotation.
+	MaximumCaptureFileAge time.Duration
+	// Compression defines the codec data capture files are compressed with, see data.ValidateCompression.
+	Compression string
+	// TriggerDependencies are the resources the conditions of triggered collectors are evaluated with,
+	// see datamanager.CaptureTrigger.
+	TriggerDependencies resource.Dependencies
 }
@@ -0,0 +1,140 @@
+package capture
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/vision"
+)
+
+// newTrigger returns the trigger parameters of a collector configured with trigger, along with the resource its
+// condition is evaluated with.
+func newTrigger(
+	trigger *datamanager.CaptureTrigger,
+	deps resource.Dependencies,
+) (*data.TriggerParams, resource.Resource, error) {
+	if err := trigger.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		res  resource.Resource
+		f    data.TriggerFunc
+		err  error
+		name = trigger.Resource
+	)
+	switch trigger.Type {
+	case datamanager.TriggerTypeSensorThreshold:
+		var s sensor.Sensor
+		s, err = sensor.FromDependencies(deps, name)
+		res, f = s, sensorThresholdTrigger(s, trigger)
+	case datamanager.TriggerTypeVisionDetection:
+		var v vision.Service
+		v, err = vision.FromDependencies(deps, name)
+		res, f = v, visionDetectionTrigger(v, trigger)
+	case datamanager.TriggerTypeDigitalInterrupt:
+		var b board.Board
+		b, err = board.FromDependencies(deps, name)
+		if err == nil {
+			var interrupt board.DigitalInterrupt
+			interrupt, err = b.DigitalInterruptByName(trigger.Interrupt)
+			res, f = b, digitalInterruptTrigger(interrupt)
+		}
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to find %s trigger resource %s", trigger.Type, name)
+	}
+	return &data.TriggerParams{
+		Func:        f,
+		Interval:    trigger.CheckInterval(),
+		PreTrigger:  trigger.PreTrigger(),
+		PostTrigger: trigger.PostTrigger(),
+	}, res, nil
+}
+
+// sensorThresholdTrigger is met while the reading at trigger.Key is above trigger.Above or below trigger.Below.
+func sensorThresholdTrigger(s sensor.Sensor, trigger *datamanager.CaptureTrigger) data.TriggerFunc {
+	return func(ctx context.Context) (bool, error) {
+		readings, err := s.Readings(ctx, data.FromDMExtraMap)
+		if err != nil {
+			return false, err
+		}
+		reading, ok := readings[trigger.Key]
+		if !ok {
+			return false, errors.Errorf("sensor %s has no reading %s", trigger.Resource, trigger.Key)
+		}
+		value, ok := toFloat64(reading)
+		if !ok {
+			return false, errors.Errorf("sensor %s reading %s is a %T, not a number", trigger.Resource, trigger.Key, reading)
+		}
+		return (trigger.Above != nil && value > *trigger.Above) || (trigger.Below != nil && value < *trigger.Below), nil
+	}
+}
+
+// visionDetectionTrigger is met while the vision service detects an object labeled trigger.Label with at least
+// trigger.MinConfidence in the images of trigger.Camera.
+func visionDetectionTrigger(v vision.Service, trigger *datamanager.CaptureTrigger) data.TriggerFunc {
+	return func(ctx context.Context) (bool, error) {
+		detections, err := v.DetectionsFromCamera(ctx, trigger.Camera, data.FromDMExtraMap)
+		if err != nil {
+			return false, err
+		}
+		for _, d := range detections {
+			if d.Label() == trigger.Label && d.Score() >= trigger.MinConfidence {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// digitalInterruptTrigger is met when the value of the interrupt has changed since it was last checked.
+func digitalInterruptTrigger(interrupt board.DigitalInterrupt) data.TriggerFunc {
+	var (
+		last        int64
+		initialized bool
+	)
+	return func(ctx context.Context) (bool, error) {
+		value, err := interrupt.Value(ctx, data.FromDMExtraMap)
+		if err != nil {
+			return false, err
+		}
+		changed := initialized && value != last
+		last, initialized = value, true
+		return changed, nil
+	}
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,121 @@
+package capture
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestTrigger(t *testing.T) {
+	ctx := context.Background()
+	temp := 20.0
+	s := inject.NewSensor("sensor1")
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": temp, "status": "ok"}, nil
+	}
+	var detections []objectdetection.Detection
+	v := inject.NewVisionService("vision1")
+	v.DetectionsFromCameraFunc = func(
+		ctx context.Context, cameraName string, extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		return detections, nil
+	}
+	var ticks int64
+	interrupt := &inject.DigitalInterrupt{}
+	interrupt.ValueFunc = func(ctx context.Context, extra map[string]interface{}) (int64, error) {
+		return ticks, nil
+	}
+	b := inject.NewBoard("board1")
+	b.DigitalInterruptByNameFunc = func(name string) (board.DigitalInterrupt, error) {
+		return interrupt, nil
+	}
+	deps := resource.Dependencies{
+		sensor.Named("sensor1"): s,
+		vision.Named("vision1"): v,
+		board.Named("board1"):   b,
+	}
+
+	t.Run("sensor threshold", func(t *testing.T) {
+		above := 30.0
+		params, res, err := newTrigger(&datamanager.CaptureTrigger{
+			Type: datamanager.TriggerTypeSensorThreshold, Resource: "sensor1", Key: "temp", Above: &above,
+			CheckFrequencyHz: 2, PreTriggerSecs: 5, PostTriggerSecs: 10,
+		}, deps)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldEqual, s)
+		test.That(t, params.Interval, test.ShouldEqual, 500*time.Millisecond)
+		test.That(t, params.PreTrigger, test.ShouldEqual, 5*time.Second)
+		test.That(t, params.PostTrigger, test.ShouldEqual, 10*time.Second)
+
+		triggered, err := params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeFalse)
+		temp = 31
+		triggered, err = params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeTrue)
+
+		params, _, err = newTrigger(&datamanager.CaptureTrigger{
+			Type: datamanager.TriggerTypeSensorThreshold, Resource: "sensor1", Key: "status", Above: &above,
+		}, deps)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = params.Func(ctx)
+		test.That(t, err, test.ShouldBeError, "sensor sensor1 reading status is a string, not a number")
+	})
+
+	t.Run("vision detection", func(t *testing.T) {
+		params, _, err := newTrigger(&datamanager.CaptureTrigger{
+			Type: datamanager.TriggerTypeVisionDetection, Resource: "vision1", Camera: "cam1", Label: "person", MinConfidence: 0.5,
+		}, deps)
+		test.That(t, err, test.ShouldBeNil)
+		detections = []objectdetection.Detection{
+			objectdetection.NewDetection(image.Rect(0, 0, 1, 1), 0.9, "dog"),
+			objectdetection.NewDetection(image.Rect(0, 0, 1, 1), 0.4, "person"),
+		}
+		triggered, err := params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeFalse)
+		detections = append(detections, objectdetection.NewDetection(image.Rect(0, 0, 1, 1), 0.6, "person"))
+		triggered, err = params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeTrue)
+	})
+
+	t.Run("digital interrupt", func(t *testing.T) {
+		params, _, err := newTrigger(&datamanager.CaptureTrigger{
+			Type: datamanager.TriggerTypeDigitalInterrupt, Resource: "board1", Interrupt: "estop",
+		}, deps)
+		test.That(t, err, test.ShouldBeNil)
+		ticks = 3
+		// the first check only records the current value
+		triggered, err := params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeFalse)
+		ticks = 4
+		triggered, err = params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeTrue)
+		triggered, err = params.Func(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triggered, test.ShouldBeFalse)
+	})
+
+	t.Run("missing resource", func(t *testing.T) {
+		_, _, err := newTrigger(&datamanager.CaptureTrigger{
+			Type: datamanager.TriggerTypeDigitalInterrupt, Resource: "board2", Interrupt: "estop",
+		}, deps)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "failed to find digital_interrupt trigger resource board2")
+	})
+}
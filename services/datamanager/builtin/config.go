@@ -1,10 +1,14 @@
 package builtin
 
 import (
-	"errors"
 	"runtime"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/services/datamanager/builtin/capture"
@@ -39,9 +43,13 @@ type Config struct {
 	CaptureDir string   `json:"capture_dir"`
 	Tags       []string `json:"tags"`
 	// Capture
-	CaptureDisabled             bool  `json:"capture_disabled"`
-	DeleteEveryNthWhenDiskFull  int   `json:"delete_every_nth_when_disk_full"`
-	MaximumCaptureFileSizeBytes int64 `json:"maximum_capture_file_size_bytes"`
+	CaptureCompression          string  `json:"capture_compression,omitempty"`
+	CaptureDisabled             bool    `json:"capture_disabled"`
+	DeleteEveryNthWhenDiskFull  int     `json:"delete_every_nth_when_disk_full"`
+	MaximumCaptureFileAgeMins   float64 `json:"maximum_capture_file_age_mins,omitempty"`
+	MaximumCaptureFileSizeBytes int64   `json:"maximum_capture_file_size_bytes"`
+	// RetentionPolicy limits how much unsynced capture data is kept, and which files are deleted first when the disk is full
+	RetentionPolicy *datasync.RetentionPolicy `json:"retention_policy,omitempty"`
 	// Sync
 	AdditionalSyncPaths    []string `json:"additional_sync_paths"`
 	FileLastModifiedMillis int      `json:"file_last_modified_millis"`
@@ -49,9 +57,25 @@ type Config struct {
 	ScheduledSyncDisabled  bool     `json:"sync_disabled"`
 	SelectiveSyncerName    string   `json:"selective_syncer_name"`
 	SyncIntervalMins       float64  `json:"sync_interval_mins"`
+	// SyncTarget selects where data is synced to, defaults to the cloud
+	SyncTarget *datasync.TargetConfig `json:"sync_target,omitempty"`
+
+	// triggerDependencies are the resources the triggers of the associated capture methods are evaluated with.
+	triggerDependencies []string
 }
 
-// Validate returns components which will be depended upon weakly due to the above matcher.
+// AddTriggerDependencies adds resources the triggers of the associated capture methods are evaluated with to the
+// dependencies returned by Validate.
+func (c *Config) AddTriggerDependencies(names ...string) {
+	for _, name := range names {
+		if name != "" && !slices.Contains(c.triggerDependencies, name) {
+			c.triggerDependencies = append(c.triggerDependencies, name)
+		}
+	}
+}
+
+// Validate returns the internal cloud service and the resources the triggers of the associated capture methods are
+// evaluated with as dependencies. Other components are depended upon weakly due to the above matcher.
 func (c *Config) Validate(path string) ([]string, error) {
 	if c.SyncIntervalMins < 0 {
 		return nil, errors.New("sync_interval_mins can't be negative")
@@ -65,10 +89,26 @@ func (c *Config) Validate(path string) ([]string, error) {
 	if c.MaximumCaptureFileSizeBytes < 0 {
 		return nil, errors.New("maximum_capture_file_size_bytes can't be negative")
 	}
+	if c.MaximumCaptureFileAgeMins < 0 {
+		return nil, errors.New("maximum_capture_file_age_mins can't be negative")
+	}
+	if err := data.ValidateCompression(c.CaptureCompression); err != nil {
+		return nil, errors.Wrap(err, "invalid capture_compression")
+	}
 	if c.DeleteEveryNthWhenDiskFull < 0 {
 		return nil, errors.New("delete_every_nth_when_disk_full can't be negative")
 	}
-	return []string{cloud.InternalServiceName.String()}, nil
+	if c.RetentionPolicy != nil {
+		if err := c.RetentionPolicy.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid retention_policy")
+		}
+	}
+	if c.SyncTarget != nil {
+		if err := c.SyncTarget.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid sync_target")
+		}
+	}
+	return append([]string{cloud.InternalServiceName.String()}, c.triggerDependencies...), nil
 }
 
 func (c *Config) getCaptureDir() string {
@@ -89,6 +129,9 @@ func (c *Config) captureConfig() capture.Config {
 		CaptureDir:                  c.getCaptureDir(),
 		Tags:                        c.Tags,
 		MaximumCaptureFileSizeBytes: maximumCaptureFileSizeBytes,
+		// time.Duration loses precision at low floating point values, so turn the minutes to milliseconds.
+		MaximumCaptureFileAge: time.Millisecond * time.Duration(60000.0*c.MaximumCaptureFileAgeMins),
+		Compression:           c.CaptureCompression,
 	}
 }
 
@@ -118,6 +161,16 @@ func (c *Config) syncConfig(syncSensor sensor.Sensor, syncSensorEnabled bool, lo
 			c.SyncIntervalMins, syncIntervalMinsEpsilon, defaultSyncIntervalMins)
 	}
 
+	var syncTarget datasync.TargetConfig
+	if c.SyncTarget != nil {
+		syncTarget = *c.SyncTarget
+	}
+
+	var retention datasync.RetentionPolicy
+	if c.RetentionPolicy != nil {
+		retention = *c.RetentionPolicy
+	}
+
 	return datasync.Config{
 		AdditionalSyncPaths:        c.AdditionalSyncPaths,
 		Tags:                       c.Tags,
@@ -131,5 +184,7 @@ func (c *Config) syncConfig(syncSensor sensor.Sensor, syncSensorEnabled bool, lo
 		SyncIntervalMins:           syncIntervalMins,
 		SelectiveSyncSensor:        syncSensor,
 		SelectiveSyncSensorEnabled: syncSensorEnabled,
+		Target:                     syncTarget,
+		Retention:                  retention,
 	}
 }
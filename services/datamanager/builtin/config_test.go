@@ -4,9 +4,11 @@ import (
 	"errors"
 	"runtime"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/services/datamanager/builtin/capture"
@@ -17,15 +19,19 @@ import (
 var fullConfig = &Config{
 	AdditionalSyncPaths:         []string{"/tmp/a", "/tmp/b"},
 	CaptureDir:                  "/tmp/some/path",
+	CaptureCompression:          data.CompressionZstd,
 	CaptureDisabled:             true,
 	DeleteEveryNthWhenDiskFull:  2,
 	FileLastModifiedMillis:      50000,
+	MaximumCaptureFileAgeMins:   0.5,
 	MaximumCaptureFileSizeBytes: 5,
 	MaximumNumSyncThreads:       10,
 	ScheduledSyncDisabled:       true,
 	SelectiveSyncerName:         "some name",
 	SyncIntervalMins:            0.5,
 	Tags:                        []string{"a", "b", "c"},
+	SyncTarget:                  &sync.TargetConfig{Type: sync.TargetTypeLocal, Path: "/mnt/nas"},
+	RetentionPolicy:             &sync.RetentionPolicy{MaxAgeHours: 24, PriorityTags: []string{"a"}},
 }
 
 func TestConfig(t *testing.T) {
@@ -44,6 +50,11 @@ func TestConfig(t *testing.T) {
 				config: Config{},
 				deps:   []string{cloud.InternalServiceName.String()},
 			},
+			{
+				name:   "returns the resources of triggers when valid",
+				config: Config{triggerDependencies: []string{"sensor1", "vision1", "camera1"}},
+				deps:   []string{cloud.InternalServiceName.String(), "sensor1", "vision1", "camera1"},
+			},
 			{
 				name:   "returns an error if SyncIntervalMins is negative",
 				config: Config{SyncIntervalMins: -1},
@@ -69,6 +80,32 @@ func TestConfig(t *testing.T) {
 				config: Config{DeleteEveryNthWhenDiskFull: -1},
 				err:    errors.New("delete_every_nth_when_disk_full can't be negative"),
 			},
+			{
+				name:   "returns an error if MaximumCaptureFileAgeMins is negative",
+				config: Config{MaximumCaptureFileAgeMins: -1},
+				err:    errors.New("maximum_capture_file_age_mins can't be negative"),
+			},
+			{
+				name:   "returns an error if CaptureCompression is unsupported",
+				config: Config{CaptureCompression: "lz4"},
+				err: errors.New("invalid capture_compression: " +
+					"unsupported capture file compression \"lz4\", expected one of \"\", \"gzip\" or \"zstd\""),
+			},
+			{
+				name:   "returns an error if SyncTarget is missing required fields",
+				config: Config{SyncTarget: &sync.TargetConfig{Type: sync.TargetTypeS3, URL: "http://localhost:9000"}},
+				err:    errors.New("invalid sync_target: sync target of type s3 requires a url and a bucket"),
+			},
+			{
+				name:   "returns an error if SyncTarget has an unknown type",
+				config: Config{SyncTarget: &sync.TargetConfig{Type: "ftp"}},
+				err:    errors.New("invalid sync_target: unknown sync target type \"ftp\", expected one of cloud, local, s3 or http"),
+			},
+			{
+				name:   "returns an error if RetentionPolicy has a negative max age",
+				config: Config{RetentionPolicy: &sync.RetentionPolicy{MaxAgeHours: -1}},
+				err:    errors.New("invalid retention_policy: max_age_hours can't be negative"),
+			},
 		}
 
 		for _, tc := range tcs {
@@ -110,6 +147,8 @@ func TestConfig(t *testing.T) {
 				CaptureDisabled:             true,
 				CaptureDir:                  "/tmp/some/path",
 				MaximumCaptureFileSizeBytes: 5,
+				MaximumCaptureFileAge:       30 * time.Second,
+				Compression:                 data.CompressionZstd,
 				Tags:                        []string{"a", "b", "c"},
 			})
 		})
@@ -152,6 +191,8 @@ func TestConfig(t *testing.T) {
 				SelectiveSyncerName:        "some name",
 				SyncIntervalMins:           0.5,
 				Tags:                       []string{"a", "b", "c"},
+				Target:                     sync.TargetConfig{Type: sync.TargetTypeLocal, Path: "/mnt/nas"},
+				Retention:                  sync.RetentionPolicy{MaxAgeHours: 24, PriorityTags: []string{"a"}},
 			})
 		})
 	})
@@ -8,6 +8,7 @@ import (
 
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/logging"
+	datasync "go.viam.com/rdk/services/datamanager/builtin/sync"
 	"go.viam.com/rdk/utils/diskusage"
 )
 
@@ -25,10 +26,13 @@ func newDiskSummaryLogger(logger logging.Logger) *diskSummaryLogger {
 	}
 }
 
-func (poller *diskSummaryLogger) reconfigure(dirs []string, interval time.Duration) {
+// reconfigure restarts logging a summary of dirs every interval. Directories whose files exceed retention are flagged,
+// as they will have files deleted before they are synced.
+func (poller *diskSummaryLogger) reconfigure(dirs []string, interval time.Duration, retention datasync.RetentionPolicy) {
 	poller.worker.Stop()
 	poller.worker = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
 		poller.logger.Info("datamanager disk state summary logger starting...")
+		poller.logger.Infof("datamanager retention policy: %s", retention)
 		t := time.NewTicker(interval)
 		defer t.Stop()
 		for {
@@ -57,9 +61,11 @@ func (poller *diskSummaryLogger) reconfigure(dirs []string, interval time.Durati
 						dataTimeRange string
 						dataStart     string
 						dataEnd       string
+						oldest        time.Time
 					)
 					if s.DataTimeRange != nil {
 						dtr := *s.DataTimeRange
+						oldest = dtr.Start
 						dataTimeRange = dtr.End.Sub(dtr.Start).Round(time.Second).String()
 						dataStart = dtr.Start.String()
 						dataEnd = dtr.End.String()
@@ -70,6 +76,7 @@ func (poller *diskSummaryLogger) reconfigure(dirs []string, interval time.Durati
 						"data_time_range", dataTimeRange,
 						"data_start", dataStart,
 						"data_end", dataEnd,
+						"exceeds_retention", retention.Exceeds(s.FileSize, oldest, time.Now()),
 						"err", s.Err)
 				}
 			}
@@ -85,6 +85,13 @@ type Config struct {
 	// unil the Readings method of the SelectiveSyncSensor (when called on the SyncIntervalMins interval) returns
 	// the a key of datamanager.ShouldSyncKey and a value of `true`
 	SelectiveSyncSensor sensor.Sensor
+	// Target selects where files are synced to.
+	// defaults to the cloud
+	Target TargetConfig
+	// Retention, when enabled, replaces deleting every DeleteEveryNthWhenDiskFull-th file when the
+	// disk is full with deleting the oldest files first, and limits the size and age of the
+	// capture files of each collector.
+	Retention RetentionPolicy
 }
 
 func (c Config) schedulerEnabled() bool {
@@ -106,7 +113,9 @@ func (c Config) Equal(o Config) bool {
 		c.SyncIntervalMins == o.SyncIntervalMins &&
 		reflect.DeepEqual(c.Tags, o.Tags) &&
 		c.SelectiveSyncSensorEnabled == o.SelectiveSyncSensorEnabled &&
-		c.SelectiveSyncSensor == o.SelectiveSyncSensor
+		c.SelectiveSyncSensor == o.SelectiveSyncSensor &&
+		reflect.DeepEqual(c.Target, o.Target) &&
+		reflect.DeepEqual(c.Retention, o.Retention)
 }
 
 func (c *Config) logDiff(o Config, logger logging.Logger) {
@@ -173,6 +182,14 @@ func (c *Config) logDiff(o Config, logger logging.Logger) {
 		}
 		logger.Infof("SelectiveSyncSensor: old: %s, new: %s", oldName, newName)
 	}
+
+	if !reflect.DeepEqual(c.Target, o.Target) {
+		logger.Infof("sync_target: old: %s, new: %s", c.Target, o.Target)
+	}
+
+	if !reflect.DeepEqual(c.Retention, o.Retention) {
+		logger.Infof("retention_policy: old: %s, new: %s", c.Retention, o.Retention)
+	}
 }
 
 // SyncPaths returns the capture directory and additional sync paths as a slice.
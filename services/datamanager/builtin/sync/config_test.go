@@ -177,6 +177,16 @@ func TestConfig(t *testing.T) {
 				},
 				equal: false,
 			},
+			{
+				name: "different Target are not equal",
+				a: Config{
+					Target: TargetConfig{Type: TargetTypeLocal, Path: "/mnt/a"},
+				},
+				b: Config{
+					Target: TargetConfig{Type: TargetTypeLocal, Path: "/mnt/b"},
+				},
+				equal: false,
+			},
 		}
 
 		for _, tc := range tcs {
@@ -168,6 +168,10 @@ func getNextWait(lastWait time.Duration, isOffline bool) time.Duration {
 // terminalError returns true if retrying will never succeed so that
 // the data gets moved to the corrupted data directory and false otherwise.
 func terminalError(err error) bool {
+	var targetErr *terminalTargetError
+	if errors.As(err, &targetErr) {
+		return true
+	}
 	errStatus := status.Convert(err)
 	return errStatus.Code() == codes.InvalidArgument || errors.Is(err, proto.Error)
 }
@@ -31,6 +31,7 @@ func deleteExcessFilesOnSchedule(
 	fileTracker *fileTracker,
 	captureDir string,
 	deleteEveryNth int,
+	policy RetentionPolicy,
 	clock clock.Clock,
 	logger logging.Logger,
 ) {
@@ -38,6 +39,11 @@ func deleteExcessFilesOnSchedule(
 		logger.Debug("file deletion if disk is full is not currently supported on Android")
 		return
 	}
+	// the retention is kept across ticks so that it only reads the tags of each capture file once
+	var r *retention
+	if policy.enabled() {
+		r = &retention{policy: policy, fileTracker: fileTracker, clock: clock, logger: logger}
+	}
 	t := clock.Ticker(CheckDeleteExcessFilesInterval)
 	defer t.Stop()
 	for {
@@ -49,7 +55,7 @@ func deleteExcessFilesOnSchedule(
 		case <-ctx.Done():
 			return
 		case <-t.C:
-			maybeDeleteExcessFiles(ctx, fileTracker, captureDir, deleteEveryNth, clock, logger)
+			maybeDeleteExcessFiles(ctx, fileTracker, captureDir, deleteEveryNth, r, clock, logger)
 		}
 	}
 }
@@ -59,6 +65,7 @@ func maybeDeleteExcessFiles(
 	fileTracker *fileTracker,
 	captureDir string,
 	deleteEveryNth int,
+	r *retention,
 	clock clock.Clock,
 	logger logging.Logger,
 ) {
@@ -75,19 +82,26 @@ func maybeDeleteExcessFiles(
 		logger.Error("captureDir partition has size zero")
 		return
 	}
-	deletedFileCount, err := deleteExcessFiles(
-		ctx,
-		fileTracker,
-		usage,
-		captureDir,
-		deleteEveryNth,
-		logger)
+	var deletedFileCount int
+	if r != nil {
+		deletedFileCount, err = r.apply(ctx, captureDir, usage)
+	} else {
+		deletedFileCount, err = deleteExcessFiles(
+			ctx,
+			fileTracker,
+			usage,
+			captureDir,
+			deleteEveryNth,
+			logger)
+	}
 
 	duration := clock.Since(start)
 
 	switch {
 	case err != nil:
 		logger.Errorw("error deleting cached datacapture files", "error", err, "execution time", duration.String())
+	case deletedFileCount > 0 && r != nil && r.policy.DryRun:
+		logger.Infof("retention policy dry run: %d files would have been deleted, execution time: %s", deletedFileCount, duration.String())
 	case deletedFileCount > 0:
 		logger.Infof("%d files have been deleted to avoid the disk filling up, execution time: %s", deletedFileCount, duration.String())
 	default:
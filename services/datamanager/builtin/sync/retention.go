@@ -0,0 +1,297 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils/diskusage"
+)
+
+// RetentionPolicy defines which completed data capture files the data manager deletes before they are synced.
+// When a RetentionPolicy is configured, files are deleted oldest first once the disk is full, rather than
+// deleting every DeleteEveryNthWhenDiskFull-th file.
+type RetentionPolicy struct {
+	// MaxBytesPerCollector is the maximum total size of the completed capture files of a single collector (i.e. in a
+	// single directory). The oldest files of collectors over the limit are deleted. Zero means no limit.
+	MaxBytesPerCollector int64 `json:"max_bytes_per_collector,omitempty"`
+	// MaxAgeHours is the maximum age of a completed capture file, measured from when it was last written.
+	// Older files are deleted. Zero means no limit.
+	MaxAgeHours float64 `json:"max_age_hours,omitempty"`
+	// PriorityTags are capture tags whose files are only deleted once every file without any of them has been.
+	PriorityTags []string `json:"priority_tags,omitempty"`
+	// DryRun, when true, logs the files which would be deleted without deleting them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// Validate returns an error if the RetentionPolicy is invalid.
+func (p RetentionPolicy) Validate() error {
+	if p.MaxBytesPerCollector < 0 {
+		return errors.New("max_bytes_per_collector can't be negative")
+	}
+	if p.MaxAgeHours < 0 {
+		return errors.New("max_age_hours can't be negative")
+	}
+	return nil
+}
+
+// MaxAge returns MaxAgeHours as a time.Duration.
+func (p RetentionPolicy) MaxAge() time.Duration {
+	// time.Duration loses precision at low floating point values, so turn hours to milliseconds.
+	return time.Millisecond * time.Duration(3600000.0*p.MaxAgeHours)
+}
+
+// Exceeds returns true if the files summarized by size and oldest, the time the oldest of them was written, exceed
+// the policy at now.
+func (p RetentionPolicy) Exceeds(size int64, oldest, now time.Time) bool {
+	if p.MaxBytesPerCollector > 0 && size > p.MaxBytesPerCollector {
+		return true
+	}
+	return p.MaxAgeHours > 0 && !oldest.IsZero() && now.Sub(oldest) > p.MaxAge()
+}
+
+func (p RetentionPolicy) enabled() bool {
+	return p.MaxBytesPerCollector > 0 || p.MaxAgeHours > 0 || len(p.PriorityTags) > 0 || p.DryRun
+}
+
+func (p RetentionPolicy) String() string {
+	if !p.enabled() {
+		return "none"
+	}
+	return fmt.Sprintf("max_bytes_per_collector: %s, max_age_hours: %g, priority_tags: [%s], dry_run: %t",
+		data.FormatBytesI64(p.MaxBytesPerCollector), p.MaxAgeHours, strings.Join(p.PriorityTags, " "), p.DryRun)
+}
+
+// retentionCandidate is a completed capture file which a RetentionPolicy may delete.
+type retentionCandidate struct {
+	path     string
+	size     int64
+	modTime  time.Time
+	priority bool
+	deleted  bool
+}
+
+// retention applies a RetentionPolicy to a capture directory.
+type retention struct {
+	policy      RetentionPolicy
+	fileTracker *fileTracker
+	clock       clock.Clock
+	logger      logging.Logger
+	// deleted counts the files deleted by the current apply, or which would have been in a dry run
+	deleted int
+	// priority caches whether each completed capture file has a priority tag, since completed files don't change
+	priority map[string]bool
+}
+
+// apply deletes expired files, then the oldest files of collectors over their size limit, then, if usage shows that the
+// disk is full, the oldest remaining files until it no longer is. Files with priority tags are deleted last.
+func (r *retention) apply(ctx context.Context, captureDir string, usage diskusage.DiskUsage) (int, error) {
+	r.deleted = 0
+	candidates, captureDirSize, err := r.listCandidates(ctx, captureDir)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.policy.MaxAgeHours > 0 {
+		cutoff := r.clock.Now().Add(-r.policy.MaxAge())
+		for _, c := range candidates {
+			if c.modTime.Before(cutoff) {
+				r.remove(c, "older than max_age_hours")
+			}
+		}
+	}
+
+	if r.policy.MaxBytesPerCollector > 0 {
+		byCollector := map[string][]*retentionCandidate{}
+		collectorSize := map[string]int64{}
+		for _, c := range candidates {
+			if c.deleted {
+				continue
+			}
+			dir := filepath.Dir(c.path)
+			byCollector[dir] = append(byCollector[dir], c)
+			collectorSize[dir] += c.size
+		}
+		for dir, files := range byCollector {
+			for _, c := range files {
+				if collectorSize[dir] <= r.policy.MaxBytesPerCollector {
+					break
+				}
+				if r.remove(c, "collector exceeds max_bytes_per_collector") {
+					collectorSize[dir] -= c.size
+				}
+			}
+		}
+	}
+
+	if toFree := bytesToFree(usage, captureDirSize, candidates); toFree > 0 {
+		r.logger.Warnf("current disk usage of the data capture directory exceeds threshold (%f), deleting oldest files first",
+			CaptureDirToFSUsageRatio)
+		for _, c := range candidates {
+			if toFree <= 0 {
+				break
+			}
+			if !c.deleted && r.remove(c, "disk is full") {
+				toFree -= c.size
+			}
+		}
+	}
+	return r.deleted, ctx.Err()
+}
+
+// listCandidates returns the completed capture files in captureDir in the order they should be deleted: those
+// without priority tags before those with them, oldest first. It also returns the total size of the directory.
+func (r *retention) listCandidates(ctx context.Context, captureDir string) ([]*retentionCandidate, int64, error) {
+	var (
+		candidates []*retentionCandidate
+		dirSize    int64
+		// only files still in the directory stay cached
+		priority = map[string]bool{}
+	)
+	err := filepath.WalkDir(captureDir, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// files can be renamed or synced and deleted while we walk the directory
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			// files which could not be synced are never retried, so they are not subject to retention
+			if d.Name() == FailedDir && path != captureDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		dirSize += info.Size()
+		if filepath.Ext(path) != data.CompletedCaptureFileExt {
+			return nil
+		}
+		isPriority, ok := r.priority[path]
+		if !ok {
+			isPriority, ok = r.hasPriorityTag(path)
+		}
+		if ok {
+			priority[path] = isPriority
+		}
+		candidates = append(candidates, &retentionCandidate{
+			path:     path,
+			size:     info.Size(),
+			modTime:  info.ModTime(),
+			priority: isPriority,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	r.priority = priority
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return !candidates[i].priority
+		}
+		return candidates[i].modTime.Before(candidates[j].modTime)
+	})
+	return candidates, dirSize, nil
+}
+
+// hasPriorityTag returns whether the capture file at path has a priority tag, and false if that could not be
+// determined because the file could not be read.
+func (r *retention) hasPriorityTag(path string) (bool, bool) {
+	if len(r.policy.PriorityTags) == 0 {
+		return false, true
+	}
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return false, false
+	}
+	defer goutils.UncheckedErrorFunc(f.Close)
+	captureFile, err := data.ReadCaptureFile(f)
+	if err != nil {
+		// files whose metadata can't be read will be moved to the failed directory by sync
+		return false, false
+	}
+	for _, tag := range captureFile.ReadMetadata().GetTags() {
+		for _, priorityTag := range r.policy.PriorityTags {
+			if tag == priorityTag {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
+
+// remove deletes c, or logs that it would have in a dry run. It returns false if c could not be deleted, such as
+// when it is being synced.
+func (r *retention) remove(c *retentionCandidate, reason string) bool {
+	if r.policy.DryRun {
+		r.logger.Infof("retention policy dry run: would delete %s (%s), reason: %s", c.path, data.FormatBytesI64(c.size), reason)
+		c.deleted = true
+		r.deleted++
+		return true
+	}
+	if !r.fileTracker.markInProgress(c.path) {
+		r.logger.Debugw("Tried to mark file as in progress but lock already held", "file", c.path)
+		return false
+	}
+	defer r.fileTracker.unmarkInProgress(c.path)
+	if err := os.Remove(c.path); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			r.logger.Warnw("error deleting file", "error", err)
+		}
+		return false
+	}
+	r.logger.Infof("deleted %s (%s), reason: %s", c.path, data.FormatBytesI64(c.size), reason)
+	c.deleted = true
+	r.deleted++
+	return true
+}
+
+// bytesToFree returns the number of bytes which need to be deleted from the capture directory for the disk to no longer
+// be considered full, or 0 if it isn't. Files already deleted by the retention policy are accounted for.
+func bytesToFree(usage diskusage.DiskUsage, captureDirSize int64, candidates []*retentionCandidate) int64 {
+	if usage.SizeBytes == 0 {
+		return 0
+	}
+	var freed int64
+	for _, c := range candidates {
+		if c.deleted {
+			freed += c.size
+		}
+	}
+	size := float64(usage.SizeBytes)
+	used := size - float64(usage.AvailableBytes) - float64(freed)
+	captureDirSize -= freed
+	if used/size < FSThresholdToTriggerDeletion || float64(captureDirSize)/size < CaptureDirToFSUsageRatio {
+		return 0
+	}
+	// deleting enough to satisfy either threshold means the disk is no longer full
+	overFS := used - FSThresholdToTriggerDeletion*size
+	overCaptureDir := float64(captureDirSize) - CaptureDirToFSUsageRatio*size
+	if overCaptureDir < overFS {
+		return int64(overCaptureDir) + 1
+	}
+	return int64(overFS) + 1
+}
@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils/diskusage"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	test.That(t, RetentionPolicy{}.Validate(), test.ShouldBeNil)
+	test.That(t, RetentionPolicy{MaxBytesPerCollector: -1}.Validate(), test.ShouldBeError,
+		"max_bytes_per_collector can't be negative")
+	test.That(t, RetentionPolicy{MaxAgeHours: -1}.Validate(), test.ShouldBeError, "max_age_hours can't be negative")
+
+	test.That(t, RetentionPolicy{}.enabled(), test.ShouldBeFalse)
+	test.That(t, RetentionPolicy{}.String(), test.ShouldEqual, "none")
+	test.That(t, RetentionPolicy{DryRun: true}.enabled(), test.ShouldBeTrue)
+	test.That(t, RetentionPolicy{MaxAgeHours: 0.5}.MaxAge(), test.ShouldEqual, 30*time.Minute)
+
+	now := time.Now()
+	p := RetentionPolicy{MaxBytesPerCollector: 100, MaxAgeHours: 1}
+	test.That(t, p.Exceeds(100, now.Add(-time.Minute), now), test.ShouldBeFalse)
+	test.That(t, p.Exceeds(101, now.Add(-time.Minute), now), test.ShouldBeTrue)
+	test.That(t, p.Exceeds(1, now.Add(-2*time.Hour), now), test.ShouldBeTrue)
+	test.That(t, p.Exceeds(1, time.Time{}, now), test.ShouldBeFalse)
+	test.That(t, RetentionPolicy{}.Exceeds(1000, now.Add(-time.Hour*1000), now), test.ShouldBeFalse)
+}
+
+func TestRetention(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// the size of the files written by writeFiles
+	const fileSize = 24
+	notFull := diskusage.DiskUsage{SizeBytes: 1 << 40, AvailableBytes: 1 << 39}
+
+	tests := []struct {
+		name     string
+		policy   RetentionPolicy
+		usage    diskusage.DiskUsage
+		files    map[string][]string
+		ages     map[string]time.Duration
+		inUse    []string
+		expected []string
+	}{
+		{
+			name:   "files older than max age are deleted",
+			policy: RetentionPolicy{MaxAgeHours: 1},
+			usage:  notFull,
+			files:  map[string][]string{"a": {"0.capture", "1.capture", "2.prog"}},
+			ages:   map[string]time.Duration{"0.capture": 2 * time.Hour, "1.capture": time.Minute, "2.prog": 2 * time.Hour},
+			expected: []string{
+				"a/1.capture", "a/2.prog",
+			},
+		},
+		{
+			name:   "the oldest files of collectors over max bytes are deleted",
+			policy: RetentionPolicy{MaxBytesPerCollector: 2 * fileSize},
+			usage:  notFull,
+			files: map[string][]string{
+				"a": {"0.capture", "1.capture", "2.capture", "3.capture"},
+				"b": {"0.capture", "1.capture"},
+			},
+			ages: map[string]time.Duration{
+				"0.capture": time.Minute, "1.capture": 4 * time.Minute, "2.capture": 3 * time.Minute, "3.capture": 2 * time.Minute,
+			},
+			expected: []string{"a/0.capture", "a/3.capture", "b/0.capture", "b/1.capture"},
+		},
+		{
+			name:   "files being synced are not deleted",
+			policy: RetentionPolicy{MaxAgeHours: 1},
+			usage:  notFull,
+			files:  map[string][]string{"a": {"0.capture", "1.capture"}},
+			ages:   map[string]time.Duration{"0.capture": 2 * time.Hour, "1.capture": 2 * time.Hour},
+			inUse:  []string{"a/0.capture"},
+			expected: []string{
+				"a/0.capture",
+			},
+		},
+		{
+			name:   "when the disk is full the oldest files are deleted until it no longer is",
+			policy: RetentionPolicy{MaxAgeHours: 100},
+			// 100 bytes used of 100, the first file deleted brings usage below 90%
+			usage: diskusage.DiskUsage{SizeBytes: 100},
+			files: map[string][]string{"a": {"0.capture", "1.capture"}, "b": {"0.capture", "1.capture"}},
+			ages: map[string]time.Duration{
+				"0.capture": time.Minute, "1.capture": 2 * time.Minute,
+			},
+			expected: []string{"a/0.capture", "a/1.capture", "b/0.capture"},
+		},
+		{
+			name:     "dry run deletes nothing",
+			policy:   RetentionPolicy{MaxAgeHours: 1, MaxBytesPerCollector: 1, DryRun: true},
+			usage:    diskusage.DiskUsage{SizeBytes: 100},
+			files:    map[string][]string{"a": {"0.capture", "1.capture"}},
+			ages:     map[string]time.Duration{"0.capture": 2 * time.Hour, "1.capture": 2 * time.Hour},
+			expected: []string{"a/0.capture", "a/1.capture"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			captureDir := t.TempDir()
+			mockClock := clock.NewMock()
+			mockClock.Set(now)
+			ft := newFileTracker()
+			for dir, names := range tc.files {
+				test.That(t, os.Mkdir(filepath.Join(captureDir, dir), 0o700), test.ShouldBeNil)
+				for name, path := range writeFiles(t, filepath.Join(captureDir, dir), names) {
+					// the files of collector b are a second older than those of a
+					modTime := now.Add(-tc.ages[name])
+					if dir == "b" {
+						modTime = modTime.Add(-time.Second)
+					}
+					test.That(t, os.Chtimes(path, modTime, modTime), test.ShouldBeNil)
+				}
+			}
+			for _, path := range tc.inUse {
+				ft.markInProgress(filepath.Join(captureDir, path))
+			}
+
+			r := &retention{policy: tc.policy, fileTracker: ft, clock: mockClock, logger: logging.NewTestLogger(t)}
+			_, err := r.apply(context.Background(), captureDir, tc.usage)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, remainingFiles(t, captureDir), test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestRetentionPriorityTags(t *testing.T) {
+	captureDir := t.TempDir()
+	now := time.Now()
+	writeCaptureFile := func(tags []string, age time.Duration) string {
+		f, err := data.NewCaptureFile(captureDir, &v1.DataCaptureMetadata{MethodName: "Readings", Tags: tags})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.WriteNext(&v1.SensorData{Metadata: &v1.SensorMetadata{}}), test.ShouldBeNil)
+		test.That(t, f.Close(), test.ShouldBeNil)
+		path := strings.TrimSuffix(f.GetPath(), data.InProgressCaptureFileExt) + data.CompletedCaptureFileExt
+		test.That(t, os.Chtimes(path, now.Add(-age), now.Add(-age)), test.ShouldBeNil)
+		return filepath.Base(path)
+	}
+	oldestImportant := writeCaptureFile([]string{"important"}, 3*time.Minute)
+	older := writeCaptureFile(nil, 2*time.Minute)
+	newer := writeCaptureFile([]string{"other"}, time.Minute)
+
+	r := &retention{
+		policy:      RetentionPolicy{MaxBytesPerCollector: 1, PriorityTags: []string{"important"}},
+		fileTracker: newFileTracker(),
+		clock:       clock.New(),
+		logger:      logging.NewTestLogger(t),
+	}
+	candidates, _, err := r.listCandidates(context.Background(), captureDir)
+	test.That(t, err, test.ShouldBeNil)
+	var order []string
+	for _, c := range candidates {
+		order = append(order, filepath.Base(c.path))
+	}
+	// files with priority tags are deleted last, even when they are the oldest
+	test.That(t, order, test.ShouldResemble, []string{older, newer, oldestImportant})
+
+	// tags are only read once per file, so a file which can no longer be read keeps its priority
+	test.That(t, os.WriteFile(filepath.Join(captureDir, oldestImportant), []byte("not a capture file"), 0o600), test.ShouldBeNil)
+	candidates, _, err = r.listCandidates(context.Background(), captureDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filepath.Base(candidates[2].path), test.ShouldEqual, oldestImportant)
+	test.That(t, candidates[2].priority, test.ShouldBeTrue)
+
+	deleted, err := r.apply(context.Background(), captureDir, diskusage.DiskUsage{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deleted, test.ShouldEqual, 3)
+	test.That(t, getFileNames(t, captureDir), test.ShouldBeEmpty)
+
+	// deleted files are dropped from the cache
+	_, _, err = r.listCandidates(context.Background(), captureDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, r.priority, test.ShouldBeEmpty)
+}
+
+// remainingFiles returns the paths of the files in dir relative to it.
+func remainingFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, rel)
+		return err
+	})
+	test.That(t, err, test.ShouldBeNil)
+	return files
+}
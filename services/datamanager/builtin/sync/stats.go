@@ -11,6 +11,14 @@ import (
 
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/metrics"
+)
+
+// The kinds of files uploaded, as reported in the sync metrics.
+const (
+	binaryKind    = "binary"
+	tabularKind   = "tabular"
+	arbitraryKind = "arbitrary"
 )
 
 type atomicUploadStats struct {
@@ -25,6 +33,20 @@ type atomicStat struct {
 	uploadFailedFileCount atomic.Uint64
 }
 
+// uploaded records a file of the given kind being uploaded.
+func (s *atomicStat) uploaded(kind string, bytes uint64) {
+	s.uploadedFileCount.Add(1)
+	s.uploadedBytes.Add(bytes)
+	metrics.SyncUploadedFiles.WithLabelValues(kind).Inc()
+	metrics.SyncUploadedBytes.WithLabelValues(kind).Add(float64(bytes))
+}
+
+// uploadFailed records a file of the given kind failing to be uploaded.
+func (s *atomicStat) uploadFailed(kind string) {
+	s.uploadFailedFileCount.Add(1)
+	metrics.SyncUploadFailures.WithLabelValues(kind).Inc()
+}
+
 type uploadStats struct {
 	binary    stat
 	tabular   stat
@@ -97,9 +119,9 @@ func perSecond(v uint64, interval time.Duration) float64 {
 
 func summary(oldState, newState uploadStats, interval time.Duration) []string {
 	summary := totalSummary(oldState, newState, interval)
-	summary = summarizeStat(summary, "arbitrary", oldState.arbitrary, newState.arbitrary, interval)
-	summary = summarizeStat(summary, "binary", oldState.binary, newState.binary, interval)
-	summary = summarizeStat(summary, "tabular", oldState.tabular, newState.tabular, interval)
+	summary = summarizeStat(summary, arbitraryKind, oldState.arbitrary, newState.arbitrary, interval)
+	summary = summarizeStat(summary, binaryKind, oldState.binary, newState.binary, interval)
+	summary = summarizeStat(summary, tabularKind, oldState.tabular, newState.tabular, interval)
 	return summary
 }
 
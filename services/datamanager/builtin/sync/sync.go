@@ -18,7 +18,6 @@ import (
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
 
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal/cloud"
@@ -44,7 +43,7 @@ const (
 )
 
 // Sync manages uploading files (both written by data capture and by 3rd party applications)
-// to the cloud & deleting the upload files.
+// to the cloud, or another configured Target, & deleting the upload files.
 // It also manages deleting files if capture is enabled and the disk is about to fill up.
 // There must be only one Sync per DataManager. The lifecycle of a Sync is:
 //
@@ -66,6 +65,8 @@ type Sync struct {
 
 	configMu sync.Mutex
 	config   Config
+	// target is nil if the configured target is invalid
+	target Target
 
 	configCtx        context.Context
 	configCancelFunc func()
@@ -121,7 +122,7 @@ func New(
 func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.ConnectionService) {
 	s.logger.Debug("Reconfigure START")
 	defer s.logger.Debug("Reconfigure END")
-	if s.cloudConnManager == nil {
+	if s.cloudConnManager == nil && config.Target.isCloud() {
 		s.cloudConnManager = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
 			s.runCloudConnManager(ctx, cloudConnSvc)
 		})
@@ -146,16 +147,24 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 	// wait for workers to stop
 	s.workersWg.Wait()
 
+	target, err := newTarget(config.Target, &s.cloudConn)
+	if err != nil {
+		s.logger.Errorw("data manager can't sync as its sync target is invalid", "error", err)
+	}
+
 	// update config
 	s.configMu.Lock()
 	s.config = config
+	s.target = target
 	s.configMu.Unlock()
 	// reset config context
 	s.configCtx, s.configCancelFunc = context.WithCancel(context.Background())
 
 	// start workers
-	s.startWorkers(config)
-	if config.schedulerEnabled() {
+	if target != nil {
+		s.startWorkers(config, target)
+	}
+	if target != nil && config.schedulerEnabled() {
 		// time.Duration loses precision at low floating point values, so turn intervalMins to milliseconds.
 		intervalMillis := 60000.0 * config.SyncIntervalMins
 		// The ticker must be created before uploadData returns to prevent race conditions between clock.Ticker and
@@ -164,15 +173,15 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 		tkr := s.clock.Ticker(interval)
 		s.ScheduledTicker = tkr
 		s.Scheduler = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
-			s.runScheduler(ctx, tkr, config)
+			s.runScheduler(ctx, tkr, config, target)
 		})
 	} else {
 		s.logger.Info("Sync Disabled")
 	}
 
-	// if datacapture is enabled, kick off a go routine to handle disk space filling due to
-	// cached datacapture files
-	shouldDeleteExcessFiles := !config.CaptureDisabled
+	// if datacapture is enabled or a retention policy is configured, kick off a go routine to
+	// handle disk space filling due to cached datacapture files
+	shouldDeleteExcessFiles := !config.CaptureDisabled || config.Retention.enabled()
 	if shouldDeleteExcessFiles {
 		s.FileDeletingWorkers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
 			deleteExcessFilesOnSchedule(
@@ -180,6 +189,7 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 				s.fileTracker,
 				config.CaptureDir,
 				config.DeleteEveryNthWhenDiskFull,
+				config.Retention,
 				s.clock,
 				s.logger,
 			)
@@ -211,14 +221,21 @@ func (s *Sync) CloudConnReady() chan struct{} {
 // If automated sync is also enabled, calling Sync will upload the files,
 // regardless of whether or not is the scheduled time.
 func (s *Sync) Sync(ctx context.Context, _ map[string]interface{}) error {
-	select {
-	case <-s.cloudConn.ready:
-	default:
-		return errors.New("not connected to the cloud")
-	}
 	s.configMu.Lock()
 	config := s.config
+	target := s.target
 	s.configMu.Unlock()
+	if target == nil {
+		return errors.New("sync target is invalid")
+	}
+	select {
+	case <-target.Ready():
+	default:
+		if config.Target.isCloud() {
+			return errors.New("not connected to the cloud")
+		}
+		return fmt.Errorf("sync target %s is not ready", config.Target)
+	}
 	return s.walkDirsAndSendFilesToSync(ctx, config)
 }
 
@@ -310,17 +327,17 @@ func newCloudConn(
 
 // BEGIN sync workers
 // Assumed to be called after reconfigure is called.
-func (s *Sync) startWorkers(config Config) {
+func (s *Sync) startWorkers(config Config, target Target) {
 	numThreads := config.MaximumNumSyncThreads
 	s.MaxSyncThreads = numThreads
 	s.logger.Infof("starting sync worker pool of size: %d", numThreads)
 	for i := 0; i < numThreads; i++ {
 		s.workersWg.Add(1)
-		goutils.ManagedGo(func() { s.runWorker(config) }, s.workersWg.Done)
+		goutils.ManagedGo(func() { s.runWorker(config, target) }, s.workersWg.Done)
 	}
 }
 
-func (s *Sync) runWorker(config Config) {
+func (s *Sync) runWorker(config Config, target Target) {
 	for {
 		if s.configCtx.Err() != nil {
 			return
@@ -329,12 +346,12 @@ func (s *Sync) runWorker(config Config) {
 		case <-s.configCtx.Done():
 			return
 		case path := <-s.filesToSync:
-			s.syncFile(config, path)
+			s.syncFile(config, target, path)
 		}
 	}
 }
 
-func (s *Sync) syncFile(config Config, filePath string) {
+func (s *Sync) syncFile(config Config, target Target, filePath string) {
 	// don't sync in progress files
 	if filepath.Ext(filePath) == data.InProgressCaptureFileExt {
 		s.logger.Warn("ignoreing request to sync in progress capture file: %s", filePath)
@@ -359,14 +376,15 @@ func (s *Sync) syncFile(config Config, filePath string) {
 		return
 	}
 
+	name := targetFileName(config, filePath)
 	if data.IsDataCaptureFile(f) {
-		s.syncDataCaptureFile(f, config.CaptureDir, s.logger)
+		s.syncDataCaptureFile(f, target, name, config.CaptureDir, s.logger)
 	} else {
-		s.syncArbitraryFile(f, config.Tags, config.FileLastModifiedMillis, s.logger)
+		s.syncArbitraryFile(f, target, name, config.Tags, config.FileLastModifiedMillis, s.logger)
 	}
 }
 
-func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging.Logger) {
+func (s *Sync) syncDataCaptureFile(f *os.File, target Target, name, captureDir string, logger logging.Logger) {
 	captureFile, err := data.ReadCaptureFile(f)
 	// if you can't read the capture file's metadata field, close & move it to the failed directory
 	if err != nil {
@@ -379,7 +397,7 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 		if err := moveFailedData(f.Name(), captureDir, cause, logger); err != nil {
 			s.logger.Error(err)
 		}
-		s.atomicUploadStats.tabular.uploadFailedFileCount.Add(1)
+		s.atomicUploadStats.tabular.uploadFailed(tabularKind)
 		return
 	}
 	isBinary := captureFile.ReadMetadata().GetType() == v1.DataType_DATA_TYPE_BINARY_SENSOR
@@ -388,7 +406,7 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 	retry := newExponentialRetry(s.configCtx, s.clock, s.logger, f.Name(), func(ctx context.Context) (uint64, error) {
 		msg := "error uploading data capture file %s, size: %s, md: %s"
 		errMetadata := fmt.Sprintf(msg, captureFile.GetPath(), data.FormatBytesI64(captureFile.Size()), captureFile.ReadMetadata())
-		bytesUploaded, err := uploadDataCaptureFile(ctx, captureFile, s.cloudConn, logger)
+		bytesUploaded, err := target.UploadDataCaptureFile(ctx, captureFile, name, logger)
 		if err != nil {
 			return 0, errors.Wrap(err, errMetadata)
 		}
@@ -414,9 +432,9 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 			logger.Error(err)
 		}
 		if isBinary {
-			s.atomicUploadStats.binary.uploadFailedFileCount.Add(1)
+			s.atomicUploadStats.binary.uploadFailed(binaryKind)
 		} else {
-			s.atomicUploadStats.tabular.uploadFailedFileCount.Add(1)
+			s.atomicUploadStats.tabular.uploadFailed(tabularKind)
 		}
 		return
 	}
@@ -426,18 +444,23 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 		logger.Error(errors.Wrap(err, "error deleting data capture file").Error())
 	}
 	if isBinary {
-		s.atomicUploadStats.binary.uploadedFileCount.Add(1)
-		s.atomicUploadStats.binary.uploadedBytes.Add(bytesUploaded)
+		s.atomicUploadStats.binary.uploaded(binaryKind, bytesUploaded)
 	} else {
-		s.atomicUploadStats.tabular.uploadedFileCount.Add(1)
-		s.atomicUploadStats.tabular.uploadedBytes.Add(bytesUploaded)
+		s.atomicUploadStats.tabular.uploaded(tabularKind, bytesUploaded)
 	}
 }
 
-func (s *Sync) syncArbitraryFile(f *os.File, tags []string, fileLastModifiedMillis int, logger logging.Logger) {
+func (s *Sync) syncArbitraryFile(
+	f *os.File,
+	target Target,
+	name string,
+	tags []string,
+	fileLastModifiedMillis int,
+	logger logging.Logger,
+) {
 	retry := newExponentialRetry(s.configCtx, s.clock, s.logger, f.Name(), func(ctx context.Context) (uint64, error) {
 		errMetadata := fmt.Sprintf("error uploading arbitrary file %s", f.Name())
-		bytesUploaded, err := uploadArbitraryFile(ctx, f, s.cloudConn, tags, fileLastModifiedMillis, s.clock, logger)
+		bytesUploaded, err := target.UploadArbitraryFile(ctx, f, name, tags, fileLastModifiedMillis, s.clock, logger)
 		if err != nil {
 			return 0, errors.Wrap(err, errMetadata)
 		}
@@ -461,7 +484,7 @@ func (s *Sync) syncArbitraryFile(f *os.File, tags []string, fileLastModifiedMill
 		if err := moveFailedData(f.Name(), path.Dir(f.Name()), err, logger); err != nil {
 			logger.Error(err.Error())
 		}
-		s.atomicUploadStats.arbitrary.uploadFailedFileCount.Add(1)
+		s.atomicUploadStats.arbitrary.uploadFailed(arbitraryKind)
 		return
 	}
 
@@ -472,8 +495,7 @@ func (s *Sync) syncArbitraryFile(f *os.File, tags []string, fileLastModifiedMill
 	if err := os.Remove(f.Name()); err != nil {
 		logger.Error(errors.Wrap(err, fmt.Sprintf("error deleting file %s", f.Name())).Error())
 	}
-	s.atomicUploadStats.arbitrary.uploadedFileCount.Add(1)
-	s.atomicUploadStats.arbitrary.uploadedBytes.Add(bytesUploaded)
+	s.atomicUploadStats.arbitrary.uploaded(arbitraryKind, bytesUploaded)
 }
 
 // moveFailedData takes any data that could not be synced in the parentDir and
@@ -501,7 +523,7 @@ func moveFailedData(path, parentDir string, cause error, logger logging.Logger)
 // END sync workers
 
 // BEGIN sync scheudler.
-func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Config) {
+func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Config, target Target) {
 	defer tkr.Stop()
 	var readyLogged bool
 
@@ -510,12 +532,12 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 			return
 		}
 
-		// wait for the cloud connection to be ready
+		// wait for the sync target to be ready
 		// or the scheduler to be cancelled
 		select {
 		case <-ctx.Done():
 			return
-		case <-s.cloudConn.ready:
+		case <-target.Ready():
 			if !readyLogged {
 				readyLogged = true
 			}
@@ -526,11 +548,8 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 			return
 		case <-tkr.C:
 			shouldSync := readyToSyncDirectories(ctx, config, s.logger)
-			state := s.cloudConn.connectivityStateEnabledConn.GetState()
-			online := state == connectivity.Ready
-			if !online {
-				s.logger.Infof("data manager: NOT syncing data to the cloud as it's cloud connection is in state: %s"+
-					"; waiting for it to be in state: %s", state, connectivity.Ready)
+			if err := target.Online(); err != nil {
+				s.logger.Infof("data manager: NOT syncing data to %s as it is offline: %s", config.Target, err)
 				continue
 			}
 
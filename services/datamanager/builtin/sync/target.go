@@ -0,0 +1,318 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/grpc/connectivity"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+// Sync target types.
+const (
+	// TargetTypeCloud syncs files to app.viam.com. This is the default.
+	TargetTypeCloud = "cloud"
+	// TargetTypeLocal syncs files to a directory, such as a mounted NAS share.
+	TargetTypeLocal = "local"
+	// TargetTypeS3 syncs files to an S3 compatible bucket, such as one served by MinIO.
+	TargetTypeS3 = "s3"
+	// TargetTypeHTTP syncs files to an HTTP endpoint.
+	TargetTypeHTTP = "http"
+)
+
+// defaultTargetUploadTimeout bounds how long each upload by the s3 and http targets may take unless configured
+// otherwise.
+const defaultTargetUploadTimeout = 5 * time.Minute
+
+// Target is a destination that data sync uploads files to.
+// Uploads are retried by data sync with exponential backoff until they succeed, or until they fail with an error
+// for which terminalError returns true, at which point the file is moved to the failed directory.
+type Target interface {
+	// Ready returns a channel which is closed once the target is able to accept uploads.
+	Ready() <-chan struct{}
+	// Online returns nil if the target is currently reachable, or an error explaining why it is not.
+	Online() error
+	// UploadDataCaptureFile uploads a file written by data capture. name is the path of the file relative to the
+	// sync path it was found in.
+	// Note: the bytes size returned is the size of the input file. It only returns a non 0 value in the success case.
+	UploadDataCaptureFile(ctx context.Context, f *data.CaptureFile, name string, logger logging.Logger) (uint64, error)
+	// UploadArbitraryFile uploads a file which was not written by data capture. name is the path of the file relative
+	// to the sync path it was found in.
+	// Note: the bytes size returned is the size of the input file. It only returns a non 0 value in the success case.
+	UploadArbitraryFile(
+		ctx context.Context,
+		f *os.File,
+		name string,
+		tags []string,
+		fileLastModifiedMillis int,
+		clock clock.Clock,
+		logger logging.Logger,
+	) (uint64, error)
+}
+
+// TargetConfig selects and configures the Target data sync uploads files to.
+type TargetConfig struct {
+	// Type is one of TargetTypeCloud, TargetTypeLocal, TargetTypeS3 or TargetTypeHTTP.
+	// defaults to TargetTypeCloud
+	Type string `json:"type,omitempty"`
+	// Path is the directory files are written to by the local target.
+	Path string `json:"path,omitempty"`
+	// URL is the endpoint of the S3 compatible service (e.g. http://minio.local:9000) for the s3 target,
+	// or the URL files are POSTed to for the http target.
+	URL string `json:"url,omitempty"`
+	// Bucket is the bucket files are written to by the s3 target.
+	Bucket string `json:"bucket,omitempty"`
+	// Prefix is prepended to the key of every object written by the s3 target.
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the region requests made by the s3 target are signed for.
+	// defaults to us-east-1
+	Region string `json:"region,omitempty"`
+	// AccessKeyID and SecretAccessKey are the credentials used by the s3 target.
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	// Headers are added to every request made by the http target, e.g. for authorization.
+	Headers map[string]string `json:"headers,omitempty"`
+	// UploadTimeoutSecs bounds how long each upload made by the s3 and http targets may take before it fails and
+	// is retried. It should leave time to upload the largest files synced.
+	// defaults to 300
+	UploadTimeoutSecs int `json:"upload_timeout_secs,omitempty"`
+}
+
+// Validate returns an error if the TargetConfig is missing fields its Type requires.
+func (c TargetConfig) Validate() error {
+	if c.UploadTimeoutSecs < 0 {
+		return errors.New("sync target upload_timeout_secs cannot be negative")
+	}
+	switch c.Type {
+	case "", TargetTypeCloud:
+	case TargetTypeLocal:
+		if c.Path == "" {
+			return errors.New("sync target of type local requires a path")
+		}
+	case TargetTypeS3:
+		if c.URL == "" || c.Bucket == "" {
+			return errors.New("sync target of type s3 requires a url and a bucket")
+		}
+		if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+			return errors.New("sync target of type s3 requires both or neither of access_key_id and secret_access_key")
+		}
+	case TargetTypeHTTP:
+		if c.URL == "" {
+			return errors.New("sync target of type http requires a url")
+		}
+	default:
+		return fmt.Errorf("unknown sync target type %q, expected one of %s, %s, %s or %s",
+			c.Type, TargetTypeCloud, TargetTypeLocal, TargetTypeS3, TargetTypeHTTP)
+	}
+	return nil
+}
+
+func (c TargetConfig) isCloud() bool {
+	return c.Type == "" || c.Type == TargetTypeCloud
+}
+
+// httpClient returns the client the s3 and http targets upload with.
+func (c TargetConfig) httpClient() *http.Client {
+	timeout := defaultTargetUploadTimeout
+	if c.UploadTimeoutSecs > 0 {
+		timeout = time.Duration(c.UploadTimeoutSecs) * time.Second
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// String describes the target without exposing its credentials.
+func (c TargetConfig) String() string {
+	switch c.Type {
+	case TargetTypeLocal:
+		return fmt.Sprintf("%s (%s)", c.Type, c.Path)
+	case TargetTypeS3:
+		return fmt.Sprintf("%s (%s/%s)", c.Type, c.URL, c.Bucket)
+	case TargetTypeHTTP:
+		return fmt.Sprintf("%s (%s)", c.Type, c.URL)
+	default:
+		return TargetTypeCloud
+	}
+}
+
+// newTarget returns the Target described by the config. The cloud target uploads over conn, which is populated by the
+// cloud connection manager.
+func newTarget(c TargetConfig, conn *cloudConn) (Target, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case TargetTypeLocal:
+		return newLocalTarget(c.Path), nil
+	case TargetTypeS3:
+		return newS3Target(c)
+	case TargetTypeHTTP:
+		return newHTTPTarget(c), nil
+	default:
+		return &cloudTarget{conn: conn}, nil
+	}
+}
+
+// cloudTarget uploads files to app.viam.com.
+type cloudTarget struct {
+	conn *cloudConn
+}
+
+func (t *cloudTarget) Ready() <-chan struct{} {
+	return t.conn.ready
+}
+
+func (t *cloudTarget) Online() error {
+	state := t.conn.connectivityStateEnabledConn.GetState()
+	if state != connectivity.Ready {
+		return fmt.Errorf("cloud connection is in state: %s; waiting for it to be in state: %s", state, connectivity.Ready)
+	}
+	return nil
+}
+
+func (t *cloudTarget) UploadDataCaptureFile(
+	ctx context.Context,
+	f *data.CaptureFile,
+	_ string,
+	logger logging.Logger,
+) (uint64, error) {
+	return uploadDataCaptureFile(ctx, f, *t.conn, logger)
+}
+
+func (t *cloudTarget) UploadArbitraryFile(
+	ctx context.Context,
+	f *os.File,
+	_ string,
+	tags []string,
+	fileLastModifiedMillis int,
+	clock clock.Clock,
+	logger logging.Logger,
+) (uint64, error) {
+	return uploadArbitraryFile(ctx, f, *t.conn, tags, fileLastModifiedMillis, clock, logger)
+}
+
+// targetFileName returns the name a file is uploaded to non cloud targets with: its path relative to the capture
+// directory, or relative to the parent of the additional sync path it was found in, so that files from different
+// sync paths don't collide.
+func targetFileName(config Config, path string) string {
+	if rel, ok := relativeTo(config.CaptureDir, path); ok {
+		return rel
+	}
+	for _, dir := range config.AdditionalSyncPaths {
+		if rel, ok := relativeTo(dir, path); ok {
+			return filepath.ToSlash(filepath.Join(filepath.Base(filepath.Clean(dir)), rel))
+		}
+	}
+	return filepath.Base(path)
+}
+
+func relativeTo(dir, path string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// prepareArbitraryFile checks that an arbitrary file is ready to be synced and seeks to its start.
+// It returns the absolute path and size of the file.
+func prepareArbitraryFile(f *os.File, fileLastModifiedMillis int, clock clock.Clock) (string, int64, error) {
+	path, err := filepath.Abs(f.Name())
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to get absolute path")
+	}
+
+	// Only sync non-datacapture files that have not been modified in the last
+	// fileLastModifiedMillis to avoid uploading files that are being
+	// to written to.
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "stat failed")
+	}
+	if info.Size() == 0 {
+		return "", 0, errFileEmpty
+	}
+
+	timeSinceMod := clock.Since(info.ModTime())
+	if timeSinceMod < time.Duration(fileLastModifiedMillis)*time.Millisecond {
+		return "", 0, errFileModifiedTooRecently
+	}
+
+	// We need to seek to the start of the file as if we have tried to upload this file
+	// previously, the read offset of the file might not be at the beginning, which would
+	// result in partial data loss when uploading to the cloud.
+	// Fixes https://viam.atlassian.net/browse/DATA-3114
+	pos, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "error trying to Seek to beginning of file")
+	}
+
+	if pos != 0 {
+		return "", 0, fmt.Errorf("error trying to seek to beginning of file %s: expected position 0, instead got to position %d", path, pos)
+	}
+	return path, info.Size(), nil
+}
+
+// captureFileMetadata returns the metadata non cloud targets store alongside a data capture file.
+func captureFileMetadata(md *v1.DataCaptureMetadata) map[string]string {
+	return map[string]string{
+		"component-type": md.GetComponentType(),
+		"component-name": md.GetComponentName(),
+		"method-name":    md.GetMethodName(),
+		"data-type":      md.GetType().String(),
+	}
+}
+
+// arbitraryFileMetadata returns the metadata non cloud targets store alongside an arbitrary file.
+func arbitraryFileMetadata(tags []string) map[string]string {
+	return map[string]string{"tags": strings.Join(tags, ",")}
+}
+
+// terminalTargetError marks an error returned by a Target as one that retrying will never fix.
+type terminalTargetError struct {
+	err error
+}
+
+func newTerminalTargetError(err error) error {
+	return &terminalTargetError{err: err}
+}
+
+func (e *terminalTargetError) Error() string {
+	return e.err.Error()
+}
+
+func (e *terminalTargetError) Unwrap() error {
+	return e.err
+}
+
+// checkUploadResponse returns an error if resp does not indicate a successful upload. Errors for requests the
+// server will never accept are terminal, all others are retried.
+func checkUploadResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		body = nil
+	}
+	err = fmt.Errorf("upload failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusLengthRequired, http.StatusRequestEntityTooLarge:
+		return newTerminalTargetError(err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,116 @@
+package sync
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/benbjohnson/clock"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	httpHeaderPrefix      = "X-Viam-"
+	httpFileNameHeader    = httpHeaderPrefix + "File-Name"
+	httpFileTypeHeader    = httpHeaderPrefix + "File-Type"
+	httpFileTypeCapture   = "capture"
+	httpFileTypeArbitrary = "arbitrary"
+)
+
+// httpTarget POSTs the contents of each file to a URL. The name of the file relative to the sync path it was found in,
+// whether it is a data capture file, and its metadata are sent as X-Viam-* headers.
+type httpTarget struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	ready   chan struct{}
+}
+
+func newHTTPTarget(c TargetConfig) *httpTarget {
+	ready := make(chan struct{})
+	close(ready)
+	return &httpTarget{url: c.URL, headers: c.Headers, client: c.httpClient(), ready: ready}
+}
+
+func (t *httpTarget) Ready() <-chan struct{} {
+	return t.ready
+}
+
+// Online always returns nil, unreachable endpoints are handled by retrying the upload.
+func (t *httpTarget) Online() error {
+	return nil
+}
+
+func (t *httpTarget) UploadDataCaptureFile(
+	ctx context.Context,
+	f *data.CaptureFile,
+	name string,
+	logger logging.Logger,
+) (uint64, error) {
+	//nolint:gosec
+	src, err := os.Open(f.GetPath())
+	if err != nil {
+		return 0, err
+	}
+	defer goutils.UncheckedErrorFunc(src.Close)
+	if err := t.post(ctx, src, f.Size(), name, httpFileTypeCapture, captureFileMetadata(f.ReadMetadata()), logger); err != nil {
+		return 0, err
+	}
+	return uint64(f.Size()), nil
+}
+
+func (t *httpTarget) UploadArbitraryFile(
+	ctx context.Context,
+	f *os.File,
+	name string,
+	tags []string,
+	fileLastModifiedMillis int,
+	clock clock.Clock,
+	logger logging.Logger,
+) (uint64, error) {
+	_, size, err := prepareArbitraryFile(f, fileLastModifiedMillis, clock)
+	if err != nil {
+		return 0, err
+	}
+	if err := t.post(ctx, f, size, name, httpFileTypeArbitrary, arbitraryFileMetadata(tags), logger); err != nil {
+		return 0, err
+	}
+	return uint64(size), nil
+}
+
+func (t *httpTarget) post(
+	ctx context.Context,
+	body io.Reader,
+	size int64,
+	name, fileType string,
+	metadata map[string]string,
+	logger logging.Logger,
+) error {
+	logger.Debugf("uploading %s to %s", name, t.url)
+	// the http client must not close the files being uploaded
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, io.NopCloser(body))
+	if err != nil {
+		return newTerminalTargetError(err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(httpFileNameHeader, name)
+	req.Header.Set(httpFileTypeHeader, fileType)
+	for k, v := range metadata {
+		req.Header.Set(httpHeaderPrefix+k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer goutils.UncheckedErrorFunc(resp.Body.Close)
+	return checkUploadResponse(resp)
+}
@@ -0,0 +1,129 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+// localTarget copies files into a directory, such as a mounted NAS share, preserving their path relative to the
+// sync path they were found in.
+type localTarget struct {
+	dir   string
+	ready chan struct{}
+}
+
+func newLocalTarget(dir string) *localTarget {
+	ready := make(chan struct{})
+	close(ready)
+	return &localTarget{dir: dir, ready: ready}
+}
+
+func (t *localTarget) Ready() <-chan struct{} {
+	return t.ready
+}
+
+// Online returns an error if the target directory doesn't exist. The directory is never created by the local target
+// so that files are not written to the mount point of a NAS share which is not currently mounted.
+func (t *localTarget) Online() error {
+	info, err := os.Stat(t.dir)
+	if err != nil {
+		return errors.Wrapf(err, "sync target directory %s is unavailable", t.dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("sync target %s is not a directory", t.dir)
+	}
+	return nil
+}
+
+func (t *localTarget) UploadDataCaptureFile(
+	ctx context.Context,
+	f *data.CaptureFile,
+	name string,
+	logger logging.Logger,
+) (uint64, error) {
+	logger.Debugf("copying data capture file %s to %s", f.GetPath(), t.dir)
+	//nolint:gosec
+	src, err := os.Open(f.GetPath())
+	if err != nil {
+		return 0, err
+	}
+	defer goutils.UncheckedErrorFunc(src.Close)
+	if err := t.copy(ctx, src, name); err != nil {
+		return 0, err
+	}
+	return uint64(f.Size()), nil
+}
+
+func (t *localTarget) UploadArbitraryFile(
+	ctx context.Context,
+	f *os.File,
+	name string,
+	_ []string,
+	fileLastModifiedMillis int,
+	clock clock.Clock,
+	logger logging.Logger,
+) (uint64, error) {
+	logger.Debugf("copying arbitrary file %s to %s", f.Name(), t.dir)
+	_, size, err := prepareArbitraryFile(f, fileLastModifiedMillis, clock)
+	if err != nil {
+		return 0, err
+	}
+	if err := t.copy(ctx, f, name); err != nil {
+		return 0, err
+	}
+	return uint64(size), nil
+}
+
+// copy writes src to name within the target directory. The contents are written to a temporary file which is renamed
+// into place once complete, so that readers of the target directory never see a partially written file.
+func (t *localTarget) copy(ctx context.Context, src io.Reader, name string) error {
+	if err := t.Online(); err != nil {
+		return err
+	}
+	dst := filepath.Join(t.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return errors.Wrapf(err, "failed to create directory for %s", dst)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary file for %s", dst)
+	}
+	// removing the temporary file fails once it has been renamed, which is expected
+	defer func() { goutils.UncheckedError(os.Remove(tmp.Name())) }()
+
+	if _, err := io.Copy(tmp, &contextReader{ctx: ctx, r: src}); err != nil {
+		goutils.UncheckedError(tmp.Close())
+		return errors.Wrapf(err, "failed to write %s", dst)
+	}
+	if err := tmp.Sync(); err != nil {
+		goutils.UncheckedError(tmp.Close())
+		return errors.Wrapf(err, "failed to sync %s", dst)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close %s", dst)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// contextReader stops reading once its context is cancelled, so that copying a large file does not delay shutdown.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
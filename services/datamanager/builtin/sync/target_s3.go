@@ -0,0 +1,149 @@
+package sync
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultS3Region  = "us-east-1"
+	s3MetadataPrefix = "X-Amz-Meta-"
+)
+
+// s3Target PUTs files into a bucket of an S3 compatible service such as MinIO. Objects are addressed path style
+// (<url>/<bucket>/<key>) as that is supported by every S3 compatible service, and are keyed by the path of the file
+// relative to the sync path it was found in.
+type s3Target struct {
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	region   string
+	// nil when the bucket allows anonymous writes
+	signer *v4.Signer
+	client *http.Client
+	ready  chan struct{}
+}
+
+func newS3Target(c TargetConfig) (*s3Target, error) {
+	endpoint, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid sync target url %s", c.URL)
+	}
+	region := c.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	var signer *v4.Signer
+	if c.AccessKeyID != "" {
+		signer = v4.NewSigner(credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, ""), func(s *v4.Signer) {
+			// the http client must not close the files being uploaded
+			s.DisableRequestBodyOverwrite = true
+		})
+	}
+	ready := make(chan struct{})
+	close(ready)
+	return &s3Target{
+		endpoint: endpoint,
+		bucket:   c.Bucket,
+		prefix:   strings.Trim(c.Prefix, "/"),
+		region:   region,
+		signer:   signer,
+		client:   c.httpClient(),
+		ready:    ready,
+	}, nil
+}
+
+func (t *s3Target) Ready() <-chan struct{} {
+	return t.ready
+}
+
+// Online always returns nil, unreachable endpoints are handled by retrying the upload.
+func (t *s3Target) Online() error {
+	return nil
+}
+
+func (t *s3Target) UploadDataCaptureFile(
+	ctx context.Context,
+	f *data.CaptureFile,
+	name string,
+	logger logging.Logger,
+) (uint64, error) {
+	//nolint:gosec
+	src, err := os.Open(f.GetPath())
+	if err != nil {
+		return 0, err
+	}
+	defer goutils.UncheckedErrorFunc(src.Close)
+	if err := t.put(ctx, src, f.Size(), name, captureFileMetadata(f.ReadMetadata()), logger); err != nil {
+		return 0, err
+	}
+	return uint64(f.Size()), nil
+}
+
+func (t *s3Target) UploadArbitraryFile(
+	ctx context.Context,
+	f *os.File,
+	name string,
+	tags []string,
+	fileLastModifiedMillis int,
+	clock clock.Clock,
+	logger logging.Logger,
+) (uint64, error) {
+	_, size, err := prepareArbitraryFile(f, fileLastModifiedMillis, clock)
+	if err != nil {
+		return 0, err
+	}
+	if err := t.put(ctx, f, size, name, arbitraryFileMetadata(tags), logger); err != nil {
+		return 0, err
+	}
+	return uint64(size), nil
+}
+
+func (t *s3Target) put(
+	ctx context.Context,
+	body io.ReadSeeker,
+	size int64,
+	name string,
+	metadata map[string]string,
+	logger logging.Logger,
+) error {
+	key := path.Join(t.prefix, name)
+	objectURL := t.endpoint.JoinPath(t.bucket, key)
+	logger.Debugf("uploading %s to %s", name, objectURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	for k, v := range metadata {
+		req.Header.Set(s3MetadataPrefix+k, v)
+	}
+	if t.signer != nil {
+		if _, err := t.signer.Sign(req, body, "s3", t.region, time.Now()); err != nil {
+			return errors.Wrap(err, "failed to sign request")
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer goutils.UncheckedErrorFunc(resp.Body.Close)
+	return checkUploadResponse(resp)
+}
@@ -0,0 +1,305 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+// writeTestCaptureFile writes a completed capture file with a single tabular reading to dir.
+func writeTestCaptureFile(t *testing.T, dir string) *data.CaptureFile {
+	t.Helper()
+	md := &v1.DataCaptureMetadata{
+		ComponentName: "sensor1",
+		MethodName:    "Readings",
+		Type:          v1.DataType_DATA_TYPE_TABULAR_SENSOR,
+	}
+	f, err := data.NewCaptureFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	s, err := structpb.NewStruct(map[string]interface{}{"value": 1.0})
+	test.That(t, err, test.ShouldBeNil)
+	now := timestamppb.Now()
+	test.That(t, f.WriteNext(&v1.SensorData{
+		Metadata: &v1.SensorMetadata{TimeRequested: now, TimeReceived: now},
+		Data:     &v1.SensorData_Struct{Struct: s},
+	}), test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+
+	path := strings.TrimSuffix(f.GetPath(), data.InProgressCaptureFileExt) + data.CompletedCaptureFileExt
+	//nolint:gosec
+	completed, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	captureFile, err := data.ReadCaptureFile(completed)
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, captureFile.Close(), test.ShouldBeNil) })
+	return captureFile
+}
+
+func writeTestArbitraryFile(t *testing.T, dir, name, contents string) *os.File {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	test.That(t, os.WriteFile(path, []byte(contents), 0o600), test.ShouldBeNil)
+	//nolint:gosec
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, f.Close(), test.ShouldBeNil) })
+	return f
+}
+
+func TestTargetConfig(t *testing.T) {
+	test.That(t, TargetConfig{}.Validate(), test.ShouldBeNil)
+	test.That(t, TargetConfig{Type: TargetTypeLocal}.Validate(), test.ShouldBeError,
+		"sync target of type local requires a path")
+	test.That(t, TargetConfig{Type: TargetTypeHTTP}.Validate(), test.ShouldBeError,
+		"sync target of type http requires a url")
+	test.That(t, TargetConfig{Type: TargetTypeS3, URL: "http://localhost:9000", Bucket: "b", AccessKeyID: "id"}.Validate(),
+		test.ShouldBeError, "sync target of type s3 requires both or neither of access_key_id and secret_access_key")
+	test.That(t, TargetConfig{Type: TargetTypeHTTP, URL: "http://localhost", UploadTimeoutSecs: -1}.Validate(),
+		test.ShouldBeError, "sync target upload_timeout_secs cannot be negative")
+
+	// uploads are bounded by a timeout
+	test.That(t, TargetConfig{}.httpClient().Timeout, test.ShouldEqual, defaultTargetUploadTimeout)
+	test.That(t, TargetConfig{UploadTimeoutSecs: 30}.httpClient().Timeout, test.ShouldEqual, 30*time.Second)
+
+	// credentials are not logged
+	c := TargetConfig{Type: TargetTypeS3, URL: "http://localhost:9000", Bucket: "b", AccessKeyID: "id", SecretAccessKey: "secret"}
+	test.That(t, c.String(), test.ShouldEqual, "s3 (http://localhost:9000/b)")
+}
+
+func TestTargetFileName(t *testing.T) {
+	config := Config{CaptureDir: "/capture", AdditionalSyncPaths: []string{"/mnt/logs/"}}
+	test.That(t, targetFileName(config, "/capture/sensor/Readings/a.capture"), test.ShouldEqual, "sensor/Readings/a.capture")
+	test.That(t, targetFileName(config, "/mnt/logs/today/b.txt"), test.ShouldEqual, "logs/today/b.txt")
+	test.That(t, targetFileName(config, "/elsewhere/c.txt"), test.ShouldEqual, "c.txt")
+}
+
+func TestLocalTarget(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	dst := t.TempDir()
+	target := newLocalTarget(dst)
+	test.That(t, target.Online(), test.ShouldBeNil)
+
+	captureFile := writeTestCaptureFile(t, t.TempDir())
+	n, err := target.UploadDataCaptureFile(ctx, captureFile, "sensor1/a.capture", logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, n, test.ShouldEqual, captureFile.Size())
+	//nolint:gosec
+	readings, err := data.SensorDataFromCaptureFilePath(filepath.Join(dst, "sensor1", "a.capture"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(readings), test.ShouldEqual, 1)
+
+	f := writeTestArbitraryFile(t, t.TempDir(), "b.txt", "hello")
+	// a partially read file is still copied in full
+	_, err = f.Read(make([]byte, 2))
+	test.That(t, err, test.ShouldBeNil)
+	n, err = target.UploadArbitraryFile(ctx, f, "b.txt", nil, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, n, test.ShouldEqual, 5)
+	//nolint:gosec
+	contents, err := os.ReadFile(filepath.Join(dst, "b.txt"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents), test.ShouldEqual, "hello")
+
+	// no temporary files are left behind
+	entries, err := os.ReadDir(dst)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(entries), test.ShouldEqual, 2)
+
+	// recently modified files are not copied
+	_, err = target.UploadArbitraryFile(ctx, f, "b.txt", nil, 60000, clock.New(), logger)
+	test.That(t, err, test.ShouldBeError, errFileModifiedTooRecently)
+
+	// a missing target directory is never created
+	missing := newLocalTarget(filepath.Join(dst, "unmounted"))
+	test.That(t, missing.Online(), test.ShouldNotBeNil)
+	_, err = missing.UploadArbitraryFile(ctx, f, "b.txt", nil, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, terminalError(err), test.ShouldBeFalse)
+	_, err = os.Stat(filepath.Join(dst, "unmounted"))
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+}
+
+func TestHTTPTarget(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	status := http.StatusOK
+	var gotHeaders http.Header
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header
+		var err error
+		gotBody, err = io.ReadAll(r.Body)
+		test.That(t, err, test.ShouldBeNil)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	target := newHTTPTarget(TargetConfig{Type: TargetTypeHTTP, URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer abc"}})
+	f := writeTestArbitraryFile(t, t.TempDir(), "b.txt", "hello")
+	n, err := target.UploadArbitraryFile(ctx, f, "logs/b.txt", []string{"x", "y"}, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, n, test.ShouldEqual, 5)
+	test.That(t, string(gotBody), test.ShouldEqual, "hello")
+	test.That(t, gotHeaders.Get("Authorization"), test.ShouldEqual, "Bearer abc")
+	test.That(t, gotHeaders.Get("X-Viam-File-Name"), test.ShouldEqual, "logs/b.txt")
+	test.That(t, gotHeaders.Get("X-Viam-File-Type"), test.ShouldEqual, "arbitrary")
+	test.That(t, gotHeaders.Get("X-Viam-Tags"), test.ShouldEqual, "x,y")
+
+	captureFile := writeTestCaptureFile(t, t.TempDir())
+	_, err = target.UploadDataCaptureFile(ctx, captureFile, "a.capture", logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotHeaders.Get("X-Viam-File-Type"), test.ShouldEqual, "capture")
+	test.That(t, gotHeaders.Get("X-Viam-Component-Name"), test.ShouldEqual, "sensor1")
+	test.That(t, int64(len(gotBody)), test.ShouldEqual, captureFile.Size())
+
+	// requests the server will never accept are terminal, other failures are retried
+	status = http.StatusBadRequest
+	_, err = target.UploadArbitraryFile(ctx, f, "b.txt", nil, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, terminalError(err), test.ShouldBeTrue)
+	status = http.StatusServiceUnavailable
+	_, err = target.UploadArbitraryFile(ctx, f, "b.txt", nil, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, terminalError(err), test.ShouldBeFalse)
+
+	// uploads to a server which never responds time out and are retried
+	hung := make(chan struct{})
+	hungSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-hung:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hungSrv.Close()
+	defer close(hung)
+	target = newHTTPTarget(TargetConfig{Type: TargetTypeHTTP, URL: hungSrv.URL, UploadTimeoutSecs: 1})
+	_, err = target.UploadArbitraryFile(ctx, f, "b.txt", nil, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "Client.Timeout exceeded")
+	test.That(t, terminalError(err), test.ShouldBeFalse)
+}
+
+func TestS3Target(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	var gotRequest *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = r
+		var err error
+		gotBody, err = io.ReadAll(r.Body)
+		test.That(t, err, test.ShouldBeNil)
+	}))
+	defer srv.Close()
+
+	target, err := newS3Target(TargetConfig{
+		Type:            TargetTypeS3,
+		URL:             srv.URL,
+		Bucket:          "robot-data",
+		Prefix:          "/site-a/",
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	f := writeTestArbitraryFile(t, t.TempDir(), "b.txt", "hello")
+	n, err := target.UploadArbitraryFile(ctx, f, "logs/b.txt", []string{"x"}, 0, clock.New(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, n, test.ShouldEqual, 5)
+	test.That(t, gotRequest.Method, test.ShouldEqual, http.MethodPut)
+	test.That(t, gotRequest.URL.Path, test.ShouldEqual, "/robot-data/site-a/logs/b.txt")
+	test.That(t, string(gotBody), test.ShouldEqual, "hello")
+	test.That(t, gotRequest.Header.Get("X-Amz-Meta-Tags"), test.ShouldEqual, "x")
+
+	// the request is signed for the body that was sent
+	sum := sha256.Sum256([]byte("hello"))
+	test.That(t, gotRequest.Header.Get("X-Amz-Content-Sha256"), test.ShouldEqual, hex.EncodeToString(sum[:]))
+	auth := gotRequest.Header.Get("Authorization")
+	test.That(t, auth, test.ShouldStartWith, "AWS4-HMAC-SHA256 Credential=minioadmin/")
+	test.That(t, auth, test.ShouldContainSubstring, "/us-east-1/s3/aws4_request")
+	test.That(t, auth, test.ShouldContainSubstring, "x-amz-meta-tags")
+}
+
+func TestSyncToTarget(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	newSync := func(t *testing.T, captureDir string, target TargetConfig) *Sync {
+		t.Helper()
+		s := New(nil, nil, func() {}, clock.New(), logger)
+		s.Reconfigure(context.Background(), Config{
+			CaptureDir:            captureDir,
+			CaptureDisabled:       true,
+			MaximumNumSyncThreads: 1,
+			ScheduledSyncDisabled: true,
+			Target:                target,
+		}, nil)
+		t.Cleanup(s.Close)
+		return s
+	}
+	waitForNotExist := func(t *testing.T, path string) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("%s was not removed", path)
+	}
+
+	t.Run("synced files are deleted", func(t *testing.T) {
+		captureDir := t.TempDir()
+		dst := t.TempDir()
+		test.That(t, os.MkdirAll(filepath.Join(captureDir, "sub"), 0o700), test.ShouldBeNil)
+		src := filepath.Join(captureDir, "sub", "b.txt")
+		test.That(t, os.WriteFile(src, []byte("hello"), 0o600), test.ShouldBeNil)
+
+		s := newSync(t, captureDir, TargetConfig{Type: TargetTypeLocal, Path: dst})
+		test.That(t, s.Sync(context.Background(), nil), test.ShouldBeNil)
+		waitForNotExist(t, src)
+
+		//nolint:gosec
+		contents, err := os.ReadFile(filepath.Join(dst, "sub", "b.txt"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(contents), test.ShouldEqual, "hello")
+	})
+
+	t.Run("files rejected by the target are moved to the failed directory", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer srv.Close()
+		captureDir := t.TempDir()
+		src := filepath.Join(captureDir, "b.txt")
+		test.That(t, os.WriteFile(src, []byte("hello"), 0o600), test.ShouldBeNil)
+
+		s := newSync(t, captureDir, TargetConfig{Type: TargetTypeHTTP, URL: srv.URL})
+		test.That(t, s.Sync(context.Background(), nil), test.ShouldBeNil)
+		waitForNotExist(t, src)
+
+		_, err := os.Stat(filepath.Join(captureDir, FailedDir, "b.txt"))
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("an invalid target disables sync", func(t *testing.T) {
+		s := newSync(t, t.TempDir(), TargetConfig{Type: TargetTypeLocal})
+		test.That(t, s.Sync(context.Background(), nil), test.ShouldBeError, "sync target is invalid")
+	})
+}
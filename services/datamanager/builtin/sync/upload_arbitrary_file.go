@@ -2,11 +2,9 @@ package sync
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
@@ -36,38 +34,9 @@ func uploadArbitraryFile(
 	logger logging.Logger,
 ) (uint64, error) {
 	logger.Debugf("attempting to sync arbitrary file: %s", f.Name())
-	path, err := filepath.Abs(f.Name())
+	path, size, err := prepareArbitraryFile(f, fileLastModifiedMillis, clock)
 	if err != nil {
-		return 0, errors.Wrap(err, "failed to get absolute path")
-	}
-
-	// Only sync non-datacapture files that have not been modified in the last
-	// fileLastModifiedMillis to avoid uploading files that are being
-	// to written to.
-	info, err := os.Stat(path)
-	if err != nil {
-		return 0, errors.Wrap(err, "stat failed")
-	}
-	if info.Size() == 0 {
-		return 0, errFileEmpty
-	}
-
-	timeSinceMod := clock.Since(info.ModTime())
-	if timeSinceMod < time.Duration(fileLastModifiedMillis)*time.Millisecond {
-		return 0, errFileModifiedTooRecently
-	}
-
-	// We need to seek to the start of the file as if we have tried to upload this file
-	// previously, the read offset of the file might not be at the beginning, which would
-	// result in partial data loss when uploading to the cloud.
-	// Fixes https://viam.atlassian.net/browse/DATA-3114
-	pos, err := f.Seek(0, io.SeekStart)
-	if err != nil {
-		return 0, errors.Wrap(err, "error trying to Seek to beginning of file")
-	}
-
-	if pos != 0 {
-		return 0, fmt.Errorf("error trying to seek to beginning of file %s: expected position 0, instead got to position %d", path, pos)
+		return 0, err
 	}
 
 	logger.Debugf("datasync.FileUpload request started for arbitrary file: %s", path)
@@ -100,7 +69,7 @@ func uploadArbitraryFile(
 	if _, err = stream.CloseAndRecv(); err != nil {
 		return 0, errors.Wrap(err, "FileUpload  CloseAndRecv failed")
 	}
-	return uint64(info.Size()), nil
+	return uint64(size), nil
 }
 
 func sendFileUploadRequests(
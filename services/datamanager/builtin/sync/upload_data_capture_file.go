@@ -63,7 +63,7 @@ func uploadMetadata(partID string, md *v1.DataCaptureMetadata, fileextension str
 		ComponentName:    md.GetComponentName(),
 		MethodName:       md.GetMethodName(),
 		Type:             md.GetType(),
-		MethodParameters: md.GetMethodParameters(),
+		MethodParameters: data.MethodParametersWithoutCompression(md),
 		Tags:             md.GetTags(),
 		FileExtension:    fileextension,
 	}
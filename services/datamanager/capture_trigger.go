@@ -0,0 +1,109 @@
+package datamanager
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Capture trigger types.
+const (
+	// TriggerTypeSensorThreshold triggers while a numeric reading of a sensor is above or below a threshold.
+	TriggerTypeSensorThreshold = "sensor_threshold"
+	// TriggerTypeVisionDetection triggers while a vision service detects an object with a given label in a camera's images.
+	TriggerTypeVisionDetection = "vision_detection"
+	// TriggerTypeDigitalInterrupt triggers when a digital interrupt of a board ticks.
+	TriggerTypeDigitalInterrupt = "digital_interrupt"
+
+	defaultTriggerCheckFrequencyHz = 1
+)
+
+// CaptureTrigger makes a collector only keep the data it captures around the times a condition is met, rather than
+// everything it captures at capture_frequency_hz. Readings are held in memory for PreTriggerSecs in case the condition
+// is met, and written for PostTriggerSecs after it was last met.
+type CaptureTrigger struct {
+	Type string `json:"type"`
+	// Resource is the name of the sensor, vision service or board the condition is evaluated with.
+	Resource string `json:"resource"`
+	// CheckFrequencyHz is how often the condition is evaluated, defaults to 1.
+	CheckFrequencyHz float32 `json:"check_frequency_hz,omitempty"`
+	PreTriggerSecs   float64 `json:"pre_trigger_secs,omitempty"`
+	PostTriggerSecs  float64 `json:"post_trigger_secs,omitempty"`
+
+	// Key is the sensor reading compared to Above and Below, for sensor_threshold triggers.
+	Key   string   `json:"key,omitempty"`
+	Above *float64 `json:"above,omitempty"`
+	Below *float64 `json:"below,omitempty"`
+
+	// Camera, Label and MinConfidence select the detections which trigger vision_detection triggers.
+	Camera        string  `json:"camera,omitempty"`
+	Label         string  `json:"label,omitempty"`
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+
+	// Interrupt is the name of the digital interrupt of a digital_interrupt trigger's board.
+	Interrupt string `json:"interrupt,omitempty"`
+}
+
+// Validate returns an error if the trigger is invalid.
+func (t *CaptureTrigger) Validate() error {
+	if t.Resource == "" {
+		return errors.New("trigger requires a resource")
+	}
+	if t.CheckFrequencyHz < 0 {
+		return errors.New("trigger check_frequency_hz can't be negative")
+	}
+	if t.PreTriggerSecs < 0 || t.PostTriggerSecs < 0 {
+		return errors.New("trigger pre_trigger_secs and post_trigger_secs can't be negative")
+	}
+	switch t.Type {
+	case TriggerTypeSensorThreshold:
+		if t.Key == "" {
+			return errors.Errorf("%s trigger requires a key", t.Type)
+		}
+		if t.Above == nil && t.Below == nil {
+			return errors.Errorf("%s trigger requires above or below", t.Type)
+		}
+	case TriggerTypeVisionDetection:
+		if t.Camera == "" || t.Label == "" {
+			return errors.Errorf("%s trigger requires a camera and a label", t.Type)
+		}
+		if t.MinConfidence < 0 || t.MinConfidence > 1 {
+			return errors.Errorf("%s trigger min_confidence must be between 0 and 1", t.Type)
+		}
+	case TriggerTypeDigitalInterrupt:
+		if t.Interrupt == "" {
+			return errors.Errorf("%s trigger requires an interrupt", t.Type)
+		}
+	default:
+		return errors.Errorf("unknown trigger type %q, expected one of %s, %s or %s",
+			t.Type, TriggerTypeSensorThreshold, TriggerTypeVisionDetection, TriggerTypeDigitalInterrupt)
+	}
+	return nil
+}
+
+// Dependencies returns the names of the resources the condition is evaluated with.
+func (t *CaptureTrigger) Dependencies() []string {
+	if t.Type == TriggerTypeVisionDetection && t.Camera != "" {
+		return []string{t.Resource, t.Camera}
+	}
+	return []string{t.Resource}
+}
+
+// CheckInterval returns how often the condition is evaluated.
+func (t *CaptureTrigger) CheckInterval() time.Duration {
+	hz := t.CheckFrequencyHz
+	if hz == 0 {
+		hz = defaultTriggerCheckFrequencyHz
+	}
+	return time.Duration(float32(time.Second) / hz)
+}
+
+// PreTrigger returns PreTriggerSecs as a time.Duration.
+func (t *CaptureTrigger) PreTrigger() time.Duration {
+	return time.Duration(t.PreTriggerSecs * float64(time.Second))
+}
+
+// PostTrigger returns PostTriggerSecs as a time.Duration.
+func (t *CaptureTrigger) PostTrigger() time.Duration {
+	return time.Duration(t.PostTriggerSecs * float64(time.Second))
+}
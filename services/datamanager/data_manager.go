@@ -123,17 +123,32 @@ func (ac *AssociatedConfig) Link(conf *resource.Config) {
 		return
 	}
 
-	// infer name from first index in CaptureMethods
-	name := ac.CaptureMethods[0].Name
-	captureMethodCopies := make([]DataCaptureConfig, 0, len(ac.CaptureMethods))
-	for _, method := range ac.CaptureMethods {
-		methodCopy := method
-		captureMethodCopies = append(captureMethodCopies, methodCopy)
-	}
 	if conf.AssociatedAttributes == nil {
 		conf.AssociatedAttributes = make(map[resource.Name]resource.AssociatedConfig)
 	}
-	conf.AssociatedAttributes[name] = &AssociatedConfig{CaptureMethods: captureMethodCopies}
+	// the capture methods of a remote's associated config may each be on a different resource of the remote, so
+	// group them by the resource they capture from.
+	for _, method := range ac.CaptureMethods {
+		linked, ok := conf.AssociatedAttributes[method.Name].(*AssociatedConfig)
+		if !ok {
+			linked = &AssociatedConfig{}
+			conf.AssociatedAttributes[method.Name] = linked
+		}
+		linked.CaptureMethods = append(linked.CaptureMethods, method)
+
+		if method.Trigger == nil {
+			continue
+		}
+		if dependent, ok := conf.ConvertedAttributes.(TriggerDependent); ok {
+			dependent.AddTriggerDependencies(method.Trigger.Dependencies()...)
+		}
+	}
+}
+
+// TriggerDependent is implemented by the configs of data manager models that evaluate the triggers of the capture
+// methods associated with them, so that they can depend on the resources those triggers use.
+type TriggerDependent interface {
+	AddTriggerDependencies(names ...string)
 }
 
 // DataCaptureConfig is used to initialize a collector for a component or remote.
@@ -147,6 +162,7 @@ type DataCaptureConfig struct {
 	Disabled           bool              `json:"disabled"`
 	Tags               []string          `json:"tags,omitempty"`
 	CaptureDirectory   string            `json:"capture_directory"`
+	Trigger            *CaptureTrigger   `json:"trigger,omitempty"`
 }
 
 // Equals checks if one capture config is equal to another.
@@ -159,7 +175,8 @@ func (c *DataCaptureConfig) Equals(other *DataCaptureConfig) bool {
 		c.Disabled == other.Disabled &&
 		slices.Compare(c.Tags, other.Tags) == 0 &&
 		reflect.DeepEqual(c.AdditionalParams, other.AdditionalParams) &&
-		c.CaptureDirectory == other.CaptureDirectory
+		c.CaptureDirectory == other.CaptureDirectory &&
+		reflect.DeepEqual(c.Trigger, other.Trigger)
 }
 
 // ShouldSyncKey is a special key we use within a modular sensor to pass a boolean
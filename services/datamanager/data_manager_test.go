@@ -2,10 +2,13 @@ package datamanager
 
 import (
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
 )
 
 func TestDataCaptureConfig(t *testing.T) {
@@ -165,6 +168,16 @@ func TestDataCaptureConfig(t *testing.T) {
 			},
 			equal: false,
 		},
+		{
+			name: "different Trigger are not equal",
+			a: &DataCaptureConfig{
+				Trigger: &CaptureTrigger{Type: TriggerTypeDigitalInterrupt, Resource: "board1", Interrupt: "a"},
+			},
+			b: &DataCaptureConfig{
+				Trigger: &CaptureTrigger{Type: TriggerTypeDigitalInterrupt, Resource: "board1", Interrupt: "b"},
+			},
+			equal: false,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -174,3 +187,117 @@ func TestDataCaptureConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCaptureTrigger(t *testing.T) {
+	above := 1.0
+	tcs := []struct {
+		name    string
+		trigger CaptureTrigger
+		err     string
+	}{
+		{
+			name:    "sensor threshold",
+			trigger: CaptureTrigger{Type: TriggerTypeSensorThreshold, Resource: "sensor1", Key: "temp", Above: &above},
+		},
+		{
+			name:    "vision detection",
+			trigger: CaptureTrigger{Type: TriggerTypeVisionDetection, Resource: "vision1", Camera: "cam1", Label: "person"},
+		},
+		{
+			name:    "digital interrupt",
+			trigger: CaptureTrigger{Type: TriggerTypeDigitalInterrupt, Resource: "board1", Interrupt: "estop"},
+		},
+		{
+			name:    "missing resource",
+			trigger: CaptureTrigger{Type: TriggerTypeDigitalInterrupt, Interrupt: "estop"},
+			err:     "trigger requires a resource",
+		},
+		{
+			name:    "sensor threshold without a threshold",
+			trigger: CaptureTrigger{Type: TriggerTypeSensorThreshold, Resource: "sensor1", Key: "temp"},
+			err:     "sensor_threshold trigger requires above or below",
+		},
+		{
+			name: "vision detection with an invalid confidence",
+			trigger: CaptureTrigger{
+				Type: TriggerTypeVisionDetection, Resource: "vision1", Camera: "cam1", Label: "person", MinConfidence: 2,
+			},
+			err: "vision_detection trigger min_confidence must be between 0 and 1",
+		},
+		{
+			name:    "negative window",
+			trigger: CaptureTrigger{Type: TriggerTypeDigitalInterrupt, Resource: "board1", Interrupt: "estop", PreTriggerSecs: -1},
+			err:     "trigger pre_trigger_secs and post_trigger_secs can't be negative",
+		},
+		{
+			name:    "unknown type",
+			trigger: CaptureTrigger{Type: "gpio", Resource: "board1"},
+			err:     `unknown trigger type "gpio", expected one of sensor_threshold, vision_detection or digital_interrupt`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.trigger.Validate()
+			if tc.err == "" {
+				test.That(t, err, test.ShouldBeNil)
+			} else {
+				test.That(t, err, test.ShouldBeError, tc.err)
+			}
+		})
+	}
+
+	trigger := CaptureTrigger{PreTriggerSecs: 1.5, PostTriggerSecs: 2}
+	test.That(t, trigger.CheckInterval(), test.ShouldEqual, time.Second)
+	test.That(t, trigger.PreTrigger(), test.ShouldEqual, 1500*time.Millisecond)
+	test.That(t, trigger.PostTrigger(), test.ShouldEqual, 2*time.Second)
+	trigger.CheckFrequencyHz = 4
+	test.That(t, trigger.CheckInterval(), test.ShouldEqual, 250*time.Millisecond)
+}
+
+type fakeTriggerDependent struct {
+	deps []string
+}
+
+func (f *fakeTriggerDependent) Validate(path string) ([]string, error) {
+	return f.deps, nil
+}
+
+func (f *fakeTriggerDependent) AddTriggerDependencies(names ...string) {
+	f.deps = append(f.deps, names...)
+}
+
+func TestAssociatedConfigLink(t *testing.T) {
+	above := 1.0
+	sensor1 := sensor.Named("sensor1").PrependRemote("remote1")
+	sensor2 := sensor.Named("sensor2").PrependRemote("remote1")
+	ac := &AssociatedConfig{CaptureMethods: []DataCaptureConfig{
+		{Name: sensor1, Method: "Readings"},
+		{
+			Name:   sensor2,
+			Method: "Readings",
+			Trigger: &CaptureTrigger{
+				Type: TriggerTypeSensorThreshold, Resource: "remote1:sensor2", Key: "temp", Above: &above,
+			},
+		},
+		{
+			Name:   sensor2,
+			Method: "Readings",
+			Tags:   []string{"person"},
+			Trigger: &CaptureTrigger{
+				Type: TriggerTypeVisionDetection, Resource: "vision1", Camera: "camera1", Label: "person",
+			},
+		},
+	}}
+	attrs := &fakeTriggerDependent{}
+	conf := &resource.Config{ConvertedAttributes: attrs}
+	ac.Link(conf)
+
+	test.That(t, conf.AssociatedAttributes, test.ShouldHaveLength, 2)
+	test.That(t, conf.AssociatedAttributes[sensor1], test.ShouldResemble, &AssociatedConfig{
+		CaptureMethods: ac.CaptureMethods[:1],
+	})
+	test.That(t, conf.AssociatedAttributes[sensor2], test.ShouldResemble, &AssociatedConfig{
+		CaptureMethods: ac.CaptureMethods[1:],
+	})
+	test.That(t, attrs.deps, test.ShouldResemble, []string{"remote1:sensor2", "vision1", "camera1"})
+}
@@ -46,8 +46,9 @@ func init() {
 
 // export keys to be used with DoCommand so they can be referenced by clients.
 const (
-	DoPlan    = "plan"
-	DoExecute = "execute"
+	DoPlan           = "plan"
+	DoExecute        = "execute"
+	DoPlanCacheStats = "plan_cache_stats"
 )
 
 const (
@@ -82,6 +83,8 @@ type inputEnabledActuator interface {
 // Config describes how to configure the service; currently only used for specifying dependency on framesystem service.
 type Config struct {
 	LogFilePath string `json:"log_file_path"`
+	// If set, the results of Move requests will be cached, and identical future requests served from the cache.
+	PlanCache *motionplan.PlanCacheConfig `json:"plan_cache,omitempty"`
 }
 
 // Validate here adds a dependency on the internal framesystem service.
@@ -146,6 +149,11 @@ func (ms *builtIn) Reconfigure(
 	ms.slamServices = slamServices
 	ms.visionServices = visionServices
 	ms.components = components
+	if config.PlanCache != nil {
+		ms.planCache = motionplan.NewPlanCache(*config.PlanCache)
+	} else {
+		ms.planCache = nil
+	}
 	if ms.state != nil {
 		ms.state.Stop()
 	}
@@ -168,6 +176,7 @@ type builtIn struct {
 	components      map[resource.Name]resource.Resource
 	logger          logging.Logger
 	state           *state.State
+	planCache       *motionplan.PlanCache
 }
 
 func (ms *builtIn) Close(ctx context.Context) error {
@@ -344,6 +353,10 @@ func (ms *builtIn) PlanHistory(
 //     required key: DoExecute
 //     input value: a motionplan.Trajectory
 //     output value: a bool
+//   - DoPlanCacheStats returns how many Move requests have been served from the plan cache, if one is configured
+//     required key: DoPlanCacheStats
+//     input value: ignored
+//     output value: a motionplan.PlanCacheStats
 func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
@@ -380,6 +393,12 @@ func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (m
 		}
 		resp[DoExecute] = true
 	}
+	if _, ok := cmd[DoPlanCacheStats]; ok {
+		if ms.planCache == nil {
+			return nil, errors.New("plan cache is not enabled for this motion service")
+		}
+		resp[DoPlanCacheStats] = ms.planCache.Stats()
+	}
 	return resp, nil
 }
 
@@ -414,7 +433,7 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Pla
 	goalPose, _ := tf.(*referenceframe.PoseInFrame)
 
 	// the goal is to move the component to goalPose which is specified in coordinates of goalFrameName
-	return motionplan.PlanMotion(ctx, &motionplan.PlanRequest{
+	planRequest := &motionplan.PlanRequest{
 		Logger:             ms.logger,
 		Goal:               goalPose,
 		Frame:              movingFrame,
@@ -423,7 +442,11 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Pla
 		WorldState:         req.WorldState,
 		Constraints:        req.Constraints,
 		Options:            req.Extra,
-	})
+	}
+	if ms.planCache != nil {
+		return ms.planCache.PlanMotion(ctx, planRequest)
+	}
+	return motionplan.PlanMotion(ctx, planRequest)
 }
 
 func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory) error {
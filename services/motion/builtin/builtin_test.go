@@ -385,7 +385,9 @@ func TestObstacleReplanningSlam(t *testing.T) {
 		currentPif, err := kb.CurrentPosition(ctx)
 		test.That(t, err, test.ShouldBeNil)
 		relativeBox := boxWrld.Transform(spatialmath.PoseBetween(spatialmath.Compose(currentPif.Pose(), cameraPoseInBase), boxWrld.Pose()))
-		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "test-case-1-detection", relativeBox.ToProtobuf())
+		relativeBoxProto, err := relativeBox.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
+		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "test-case-1-detection", relativeBoxProto)
 		test.That(t, err, test.ShouldBeNil)
 
 		return []*viz.Object{detection}, nil
@@ -833,7 +835,9 @@ func TestGetTransientDetectionsSlam(t *testing.T) {
 			"test-box",
 		)
 		test.That(t, err, test.ShouldBeNil)
-		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "test-box", boxGeom.ToProtobuf())
+		boxProto, err := boxGeom.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
+		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "test-box", boxProto)
 		test.That(t, err, test.ShouldBeNil)
 		return []*viz.Object{detection}, nil
 	}
@@ -1299,4 +1303,20 @@ func TestDoCommand(t *testing.T) {
 		// the client will need to decode the response still
 		test.That(t, resp, test.ShouldBeTrue)
 	})
+	t.Run("DoPlanCacheStats", func(t *testing.T) {
+		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+		defer teardown()
+
+		cmd := map[string]interface{}{DoPlanCacheStats: true}
+		_, err := ms.DoCommand(ctx, cmd)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		ms.(*builtIn).planCache = motionplan.NewPlanCache(motionplan.PlanCacheConfig{})
+		resp, ok := doOverWire(ms, cmd)[DoPlanCacheStats]
+		test.That(t, ok, test.ShouldBeTrue)
+
+		test.That(t, resp, test.ShouldResemble, map[string]interface{}{
+			"hits": 0., "misses": 0., "near_misses": 0., "invalidated": 0., "size": 0.,
+		})
+	})
 }
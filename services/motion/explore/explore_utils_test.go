@@ -97,7 +97,11 @@ func createMockVisionService(visionSvcNum string, obstacles []obstacleMetadata)
 				return nil, err
 			}
 
-			detection, err := vision.NewObjectWithLabel(pointcloud.New(), obs.label+visionSvcNum, box.ToProtobuf())
+			boxProto, err := box.ToProtobuf()
+			if err != nil {
+				return nil, err
+			}
+			detection, err := vision.NewObjectWithLabel(pointcloud.New(), obs.label+visionSvcNum, boxProto)
 			if err != nil {
 				return nil, err
 			}
@@ -1127,6 +1127,11 @@ func TestMoveOnMapReq(t *testing.T) {
 			err         error
 		}
 
+		obstaclesProto, err := spatialmath.NewGeometriesToProto(
+			[]spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{X: 2, Y: 2, Z: 2}, "pt")},
+		)
+		test.That(t, err, test.ShouldBeNil)
+
 		testCases := []testCase{
 			{
 				description: "empty struct fails due to nil destination",
@@ -1175,7 +1180,7 @@ func TestMoveOnMapReq(t *testing.T) {
 					Destination:     spatialmath.PoseToProtobuf(spatialmath.NewZeroPose()),
 					ComponentName:   rprotoutils.ResourceNameToProto(myBase),
 					SlamServiceName: rprotoutils.ResourceNameToProto(mySlam),
-					Obstacles:       spatialmath.NewGeometriesToProto([]spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{2, 2, 2}, "pt")}),
+					Obstacles:       obstaclesProto,
 					Extra:           &structpb.Struct{},
 				},
 				err: nil,
@@ -1204,6 +1209,11 @@ func TestMoveOnMapReq(t *testing.T) {
 			err    error
 		}
 
+		obstaclesProto, err := spatialmath.NewGeometriesToProto(
+			[]spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{X: 2, Y: 2, Z: 2}, "pt")},
+		)
+		test.That(t, err, test.ShouldBeNil)
+
 		testCases := []testCase{
 			{
 				description: "nil request fails",
@@ -1267,9 +1277,7 @@ func TestMoveOnMapReq(t *testing.T) {
 					Destination:     spatialmath.PoseToProtobuf(spatialmath.NewPoseFromPoint(r3.Vector{2700, 0, 0})),
 					ComponentName:   rprotoutils.ResourceNameToProto(myBase),
 					SlamServiceName: rprotoutils.ResourceNameToProto(mySlam),
-					Obstacles: spatialmath.NewGeometriesToProto(
-						[]spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{X: 2, Y: 2, Z: 2}, "pt")},
-					),
+					Obstacles:       obstaclesProto,
 				},
 				result: MoveOnMapReq{
 					ComponentName: myBase,
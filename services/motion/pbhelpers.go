@@ -219,14 +219,22 @@ func (r MoveOnGlobeReq) toProto(name string) (*pb.MoveOnGlobeRequest, error) {
 	if len(r.Obstacles) > 0 {
 		obstaclesProto := make([]*commonpb.GeoGeometry, 0, len(r.Obstacles))
 		for _, obstacle := range r.Obstacles {
-			obstaclesProto = append(obstaclesProto, spatialmath.GeoGeometryToProtobuf(obstacle))
+			obstacleProto, err := spatialmath.GeoGeometryToProtobuf(obstacle)
+			if err != nil {
+				return nil, err
+			}
+			obstaclesProto = append(obstaclesProto, obstacleProto)
 		}
 		req.Obstacles = obstaclesProto
 	}
 	if len(r.BoundingRegions) > 0 {
 		obstaclesProto := make([]*commonpb.GeoGeometry, 0, len(r.BoundingRegions))
 		for _, obstacle := range r.BoundingRegions {
-			obstaclesProto = append(obstaclesProto, spatialmath.GeoGeometryToProtobuf(obstacle))
+			obstacleProto, err := spatialmath.GeoGeometryToProtobuf(obstacle)
+			if err != nil {
+				return nil, err
+			}
+			obstaclesProto = append(obstaclesProto, obstacleProto)
 		}
 		req.BoundingRegions = obstaclesProto
 	}
@@ -375,12 +383,16 @@ func (r MoveOnMapReq) toProto(name string) (*pb.MoveOnMapRequest, error) {
 	if r.Destination == nil {
 		return nil, errors.New("must provide a destination")
 	}
+	obstacles, err := spatialmath.NewGeometriesToProto(r.Obstacles)
+	if err != nil {
+		return nil, err
+	}
 	req := &pb.MoveOnMapRequest{
 		Name:            name,
 		ComponentName:   rprotoutils.ResourceNameToProto(r.ComponentName),
 		Destination:     spatialmath.PoseToProtobuf(r.Destination),
 		SlamServiceName: rprotoutils.ResourceNameToProto(r.SlamName),
-		Obstacles:       spatialmath.NewGeometriesToProto(r.Obstacles),
+		Obstacles:       obstacles,
 		Extra:           ext,
 	}
 
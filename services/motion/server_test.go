@@ -207,13 +207,15 @@ func TestServerMoveOnGlobe(t *testing.T) {
 		geoGeometry2 := spatialmath.NewGeoGeometry(geo.NewPoint(-70, 40), []spatialmath.Geometry{geometries2})
 		geoGeometry3 := spatialmath.NewGeoGeometry(geo.NewPoint(1, 2), []spatialmath.Geometry{geometries3})
 
-		obs := []*commonpb.GeoGeometry{
-			spatialmath.GeoGeometryToProtobuf(geoGeometry1),
-			spatialmath.GeoGeometryToProtobuf(geoGeometry2),
-		}
-		boundingRegionGeoms := []*commonpb.GeoGeometry{
-			spatialmath.GeoGeometryToProtobuf(geoGeometry3),
+		var obs, boundingRegionGeoms []*commonpb.GeoGeometry
+		for _, geoGeometry := range []*spatialmath.GeoGeometry{geoGeometry1, geoGeometry2} {
+			geoGeometryProto, err := spatialmath.GeoGeometryToProtobuf(geoGeometry)
+			test.That(t, err, test.ShouldBeNil)
+			obs = append(obs, geoGeometryProto)
 		}
+		geoGeometryProto, err := spatialmath.GeoGeometryToProtobuf(geoGeometry3)
+		test.That(t, err, test.ShouldBeNil)
+		boundingRegionGeoms = append(boundingRegionGeoms, geoGeometryProto)
 		angularDegsPerSec := 1.
 		linearMPerSec := 2.
 		planDeviationM := 3.
@@ -406,12 +408,14 @@ func TestServerMoveOnMap(t *testing.T) {
 	})
 
 	t.Run("non-nil obstacles passes", func(t *testing.T) {
+		obstacles, err := spatialmath.NewGeometriesToProto([]spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{2, 2, 2}, "pt")})
+		test.That(t, err, test.ShouldBeNil)
 		moveOnMapReq := &pb.MoveOnMapRequest{
 			Name:            testMotionServiceName.ShortName(),
 			ComponentName:   protoutils.ResourceNameToProto(base.Named("test-base")),
 			Destination:     spatialmath.PoseToProtobuf(spatialmath.NewZeroPose()),
 			SlamServiceName: protoutils.ResourceNameToProto(slam.Named("test-slam")),
-			Obstacles:       spatialmath.NewGeometriesToProto([]spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{2, 2, 2}, "pt")}),
+			Obstacles:       obstacles,
 		}
 
 		firstExecutionID := uuid.New()
@@ -1837,7 +1837,9 @@ func TestGetObstacles(t *testing.T) {
 		)
 		test.That(t, err, test.ShouldBeNil)
 
-		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "test-box", boxGeom.ToProtobuf())
+		boxProto, err := boxGeom.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
+		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "test-box", boxProto)
 		test.That(t, err, test.ShouldBeNil)
 		return []*viz.Object{detection}, nil
 	}
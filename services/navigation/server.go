@@ -150,7 +150,11 @@ func (server *serviceServer) GetObstacles(ctx context.Context, req *pb.GetObstac
 	}
 	protoObs := []*commonpb.GeoGeometry{}
 	for _, obstacle := range obstacles {
-		protoObs = append(protoObs, spatialmath.GeoGeometryToProtobuf(obstacle))
+		protoOb, err := spatialmath.GeoGeometryToProtobuf(obstacle)
+		if err != nil {
+			return nil, err
+		}
+		protoObs = append(protoObs, protoOb)
 	}
 	return &pb.GetObstaclesResponse{Obstacles: protoObs}, nil
 }
@@ -189,10 +189,14 @@ func segmentsToProto(frame string, segs []*vision.Object) ([]*commonpb.PointClou
 		if err != nil {
 			return nil, err
 		}
+		geometry, err := seg.Geometry.ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
 		ps := &commonpb.PointCloudObject{
 			PointCloud: buf.Bytes(),
 			Geometries: &commonpb.GeometriesInFrame{
-				Geometries:     []*commonpb.Geometry{seg.Geometry.ToProtobuf()},
+				Geometries:     []*commonpb.Geometry{geometry},
 				ReferenceFrame: frame,
 			},
 		}
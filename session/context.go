@@ -31,7 +31,10 @@ const (
 
 type ctxKey int
 
-const ctxKeySessionID = ctxKey(iota)
+const (
+	ctxKeySessionID = ctxKey(iota)
+	ctxKeyMaxLeasePriority
+)
 
 // ToContext attaches a session to the given context.
 func ToContext(ctx context.Context, sess *Session) context.Context {
@@ -157,6 +157,28 @@ response.
 Starting points:
   - [golang Server]
 
+# Resource Leases
+
+Sessions do not stop two clients from controlling the same resource at once. A session may additionally hold a lease
+on a resource by sending a "viam-lease" metadata header with any method call to it (see WithLease). The header's value
+is the lease mode followed by optional parameters, e.g. "exclusive;priority=10;preempt", or "release" to give the
+lease up:
+
+  - An "exclusive" lease lets only its session control the resource.
+
+  - A "shared_read" lease may be held by many sessions at once, while no session may control the resource.
+
+Every method of a resource controls it, including DoCommand, except Stop and the methods that only read its state,
+such as those named Get*, Is* or Read*.
+
+Acquiring a lease that conflicts with one held by another session fails with a gRPC error with code
+"FailedPrecondition", unless the request preempts and has a higher priority than every conflicting lease. Preempting
+an exclusive lease stops the resource. Calls controlling a resource in violation of a lease fail with the same code.
+When auth roles are configured, the priority of a lease may not exceed the highest "max_lease_priority" of the roles
+bound to the caller, which defaults to 0; requests above it fail with a gRPC error with code "PermissionDenied".
+Without auth roles, callers may lease resources with any priority.
+Leases are released when their session expires.
+
 # Remote Robot Considerations
 
 When connecting to a remote robot, the underlying client will maintain its own, single, session that is
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/rdk/resource"
+)
+
+// LeaseMetadataKey is the gRPC metadata key to use when acquiring or releasing a lease on the
+// resource a call is made to.
+const LeaseMetadataKey = "viam-lease"
+
+// A LeaseMode describes how a lease holds a resource.
+type LeaseMode int
+
+const (
+	// LeaseExclusive lets a single session control a resource. Other sessions may still call its
+	// methods that are not safety monitored.
+	LeaseExclusive LeaseMode = iota + 1
+	// LeaseSharedRead lets any number of sessions hold a resource while no session may control it.
+	LeaseSharedRead
+)
+
+// String returns the name of the mode as used in metadata.
+func (mode LeaseMode) String() string {
+	switch mode {
+	case LeaseExclusive:
+		return "exclusive"
+	case LeaseSharedRead:
+		return "shared_read"
+	default:
+		return fmt.Sprintf("LeaseMode(%d)", int(mode))
+	}
+}
+
+// MarshalText marshals the mode as its name.
+func (mode LeaseMode) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// UnmarshalText unmarshals the mode from its name.
+func (mode *LeaseMode) UnmarshalText(text []byte) error {
+	parsed, err := ParseLeaseMode(string(text))
+	if err != nil {
+		return err
+	}
+	*mode = parsed
+	return nil
+}
+
+// ParseLeaseMode parses the name of a lease mode.
+func ParseLeaseMode(name string) (LeaseMode, error) {
+	switch name {
+	case LeaseExclusive.String():
+		return LeaseExclusive, nil
+	case LeaseSharedRead.String():
+		return LeaseSharedRead, nil
+	default:
+		return 0, errors.Errorf("unknown lease mode %q", name)
+	}
+}
+
+// A LeaseRequest asks for a lease on a resource to be acquired, renewed or released.
+type LeaseRequest struct {
+	Mode LeaseMode
+	// Priority orders competing sessions; only a request of higher priority may preempt a lease. It
+	// may not exceed the maximum allowed for the caller, see WithMaxLeasePriority.
+	Priority int
+	// Preempt revokes conflicting leases of lower priority instead of failing to acquire.
+	Preempt bool
+	// Release gives up the lease held instead of acquiring one.
+	Release bool
+}
+
+const (
+	leaseReleaseValue  = "release"
+	leasePriorityParam = "priority="
+	leasePreemptParam  = "preempt"
+)
+
+// String encodes the request as a metadata value, e.g. "exclusive;priority=10;preempt".
+func (req LeaseRequest) String() string {
+	if req.Release {
+		return leaseReleaseValue
+	}
+	parts := []string{req.Mode.String()}
+	if req.Priority != 0 {
+		parts = append(parts, leasePriorityParam+strconv.Itoa(req.Priority))
+	}
+	if req.Preempt {
+		parts = append(parts, leasePreemptParam)
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseLeaseRequest decodes a request from a metadata value.
+func ParseLeaseRequest(value string) (LeaseRequest, error) {
+	parts := strings.Split(strings.TrimSpace(value), ";")
+	if len(parts) == 1 && parts[0] == leaseReleaseValue {
+		return LeaseRequest{Release: true}, nil
+	}
+	mode, err := ParseLeaseMode(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return LeaseRequest{}, err
+	}
+	req := LeaseRequest{Mode: mode}
+	for _, param := range parts[1:] {
+		param = strings.TrimSpace(param)
+		switch {
+		case param == leasePreemptParam:
+			req.Preempt = true
+		case strings.HasPrefix(param, leasePriorityParam):
+			req.Priority, err = strconv.Atoi(strings.TrimPrefix(param, leasePriorityParam))
+			if err != nil {
+				return LeaseRequest{}, errors.Wrapf(err, "invalid lease priority in %q", value)
+			}
+		default:
+			return LeaseRequest{}, errors.Errorf("unknown lease parameter %q", param)
+		}
+	}
+	return req, nil
+}
+
+// LeaseRequestFromMetadata returns the lease request, if any, in the given metadata.
+func LeaseRequestFromMetadata(meta metadata.MD) (*LeaseRequest, error) {
+	values := meta.Get(LeaseMetadataKey)
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		req, err := ParseLeaseRequest(values[0])
+		if err != nil {
+			return nil, err
+		}
+		return &req, nil
+	default:
+		return nil, errors.New("found more than one lease request in metadata")
+	}
+}
+
+// WithLease returns a context whose outgoing calls to a resource ask for the lease to be acquired
+// (or renewed) on that resource for the calling session. Any method of the resource may be called
+// to do so.
+func WithLease(ctx context.Context, req LeaseRequest) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, LeaseMetadataKey, req.String())
+}
+
+// WithMaxLeasePriority returns a context whose incoming call may acquire leases of up to the given
+// priority. It is set by the server from the auth roles bound to the caller, so that callers cannot
+// preempt leases at will.
+func WithMaxLeasePriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, ctxKeyMaxLeasePriority, priority)
+}
+
+// DefaultMaxLeasePriority is the highest priority a call not limited by WithMaxLeasePriority may
+// acquire a lease with. It places no limit, since the server only limits lease priorities when auth
+// roles are configured, and otherwise does not restrict what any caller may do.
+const DefaultMaxLeasePriority = math.MaxInt
+
+// MaxLeasePriorityFromContext returns the highest priority the incoming call may acquire a lease
+// with, which is DefaultMaxLeasePriority unless limited by WithMaxLeasePriority.
+func MaxLeasePriorityFromContext(ctx context.Context) int {
+	priority, ok := ctx.Value(ctxKeyMaxLeasePriority).(int)
+	if !ok {
+		return DefaultMaxLeasePriority
+	}
+	return priority
+}
+
+// A Lease is held by a session on a resource until it is released, preempted or the session expires.
+type Lease struct {
+	Resource  resource.Name `json:"resource"`
+	SessionID uuid.UUID     `json:"session_id"`
+	Mode      LeaseMode     `json:"mode"`
+	Priority  int           `json:"priority"`
+	Acquired  time.Time     `json:"acquired"`
+}
+
+// MarshalJSON marshals the lease with its resource as its fully qualified name, which is how
+// resource.Name is unmarshaled.
+func (l Lease) MarshalJSON() ([]byte, error) {
+	type plainLease Lease
+	return json.Marshal(struct {
+		plainLease
+		Resource string `json:"resource"`
+	}{plainLease(l), l.Resource.String()})
+}
+
+// ConflictsWith returns whether holding the lease prevents another session from holding a lease of
+// the given mode on the same resource.
+func (l Lease) ConflictsWith(mode LeaseMode) bool {
+	return l.Mode == LeaseExclusive || mode == LeaseExclusive
+}
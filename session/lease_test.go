@@ -0,0 +1,64 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/rdk/session"
+)
+
+func TestLeaseRequestMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		req   session.LeaseRequest
+	}{
+		{"exclusive", session.LeaseRequest{Mode: session.LeaseExclusive}},
+		{"shared_read", session.LeaseRequest{Mode: session.LeaseSharedRead}},
+		{"exclusive;priority=10;preempt", session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 10, Preempt: true}},
+		{"shared_read;priority=-1", session.LeaseRequest{Mode: session.LeaseSharedRead, Priority: -1}},
+		{"release", session.LeaseRequest{Release: true}},
+	} {
+		test.That(t, tc.req.String(), test.ShouldEqual, tc.value)
+		req, err := session.ParseLeaseRequest(tc.value)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, req, test.ShouldResemble, tc.req)
+	}
+
+	for _, value := range []string{"", "forever", "exclusive;priority=high", "exclusive;steal"} {
+		_, err := session.ParseLeaseRequest(value)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	ctx := session.WithLease(context.Background(), session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 2})
+	md, ok := metadata.FromOutgoingContext(ctx)
+	test.That(t, ok, test.ShouldBeTrue)
+	req, err := session.LeaseRequestFromMetadata(md)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, *req, test.ShouldResemble, session.LeaseRequest{Mode: session.LeaseExclusive, Priority: 2})
+
+	req, err = session.LeaseRequestFromMetadata(metadata.MD{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, req, test.ShouldBeNil)
+
+	md.Append(session.LeaseMetadataKey, "release")
+	_, err = session.LeaseRequestFromMetadata(md)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestLeaseConflicts(t *testing.T) {
+	exclusive := session.Lease{Mode: session.LeaseExclusive}
+	shared := session.Lease{Mode: session.LeaseSharedRead}
+	test.That(t, exclusive.ConflictsWith(session.LeaseExclusive), test.ShouldBeTrue)
+	test.That(t, exclusive.ConflictsWith(session.LeaseSharedRead), test.ShouldBeTrue)
+	test.That(t, shared.ConflictsWith(session.LeaseExclusive), test.ShouldBeTrue)
+	test.That(t, shared.ConflictsWith(session.LeaseSharedRead), test.ShouldBeFalse)
+}
+
+func TestMaxLeasePriority(t *testing.T) {
+	ctx := context.Background()
+	test.That(t, session.MaxLeasePriorityFromContext(ctx), test.ShouldEqual, session.DefaultMaxLeasePriority)
+	test.That(t, session.MaxLeasePriorityFromContext(session.WithMaxLeasePriority(ctx, 0)), test.ShouldEqual, 0)
+}
@@ -15,6 +15,14 @@ type Manager interface {
 	All() []*Session
 	FindByID(ctx context.Context, id uuid.UUID, ownerID string) (*Session, error)
 	AssociateResource(id uuid.UUID, resourceName resource.Name)
+
+	// AcquireLease acquires or renews a lease on a resource for the session with the given ID.
+	AcquireLease(ctx context.Context, id uuid.UUID, resourceName resource.Name, req LeaseRequest) (Lease, error)
+	// ReleaseLease releases the lease, if any, the session with the given ID holds on a resource.
+	ReleaseLease(id uuid.UUID, resourceName resource.Name)
+	// Leases returns all leases held.
+	Leases() []Lease
+
 	Close()
 
 	// ServerInterceptors returns gRPC interceptors to work with sessions.
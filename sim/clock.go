@@ -0,0 +1,38 @@
+// Package sim provides lightweight physical models for fake components, so that controllers and
+// planners exercised against them see lag, saturation, friction and slip. Every model advances
+// against a shared clock, which is the wall clock unless a test replaces it with a clock.Mock and
+// steps it.
+package sim
+
+import (
+	"sync"
+
+	"github.com/benbjohnson/clock"
+)
+
+var (
+	clockMu sync.RWMutex
+	clk     clock.Clock = clock.New()
+)
+
+// Clock returns the clock simulated components are built with.
+func Clock() clock.Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return clk
+}
+
+// SetClock replaces the clock simulated components are built with, usually with a clock.Mock that
+// a test steps, and returns a function restoring the previous clock. Components already built keep
+// the clock they were built with.
+func SetClock(c clock.Clock) func() {
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	prev := clk
+	clk = c
+	return func() {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		clk = prev
+	}
+}
@@ -0,0 +1,94 @@
+package sim
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// A DifferentialDrive simulates a base driven by a left and right wheel, tracking its pose on the
+// ground as the wheels turn. The wheels slip by a random amount as they travel, so the pose drifts
+// away from what their positions alone imply.
+type DifferentialDrive struct {
+	Left, Right *Motor
+
+	widthM         float64
+	circumferenceM float64
+	slipStdDev     float64
+
+	mu        sync.Mutex
+	rand      *rand.Rand
+	lastLeft  float64
+	lastRight float64
+	x, y      float64
+	theta     float64
+}
+
+// NewDifferentialDrive returns a drive at the origin facing along +Y. Each wheel's travel over a
+// meter differs from what it turned by a normally distributed slip with the given standard deviation
+// in meters, growing with the square root of the distance. The seed makes the slip repeatable.
+func NewDifferentialDrive(left, right *Motor, widthM, wheelCircumferenceM, slipStdDev float64, seed int64) *DifferentialDrive {
+	d := &DifferentialDrive{
+		Left:           left,
+		Right:          right,
+		widthM:         widthM,
+		circumferenceM: wheelCircumferenceM,
+		slipStdDev:     slipStdDev,
+	}
+	//nolint: gosec
+	d.rand = rand.New(rand.NewSource(seed))
+	d.lastLeft, _ = left.State()
+	d.lastRight, _ = right.State()
+	return d
+}
+
+// SetPowers sets the power of each wheel.
+func (d *DifferentialDrive) SetPowers(left, right float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.advance()
+	d.Left.SetPower(left)
+	d.Right.SetPower(right)
+}
+
+// Pose returns the position of the drive in meters and its heading in radians counterclockwise
+// from +Y.
+func (d *DifferentialDrive) Pose() (float64, float64, float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.advance()
+	return d.x, d.y, d.theta
+}
+
+// Velocities returns the forward speed of the drive in meters per second and its counterclockwise
+// turn rate in radians per second.
+func (d *DifferentialDrive) Velocities() (float64, float64) {
+	_, leftRPM := d.Left.State()
+	_, rightRPM := d.Right.State()
+	left := leftRPM / 60 * d.circumferenceM
+	right := rightRPM / 60 * d.circumferenceM
+	return (left + right) / 2, (right - left) / d.widthM
+}
+
+// advance moves the drive along the arc its wheels turned through since it was last advanced.
+func (d *DifferentialDrive) advance() {
+	leftPosition, _ := d.Left.State()
+	rightPosition, _ := d.Right.State()
+	left := d.slip((leftPosition - d.lastLeft) * d.circumferenceM)
+	right := d.slip((rightPosition - d.lastRight) * d.circumferenceM)
+	d.lastLeft, d.lastRight = leftPosition, rightPosition
+
+	distance := (left + right) / 2
+	turn := (right - left) / d.widthM
+	sin, cos := math.Sincos(d.theta + turn/2)
+	d.x -= distance * sin
+	d.y += distance * cos
+	d.theta = math.Remainder(d.theta+turn, 2*math.Pi)
+}
+
+func (d *DifferentialDrive) slip(distance float64) float64 {
+	if d.slipStdDev == 0 || distance == 0 {
+		return distance
+	}
+	return distance + d.rand.NormFloat64()*d.slipStdDev*math.Sqrt(math.Abs(distance))
+}
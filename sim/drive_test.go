@@ -0,0 +1,79 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+)
+
+func TestDifferentialDrive(t *testing.T) {
+	clk := clock.NewMock()
+	newDrive := func(slip float64, seed int64) *DifferentialDrive {
+		return NewDifferentialDrive(NewMotor(MotorConfig{}, 60, clk), NewMotor(MotorConfig{}, 60, clk), 0.5, 1, slip, seed)
+	}
+
+	// both wheels forward drive straight along +Y
+	d := newDrive(0, 0)
+	d.SetPowers(1, 1)
+	clk.Add(10 * time.Second)
+	linear, angular := d.Velocities()
+	test.That(t, linear, test.ShouldAlmostEqual, 0.95, 1e-6)
+	test.That(t, angular, test.ShouldEqual, 0)
+	x, y, theta := d.Pose()
+	test.That(t, x, test.ShouldEqual, 0)
+	test.That(t, y, test.ShouldBeBetween, 9, 9.5)
+	test.That(t, theta, test.ShouldEqual, 0)
+
+	// opposite wheels turn in place, counterclockwise when the right wheel leads
+	d = newDrive(0, 0)
+	d.SetPowers(-1, 1)
+	clk.Add(time.Second)
+	_, angular = d.Velocities()
+	test.That(t, angular, test.ShouldAlmostEqual, 2*0.95/0.5, 1e-6)
+	d.SetPowers(0, 0)
+	clk.Add(time.Second)
+	leftPosition, _ := d.Left.State()
+	x, y, theta = d.Pose()
+	test.That(t, x, test.ShouldAlmostEqual, 0)
+	test.That(t, y, test.ShouldAlmostEqual, 0)
+	test.That(t, theta, test.ShouldAlmostEqual, math.Remainder(-2*leftPosition/0.5, 2*math.Pi))
+
+	// a turning drive follows an arc, regardless of how often it's observed
+	d = newDrive(0, 0)
+	d.SetPowers(0.5, 1)
+	for i := 0; i < 100; i++ {
+		clk.Add(10 * time.Millisecond)
+		d.Pose()
+	}
+	x, y, theta = d.Pose()
+	test.That(t, theta, test.ShouldBeGreaterThan, 0)
+	test.That(t, x, test.ShouldBeLessThan, 0)
+	test.That(t, y, test.ShouldBeGreaterThan, 0)
+	linear, angular = d.Velocities()
+	radius := linear / angular
+	test.That(t, math.Hypot(x+radius, y), test.ShouldAlmostEqual, radius, 0.01)
+
+	// slip makes the pose wander from the ideal, repeatably for the same seed
+	drives := []*DifferentialDrive{newDrive(0, 0), newDrive(0.05, 1), newDrive(0.05, 1), newDrive(0.05, 2)}
+	for _, d := range drives {
+		d.SetPowers(1, 1)
+	}
+	for i := 0; i < 100; i++ {
+		clk.Add(100 * time.Millisecond)
+		for _, d := range drives {
+			d.Pose()
+		}
+	}
+	var poses [][3]float64
+	for _, d := range drives {
+		x, y, theta := d.Pose()
+		poses = append(poses, [3]float64{x, y, theta})
+	}
+	test.That(t, poses[1], test.ShouldNotResemble, poses[0])
+	test.That(t, poses[1], test.ShouldResemble, poses[2])
+	test.That(t, poses[3], test.ShouldNotResemble, poses[1])
+	test.That(t, math.Hypot(poses[1][0], poses[1][1]), test.ShouldAlmostEqual, poses[0][1], 0.5)
+}
@@ -0,0 +1,93 @@
+package sim
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Joints simulates a set of joints moving toward a goal no faster than their speed limits. The
+// joints move in a straight line through joint space, so the slowest joint sets the pace and they
+// all arrive together.
+type Joints struct {
+	clock     clock.Clock
+	maxSpeeds []float64
+
+	mu       sync.Mutex
+	start    []float64
+	goal     []float64
+	began    time.Time
+	duration time.Duration
+}
+
+// NewJoints returns joints at rest at the given positions. A joint's maximum speed is in its units
+// per second, and a speed of zero leaves it unlimited.
+func NewJoints(positions, maxSpeeds []float64, clk clock.Clock) *Joints {
+	return &Joints{
+		clock:     clk,
+		maxSpeeds: maxSpeeds,
+		start:     append([]float64{}, positions...),
+		goal:      append([]float64{}, positions...),
+		began:     clk.Now(),
+	}
+}
+
+// MoveTo starts the joints moving toward the goal from wherever they are and returns how long
+// they will take to arrive.
+func (j *Joints) MoveTo(goal []float64) time.Duration {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.clock.Now()
+	j.start = j.positions(now)
+	j.goal = append([]float64{}, goal...)
+	j.began = now
+
+	var seconds float64
+	for i := range j.goal {
+		if i < len(j.maxSpeeds) && j.maxSpeeds[i] > 0 {
+			seconds = math.Max(seconds, math.Abs(j.goal[i]-j.start[i])/j.maxSpeeds[i])
+		}
+	}
+	j.duration = time.Duration(seconds * float64(time.Second))
+	return j.duration
+}
+
+// Stop holds the joints where they are.
+func (j *Joints) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.clock.Now()
+	j.start = j.positions(now)
+	j.goal = append([]float64{}, j.start...)
+	j.began = now
+	j.duration = 0
+}
+
+// Positions returns the current positions of the joints.
+func (j *Joints) Positions() []float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.positions(j.clock.Now())
+}
+
+// Moving returns whether the joints have yet to reach their goal.
+func (j *Joints) Moving() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.clock.Since(j.began) < j.duration
+}
+
+func (j *Joints) positions(now time.Time) []float64 {
+	elapsed := now.Sub(j.began)
+	if elapsed >= j.duration {
+		return append([]float64{}, j.goal...)
+	}
+	fraction := elapsed.Seconds() / j.duration.Seconds()
+	positions := make([]float64, len(j.goal))
+	for i := range positions {
+		positions[i] = j.start[i] + (j.goal[i]-j.start[i])*fraction
+	}
+	return positions
+}
@@ -0,0 +1,35 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+)
+
+func TestJoints(t *testing.T) {
+	clk := clock.NewMock()
+	j := NewJoints([]float64{0, 0, 0}, []float64{10, 20, 0}, clk)
+	test.That(t, j.Moving(), test.ShouldBeFalse)
+
+	// the slowest joint sets the pace and the unlimited one keeps in step
+	test.That(t, j.MoveTo([]float64{10, 10, 100}), test.ShouldEqual, time.Second)
+	test.That(t, j.Moving(), test.ShouldBeTrue)
+	clk.Add(500 * time.Millisecond)
+	test.That(t, j.Positions(), test.ShouldResemble, []float64{5, 5, 50})
+
+	// moving again starts from the current positions
+	test.That(t, j.MoveTo([]float64{5, 0, 50}), test.ShouldEqual, 250*time.Millisecond)
+	clk.Add(time.Second)
+	test.That(t, j.Moving(), test.ShouldBeFalse)
+	test.That(t, j.Positions(), test.ShouldResemble, []float64{5, 0, 50})
+
+	// stopping holds the joints partway
+	j.MoveTo([]float64{15, 0, 50})
+	clk.Add(500 * time.Millisecond)
+	j.Stop()
+	test.That(t, j.Moving(), test.ShouldBeFalse)
+	clk.Add(time.Second)
+	test.That(t, j.Positions(), test.ShouldResemble, []float64{10, 0, 50})
+}
@@ -0,0 +1,176 @@
+package sim
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	defaultInertiaKgM2      = 0.001
+	defaultMaxTorqueNm      = 0.1
+	defaultFrictionTorqueNm = 0.005
+)
+
+// MotorConfig describes the physical properties of a simulated DC motor and its load. Zero values
+// take defaults, which give a motor at 100 rpm a time constant of about a tenth of a second.
+type MotorConfig struct {
+	// InertiaKgM2 is the moment of inertia of the rotor and everything it drives.
+	InertiaKgM2 float64 `json:"inertia_kg_m2,omitempty"`
+	// MaxTorqueNm is the torque the motor produces at full power when stalled. It falls linearly to
+	// zero at the motor's maximum speed.
+	MaxTorqueNm float64 `json:"max_torque_nm,omitempty"`
+	// FrictionTorqueNm is the constant friction opposing motion, which also holds the motor still
+	// until it is overcome.
+	FrictionTorqueNm float64 `json:"friction_torque_nm,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *MotorConfig) Validate(path string) error {
+	if conf.InertiaKgM2 < 0 {
+		return resource.NewConfigValidationError(path, errors.New("inertia_kg_m2 cannot be negative"))
+	}
+	if conf.MaxTorqueNm < 0 {
+		return resource.NewConfigValidationError(path, errors.New("max_torque_nm cannot be negative"))
+	}
+	if conf.FrictionTorqueNm < 0 {
+		return resource.NewConfigValidationError(path, errors.New("friction_torque_nm cannot be negative"))
+	}
+	if withDefaults := conf.withDefaults(); withDefaults.FrictionTorqueNm >= withDefaults.MaxTorqueNm {
+		return resource.NewConfigValidationError(path, errors.New("friction_torque_nm must be less than max_torque_nm"))
+	}
+	return nil
+}
+
+func (conf MotorConfig) withDefaults() MotorConfig {
+	if conf.InertiaKgM2 == 0 {
+		conf.InertiaKgM2 = defaultInertiaKgM2
+	}
+	if conf.MaxTorqueNm == 0 {
+		conf.MaxTorqueNm = defaultMaxTorqueNm
+	}
+	if conf.FrictionTorqueNm == 0 {
+		conf.FrictionTorqueNm = defaultFrictionTorqueNm
+	}
+	return conf
+}
+
+// A Motor simulates a DC motor driven at a power between -1 and 1. Its torque falls linearly with
+// speed, so that it approaches the speed for its power with a first order lag, and friction slows
+// it and keeps it from moving at low power. The motion is integrated exactly whenever the motor is
+// observed or commanded, so the clock can be stepped arbitrarily far.
+type Motor struct {
+	clock  clock.Clock
+	maxRPM float64
+	// tau is the time constant in seconds and frictionRatio is the power needed to overcome friction.
+	tau           float64
+	frictionRatio float64
+
+	mu       sync.Mutex
+	last     time.Time
+	power    float64
+	position float64 // revolutions
+	velocity float64 // revolutions per second
+}
+
+// NewMotor returns a motor at rest that reaches maxRPM at full power without friction.
+func NewMotor(conf MotorConfig, maxRPM float64, clk clock.Clock) *Motor {
+	conf = conf.withDefaults()
+	return &Motor{
+		clock:         clk,
+		maxRPM:        maxRPM,
+		tau:           conf.InertiaKgM2 * maxRPM / 60 * 2 * math.Pi / conf.MaxTorqueNm,
+		frictionRatio: conf.FrictionTorqueNm / conf.MaxTorqueNm,
+		last:          clk.Now(),
+	}
+}
+
+// MaxRPM returns the speed of the motor at full power without friction.
+func (m *Motor) MaxRPM() float64 {
+	return m.maxRPM
+}
+
+// SetPower sets the power, saturating at -1 and 1.
+func (m *Motor) SetPower(power float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.advance()
+	m.power = math.Max(-1, math.Min(1, power))
+}
+
+// Power returns the power the motor is driven at.
+func (m *Motor) Power() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.power
+}
+
+// State returns the position of the motor in revolutions and its speed in revolutions per minute.
+func (m *Motor) State() (float64, float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.advance()
+	return m.position, m.velocity * 60
+}
+
+// SetPosition sets the position of the motor in revolutions without affecting its motion.
+func (m *Motor) SetPosition(position float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.advance()
+	m.position = position
+}
+
+// advance integrates the motion up to now. While the direction of motion doesn't change, the speed
+// decays exponentially toward the steady state speed for the power and the friction opposing that
+// direction. When the motor is braking through zero, it stops there and stays stopped unless the
+// power overcomes friction.
+func (m *Motor) advance() {
+	now := m.clock.Now()
+	dt := now.Sub(m.last).Seconds()
+	m.last = now
+
+	for dt > 0 {
+		dir := sign(m.velocity)
+		if dir == 0 {
+			if math.Abs(m.power) <= m.frictionRatio {
+				return
+			}
+			dir = sign(m.power)
+		}
+		steady := m.maxRPM / 60 * (m.power - dir*m.frictionRatio)
+
+		step := dt
+		stops := false
+		if steady*dir < 0 {
+			if toStop := m.tau * math.Log((m.velocity-steady)/-steady); toStop < step {
+				step = toStop
+				stops = true
+			}
+		}
+
+		decay := math.Exp(-step / m.tau)
+		m.position += steady*step + (m.velocity-steady)*m.tau*(1-decay)
+		m.velocity = steady + (m.velocity-steady)*decay
+		if stops {
+			m.velocity = 0
+		}
+		dt -= step
+	}
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+)
+
+func TestMotorConfigValidate(t *testing.T) {
+	test.That(t, (&MotorConfig{}).Validate("path"), test.ShouldBeNil)
+	test.That(t, (&MotorConfig{InertiaKgM2: 0.1, MaxTorqueNm: 2, FrictionTorqueNm: 1}).Validate("path"), test.ShouldBeNil)
+
+	for _, conf := range []MotorConfig{
+		{InertiaKgM2: -1},
+		{MaxTorqueNm: -1},
+		{FrictionTorqueNm: -1},
+		{MaxTorqueNm: 1, FrictionTorqueNm: 1},
+		{FrictionTorqueNm: 1},
+	} {
+		test.That(t, conf.Validate("path"), test.ShouldNotBeNil)
+	}
+}
+
+func TestMotor(t *testing.T) {
+	clk := clock.NewMock()
+	m := NewMotor(MotorConfig{}, 100, clk)
+	tau := m.tau
+	test.That(t, tau, test.ShouldAlmostEqual, 0.1, 0.01)
+
+	// the motor lags behind its power and friction takes a share of it
+	m.SetPower(0.5)
+	clk.Add(time.Duration(tau * float64(time.Second)))
+	_, rpm := m.State()
+	test.That(t, rpm, test.ShouldAlmostEqual, 45*(1-math.Exp(-1)), 1e-6)
+	clk.Add(2 * time.Second)
+	_, rpm = m.State()
+	test.That(t, rpm, test.ShouldAlmostEqual, 45, 1e-6)
+
+	// stepping the clock in pieces gives the same motion as one step
+	stepped := NewMotor(MotorConfig{}, 100, clk)
+	stepped.SetPower(0.5)
+	other := NewMotor(MotorConfig{}, 100, clk)
+	other.SetPower(0.5)
+	for i := 0; i < 100; i++ {
+		clk.Add(10 * time.Millisecond)
+		stepped.State()
+	}
+	position, _ := stepped.State()
+	otherPosition, _ := other.State()
+	test.That(t, position, test.ShouldAlmostEqual, otherPosition, 1e-9)
+
+	// without power, it coasts to a stop and friction holds it there
+	m.SetPosition(0)
+	m.SetPower(0)
+	clk.Add(time.Second)
+	position, rpm = m.State()
+	test.That(t, rpm, test.ShouldEqual, 0)
+	clk.Add(time.Second)
+	stopped, _ := m.State()
+	test.That(t, stopped, test.ShouldEqual, position)
+	// from 45 rpm, friction stops it within about an eighth of a revolution
+	test.That(t, position, test.ShouldBeBetween, 0.05, 0.125)
+
+	// too little power can't overcome friction
+	m.SetPower(-0.04)
+	clk.Add(time.Second)
+	position, rpm = m.State()
+	test.That(t, rpm, test.ShouldEqual, 0)
+	test.That(t, position, test.ShouldEqual, stopped)
+
+	// power saturates, and reversing brakes through zero
+	m.SetPower(-2)
+	test.That(t, m.Power(), test.ShouldEqual, -1)
+	clk.Add(2 * time.Second)
+	_, rpm = m.State()
+	test.That(t, rpm, test.ShouldAlmostEqual, -95, 1e-6)
+	m.SetPower(1)
+	clk.Add(2 * time.Second)
+	_, rpm = m.State()
+	test.That(t, rpm, test.ShouldAlmostEqual, 95, 1e-6)
+}
@@ -130,7 +130,7 @@ func (b *box) Transform(toPremultiply Pose) Geometry {
 }
 
 // ToProtobuf converts the box to a Geometry proto message.
-func (b *box) ToProtobuf() *commonpb.Geometry {
+func (b *box) ToProtobuf() (*commonpb.Geometry, error) {
 	return &commonpb.Geometry{
 		Center: PoseToProtobuf(b.pose),
 		GeometryType: &commonpb.Geometry_Box{
@@ -141,7 +141,7 @@ func (b *box) ToProtobuf() *commonpb.Geometry {
 			}},
 		},
 		Label: b.label,
-	}
+	}, nil
 }
 
 // CollidesWith checks if the given box collides with the given geometry and returns true if it does.
@@ -158,6 +158,9 @@ func (b *box) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error)
 	if other, ok := g.(*point); ok {
 		return pointVsBoxCollision(other.position, b, collisionBufferMM), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsBoxCollision(other, b, collisionBufferMM), nil
+	}
 	return true, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -174,6 +177,9 @@ func (b *box) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxDistance(other.position, b), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsBoxDistance(other, b), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -190,6 +196,9 @@ func (b *box) EncompassedBy(g Geometry) (bool, error) {
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return false, newCollisionTypeUnsupportedError(b, g)
 }
 
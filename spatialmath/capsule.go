@@ -133,7 +133,7 @@ func (c *capsule) Transform(toPremultiply Pose) Geometry {
 }
 
 // ToProto converts the capsule to a Geometry proto message.
-func (c *capsule) ToProtobuf() *commonpb.Geometry {
+func (c *capsule) ToProtobuf() (*commonpb.Geometry, error) {
 	return &commonpb.Geometry{
 		Center: PoseToProtobuf(c.pose),
 		GeometryType: &commonpb.Geometry_Capsule{
@@ -143,7 +143,7 @@ func (c *capsule) ToProtobuf() *commonpb.Geometry {
 			},
 		},
 		Label: c.label,
-	}
+	}, nil
 }
 
 // CollidesWith checks if the given capsule collides with the given geometry and returns true if it does.
@@ -171,6 +171,9 @@ func (c *capsule) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleVsSphereDistance(c, other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return capsuleVsMeshDistance(c, other), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -187,6 +190,9 @@ func (c *capsule) EncompassedBy(g Geometry) (bool, error) {
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -4,6 +4,8 @@ import "github.com/pkg/errors"
 
 var errGeometryTypeUnsupported = errors.New("unsupported Geometry type")
 
+var errMeshProtobufUnsupported = errors.New("meshes cannot be converted to protobuf, which has no mesh geometry")
+
 func newBadGeometryDimensionsError(g Geometry) error {
 	return errors.Errorf("Invalid dimension(s) for Geometry type %T", g)
 }
@@ -35,15 +35,15 @@ func (gob *GeoGeometry) Geometries() []Geometry {
 }
 
 // GeoGeometryToProtobuf converts the GeoGeometry struct into an equivalent Protobuf message.
-func GeoGeometryToProtobuf(geoObst *GeoGeometry) *commonpb.GeoGeometry {
-	var convGeoms []*commonpb.Geometry
-	for _, geometry := range geoObst.geometries {
-		convGeoms = append(convGeoms, geometry.ToProtobuf())
+func GeoGeometryToProtobuf(geoObst *GeoGeometry) (*commonpb.GeoGeometry, error) {
+	convGeoms, err := NewGeometriesToProto(geoObst.geometries)
+	if err != nil {
+		return nil, err
 	}
 	return &commonpb.GeoGeometry{
 		Location:   &commonpb.GeoPoint{Latitude: geoObst.location.Lat(), Longitude: geoObst.location.Lng()},
 		Geometries: convGeoms,
-	}
+	}, nil
 }
 
 // GeoGeometryFromProtobuf takes a Protobuf representation of a GeoGeometry and converts back into a Go struct.
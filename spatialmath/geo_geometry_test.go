@@ -51,7 +51,7 @@ func TestGeoGeometries(t *testing.T) {
 	test.That(t, testGeoms, test.ShouldResemble, testGeoObst.Geometries())
 
 	t.Run("Conversion from GeoGeometry to Protobuf", func(t *testing.T) {
-		convGeoObstProto := GeoGeometryToProtobuf(testGeoObst)
+		convGeoObstProto, err := GeoGeometryToProtobuf(testGeoObst)
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, testPoint.Lat(), test.ShouldEqual, convGeoObstProto.GetLocation().GetLatitude())
 		test.That(t, testPoint.Lng(), test.ShouldEqual, convGeoObstProto.GetLocation().GetLongitude())
@@ -59,9 +59,11 @@ func TestGeoGeometries(t *testing.T) {
 	})
 
 	t.Run("Conversion from Protobuf to GeoGeometry", func(t *testing.T) {
+		testSphereProto, err := testSphere.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
 		testProtobuf := &commonpb.GeoGeometry{
 			Location:   &commonpb.GeoPoint{Latitude: testLatitude, Longitude: testLongitude},
-			Geometries: []*commonpb.Geometry{testSphere.ToProtobuf()},
+			Geometries: []*commonpb.Geometry{testSphereProto},
 		}
 
 		convGeoObst, err := GeoGeometryFromProtobuf(testProtobuf)
@@ -37,8 +37,8 @@ type Geometry interface {
 	// ToPoints returns a vector of points that together represent a point cloud of the Geometry
 	ToPoints(float64) []r3.Vector
 
-	// ToProtobuf converts a Geometry to its protobuf representation.
-	ToProtobuf() *commonpb.Geometry
+	// ToProtobuf converts a Geometry to its protobuf representation, or returns an error if it has none.
+	ToProtobuf() (*commonpb.Geometry, error)
 
 	json.Marshaler
 }
@@ -53,6 +53,7 @@ const (
 	SphereType  = GeometryType("sphere")
 	CapsuleType = GeometryType("capsule")
 	PointType   = GeometryType("point")
+	MeshType    = GeometryType("mesh")
 
 	// objects must be separated by this many mm to not be in collision.
 	defaultCollisionBufferMM = 1e-8
@@ -76,6 +77,14 @@ type GeometryConfig struct {
 	// parameter used for defining a capsule's length
 	L float64 `json:"l"`
 
+	// parameters used for defining a mesh, either inline as the contents of a mesh file of the given content type (stl, obj or ply),
+	// or as a path to a mesh file whose extension determines its content type. If both are given the inline data is used and the
+	// path is kept only as a reference to where the mesh came from. The optional scale is applied to the vertices of the mesh file.
+	MeshData        []byte     `json:"mesh_data,omitempty"`
+	MeshContentType string     `json:"mesh_content_type,omitempty"`
+	MeshFilePath    string     `json:"mesh_file_path,omitempty"`
+	MeshScale       *r3.Vector `json:"mesh_scale,omitempty"`
+
 	// define an offset to position the geometry
 	TranslationOffset r3.Vector         `json:"translation,omitempty"`
 	OrientationOffset OrientationConfig `json:"orientation,omitempty"`
@@ -105,6 +114,16 @@ func NewGeometryConfig(g Geometry) (*GeometryConfig, error) {
 	case *point:
 		config.Type = PointType
 		config.Label = gType.label
+	case *mesh:
+		config.Type = MeshType
+		config.MeshData = gType.toPLY()
+		config.MeshContentType = MeshContentTypePLY
+		if gType.filePath != "" {
+			config.MeshFilePath = gType.filePath
+			scale := gType.fileScale
+			config.MeshScale = &scale
+		}
+		config.Label = gType.label
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", gType))
 	}
@@ -138,6 +157,8 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewCapsule(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case MeshType:
+		return config.parseMesh(offset)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
@@ -157,13 +178,42 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 	return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, string(config.Type))
 }
 
+// parseMesh builds a mesh from either the inline mesh data or the mesh file of the config.
+func (config *GeometryConfig) parseMesh(offset Pose) (Geometry, error) {
+	scale := r3.Vector{X: 1, Y: 1, Z: 1}
+	if config.MeshScale != nil {
+		scale = *config.MeshScale
+	}
+	if len(config.MeshData) == 0 {
+		g, err := NewMeshFromFile(offset, config.MeshFilePath, config.Label)
+		if err != nil {
+			return nil, err
+		}
+		if config.MeshScale == nil {
+			return g, nil
+		}
+		return ScaleMesh(g, scale)
+	}
+	g, err := NewMeshFromBytes(offset, config.MeshData, config.MeshContentType, config.Label)
+	if err != nil {
+		return nil, err
+	}
+	// inline data is already scaled, but remember which file it originally came from
+	if config.MeshFilePath != "" {
+		m := g.(*mesh)
+		m.filePath = config.MeshFilePath
+		m.fileScale = scale
+	}
+	return g, nil
+}
+
 // ToProtobuf converts a GeometryConfig to Protobuf.
 func (config *GeometryConfig) ToProtobuf() (*commonpb.Geometry, error) {
 	creator, err := config.ParseConfig()
 	if err != nil {
 		return nil, err
 	}
-	return creator.ToProtobuf(), nil
+	return creator.ToProtobuf()
 }
 
 // GeometriesAlmostEqual returns a bool describing if the two input Geometries are equal.
@@ -177,6 +227,8 @@ func GeometriesAlmostEqual(a, b Geometry) bool {
 		return gType.almostEqual(b)
 	case *point:
 		return gType.almostEqual(b)
+	case *mesh:
+		return gType.almostEqual(b)
 	default:
 		return false
 	}
@@ -217,10 +269,14 @@ func NewGeometriesFromProto(proto []*commonpb.Geometry) ([]Geometry, error) {
 }
 
 // NewGeometriesToProto converts a list of Geometries to profobuf.
-func NewGeometriesToProto(geometries []Geometry) []*commonpb.Geometry {
+func NewGeometriesToProto(geometries []Geometry) ([]*commonpb.Geometry, error) {
 	var proto []*commonpb.Geometry
 	for _, geometry := range geometries {
-		proto = append(proto, geometry.ToProtobuf())
+		g, err := geometry.ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
+		proto = append(proto, g)
 	}
-	return proto
+	return proto, nil
 }
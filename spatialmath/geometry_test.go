@@ -76,7 +76,9 @@ func TestGeometryToFromProtobuf(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			newVol, err := NewGeometryFromProto(testCase.geometry.ToProtobuf())
+			geometryProto, err := testCase.geometry.ToProtobuf()
+			test.That(t, err, test.ShouldBeNil)
+			newVol, err := NewGeometryFromProto(geometryProto)
 			test.That(t, err, test.ShouldBeNil)
 			test.That(t, GeometriesAlmostEqual(testCase.geometry, newVol), test.ShouldBeTrue)
 			test.That(t, testCase.geometry.Label(), test.ShouldEqual, testCase.name)
@@ -92,6 +92,8 @@ func BoundingSphere(geometry Geometry) (Geometry, error) {
 		r += g.radius
 	case *capsule:
 		r += g.length / 2
+	case *mesh:
+		r += g.boundingRadius()
 	case *point:
 	default:
 		return nil, errGeometryTypeUnsupported
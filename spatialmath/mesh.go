@@ -1,7 +1,15 @@
 package spatialmath
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
 	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/utils"
 )
 
 // This file incorporates work covered by the Brax project -- https://github.com/google/brax/blob/main/LICENSE.
@@ -9,9 +17,322 @@ import (
 // You may obtain a copy of the license at http://www.apache.org/licenses/LICENSE-2.0.
 
 // mesh is a collision geometry that represents a set of triangles that represent a mesh.
+// The triangles are stored in the frame of the mesh's parent, i.e. they have already been transformed by pose.
+// IMPORTANT: meshes are not considered solid. A mesh is not guaranteed to represent an enclosed area, so distances are measured
+// to the closest triangle surface.
 type mesh struct {
 	pose      Pose
 	triangles []*triangle
+	label     string
+
+	// filePath is the mesh file this mesh was loaded from, if any, and fileScale the per-axis scale applied to that file's vertices.
+	filePath  string
+	fileScale r3.Vector
+
+	boundingSphereR float64
+	once            sync.Once
+}
+
+// newMesh instantiates a mesh from triangles defined in the frame of the mesh, transforming them by the given pose.
+func newMesh(offset Pose, triangles []*triangle, label string) (*mesh, error) {
+	if len(triangles) == 0 {
+		return nil, newBadGeometryDimensionsError(&mesh{})
+	}
+	transformed := make([]*triangle, 0, len(triangles))
+	for _, t := range triangles {
+		transformed = append(transformed, t.transform(offset))
+	}
+	return &mesh{pose: offset, triangles: transformed, label: label}, nil
+}
+
+// String returns a human readable string that represents the mesh.
+func (m *mesh) String() string {
+	pt := m.pose.Point()
+	return fmt.Sprintf("Type: Mesh | Position: X:%.1f, Y:%.1f, Z:%.1f | Triangles: %d", pt.X, pt.Y, pt.Z, len(m.triangles))
+}
+
+func (m *mesh) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// Label returns the label of this mesh.
+func (m *mesh) Label() string {
+	return m.label
+}
+
+// SetLabel sets the label of this mesh.
+func (m *mesh) SetLabel(label string) {
+	m.label = label
+}
+
+// Pose returns the pose of the mesh.
+func (m *mesh) Pose() Pose {
+	return m.pose
+}
+
+// almostEqual compares the mesh with another geometry and checks if they are equivalent.
+func (m *mesh) almostEqual(g Geometry) bool {
+	other, ok := g.(*mesh)
+	if !ok {
+		return false
+	}
+	if len(m.triangles) != len(other.triangles) || !PoseAlmostEqualEps(m.pose, other.pose, 1e-6) {
+		return false
+	}
+	for i, t := range m.triangles {
+		o := other.triangles[i]
+		if !utils.Float64AlmostEqual(t.p0.Sub(o.p0).Norm(), 0, 1e-6) ||
+			!utils.Float64AlmostEqual(t.p1.Sub(o.p1).Norm(), 0, 1e-6) ||
+			!utils.Float64AlmostEqual(t.p2.Sub(o.p2).Norm(), 0, 1e-6) {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform premultiplies the mesh pose with a transform, allowing the mesh to be moved in space.
+func (m *mesh) Transform(toPremultiply Pose) Geometry {
+	triangles := make([]*triangle, 0, len(m.triangles))
+	for _, t := range m.triangles {
+		triangles = append(triangles, t.transform(toPremultiply))
+	}
+	return &mesh{
+		pose:      Compose(toPremultiply, m.pose),
+		triangles: triangles,
+		label:     m.label,
+		filePath:  m.filePath,
+		fileScale: m.fileScale,
+	}
+}
+
+// ToProtobuf returns an error, as the common API has no mesh message to represent the mesh with.
+func (m *mesh) ToProtobuf() (*commonpb.Geometry, error) {
+	return nil, errMeshProtobufUnsupported
+}
+
+// CollidesWith checks if the given mesh collides with the given geometry and returns true if it does.
+func (m *mesh) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error) {
+	if other, ok := g.(*box); ok {
+		return meshVsBoxCollision(m, other, collisionBufferMM), nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsMeshDistance(m, other, collisionBufferMM) <= collisionBufferMM, nil
+	}
+	dist, err := m.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return dist <= collisionBufferMM, nil
+}
+
+func (m *mesh) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*box); ok {
+		return meshVsBoxDistance(m, other), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return meshVsSphereDistance(m, other), nil
+	}
+	if other, ok := g.(*capsule); ok {
+		return capsuleVsMeshDistance(other, m), nil
+	}
+	if other, ok := g.(*point); ok {
+		return meshVsPointDistance(m, other.position), nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsMeshDistance(m, other, math.Inf(-1)), nil
+	}
+	return math.Inf(-1), newCollisionTypeUnsupportedError(m, g)
+}
+
+func (m *mesh) EncompassedBy(g Geometry) (bool, error) {
+	if other, ok := g.(*box); ok {
+		return meshInGeometry(m, func(pt r3.Vector) bool { return pointVsBoxCollision(pt, other, defaultCollisionBufferMM) }), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return meshInGeometry(m, func(pt r3.Vector) bool { return sphereVsPointDistance(other, pt) <= defaultCollisionBufferMM }), nil
+	}
+	if other, ok := g.(*capsule); ok {
+		return meshInGeometry(m, func(pt r3.Vector) bool { return capsuleVsPointDistance(other, pt) <= defaultCollisionBufferMM }), nil
+	}
+	if _, ok := g.(*point); ok {
+		return false, nil
+	}
+	if _, ok := g.(*mesh); ok {
+		// meshes are not solid and therefore cannot encompass anything
+		return false, nil
+	}
+	return false, newCollisionTypeUnsupportedError(m, g)
+}
+
+// ToPoints converts a mesh geometry into []r3.Vector. This method takes one argument which determines the spacing in mm between
+// points sampled across the surface of each triangle. If the argument is set to 0. we automatically substitute the value with
+// defaultPointDensity.
+func (m *mesh) ToPoints(resolution float64) []r3.Vector {
+	if resolution <= 0 {
+		resolution = defaultPointDensity
+	}
+	var pts []r3.Vector
+	for _, t := range m.triangles {
+		longest := math.Max(t.p1.Sub(t.p0).Norm(), math.Max(t.p2.Sub(t.p1).Norm(), t.p0.Sub(t.p2).Norm()))
+		steps := int(math.Ceil(longest / resolution))
+		if steps < 1 {
+			steps = 1
+		}
+		e0 := t.p1.Sub(t.p0)
+		e1 := t.p2.Sub(t.p0)
+		for i := 0; i <= steps; i++ {
+			for j := 0; i+j <= steps; j++ {
+				u := float64(i) / float64(steps)
+				v := float64(j) / float64(steps)
+				pts = append(pts, t.p0.Add(e0.Mul(u)).Add(e1.Mul(v)))
+			}
+		}
+	}
+	return pts
+}
+
+// vertices returns every triangle vertex of the mesh, in the frame of the mesh's parent.
+func (m *mesh) vertices() []r3.Vector {
+	verts := make([]r3.Vector, 0, 3*len(m.triangles))
+	for _, t := range m.triangles {
+		verts = append(verts, t.p0, t.p1, t.p2)
+	}
+	return verts
+}
+
+// localTriangles returns the triangles of the mesh expressed in the frame of the mesh itself.
+func (m *mesh) localTriangles() []*triangle {
+	inv := PoseInverse(m.pose)
+	triangles := make([]*triangle, 0, len(m.triangles))
+	for _, t := range m.triangles {
+		triangles = append(triangles, t.transform(inv))
+	}
+	return triangles
+}
+
+// localBounds returns the center and dimensions of the axis-aligned bounding box of the mesh, in the frame of the mesh.
+func (m *mesh) localBounds() (r3.Vector, r3.Vector) {
+	minPt := r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxPt := r3.Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, t := range m.localTriangles() {
+		for _, pt := range []r3.Vector{t.p0, t.p1, t.p2} {
+			minPt = r3.Vector{X: math.Min(minPt.X, pt.X), Y: math.Min(minPt.Y, pt.Y), Z: math.Min(minPt.Z, pt.Z)}
+			maxPt = r3.Vector{X: math.Max(maxPt.X, pt.X), Y: math.Max(maxPt.Y, pt.Y), Z: math.Max(maxPt.Z, pt.Z)}
+		}
+	}
+	return minPt.Add(maxPt).Mul(0.5), maxPt.Sub(minPt)
+}
+
+// boundingRadius returns the radius of the smallest sphere centered on the mesh's pose which contains the whole mesh.
+// It is cached as it is invariant to rigid transformations.
+func (m *mesh) boundingRadius() float64 {
+	m.once.Do(func() {
+		center := m.pose.Point()
+		for _, pt := range m.vertices() {
+			if d := pt.Sub(center).Norm(); d > m.boundingSphereR {
+				m.boundingSphereR = d
+			}
+		}
+	})
+	return m.boundingSphereR
+}
+
+func meshVsPointDistance(m *mesh, pt r3.Vector) float64 {
+	lowDist := math.Inf(1)
+	for _, t := range m.triangles {
+		if dist := t.closestPointToPoint(pt).Sub(pt).Norm(); dist < lowDist {
+			lowDist = dist
+		}
+	}
+	return lowDist
+}
+
+func meshVsSphereDistance(m *mesh, s *sphere) float64 {
+	return meshVsPointDistance(m, s.pose.Point()) - s.radius
+}
+
+// meshVsBoxCollision returns a bool describing if the mesh and box are within collisionBufferMM of each other.
+// The bounding sphere check allows for an early exit before any triangles are compared.
+func meshVsBoxCollision(m *mesh, b *box, collisionBufferMM float64) bool {
+	if b.pose.Point().Sub(m.pose.Point()).Norm()-(m.boundingRadius()+b.boundingSphereR) > collisionBufferMM {
+		return false
+	}
+	return meshVsBoxDistance(m, b) <= collisionBufferMM
+}
+
+// meshVsBoxDistance returns the separation distance between a mesh and a box. As a box is solid, any mesh vertex lying inside of it
+// is in collision and the deepest such vertex determines the (approximate) penetration depth.
+func meshVsBoxDistance(m *mesh, b *box) float64 {
+	deepest := math.Inf(1)
+	for _, pt := range m.vertices() {
+		if dist := pointVsBoxDistance(pt, b); dist < deepest {
+			deepest = dist
+		}
+	}
+	if deepest <= 0 {
+		return deepest
+	}
+	// No vertex is inside the box, but triangle faces may still pass through it
+	return meshVsMeshDistance(m, b.toMesh(), math.Inf(-1))
+}
+
+// meshVsMeshDistance returns the smallest distance between any two triangles of the two meshes.
+// Triangle pairs whose bounding spheres are further apart than the best distance found so far are skipped. If the distance drops to
+// or below stopAt the search ends early, which is useful when only a collision answer is required.
+func meshVsMeshDistance(a, b *mesh, stopAt float64) float64 {
+	lowDist := math.Inf(1)
+	bCenters := make([]r3.Vector, len(b.triangles))
+	bRadii := make([]float64, len(b.triangles))
+	for j, tb := range b.triangles {
+		bCenters[j], bRadii[j] = tb.boundingSphere()
+	}
+	for _, ta := range a.triangles {
+		aCenter, aRadius := ta.boundingSphere()
+		for j, tb := range b.triangles {
+			if aCenter.Sub(bCenters[j]).Norm()-aRadius-bRadii[j] >= lowDist {
+				continue
+			}
+			if dist := triangleVsTriangleDistance(ta, tb); dist < lowDist {
+				lowDist = dist
+				if lowDist <= stopAt {
+					return lowDist
+				}
+			}
+		}
+	}
+	return lowDist
+}
+
+// triangleVsTriangleDistance returns the distance between the closest points on two triangles.
+// The closest points between two triangles always lie on an edge of at least one of them, so it suffices to check every edge of each
+// triangle against the other triangle.
+func triangleVsTriangleDistance(a, b *triangle) float64 {
+	lowDist := math.Inf(1)
+	for _, pair := range [2][2]*triangle{{a, b}, {b, a}} {
+		edges := [3][2]r3.Vector{{pair[0].p0, pair[0].p1}, {pair[0].p1, pair[0].p2}, {pair[0].p2, pair[0].p0}}
+		for _, edge := range edges {
+			segPt, triPt := closestPointsSegmentTriangle(edge[0], edge[1], pair[1])
+			if dist := segPt.Sub(triPt).Norm(); dist < lowDist {
+				lowDist = dist
+			}
+		}
+	}
+	return lowDist
+}
+
+// meshInGeometry returns true if every vertex of the mesh is inside the encompassing geometry as determined by the inside function.
+// This is exact for convex encompassing geometries.
+func meshInGeometry(m *mesh, inside func(r3.Vector) bool) bool {
+	for _, pt := range m.vertices() {
+		if !inside(pt) {
+			return false
+		}
+	}
+	return true
 }
 
 type triangle struct {
@@ -31,6 +352,26 @@ func newTriangle(p0, p1, p2 r3.Vector) *triangle {
 	}
 }
 
+// transform returns a copy of the triangle with each vertex transformed by the given pose.
+func (t *triangle) transform(p Pose) *triangle {
+	return newTriangle(
+		Compose(p, NewPoseFromPoint(t.p0)).Point(),
+		Compose(p, NewPoseFromPoint(t.p1)).Point(),
+		Compose(p, NewPoseFromPoint(t.p2)).Point(),
+	)
+}
+
+// boundingSphere returns the centroid of the triangle and the distance from it to the furthest vertex.
+func (t *triangle) boundingSphere() (r3.Vector, float64) {
+	center := t.p0.Add(t.p1).Add(t.p2).Mul(1. / 3)
+	return center, math.Max(center.Sub(t.p0).Norm(), math.Max(center.Sub(t.p1).Norm(), center.Sub(t.p2).Norm()))
+}
+
+// degenerate returns true if the triangle has no area, in which case it has no well defined normal.
+func (t *triangle) degenerate() bool {
+	return t.p1.Sub(t.p0).Cross(t.p2.Sub(t.p0)).Norm() < floatEpsilon*floatEpsilon
+}
+
 // closestPointToCoplanarPoint takes a point, and returns the closest point on the triangle to the given point
 // The given point *MUST* be coplanar with the triangle. If it is known ahead of time that the point is coplanar, this is faster.
 func (t *triangle) closestPointToCoplanarPoint(pt r3.Vector) r3.Vector {
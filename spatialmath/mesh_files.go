@@ -0,0 +1,472 @@
+package spatialmath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// Supported mesh file formats, as used by the mesh_content_type field of a GeometryConfig.
+const (
+	MeshContentTypeSTL = "stl"
+	MeshContentTypeOBJ = "obj"
+	MeshContentTypePLY = "ply"
+)
+
+// NewMeshFromFile instantiates a new mesh Geometry from an STL, OBJ or PLY file, as determined by the file's extension.
+// Vertex coordinates are interpreted as millimeters; use ScaleMesh for files authored in other units.
+func NewMeshFromFile(offset Pose, path, label string) (Geometry, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	g, err := NewMeshFromBytes(offset, data, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."), label)
+	if err != nil {
+		return nil, err
+	}
+	m := g.(*mesh)
+	m.filePath = path
+	m.fileScale = r3.Vector{X: 1, Y: 1, Z: 1}
+	return m, nil
+}
+
+// NewMeshFromBytes instantiates a new mesh Geometry from the contents of a mesh file of the given content type.
+func NewMeshFromBytes(offset Pose, data []byte, contentType, label string) (Geometry, error) {
+	switch strings.ToLower(contentType) {
+	case MeshContentTypeSTL:
+		return NewMeshFromSTL(offset, data, label)
+	case MeshContentTypeOBJ:
+		return NewMeshFromOBJ(offset, data, label)
+	case MeshContentTypePLY:
+		return NewMeshFromPLY(offset, data, label)
+	default:
+		return nil, errors.Errorf("unsupported mesh content type %q", contentType)
+	}
+}
+
+// NewMeshFromSTL instantiates a new mesh Geometry from the contents of an ASCII or binary STL file.
+func NewMeshFromSTL(offset Pose, data []byte, label string) (Geometry, error) {
+	triangles, err := parseSTL(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse STL mesh")
+	}
+	return newMeshGeometry(offset, triangles, label)
+}
+
+// NewMeshFromOBJ instantiates a new mesh Geometry from the contents of a Wavefront OBJ file.
+// Only vertex and face statements are used; all faces of all objects and groups are combined into a single mesh.
+func NewMeshFromOBJ(offset Pose, data []byte, label string) (Geometry, error) {
+	triangles, err := parseOBJ(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse OBJ mesh")
+	}
+	return newMeshGeometry(offset, triangles, label)
+}
+
+// NewMeshFromPLY instantiates a new mesh Geometry from the contents of an ASCII or binary PLY file.
+func NewMeshFromPLY(offset Pose, data []byte, label string) (Geometry, error) {
+	triangles, err := parsePLY(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse PLY mesh")
+	}
+	return newMeshGeometry(offset, triangles, label)
+}
+
+// ScaleMesh returns a copy of the given mesh Geometry with every vertex scaled about the mesh's origin by the given per-axis factors.
+// This is useful for meshes authored in meters, or with a scale specified alongside them such as in URDF files.
+func ScaleMesh(g Geometry, scale r3.Vector) (Geometry, error) {
+	m, ok := g.(*mesh)
+	if !ok {
+		return nil, errors.Errorf("cannot scale geometry of type %T, only meshes can be scaled", g)
+	}
+	local := m.localTriangles()
+	scaled := make([]*triangle, 0, len(local))
+	for _, t := range local {
+		s := newTriangle(
+			r3.Vector{X: t.p0.X * scale.X, Y: t.p0.Y * scale.Y, Z: t.p0.Z * scale.Z},
+			r3.Vector{X: t.p1.X * scale.X, Y: t.p1.Y * scale.Y, Z: t.p1.Z * scale.Z},
+			r3.Vector{X: t.p2.X * scale.X, Y: t.p2.Y * scale.Y, Z: t.p2.Z * scale.Z},
+		)
+		if !s.degenerate() {
+			scaled = append(scaled, s)
+		}
+	}
+	newM, err := newMesh(m.pose, scaled, m.label)
+	if err != nil {
+		return nil, err
+	}
+	if m.filePath != "" {
+		newM.filePath = m.filePath
+		newM.fileScale = r3.Vector{X: m.fileScale.X * scale.X, Y: m.fileScale.Y * scale.Y, Z: m.fileScale.Z * scale.Z}
+	}
+	return newM, nil
+}
+
+// newMeshGeometry wraps newMesh such that a failure results in a nil Geometry rather than a Geometry holding a nil mesh.
+func newMeshGeometry(offset Pose, triangles []*triangle, label string) (Geometry, error) {
+	m, err := newMesh(offset, triangles, label)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// appendTriangle appends the triangle defined by the three points if it has a nonzero area.
+func appendTriangle(triangles []*triangle, p0, p1, p2 r3.Vector) []*triangle {
+	t := newTriangle(p0, p1, p2)
+	if t.degenerate() {
+		return triangles
+	}
+	return append(triangles, t)
+}
+
+// stlBinaryHeaderSize is the size of the header and triangle count of a binary STL, and stlBinaryTriangleSize the size of each facet.
+const (
+	stlBinaryHeaderSize   = 84
+	stlBinaryTriangleSize = 50
+)
+
+func parseSTL(data []byte) ([]*triangle, error) {
+	// Binary STLs may also begin with "solid", so the size implied by the triangle count is the most reliable way to tell them apart.
+	if len(data) >= stlBinaryHeaderSize {
+		count := int(binary.LittleEndian.Uint32(data[80:84]))
+		if len(data) == stlBinaryHeaderSize+count*stlBinaryTriangleSize {
+			return parseBinarySTL(data[stlBinaryHeaderSize:], count), nil
+		}
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return nil, errors.New("data is neither an ASCII nor a binary STL")
+	}
+	return parseASCIISTL(data)
+}
+
+func parseBinarySTL(data []byte, count int) []*triangle {
+	readVec := func(b []byte) r3.Vector {
+		return r3.Vector{
+			X: float64(math.Float32frombits(binary.LittleEndian.Uint32(b[0:4]))),
+			Y: float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4:8]))),
+			Z: float64(math.Float32frombits(binary.LittleEndian.Uint32(b[8:12]))),
+		}
+	}
+	triangles := make([]*triangle, 0, count)
+	for i := 0; i < count; i++ {
+		// each facet is a normal, three vertices and a two byte attribute count
+		facet := data[i*stlBinaryTriangleSize : (i+1)*stlBinaryTriangleSize]
+		triangles = appendTriangle(triangles, readVec(facet[12:24]), readVec(facet[24:36]), readVec(facet[36:48]))
+	}
+	return triangles
+}
+
+func parseASCIISTL(data []byte) ([]*triangle, error) {
+	var triangles []*triangle
+	verts := make([]r3.Vector, 0, 3)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "vertex":
+			v, err := parseVector(fields[1:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNum)
+			}
+			verts = append(verts, v)
+		case "endloop":
+			if len(verts) != 3 {
+				return nil, errors.Errorf("line %d: facet has %d vertices, expected 3", lineNum, len(verts))
+			}
+			triangles = appendTriangle(triangles, verts[0], verts[1], verts[2])
+			verts = verts[:0]
+		}
+	}
+	return triangles, scanner.Err()
+}
+
+func parseOBJ(data []byte) ([]*triangle, error) {
+	var verts []r3.Vector
+	var triangles []*triangle
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			v, err := parseVector(fields[1:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNum)
+			}
+			verts = append(verts, v)
+		case "f":
+			if len(fields) < 4 {
+				return nil, errors.Errorf("line %d: face has fewer than 3 vertices", lineNum)
+			}
+			face := make([]r3.Vector, 0, len(fields)-1)
+			for _, field := range fields[1:] {
+				// vertices may be specified as v, v/vt, v//vn or v/vt/vn
+				idx, err := strconv.Atoi(strings.SplitN(field, "/", 2)[0])
+				if err != nil {
+					return nil, errors.Wrapf(err, "line %d", lineNum)
+				}
+				// indices are 1-based, and negative indices are relative to the end of the vertex list
+				if idx < 0 {
+					idx += len(verts) + 1
+				}
+				if idx < 1 || idx > len(verts) {
+					return nil, errors.Errorf("line %d: vertex index %s out of range", lineNum, field)
+				}
+				face = append(face, verts[idx-1])
+			}
+			triangles = appendFace(triangles, face)
+		}
+	}
+	return triangles, scanner.Err()
+}
+
+// appendFace triangulates a convex polygonal face as a fan around its first vertex.
+func appendFace(triangles []*triangle, face []r3.Vector) []*triangle {
+	for i := 1; i+1 < len(face); i++ {
+		triangles = appendTriangle(triangles, face[0], face[i], face[i+1])
+	}
+	return triangles
+}
+
+func parseVector(fields []string) (r3.Vector, error) {
+	if len(fields) < 3 {
+		return r3.Vector{}, errors.Errorf("expected 3 coordinates, got %d", len(fields))
+	}
+	var coords [3]float64
+	for i := range coords {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return r3.Vector{}, err
+		}
+		coords[i] = f
+	}
+	return r3.Vector{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
+
+// plyProperty is a single property of a PLY element. List properties have a count type in addition to their value type.
+type plyProperty struct {
+	name      string
+	valueType string
+	countType string
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// plyTypeSizes maps each PLY scalar type name to its size in bytes.
+var plyTypeSizes = map[string]int{
+	"char": 1, "int8": 1, "uchar": 1, "uint8": 1,
+	"short": 2, "int16": 2, "ushort": 2, "uint16": 2,
+	"int": 4, "int32": 4, "uint": 4, "uint32": 4, "float": 4, "float32": 4,
+	"double": 8, "float64": 8,
+}
+
+func parsePLY(data []byte) ([]*triangle, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	format, elements, err := parsePLYHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var readValue func(string) (float64, error)
+	switch format {
+	case "ascii":
+		var fields []string
+		readValue = func(string) (float64, error) {
+			for len(fields) == 0 {
+				line, err := reader.ReadString('\n')
+				fields = strings.Fields(line)
+				if err != nil && len(fields) == 0 {
+					return 0, err
+				}
+			}
+			field := fields[0]
+			fields = fields[1:]
+			return strconv.ParseFloat(field, 64)
+		}
+	case "binary_little_endian":
+		readValue = func(t string) (float64, error) { return readPLYBinaryValue(reader, binary.LittleEndian, t) }
+	case "binary_big_endian":
+		readValue = func(t string) (float64, error) { return readPLYBinaryValue(reader, binary.BigEndian, t) }
+	default:
+		return nil, errors.Errorf("unsupported PLY format %q", format)
+	}
+
+	var verts []r3.Vector
+	var triangles []*triangle
+	for _, element := range elements {
+		for i := 0; i < element.count; i++ {
+			var v r3.Vector
+			var face []r3.Vector
+			for _, prop := range element.properties {
+				if prop.countType == "" {
+					val, err := readValue(prop.valueType)
+					if err != nil {
+						return nil, errors.Wrapf(err, "reading %s %d", element.name, i)
+					}
+					switch prop.name {
+					case "x":
+						v.X = val
+					case "y":
+						v.Y = val
+					case "z":
+						v.Z = val
+					}
+					continue
+				}
+				count, err := readValue(prop.countType)
+				if err != nil {
+					return nil, errors.Wrapf(err, "reading %s %d", element.name, i)
+				}
+				for j := 0; j < int(count); j++ {
+					val, err := readValue(prop.valueType)
+					if err != nil {
+						return nil, errors.Wrapf(err, "reading %s %d", element.name, i)
+					}
+					if element.name != "face" || (prop.name != "vertex_indices" && prop.name != "vertex_index") {
+						continue
+					}
+					idx := int(val)
+					if idx < 0 || idx >= len(verts) {
+						return nil, errors.Errorf("face %d references vertex %d out of range", i, idx)
+					}
+					face = append(face, verts[idx])
+				}
+			}
+			switch element.name {
+			case "vertex":
+				verts = append(verts, v)
+			case "face":
+				triangles = appendFace(triangles, face)
+			}
+		}
+	}
+	return triangles, nil
+}
+
+func parsePLYHeader(reader *bufio.Reader) (string, []*plyElement, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "ply" {
+		return "", nil, errors.New("data is not a PLY file")
+	}
+	var format string
+	var elements []*plyElement
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", nil, errors.Wrap(err, "unterminated PLY header")
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				return "", nil, errors.New("malformed PLY format line")
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				return "", nil, errors.Errorf("malformed PLY element line %q", strings.TrimSpace(line))
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return "", nil, err
+			}
+			elements = append(elements, &plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return "", nil, errors.New("PLY property declared before any element")
+			}
+			element := elements[len(elements)-1]
+			var prop plyProperty
+			switch {
+			case len(fields) == 5 && fields[1] == "list":
+				prop = plyProperty{name: fields[4], countType: fields[2], valueType: fields[3]}
+			case len(fields) == 3:
+				prop = plyProperty{name: fields[2], valueType: fields[1]}
+			default:
+				return "", nil, errors.Errorf("malformed PLY property line %q", strings.TrimSpace(line))
+			}
+			for _, t := range []string{prop.valueType, prop.countType} {
+				if _, ok := plyTypeSizes[t]; t != "" && !ok {
+					return "", nil, errors.Errorf("unsupported PLY property type %q", t)
+				}
+			}
+			element.properties = append(element.properties, prop)
+		case "end_header":
+			return format, elements, nil
+		}
+	}
+}
+
+func readPLYBinaryValue(reader io.Reader, order binary.ByteOrder, valueType string) (float64, error) {
+	buf := make([]byte, plyTypeSizes[valueType])
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		return 0, err
+	}
+	switch valueType {
+	case "char", "int8":
+		return float64(int8(buf[0])), nil
+	case "uchar", "uint8":
+		return float64(buf[0]), nil
+	case "short", "int16":
+		return float64(int16(order.Uint16(buf))), nil
+	case "ushort", "uint16":
+		return float64(order.Uint16(buf)), nil
+	case "int", "int32":
+		return float64(int32(order.Uint32(buf))), nil
+	case "uint", "uint32":
+		return float64(order.Uint32(buf)), nil
+	case "float", "float32":
+		return float64(math.Float32frombits(order.Uint32(buf))), nil
+	case "double", "float64":
+		return math.Float64frombits(order.Uint64(buf)), nil
+	default:
+		return 0, errors.Errorf("unsupported PLY property type %q", valueType)
+	}
+}
+
+// toPLY encodes the mesh, in its own frame, as an ASCII PLY file.
+func (m *mesh) toPLY() []byte {
+	triangles := m.localTriangles()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "ply\nformat ascii 1.0\nelement vertex %d\n", 3*len(triangles))
+	buf.WriteString("property double x\nproperty double y\nproperty double z\n")
+	fmt.Fprintf(&buf, "element face %d\nproperty list uchar int vertex_indices\nend_header\n", len(triangles))
+	formatVec := func(v r3.Vector) string {
+		return strings.Join([]string{
+			strconv.FormatFloat(v.X, 'g', -1, 64),
+			strconv.FormatFloat(v.Y, 'g', -1, 64),
+			strconv.FormatFloat(v.Z, 'g', -1, 64),
+		}, " ")
+	}
+	for _, t := range triangles {
+		fmt.Fprintf(&buf, "%s\n%s\n%s\n", formatVec(t.p0), formatVec(t.p1), formatVec(t.p2))
+	}
+	for i := range triangles {
+		fmt.Fprintf(&buf, "3 %d %d %d\n", 3*i, 3*i+1, 3*i+2)
+	}
+	return buf.Bytes()
+}
@@ -1,6 +1,10 @@
 package spatialmath
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -21,3 +25,158 @@ func TestClosestPoint(t *testing.T) {
 	test.That(t, cp3.ApproxEqual(qp1), test.ShouldBeTrue)
 	test.That(t, cp1.ApproxEqual(cp2), test.ShouldBeTrue)
 }
+
+// unitCubeOBJ is a 2x2x2 cube centered on the origin.
+const unitCubeOBJ = `# cube
+v -1 -1 -1
+v 1 -1 -1
+v 1 1 -1
+v -1 1 -1
+v -1 -1 1
+v 1 -1 1
+v 1 1 1
+v -1 1 1
+f 1 4 3 2
+f 5 6 7 8
+f 1 2 6 5
+f 2 3 7 6
+f 3 4 8 7
+f 4/1 1/2 5/3 8/4
+`
+
+const triangleSTL = `solid tri
+facet normal 0 0 1
+  outer loop
+    vertex 0 0 0
+    vertex 1 0 0
+    vertex 0 1 0
+  endloop
+endfacet
+endsolid tri
+`
+
+const trianglePLY = `ply
+format ascii 1.0
+comment a single triangle
+element vertex 3
+property float x
+property float y
+property float z
+element face 1
+property list uchar int vertex_indices
+end_header
+0 0 0
+1 0 0
+0 1 0
+3 0 1 2
+`
+
+func TestMeshFromFiles(t *testing.T) {
+	t.Run("obj", func(t *testing.T) {
+		g, err := NewMeshFromOBJ(NewZeroPose(), []byte(unitCubeOBJ), "cube")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(g.(*mesh).triangles), test.ShouldEqual, 12)
+		test.That(t, g.Label(), test.ShouldEqual, "cube")
+	})
+	t.Run("ascii stl", func(t *testing.T) {
+		g, err := NewMeshFromSTL(NewZeroPose(), []byte(triangleSTL), "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(g.(*mesh).triangles), test.ShouldEqual, 1)
+	})
+	t.Run("binary stl", func(t *testing.T) {
+		data := make([]byte, stlBinaryHeaderSize+stlBinaryTriangleSize)
+		copy(data, "solid but actually binary")
+		binary.LittleEndian.PutUint32(data[80:], 1)
+		for i, v := range []float32{0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 0} {
+			binary.LittleEndian.PutUint32(data[stlBinaryHeaderSize+4*i:], math.Float32bits(v))
+		}
+		g, err := NewMeshFromSTL(NewZeroPose(), data, "")
+		test.That(t, err, test.ShouldBeNil)
+		tri := g.(*mesh).triangles[0]
+		test.That(t, tri.p1, test.ShouldResemble, r3.Vector{1, 0, 0})
+		test.That(t, tri.p2, test.ShouldResemble, r3.Vector{0, 1, 0})
+	})
+	t.Run("ascii ply", func(t *testing.T) {
+		g, err := NewMeshFromPLY(NewZeroPose(), []byte(trianglePLY), "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(g.(*mesh).triangles), test.ShouldEqual, 1)
+	})
+	t.Run("binary ply", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.WriteString("ply\nformat binary_big_endian 1.0\nelement vertex 3\nproperty double x\nproperty double y\nproperty double z\n")
+		buf.WriteString("element face 1\nproperty list uchar uint vertex_indices\nend_header\n")
+		for _, v := range []float64{0, 0, 0, 1, 0, 0, 0, 1, 0} {
+			test.That(t, binary.Write(&buf, binary.BigEndian, v), test.ShouldBeNil)
+		}
+		buf.WriteByte(3)
+		test.That(t, binary.Write(&buf, binary.BigEndian, []uint32{0, 1, 2}), test.ShouldBeNil)
+		g, err := NewMeshFromPLY(NewZeroPose(), buf.Bytes(), "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, g.(*mesh).triangles[0].p1, test.ShouldResemble, r3.Vector{1, 0, 0})
+	})
+	t.Run("bad data", func(t *testing.T) {
+		_, err := NewMeshFromSTL(NewZeroPose(), []byte("not a mesh"), "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewMeshFromOBJ(NewZeroPose(), []byte("v 0 0 0\nf 1 2 3\n"), "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewMeshFromBytes(NewZeroPose(), []byte(triangleSTL), "dae", "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestMeshSerialization(t *testing.T) {
+	pose := NewPose(r3.Vector{1, 2, 3}, &OrientationVector{OZ: 1, Theta: math.Pi / 3})
+	g, err := NewMeshFromOBJ(pose, []byte(unitCubeOBJ), "cube")
+	test.That(t, err, test.ShouldBeNil)
+
+	data, err := g.MarshalJSON()
+	test.That(t, err, test.ShouldBeNil)
+	config := GeometryConfig{}
+	test.That(t, json.Unmarshal(data, &config), test.ShouldBeNil)
+	test.That(t, config.Type, test.ShouldEqual, MeshType)
+	newGeom, err := config.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(g, newGeom), test.ShouldBeTrue)
+
+	// the common API has no mesh geometry, so meshes can't be converted to protobuf
+	_, err = g.ToProtobuf()
+	test.That(t, err, test.ShouldBeError, errMeshProtobufUnsupported)
+	_, err = NewGeometriesToProto([]Geometry{NewPoint(r3.Vector{}, ""), g})
+	test.That(t, err, test.ShouldBeError, errMeshProtobufUnsupported)
+
+	scaled, err := ScaleMesh(g, r3.Vector{2, 2, 2})
+	test.That(t, err, test.ShouldBeNil)
+	_, dims := scaled.(*mesh).localBounds()
+	test.That(t, dims.Sub(r3.Vector{4, 4, 4}).Norm(), test.ShouldAlmostEqual, 0)
+}
+
+func TestMeshCollision(t *testing.T) {
+	cube, err := NewMeshFromOBJ(NewZeroPose(), []byte(unitCubeOBJ), "")
+	test.That(t, err, test.ShouldBeNil)
+	cases := []geometryComparisonTestCase{
+		{"mesh point separated", [2]Geometry{cube, NewPoint(r3.Vector{3, 0, 0}, "")}, 2},
+		{"mesh sphere separated", [2]Geometry{cube, makeTestSphere(r3.Vector{0, 0, 4}, 1, "")}, 2},
+		{"mesh sphere touching", [2]Geometry{cube, makeTestSphere(r3.Vector{0, 0, 2}, 1, "")}, 0},
+		{"mesh capsule separated", [2]Geometry{cube, makeTestCapsule(NewZeroOrientation(), r3.Vector{0, 0, 5}, 1, 4)}, 2},
+		{
+			"mesh box separated",
+			[2]Geometry{cube, makeTestBox(NewZeroOrientation(), r3.Vector{4, 0, 0}, r3.Vector{2, 2, 2}, "")},
+			2,
+		},
+		{
+			"mesh vertex inside box",
+			[2]Geometry{cube, makeTestBox(NewZeroOrientation(), r3.Vector{1.5, 1.5, 1.5}, r3.Vector{2, 2, 2}, "")},
+			-0.5,
+		},
+		{"mesh mesh separated", [2]Geometry{cube, cube.Transform(NewPoseFromPoint(r3.Vector{0, 5, 0}))}, 3},
+		{"mesh mesh intersecting", [2]Geometry{cube, cube.Transform(NewPoseFromPoint(r3.Vector{0, 1, 1}))}, 0},
+	}
+	testGeometryCollision(t, cases)
+
+	inside, err := cube.EncompassedBy(makeTestSphere(r3.Vector{}, 2, ""))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inside, test.ShouldBeTrue)
+	inside, err = cube.EncompassedBy(makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{1, 1, 1}, ""))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inside, test.ShouldBeFalse)
+}
@@ -64,7 +64,7 @@ func (pt *point) Transform(toPremultiply Pose) Geometry {
 }
 
 // ToProto converts the point to a Geometry proto message.
-func (pt *point) ToProtobuf() *commonpb.Geometry {
+func (pt *point) ToProtobuf() (*commonpb.Geometry, error) {
 	return &commonpb.Geometry{
 		Center: PoseToProtobuf(NewPoseFromPoint(pt.position)),
 		GeometryType: &commonpb.Geometry_Sphere{
@@ -73,7 +73,7 @@ func (pt *point) ToProtobuf() *commonpb.Geometry {
 			},
 		},
 		Label: pt.label,
-	}
+	}, nil
 }
 
 // CollidesWith checks if the given point collides with the given geometry and returns true if it does.
@@ -90,6 +90,9 @@ func (pt *point) CollidesWith(g Geometry, collisionBufferMM float64) (bool, erro
 	if other, ok := g.(*point); ok {
 		return pt.almostEqual(other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsPointDistance(other, pt.position) <= collisionBufferMM, nil
+	}
 	return true, newCollisionTypeUnsupportedError(pt, g)
 }
 
@@ -107,6 +110,9 @@ func (pt *point) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pt.position.Sub(other.position).Norm(), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsPointDistance(other, pt.position), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(pt, g)
 }
 
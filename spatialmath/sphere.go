@@ -77,7 +77,7 @@ func (s *sphere) Transform(toPremultiply Pose) Geometry {
 }
 
 // ToProto converts the sphere to a Geometry proto message.
-func (s *sphere) ToProtobuf() *commonpb.Geometry {
+func (s *sphere) ToProtobuf() (*commonpb.Geometry, error) {
 	return &commonpb.Geometry{
 		Center: PoseToProtobuf(s.pose),
 		GeometryType: &commonpb.Geometry_Sphere{
@@ -86,7 +86,7 @@ func (s *sphere) ToProtobuf() *commonpb.Geometry {
 			},
 		},
 		Label: s.label,
-	}
+	}, nil
 }
 
 // CollidesWith checks if the given sphere collides with the given geometry and returns true if it does.
@@ -103,6 +103,9 @@ func (s *sphere) CollidesWith(g Geometry, collisionBufferMM float64) (bool, erro
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position) <= collisionBufferMM, nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsSphereDistance(other, s) <= collisionBufferMM, nil
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -119,6 +122,9 @@ func (s *sphere) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsSphereDistance(other, s), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -135,6 +141,9 @@ func (s *sphere) EncompassedBy(g Geometry) (bool, error) {
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
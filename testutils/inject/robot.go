@@ -333,6 +333,22 @@ func (m noopSessionManager) FindByID(ctx context.Context, id uuid.UUID, ownerID
 func (m noopSessionManager) AssociateResource(id uuid.UUID, resourceName resource.Name) {
 }
 
+func (m noopSessionManager) AcquireLease(
+	ctx context.Context,
+	id uuid.UUID,
+	resourceName resource.Name,
+	req session.LeaseRequest,
+) (session.Lease, error) {
+	return session.Lease{}, session.ErrNoSession
+}
+
+func (m noopSessionManager) ReleaseLease(id uuid.UUID, resourceName resource.Name) {
+}
+
+func (m noopSessionManager) Leases() []session.Lease {
+	return nil
+}
+
 func (m noopSessionManager) Close() {
 }
 
@@ -0,0 +1,121 @@
+// Package tracing exports the spans recorded with opencensus to an OpenTelemetry (OTLP) collector or file.
+package tracing
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The protocols spans may be exported with.
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+	ProtocolFile = "file"
+)
+
+const (
+	defaultGRPCEndpoint = "localhost:4317"
+	defaultHTTPEndpoint = "localhost:4318"
+	httpTracesPath      = "/v1/traces"
+)
+
+// Config configures where the spans recorded by a robot, its remotes' clients and its modules are exported to.
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Protocol is one of "grpc" (the default), "http" or "file".
+	Protocol string `json:"protocol,omitempty"`
+	// Endpoint is the address of the OTLP collector. Defaults to localhost:4317 for grpc and localhost:4318 for http.
+	// An http endpoint without a path is sent spans at /v1/traces.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool `json:"insecure,omitempty"`
+	// Headers are sent with every export, e.g: for authenticating with the collector.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Path is the file spans are appended to as OTLP JSON lines when the protocol is "file".
+	Path string `json:"path,omitempty"`
+	// SampleRate is the fraction of traces started on this robot that are recorded. Defaults to 1. Traces started
+	// by a caller are recorded if the caller recorded them.
+	SampleRate float64 `json:"sample_rate,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *Config) Validate(path string) error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Protocol {
+	case "", ProtocolGRPC, ProtocolHTTP:
+		if c.Path != "" {
+			return resource.NewConfigValidationError(path, errors.New("path may only be set for the file protocol"))
+		}
+	case ProtocolFile:
+		if c.Path == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "path")
+		}
+		if c.Endpoint != "" {
+			return resource.NewConfigValidationError(path, errors.New("endpoint may not be set for the file protocol"))
+		}
+	default:
+		return resource.NewConfigValidationError(path, fmt.Errorf("unknown protocol %q", c.Protocol))
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return resource.NewConfigValidationError(path, errors.New("sample_rate must be between 0 and 1"))
+	}
+	return nil
+}
+
+// Equals returns whether the two configs export spans the same way.
+func (c *Config) Equals(other *Config) bool {
+	if !c.enabled() || !other.enabled() {
+		return c.enabled() == other.enabled()
+	}
+	if c.Protocol != other.Protocol || c.Endpoint != other.Endpoint || c.Insecure != other.Insecure ||
+		c.Path != other.Path || c.SampleRate != other.SampleRate || len(c.Headers) != len(other.Headers) {
+		return false
+	}
+	for key, value := range c.Headers {
+		if otherValue, ok := other.Headers[key]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Config) enabled() bool {
+	return c != nil && c.Enabled
+}
+
+func (c *Config) sampleRate() float64 {
+	if c.SampleRate == 0 {
+		return 1
+	}
+	return c.SampleRate
+}
+
+// httpURL returns the URL spans are posted to over http.
+func (c *Config) httpURL() (string, error) {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = defaultHTTPEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		if c.Insecure {
+			endpoint = "http://" + endpoint
+		} else {
+			endpoint = "https://" + endpoint
+		}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid tracing endpoint")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = httpTracesPath
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opencensus.io/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// instrumentationScope is the name of the instrumentation scope spans are exported under.
+const instrumentationScope = "go.viam.com/rdk"
+
+// resourceSpans returns the spans recorded by the named service as OTLP resource spans.
+func resourceSpans(serviceName string, spans []*tracepb.Span) *tracepb.ResourceSpans {
+	attrs := map[string]interface{}{
+		"service.name": serviceName,
+		"process.pid":  int64(os.Getpid()),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		attrs["host.name"] = hostname
+	}
+	return &tracepb.ResourceSpans{
+		Resource: &resourcepb.Resource{Attributes: attributes(attrs)},
+		ScopeSpans: []*tracepb.ScopeSpans{{
+			Scope: &commonpb.InstrumentationScope{Name: instrumentationScope},
+			Spans: spans,
+		}},
+	}
+}
+
+// spanFromData converts a span recorded by opencensus to an OTLP span.
+func spanFromData(sd *trace.SpanData) *tracepb.Span {
+	span := &tracepb.Span{
+		TraceId:                sd.TraceID[:],
+		SpanId:                 sd.SpanID[:],
+		Name:                   sd.Name,
+		Kind:                   spanKind(sd.SpanKind),
+		StartTimeUnixNano:      uint64(sd.StartTime.UnixNano()),
+		EndTimeUnixNano:        uint64(sd.EndTime.UnixNano()),
+		Attributes:             attributes(sd.Attributes),
+		DroppedAttributesCount: uint32(sd.DroppedAttributeCount),
+		DroppedEventsCount:     uint32(sd.DroppedAnnotationCount + sd.DroppedMessageEventCount),
+		DroppedLinksCount:      uint32(sd.DroppedLinkCount),
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		span.ParentSpanId = sd.ParentSpanID[:]
+	}
+	if sd.Tracestate != nil {
+		entries := make([]string, 0, len(sd.Tracestate.Entries()))
+		for _, entry := range sd.Tracestate.Entries() {
+			entries = append(entries, entry.Key+"="+entry.Value)
+		}
+		span.TraceState = strings.Join(entries, ",")
+	}
+	if sd.Code != 0 {
+		span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: sd.Message}
+	}
+	for _, annotation := range sd.Annotations {
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			TimeUnixNano: uint64(annotation.Time.UnixNano()),
+			Name:         annotation.Message,
+			Attributes:   attributes(annotation.Attributes),
+		})
+	}
+	for _, event := range sd.MessageEvents {
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			TimeUnixNano: uint64(event.Time.UnixNano()),
+			Name:         "message",
+			Attributes: attributes(map[string]interface{}{
+				"message.type":              messageEventType(event.EventType),
+				"message.id":                event.MessageID,
+				"message.uncompressed_size": event.UncompressedByteSize,
+				"message.compressed_size":   event.CompressedByteSize,
+			}),
+		})
+	}
+	for _, link := range sd.Links {
+		traceID, spanID := link.TraceID, link.SpanID
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:    traceID[:],
+			SpanId:     spanID[:],
+			Attributes: attributes(link.Attributes),
+		})
+	}
+	return span
+}
+
+func spanKind(kind int) tracepb.Span_SpanKind {
+	switch kind {
+	case trace.SpanKindServer:
+		return tracepb.Span_SPAN_KIND_SERVER
+	case trace.SpanKindClient:
+		return tracepb.Span_SPAN_KIND_CLIENT
+	default:
+		return tracepb.Span_SPAN_KIND_INTERNAL
+	}
+}
+
+func messageEventType(eventType trace.MessageEventType) string {
+	switch eventType {
+	case trace.MessageEventTypeSent:
+		return "SENT"
+	case trace.MessageEventTypeRecv:
+		return "RECEIVED"
+	case trace.MessageEventTypeUnspecified:
+		return "UNSPECIFIED"
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+// attributes converts opencensus attributes, whose values are strings, bools, int64s or float64s, to OTLP ones.
+func attributes(attrs map[string]interface{}) []*commonpb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	kvs := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, key := range keys {
+		value := attrs[key]
+		var anyValue commonpb.AnyValue
+		switch v := value.(type) {
+		case string:
+			anyValue.Value = &commonpb.AnyValue_StringValue{StringValue: v}
+		case bool:
+			anyValue.Value = &commonpb.AnyValue_BoolValue{BoolValue: v}
+		case int64:
+			anyValue.Value = &commonpb.AnyValue_IntValue{IntValue: v}
+		case float64:
+			anyValue.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: v}
+		default:
+			anyValue.Value = &commonpb.AnyValue_StringValue{StringValue: fmt.Sprint(v)}
+		}
+		kvs = append(kvs, &commonpb.KeyValue{Key: key, Value: &anyValue})
+	}
+	return kvs
+}
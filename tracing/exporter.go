@@ -0,0 +1,338 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	exportInterval  = 5 * time.Second
+	exportTimeout   = 10 * time.Second
+	maxBatchSize    = 512
+	maxBufferedSize = 4096
+)
+
+// spanUploader sends batches of spans to where they are exported.
+type spanUploader interface {
+	upload(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error
+	io.Closer
+}
+
+// Exporter is an opencensus trace exporter that sends the spans it is given to an OTLP collector, or appends them
+// to a file, in batches. Spans are dropped if they cannot be exported fast enough.
+type Exporter struct {
+	serviceName string
+	uploader    spanUploader
+	logger      logging.Logger
+
+	mu      sync.Mutex
+	spans   []*tracepb.Span
+	dropped int
+
+	flush                   chan struct{}
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+// NewExporter returns an Exporter exporting the spans of the named service as configured. The exporter must be
+// registered with opencensus to be given spans.
+func NewExporter(cfg *Config, serviceName string, logger logging.Logger) (*Exporter, error) {
+	var uploader spanUploader
+	var err error
+	switch cfg.Protocol {
+	case "", ProtocolGRPC:
+		uploader, err = newGRPCUploader(cfg)
+	case ProtocolHTTP:
+		uploader, err = newHTTPUploader(cfg)
+	case ProtocolFile:
+		uploader, err = newFileUploader(cfg.Path)
+	default:
+		err = fmt.Errorf("unknown tracing protocol %q", cfg.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exporter := &Exporter{
+		serviceName: serviceName,
+		uploader:    uploader,
+		logger:      logger,
+		flush:       make(chan struct{}, 1),
+		cancel:      cancel,
+	}
+	exporter.activeBackgroundWorkers.Add(1)
+	goutils.ManagedGo(func() {
+		exporter.run(ctx)
+	}, exporter.activeBackgroundWorkers.Done)
+	return exporter, nil
+}
+
+// ExportSpan queues a span to be exported.
+func (e *Exporter) ExportSpan(sd *trace.SpanData) {
+	span := spanFromData(sd)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.spans) >= maxBufferedSize {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, span)
+	if len(e.spans) >= maxBatchSize {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *Exporter) run(ctx context.Context) {
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.flush:
+		}
+		if err := e.Flush(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Warnw("failed to export spans", "error", err)
+		}
+	}
+}
+
+// Flush exports all of the queued spans.
+func (e *Exporter) Flush(ctx context.Context) error {
+	for {
+		e.mu.Lock()
+		batch := e.spans
+		if len(batch) > maxBatchSize {
+			batch = batch[:maxBatchSize]
+		}
+		e.spans = e.spans[len(batch):]
+		dropped := e.dropped
+		e.dropped = 0
+		e.mu.Unlock()
+
+		if dropped > 0 {
+			e.logger.Warnw("dropped spans that could not be exported in time", "count", dropped)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		uploadCtx, cancel := context.WithTimeout(ctx, exportTimeout)
+		err := e.uploader.upload(uploadCtx, &coltracepb.ExportTraceServiceRequest{
+			ResourceSpans: []*tracepb.ResourceSpans{resourceSpans(e.serviceName, batch)},
+		})
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Close exports the spans still queued and closes the connection spans are exported over.
+func (e *Exporter) Close() error {
+	e.cancel()
+	e.activeBackgroundWorkers.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	return errors.Wrap(multierr.Combine(e.Flush(ctx), e.uploader.Close()), "failed to close span exporter")
+}
+
+// grpcUploader exports spans to an OTLP collector over gRPC.
+type grpcUploader struct {
+	conn    *grpc.ClientConn
+	client  coltracepb.TraceServiceClient
+	headers metadata.MD
+}
+
+func newGRPCUploader(cfg *Config) (*grpcUploader, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGRPCEndpoint
+	}
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if cfg.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to OTLP collector at %s", endpoint)
+	}
+	return &grpcUploader{
+		conn:    conn,
+		client:  coltracepb.NewTraceServiceClient(conn),
+		headers: metadata.New(cfg.Headers),
+	}, nil
+}
+
+func (u *grpcUploader) upload(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	if len(u.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, u.headers)
+	}
+	_, err := u.client.Export(ctx, req)
+	return err
+}
+
+func (u *grpcUploader) Close() error {
+	return u.conn.Close()
+}
+
+// httpUploader exports spans to an OTLP collector by posting them as protobuf over HTTP.
+type httpUploader struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPUploader(cfg *Config) (*httpUploader, error) {
+	url, err := cfg.httpURL()
+	if err != nil {
+		return nil, err
+	}
+	return &httpUploader{url: url, headers: cfg.Headers, client: &http.Client{}}, nil
+}
+
+func (u *httpUploader) upload(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range u.headers {
+		httpReq.Header.Set(key, value)
+	}
+	resp, err := u.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		goutils.UncheckedError(resp.Body.Close())
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("OTLP collector responded with %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (u *httpUploader) Close() error {
+	u.client.CloseIdleConnections()
+	return nil
+}
+
+// fileUploader appends spans to a file in the OTLP JSON file format, one export request per line.
+type fileUploader struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFileUploader(path string) (*fileUploader, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+	//nolint:gosec
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &fileUploader{file: file}, nil
+}
+
+func (u *fileUploader) upload(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	line, err := marshalOTLPJSON(req)
+	if err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	// written in one call so that lines appended by modules exporting to the same file are not interleaved
+	_, err = u.file.Write(append(line, '\n'))
+	return err
+}
+
+func (u *fileUploader) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.file.Close()
+}
+
+// marshalOTLPJSON encodes an export request as OTLP JSON, which differs from the canonical protobuf JSON encoding in
+// that trace and span IDs are hex rather than base64 encoded.
+func marshalOTLPJSON(req *coltracepb.ExportTraceServiceRequest) ([]byte, error) {
+	encoded, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+	for _, rs := range jsonList(decoded["resourceSpans"]) {
+		for _, ss := range jsonList(rs["scopeSpans"]) {
+			for _, span := range jsonList(ss["spans"]) {
+				hexIDs(span, "traceId", "spanId", "parentSpanId")
+				for _, link := range jsonList(span["links"]) {
+					hexIDs(link, "traceId", "spanId")
+				}
+			}
+		}
+	}
+	return json.Marshal(decoded)
+}
+
+func jsonList(value interface{}) []map[string]interface{} {
+	list, _ := value.([]interface{})
+	objects := make([]map[string]interface{}, 0, len(list))
+	for _, elem := range list {
+		if object, ok := elem.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+func hexIDs(object map[string]interface{}, keys ...string) {
+	for _, key := range keys {
+		encoded, ok := object[key].(string)
+		if !ok {
+			continue
+		}
+		var id []byte
+		if err := json.Unmarshal([]byte(`"`+encoded+`"`), &id); err == nil {
+			object[key] = hex.EncodeToString(id)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.opencensus.io/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+)
+
+// recordSpans records a parent span with one child span and exports them with the exporter.
+func recordSpans(t *testing.T, exporter trace.Exporter) (parent, child trace.SpanContext) {
+	t.Helper()
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+
+	ctx, parentSpan := trace.StartSpan(context.Background(), "MoveOnGlobe", trace.WithSampler(trace.AlwaysSample()))
+	_, childSpan := trace.StartSpan(ctx, "plan", trace.WithSpanKind(trace.SpanKindClient))
+	childSpan.AddAttributes(trace.StringAttribute("component", "base1"), trace.Int64Attribute("waypoints", 3))
+	childSpan.Annotate(nil, "planning")
+	childSpan.SetStatus(trace.Status{Code: 2, Message: "no plan found"})
+	childSpan.End()
+	parentSpan.End()
+	return parentSpan.SpanContext(), childSpan.SpanContext()
+}
+
+func TestExporterFile(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+	exporter, err := NewExporter(&Config{Enabled: true, Protocol: ProtocolFile, Path: path}, "viam-server", logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	parent, child := recordSpans(t, exporter)
+	test.That(t, exporter.Close(), test.ShouldBeNil)
+
+	//nolint:gosec
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	test.That(t, scanner.Scan(), test.ShouldBeTrue)
+
+	var req struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string                 `json:"key"`
+					Value map[string]interface{} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Kind         string `json:"kind"`
+					Status       struct {
+						Code    string `json:"code"`
+						Message string `json:"message"`
+					} `json:"status"`
+					Attributes []map[string]interface{} `json:"attributes"`
+					Events     []map[string]interface{} `json:"events"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	test.That(t, json.Unmarshal(scanner.Bytes(), &req), test.ShouldBeNil)
+	test.That(t, scanner.Scan(), test.ShouldBeFalse)
+
+	test.That(t, req.ResourceSpans, test.ShouldHaveLength, 1)
+	test.That(t, req.ResourceSpans[0].Resource.Attributes[0].Key, test.ShouldEqual, "host.name")
+	test.That(t, req.ResourceSpans[0].Resource.Attributes[2].Key, test.ShouldEqual, "service.name")
+	test.That(t, req.ResourceSpans[0].Resource.Attributes[2].Value["stringValue"], test.ShouldEqual, "viam-server")
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	test.That(t, spans, test.ShouldHaveLength, 2)
+	// the child span ends first
+	test.That(t, spans[0].Name, test.ShouldEqual, "plan")
+	test.That(t, spans[0].TraceID, test.ShouldEqual, hex.EncodeToString(parent.TraceID[:]))
+	test.That(t, spans[0].SpanID, test.ShouldEqual, hex.EncodeToString(child.SpanID[:]))
+	test.That(t, spans[0].ParentSpanID, test.ShouldEqual, hex.EncodeToString(parent.SpanID[:]))
+	test.That(t, spans[0].Kind, test.ShouldEqual, "SPAN_KIND_CLIENT")
+	test.That(t, spans[0].Status.Code, test.ShouldEqual, "STATUS_CODE_ERROR")
+	test.That(t, spans[0].Status.Message, test.ShouldEqual, "no plan found")
+	test.That(t, spans[0].Attributes, test.ShouldResemble, []map[string]interface{}{
+		{"key": "component", "value": map[string]interface{}{"stringValue": "base1"}},
+		{"key": "waypoints", "value": map[string]interface{}{"intValue": "3"}},
+	})
+	test.That(t, spans[0].Events, test.ShouldHaveLength, 1)
+	test.That(t, spans[0].Events[0]["name"], test.ShouldEqual, "planning")
+
+	test.That(t, spans[1].Name, test.ShouldEqual, "MoveOnGlobe")
+	test.That(t, spans[1].ParentSpanID, test.ShouldBeEmpty)
+	test.That(t, spans[1].Kind, test.ShouldEqual, "SPAN_KIND_INTERNAL")
+}
+
+func TestExporterHTTP(t *testing.T) {
+	var mu sync.Mutex
+	var received []*coltracepb.ExportTraceServiceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Authorization") != "Bearer secret" ||
+			r.Header.Get("Content-Type") != "application/x-protobuf" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		test.That(t, err, test.ShouldBeNil)
+		var req coltracepb.ExportTraceServiceRequest
+		test.That(t, proto.Unmarshal(body, &req), test.ShouldBeNil)
+		mu.Lock()
+		received = append(received, &req)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	logger := logging.NewTestLogger(t)
+	exporter, err := NewExporter(&Config{
+		Enabled:  true,
+		Protocol: ProtocolHTTP,
+		Endpoint: server.URL,
+		Headers:  map[string]string{"Authorization": "Bearer secret"},
+	}, "arm-module", logger)
+	test.That(t, err, test.ShouldBeNil)
+	parent, _ := recordSpans(t, exporter)
+	test.That(t, exporter.Flush(context.Background()), test.ShouldBeNil)
+
+	mu.Lock()
+	test.That(t, received, test.ShouldHaveLength, 1)
+	spans := received[0].ResourceSpans[0].ScopeSpans[0].Spans
+	mu.Unlock()
+	test.That(t, spans, test.ShouldHaveLength, 2)
+	test.That(t, spans[1].TraceId, test.ShouldResemble, parent.TraceID[:])
+	test.That(t, spans[1].SpanId, test.ShouldResemble, parent.SpanID[:])
+	test.That(t, exporter.Close(), test.ShouldBeNil)
+
+	exporter, err = NewExporter(&Config{Enabled: true, Protocol: ProtocolHTTP, Endpoint: server.URL}, "arm-module", logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer exporter.Close()
+	recordSpans(t, exporter)
+	test.That(t, exporter.Flush(context.Background()), test.ShouldBeError)
+}
+
+type fakeTraceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	mu       sync.Mutex
+	spans    int
+	metadata []metadata.MD
+}
+
+func (s *fakeTraceServer) Export(
+	ctx context.Context, req *coltracepb.ExportTraceServiceRequest,
+) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metadata = append(s.metadata, md)
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			s.spans += len(ss.Spans)
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestExporterGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	server := grpc.NewServer()
+	traceServer := &fakeTraceServer{}
+	coltracepb.RegisterTraceServiceServer(server, traceServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	logger := logging.NewTestLogger(t)
+	exporter, err := NewExporter(&Config{
+		Enabled:  true,
+		Endpoint: listener.Addr().String(),
+		Insecure: true,
+		Headers:  map[string]string{"x-api-key": "secret"},
+	}, "viam-server", logger)
+	test.That(t, err, test.ShouldBeNil)
+	recordSpans(t, exporter)
+	recordSpans(t, exporter)
+	// remaining spans are exported on close
+	test.That(t, exporter.Close(), test.ShouldBeNil)
+
+	traceServer.mu.Lock()
+	defer traceServer.mu.Unlock()
+	test.That(t, traceServer.spans, test.ShouldEqual, 4)
+	test.That(t, traceServer.metadata, test.ShouldHaveLength, 1)
+	test.That(t, traceServer.metadata[0].Get("x-api-key"), test.ShouldResemble, []string{"secret"})
+}
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// ConfigEnvVar is the environment variable the tracing config of a robot is passed to its modules in, as JSON.
+	ConfigEnvVar = "VIAM_TRACING_CONFIG"
+	// ServiceNameEnvVar is the environment variable a module is told the service name to export its spans as in.
+	ServiceNameEnvVar = "VIAM_TRACING_SERVICE_NAME"
+)
+
+// defaultSampler is the sampler opencensus uses when none is configured.
+var defaultSampler = trace.ProbabilitySampler(1e-4)
+
+// The tracing of this process is global, as is opencensus'.
+var (
+	globalMu          sync.Mutex
+	globalConfig      *Config
+	globalServiceName string
+	globalExporter    *Exporter
+)
+
+// Configure starts exporting the spans recorded by this process as the named service, or stops doing so if the
+// config is nil or disabled. It is a no-op if the config has not changed since it was last called.
+func Configure(cfg *Config, serviceName string, logger logging.Logger) error {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if globalConfig.Equals(cfg) && globalServiceName == serviceName {
+		return nil
+	}
+
+	var exporter *Exporter
+	if cfg.enabled() {
+		var err error
+		if exporter, err = NewExporter(cfg, serviceName, logger); err != nil {
+			return err
+		}
+	}
+
+	var closeErr error
+	if globalExporter != nil {
+		trace.UnregisterExporter(globalExporter)
+		closeErr = globalExporter.Close()
+	}
+	globalExporter = exporter
+	if exporter != nil {
+		trace.RegisterExporter(exporter)
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(cfg.sampleRate())})
+		logger.Infow("exporting traces", "protocol", protocolName(cfg), "service", serviceName)
+	} else if globalConfig.enabled() {
+		trace.ApplyConfig(trace.Config{DefaultSampler: defaultSampler})
+		logger.Info("stopped exporting traces")
+	}
+	if cfg != nil {
+		copied := *cfg
+		globalConfig = &copied
+	} else {
+		globalConfig = nil
+	}
+	globalServiceName = serviceName
+	return closeErr
+}
+
+func protocolName(cfg *Config) string {
+	if cfg.Protocol == "" {
+		return ProtocolGRPC
+	}
+	return cfg.Protocol
+}
+
+// Close stops exporting spans, exporting those still queued first.
+func Close() error {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if globalExporter == nil {
+		return nil
+	}
+	trace.UnregisterExporter(globalExporter)
+	err := globalExporter.Close()
+	globalExporter = nil
+	globalConfig = nil
+	return err
+}
+
+// Environment returns the environment variables a module is started with so that it exports its spans, as the
+// named service, as this process does. It is empty if this process is not exporting spans. The config's headers
+// are passed too, so that modules can authenticate with the same collector; a module's environment can only be read
+// by the user it runs as, which is the user this process runs as.
+func Environment(serviceName string) map[string]string {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if !globalConfig.enabled() {
+		return nil
+	}
+	encoded, err := json.Marshal(globalConfig)
+	if err != nil {
+		return nil
+	}
+	return map[string]string{ConfigEnvVar: string(encoded), ServiceNameEnvVar: serviceName}
+}
+
+// ConfigureFromEnvironment configures tracing from the environment a module was started with by Environment. It is
+// a no-op if the module was not started with a tracing config.
+func ConfigureFromEnvironment(logger logging.Logger) error {
+	encoded, ok := os.LookupEnv(ConfigEnvVar)
+	if !ok {
+		return nil
+	}
+	var cfg Config
+	if err := json.Unmarshal([]byte(encoded), &cfg); err != nil {
+		return errors.Wrapf(err, "invalid %s", ConfigEnvVar)
+	}
+	serviceName := os.Getenv(ServiceNameEnvVar)
+	if serviceName == "" {
+		serviceName = "module"
+	}
+	return Configure(&cfg, serviceName, logger)
+}
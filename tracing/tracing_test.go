@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		cfg Config
+		err string
+	}{
+		{Config{}, ""},
+		{Config{Enabled: false, Protocol: "carrier pigeon"}, ""},
+		{Config{Enabled: true}, ""},
+		{Config{Enabled: true, Protocol: ProtocolHTTP, Endpoint: "collector:4318", SampleRate: 0.5}, ""},
+		{Config{Enabled: true, Protocol: ProtocolFile, Path: "/tmp/spans.jsonl"}, ""},
+		{Config{Enabled: true, Protocol: "carrier pigeon"}, `unknown protocol "carrier pigeon"`},
+		{Config{Enabled: true, Protocol: ProtocolFile}, `Field: "path"`},
+		{Config{Enabled: true, Protocol: ProtocolFile, Path: "spans.jsonl", Endpoint: "collector:4317"}, "endpoint may not be set"},
+		{Config{Enabled: true, Path: "spans.jsonl"}, "path may only be set"},
+		{Config{Enabled: true, SampleRate: 2}, "sample_rate must be between 0 and 1"},
+	} {
+		err := tc.cfg.Validate("tracing")
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+}
+
+func TestConfigHTTPURL(t *testing.T) {
+	for _, tc := range []struct {
+		cfg Config
+		url string
+	}{
+		{Config{}, "https://localhost:4318/v1/traces"},
+		{Config{Insecure: true, Endpoint: "collector:4318"}, "http://collector:4318/v1/traces"},
+		{Config{Endpoint: "https://collector.example.com/otlp/v1/traces"}, "https://collector.example.com/otlp/v1/traces"},
+	} {
+		url, err := tc.cfg.httpURL()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, url, test.ShouldEqual, tc.url)
+	}
+}
+
+func TestConfigEquals(t *testing.T) {
+	var disabled *Config
+	test.That(t, disabled.Equals(nil), test.ShouldBeTrue)
+	test.That(t, disabled.Equals(&Config{Protocol: ProtocolHTTP}), test.ShouldBeTrue)
+	test.That(t, disabled.Equals(&Config{Enabled: true}), test.ShouldBeFalse)
+
+	cfg := &Config{Enabled: true, Headers: map[string]string{"a": "b"}}
+	test.That(t, cfg.Equals(&Config{Enabled: true, Headers: map[string]string{"a": "b"}}), test.ShouldBeTrue)
+	test.That(t, cfg.Equals(&Config{Enabled: true, Headers: map[string]string{"a": "c"}}), test.ShouldBeFalse)
+	test.That(t, cfg.Equals(&Config{Enabled: true, Headers: map[string]string{"a": "b"}, SampleRate: 0.1}), test.ShouldBeFalse)
+}
+
+func TestConfigureEnvironment(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	test.That(t, Environment("my-module"), test.ShouldBeEmpty)
+
+	headers := map[string]string{"authorization": "Bearer secret"}
+	cfg := &Config{Enabled: true, Protocol: ProtocolFile, Path: filepath.Join(t.TempDir(), "spans.jsonl"), Headers: headers}
+	test.That(t, Configure(cfg, "viam-server", logger), test.ShouldBeNil)
+	exporter := globalExporter
+	test.That(t, exporter, test.ShouldNotBeNil)
+	// reconfiguring with the same config keeps the exporter
+	test.That(t, Configure(&Config{Enabled: true, Protocol: ProtocolFile, Path: cfg.Path, Headers: headers}, "viam-server", logger),
+		test.ShouldBeNil)
+	test.That(t, globalExporter, test.ShouldEqual, exporter)
+
+	env := Environment("my-module")
+	test.That(t, env[ServiceNameEnvVar], test.ShouldEqual, "my-module")
+	// modules are passed the collector's headers so that they can authenticate with it too
+	var passed Config
+	test.That(t, json.Unmarshal([]byte(env[ConfigEnvVar]), &passed), test.ShouldBeNil)
+	test.That(t, passed.Headers, test.ShouldResemble, headers)
+	test.That(t, passed.Equals(cfg), test.ShouldBeTrue)
+
+	// a module started with the environment exports as the robot does, under its own name
+	test.That(t, Close(), test.ShouldBeNil)
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	test.That(t, ConfigureFromEnvironment(logger), test.ShouldBeNil)
+	test.That(t, globalExporter, test.ShouldNotBeNil)
+	test.That(t, globalServiceName, test.ShouldEqual, "my-module")
+
+	test.That(t, Configure(nil, "viam-server", logger), test.ShouldBeNil)
+	test.That(t, globalExporter, test.ShouldBeNil)
+	test.That(t, Environment("my-module"), test.ShouldBeEmpty)
+}
@@ -50,8 +50,10 @@ func TestObjectCreationWithLabel(t *testing.T) {
 	// create labelled and unlabelled Geometries
 	geom := spatialmath.NewPoint(r3.Vector{0, 0, 200}, geomLabel)
 	geom2 := spatialmath.NewPoint(r3.Vector{0, 0, 200}, "")
-	pbGeomWithLabel := geom.ToProtobuf()
-	pbGeomNoLabel := geom2.ToProtobuf()
+	pbGeomWithLabel, err := geom.ToProtobuf()
+	test.That(t, err, test.ShouldBeNil)
+	pbGeomNoLabel, err := geom2.ToProtobuf()
+	test.That(t, err, test.ShouldBeNil)
 
 	// Test that a providedLabel will overwrite the geometry label
 	obj, err := NewObjectWithLabel(pc, "", pbGeomWithLabel)
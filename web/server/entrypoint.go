@@ -28,6 +28,7 @@ import (
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -42,12 +43,15 @@ type Arguments struct {
 	SharedDir                  string `flag:"shareddir,usage=web resource directory"`
 	Version                    bool   `flag:"version,usage=print version"`
 	WebProfile                 bool   `flag:"webprofile,usage=include profiler in http server"`
+	WebMetrics                 bool   `flag:"webmetrics,usage=serve prometheus metrics at /metrics in http server"`
 	WebRTC                     bool   `flag:"webrtc,default=true,usage=force webrtc connections instead of direct"`
 	RevealSensitiveConfigDiffs bool   `flag:"reveal-sensitive-config-diffs,usage=show config diffs"`
 	UntrustedEnv               bool   `flag:"untrusted-env,usage=disable processes and shell from running in a untrusted environment"`
 	OutputTelemetry            bool   `flag:"output-telemetry,usage=print out telemetry data (metrics and spans)"`
 	DisableMulticastDNS        bool   `flag:"disable-mdns,usage=disable server discovery through multicast DNS"`
 	DumpResourcesPath          string `flag:"dump-resources,usage=dump all resource registrations as json to the provided file path"`
+	Profile                    string `flag:"profile,usage=config profile to apply over the config file, overriding VIAM_CONFIG_PROFILE"`
+	DumpConfig                 bool   `flag:"dump-config,usage=print the config resolved from the config file and its overlays and exit"`
 }
 
 type robotServer struct {
@@ -112,6 +116,14 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 		return
 	}
 
+	if argsParsed.Profile != "" {
+		config.SetProfile(argsParsed.Profile)
+	}
+	if argsParsed.DumpConfig {
+		versionLogged = true
+		return dumpConfig(argsParsed.ConfigFile)
+	}
+
 	if argsParsed.CPUProfile != "" {
 		f, err := os.Create(argsParsed.CPUProfile)
 		if err != nil {
@@ -204,6 +216,7 @@ func (s *robotServer) createWebOptions(cfg *config.Config) (weboptions.Options,
 		return weboptions.Options{}, err
 	}
 	options.Pprof = s.args.WebProfile || cfg.EnableWebProfile
+	options.Metrics = s.args.WebMetrics || cfg.EnableWebMetrics
 	options.SharedDir = s.args.SharedDir
 	options.Debug = s.args.Debug || cfg.Debug
 	options.PreferWebRTC = s.args.WebRTC
@@ -307,6 +320,7 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 		}
 		out.Debug = s.args.Debug || in.Debug
 		out.EnableWebProfile = s.args.WebProfile || in.EnableWebProfile
+		out.EnableWebMetrics = s.args.WebMetrics || in.EnableWebMetrics
 		out.FromCommand = true
 		out.AllowInsecureCreds = s.args.AllowInsecureCreds
 		out.UntrustedEnv = s.args.UntrustedEnv
@@ -325,6 +339,14 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	// This functionality is tested in `TestLogPropagation` in `local_robot_test.go`.
 	config.UpdateLoggerRegistryFromConfig(s.registry, processedConfig, s.logger)
 
+	// Export traces before any modules are started so that they are started exporting theirs too.
+	s.configureTracing(processedConfig)
+	defer func() {
+		if err := tracing.Close(); err != nil {
+			s.logger.Errorw("error exporting remaining traces", "error", err)
+		}
+	}()
+
 	if processedConfig.Cloud != nil {
 		cloudRestartCheckerActive = make(chan struct{})
 		utils.PanicCapturingGo(func() {
@@ -433,6 +455,11 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 					config.UpdateLoggerRegistryFromConfig(s.registry, processedConfig, s.logger)
 				}
 
+				// Modules already running keep exporting traces as they were started with until restarted.
+				if !diff.TracingEqual {
+					s.configureTracing(processedConfig)
+				}
+
 				myRobot.Reconfigure(ctx, processedConfig)
 
 				if !diff.NetworkEqual {
@@ -458,6 +485,13 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	return web.RunWeb(ctx, myRobot, options, s.logger)
 }
 
+// configureTracing exports the spans recorded by the server as configured.
+func (s *robotServer) configureTracing(cfg *config.Config) {
+	if err := tracing.Configure(cfg.Tracing, "viam-server", s.logger.Sublogger("tracing")); err != nil {
+		s.logger.Errorw("failed to configure tracing", "error", err)
+	}
+}
+
 // dumpResourceRegistrations prints all builtin resource registrations as a json array
 // to the provided file. If you edit this function, ensure that etc/system_manifest/main.go is
 // updated correspondingly.
@@ -505,6 +539,21 @@ func dumpResourceRegistrations(outputPath string) error {
 	return nil
 }
 
+// dumpConfig prints the config resolved from the config file at configPath, its overlays and the active profile,
+// with its secrets redacted.
+func dumpConfig(configPath string) error {
+	resolved, err := config.ResolveFile(configPath)
+	if err != nil {
+		return err
+	}
+	resolved, err = config.RedactSecrets(resolved)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(resolved))
+	return err
+}
+
 func logStackTraceAndCancel(cancel context.CancelFunc, logger logging.Logger) {
 	bufSize := 1 << 20
 	traces := make([]byte, bufSize)